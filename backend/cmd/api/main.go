@@ -0,0 +1,452 @@
+package main
+
+import (
+	"cooperative-erp-lite/internal/config"
+	"cooperative-erp-lite/internal/handlers"
+	"cooperative-erp-lite/internal/middleware"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"cooperative-erp-lite/pkg/penyimpanan"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	// Load konfigurasi
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Gagal memuat konfigurasi: %v", err)
+	}
+
+	// Set Gin mode
+	gin.SetMode(cfg.Server.GinMode)
+
+	// Inisialisasi database
+	if err := config.InitDatabase(cfg); err != nil {
+		log.Fatalf("Gagal menginisialisasi database: %v", err)
+	}
+	defer config.CloseDatabase()
+
+	log.Println("✓ Database berhasil diinisialisasi")
+
+	// Setup router
+	router := setupRouter(cfg)
+
+	// Start server
+	serverAddr := fmt.Sprintf(":%s", cfg.Server.Port)
+	log.Printf("🚀 Server berjalan di http://localhost%s", serverAddr)
+	log.Printf("📝 Environment: %s", cfg.Server.GinMode)
+	log.Printf("🗄️  Database: %s@%s:%s/%s", cfg.Database.User, cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+
+	if err := router.Run(serverAddr); err != nil {
+		log.Fatalf("Gagal menjalankan server: %v", err)
+	}
+}
+
+// setupRouter mengkonfigurasi router dan semua routes
+func setupRouter(cfg *config.Config) *gin.Engine {
+	router := gin.New()
+
+	// Global middleware
+	router.Use(gin.Recovery())
+	router.Use(middleware.LoggerMiddleware())
+	router.Use(middleware.CORSMiddleware(cfg.CORS.AllowedOrigins))
+
+	// Initialize JWT utility
+	jwtUtil := utils.NewJWTUtil(cfg.JWT.Secret, cfg.JWT.ExpirationHours)
+
+	// Initialize database connection
+	db := config.GetDB()
+
+	// Initialize services (order matters due to dependencies)
+	authService := services.NewAuthService(db, jwtUtil)
+	koperasiService := services.NewKoperasiService(db)
+	anggotaService := services.NewAnggotaService(db)
+	akunService := services.NewAkunService(db)
+	penggunaService := services.NewPenggunaService(db)
+	produkService := services.NewProdukService(db)
+	transaksiService := services.NewTransaksiService(db)
+	simpananService := services.NewSimpananService(db, transaksiService)
+	penjualanService := services.NewPenjualanService(db, produkService, transaksiService)
+	laporanService := services.NewLaporanService(db, akunService, simpananService, penjualanService)
+	portalAnggotaService := services.NewPortalAnggotaService(db, jwtUtil)
+	shuService := services.NewSHUService(db, laporanService, transaksiService)
+	pinjamanService := services.NewPinjamanService(db, transaksiService)
+	periodeService := services.NewPeriodeService(db, transaksiService)
+	aturanPostingService := services.NewAturanPostingService(db)
+	saldoAwalService := services.NewSaldoAwalService(db, transaksiService)
+	anggaranService := services.NewAnggaranService(db, laporanService)
+	unitUsahaService := services.NewUnitUsahaService(db)
+	asetTetapService := services.NewAsetTetapService(db, transaksiService)
+	templateJurnalService := services.NewTemplateJurnalService(db, transaksiService)
+	rekonsiliasiBankService := services.NewRekonsiliasiBankService(db, transaksiService)
+	pajakService := services.NewPajakService(db, transaksiService)
+
+	// Lampiran disimpan di filesystem lokal; backend S3-compatible dapat dipasang dengan penyimpanan.BaruS3
+	penyimpananLampiran, err := penyimpanan.BaruLokal(cfg.Storage.LocalDir)
+	if err != nil {
+		log.Fatalf("Gagal menyiapkan penyimpanan lampiran: %v", err)
+	}
+	lampiranService := services.NewLampiranService(db, penyimpananLampiran)
+	auditService := services.NewAuditService(db)
+
+	// Koperasi dengan jurnal lama yang belum memiliki snapshot saldo bulanan dibangun sekali saat start
+	if err := akunService.BangunUlangSaldoBulananKosong(); err != nil {
+		log.Printf("Gagal membangun snapshot saldo bulanan: %v", err)
+	}
+
+	// Jurnal penutupan tahun buku dibuat otomatis setelah tahun buku berakhir
+	periodeService.MulaiPenutupanOtomatis(24 * time.Hour)
+
+	// Buku pembantu simpanan direkonsiliasi dengan buku besar setiap hari; selisih dicatat ke log
+	simpananService.MulaiRekonsiliasiOtomatis(24 * time.Hour)
+
+	// Penyusutan aset tetap diposting otomatis untuk setiap bulan yang sudah berakhir
+	asetTetapService.MulaiPenyusutanOtomatis(24 * time.Hour)
+
+	// Jurnal berulang (sewa, gaji, listrik) dibuat otomatis pada tanggal jatuh temponya
+	templateJurnalService.MulaiJurnalBerulangOtomatis(time.Hour)
+
+	// Entri log audit baru dirangkai ke rantai hash per koperasi setiap menit
+	auditService.MulaiPenyegelanOtomatis(time.Minute)
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(authService)
+	koperasiHandler := handlers.NewKoperasiHandler(koperasiService)
+	anggotaHandler := handlers.NewAnggotaHandler(anggotaService)
+	simpananHandler := handlers.NewSimpananHandler(simpananService)
+	akunHandler := handlers.NewAkunHandler(akunService)
+	transaksiHandler := handlers.NewTransaksiHandler(transaksiService)
+	produkHandler := handlers.NewProdukHandler(produkService)
+	penjualanHandler := handlers.NewPenjualanHandler(penjualanService)
+	penggunaHandler := handlers.NewPenggunaHandler(penggunaService)
+	laporanHandler := handlers.NewLaporanHandler(laporanService)
+	portalAnggotaHandler := handlers.NewPortalAnggotaHandler(portalAnggotaService)
+	shuHandler := handlers.NewSHUHandler(shuService)
+	pinjamanHandler := handlers.NewPinjamanHandler(pinjamanService)
+	periodeHandler := handlers.NewPeriodeHandler(periodeService)
+	aturanPostingHandler := handlers.NewAturanPostingHandler(aturanPostingService)
+	saldoAwalHandler := handlers.NewSaldoAwalHandler(saldoAwalService)
+	anggaranHandler := handlers.NewAnggaranHandler(anggaranService)
+	unitUsahaHandler := handlers.NewUnitUsahaHandler(unitUsahaService)
+	asetTetapHandler := handlers.NewAsetTetapHandler(asetTetapService)
+	templateJurnalHandler := handlers.NewTemplateJurnalHandler(templateJurnalService)
+	rekonsiliasiBankHandler := handlers.NewRekonsiliasiBankHandler(rekonsiliasiBankService)
+	pajakHandler := handlers.NewPajakHandler(pajakService)
+	lampiranHandler := handlers.NewLampiranHandler(lampiranService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	// Health check endpoint
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "healthy",
+			"message": "Cooperative ERP Lite API is running",
+		})
+	})
+
+	// API v1 routes
+	v1 := router.Group("/api/v1")
+	{
+		// Public routes - Authentication
+		auth := v1.Group("/auth")
+		{
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/logout", authHandler.Logout)
+
+			// Protected auth routes
+			authProtected := auth.Group("")
+			authProtected.Use(middleware.AuthMiddleware(jwtUtil))
+			{
+				authProtected.GET("/profile", authHandler.GetProfile)
+				authProtected.PUT("/change-password", authHandler.ChangePassword)
+				authProtected.POST("/refresh", authHandler.RefreshToken)
+			}
+		}
+
+		// Protected routes - Require authentication
+		protected := v1.Group("")
+		protected.Use(middleware.AuthMiddleware(jwtUtil))
+		protected.Use(middleware.RLSMiddleware())   // Set RLS context for multi-tenant isolation
+		protected.Use(middleware.AuditMiddleware()) // Pengguna, IP dan request ID untuk log audit
+		{
+			// Koperasi routes - Admin only
+			koperasi := protected.Group("/koperasi")
+			koperasi.Use(middleware.RequireRole(models.PeranAdmin))
+			{
+				koperasi.POST("", koperasiHandler.Create)
+				koperasi.GET("", koperasiHandler.List)
+				koperasi.GET("/:id", koperasiHandler.GetByID)
+				koperasi.PUT("/:id", koperasiHandler.Update)
+				koperasi.DELETE("/:id", koperasiHandler.Delete)
+			}
+
+			// Anggota routes - Admin and Bendahara can manage
+			anggota := protected.Group("/anggota")
+			{
+				anggota.POST("", anggotaHandler.Create)
+				anggota.GET("", anggotaHandler.List)
+				anggota.GET("/:id", anggotaHandler.GetByID)
+				anggota.PUT("/:id", anggotaHandler.Update)
+				anggota.DELETE("/:id", anggotaHandler.Delete)
+				anggota.GET("/nomor/:nomorAnggota", anggotaHandler.GetByNomor)
+			}
+
+			// Simpanan routes - Admin and Bendahara can manage
+			simpanan := protected.Group("/simpanan")
+			{
+				simpanan.POST("", simpananHandler.CatatSetoran)
+				simpanan.POST("/tarik", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), simpananHandler.CatatPenarikan)
+				simpanan.POST("/:id/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), simpananHandler.Batalkan)
+				simpanan.GET("", simpananHandler.List)
+				simpanan.GET("/anggota/:idAnggota/saldo", simpananHandler.GetSaldoAnggota)
+				simpanan.GET("/ringkasan", simpananHandler.GetRingkasan)
+				simpanan.GET("/laporan-saldo", simpananHandler.GetLaporanSaldo)
+				simpanan.GET("/rekonsiliasi", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), simpananHandler.GetRekonsiliasi)
+				simpanan.POST("/rekonsiliasi/perbaiki", middleware.RequireRole(models.PeranAdmin), simpananHandler.PerbaikiRekonsiliasi)
+			}
+
+			// Pinjaman (Loan) routes - produk hanya dikelola Admin
+			pinjaman := protected.Group("/pinjaman")
+			{
+				pinjaman.POST("/produk", middleware.RequireRole(models.PeranAdmin), pinjamanHandler.CreateProduk)
+				pinjaman.GET("/produk", pinjamanHandler.ListProduk)
+				pinjaman.PUT("/produk/:id", middleware.RequireRole(models.PeranAdmin), pinjamanHandler.UpdateProduk)
+				pinjaman.GET("/simulasi", pinjamanHandler.Simulasi)
+				pinjaman.POST("", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), pinjamanHandler.Cairkan)
+				pinjaman.GET("", pinjamanHandler.List)
+				pinjaman.GET("/:id", pinjamanHandler.GetByID)
+				pinjaman.POST("/:id/angsuran", pinjamanHandler.BayarAngsuran)
+				pinjaman.POST("/:id/angsuran/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), pinjamanHandler.BatalkanAngsuran)
+				pinjaman.POST("/:id/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), pinjamanHandler.BatalkanPencairan)
+			}
+
+			// Akun (Chart of Accounts) routes
+			akun := protected.Group("/akun")
+			{
+				akun.POST("", akunHandler.Create)
+				akun.GET("", akunHandler.List)
+				akun.GET("/template", akunHandler.ListTemplate)
+				akun.POST("/seed-coa", middleware.RequireRole(models.PeranAdmin), akunHandler.SeedCOA)
+				akun.GET("/ekspor", akunHandler.Ekspor)
+				akun.POST("/impor", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), akunHandler.Impor)
+				akun.GET("/:id", akunHandler.GetByID)
+				akun.PUT("/:id", akunHandler.Update)
+				akun.DELETE("/:id", akunHandler.Delete)
+				akun.GET("/kode/:kodeAkun", akunHandler.GetSaldo)
+			}
+
+			// Transaksi (Journal entries) routes
+			transaksi := protected.Group("/transaksi")
+			{
+				transaksi.POST("", transaksiHandler.Create)
+				transaksi.GET("", transaksiHandler.List)
+				transaksi.GET("/:id", transaksiHandler.GetByID)
+				transaksi.PUT("/:id", transaksiHandler.Update)
+				transaksi.DELETE("/:id", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), transaksiHandler.Delete)
+				transaksi.POST("/:id/reverse", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), transaksiHandler.Reverse)
+				transaksi.POST("/:id/ajukan", transaksiHandler.Ajukan)
+				transaksi.POST("/:id/setujui", middleware.RequireRole(models.PeranAdmin), transaksiHandler.Setujui)
+				transaksi.POST("/:id/tolak", middleware.RequireRole(models.PeranAdmin), transaksiHandler.Tolak)
+				transaksi.POST("/:id/posting", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), transaksiHandler.Posting)
+			}
+
+			// Produk routes
+			produk := protected.Group("/produk")
+			{
+				produk.POST("", produkHandler.Create)
+				produk.GET("", produkHandler.List)
+				produk.GET("/:id", produkHandler.GetByID)
+				produk.PUT("/:id", produkHandler.Update)
+				produk.DELETE("/:id", produkHandler.Delete)
+				produk.GET("/sku/:sku", produkHandler.GetByBarcode)
+			}
+
+			// Penjualan (POS) routes
+			penjualan := protected.Group("/penjualan")
+			{
+				penjualan.POST("", penjualanHandler.ProsesPenjualan)
+				penjualan.GET("", penjualanHandler.List)
+				penjualan.GET("/:id", penjualanHandler.GetByID)
+				penjualan.POST("/:id/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), penjualanHandler.Batalkan)
+			}
+
+			// Pengguna (User management) routes - Admin only
+			pengguna := protected.Group("/pengguna")
+			pengguna.Use(middleware.RequireRole(models.PeranAdmin))
+			{
+				pengguna.POST("", penggunaHandler.Create)
+				pengguna.GET("", penggunaHandler.List)
+				pengguna.GET("/:id", penggunaHandler.GetByID)
+				pengguna.PUT("/:id", penggunaHandler.Update)
+				pengguna.DELETE("/:id", penggunaHandler.Delete)
+				pengguna.PUT("/:id/reset-password", penggunaHandler.ResetPassword)
+			}
+
+			// Laporan (Reports) routes
+			laporan := protected.Group("/laporan")
+			{
+				laporan.GET("/transaksi-harian", laporanHandler.GetTransaksiHarian)
+				laporan.GET("/neraca-saldo", laporanHandler.GetNeracaSaldo)
+				laporan.GET("/neraca", laporanHandler.GetNeraca)
+				laporan.GET("/laba-rugi", laporanHandler.GetLabaRugi)
+				laporan.GET("/perubahan-modal", laporanHandler.GetPerubahanModal)
+				laporan.GET("/arus-kas", laporanHandler.GetArusKas)
+				laporan.GET("/buku-besar", laporanHandler.GetBukuBesar)
+				laporan.GET("/realisasi-anggaran", anggaranHandler.GetRealisasi)
+				laporan.GET("/kontribusi-unit-usaha", laporanHandler.GetKontribusiUnitUsaha)
+				laporan.GET("/saldo-anggota", laporanHandler.GetSaldoAnggota)
+			}
+
+			// SHU (Sisa Hasil Usaha) routes - perubahan hanya oleh Admin dan Bendahara
+			shu := protected.Group("/shu")
+			{
+				shu.GET("", shuHandler.List)
+				shu.GET("/pengaturan", shuHandler.GetPengaturan)
+				shu.PUT("/pengaturan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), shuHandler.UpdatePengaturan)
+				shu.GET("/hitung", shuHandler.Hitung)
+				shu.POST("", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), shuHandler.Tetapkan)
+				shu.GET("/:tahunBuku", shuHandler.GetByTahunBuku)
+				shu.POST("/:tahunBuku/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), shuHandler.Batalkan)
+			}
+
+			// Anggaran (RAPB) routes - penyusunan dan pengesahan hanya oleh Admin dan Bendahara
+			anggaran := protected.Group("/anggaran")
+			{
+				anggaran.GET("", anggaranHandler.List)
+				anggaran.GET("/:tahunBuku", anggaranHandler.GetByTahunBuku)
+				anggaran.PUT("/:tahunBuku", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), anggaranHandler.Simpan)
+				anggaran.POST("/:tahunBuku/salin", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), anggaranHandler.Salin)
+				anggaran.POST("/:tahunBuku/sahkan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), anggaranHandler.Sahkan)
+				anggaran.DELETE("/:tahunBuku", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), anggaranHandler.Delete)
+			}
+
+			// Unit usaha routes - master unit usaha hanya dikelola Admin dan Bendahara
+			unitUsaha := protected.Group("/unit-usaha")
+			{
+				unitUsaha.GET("", unitUsahaHandler.List)
+				unitUsaha.GET("/:id", unitUsahaHandler.GetByID)
+				unitUsaha.POST("", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), unitUsahaHandler.Create)
+				unitUsaha.PUT("/:id", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), unitUsahaHandler.Update)
+				unitUsaha.DELETE("/:id", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), unitUsahaHandler.Delete)
+			}
+
+			// Aset tetap routes - registrasi, penyusutan dan pelepasan oleh Admin/Bendahara
+			asetTetap := protected.Group("/aset-tetap")
+			{
+				asetTetap.GET("", asetTetapHandler.List)
+				asetTetap.GET("/:id", asetTetapHandler.GetByID)
+				asetTetap.POST("", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), asetTetapHandler.Create)
+				asetTetap.POST("/penyusutan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), asetTetapHandler.PostingPenyusutan)
+				asetTetap.POST("/:id/pelepasan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), asetTetapHandler.Lepas)
+				asetTetap.POST("/:id/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), asetTetapHandler.Batalkan)
+				asetTetap.POST("/penyusutan/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), asetTetapHandler.BatalkanPenyusutan)
+				asetTetap.POST("/:id/pelepasan/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), asetTetapHandler.BatalkanPelepasan)
+			}
+
+			// Template jurnal routes - template dan jadwal berulang dikelola oleh Admin/Bendahara
+			templateJurnal := protected.Group("/template-jurnal")
+			{
+				templateJurnal.GET("", templateJurnalHandler.List)
+				templateJurnal.GET("/pratinjau", templateJurnalHandler.Pratinjau)
+				templateJurnal.GET("/:id", templateJurnalHandler.GetByID)
+				templateJurnal.POST("", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), templateJurnalHandler.Create)
+				templateJurnal.PUT("/:id", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), templateJurnalHandler.Update)
+				templateJurnal.DELETE("/:id", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), templateJurnalHandler.Delete)
+				templateJurnal.POST("/:id/jurnal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), templateJurnalHandler.BuatJurnal)
+			}
+
+			// Rekonsiliasi bank routes - impor rekening koran dan pencocokan oleh Admin/Bendahara
+			rekonsiliasiBank := protected.Group("/rekonsiliasi-bank")
+			{
+				rekonsiliasiBank.GET("/rekening-koran", rekonsiliasiBankHandler.ListRekeningKoran)
+				rekonsiliasiBank.GET("/mutasi", rekonsiliasiBankHandler.ListMutasi)
+				rekonsiliasiBank.GET("/laporan", rekonsiliasiBankHandler.Laporan)
+				rekonsiliasiBank.POST("/rekening-koran", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), rekonsiliasiBankHandler.Impor)
+				rekonsiliasiBank.DELETE("/rekening-koran/:id", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), rekonsiliasiBankHandler.HapusRekeningKoran)
+				rekonsiliasiBank.POST("/cocokkan-otomatis", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), rekonsiliasiBankHandler.CocokkanOtomatis)
+				rekonsiliasiBank.POST("/mutasi/:id/cocokkan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), rekonsiliasiBankHandler.Cocokkan)
+				rekonsiliasiBank.POST("/mutasi/:id/batal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), rekonsiliasiBankHandler.BatalkanCocok)
+				rekonsiliasiBank.POST("/mutasi/:id/jurnal", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), rekonsiliasiBankHandler.BuatJurnal)
+			}
+
+			// Pajak routes - laporan pajak dan e-Faktur; pemotongan PPh oleh Admin/Bendahara
+			pajak := protected.Group("/pajak")
+			{
+				pajak.GET("/laporan", pajakHandler.GetLaporan)
+				pajak.GET("/e-faktur", pajakHandler.EksporEFaktur)
+				pajak.GET("/pph", pajakHandler.ListPPh)
+				pajak.POST("/pph", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), pajakHandler.CreatePPh)
+				pajak.POST("/pph/:id/pembatalan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), pajakHandler.BatalkanPPh)
+			}
+
+			// Lampiran routes - bukti transaksi, simpanan, penjualan dan dokumen anggota
+			lampiran := protected.Group("/lampiran")
+			{
+				lampiran.POST("", lampiranHandler.Unggah)
+				lampiran.GET("", lampiranHandler.List)
+				lampiran.GET("/:id/unduh", lampiranHandler.Unduh)
+				lampiran.DELETE("/:id", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), lampiranHandler.Delete)
+			}
+
+			// Audit routes - penelusuran dan verifikasi log audit hanya oleh Admin
+			audit := protected.Group("/audit")
+			audit.Use(middleware.RequireRole(models.PeranAdmin))
+			{
+				audit.GET("", auditHandler.List)
+				audit.GET("/verifikasi", auditHandler.Verifikasi)
+			}
+
+			// Periode akuntansi routes - penutupan hanya oleh Admin dan Bendahara
+			periode := protected.Group("/periode")
+			{
+				periode.GET("", periodeHandler.List)
+				periode.POST("/tutup-bulan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), periodeHandler.TutupBulan)
+				periode.POST("/tutup-tahun", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), periodeHandler.TutupTahunBuku)
+				periode.POST("/jurnal-penutupan", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), periodeHandler.BuatJurnalPenutupan)
+			}
+
+			// Aturan posting routes - pemetaan akun jurnal otomatis hanya diubah oleh Admin
+			aturanPosting := protected.Group("/aturan-posting")
+			{
+				aturanPosting.GET("", middleware.RequireRole(models.PeranAdmin, models.PeranBendahara), aturanPostingHandler.List)
+				aturanPosting.PUT("", middleware.RequireRole(models.PeranAdmin), aturanPostingHandler.Update)
+			}
+
+			// Saldo awal routes - migrasi dari pembukuan manual hanya oleh Admin dan Bendahara
+			saldoAwal := protected.Group("/saldo-awal")
+			saldoAwal.Use(middleware.RequireRole(models.PeranAdmin, models.PeranBendahara))
+			{
+				saldoAwal.POST("", saldoAwalHandler.Impor)
+				saldoAwal.POST("/berkas", saldoAwalHandler.ImporBerkas)
+				saldoAwal.POST("/batal", saldoAwalHandler.Batalkan)
+			}
+		}
+
+		// Portal Anggota routes
+		portal := v1.Group("/portal")
+		{
+			// Public route - Login portal anggota
+			portal.POST("/login", portalAnggotaHandler.Login)
+
+			// Protected routes - Require member authentication
+			portalProtected := portal.Group("")
+			portalProtected.Use(middleware.AuthAnggotaMiddleware(jwtUtil))
+			portalProtected.Use(middleware.RLSMiddleware()) // Set RLS context for members
+			{
+				portalProtected.GET("/profile", portalAnggotaHandler.GetProfile)
+				portalProtected.GET("/saldo", portalAnggotaHandler.GetSaldo)
+				portalProtected.GET("/riwayat", portalAnggotaHandler.GetRiwayat)
+				portalProtected.PUT("/ubah-pin", portalAnggotaHandler.UbahPIN)
+			}
+		}
+	}
+
+	log.Println("✓ Routes berhasil dikonfigurasi")
+
+	return router
+}
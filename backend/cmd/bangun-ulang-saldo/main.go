@@ -0,0 +1,59 @@
+// ============================================================================
+// Bangun Ulang Snapshot Saldo Bulanan
+// Menghitung ulang tabel saldo_bulanan_akun dari jurnal yang sudah di-post,
+// misalnya setelah perbaikan data langsung di database.
+//
+// Penggunaan (dari direktori backend):
+//   go run ./cmd/bangun-ulang-saldo                  # semua koperasi
+//   go run ./cmd/bangun-ulang-saldo -koperasi <uuid> # satu koperasi
+// ============================================================================
+
+package main
+
+import (
+	"cooperative-erp-lite/internal/config"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/internal/services"
+	"flag"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+func main() {
+	idKoperasiFlag := flag.String("koperasi", "", "ID koperasi yang dibangun ulang (kosong berarti semua koperasi)")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Gagal memuat konfigurasi: %v", err)
+	}
+
+	// InitDatabase juga menjalankan migrasi sehingga tabel snapshot pasti tersedia
+	if err := config.InitDatabase(cfg); err != nil {
+		log.Fatalf("Gagal menginisialisasi database: %v", err)
+	}
+	defer config.CloseDatabase()
+
+	db := config.GetDB()
+	akunService := services.NewAkunService(db)
+
+	var idKoperasiList []uuid.UUID
+	if *idKoperasiFlag != "" {
+		idKoperasi, err := uuid.Parse(*idKoperasiFlag)
+		if err != nil {
+			log.Fatalf("ID koperasi tidak valid: %v", err)
+		}
+		idKoperasiList = append(idKoperasiList, idKoperasi)
+	} else if err := db.Model(&models.Koperasi{}).Pluck("id", &idKoperasiList).Error; err != nil {
+		log.Fatalf("Gagal mengambil daftar koperasi: %v", err)
+	}
+
+	for _, idKoperasi := range idKoperasiList {
+		jumlah, err := akunService.BangunUlangSaldoBulanan(idKoperasi)
+		if err != nil {
+			log.Fatalf("Gagal membangun ulang saldo bulanan koperasi %s: %v", idKoperasi, err)
+		}
+		log.Printf("✓ Koperasi %s: %d baris saldo bulanan", idKoperasi, jumlah)
+	}
+}
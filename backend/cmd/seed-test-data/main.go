@@ -77,7 +77,7 @@ func main() {
 	fmt.Printf("   ✓ Created %d transactions\n", len(transactions))
 
 	// Calculate and display totals
-	var totalPokok, totalWajib, totalSukarela float64
+	var totalPokok, totalWajib, totalSukarela models.Uang
 	for _, t := range transactions {
 		switch t.TipeSimpanan {
 		case models.SimpananPokok:
@@ -88,10 +88,10 @@ func main() {
 			totalSukarela += t.JumlahSetoran
 		}
 	}
-	fmt.Printf("   ✓ Simpanan Pokok: Rp %.0f\n", totalPokok)
-	fmt.Printf("   ✓ Simpanan Wajib: Rp %.0f\n", totalWajib)
-	fmt.Printf("   ✓ Simpanan Sukarela: Rp %.0f\n", totalSukarela)
-	fmt.Printf("   ✓ Total Simpanan: Rp %.0f\n", totalPokok+totalWajib+totalSukarela)
+	fmt.Printf("   ✓ Simpanan Pokok: Rp %s\n", totalPokok)
+	fmt.Printf("   ✓ Simpanan Wajib: Rp %s\n", totalWajib)
+	fmt.Printf("   ✓ Simpanan Sukarela: Rp %s\n", totalSukarela)
+	fmt.Printf("   ✓ Total Simpanan: Rp %s\n", totalPokok+totalWajib+totalSukarela)
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
@@ -222,7 +222,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SP-2024-001",
 			TipeSimpanan:     models.SimpananPokok,
 			TanggalTransaksi: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    1000000,
+			JumlahSetoran:    models.Rupiah(1000000),
 			Keterangan:       "Setoran Simpanan Pokok",
 		},
 		// Simpanan Wajib (monthly deposits)
@@ -233,7 +233,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SW-2024-001",
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    500000,
+			JumlahSetoran:    models.Rupiah(500000),
 			Keterangan:       "Setoran Simpanan Wajib Januari 2024",
 		},
 		{
@@ -243,7 +243,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SW-2024-002",
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: time.Date(2024, 2, 15, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    500000,
+			JumlahSetoran:    models.Rupiah(500000),
 			Keterangan:       "Setoran Simpanan Wajib Februari 2024",
 		},
 		{
@@ -253,7 +253,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SW-2024-003",
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    500000,
+			JumlahSetoran:    models.Rupiah(500000),
 			Keterangan:       "Setoran Simpanan Wajib Maret 2024",
 		},
 		{
@@ -263,7 +263,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SW-2024-004",
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: time.Date(2024, 4, 15, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    500000,
+			JumlahSetoran:    models.Rupiah(500000),
 			Keterangan:       "Setoran Simpanan Wajib April 2024",
 		},
 		{
@@ -273,7 +273,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SW-2024-005",
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    500000,
+			JumlahSetoran:    models.Rupiah(500000),
 			Keterangan:       "Setoran Simpanan Wajib Mei 2024",
 		},
 		// Simpanan Sukarela (voluntary deposits)
@@ -284,7 +284,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SS-2024-001",
 			TipeSimpanan:     models.SimpananSukarela,
 			TanggalTransaksi: time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    200000,
+			JumlahSetoran:    models.Rupiah(200000),
 			Keterangan:       "Setoran Simpanan Sukarela",
 		},
 		{
@@ -294,7 +294,7 @@ func createSampleTransactions(db *gorm.DB, anggotaID, koperasiID uuid.UUID) []mo
 			NomorReferensi:   "SS-2024-002",
 			TipeSimpanan:     models.SimpananSukarela,
 			TanggalTransaksi: time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC),
-			JumlahSetoran:    300000,
+			JumlahSetoran:    models.Rupiah(300000),
 			Keterangan:       "Setoran Simpanan Sukarela",
 		},
 	}
@@ -0,0 +1,86 @@
+// Package audit mencatat setiap perubahan data keuangan ke log audit yang dirangkai hash per koperasi.
+//
+// Plugin GORM di paket ini menulis entri models.LogAudit dalam transaksi yang sama dengan
+// perubahannya. Pelaku, alamat IP dan request ID dibaca dari context query yang diisi oleh
+// middleware melalui DenganInfo; query tanpa context tersebut (misalnya job background)
+// dicatat tanpa informasi request.
+package audit
+
+import (
+	"context"
+	"cooperative-erp-lite/internal/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Info adalah informasi request yang dicatat pada setiap entri log audit
+type Info struct {
+	IDPengguna *uuid.UUID
+	AlamatIP   string
+	IDRequest  string
+}
+
+type kunciInfo struct{}
+
+// DenganInfo mengembalikan context turunan yang membawa informasi request untuk log audit
+func DenganInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, kunciInfo{}, info)
+}
+
+// InfoDari mengambil informasi request dari context, jika ada
+func InfoDari(ctx context.Context) (Info, bool) {
+	if ctx == nil {
+		return Info{}, false
+	}
+	info, ok := ctx.Value(kunciInfo{}).(Info)
+	return info, ok
+}
+
+// isiHash adalah isi entri yang di-hash. Urutan field tetap sehingga hasil json.Marshal-nya
+// deterministik; setiap nilai dienkode sebagai string JSON sehingga batas antar field tidak ambigu.
+type isiHash struct {
+	Urutan         string `json:"urutan"`
+	IDKoperasi     string `json:"idKoperasi"`
+	Entitas        string `json:"entitas"`
+	IDEntitas      string `json:"idEntitas"`
+	Aksi           string `json:"aksi"`
+	DataSebelum    string `json:"dataSebelum"`
+	DataSesudah    string `json:"dataSesudah"`
+	IDPengguna     string `json:"idPengguna"`
+	AlamatIP       string `json:"alamatIp"`
+	IDRequest      string `json:"idRequest"`
+	Waktu          string `json:"waktu"`
+	HashSebelumnya string `json:"hashSebelumnya"`
+}
+
+// HashEntri menghitung hash SHA-256 (hex) entri log audit beserta Urutan dan HashSebelumnya-nya.
+// Waktu dinormalisasi ke UTC dengan presisi mikrodetik, sama dengan presisi kolom timestamp PostgreSQL.
+func HashEntri(entri *models.LogAudit) string {
+	isi := isiHash{
+		IDKoperasi:     entri.IDKoperasi.String(),
+		Entitas:        entri.Entitas,
+		IDEntitas:      entri.IDEntitas.String(),
+		Aksi:           string(entri.Aksi),
+		DataSebelum:    entri.DataSebelum,
+		DataSesudah:    entri.DataSesudah,
+		AlamatIP:       entri.AlamatIP,
+		IDRequest:      entri.IDRequest,
+		Waktu:          entri.Waktu.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		HashSebelumnya: entri.HashSebelumnya,
+	}
+	if entri.Urutan != nil {
+		isi.Urutan = strconv.FormatInt(*entri.Urutan, 10)
+	}
+	if entri.IDPengguna != nil {
+		isi.IDPengguna = entri.IDPengguna.String()
+	}
+
+	data, _ := json.Marshal(isi) // Marshal struct berisi string tidak pernah gagal
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashEntri(t *testing.T) {
+	urutan := int64(1)
+	pengguna := uuid.New()
+	entri := &models.LogAudit{
+		Urutan:      &urutan,
+		IDKoperasi:  uuid.New(),
+		Entitas:     "transaksi",
+		IDEntitas:   uuid.New(),
+		Aksi:        models.AksiAuditUbah,
+		DataSebelum: `{"deskripsi":"Setoran"}`,
+		DataSesudah: `{"deskripsi":"Setoran kas"}`,
+		IDPengguna:  &pengguna,
+		AlamatIP:    "10.0.0.1",
+		IDRequest:   "req-1",
+		Waktu:       time.Date(2025, 1, 16, 9, 30, 0, 123456789, time.UTC),
+	}
+
+	hash := HashEntri(entri)
+	assert.Len(t, hash, 64)
+
+	// Zona waktu dan presisi di bawah mikrodetik tidak memengaruhi hash (sama dengan hasil baca PostgreSQL)
+	salinan := *entri
+	salinan.Waktu = entri.Waktu.Truncate(time.Microsecond).In(time.FixedZone("WIB", 7*3600))
+	assert.Equal(t, hash, HashEntri(&salinan))
+
+	ubah := func(f func(e *models.LogAudit)) string {
+		salinan := *entri
+		f(&salinan)
+		return HashEntri(&salinan)
+	}
+	assert.NotEqual(t, hash, ubah(func(e *models.LogAudit) { e.DataSesudah = `{"deskripsi":"Setoran bank"}` }))
+	assert.NotEqual(t, hash, ubah(func(e *models.LogAudit) { e.HashSebelumnya = "ab" }))
+	assert.NotEqual(t, hash, ubah(func(e *models.LogAudit) { e.IDPengguna = nil }))
+	assert.NotEqual(t, hash, ubah(func(e *models.LogAudit) {
+		u := int64(2)
+		e.Urutan = &u
+	}))
+
+	// Batas antar field tidak ambigu
+	assert.NotEqual(t,
+		ubah(func(e *models.LogAudit) { e.DataSebelum, e.DataSesudah = "ab", "c" }),
+		ubah(func(e *models.LogAudit) { e.DataSebelum, e.DataSesudah = "a", "bc" }))
+}
+
+func TestInfoDari(t *testing.T) {
+	_, ok := InfoDari(context.Background())
+	assert.False(t, ok)
+
+	pengguna := uuid.New()
+	ctx := DenganInfo(context.Background(), Info{IDPengguna: &pengguna, AlamatIP: "10.0.0.1", IDRequest: "req-1"})
+	info, ok := InfoDari(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, pengguna, *info.IDPengguna)
+	assert.Equal(t, "req-1", info.IDRequest)
+}
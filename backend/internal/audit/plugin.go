@@ -0,0 +1,419 @@
+package audit
+
+import (
+	"context"
+	"cooperative-erp-lite/internal/models"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tabelDiaudit adalah tabel yang setiap perubahannya dicatat ke log audit: data master,
+// dokumen sumber yang menghasilkan jurnal, dan jurnal itu sendiri. Baris jurnal dan item
+// penjualan ikut dicatat karena nilainya ada pada baris tersebut.
+//
+// Tabel lain sengaja tidak diaudit: saldo_bulanan_akun hanya ringkasan yang dihitung ulang
+// dari jurnal, log_audit adalah log itu sendiri, dan tabel pengaturan atau pendukung
+// (periode, aturan posting, anggaran, template, rekening koran, lampiran) tidak mengubah
+// nilai keuangan tanpa membuat jurnal yang sudah tercatat di sini.
+var tabelDiaudit = map[string]bool{
+	"koperasi":              true,
+	"pengguna":              true,
+	"anggota":               true,
+	"akun":                  true,
+	"simpanan":              true,
+	"transaksi":             true,
+	"baris_transaksi":       true,
+	"produk":                true,
+	"penjualan":             true,
+	"item_penjualan":        true,
+	"pinjaman":              true,
+	"jadwal_angsuran":       true,
+	"shu":                   true,
+	"shu_anggota":           true,
+	"aset_tetap":            true,
+	"penyusutan_aset_tetap": true,
+	"pemotongan_pph":        true,
+}
+
+// induk adalah field dan tabel induk untuk tabel yang tidak memiliki kolom id_koperasi
+type induk struct {
+	field string
+	tabel string
+}
+
+// tabelInduk memetakan tabel tanpa kolom id_koperasi ke induknya; koperasi barisnya dibaca dari induk
+var tabelInduk = map[string]induk{
+	"baris_transaksi": {field: "IDTransaksi", tabel: "transaksi"},
+	"item_penjualan":  {field: "IDPenjualan", tabel: "penjualan"},
+}
+
+const kunciDataSebelum = "audit:data_sebelum"
+
+// Plugin adalah plugin GORM yang mencatat create, update dan delete pada tabelDiaudit.
+// Entri ditulis sebelum commit transaksi perubahannya; jika penulisan log gagal, perubahannya
+// ikut dibatalkan. Perubahan lewat SQL mentah (db.Exec) tidak tercatat, sehingga service
+// harus mengubah tabel yang diaudit melalui model.
+type Plugin struct{}
+
+// Name mengembalikan nama plugin
+func (Plugin) Name() string {
+	return "audit"
+}
+
+// Initialize mendaftarkan callback audit pada db
+func (Plugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().Before("gorm:commit_or_rollback_transaction").Register("audit:catat_buat", catatBuat); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("audit:ambil_sebelum_ubah", ambilDataSebelum); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:commit_or_rollback_transaction").Register("audit:catat_ubah", catatUbah); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("audit:ambil_sebelum_hapus", ambilDataSebelum); err != nil {
+		return err
+	}
+	return callback.Delete().Before("gorm:commit_or_rollback_transaction").Register("audit:catat_hapus", catatHapus)
+}
+
+// baris adalah snapshot satu baris yang berubah
+type baris struct {
+	id         uuid.UUID
+	idKoperasi uuid.UUID
+	idInduk    uuid.UUID // Hanya untuk tabelInduk, yang koperasinya diambil dari baris induk
+	nilai      reflect.Value
+	data       string
+}
+
+func diaudit(db *gorm.DB) bool {
+	return db.Error == nil && db.Statement.Schema != nil && tabelDiaudit[db.Statement.Schema.Table] &&
+		db.Statement.Schema.PrioritizedPrimaryField != nil
+}
+
+// elemen mengurai nilai model tunggal atau slice menjadi daftar struct
+func elemen(nilai reflect.Value) []reflect.Value {
+	nilai = reflect.Indirect(nilai)
+	switch nilai.Kind() {
+	case reflect.Slice, reflect.Array:
+		hasil := make([]reflect.Value, 0, nilai.Len())
+		for i := 0; i < nilai.Len(); i++ {
+			hasil = append(hasil, reflect.Indirect(nilai.Index(i)))
+		}
+		return hasil
+	case reflect.Struct:
+		return []reflect.Value{nilai}
+	}
+	return nil
+}
+
+// snapshot mengubah satu baris menjadi JSON berisi kolom database-nya. Kolom dengan tag json:"-"
+// (hash kata sandi, PIN, penanda soft delete) tidak ikut dicatat.
+func snapshot(ctx context.Context, sch *schema.Schema, nilai reflect.Value) (baris, error) {
+	kolom := make(map[string]interface{}, len(sch.Fields))
+	for _, field := range sch.Fields {
+		if field.DBName == "" || strings.Split(field.Tag.Get("json"), ",")[0] == "-" {
+			continue
+		}
+		kolom[field.DBName], _ = field.ValueOf(ctx, nilai)
+	}
+
+	data, err := json.Marshal(kolom)
+	if err != nil {
+		return baris{}, err
+	}
+
+	b := baris{nilai: nilai, data: string(data)}
+	id, _ := sch.PrioritizedPrimaryField.ValueOf(ctx, nilai)
+	var ok bool
+	if b.id, ok = id.(uuid.UUID); !ok {
+		return baris{}, fmt.Errorf("primary key %s bukan UUID", sch.Table)
+	}
+
+	// Koperasi pemilik baris: kolom id_koperasi, ID koperasi itu sendiri, atau baris induknya
+	switch {
+	case sch.Table == "koperasi":
+		b.idKoperasi = b.id
+	case sch.LookUpField("IDKoperasi") != nil:
+		v, _ := sch.LookUpField("IDKoperasi").ValueOf(ctx, nilai)
+		b.idKoperasi, _ = v.(uuid.UUID)
+	default:
+		if ind, ok := tabelInduk[sch.Table]; ok && sch.LookUpField(ind.field) != nil {
+			v, _ := sch.LookUpField(ind.field).ValueOf(ctx, nilai)
+			b.idInduk, _ = v.(uuid.UUID)
+		}
+	}
+	return b, nil
+}
+
+func snapshotSemua(ctx context.Context, sch *schema.Schema, nilai reflect.Value) ([]baris, error) {
+	var hasil []baris
+	for _, e := range elemen(nilai) {
+		b, err := snapshot(ctx, sch, e)
+		if err != nil {
+			return nil, err
+		}
+		hasil = append(hasil, b)
+	}
+	return hasil, nil
+}
+
+// muatBaris membaca ulang baris tabel statement dengan kondisi tertentu dalam transaksi yang sama
+func muatBaris(db *gorm.DB, kondisi []clause.Expression, unscoped, kunci bool) ([]baris, error) {
+	sch := db.Statement.Schema
+	hasil := reflect.New(reflect.SliceOf(sch.ModelType))
+
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Clauses(clause.Where{Exprs: kondisi})
+	if unscoped {
+		query = query.Unscoped()
+	}
+	if kunci {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.Find(hasil.Interface()).Error; err != nil {
+		return nil, err
+	}
+	return snapshotSemua(db.Statement.Context, sch, hasil)
+}
+
+// kondisiStatement menyusun kondisi WHERE yang akan dipakai statement update/delete, termasuk
+// primary key model yang di-set oleh GORM saat statement dijalankan
+func kondisiStatement(db *gorm.DB) []clause.Expression {
+	var kondisi []clause.Expression
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			kondisi = append(kondisi, where.Exprs...)
+		}
+	}
+
+	sch := db.Statement.Schema
+	var idList []interface{}
+	for _, e := range elemen(db.Statement.ReflectValue) {
+		if id, zero := sch.PrioritizedPrimaryField.ValueOf(db.Statement.Context, e); !zero {
+			idList = append(idList, id)
+		}
+	}
+	if len(idList) > 0 {
+		kondisi = append(kondisi, clause.IN{
+			Column: clause.Column{Table: clause.CurrentTable, Name: sch.PrioritizedPrimaryField.DBName},
+			Values: idList,
+		})
+	}
+	return kondisi
+}
+
+// ambilDataSebelum mengunci dan menyimpan snapshot baris yang akan diubah atau dihapus
+func ambilDataSebelum(db *gorm.DB) {
+	if !diaudit(db) {
+		return
+	}
+
+	kondisi := kondisiStatement(db)
+	if len(kondisi) == 0 {
+		return // Update/delete tanpa kondisi akan ditolak GORM
+	}
+
+	sebelum, err := muatBaris(db, kondisi, db.Statement.Unscoped, true)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: gagal membaca data sebelum perubahan: %w", err))
+		return
+	}
+	db.InstanceSet(kunciDataSebelum, sebelum)
+}
+
+func dataSebelum(db *gorm.DB) []baris {
+	nilai, ok := db.InstanceGet(kunciDataSebelum)
+	if !ok {
+		return nil
+	}
+	return nilai.([]baris)
+}
+
+func catatBuat(db *gorm.DB) {
+	if !diaudit(db) {
+		return
+	}
+
+	sesudah, err := snapshotSemua(db.Statement.Context, db.Statement.Schema, db.Statement.ReflectValue)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+
+	entri := make([]*models.LogAudit, 0, len(sesudah))
+	for _, b := range sesudah {
+		e := entriBaru(db, models.AksiAuditBuat, b)
+		e.DataSesudah = b.data
+		if e.IDPengguna == nil {
+			e.IDPengguna = penggunaPembuat(db, b.nilai)
+		}
+		entri = append(entri, e)
+	}
+	simpanEntri(db, entri, sesudah)
+}
+
+func catatUbah(db *gorm.DB) {
+	if !diaudit(db) {
+		return
+	}
+	sebelum := dataSebelum(db)
+	if len(sebelum) == 0 {
+		return
+	}
+
+	idList := make([]interface{}, len(sebelum))
+	for i, b := range sebelum {
+		idList[i] = b.id
+	}
+	sesudah, err := muatBaris(db, []clause.Expression{clause.IN{
+		Column: clause.Column{Table: clause.CurrentTable, Name: db.Statement.Schema.PrioritizedPrimaryField.DBName},
+		Values: idList,
+	}}, true, false)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: gagal membaca data sesudah perubahan: %w", err))
+		return
+	}
+	dataSesudah := make(map[uuid.UUID]string, len(sesudah))
+	for _, b := range sesudah {
+		dataSesudah[b.id] = b.data
+	}
+
+	var entri []*models.LogAudit
+	for _, b := range sebelum {
+		if dataSesudah[b.id] == b.data {
+			continue // Tidak ada kolom yang berubah
+		}
+		e := entriBaru(db, models.AksiAuditUbah, b)
+		e.DataSebelum = b.data
+		e.DataSesudah = dataSesudah[b.id]
+		entri = append(entri, e)
+	}
+	simpanEntri(db, entri, sebelum)
+}
+
+func catatHapus(db *gorm.DB) {
+	if !diaudit(db) {
+		return
+	}
+
+	sebelum := dataSebelum(db)
+	var entri []*models.LogAudit
+	for _, b := range sebelum {
+		e := entriBaru(db, models.AksiAuditHapus, b)
+		e.DataSebelum = b.data
+		entri = append(entri, e)
+	}
+	simpanEntri(db, entri, sebelum)
+}
+
+func entriBaru(db *gorm.DB, aksi models.AksiAudit, b baris) *models.LogAudit {
+	entri := &models.LogAudit{
+		ID:         uuid.New(),
+		IDKoperasi: b.idKoperasi,
+		Entitas:    db.Statement.Schema.Table,
+		IDEntitas:  b.id,
+		Aksi:       aksi,
+		Waktu:      time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if info, ok := InfoDari(db.Statement.Context); ok {
+		entri.IDPengguna = info.IDPengguna
+		entri.AlamatIP = info.AlamatIP
+		entri.IDRequest = info.IDRequest
+	}
+	return entri
+}
+
+// penggunaPembuat mengambil kolom DibuatOleh dari baris baru sebagai pelaku jika request tidak
+// membawa informasi pengguna, misalnya jurnal yang dibuat job background atas nama pengguna
+func penggunaPembuat(db *gorm.DB, nilai reflect.Value) *uuid.UUID {
+	field := db.Statement.Schema.LookUpField("DibuatOleh")
+	if field == nil {
+		return nil
+	}
+	v, zero := field.ValueOf(db.Statement.Context, nilai)
+	if zero {
+		return nil
+	}
+	switch id := v.(type) {
+	case uuid.UUID:
+		return &id
+	case *uuid.UUID:
+		return id
+	}
+	return nil
+}
+
+// simpanEntri menulis entri dalam transaksi statement. Baris jurnal dan item penjualan tidak
+// memiliki kolom id_koperasi, sehingga koperasinya dibaca dari baris induk lebih dulu.
+func simpanEntri(db *gorm.DB, entri []*models.LogAudit, barisList []baris) {
+	if len(entri) == 0 {
+		return
+	}
+
+	if err := isiKoperasiDariInduk(db, entri, barisList); err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	for _, e := range entri {
+		if e.IDKoperasi == uuid.Nil {
+			db.AddError(fmt.Errorf("audit: koperasi %s %s tidak diketahui", e.Entitas, e.IDEntitas))
+			return
+		}
+	}
+
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+	if err := tx.Create(&entri).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: gagal menulis log audit: %w", err))
+	}
+}
+
+func isiKoperasiDariInduk(db *gorm.DB, entri []*models.LogAudit, barisList []baris) error {
+	ind, ok := tabelInduk[db.Statement.Schema.Table]
+	if !ok {
+		return nil
+	}
+
+	idInduk := make(map[uuid.UUID]uuid.UUID)
+	var idList []uuid.UUID
+	for _, b := range barisList {
+		if b.idInduk != uuid.Nil {
+			idInduk[b.id] = b.idInduk
+			idList = append(idList, b.idInduk)
+		}
+	}
+	if len(idList) == 0 {
+		return nil
+	}
+
+	var indukList []struct {
+		ID         uuid.UUID
+		IDKoperasi uuid.UUID
+	}
+	err := db.Session(&gorm.Session{NewDB: true}).Table(ind.tabel).
+		Select("id, id_koperasi").
+		Where("id IN ?", idList).
+		Scan(&indukList).Error
+	if err != nil {
+		return fmt.Errorf("gagal membaca %s induk %s: %w", ind.tabel, db.Statement.Schema.Table, err)
+	}
+	koperasiInduk := make(map[uuid.UUID]uuid.UUID, len(indukList))
+	for _, i := range indukList {
+		koperasiInduk[i.ID] = i.IDKoperasi
+	}
+
+	for _, e := range entri {
+		if id, ok := idInduk[e.IDEntitas]; ok {
+			e.IDKoperasi = koperasiInduk[id]
+		}
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ type Config struct {
 	Server   ServerConfig
 	JWT      JWTConfig
 	CORS     CORSConfig
+	Storage  StorageConfig
 }
 
 // DatabaseConfig berisi konfigurasi database
@@ -44,6 +45,11 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
+// StorageConfig berisi konfigurasi penyimpanan berkas lampiran
+type StorageConfig struct {
+	LocalDir string
+}
+
 // LoadConfig memuat konfigurasi dari environment variables
 func LoadConfig() (*Config, error) {
 	// Load .env file jika ada (untuk development)
@@ -78,6 +84,9 @@ func LoadConfig() (*Config, error) {
 				getEnv("ALLOWED_ORIGINS", "http://localhost:3000"),
 			},
 		},
+		Storage: StorageConfig{
+			LocalDir: getEnv("STORAGE_LOCAL_DIR", "./data/lampiran"),
+		},
 	}
 
 	// Validate configuration before returning
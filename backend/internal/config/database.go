@@ -1,6 +1,7 @@
 package config
 
 import (
+	"cooperative-erp-lite/internal/audit"
 	"cooperative-erp-lite/internal/models"
 	"fmt"
 	"log"
@@ -30,6 +31,11 @@ func InitDatabase(config *Config) error {
 
 	log.Println("Koneksi database berhasil")
 
+	// Catat setiap perubahan data keuangan ke log audit berantai hash
+	if err := DB.Use(audit.Plugin{}); err != nil {
+		return fmt.Errorf("gagal memasang plugin audit: %w", err)
+	}
+
 	// Auto migrate semua model
 	err = AutoMigrate()
 	if err != nil {
@@ -54,6 +60,27 @@ func AutoMigrate() error {
 		&models.Produk{},
 		&models.Penjualan{},
 		&models.ItemPenjualan{},
+		&models.PengaturanSHU{},
+		&models.SHU{},
+		&models.SHUAnggota{},
+		&models.ProdukPinjaman{},
+		&models.Pinjaman{},
+		&models.JadwalAngsuran{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+		&models.Anggaran{},
+		&models.BarisAnggaran{},
+		&models.UnitUsaha{},
+		&models.AsetTetap{},
+		&models.PenyusutanAsetTetap{},
+		&models.TemplateJurnal{},
+		&models.BarisTemplateJurnal{},
+		&models.RekeningKoranBank{},
+		&models.MutasiBank{},
+		&models.Lampiran{},
+		&models.LogAudit{},
+		&models.PemotonganPPh{},
 	)
 	if err != nil {
 		return err
@@ -4,12 +4,53 @@ import (
 	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/internal/services"
 	"cooperative-erp-lite/internal/utils"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// ukuranMaksBerkasImpor adalah batas ukuran berkas impor (5 MB)
+const ukuranMaksBerkasImpor = 5 << 20
+
+// bacaBerkasImpor membaca berkas CSV/XLSX yang diunggah pada field multipart. Jika gagal,
+// response error sudah dikirim dan ok bernilai false.
+func bacaBerkasImpor(c *gin.Context, field, label string) (format services.FormatBerkas, data []byte, ok bool) {
+	fileHeader, err := c.FormFile(field)
+	if err != nil {
+		utils.BadRequestResponse(c, label+" wajib diunggah pada field "+field)
+		return "", nil, false
+	}
+	if fileHeader.Size > ukuranMaksBerkasImpor {
+		utils.BadRequestResponse(c, "Ukuran berkas impor maksimal 5 MB")
+		return "", nil, false
+	}
+
+	format = services.FormatBerkas(strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), "."))
+	if !format.IsValid() {
+		utils.BadRequestResponse(c, "Berkas impor harus berformat .csv atau .xlsx")
+		return "", nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, label+" tidak dapat dibaca")
+		return "", nil, false
+	}
+	defer file.Close()
+
+	data, err = io.ReadAll(io.LimitReader(file, ukuranMaksBerkasImpor))
+	if err != nil {
+		utils.BadRequestResponse(c, label+" tidak dapat dibaca")
+		return "", nil, false
+	}
+
+	return format, data, true
+}
+
 // AkunHandler menangani endpoint chart of accounts
 type AkunHandler struct {
 	akunService *services.AkunService
@@ -163,15 +204,81 @@ func (h *AkunHandler) GetSaldo(c *gin.Context) {
 	})
 }
 
-// SeedCOA handles POST /api/v1/akun/seed-coa
+// SeedCOA handles POST /api/v1/akun/seed-coa?template=KSU
 func (h *AkunHandler) SeedCOA(c *gin.Context) {
 	idKoperasi, _ := c.Get("idKoperasi")
 	koperasiUUID := idKoperasi.(uuid.UUID)
 
-	if err := h.akunService.InisialisasiCOADefault(koperasiUUID); err != nil {
+	jenis := models.JenisKoperasi(c.DefaultQuery("template", string(models.JenisKoperasiSerbaUsaha)))
+
+	if err := h.akunService.InisialisasiCOA(koperasiUUID, jenis); err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Chart of Accounts default berhasil di-seed", nil)
+	utils.SuccessResponse(c, http.StatusOK, "Chart of Accounts berhasil di-seed dari template "+string(jenis), nil)
+}
+
+// ListTemplate handles GET /api/v1/akun/template
+func (h *AkunHandler) ListTemplate(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Template COA berhasil diambil", h.akunService.DaftarTemplateCOA())
+}
+
+// Ekspor handles GET /api/v1/akun/ekspor?format=csv|xlsx
+func (h *AkunHandler) Ekspor(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	format := services.FormatBerkas(c.DefaultQuery("format", string(services.FormatCSV)))
+	if !format.IsValid() {
+		utils.BadRequestResponse(c, "Format ekspor harus csv atau xlsx")
+		return
+	}
+
+	data, err := h.akunService.EksporCOA(koperasiUUID, format)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	tipeKonten := "text/csv; charset=utf-8"
+	if format == services.FormatXLSX {
+		tipeKonten = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", `attachment; filename="coa.`+string(format)+`"`)
+	c.Data(http.StatusOK, tipeKonten, data)
+}
+
+// Impor handles POST /api/v1/akun/impor?dryRun=true (multipart, field "file")
+func (h *AkunHandler) Impor(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	format, data, ok := bacaBerkasImpor(c, "file", "Berkas COA")
+	if !ok {
+		return
+	}
+
+	dryRun := c.Query("dryRun") == "true"
+
+	hasil, err := h.akunService.ImporCOA(koperasiUUID, format, data, dryRun)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if len(hasil.Kesalahan) > 0 {
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Berkas COA berisi kesalahan, tidak ada akun yang disimpan", hasil)
+		return
+	}
+
+	pesan := "Impor COA berhasil"
+	if dryRun {
+		pesan = "Validasi impor COA berhasil, belum ada akun yang disimpan"
+	}
+	utils.SuccessResponse(c, http.StatusOK, pesan, hasil)
 }
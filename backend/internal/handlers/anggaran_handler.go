@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnggaranHandler menangani endpoint RAPB dan laporan realisasi anggaran
+type AnggaranHandler struct {
+	anggaranService *services.AnggaranService
+}
+
+// NewAnggaranHandler membuat instance baru AnggaranHandler
+func NewAnggaranHandler(anggaranService *services.AnggaranService) *AnggaranHandler {
+	return &AnggaranHandler{
+		anggaranService: anggaranService,
+	}
+}
+
+// List handles GET /api/v1/anggaran
+func (h *AnggaranHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	anggaranList, err := h.anggaranService.DaftarAnggaran(koperasiUUID)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Daftar anggaran berhasil diambil", anggaranList)
+}
+
+// GetByTahunBuku handles GET /api/v1/anggaran/:tahunBuku
+func (h *AnggaranHandler) GetByTahunBuku(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Param("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tahun buku tidak valid")
+		return
+	}
+
+	anggaran, err := h.anggaranService.DapatkanAnggaran(koperasiUUID, tahunBuku)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggaran berhasil diambil", anggaran)
+}
+
+// Simpan handles PUT /api/v1/anggaran/:tahunBuku
+// Membuat atau mengganti seluruh baris RAPB yang masih draft
+func (h *AnggaranHandler) Simpan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Param("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tahun buku tidak valid")
+		return
+	}
+
+	var req services.SimpanAnggaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	anggaran, err := h.anggaranService.SimpanAnggaran(koperasiUUID, penggunaUUID, tahunBuku, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggaran berhasil disimpan", anggaran)
+}
+
+// Salin handles POST /api/v1/anggaran/:tahunBuku/salin
+// Membuat RAPB tahun buku dari RAPB tahun sebelumnya dengan persentase pertumbuhan
+func (h *AnggaranHandler) Salin(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Param("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tahun buku tidak valid")
+		return
+	}
+
+	var req services.SalinAnggaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	anggaran, err := h.anggaranService.SalinAnggaranTahunLalu(koperasiUUID, penggunaUUID, tahunBuku, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Anggaran berhasil disalin dari tahun lalu", anggaran)
+}
+
+// Sahkan handles POST /api/v1/anggaran/:tahunBuku/sahkan
+func (h *AnggaranHandler) Sahkan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Param("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tahun buku tidak valid")
+		return
+	}
+
+	var req services.SahkanAnggaranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	anggaran, err := h.anggaranService.SahkanAnggaran(koperasiUUID, penggunaUUID, tahunBuku, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggaran berhasil disahkan", anggaran)
+}
+
+// Delete handles DELETE /api/v1/anggaran/:tahunBuku
+func (h *AnggaranHandler) Delete(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Param("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tahun buku tidak valid")
+		return
+	}
+
+	if err := h.anggaranService.HapusAnggaran(koperasiUUID, tahunBuku); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Anggaran berhasil dihapus", nil)
+}
+
+// GetRealisasi handles GET /api/v1/laporan/realisasi-anggaran?tahunBuku=2024&sampaiBulan=6
+// sampaiBulan adalah bulan ke-n tahun buku (1-12); kosong berarti satu tahun buku penuh
+func (h *AnggaranHandler) GetRealisasi(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Query("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Parameter tahunBuku wajib diisi dan harus berupa angka")
+		return
+	}
+
+	sampaiBulan := 0
+	if nilai := c.Query("sampaiBulan"); nilai != "" {
+		sampaiBulan, err = strconv.Atoi(nilai)
+		if err != nil {
+			utils.BadRequestResponse(c, "Parameter sampaiBulan harus berupa angka")
+			return
+		}
+	}
+
+	laporan, err := h.anggaranService.LaporanRealisasiAnggaran(koperasiUUID, tahunBuku, sampaiBulan)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Laporan realisasi anggaran berhasil digenerate", laporan)
+}
@@ -36,7 +36,7 @@ func (h *AnggotaHandler) Create(c *gin.Context) {
 		return
 	}
 
-	anggota, err := h.anggotaService.BuatAnggota(koperasiUUID, &req)
+	anggota, err := h.anggotaService.DenganKonteks(c.Request.Context()).BuatAnggota(koperasiUUID, &req)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -135,7 +135,7 @@ func (h *AnggotaHandler) Update(c *gin.Context) {
 		return
 	}
 
-	anggota, err := h.anggotaService.PerbaruiAnggota(koperasiUUID, id, &req)
+	anggota, err := h.anggotaService.DenganKonteks(c.Request.Context()).PerbaruiAnggota(koperasiUUID, id, &req)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -158,7 +158,7 @@ func (h *AnggotaHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.anggotaService.HapusAnggota(koperasiUUID, id); err != nil {
+	if err := h.anggotaService.DenganKonteks(c.Request.Context()).HapusAnggota(koperasiUUID, id); err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
@@ -189,7 +189,7 @@ func (h *AnggotaHandler) SetPIN(c *gin.Context) {
 		return
 	}
 
-	if err := h.anggotaService.SetPINPortal(koperasiUUID, id, req.PIN); err != nil {
+	if err := h.anggotaService.DenganKonteks(c.Request.Context()).SetPINPortal(koperasiUUID, id, req.PIN); err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
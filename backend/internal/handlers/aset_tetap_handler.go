@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AsetTetapHandler menangani endpoint register aset tetap dan penyusutan
+type AsetTetapHandler struct {
+	asetTetapService *services.AsetTetapService
+}
+
+// NewAsetTetapHandler membuat instance baru AsetTetapHandler
+func NewAsetTetapHandler(asetTetapService *services.AsetTetapService) *AsetTetapHandler {
+	return &AsetTetapHandler{
+		asetTetapService: asetTetapService,
+	}
+}
+
+// Create handles POST /api/v1/aset-tetap
+func (h *AsetTetapHandler) Create(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.RegistrasiAsetTetapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	aset, err := h.asetTetapService.RegistrasiAsetTetap(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Aset tetap berhasil didaftarkan", aset)
+}
+
+// List handles GET /api/v1/aset-tetap?status=AKTIF&kelompok=KENDARAAN
+func (h *AsetTetapHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	asetList, err := h.asetTetapService.DapatkanSemuaAsetTetap(koperasiUUID, c.Query("status"), c.Query("kelompok"))
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data aset tetap berhasil diambil", asetList)
+}
+
+// GetByID handles GET /api/v1/aset-tetap/:id
+func (h *AsetTetapHandler) GetByID(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID aset tetap tidak valid")
+		return
+	}
+
+	aset, err := h.asetTetapService.DapatkanAsetTetap(id, koperasiUUID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data aset tetap berhasil diambil", aset)
+}
+
+// PostingPenyusutan handles POST /api/v1/aset-tetap/penyusutan
+func (h *AsetTetapHandler) PostingPenyusutan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.PostingPenyusutanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	hasil, err := h.asetTetapService.PostingPenyusutan(koperasiUUID, penggunaUUID, req.Tahun, req.Bulan)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Penyusutan aset tetap berhasil diposting", hasil)
+}
+
+// Lepas handles POST /api/v1/aset-tetap/:id/pelepasan
+func (h *AsetTetapHandler) Lepas(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID aset tetap tidak valid")
+		return
+	}
+
+	var req services.LepasAsetTetapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	aset, err := h.asetTetapService.LepasAsetTetap(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pelepasan aset tetap berhasil dicatat", aset)
+}
+
+// Batalkan handles POST /api/v1/aset-tetap/:id/batal
+func (h *AsetTetapHandler) Batalkan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID aset tetap tidak valid")
+		return
+	}
+
+	var req services.BatalkanAsetTetapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	aset, err := h.asetTetapService.BatalkanRegistrasiAsetTetap(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Registrasi aset tetap berhasil dibatalkan", aset)
+}
+
+// BatalkanPenyusutan handles POST /api/v1/aset-tetap/penyusutan/batal
+func (h *AsetTetapHandler) BatalkanPenyusutan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.BatalkanPenyusutanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	hasil, err := h.asetTetapService.BatalkanPenyusutan(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Penyusutan aset tetap berhasil dibatalkan", hasil)
+}
+
+// BatalkanPelepasan handles POST /api/v1/aset-tetap/:id/pelepasan/batal
+func (h *AsetTetapHandler) BatalkanPelepasan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID aset tetap tidak valid")
+		return
+	}
+
+	var req services.BatalkanAsetTetapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	aset, err := h.asetTetapService.BatalkanPelepasan(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pelepasan aset tetap berhasil dibatalkan", aset)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AturanPostingHandler menangani endpoint pemetaan akun untuk jurnal otomatis
+type AturanPostingHandler struct {
+	aturanPostingService *services.AturanPostingService
+}
+
+// NewAturanPostingHandler membuat instance baru AturanPostingHandler
+func NewAturanPostingHandler(aturanPostingService *services.AturanPostingService) *AturanPostingHandler {
+	return &AturanPostingHandler{
+		aturanPostingService: aturanPostingService,
+	}
+}
+
+// List handles GET /api/v1/aturan-posting
+func (h *AturanPostingHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	aturanList, err := h.aturanPostingService.DapatkanAturanPosting(koperasiUUID)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Aturan posting berhasil diambil", aturanList)
+}
+
+// Update handles PUT /api/v1/aturan-posting
+func (h *AturanPostingHandler) Update(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	var req services.PerbaruiAturanPostingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	aturanList, err := h.aturanPostingService.PerbaruiAturanPosting(koperasiUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Aturan posting berhasil diperbarui", aturanList)
+}
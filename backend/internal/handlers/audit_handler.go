@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuditHandler menangani endpoint penelusuran dan verifikasi log audit
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler membuat instance baru AuditHandler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// List handles GET /api/v1/audit?entitas=transaksi&idEntitas=<uuid>&idPengguna=<uuid>&idRequest=...
+func (h *AuditHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	page, pageSize = utils.ValidatePagination(page, pageSize)
+
+	filter := services.FilterLogAudit{
+		Entitas:   c.Query("entitas"),
+		IDRequest: c.Query("idRequest"),
+	}
+	if idStr := c.Query("idEntitas"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID entitas tidak valid")
+			return
+		}
+		filter.IDEntitas = &id
+	}
+	if idStr := c.Query("idPengguna"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID pengguna tidak valid")
+			return
+		}
+		filter.IDPengguna = &id
+	}
+
+	logList, total, err := h.auditService.DapatkanLogAudit(koperasiUUID, filter, page, pageSize)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	pagination := utils.CalculatePaginationMeta(page, pageSize, total)
+	utils.PaginatedSuccessResponse(c, http.StatusOK, "Data log audit berhasil diambil", logList, pagination)
+}
+
+// Verifikasi handles GET /api/v1/audit/verifikasi
+// Menghitung ulang rantai hash log audit koperasi; valid=false berarti ada entri yang dirusak
+func (h *AuditHandler) Verifikasi(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	hasil, err := h.auditService.VerifikasiLogAudit(koperasiUUID)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	pesan := "Rantai log audit utuh"
+	if !hasil.Valid {
+		pesan = "Rantai log audit rusak: terdapat entri yang diubah atau dihapus"
+	}
+	utils.SuccessResponse(c, http.StatusOK, pesan, hasil)
+}
@@ -44,7 +44,7 @@ func (h *KoperasiHandler) Create(c *gin.Context) {
 	}
 
 	// Buat koperasi
-	koperasi, err := h.koperasiService.BuatKoperasi(&req)
+	koperasi, err := h.koperasiService.DenganKonteks(c.Request.Context()).BuatKoperasi(&req)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -141,7 +141,7 @@ func (h *KoperasiHandler) Update(c *gin.Context) {
 	}
 
 	// Update koperasi
-	koperasi, err := h.koperasiService.PerbaruiKoperasi(id, &req)
+	koperasi, err := h.koperasiService.DenganKonteks(c.Request.Context()).PerbaruiKoperasi(id, &req)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -171,7 +171,7 @@ func (h *KoperasiHandler) Delete(c *gin.Context) {
 	}
 
 	// Delete koperasi
-	if err := h.koperasiService.HapusKoperasi(id); err != nil {
+	if err := h.koperasiService.DenganKonteks(c.Request.Context()).HapusKoperasi(id); err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LampiranHandler menangani endpoint lampiran bukti transaksi, simpanan, penjualan dan anggota
+type LampiranHandler struct {
+	lampiranService *services.LampiranService
+}
+
+// NewLampiranHandler membuat instance baru LampiranHandler
+func NewLampiranHandler(lampiranService *services.LampiranService) *LampiranHandler {
+	return &LampiranHandler{
+		lampiranService: lampiranService,
+	}
+}
+
+// Unggah handles POST /api/v1/lampiran (multipart/form-data)
+// Field: berkas (PDF/JPG/PNG/WEBP, maks 10 MB), jenisDokumen (TRANSAKSI, SIMPANAN, PENJUALAN,
+// ANGGOTA), idDokumen, keterangan (opsional)
+func (h *LampiranHandler) Unggah(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	idDokumen, err := uuid.Parse(c.PostForm("idDokumen"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID dokumen tidak valid")
+		return
+	}
+
+	fileHeader, err := c.FormFile("berkas")
+	if err != nil {
+		utils.BadRequestResponse(c, "Berkas lampiran wajib diunggah pada field berkas")
+		return
+	}
+	if fileHeader.Size > services.UkuranMaksLampiran {
+		utils.BadRequestResponse(c, "Ukuran berkas lampiran maksimal 10 MB")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Berkas lampiran tidak dapat dibaca")
+		return
+	}
+	defer file.Close()
+
+	// Baca satu byte lebih dari batas agar berkas yang melebihi batas tetap ditolak service
+	data, err := io.ReadAll(io.LimitReader(file, services.UkuranMaksLampiran+1))
+	if err != nil {
+		utils.BadRequestResponse(c, "Berkas lampiran tidak dapat dibaca")
+		return
+	}
+
+	lampiran, err := h.lampiranService.UnggahLampiran(koperasiUUID, penggunaUUID, &services.UnggahLampiranRequest{
+		JenisDokumen: models.JenisDokumenLampiran(strings.ToUpper(c.PostForm("jenisDokumen"))),
+		IDDokumen:    idDokumen,
+		NamaBerkas:   fileHeader.Filename,
+		Keterangan:   c.PostForm("keterangan"),
+		Data:         data,
+	})
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Lampiran berhasil diunggah", lampiran)
+}
+
+// List handles GET /api/v1/lampiran?jenisDokumen=TRANSAKSI&idDokumen=<uuid>
+func (h *LampiranHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	idDokumen, err := uuid.Parse(c.Query("idDokumen"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID dokumen tidak valid")
+		return
+	}
+
+	jenis := models.JenisDokumenLampiran(strings.ToUpper(c.Query("jenisDokumen")))
+	lampiranList, err := h.lampiranService.DapatkanLampiranDokumen(koperasiUUID, jenis, idDokumen)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data lampiran berhasil diambil", lampiranList)
+}
+
+// Unduh handles GET /api/v1/lampiran/:id/unduh
+func (h *LampiranHandler) Unduh(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID lampiran tidak valid")
+		return
+	}
+
+	lampiran, isi, err := h.lampiranService.BukaLampiran(id, koperasiUUID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+	defer isi.Close()
+
+	// Selalu sebagai attachment dengan tipe konten hasil deteksi saat unggah,
+	// sehingga browser tidak merender berkas sebagai halaman aplikasi
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, lampiran.Ukuran, lampiran.TipeKonten, isi, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": lampiran.NamaBerkas}),
+	})
+}
+
+// Delete handles DELETE /api/v1/lampiran/:id
+func (h *LampiranHandler) Delete(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID lampiran tidak valid")
+		return
+	}
+
+	if err := h.lampiranService.HapusLampiran(id, koperasiUUID, penggunaUUID); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Lampiran berhasil dihapus", nil)
+}
@@ -4,6 +4,9 @@ import (
 	"cooperative-erp-lite/internal/services"
 	"cooperative-erp-lite/internal/utils"
 	"net/http"
+	"path"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -22,22 +25,28 @@ func NewLaporanHandler(laporanService *services.LaporanService) *LaporanHandler
 }
 
 // GetNeraca handles GET /api/v1/laporan/neraca
+// Query param pembanding (tahunLalu, bulanan) dan jumlahBulan menyajikan neraca komparatif
 func (h *LaporanHandler) GetNeraca(c *gin.Context) {
 	idKoperasi, _ := c.Get("idKoperasi")
 	koperasiUUID := idKoperasi.(uuid.UUID)
 
 	tanggalPer := c.DefaultQuery("tanggalPer", "") // Format: YYYY-MM-DD
 
-	neraca, err := h.laporanService.GenerateLaporanPosisiKeuangan(koperasiUUID, tanggalPer)
-	if err != nil {
-		utils.SafeInternalServerErrorResponse(c, err)
+	pembanding, jumlahBulan, ok := bacaPembanding(c)
+	if !ok {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Laporan neraca berhasil digenerate", neraca)
+	h.sajikanLaporan(c, koperasiUUID, "Laporan neraca berhasil digenerate", "Laporan Posisi Keuangan (Neraca)", func(laporanService *services.LaporanService) (interface{}, error) {
+		if pembanding != "" {
+			return laporanService.GenerateLaporanPosisiKeuanganKomparatif(koperasiUUID, tanggalPer, pembanding, jumlahBulan)
+		}
+		return laporanService.GenerateLaporanPosisiKeuangan(koperasiUUID, tanggalPer)
+	})
 }
 
 // GetLabaRugi handles GET /api/v1/laporan/laba-rugi
+// Query param pembanding (tahunLalu, bulanan) menyajikan laba rugi komparatif
 func (h *LaporanHandler) GetLabaRugi(c *gin.Context) {
 	idKoperasi, _ := c.Get("idKoperasi")
 	koperasiUUID := idKoperasi.(uuid.UUID)
@@ -50,13 +59,17 @@ func (h *LaporanHandler) GetLabaRugi(c *gin.Context) {
 		return
 	}
 
-	labaRugi, err := h.laporanService.GenerateLaporanLabaRugi(koperasiUUID, tanggalMulai, tanggalAkhir)
-	if err != nil {
-		utils.SafeInternalServerErrorResponse(c, err)
+	pembanding, _, ok := bacaPembanding(c)
+	if !ok {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Laporan laba rugi berhasil digenerate", labaRugi)
+	h.sajikanLaporan(c, koperasiUUID, "Laporan laba rugi berhasil digenerate", "Laporan Laba Rugi", func(laporanService *services.LaporanService) (interface{}, error) {
+		if pembanding != "" {
+			return laporanService.GenerateLaporanLabaRugiKomparatif(koperasiUUID, tanggalMulai, tanggalAkhir, pembanding)
+		}
+		return laporanService.GenerateLaporanLabaRugi(koperasiUUID, tanggalMulai, tanggalAkhir)
+	})
 }
 
 // GetPerubahanModal handles GET /api/v1/laporan/perubahan-modal
@@ -72,16 +85,13 @@ func (h *LaporanHandler) GetPerubahanModal(c *gin.Context) {
 		return
 	}
 
-	perubahanModal, err := h.laporanService.GenerateLaporanPerubahanModal(koperasiUUID, tanggalMulai, tanggalAkhir)
-	if err != nil {
-		utils.SafeInternalServerErrorResponse(c, err)
-		return
-	}
-
-	utils.SuccessResponse(c, http.StatusOK, "Laporan perubahan modal berhasil digenerate", perubahanModal)
+	h.sajikanLaporan(c, koperasiUUID, "Laporan perubahan modal berhasil digenerate", "", func(laporanService *services.LaporanService) (interface{}, error) {
+		return laporanService.GenerateLaporanPerubahanModal(koperasiUUID, tanggalMulai, tanggalAkhir)
+	})
 }
 
 // GetArusKas handles GET /api/v1/laporan/arus-kas
+// Query param metode: LANGSUNG (default) atau TIDAK_LANGSUNG
 func (h *LaporanHandler) GetArusKas(c *gin.Context) {
 	idKoperasi, _ := c.Get("idKoperasi")
 	koperasiUUID := idKoperasi.(uuid.UUID)
@@ -94,13 +104,11 @@ func (h *LaporanHandler) GetArusKas(c *gin.Context) {
 		return
 	}
 
-	arusKas, err := h.laporanService.GenerateLaporanArusKas(koperasiUUID, tanggalMulai, tanggalAkhir)
-	if err != nil {
-		utils.SafeInternalServerErrorResponse(c, err)
-		return
-	}
+	metode := services.MetodeArusKas(c.DefaultQuery("metode", string(services.MetodeArusKasLangsung)))
 
-	utils.SuccessResponse(c, http.StatusOK, "Laporan arus kas berhasil digenerate", arusKas)
+	h.sajikanLaporan(c, koperasiUUID, "Laporan arus kas berhasil digenerate", "", func(laporanService *services.LaporanService) (interface{}, error) {
+		return laporanService.GenerateLaporanArusKas(koperasiUUID, tanggalMulai, tanggalAkhir, metode)
+	})
 }
 
 // GetBukuBesar handles GET /api/v1/laporan/buku-besar
@@ -126,29 +134,30 @@ func (h *LaporanHandler) GetBukuBesar(c *gin.Context) {
 		return
 	}
 
-	bukuBesar, err := h.laporanService.GenerateBukuBesar(koperasiUUID, idAkun, tanggalMulai, tanggalAkhir)
-	if err != nil {
-		utils.SafeInternalServerErrorResponse(c, err)
-		return
-	}
-
-	utils.SuccessResponse(c, http.StatusOK, "Buku besar berhasil digenerate", bukuBesar)
+	h.sajikanLaporan(c, koperasiUUID, "Buku besar berhasil digenerate", "Buku Besar", func(laporanService *services.LaporanService) (interface{}, error) {
+		return laporanService.GenerateBukuBesar(koperasiUUID, idAkun, tanggalMulai, tanggalAkhir)
+	})
 }
 
 // GetNeracaSaldo handles GET /api/v1/laporan/neraca-saldo
+// Query param pembanding (tahunLalu, bulanan) dan jumlahBulan menyajikan neraca saldo komparatif
 func (h *LaporanHandler) GetNeracaSaldo(c *gin.Context) {
 	idKoperasi, _ := c.Get("idKoperasi")
 	koperasiUUID := idKoperasi.(uuid.UUID)
 
 	tanggalPer := c.DefaultQuery("tanggalPer", "")
 
-	neracaSaldo, err := h.laporanService.GenerateNeracaSaldo(koperasiUUID, tanggalPer)
-	if err != nil {
-		utils.SafeInternalServerErrorResponse(c, err)
+	pembanding, jumlahBulan, ok := bacaPembanding(c)
+	if !ok {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Neraca saldo berhasil digenerate", neracaSaldo)
+	h.sajikanLaporan(c, koperasiUUID, "Neraca saldo berhasil digenerate", "Neraca Saldo", func(laporanService *services.LaporanService) (interface{}, error) {
+		if pembanding != "" {
+			return laporanService.GenerateNeracaSaldoKomparatif(koperasiUUID, tanggalPer, pembanding, jumlahBulan)
+		}
+		return laporanService.GenerateNeracaSaldo(koperasiUUID, tanggalPer)
+	})
 }
 
 // GetTransaksiHarian handles GET /api/v1/laporan/transaksi-harian
@@ -158,11 +167,167 @@ func (h *LaporanHandler) GetTransaksiHarian(c *gin.Context) {
 
 	tanggal := c.DefaultQuery("tanggal", "") // YYYY-MM-DD, default hari ini
 
-	laporan, err := h.laporanService.GenerateLaporanTransaksiHarian(koperasiUUID, tanggal)
+	h.sajikanLaporan(c, koperasiUUID, "Laporan transaksi harian berhasil digenerate", "Laporan Transaksi Harian", func(laporanService *services.LaporanService) (interface{}, error) {
+		return laporanService.GenerateLaporanTransaksiHarian(koperasiUUID, tanggal)
+	})
+}
+
+// GetSaldoAnggota handles GET /api/v1/laporan/saldo-anggota
+// Saldo simpanan pokok, wajib, dan sukarela setiap anggota
+func (h *LaporanHandler) GetSaldoAnggota(c *gin.Context) {
+	idKoperasi, _ := c.Get("idKoperasi")
+	koperasiUUID := idKoperasi.(uuid.UUID)
+
+	format, ok := bacaFormatLaporan(c)
+	if !ok {
+		return
+	}
+
+	laporan, err := h.laporanService.GenerateLaporanSaldoAnggota(koperasiUUID)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Laporan transaksi harian berhasil digenerate", laporan)
+	h.kirimLaporan(c, koperasiUUID, "Laporan saldo simpanan anggota berhasil digenerate", "Laporan Saldo Simpanan Anggota", format, laporan)
+}
+
+// GetKontribusiUnitUsaha handles GET /api/v1/laporan/kontribusi-unit-usaha
+// Pendapatan, beban, dan kontribusi SHU setiap unit usaha selama periode
+func (h *LaporanHandler) GetKontribusiUnitUsaha(c *gin.Context) {
+	idKoperasi, _ := c.Get("idKoperasi")
+	koperasiUUID := idKoperasi.(uuid.UUID)
+
+	tanggalMulai := c.Query("tanggalMulai")
+	tanggalAkhir := c.Query("tanggalAkhir")
+
+	if tanggalMulai == "" || tanggalAkhir == "" {
+		utils.BadRequestResponse(c, "Parameter tanggalMulai dan tanggalAkhir wajib diisi")
+		return
+	}
+
+	kontribusi, err := h.laporanService.GenerateKontribusiUnitUsaha(koperasiUUID, tanggalMulai, tanggalAkhir)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Laporan kontribusi unit usaha berhasil digenerate", kontribusi)
+}
+
+// bacaPembanding membaca query param laporan komparatif:
+//   - pembanding=tahunLalu menambahkan kolom tanggal/periode yang sama tahun sebelumnya
+//   - pembanding=bulanan menyajikan kolom per bulan; jumlahBulan (2-24, default 12) berlaku untuk laporan per tanggal
+//
+// pembanding kosong berarti laporan satu periode. ok=false berarti response error sudah dikirim.
+func bacaPembanding(c *gin.Context) (pembanding services.JenisPembanding, jumlahBulan int, ok bool) {
+	pembanding = services.JenisPembanding(c.Query("pembanding"))
+	if pembanding != "" && !pembanding.IsValid() {
+		utils.BadRequestResponse(c, "Parameter pembanding hanya mendukung nilai tahunLalu atau bulanan")
+		return "", 0, false
+	}
+
+	if jumlahBulanStr := c.Query("jumlahBulan"); jumlahBulanStr != "" {
+		var err error
+		jumlahBulan, err = strconv.Atoi(jumlahBulanStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Parameter jumlahBulan harus berupa angka")
+			return "", 0, false
+		}
+	}
+	return pembanding, jumlahBulan, true
+}
+
+// bacaFormatLaporan menentukan format berkas laporan dari query param format (csv, xlsx, pdf, json)
+// atau, jika tidak ada, dari header Accept. Format kosong berarti response JSON biasa.
+// ok=false berarti response error sudah dikirim.
+func bacaFormatLaporan(c *gin.Context) (format services.FormatBerkas, ok bool) {
+	if formatStr := c.Query("format"); formatStr != "" {
+		format = services.FormatBerkas(formatStr)
+		if format == "json" {
+			return "", true
+		}
+		if !format.IsValidLaporan() {
+			utils.BadRequestResponse(c, "Parameter format hanya mendukung nilai json, csv, xlsx atau pdf")
+			return "", false
+		}
+		return format, true
+	}
+
+	accept := c.GetHeader("Accept")
+	for _, f := range []services.FormatBerkas{services.FormatPDF, services.FormatXLSX, services.FormatCSV} {
+		if strings.Contains(accept, strings.Split(f.TipeKonten(), ";")[0]) {
+			return f, true
+		}
+	}
+	return "", true
+}
+
+// kirimLaporan mengirim laporan sebagai JSON, atau sebagai berkas unduhan jika format diisi
+func (h *LaporanHandler) kirimLaporan(c *gin.Context, koperasiUUID uuid.UUID, pesan, judul string, format services.FormatBerkas, laporan interface{}) {
+	if format == "" {
+		utils.SuccessResponse(c, http.StatusOK, pesan, laporan)
+		return
+	}
+
+	data, err := h.laporanService.EksporLaporan(koperasiUUID, judul, laporan, format)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	// Nama berkas mengikuti endpoint, misalnya neraca.pdf atau laba-rugi.xlsx
+	c.Header("Content-Disposition", `attachment; filename="`+path.Base(c.Request.URL.Path)+"."+string(format)+`"`)
+	c.Data(http.StatusOK, format.TipeKonten(), data)
+}
+
+// sajikanLaporan menjalankan generate dengan query param unit usaha yang berlaku untuk semua laporan
+// berbasis jurnal:
+//   - idUnitUsaha=<uuid> membatasi laporan ke satu unit usaha, idUnitUsaha=tanpa ke baris tanpa unit usaha
+//   - kelompokkan=unitUsaha menyajikan satu laporan untuk setiap unit usaha
+//
+// Laporan dengan judul dapat diunduh sebagai berkas CSV, XLSX atau PDF (lihat bacaFormatLaporan).
+func (h *LaporanHandler) sajikanLaporan(c *gin.Context, koperasiUUID uuid.UUID, pesan, judul string, generate func(*services.LaporanService) (interface{}, error)) {
+	format, ok := bacaFormatLaporan(c)
+	if !ok {
+		return
+	}
+	if format != "" && judul == "" {
+		utils.BadRequestResponse(c, "Laporan ini hanya tersedia dalam format JSON")
+		return
+	}
+
+	laporanService := h.laporanService
+
+	if idUnitUsahaStr := c.Query("idUnitUsaha"); idUnitUsahaStr != "" {
+		idUnitUsaha := uuid.Nil
+		if idUnitUsahaStr != "tanpa" {
+			id, err := uuid.Parse(idUnitUsahaStr)
+			if err != nil {
+				utils.BadRequestResponse(c, "ID unit usaha tidak valid")
+				return
+			}
+			idUnitUsaha = id
+		}
+		laporanService = laporanService.UntukUnitUsaha(&idUnitUsaha)
+	}
+
+	switch c.Query("kelompokkan") {
+	case "":
+		laporan, err := generate(laporanService)
+		if err != nil {
+			utils.SafeInternalServerErrorResponse(c, err)
+			return
+		}
+		h.kirimLaporan(c, koperasiUUID, pesan, judul, format, laporan)
+	case "unitUsaha":
+		laporanPerUnit, err := laporanService.KelompokkanPerUnitUsaha(koperasiUUID, generate)
+		if err != nil {
+			utils.SafeInternalServerErrorResponse(c, err)
+			return
+		}
+		h.kirimLaporan(c, koperasiUUID, pesan, judul, format, laporanPerUnit)
+	default:
+		utils.BadRequestResponse(c, "Parameter kelompokkan hanya mendukung nilai unitUsaha")
+	}
 }
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PajakHandler menangani endpoint pemotongan PPh, laporan pajak bulanan dan ekspor e-Faktur
+type PajakHandler struct {
+	pajakService *services.PajakService
+}
+
+// NewPajakHandler membuat instance baru PajakHandler
+func NewPajakHandler(pajakService *services.PajakService) *PajakHandler {
+	return &PajakHandler{
+		pajakService: pajakService,
+	}
+}
+
+// bacaMasaPajak membaca query param tahun dan bulan yang wajib diisi
+func bacaMasaPajak(c *gin.Context) (int, int, bool) {
+	tahun, err := strconv.Atoi(c.Query("tahun"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Parameter tahun wajib diisi dan harus berupa angka")
+		return 0, 0, false
+	}
+	bulan, err := strconv.Atoi(c.Query("bulan"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Parameter bulan wajib diisi dan harus berupa angka")
+		return 0, 0, false
+	}
+	return tahun, bulan, true
+}
+
+// GetLaporan handles GET /api/v1/pajak/laporan?tahun=2025&bulan=1
+func (h *PajakHandler) GetLaporan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	tahun, bulan, ok := bacaMasaPajak(c)
+	if !ok {
+		return
+	}
+
+	laporan, err := h.pajakService.LaporanPajakBulanan(koperasiUUID, tahun, bulan)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Laporan pajak bulanan berhasil digenerate", laporan)
+}
+
+// EksporEFaktur handles GET /api/v1/pajak/e-faktur?tahun=2025&bulan=1&nomorFakturAwal=0102500000001
+func (h *PajakHandler) EksporEFaktur(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	tahun, bulan, ok := bacaMasaPajak(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.pajakService.EksporEFaktur(koperasiUUID, tahun, bulan, c.Query("nomorFakturAwal"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="e-faktur-%d-%02d.csv"`, tahun, bulan))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// ListPPh handles GET /api/v1/pajak/pph?tahun=2025&bulan=1&jenis=PPH23
+func (h *PajakHandler) ListPPh(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	var tahun, bulan int
+	var err error
+	if nilai := c.Query("tahun"); nilai != "" {
+		if tahun, err = strconv.Atoi(nilai); err != nil {
+			utils.BadRequestResponse(c, "Parameter tahun harus berupa angka")
+			return
+		}
+	}
+	if nilai := c.Query("bulan"); nilai != "" {
+		if bulan, err = strconv.Atoi(nilai); err != nil {
+			utils.BadRequestResponse(c, "Parameter bulan harus berupa angka")
+			return
+		}
+	}
+
+	daftar, err := h.pajakService.DapatkanSemuaPemotonganPPh(koperasiUUID, tahun, bulan, c.Query("jenis"))
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data pemotongan PPh berhasil diambil", daftar)
+}
+
+// CreatePPh handles POST /api/v1/pajak/pph
+func (h *PajakHandler) CreatePPh(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.BuatPemotonganPPhRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	pemotongan, err := h.pajakService.BuatPemotonganPPh(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Pemotongan PPh berhasil dicatat", pemotongan)
+}
+
+// BatalkanPPh handles POST /api/v1/pajak/pph/:id/pembatalan
+func (h *PajakHandler) BatalkanPPh(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID pemotongan PPh tidak valid")
+		return
+	}
+
+	var req services.BatalkanPemotonganPPhRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	pemotongan, err := h.pajakService.BatalkanPemotonganPPh(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pemotongan PPh berhasil dibatalkan", pemotongan)
+}
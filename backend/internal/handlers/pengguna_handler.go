@@ -34,7 +34,7 @@ func (h *PenggunaHandler) Create(c *gin.Context) {
 		return
 	}
 
-	pengguna, err := h.penggunaService.BuatPengguna(koperasiUUID, &req)
+	pengguna, err := h.penggunaService.DenganKonteks(c.Request.Context()).BuatPengguna(koperasiUUID, &req)
 	if err != nil {
 		if err.Error() == "nama pengguna sudah ada" {
 			utils.ConflictResponse(c, err.Error())
@@ -120,7 +120,7 @@ func (h *PenggunaHandler) Update(c *gin.Context) {
 		return
 	}
 
-	pengguna, err := h.penggunaService.PerbaruiPengguna(koperasiUUID, id, &req)
+	pengguna, err := h.penggunaService.DenganKonteks(c.Request.Context()).PerbaruiPengguna(koperasiUUID, id, &req)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -141,7 +141,7 @@ func (h *PenggunaHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.penggunaService.HapusPengguna(koperasiUUID, id); err != nil {
+	if err := h.penggunaService.DenganKonteks(c.Request.Context()).HapusPengguna(koperasiUUID, id); err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
@@ -162,7 +162,7 @@ func (h *PenggunaHandler) ResetPassword(c *gin.Context) {
 	}
 
 	// ResetKataSandi now returns the default password
-	passwordDefault, err := h.penggunaService.ResetKataSandi(koperasiUUID, id)
+	passwordDefault, err := h.penggunaService.DenganKonteks(c.Request.Context()).ResetKataSandi(koperasiUUID, id)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -195,7 +195,7 @@ func (h *PenggunaHandler) ChangePassword(c *gin.Context) {
 	}
 
 	// UbahKataSandiPengguna only takes the new password
-	if err := h.penggunaService.UbahKataSandiPengguna(koperasiUUID, id, req.KataSandiBaru); err != nil {
+	if err := h.penggunaService.DenganKonteks(c.Request.Context()).UbahKataSandiPengguna(koperasiUUID, id, req.KataSandiBaru); err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"cooperative-erp-lite/internal/services"
 	"cooperative-erp-lite/internal/utils"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -36,8 +37,12 @@ func (h *PenjualanHandler) ProsesPenjualan(c *gin.Context) {
 		return
 	}
 
-	penjualan, err := h.penjualanService.ProsesPenjualan(koperasiUUID, kasirUUID, &req)
+	penjualan, err := h.penjualanService.DenganKonteks(c.Request.Context()).ProsesPenjualan(koperasiUUID, kasirUUID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrPeriodeDitutup) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
@@ -95,6 +100,43 @@ func (h *PenjualanHandler) GetByID(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Data penjualan berhasil diambil", penjualan)
 }
 
+// Batalkan handles POST /api/v1/penjualan/:id/batal
+func (h *PenjualanHandler) Batalkan(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID penjualan tidak valid")
+		return
+	}
+
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.BatalkanPenjualanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	penjualan, err := h.penjualanService.DenganKonteks(c.Request.Context()).BatalkanPenjualan(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		if err.Error() == "penjualan tidak ditemukan" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Penjualan berhasil dibatalkan", penjualan)
+}
+
 // GetStruk handles GET /api/v1/penjualan/:id/struk
 func (h *PenjualanHandler) GetStruk(c *gin.Context) {
 	idStr := c.Param("id")
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PeriodeHandler menangani endpoint penutupan periode akuntansi
+type PeriodeHandler struct {
+	periodeService *services.PeriodeService
+}
+
+// NewPeriodeHandler membuat instance baru PeriodeHandler
+func NewPeriodeHandler(periodeService *services.PeriodeService) *PeriodeHandler {
+	return &PeriodeHandler{
+		periodeService: periodeService,
+	}
+}
+
+// List handles GET /api/v1/periode
+func (h *PeriodeHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	periodeList, err := h.periodeService.DapatkanSemuaPeriode(koperasiUUID)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Daftar periode yang ditutup berhasil diambil", periodeList)
+}
+
+// TutupBulan handles POST /api/v1/periode/tutup-bulan
+func (h *PeriodeHandler) TutupBulan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.TutupBulanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	periode, err := h.periodeService.TutupBulan(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Periode bulanan berhasil ditutup", periode)
+}
+
+// TutupTahunBuku handles POST /api/v1/periode/tutup-tahun
+func (h *PeriodeHandler) TutupTahunBuku(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.TutupTahunBukuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	periode, err := h.periodeService.TutupTahunBuku(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tahun buku berhasil ditutup", periode)
+}
+
+// BuatJurnalPenutupan handles POST /api/v1/periode/jurnal-penutupan
+// Membuat (ulang) jurnal penutupan tahun buku tanpa mengunci tahun buku
+func (h *PeriodeHandler) BuatJurnalPenutupan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.JurnalPenutupanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	jurnal, err := h.periodeService.BuatJurnalPenutupan(koperasiUUID, penggunaUUID, req.TahunBuku)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if jurnal == nil {
+		utils.SuccessResponse(c, http.StatusOK, "Tidak ada saldo pendapatan dan beban yang perlu ditutup", nil)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Jurnal penutupan berhasil dibuat", jurnal)
+}
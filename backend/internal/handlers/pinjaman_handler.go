@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PinjamanHandler menangani endpoint produk pinjaman dan pinjaman anggota
+type PinjamanHandler struct {
+	pinjamanService *services.PinjamanService
+}
+
+// NewPinjamanHandler membuat instance baru PinjamanHandler
+func NewPinjamanHandler(pinjamanService *services.PinjamanService) *PinjamanHandler {
+	return &PinjamanHandler{
+		pinjamanService: pinjamanService,
+	}
+}
+
+// CreateProduk handles POST /api/v1/pinjaman/produk
+func (h *PinjamanHandler) CreateProduk(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	var req services.BuatProdukPinjamanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	produk, err := h.pinjamanService.BuatProdukPinjaman(koperasiUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Produk pinjaman berhasil dibuat", produk)
+}
+
+// ListProduk handles GET /api/v1/pinjaman/produk
+func (h *PinjamanHandler) ListProduk(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	hanyaAktif := c.Query("aktif") == "true"
+
+	produkList, err := h.pinjamanService.DapatkanSemuaProdukPinjaman(koperasiUUID, hanyaAktif)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Daftar produk pinjaman berhasil diambil", produkList)
+}
+
+// UpdateProduk handles PUT /api/v1/pinjaman/produk/:id
+func (h *PinjamanHandler) UpdateProduk(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID produk pinjaman tidak valid")
+		return
+	}
+
+	var req services.BuatProdukPinjamanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	produk, err := h.pinjamanService.PerbaruiProdukPinjaman(id, koperasiUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Produk pinjaman berhasil diperbarui", produk)
+}
+
+// Simulasi handles GET /api/v1/pinjaman/simulasi
+// Query: idProdukPinjaman, jumlahPokok, tenorBulan, tanggalPencairan (opsional, YYYY-MM-DD)
+func (h *PinjamanHandler) Simulasi(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	idProduk, err := uuid.Parse(c.Query("idProdukPinjaman"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID produk pinjaman tidak valid")
+		return
+	}
+
+	jumlahPokok, err := models.ParseUang(c.Query("jumlahPokok"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Jumlah pokok tidak valid")
+		return
+	}
+
+	tenorBulan, err := strconv.Atoi(c.Query("tenorBulan"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tenor tidak valid")
+		return
+	}
+
+	tanggalPencairan := time.Now()
+	if tanggalStr := c.Query("tanggalPencairan"); tanggalStr != "" {
+		tanggalPencairan, err = time.Parse("2006-01-02", tanggalStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Format tanggal pencairan tidak valid (YYYY-MM-DD)")
+			return
+		}
+	}
+
+	jadwal, err := h.pinjamanService.SimulasiJadwalAngsuran(koperasiUUID, idProduk, jumlahPokok, tenorBulan, tanggalPencairan)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Simulasi jadwal angsuran berhasil dihitung", jadwal)
+}
+
+// Cairkan handles POST /api/v1/pinjaman
+func (h *PinjamanHandler) Cairkan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.CairkanPinjamanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	pinjaman, err := h.pinjamanService.CairkanPinjaman(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Pinjaman berhasil dicairkan", pinjaman)
+}
+
+// List handles GET /api/v1/pinjaman
+func (h *PinjamanHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	status := c.Query("status")
+
+	var idAnggotaPtr *uuid.UUID
+	if idAnggotaStr := c.Query("idAnggota"); idAnggotaStr != "" {
+		id, err := uuid.Parse(idAnggotaStr)
+		if err == nil {
+			idAnggotaPtr = &id
+		}
+	}
+
+	pinjamanList, total, err := h.pinjamanService.DapatkanSemuaPinjaman(koperasiUUID, status, idAnggotaPtr, page, pageSize)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	pagination := utils.CalculatePaginationMeta(page, pageSize, total)
+	utils.PaginatedSuccessResponse(c, http.StatusOK, "Data pinjaman berhasil diambil", pinjamanList, pagination)
+}
+
+// GetByID handles GET /api/v1/pinjaman/:id
+func (h *PinjamanHandler) GetByID(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID pinjaman tidak valid")
+		return
+	}
+
+	pinjaman, err := h.pinjamanService.DapatkanPinjaman(id, koperasiUUID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data pinjaman berhasil diambil", pinjaman)
+}
+
+// BayarAngsuran handles POST /api/v1/pinjaman/:id/angsuran
+func (h *PinjamanHandler) BayarAngsuran(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID pinjaman tidak valid")
+		return
+	}
+
+	var req services.BayarAngsuranRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	angsuran, err := h.pinjamanService.BayarAngsuran(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Angsuran berhasil dibayar", angsuran)
+}
+
+// BatalkanPencairan handles POST /api/v1/pinjaman/:id/batal
+func (h *PinjamanHandler) BatalkanPencairan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID pinjaman tidak valid")
+		return
+	}
+
+	var req services.BatalkanPinjamanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	pinjaman, err := h.pinjamanService.BatalkanPencairan(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pencairan pinjaman berhasil dibatalkan", pinjaman)
+}
+
+// BatalkanAngsuran handles POST /api/v1/pinjaman/:id/angsuran/batal
+func (h *PinjamanHandler) BatalkanAngsuran(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID pinjaman tidak valid")
+		return
+	}
+
+	var req services.BatalkanPinjamanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	angsuran, err := h.pinjamanService.BatalkanAngsuran(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pembayaran angsuran berhasil dibatalkan", angsuran)
+}
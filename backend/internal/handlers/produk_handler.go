@@ -33,7 +33,7 @@ func (h *ProdukHandler) Create(c *gin.Context) {
 		return
 	}
 
-	produk, err := h.produkService.BuatProduk(koperasiUUID, &req)
+	produk, err := h.produkService.DenganKonteks(c.Request.Context()).BuatProduk(koperasiUUID, &req)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -124,7 +124,7 @@ func (h *ProdukHandler) Update(c *gin.Context) {
 		return
 	}
 
-	produk, err := h.produkService.PerbaruiProduk(koperasiUUID, id, &req)
+	produk, err := h.produkService.DenganKonteks(c.Request.Context()).PerbaruiProduk(koperasiUUID, id, &req)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
@@ -147,7 +147,7 @@ func (h *ProdukHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.produkService.HapusProduk(koperasiUUID, id); err != nil {
+	if err := h.produkService.DenganKonteks(c.Request.Context()).HapusProduk(koperasiUUID, id); err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// formatRekeningKoranByEkstensi memetakan ekstensi berkas ke format rekening koran
+var formatRekeningKoranByEkstensi = map[string]models.FormatRekeningKoran{
+	".csv":   models.FormatRekeningKoranCSV,
+	".ofx":   models.FormatRekeningKoranOFX,
+	".qfx":   models.FormatRekeningKoranOFX,
+	".sta":   models.FormatRekeningKoranMT940,
+	".mt940": models.FormatRekeningKoranMT940,
+	".940":   models.FormatRekeningKoranMT940,
+	".txt":   models.FormatRekeningKoranMT940,
+}
+
+// RekonsiliasiBankHandler menangani endpoint impor rekening koran dan rekonsiliasi bank
+type RekonsiliasiBankHandler struct {
+	rekonsiliasiBankService *services.RekonsiliasiBankService
+}
+
+// NewRekonsiliasiBankHandler membuat instance baru RekonsiliasiBankHandler
+func NewRekonsiliasiBankHandler(rekonsiliasiBankService *services.RekonsiliasiBankService) *RekonsiliasiBankHandler {
+	return &RekonsiliasiBankHandler{
+		rekonsiliasiBankService: rekonsiliasiBankService,
+	}
+}
+
+// Impor handles POST /api/v1/rekonsiliasi-bank/rekening-koran (multipart/form-data)
+// Field "berkas" berisi rekening koran (.csv, .ofx/.qfx, atau .sta/.mt940/.940/.txt untuk MT940).
+// Field opsional "format" (CSV, OFX, MT940) menimpa format dari ekstensi, dan "kodeAkun"
+// memilih akun bank selain 1102.
+func (h *RekonsiliasiBankHandler) Impor(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	fileHeader, err := c.FormFile("berkas")
+	if err != nil {
+		utils.BadRequestResponse(c, "Berkas rekening koran wajib diunggah pada field berkas")
+		return
+	}
+	if fileHeader.Size > ukuranMaksBerkasImpor {
+		utils.BadRequestResponse(c, "Ukuran berkas impor maksimal 5 MB")
+		return
+	}
+
+	format := models.FormatRekeningKoran(strings.ToUpper(c.PostForm("format")))
+	if format == "" {
+		format = formatRekeningKoranByEkstensi[strings.ToLower(filepath.Ext(fileHeader.Filename))]
+	}
+	if format == "" {
+		utils.BadRequestResponse(c, "Format rekening koran tidak dikenali, gunakan berkas .csv, .ofx atau .sta (MT940)")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, "Berkas rekening koran tidak dapat dibaca")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, ukuranMaksBerkasImpor))
+	if err != nil {
+		utils.BadRequestResponse(c, "Berkas rekening koran tidak dapat dibaca")
+		return
+	}
+
+	hasil, err := h.rekonsiliasiBankService.ImporRekeningKoran(koperasiUUID, penggunaUUID, &services.ImporRekeningKoranRequest{
+		KodeAkun:   c.PostForm("kodeAkun"),
+		Format:     format,
+		NamaBerkas: filepath.Base(fileHeader.Filename),
+		Data:       data,
+	})
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Rekening koran berhasil diimpor", hasil)
+}
+
+// ListRekeningKoran handles GET /api/v1/rekonsiliasi-bank/rekening-koran?kodeAkun=1102
+func (h *RekonsiliasiBankHandler) ListRekeningKoran(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	rekeningKoranList, err := h.rekonsiliasiBankService.DapatkanRekeningKoran(koperasiUUID, c.Query("kodeAkun"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data rekening koran berhasil diambil", rekeningKoranList)
+}
+
+// HapusRekeningKoran handles DELETE /api/v1/rekonsiliasi-bank/rekening-koran/:id
+func (h *RekonsiliasiBankHandler) HapusRekeningKoran(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID rekening koran tidak valid")
+		return
+	}
+
+	if err := h.rekonsiliasiBankService.HapusRekeningKoran(id, koperasiUUID); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rekening koran berhasil dihapus", nil)
+}
+
+// ListMutasi handles GET /api/v1/rekonsiliasi-bank/mutasi
+// Query: kodeAkun, status (BELUM_COCOK, COCOK), idRekeningKoran, tanggalMulai, tanggalAkhir
+func (h *RekonsiliasiBankHandler) ListMutasi(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	filter := services.FilterMutasiBank{
+		KodeAkun:     c.Query("kodeAkun"),
+		Status:       models.StatusMutasiBank(c.Query("status")),
+		TanggalMulai: c.Query("tanggalMulai"),
+		TanggalAkhir: c.Query("tanggalAkhir"),
+	}
+	if idStr := c.Query("idRekeningKoran"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "ID rekening koran tidak valid")
+			return
+		}
+		filter.IDRekeningKoran = &id
+	}
+
+	mutasiList, err := h.rekonsiliasiBankService.DapatkanMutasiBank(koperasiUUID, filter)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data mutasi bank berhasil diambil", mutasiList)
+}
+
+// CocokkanOtomatis handles POST /api/v1/rekonsiliasi-bank/cocokkan-otomatis?kodeAkun=1102
+func (h *RekonsiliasiBankHandler) CocokkanOtomatis(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	jumlahCocok, err := h.rekonsiliasiBankService.CocokkanOtomatis(koperasiUUID, c.Query("kodeAkun"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pencocokan otomatis selesai", gin.H{"jumlahCocok": jumlahCocok})
+}
+
+// parseIDMutasi membaca parameter :id mutasi bank
+func parseIDMutasi(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID mutasi bank tidak valid")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// Cocokkan handles POST /api/v1/rekonsiliasi-bank/mutasi/:id/cocokkan
+func (h *RekonsiliasiBankHandler) Cocokkan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, ok := parseIDMutasi(c)
+	if !ok {
+		return
+	}
+
+	var req services.CocokkanMutasiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	mutasi, err := h.rekonsiliasiBankService.CocokkanManual(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Mutasi bank berhasil dicocokkan", mutasi)
+}
+
+// BatalkanCocok handles POST /api/v1/rekonsiliasi-bank/mutasi/:id/batal
+func (h *RekonsiliasiBankHandler) BatalkanCocok(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, ok := parseIDMutasi(c)
+	if !ok {
+		return
+	}
+
+	// Body opsional; alasan hanya wajib untuk mutasi yang jurnalnya dibuat dari rekening koran
+	var req services.BatalkanCocokRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err.Error())
+			return
+		}
+	}
+
+	mutasi, err := h.rekonsiliasiBankService.BatalkanCocok(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pencocokan mutasi bank berhasil dibatalkan", mutasi)
+}
+
+// BuatJurnal handles POST /api/v1/rekonsiliasi-bank/mutasi/:id/jurnal
+func (h *RekonsiliasiBankHandler) BuatJurnal(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, ok := parseIDMutasi(c)
+	if !ok {
+		return
+	}
+
+	var req services.BuatJurnalMutasiRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err.Error())
+			return
+		}
+	}
+
+	mutasi, err := h.rekonsiliasiBankService.BuatJurnalMutasi(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Jurnal mutasi bank berhasil dibuat", mutasi)
+}
+
+// Laporan handles GET /api/v1/rekonsiliasi-bank/laporan?kodeAkun=1102&tanggalPer=2026-01-31
+func (h *RekonsiliasiBankHandler) Laporan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	laporan, err := h.rekonsiliasiBankService.LaporanRekonsiliasiBank(koperasiUUID, c.Query("kodeAkun"), c.Query("tanggalPer"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Laporan rekonsiliasi bank berhasil diambil", laporan)
+}
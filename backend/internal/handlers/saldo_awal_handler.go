@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SaldoAwalHandler menangani endpoint migrasi saldo awal
+type SaldoAwalHandler struct {
+	saldoAwalService *services.SaldoAwalService
+}
+
+// NewSaldoAwalHandler membuat instance baru SaldoAwalHandler
+func NewSaldoAwalHandler(saldoAwalService *services.SaldoAwalService) *SaldoAwalHandler {
+	return &SaldoAwalHandler{
+		saldoAwalService: saldoAwalService,
+	}
+}
+
+// Impor handles POST /api/v1/saldo-awal
+func (h *SaldoAwalHandler) Impor(c *gin.Context) {
+	var req services.ImporSaldoAwalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	h.impor(c, &req)
+}
+
+// ImporBerkas handles POST /api/v1/saldo-awal/berkas?dryRun=true
+// (multipart: field "neraca", field opsional "simpanan", dan tanggalSaldo YYYY-MM-DD)
+func (h *SaldoAwalHandler) ImporBerkas(c *gin.Context) {
+	tanggalSaldo, err := time.Parse("2006-01-02", c.PostForm("tanggalSaldo"))
+	if err != nil {
+		utils.BadRequestResponse(c, "tanggalSaldo wajib diisi dengan format YYYY-MM-DD")
+		return
+	}
+
+	format, data, ok := bacaBerkasImpor(c, "neraca", "Berkas neraca saldo awal")
+	if !ok {
+		return
+	}
+	akun, err := services.BacaBerkasNeracaSaldoAwal(format, data)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	req := services.ImporSaldoAwalRequest{
+		TanggalSaldo: tanggalSaldo,
+		Akun:         akun,
+		DryRun:       c.Query("dryRun") == "true",
+	}
+
+	// Berkas simpanan anggota opsional, misalnya untuk koperasi tanpa simpanan
+	if _, err := c.FormFile("simpanan"); err == nil {
+		format, data, ok := bacaBerkasImpor(c, "simpanan", "Berkas simpanan saldo awal")
+		if !ok {
+			return
+		}
+		req.Simpanan, err = services.BacaBerkasSimpananSaldoAwal(format, data)
+		if err != nil {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
+	}
+
+	h.impor(c, &req)
+}
+
+// impor menjalankan impor saldo awal dan mengirim hasilnya
+func (h *SaldoAwalHandler) impor(c *gin.Context, req *services.ImporSaldoAwalRequest) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	hasil, err := h.saldoAwalService.ImporSaldoAwal(koperasiUUID, penggunaUUID, req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	if len(hasil.Kesalahan) > 0 {
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, "VALIDATION_ERROR", "Saldo awal berisi kesalahan, tidak ada data yang disimpan", hasil)
+		return
+	}
+
+	if req.DryRun {
+		utils.SuccessResponse(c, http.StatusOK, "Validasi saldo awal berhasil, belum ada data yang disimpan", hasil)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusCreated, "Saldo awal berhasil diimpor", hasil)
+}
+
+// Batalkan handles POST /api/v1/saldo-awal/batal
+func (h *SaldoAwalHandler) Batalkan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.BatalkanSaldoAwalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	pembalik, err := h.saldoAwalService.BatalkanSaldoAwal(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Saldo awal berhasil dibatalkan", pembalik)
+}
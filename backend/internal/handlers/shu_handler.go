@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SHUHandler menangani endpoint perhitungan dan pembagian SHU
+type SHUHandler struct {
+	shuService *services.SHUService
+}
+
+// NewSHUHandler membuat instance baru SHUHandler
+func NewSHUHandler(shuService *services.SHUService) *SHUHandler {
+	return &SHUHandler{
+		shuService: shuService,
+	}
+}
+
+// GetPengaturan handles GET /api/v1/shu/pengaturan
+func (h *SHUHandler) GetPengaturan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	pengaturan, err := h.shuService.DapatkanPengaturanSHU(koperasiUUID)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pengaturan SHU berhasil diambil", pengaturan)
+}
+
+// UpdatePengaturan handles PUT /api/v1/shu/pengaturan
+func (h *SHUHandler) UpdatePengaturan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	var req services.PengaturanSHURequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	pengaturan, err := h.shuService.SimpanPengaturanSHU(koperasiUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pengaturan SHU berhasil disimpan", pengaturan)
+}
+
+// Hitung handles GET /api/v1/shu/hitung?tahunBuku=2024
+// Mengembalikan simulasi pembagian SHU tanpa menyimpan atau memposting jurnal
+func (h *SHUHandler) Hitung(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Query("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Parameter tahunBuku wajib diisi dan harus berupa angka")
+		return
+	}
+
+	shu, err := h.shuService.HitungSHU(koperasiUUID, tahunBuku)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Simulasi SHU berhasil dihitung", shu)
+}
+
+// Tetapkan handles POST /api/v1/shu
+// Menyimpan hasil perhitungan SHU dan memposting jurnal pembagiannya
+func (h *SHUHandler) Tetapkan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.TetapkanSHURequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	shu, err := h.shuService.TetapkanSHU(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "SHU berhasil ditetapkan", shu)
+}
+
+// List handles GET /api/v1/shu
+func (h *SHUHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	shuList, err := h.shuService.DapatkanSemuaSHU(koperasiUUID)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Daftar SHU berhasil diambil", shuList)
+}
+
+// GetByTahunBuku handles GET /api/v1/shu/:tahunBuku
+func (h *SHUHandler) GetByTahunBuku(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Param("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tahun buku tidak valid")
+		return
+	}
+
+	shu, err := h.shuService.DapatkanSHU(koperasiUUID, tahunBuku)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "SHU berhasil diambil", shu)
+}
+
+// Batalkan handles POST /api/v1/shu/:tahunBuku/batal
+// Membalik jurnal pembagian SHU agar tahun buku dapat ditetapkan ulang
+func (h *SHUHandler) Batalkan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	tahunBuku, err := strconv.Atoi(c.Param("tahunBuku"))
+	if err != nil {
+		utils.BadRequestResponse(c, "Tahun buku tidak valid")
+		return
+	}
+
+	var req services.BatalkanSHURequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	if err := h.shuService.BatalkanSHU(koperasiUUID, penggunaUUID, tahunBuku, &req); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Penetapan SHU berhasil dibatalkan", nil)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"cooperative-erp-lite/internal/services"
 	"cooperative-erp-lite/internal/utils"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -46,8 +47,12 @@ func (h *SimpananHandler) CatatSetoran(c *gin.Context) {
 		return
 	}
 
-	simpanan, err := h.simpananService.CatatSetoran(koperasiUUID, penggunaUUID, &req)
+	simpanan, err := h.simpananService.DenganKonteks(c.Request.Context()).CatatSetoran(koperasiUUID, penggunaUUID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrPeriodeDitutup) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
 		utils.SafeInternalServerErrorResponse(c, err)
 		return
 	}
@@ -55,6 +60,70 @@ func (h *SimpananHandler) CatatSetoran(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusCreated, "Setoran simpanan berhasil dicatat", simpanan)
 }
 
+// CatatPenarikan handles POST /api/v1/simpanan/tarik
+func (h *SimpananHandler) CatatPenarikan(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.CatatPenarikanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	simpanan, err := h.simpananService.DenganKonteks(c.Request.Context()).CatatPenarikan(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Penarikan simpanan berhasil dicatat", simpanan)
+}
+
+// Batalkan handles POST /api/v1/simpanan/:id/batal
+func (h *SimpananHandler) Batalkan(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID simpanan tidak valid")
+		return
+	}
+
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.BatalkanSimpananRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	simpanan, err := h.simpananService.DenganKonteks(c.Request.Context()).BatalkanSimpanan(koperasiUUID, penggunaUUID, id, &req)
+	if err != nil {
+		if err.Error() == "simpanan tidak ditemukan" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Transaksi simpanan berhasil dibatalkan", simpanan)
+}
+
 // List handles GET /api/v1/simpanan
 func (h *SimpananHandler) List(c *gin.Context) {
 	koperasiUUID, ok := utils.GetKoperasiID(c)
@@ -139,3 +208,46 @@ func (h *SimpananHandler) GetLaporanSaldo(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Laporan saldo simpanan berhasil diambil", laporanSaldo)
 }
+
+// GetRekonsiliasi handles GET /api/v1/simpanan/rekonsiliasi?tanggal=YYYY-MM-DD
+func (h *SimpananHandler) GetRekonsiliasi(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	laporan, err := h.simpananService.RekonsiliasiSimpanan(koperasiUUID, c.Query("tanggal"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rekonsiliasi simpanan berhasil diambil", laporan)
+}
+
+// PerbaikiRekonsiliasi handles POST /api/v1/simpanan/rekonsiliasi/perbaiki
+func (h *SimpananHandler) PerbaikiRekonsiliasi(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.PerbaikiRekonsiliasiSimpananRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	hasil, err := h.simpananService.DenganKonteks(c.Request.Context()).PerbaikiRekonsiliasiSimpanan(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Perbaikan rekonsiliasi simpanan selesai", hasil)
+}
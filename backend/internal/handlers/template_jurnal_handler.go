@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TemplateJurnalHandler menangani endpoint template jurnal dan jurnal berulang
+type TemplateJurnalHandler struct {
+	templateJurnalService *services.TemplateJurnalService
+}
+
+// NewTemplateJurnalHandler membuat instance baru TemplateJurnalHandler
+func NewTemplateJurnalHandler(templateJurnalService *services.TemplateJurnalService) *TemplateJurnalHandler {
+	return &TemplateJurnalHandler{
+		templateJurnalService: templateJurnalService,
+	}
+}
+
+// Create handles POST /api/v1/template-jurnal
+func (h *TemplateJurnalHandler) Create(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.SimpanTemplateJurnalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	template, err := h.templateJurnalService.BuatTemplateJurnal(koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Template jurnal berhasil dibuat", template)
+}
+
+// List handles GET /api/v1/template-jurnal
+func (h *TemplateJurnalHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	templateList, err := h.templateJurnalService.DapatkanSemuaTemplateJurnal(koperasiUUID)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data template jurnal berhasil diambil", templateList)
+}
+
+// GetByID handles GET /api/v1/template-jurnal/:id
+func (h *TemplateJurnalHandler) GetByID(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID template jurnal tidak valid")
+		return
+	}
+
+	template, err := h.templateJurnalService.DapatkanTemplateJurnal(id, koperasiUUID)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data template jurnal berhasil diambil", template)
+}
+
+// Update handles PUT /api/v1/template-jurnal/:id
+func (h *TemplateJurnalHandler) Update(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID template jurnal tidak valid")
+		return
+	}
+
+	var req services.SimpanTemplateJurnalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	template, err := h.templateJurnalService.PerbaruiTemplateJurnal(id, koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Template jurnal berhasil diupdate", template)
+}
+
+// Delete handles DELETE /api/v1/template-jurnal/:id
+func (h *TemplateJurnalHandler) Delete(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID template jurnal tidak valid")
+		return
+	}
+
+	if err := h.templateJurnalService.HapusTemplateJurnal(id, koperasiUUID); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Template jurnal berhasil dihapus", nil)
+}
+
+// BuatJurnal handles POST /api/v1/template-jurnal/:id/jurnal
+func (h *TemplateJurnalHandler) BuatJurnal(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID template jurnal tidak valid")
+		return
+	}
+
+	var req services.GunakanTemplateJurnalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	transaksi, err := h.templateJurnalService.BuatJurnalDariTemplate(id, koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Jurnal berhasil dibuat dari template", transaksi)
+}
+
+// Pratinjau handles GET /api/v1/template-jurnal/pratinjau?sampai=2026-12-31
+// Default sampai tiga bulan ke depan.
+func (h *TemplateJurnalHandler) Pratinjau(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	sampai := time.Now().UTC().AddDate(0, 3, 0)
+	if sampaiStr := c.Query("sampai"); sampaiStr != "" {
+		var err error
+		sampai, err = time.Parse("2006-01-02", sampaiStr)
+		if err != nil {
+			utils.BadRequestResponse(c, "Format tanggal sampai tidak valid (gunakan YYYY-MM-DD)")
+			return
+		}
+	}
+
+	pratinjau, err := h.templateJurnalService.PratinjauJurnalBerulang(koperasiUUID, sampai)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pratinjau jurnal berulang berhasil diambil", pratinjau)
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/internal/services"
 	"cooperative-erp-lite/internal/utils"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -36,8 +38,12 @@ func (h *TransaksiHandler) Create(c *gin.Context) {
 		return
 	}
 
-	transaksi, err := h.transaksiService.BuatTransaksi(koperasiUUID, penggunaUUID, &req)
+	transaksi, err := h.transaksiService.DenganKonteks(c.Request.Context()).BuatTransaksi(koperasiUUID, penggunaUUID, &req)
 	if err != nil {
+		if errors.Is(err, services.ErrPeriodeDitutup) {
+			utils.BadRequestResponse(c, err.Error())
+			return
+		}
 		// Check jika error validasi double-entry
 		if err.Error() == "total debit harus sama dengan total kredit" ||
 		   err.Error() == "satu baris tidak boleh memiliki debit dan kredit sekaligus" {
@@ -61,9 +67,10 @@ func (h *TransaksiHandler) List(c *gin.Context) {
 	tanggalMulai := c.Query("tanggalMulai")
 	tanggalAkhir := c.Query("tanggalAkhir")
 	tipeTransaksi := c.Query("tipeTransaksi")
+	status := c.Query("status")
 
 	transaksiList, total, err := h.transaksiService.DapatkanSemuaTransaksi(
-		koperasiUUID, tanggalMulai, tanggalAkhir, tipeTransaksi, page, pageSize,
+		koperasiUUID, tanggalMulai, tanggalAkhir, tipeTransaksi, status, page, pageSize,
 	)
 	if err != nil {
 		utils.SafeInternalServerErrorResponse(c, err)
@@ -76,6 +83,11 @@ func (h *TransaksiHandler) List(c *gin.Context) {
 
 // GetByID handles GET /api/v1/transaksi/:id
 func (h *TransaksiHandler) GetByID(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -83,7 +95,7 @@ func (h *TransaksiHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	transaksi, err := h.transaksiService.DapatkanTransaksi(id)
+	transaksi, err := h.transaksiService.DapatkanTransaksi(koperasiUUID, id)
 	if err != nil {
 		utils.NotFoundResponse(c, "Transaksi tidak ditemukan")
 		return
@@ -117,7 +129,7 @@ func (h *TransaksiHandler) Update(c *gin.Context) {
 	}
 
 	// Call service to update transaction
-	transaksi, err := h.transaksiService.PerbaruiTransaksi(id, koperasiUUID, penggunaUUID, &req)
+	transaksi, err := h.transaksiService.DenganKonteks(c.Request.Context()).PerbaruiTransaksi(id, koperasiUUID, penggunaUUID, &req)
 	if err != nil {
 		// Handle specific error cases
 		if err.Error() == "transaksi tidak ditemukan" {
@@ -127,7 +139,9 @@ func (h *TransaksiHandler) Update(c *gin.Context) {
 
 		// Check if validation error
 		if err.Error() == "total debit harus sama dengan total kredit" ||
-			err.Error() == "satu baris tidak boleh memiliki debit dan kredit sekaligus" {
+			err.Error() == "satu baris tidak boleh memiliki debit dan kredit sekaligus" ||
+			errors.Is(err, services.ErrPeriodeDitutup) ||
+			errors.Is(err, services.ErrStatusJurnalTidakSesuai) {
 			utils.BadRequestResponse(c, err.Error())
 			return
 		}
@@ -141,6 +155,11 @@ func (h *TransaksiHandler) Update(c *gin.Context) {
 
 // Delete handles DELETE /api/v1/transaksi/:id
 func (h *TransaksiHandler) Delete(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -148,8 +167,14 @@ func (h *TransaksiHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.transaksiService.HapusTransaksi(id); err != nil {
-		if err.Error() == "transaksi sudah di-post, tidak dapat dihapus" {
+	if err := h.transaksiService.DenganKonteks(c.Request.Context()).HapusTransaksi(koperasiUUID, id); err != nil {
+		if err.Error() == "transaksi tidak ditemukan" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		if err.Error() == "transaksi sudah di-post, tidak dapat dihapus" ||
+			errors.Is(err, services.ErrPeriodeDitutup) ||
+			errors.Is(err, services.ErrStatusJurnalTidakSesuai) {
 			utils.BadRequestResponse(c, err.Error())
 			return
 		}
@@ -162,6 +187,96 @@ func (h *TransaksiHandler) Delete(c *gin.Context) {
 
 // Reverse handles POST /api/v1/transaksi/:id/reverse
 func (h *TransaksiHandler) Reverse(c *gin.Context) {
-	// TODO: ReverseTransaksi method not yet implemented in service
-	utils.BadRequestResponse(c, "Fitur reverse transaksi belum tersedia")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID transaksi tidak valid")
+		return
+	}
+
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	var req services.BalikTransaksiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	transaksi, err := h.transaksiService.DenganKonteks(c.Request.Context()).BalikTransaksi(id, koperasiUUID, penggunaUUID, &req)
+	if err != nil {
+		if err.Error() == "transaksi tidak ditemukan" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Transaksi berhasil dibalik", transaksi)
+}
+
+// Ajukan handles POST /api/v1/transaksi/:id/ajukan
+func (h *TransaksiHandler) Ajukan(c *gin.Context) {
+	h.ubahStatus(c, "Jurnal berhasil diajukan", h.transaksiService.DenganKonteks(c.Request.Context()).AjukanTransaksi)
+}
+
+// Setujui handles POST /api/v1/transaksi/:id/setujui
+func (h *TransaksiHandler) Setujui(c *gin.Context) {
+	h.ubahStatus(c, "Jurnal berhasil disetujui", h.transaksiService.DenganKonteks(c.Request.Context()).SetujuiTransaksi)
+}
+
+// Tolak handles POST /api/v1/transaksi/:id/tolak
+func (h *TransaksiHandler) Tolak(c *gin.Context) {
+	var req services.TolakTransaksiRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	h.ubahStatus(c, "Jurnal dikembalikan ke draft", func(id, idKoperasi, idPengguna uuid.UUID) (*models.TransaksiResponse, error) {
+		return h.transaksiService.DenganKonteks(c.Request.Context()).TolakTransaksi(id, idKoperasi, idPengguna, &req)
+	})
+}
+
+// Posting handles POST /api/v1/transaksi/:id/posting
+func (h *TransaksiHandler) Posting(c *gin.Context) {
+	h.ubahStatus(c, "Jurnal berhasil di-posting", h.transaksiService.DenganKonteks(c.Request.Context()).PostingTransaksi)
+}
+
+// ubahStatus menjalankan satu langkah alur persetujuan jurnal untuk jurnal pada parameter :id
+func (h *TransaksiHandler) ubahStatus(c *gin.Context, pesan string, aksi func(id, idKoperasi, idPengguna uuid.UUID) (*models.TransaksiResponse, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID transaksi tidak valid")
+		return
+	}
+
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	penggunaUUID, ok := utils.GetPenggunaID(c)
+	if !ok {
+		return // Error response already sent by GetPenggunaID
+	}
+
+	transaksi, err := aksi(id, koperasiUUID, penggunaUUID)
+	if err != nil {
+		if err.Error() == "transaksi tidak ditemukan" {
+			utils.NotFoundResponse(c, err.Error())
+			return
+		}
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, pesan, transaksi)
 }
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"cooperative-erp-lite/internal/services"
+	"cooperative-erp-lite/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UnitUsahaHandler menangani endpoint master unit usaha
+type UnitUsahaHandler struct {
+	unitUsahaService *services.UnitUsahaService
+}
+
+// NewUnitUsahaHandler membuat instance baru UnitUsahaHandler
+func NewUnitUsahaHandler(unitUsahaService *services.UnitUsahaService) *UnitUsahaHandler {
+	return &UnitUsahaHandler{
+		unitUsahaService: unitUsahaService,
+	}
+}
+
+// Create handles POST /api/v1/unit-usaha
+func (h *UnitUsahaHandler) Create(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	var req services.BuatUnitUsahaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	unit, err := h.unitUsahaService.BuatUnitUsaha(koperasiUUID, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Unit usaha berhasil dibuat", unit)
+}
+
+// List handles GET /api/v1/unit-usaha?statusAktif=true
+func (h *UnitUsahaHandler) List(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	var statusAktifPtr *bool
+	if statusAktif := c.Query("statusAktif"); statusAktif != "" {
+		aktif := statusAktif == "true"
+		statusAktifPtr = &aktif
+	}
+
+	unitList, err := h.unitUsahaService.DapatkanSemuaUnitUsaha(koperasiUUID, statusAktifPtr)
+	if err != nil {
+		utils.SafeInternalServerErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data unit usaha berhasil diambil", unitList)
+}
+
+// GetByID handles GET /api/v1/unit-usaha/:id
+func (h *UnitUsahaHandler) GetByID(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID unit usaha tidak valid")
+		return
+	}
+
+	unit, err := h.unitUsahaService.DapatkanUnitUsaha(koperasiUUID, id)
+	if err != nil {
+		utils.NotFoundResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Data unit usaha berhasil diambil", unit)
+}
+
+// Update handles PUT /api/v1/unit-usaha/:id
+func (h *UnitUsahaHandler) Update(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID unit usaha tidak valid")
+		return
+	}
+
+	var req services.PerbaruiUnitUsahaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err.Error())
+		return
+	}
+
+	unit, err := h.unitUsahaService.PerbaruiUnitUsaha(koperasiUUID, id, &req)
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unit usaha berhasil diupdate", unit)
+}
+
+// Delete handles DELETE /api/v1/unit-usaha/:id
+func (h *UnitUsahaHandler) Delete(c *gin.Context) {
+	koperasiUUID, ok := utils.GetKoperasiID(c)
+	if !ok {
+		return // Error response already sent by GetKoperasiID
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "ID unit usaha tidak valid")
+		return
+	}
+
+	if err := h.unitUsahaService.HapusUnitUsaha(koperasiUUID, id); err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unit usaha berhasil dihapus", nil)
+}
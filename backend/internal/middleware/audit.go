@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"cooperative-erp-lite/internal/audit"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID adalah header yang membawa ID request dari klien/proxy dan dikembalikan di response
+const HeaderRequestID = "X-Request-ID"
+
+// polaRequestID membatasi request ID dari klien agar aman disimpan di log audit
+var polaRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// AuditMiddleware menyiapkan informasi audit (pengguna, alamat IP, request ID) di context request.
+// Service yang menjalankan query dengan context ini mencatat informasi tersebut di log audit.
+//
+// IMPORTANT: Middleware ini harus dipasang setelah AuthMiddleware agar ID pengguna tersedia
+func AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idRequest := c.GetHeader(HeaderRequestID)
+		if !polaRequestID.MatchString(idRequest) {
+			idRequest = uuid.New().String()
+		}
+		c.Header(HeaderRequestID, idRequest)
+
+		info := audit.Info{
+			AlamatIP:  c.ClientIP(),
+			IDRequest: idRequest,
+		}
+		if idPengguna, ok := c.Get("idPengguna"); ok {
+			if id, ok := idPengguna.(uuid.UUID); ok {
+				info.IDPengguna = &id
+			}
+		}
+
+		c.Request = c.Request.WithContext(audit.DenganInfo(c.Request.Context(), info))
+		c.Next()
+	}
+}
@@ -18,6 +18,25 @@ const (
 	AkunBeban      TipeAkun = "BEBAN"      // Beban/Biaya
 )
 
+// KategoriArusKas mendefinisikan klasifikasi akun dalam laporan arus kas
+type KategoriArusKas string
+
+const (
+	ArusKasKas       KategoriArusKas = "KAS"       // Kas dan setara kas
+	ArusKasOperasi   KategoriArusKas = "OPERASI"   // Aktivitas operasi
+	ArusKasInvestasi KategoriArusKas = "INVESTASI" // Aktivitas investasi
+	ArusKasPendanaan KategoriArusKas = "PENDANAAN" // Aktivitas pendanaan
+)
+
+// IsValid memeriksa apakah kategori arus kas dikenal
+func (k KategoriArusKas) IsValid() bool {
+	switch k {
+	case ArusKasKas, ArusKasOperasi, ArusKasInvestasi, ArusKasPendanaan:
+		return true
+	}
+	return false
+}
+
 // Akun merepresentasikan Chart of Accounts (Bagan Akun)
 type Akun struct {
 	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
@@ -29,6 +48,7 @@ type Akun struct {
 	NormalSaldo       string         `gorm:"type:varchar(6);not null" json:"normalSaldo" validate:"oneof=DEBIT KREDIT"` // DEBIT atau KREDIT
 	Deskripsi         string         `gorm:"type:text" json:"deskripsi"`
 	StatusAktif       bool           `gorm:"type:boolean;default:true" json:"statusAktif"`
+	KategoriArusKas   KategoriArusKas `gorm:"type:varchar(20)" json:"kategoriArusKas"` // Kosong: ditentukan dari kode dan tipe akun
 	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
 	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
 	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -59,6 +79,24 @@ func (a *Akun) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// KategoriArusKasEfektif mengembalikan klasifikasi arus kas akun.
+// Jika belum diatur, Kas (1101) dan Bank (1102) dianggap kas dan setara kas,
+// akun modal sebagai pendanaan, dan akun lainnya sebagai aktivitas operasi.
+func (a *Akun) KategoriArusKasEfektif() KategoriArusKas {
+	if a.KategoriArusKas != "" {
+		return a.KategoriArusKas
+	}
+
+	switch {
+	case a.KodeAkun == "1101" || a.KodeAkun == "1102":
+		return ArusKasKas
+	case a.TipeAkun == AkunModal:
+		return ArusKasPendanaan
+	default:
+		return ArusKasOperasi
+	}
+}
+
 // TableName menentukan nama tabel di database
 func (Akun) TableName() string {
 	return "akun"
@@ -75,7 +113,8 @@ type AkunResponse struct {
 	NormalSaldo string    `json:"normalSaldo"`
 	Deskripsi   string    `json:"deskripsi"`
 	StatusAktif bool      `json:"statusAktif"`
-	Saldo       float64   `json:"saldo,omitempty"` // Computed field
+	KategoriArusKas KategoriArusKas `json:"kategoriArusKas"`
+	Saldo       Uang      `json:"saldo,omitempty"` // Computed field
 }
 
 // ToResponse mengkonversi Akun ke AkunResponse
@@ -89,6 +128,7 @@ func (a *Akun) ToResponse() AkunResponse {
 		NormalSaldo: a.NormalSaldo,
 		Deskripsi:   a.Deskripsi,
 		StatusAktif: a.StatusAktif,
+		KategoriArusKas: a.KategoriArusKasEfektif(),
 	}
 
 	// Populate nama induk jika relasi sudah di-load
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StatusAnggaran adalah status Rencana Anggaran Pendapatan dan Belanja (RAPB)
+type StatusAnggaran string
+
+const (
+	StatusAnggaranDraft    StatusAnggaran = "DRAFT"    // Masih dapat diubah
+	StatusAnggaranDisahkan StatusAnggaran = "DISAHKAN" // Sudah disahkan dalam RAT, tidak dapat diubah
+)
+
+// Anggaran merepresentasikan RAPB koperasi untuk satu tahun buku
+type Anggaran struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi      uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_tahun_buku_anggaran" json:"idKoperasi" validate:"required"`
+	TahunBuku       int            `gorm:"type:int;not null;uniqueIndex:idx_koperasi_tahun_buku_anggaran" json:"tahunBuku" validate:"required"`
+	Keterangan      string         `gorm:"type:text" json:"keterangan"`
+	Status          StatusAnggaran `gorm:"type:varchar(20);not null;default:'DRAFT'" json:"status"`
+	TanggalDisahkan *time.Time     `gorm:"type:date" json:"tanggalDisahkan"` // Tanggal RAT yang mengesahkan RAPB
+	DisahkanOleh    *uuid.UUID     `gorm:"type:uuid" json:"disahkanOleh"`
+	DibuatOleh      uuid.UUID      `gorm:"type:uuid" json:"dibuatOleh"`
+	DiperbaruiOleh  uuid.UUID      `gorm:"type:uuid" json:"diperbaruiOleh"`
+
+	TanggalDibuat     time.Time `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+
+	// Relasi
+	Koperasi      Koperasi        `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	BarisAnggaran []BarisAnggaran `gorm:"foreignKey:IDAnggaran;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (a *Anggaran) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.Status == "" {
+		a.Status = StatusAnggaranDraft
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (Anggaran) TableName() string {
+	return "anggaran"
+}
+
+// BarisAnggaran adalah anggaran satu akun pendapatan atau beban untuk satu bulan tahun buku
+type BarisAnggaran struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	IDAnggaran uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_anggaran_akun_bulan" json:"idAnggaran"`
+	IDAkun     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_anggaran_akun_bulan;index" json:"idAkun"`
+	BulanKe    int       `gorm:"type:int;not null;uniqueIndex:idx_anggaran_akun_bulan" json:"bulanKe"` // 1 = bulan pertama tahun buku
+	Jumlah     Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"jumlah"`
+
+	// Relasi
+	Akun Akun `gorm:"foreignKey:IDAkun" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (b *BarisAnggaran) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (BarisAnggaran) TableName() string {
+	return "baris_anggaran"
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// KelompokAsetTetap mendefinisikan kelompok aset tetap; kelompok menentukan akun COA default
+type KelompokAsetTetap string
+
+const (
+	KelompokAsetTanah     KelompokAsetTetap = "TANAH"     // Tidak disusutkan
+	KelompokAsetBangunan  KelompokAsetTetap = "BANGUNAN"  // Gedung dan bangunan
+	KelompokAsetKendaraan KelompokAsetTetap = "KENDARAAN" // Kendaraan bermotor
+	KelompokAsetPeralatan KelompokAsetTetap = "PERALATAN" // Peralatan dan inventaris kantor
+)
+
+// MetodePenyusutan mendefinisikan metode penyusutan aset tetap
+type MetodePenyusutan string
+
+const (
+	PenyusutanGarisLurus   MetodePenyusutan = "GARIS_LURUS"   // Beban penyusutan sama setiap bulan
+	PenyusutanSaldoMenurun MetodePenyusutan = "SALDO_MENURUN" // Saldo menurun ganda dari nilai buku
+)
+
+// StatusAsetTetap mendefinisikan status aset tetap
+type StatusAsetTetap string
+
+const (
+	StatusAsetAktif   StatusAsetTetap = "AKTIF"   // Masih dimiliki dan disusutkan
+	StatusAsetDilepas StatusAsetTetap = "DILEPAS" // Sudah dijual atau dihapusbukukan
+	StatusAsetBatal   StatusAsetTetap = "BATAL"   // Registrasi dibatalkan, jurnal perolehan sudah dibalik
+)
+
+// AsetTetap merepresentasikan aset tetap koperasi (tanah, bangunan, kendaraan, peralatan)
+type AsetTetap struct {
+	ID                   uuid.UUID         `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi           uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_kode_aset" json:"idKoperasi" validate:"required"`
+	KodeAset             string            `gorm:"type:varchar(30);not null;uniqueIndex:idx_koperasi_kode_aset" json:"kodeAset" validate:"required"`
+	NamaAset             string            `gorm:"type:varchar(255);not null" json:"namaAset" validate:"required"`
+	Kelompok             KelompokAsetTetap `gorm:"type:varchar(20);not null" json:"kelompok" validate:"required,oneof=TANAH BANGUNAN KENDARAAN PERALATAN"`
+	Deskripsi            string            `gorm:"type:text" json:"deskripsi"`
+	TanggalPerolehan     time.Time         `gorm:"type:date;not null" json:"tanggalPerolehan" validate:"required"`
+	HargaPerolehan       Uang              `gorm:"type:decimal(15,2);not null" json:"hargaPerolehan" validate:"required,gt=0"`
+	NilaiResidu          Uang              `gorm:"type:decimal(15,2);not null;default:0" json:"nilaiResidu"`
+	UmurEkonomisBulan    int               `gorm:"type:int;not null;default:0" json:"umurEkonomisBulan"` // 0 untuk aset yang tidak disusutkan
+	MetodePenyusutan     MetodePenyusutan  `gorm:"type:varchar(20);not null" json:"metodePenyusutan"`
+	AkumulasiPenyusutan  Uang              `gorm:"type:decimal(15,2);not null;default:0" json:"akumulasiPenyusutan"`
+	IDAkunAset           uuid.UUID         `gorm:"type:uuid;not null" json:"idAkunAset"`
+	IDAkunAkumulasi      *uuid.UUID        `gorm:"type:uuid" json:"idAkunAkumulasi"` // Kosong untuk aset yang tidak disusutkan
+	IDAkunBeban          *uuid.UUID        `gorm:"type:uuid" json:"idAkunBeban"`
+	IDUnitUsaha          *uuid.UUID        `gorm:"type:uuid;index" json:"idUnitUsaha"` // Unit usaha yang menanggung beban penyusutan
+	Status               StatusAsetTetap   `gorm:"type:varchar(20);not null;default:'AKTIF';index" json:"status"`
+	IDTransaksiPerolehan *uuid.UUID        `gorm:"type:uuid" json:"idTransaksiPerolehan"` // Kosong jika saldo aset sudah ada di saldo awal
+	TanggalPelepasan     *time.Time        `gorm:"type:date" json:"tanggalPelepasan"`
+	NilaiPelepasan       Uang              `gorm:"type:decimal(15,2);not null;default:0" json:"nilaiPelepasan"`
+	IDTransaksiPelepasan *uuid.UUID        `gorm:"type:uuid" json:"idTransaksiPelepasan"`
+	TanggalDibatalkan    *time.Time        `json:"tanggalDibatalkan"`
+	DibatalkanOleh       *uuid.UUID        `gorm:"type:uuid" json:"dibatalkanOleh"`
+	AlasanPembatalan     string            `gorm:"type:text" json:"alasanPembatalan"`
+	DibuatOleh           uuid.UUID         `gorm:"type:uuid" json:"dibuatOleh"`
+	TanggalDibuat        time.Time         `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui    time.Time         `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus       gorm.DeletedAt    `gorm:"index" json:"-"`
+
+	// Relasi
+	Koperasi   Koperasi              `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	Penyusutan []PenyusutanAsetTetap `gorm:"foreignKey:IDAsetTetap" json:"penyusutan,omitempty"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (a *AsetTetap) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (AsetTetap) TableName() string {
+	return "aset_tetap"
+}
+
+// NilaiBuku mengembalikan harga perolehan dikurangi akumulasi penyusutan
+func (a *AsetTetap) NilaiBuku() Uang {
+	return a.HargaPerolehan - a.AkumulasiPenyusutan
+}
+
+// PenyusutanAsetTetap mencatat penyusutan satu aset untuk satu bulan.
+// Unique index per aset dan bulan mencegah penyusutan ganda saat posting bulanan diulang.
+type PenyusutanAsetTetap struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi     uuid.UUID `gorm:"type:uuid;not null;index" json:"idKoperasi"`
+	IDAsetTetap    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_aset_periode_penyusutan" json:"idAsetTetap"`
+	Tahun          int       `gorm:"type:int;not null;uniqueIndex:idx_aset_periode_penyusutan" json:"tahun"`
+	Bulan          int       `gorm:"type:int;not null;uniqueIndex:idx_aset_periode_penyusutan" json:"bulan"`
+	Jumlah         Uang      `gorm:"type:decimal(15,2);not null" json:"jumlah"`
+	NilaiBukuAkhir Uang      `gorm:"type:decimal(15,2);not null" json:"nilaiBukuAkhir"`
+	IDTransaksi    uuid.UUID `gorm:"type:uuid;not null;index" json:"idTransaksi"`
+	TanggalDibuat  time.Time `gorm:"autoCreateTime" json:"tanggalDibuat"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *PenyusutanAsetTetap) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (PenyusutanAsetTetap) TableName() string {
+	return "penyusutan_aset_tetap"
+}
+
+// AsetTetapResponse adalah response untuk API
+type AsetTetapResponse struct {
+	ID                   uuid.UUID             `json:"id"`
+	KodeAset             string                `json:"kodeAset"`
+	NamaAset             string                `json:"namaAset"`
+	Kelompok             KelompokAsetTetap     `json:"kelompok"`
+	Deskripsi            string                `json:"deskripsi"`
+	TanggalPerolehan     time.Time             `json:"tanggalPerolehan"`
+	HargaPerolehan       Uang                  `json:"hargaPerolehan"`
+	NilaiResidu          Uang                  `json:"nilaiResidu"`
+	UmurEkonomisBulan    int                   `json:"umurEkonomisBulan"`
+	MetodePenyusutan     MetodePenyusutan      `json:"metodePenyusutan"`
+	AkumulasiPenyusutan  Uang                  `json:"akumulasiPenyusutan"`
+	NilaiBuku            Uang                  `json:"nilaiBuku"`
+	IDAkunAset           uuid.UUID             `json:"idAkunAset"`
+	IDAkunAkumulasi      *uuid.UUID            `json:"idAkunAkumulasi"`
+	IDAkunBeban          *uuid.UUID            `json:"idAkunBeban"`
+	IDUnitUsaha          *uuid.UUID            `json:"idUnitUsaha,omitempty"`
+	Status               StatusAsetTetap       `json:"status"`
+	IDTransaksiPerolehan *uuid.UUID            `json:"idTransaksiPerolehan"`
+	TanggalPelepasan     *time.Time            `json:"tanggalPelepasan"`
+	NilaiPelepasan       Uang                  `json:"nilaiPelepasan"`
+	IDTransaksiPelepasan *uuid.UUID            `json:"idTransaksiPelepasan"`
+	AlasanPembatalan     string                `json:"alasanPembatalan,omitempty"`
+	Penyusutan           []PenyusutanAsetTetap `json:"penyusutan,omitempty"`
+}
+
+// ToResponse mengkonversi AsetTetap ke AsetTetapResponse
+func (a *AsetTetap) ToResponse() AsetTetapResponse {
+	return AsetTetapResponse{
+		ID:                   a.ID,
+		KodeAset:             a.KodeAset,
+		NamaAset:             a.NamaAset,
+		Kelompok:             a.Kelompok,
+		Deskripsi:            a.Deskripsi,
+		TanggalPerolehan:     a.TanggalPerolehan,
+		HargaPerolehan:       a.HargaPerolehan,
+		NilaiResidu:          a.NilaiResidu,
+		UmurEkonomisBulan:    a.UmurEkonomisBulan,
+		MetodePenyusutan:     a.MetodePenyusutan,
+		AkumulasiPenyusutan:  a.AkumulasiPenyusutan,
+		NilaiBuku:            a.NilaiBuku(),
+		IDAkunAset:           a.IDAkunAset,
+		IDAkunAkumulasi:      a.IDAkunAkumulasi,
+		IDAkunBeban:          a.IDAkunBeban,
+		IDUnitUsaha:          a.IDUnitUsaha,
+		Status:               a.Status,
+		IDTransaksiPerolehan: a.IDTransaksiPerolehan,
+		TanggalPelepasan:     a.TanggalPelepasan,
+		NilaiPelepasan:       a.NilaiPelepasan,
+		IDTransaksiPelepasan: a.IDTransaksiPelepasan,
+		AlasanPembatalan:     a.AlasanPembatalan,
+		Penyusutan:           a.Penyusutan,
+	}
+}
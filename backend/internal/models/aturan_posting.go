@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JenisPeristiwa mendefinisikan peristiwa bisnis yang dijurnal otomatis
+type JenisPeristiwa string
+
+const (
+	PeristiwaPenjualan         JenisPeristiwa = "PENJUALAN"          // Penjualan tunai POS
+	PeristiwaSimpananPokok     JenisPeristiwa = "SIMPANAN_POKOK"     // Setoran/penarikan simpanan pokok
+	PeristiwaSimpananWajib     JenisPeristiwa = "SIMPANAN_WAJIB"     // Setoran/penarikan simpanan wajib
+	PeristiwaSimpananSukarela  JenisPeristiwa = "SIMPANAN_SUKARELA"  // Setoran/penarikan simpanan sukarela
+	PeristiwaPencairanPinjaman JenisPeristiwa = "PENCAIRAN_PINJAMAN" // Pencairan pinjaman anggota
+	PeristiwaAngsuranPinjaman  JenisPeristiwa = "ANGSURAN_PINJAMAN"  // Pembayaran angsuran pinjaman
+	PeristiwaPembagianSHU      JenisPeristiwa = "PEMBAGIAN_SHU"      // Pembagian SHU tahun buku
+	PeristiwaPenutupanTahun    JenisPeristiwa = "PENUTUPAN_TAHUN"    // Jurnal penutupan tahun buku
+	PeristiwaPelepasanAset     JenisPeristiwa = "PELEPASAN_ASET"     // Penjualan atau penghapusan aset tetap
+	PeristiwaMutasiBank        JenisPeristiwa = "MUTASI_BANK"        // Biaya administrasi dan jasa giro dari rekening koran
+	PeristiwaPPNKeluaran       JenisPeristiwa = "PPN_KELUARAN"       // PPN atas penjualan barang kena pajak
+	PeristiwaPemotonganPPh21   JenisPeristiwa = "PEMOTONGAN_PPH21"   // PPh 21 yang dipotong dari pembayaran beban
+	PeristiwaPemotonganPPh23   JenisPeristiwa = "PEMOTONGAN_PPH23"   // PPh 23 yang dipotong dari pembayaran beban
+)
+
+// PeranAkun mendefinisikan peran akun di dalam jurnal otomatis suatu peristiwa
+type PeranAkun string
+
+const (
+	PeranAkunKas              PeranAkun = "KAS"
+	PeranAkunPendapatan       PeranAkun = "PENDAPATAN"
+	PeranAkunHPP              PeranAkun = "HPP"
+	PeranAkunPersediaan       PeranAkun = "PERSEDIAAN"
+	PeranAkunSimpanan         PeranAkun = "SIMPANAN"
+	PeranAkunPiutangPinjaman  PeranAkun = "PIUTANG_PINJAMAN"
+	PeranAkunPendapatanJasa   PeranAkun = "PENDAPATAN_JASA"
+	PeranAkunSHUTahunBerjalan PeranAkun = "SHU_TAHUN_BERJALAN"
+	PeranAkunDanaCadangan     PeranAkun = "DANA_CADANGAN"
+	PeranAkunSHUBagianAnggota PeranAkun = "SHU_BAGIAN_ANGGOTA"
+	PeranAkunDanaPengurus     PeranAkun = "DANA_PENGURUS"
+	PeranAkunDanaPendidikan   PeranAkun = "DANA_PENDIDIKAN"
+	PeranAkunDanaSosial       PeranAkun = "DANA_SOSIAL"
+	PeranAkunLabaPelepasan    PeranAkun = "LABA_PELEPASAN"
+	PeranAkunRugiPelepasan    PeranAkun = "RUGI_PELEPASAN"
+	PeranAkunBiayaBank        PeranAkun = "BIAYA_BANK"
+	PeranAkunJasaGiro         PeranAkun = "JASA_GIRO"
+	PeranAkunPPNKeluaran      PeranAkun = "PPN_KELUARAN"
+	PeranAkunUtangPPh         PeranAkun = "UTANG_PPH"
+)
+
+// AturanPosting memetakan peran akun suatu peristiwa ke akun di bagan akun koperasi.
+// Peran yang belum dipetakan memakai kode akun bawaan dari COA default.
+type AturanPosting struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_peristiwa_peran" json:"idKoperasi" validate:"required"`
+	JenisPeristiwa    JenisPeristiwa `gorm:"type:varchar(30);not null;uniqueIndex:idx_koperasi_peristiwa_peran" json:"jenisPeristiwa" validate:"required"`
+	PeranAkun         PeranAkun      `gorm:"type:varchar(30);not null;uniqueIndex:idx_koperasi_peristiwa_peran" json:"peranAkun" validate:"required"`
+	IDAkun            uuid.UUID      `gorm:"type:uuid;not null;index" json:"idAkun" validate:"required"`
+	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	Akun     Akun     `gorm:"foreignKey:IDAkun" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (a *AturanPosting) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (AturanPosting) TableName() string {
+	return "aturan_posting"
+}
@@ -7,6 +7,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// JenisKoperasi mendefinisikan jenis usaha koperasi, dipakai untuk memilih template COA
+type JenisKoperasi string
+
+const (
+	JenisKoperasiSimpanPinjam JenisKoperasi = "KSP"      // Koperasi Simpan Pinjam
+	JenisKoperasiSerbaUsaha   JenisKoperasi = "KSU"      // Koperasi Serba Usaha
+	JenisKoperasiKonsumen     JenisKoperasi = "KONSUMEN" // Koperasi Konsumen
+	JenisKoperasiSyariah      JenisKoperasi = "KSPPS"    // Koperasi Simpan Pinjam dan Pembiayaan Syariah
+)
+
 // Koperasi merepresentasikan entitas koperasi dalam sistem
 // Setiap koperasi adalah tenant terpisah dalam multi-tenant architecture
 type Koperasi struct {
@@ -18,6 +28,10 @@ type Koperasi struct {
 	LogoURL           string         `gorm:"type:varchar(500)" json:"logoUrl"`
 	TahunBukuMulai    int            `gorm:"type:int;default:1" json:"tahunBukuMulai" validate:"min=1,max=12"` // Bulan mulai tahun buku (1-12)
 	Pengaturan        string         `gorm:"type:jsonb" json:"pengaturan"`                                     // JSON untuk pengaturan koperasi
+	NPWP              string         `gorm:"type:varchar(16)" json:"npwp"`
+	PKP               bool           `gorm:"type:boolean;not null;default:false" json:"pkp"`              // Pengusaha Kena Pajak: memungut PPN atas penjualan
+	TarifPPN          float64        `gorm:"type:decimal(5,2);not null;default:11" json:"tarifPPN"`       // Persen; 11 = tarif efektif PPN barang non-mewah
+	HargaTermasukPPN  bool           `gorm:"type:boolean;not null;default:false" json:"hargaTermasukPPN"` // Harga jual produk sudah termasuk PPN
 	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
 	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
 	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete
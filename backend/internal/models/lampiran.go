@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JenisDokumenLampiran mendefinisikan dokumen yang dapat diberi lampiran
+type JenisDokumenLampiran string
+
+const (
+	DokumenTransaksi JenisDokumenLampiran = "TRANSAKSI" // Bukti jurnal (nota, kuitansi, faktur)
+	DokumenSimpanan  JenisDokumenLampiran = "SIMPANAN"  // Bukti setoran atau penarikan simpanan
+	DokumenPenjualan JenisDokumenLampiran = "PENJUALAN" // Bukti penjualan
+	DokumenAnggota   JenisDokumenLampiran = "ANGGOTA"   // Dokumen anggota seperti scan KTP
+)
+
+// IsValid memeriksa apakah jenis dokumen dikenal
+func (j JenisDokumenLampiran) IsValid() bool {
+	switch j {
+	case DokumenTransaksi, DokumenSimpanan, DokumenPenjualan, DokumenAnggota:
+		return true
+	}
+	return false
+}
+
+// Lampiran merepresentasikan berkas bukti yang dilampirkan pada dokumen.
+// Isi berkas disimpan di penyimpanan berkas dengan kunci KunciPenyimpanan; tabel ini
+// hanya menyimpan metadata. Lampiran yang dihapus hanya di-soft delete dan berkasnya tetap
+// disimpan agar jejak bukti untuk auditor tidak hilang.
+type Lampiran struct {
+	ID               uuid.UUID            `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi       uuid.UUID            `gorm:"type:uuid;not null;index:idx_lampiran_dokumen" json:"idKoperasi" validate:"required"`
+	JenisDokumen     JenisDokumenLampiran `gorm:"type:varchar(20);not null;index:idx_lampiran_dokumen" json:"jenisDokumen" validate:"required"`
+	IDDokumen        uuid.UUID            `gorm:"type:uuid;not null;index:idx_lampiran_dokumen" json:"idDokumen" validate:"required"`
+	NamaBerkas       string               `gorm:"type:varchar(255);not null" json:"namaBerkas"`
+	TipeKonten       string               `gorm:"type:varchar(100);not null" json:"tipeKonten"`
+	Ukuran           int64                `gorm:"type:bigint;not null" json:"ukuran"`
+	ChecksumSHA256   string               `gorm:"type:varchar(64);not null" json:"checksumSha256"`
+	KunciPenyimpanan string               `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"`
+	Keterangan       string               `gorm:"type:text" json:"keterangan"`
+	DiunggahOleh     uuid.UUID            `gorm:"type:uuid;not null" json:"diunggahOleh"`
+	DihapusOleh      *uuid.UUID           `gorm:"type:uuid" json:"-"`
+	TanggalDibuat    time.Time            `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDihapus   gorm.DeletedAt       `gorm:"index" json:"-"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (l *Lampiran) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (Lampiran) TableName() string {
+	return "lampiran"
+}
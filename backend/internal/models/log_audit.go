@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AksiAudit mendefinisikan jenis perubahan data yang dicatat di log audit
+type AksiAudit string
+
+const (
+	AksiAuditBuat  AksiAudit = "CREATE"
+	AksiAuditUbah  AksiAudit = "UPDATE"
+	AksiAuditHapus AksiAudit = "DELETE"
+)
+
+// LogAudit merepresentasikan satu perubahan data yang tidak boleh diubah atau dihapus (append-only).
+// Entri ditulis dalam transaksi yang sama dengan perubahannya, lalu disegel: diberi Urutan per
+// koperasi dan Hash yang merangkai HashSebelumnya, sehingga perubahan, penghapusan atau penyisipan
+// entri yang sudah disegel terdeteksi saat verifikasi.
+// DataSebelum dan DataSesudah disimpan sebagai teks JSON apa adanya agar byte yang di-hash tidak
+// berubah oleh normalisasi jsonb.
+type LogAudit struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Nomor          int64      `gorm:"autoIncrement;uniqueIndex;not null" json:"-"` // Urutan penulisan global, dipakai saat penyegelan
+	IDKoperasi     uuid.UUID  `gorm:"type:uuid;not null;index:idx_log_audit_entitas;uniqueIndex:idx_log_audit_urutan" json:"idKoperasi"`
+	Urutan         *int64     `gorm:"uniqueIndex:idx_log_audit_urutan" json:"urutan"` // Nil sampai entri disegel
+	Entitas        string     `gorm:"type:varchar(50);not null;index:idx_log_audit_entitas" json:"entitas"`
+	IDEntitas      uuid.UUID  `gorm:"type:uuid;not null;index:idx_log_audit_entitas" json:"idEntitas"`
+	Aksi           AksiAudit  `gorm:"type:varchar(10);not null" json:"aksi"`
+	DataSebelum    string     `gorm:"type:text" json:"dataSebelum,omitempty"`
+	DataSesudah    string     `gorm:"type:text" json:"dataSesudah,omitempty"`
+	IDPengguna     *uuid.UUID `gorm:"type:uuid" json:"idPengguna"`
+	AlamatIP       string     `gorm:"type:varchar(45)" json:"alamatIp,omitempty"`
+	IDRequest      string     `gorm:"type:varchar(64)" json:"idRequest,omitempty"`
+	Waktu          time.Time  `gorm:"not null;index" json:"waktu"`
+	HashSebelumnya string     `gorm:"type:varchar(64)" json:"hashSebelumnya,omitempty"`
+	Hash           *string    `gorm:"type:varchar(64)" json:"hash"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (l *LogAudit) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (LogAudit) TableName() string {
+	return "log_audit"
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// KodePajak mendefinisikan perlakuan PPN atas penjualan suatu produk
+type KodePajak string
+
+const (
+	KodePajakNonPPN KodePajak = "NON_PPN" // Tidak dipungut PPN (bukan barang kena pajak atau koperasi belum PKP)
+	KodePajakPPN    KodePajak = "PPN"     // Barang kena pajak dengan tarif PPN koperasi
+)
+
+// JenisPPh mendefinisikan jenis PPh yang dipotong koperasi atas pembayaran beban
+type JenisPPh string
+
+const (
+	PPh21 JenisPPh = "PPH21" // Honorarium, upah dan imbalan kepada orang pribadi
+	PPh23 JenisPPh = "PPH23" // Sewa dan jasa kepada badan atau orang pribadi
+)
+
+// PemotonganPPh mencatat satu bukti potong PPh 21/23 beserta jurnal bebannya.
+// Beban dicatat sebesar bruto; pembayaran dikredit sebesar netto dan selisihnya menjadi utang PPh
+// yang disetor koperasi ke kas negara.
+type PemotonganPPh struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID      `gorm:"type:uuid;not null;index" json:"idKoperasi" validate:"required"`
+	JenisPPh          JenisPPh       `gorm:"type:varchar(10);not null;index" json:"jenisPPh" validate:"required,oneof=PPH21 PPH23"`
+	TanggalTransaksi  time.Time      `gorm:"type:date;not null;index" json:"tanggalTransaksi" validate:"required"`
+	NamaPenerima      string         `gorm:"type:varchar(255);not null" json:"namaPenerima" validate:"required"`
+	NPWPPenerima      string         `gorm:"type:varchar(16)" json:"npwpPenerima"` // NPWP 15 digit atau NIK 16 digit; kosong jika tidak ada
+	Deskripsi         string         `gorm:"type:text" json:"deskripsi"`
+	IDAkunBeban       uuid.UUID      `gorm:"type:uuid;not null" json:"idAkunBeban"`
+	IDAkunPembayaran  uuid.UUID      `gorm:"type:uuid;not null" json:"idAkunPembayaran"`
+	IDUnitUsaha       *uuid.UUID     `gorm:"type:uuid;index" json:"idUnitUsaha"`
+	JumlahBruto       Uang           `gorm:"type:decimal(15,2);not null" json:"jumlahBruto"`
+	TarifPersen       float64        `gorm:"type:decimal(5,2);not null" json:"tarifPersen"` // Tarif efektif, termasuk tarif lebih tinggi tanpa NPWP
+	JumlahPPh         Uang           `gorm:"type:decimal(15,2);not null" json:"jumlahPPh"`
+	JumlahNetto       Uang           `gorm:"type:decimal(15,2);not null" json:"jumlahNetto"`
+	IDTransaksi       uuid.UUID      `gorm:"type:uuid;not null;index" json:"idTransaksi"`
+	DibuatOleh        uuid.UUID      `gorm:"type:uuid" json:"dibuatOleh"`
+	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Pembatalan: bukti potong tidak dihapus, jurnalnya dibalik
+	Dibatalkan        bool       `gorm:"type:boolean;not null;default:false;index" json:"dibatalkan"`
+	TanggalDibatalkan *time.Time `json:"tanggalDibatalkan"`
+	DibatalkanOleh    *uuid.UUID `gorm:"type:uuid" json:"dibatalkanOleh"`
+	AlasanPembatalan  string     `gorm:"type:text" json:"alasanPembatalan"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *PemotonganPPh) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (PemotonganPPh) TableName() string {
+	return "pemotongan_pph"
+}
@@ -23,10 +23,12 @@ type Penjualan struct {
 	NomorPenjualan    string           `gorm:"type:varchar(50);not null;uniqueIndex:idx_koperasi_nomor_penjualan" json:"nomorPenjualan" validate:"required"`
 	TanggalPenjualan  time.Time        `gorm:"type:timestamp;not null;index" json:"tanggalPenjualan" validate:"required"`
 	IDAnggota         *uuid.UUID       `gorm:"type:uuid;index" json:"idAnggota"` // Opsional, bisa non-member
-	TotalBelanja      float64          `gorm:"type:decimal(15,2);not null" json:"totalBelanja" validate:"required,gt=0"`
+	TotalBelanja      Uang             `gorm:"type:decimal(15,2);not null" json:"totalBelanja" validate:"required,gt=0"`
 	MetodePembayaran  MetodePembayaran `gorm:"type:varchar(20);not null;default:'tunai'" json:"metodePembayaran"`
-	JumlahBayar       float64          `gorm:"type:decimal(15,2);not null" json:"jumlahBayar" validate:"required,gte=0"`
-	Kembalian         float64          `gorm:"type:decimal(15,2);not null;default:0" json:"kembalian"`
+	JumlahBayar       Uang             `gorm:"type:decimal(15,2);not null" json:"jumlahBayar" validate:"required,gte=0"`
+	Kembalian         Uang             `gorm:"type:decimal(15,2);not null;default:0" json:"kembalian"`
+	TotalDPP          Uang             `gorm:"type:decimal(15,2);not null;default:0" json:"totalDPP"` // Dasar pengenaan pajak item kena PPN
+	TotalPPN          Uang             `gorm:"type:decimal(15,2);not null;default:0" json:"totalPPN"` // Sudah termasuk dalam total belanja
 	IDKasir           uuid.UUID        `gorm:"type:uuid;not null" json:"idKasir" validate:"required"`
 	IDTransaksi       *uuid.UUID       `gorm:"type:uuid;index" json:"idTransaksi"` // Link ke jurnal akuntansi
 	Catatan           string           `gorm:"type:text" json:"catatan"`
@@ -34,6 +36,12 @@ type Penjualan struct {
 	TanggalDiperbarui time.Time        `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
 	TanggalDihapus    gorm.DeletedAt   `gorm:"index" json:"-"`
 
+	// Pembatalan (void): penjualan tidak dihapus, stok dikembalikan dan jurnalnya dibalik
+	Dibatalkan        bool       `gorm:"type:boolean;not null;default:false;index" json:"dibatalkan"`
+	TanggalDibatalkan *time.Time `json:"tanggalDibatalkan"`
+	DibatalkanOleh    *uuid.UUID `gorm:"type:uuid" json:"dibatalkanOleh"`
+	AlasanPembatalan  string     `gorm:"type:text" json:"alasanPembatalan"`
+
 	// Relasi
 	Koperasi      Koperasi        `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
 	Anggota       *Anggota        `gorm:"foreignKey:IDAnggota" json:"anggota,omitempty"`
@@ -79,8 +87,11 @@ type ItemPenjualan struct {
 	IDProduk     uuid.UUID      `gorm:"type:uuid;not null;index" json:"idProduk" validate:"required"`
 	NamaProduk   string         `gorm:"type:varchar(255);not null" json:"namaProduk"` // Snapshot nama produk saat transaksi
 	Kuantitas    int            `gorm:"type:int;not null" json:"kuantitas" validate:"required,gt=0"`
-	HargaSatuan  float64        `gorm:"type:decimal(15,2);not null" json:"hargaSatuan" validate:"required,gt=0"`
-	Subtotal     float64        `gorm:"type:decimal(15,2);not null" json:"subtotal"`
+	HargaSatuan  Uang           `gorm:"type:decimal(15,2);not null" json:"hargaSatuan" validate:"required,gt=0"`
+	Subtotal     Uang           `gorm:"type:decimal(15,2);not null" json:"subtotal"`
+	KodePajak    KodePajak      `gorm:"type:varchar(20);not null;default:'NON_PPN'" json:"kodePajak"` // Snapshot kode pajak produk saat transaksi
+	DPP          Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"dpp"`
+	PPN          Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"ppn"`
 	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
 	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
 	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -97,14 +108,14 @@ func (i *ItemPenjualan) BeforeCreate(tx *gorm.DB) error {
 	}
 
 	// Hitung subtotal
-	i.Subtotal = float64(i.Kuantitas) * i.HargaSatuan
+	i.Subtotal = i.HargaSatuan.Kali(i.Kuantitas)
 
 	return nil
 }
 
 // BeforeSave hook untuk hitung subtotal
 func (i *ItemPenjualan) BeforeSave(tx *gorm.DB) error {
-	i.Subtotal = float64(i.Kuantitas) * i.HargaSatuan
+	i.Subtotal = i.HargaSatuan.Kali(i.Kuantitas)
 	return nil
 }
 
@@ -121,12 +132,16 @@ type PenjualanResponse struct {
 	IDAnggota        *uuid.UUID            `json:"idAnggota"`
 	NamaAnggota      string                `json:"namaAnggota,omitempty"`
 	NomorAnggota     string                `json:"nomorAnggota,omitempty"`
-	TotalBelanja     float64               `json:"totalBelanja"`
+	TotalBelanja     Uang                  `json:"totalBelanja"`
+	TotalDPP         Uang                  `json:"totalDPP"`
+	TotalPPN         Uang                  `json:"totalPPN"`
 	MetodePembayaran MetodePembayaran      `json:"metodePembayaran"`
-	JumlahBayar      float64               `json:"jumlahBayar"`
-	Kembalian        float64               `json:"kembalian"`
+	JumlahBayar      Uang                  `json:"jumlahBayar"`
+	Kembalian        Uang                  `json:"kembalian"`
 	NamaKasir        string                `json:"namaKasir"`
 	Catatan          string                `json:"catatan"`
+	Dibatalkan       bool                  `json:"dibatalkan"`
+	AlasanPembatalan string                `json:"alasanPembatalan,omitempty"`
 	ItemPenjualan    []ItemPenjualanResponse `json:"itemPenjualan,omitempty"`
 }
 
@@ -137,8 +152,11 @@ type ItemPenjualanResponse struct {
 	KodeProduk  string    `json:"kodeProduk,omitempty"`
 	NamaProduk  string    `json:"namaProduk"`
 	Kuantitas   int       `json:"kuantitas"`
-	HargaSatuan float64   `json:"hargaSatuan"`
-	Subtotal    float64   `json:"subtotal"`
+	HargaSatuan Uang      `json:"hargaSatuan"`
+	Subtotal    Uang      `json:"subtotal"`
+	KodePajak   KodePajak `json:"kodePajak"`
+	DPP         Uang      `json:"dpp"`
+	PPN         Uang      `json:"ppn"`
 }
 
 // ToResponse mengkonversi Penjualan ke PenjualanResponse
@@ -149,10 +167,14 @@ func (p *Penjualan) ToResponse() PenjualanResponse {
 		TanggalPenjualan: p.TanggalPenjualan,
 		IDAnggota:        p.IDAnggota,
 		TotalBelanja:     p.TotalBelanja,
+		TotalDPP:         p.TotalDPP,
+		TotalPPN:         p.TotalPPN,
 		MetodePembayaran: p.MetodePembayaran,
 		JumlahBayar:      p.JumlahBayar,
 		Kembalian:        p.Kembalian,
 		Catatan:          p.Catatan,
+		Dibatalkan:       p.Dibatalkan,
+		AlasanPembatalan: p.AlasanPembatalan,
 	}
 
 	// Populate info anggota jika ada dan relasi sudah di-load
@@ -177,6 +199,9 @@ func (p *Penjualan) ToResponse() PenjualanResponse {
 				Kuantitas:   item.Kuantitas,
 				HargaSatuan: item.HargaSatuan,
 				Subtotal:    item.Subtotal,
+				KodePajak:   item.KodePajak,
+				DPP:         item.DPP,
+				PPN:         item.PPN,
 			}
 
 			// Populate kode produk jika relasi sudah di-load
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TipePeriode mendefinisikan jenis periode akuntansi yang ditutup
+type TipePeriode string
+
+const (
+	PeriodeBulanan TipePeriode = "BULANAN" // Penutupan satu bulan kalender
+	PeriodeTahunan TipePeriode = "TAHUNAN" // Penutupan satu tahun buku
+)
+
+// PeriodeAkuntansi mencatat periode yang sudah ditutup.
+// Jurnal dengan tanggal di dalam rentang periode yang ditutup tidak dapat dibuat, diubah, atau dihapus.
+type PeriodeAkuntansi struct {
+	ID             uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi     uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_periode" json:"idKoperasi" validate:"required"`
+	TipePeriode    TipePeriode `gorm:"type:varchar(20);not null;uniqueIndex:idx_koperasi_periode" json:"tipePeriode" validate:"required,oneof=BULANAN TAHUNAN"`
+	TanggalMulai   time.Time   `gorm:"type:date;not null;uniqueIndex:idx_koperasi_periode" json:"tanggalMulai"`
+	TanggalAkhir   time.Time   `gorm:"type:date;not null;index" json:"tanggalAkhir"`
+	TahunBuku      int         `gorm:"type:int;not null" json:"tahunBuku"`       // Label tahun buku (lihat RentangTahunBuku)
+	Bulan          int         `gorm:"type:int;not null;default:0" json:"bulan"` // Bulan kalender (1-12), 0 untuk penutupan tahunan
+	Keterangan     string      `gorm:"type:text" json:"keterangan"`
+	DitutupOleh    uuid.UUID   `gorm:"type:uuid" json:"ditutupOleh"`
+	TanggalDitutup time.Time   `gorm:"autoCreateTime" json:"tanggalDitutup"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *PeriodeAkuntansi) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (PeriodeAkuntansi) TableName() string {
+	return "periode_akuntansi"
+}
+
+// PeriodeAkuntansiResponse adalah response untuk periode akuntansi yang ditutup
+type PeriodeAkuntansiResponse struct {
+	ID             uuid.UUID   `json:"id"`
+	TipePeriode    TipePeriode `json:"tipePeriode"`
+	TanggalMulai   time.Time   `json:"tanggalMulai"`
+	TanggalAkhir   time.Time   `json:"tanggalAkhir"`
+	TahunBuku      int         `json:"tahunBuku"`
+	Bulan          int         `json:"bulan"`
+	Keterangan     string      `json:"keterangan"`
+	DitutupOleh    uuid.UUID   `json:"ditutupOleh"`
+	TanggalDitutup time.Time   `json:"tanggalDitutup"`
+}
+
+// ToResponse converts PeriodeAkuntansi to PeriodeAkuntansiResponse
+func (p *PeriodeAkuntansi) ToResponse() PeriodeAkuntansiResponse {
+	return PeriodeAkuntansiResponse{
+		ID:             p.ID,
+		TipePeriode:    p.TipePeriode,
+		TanggalMulai:   p.TanggalMulai,
+		TanggalAkhir:   p.TanggalAkhir,
+		TahunBuku:      p.TahunBuku,
+		Bulan:          p.Bulan,
+		Keterangan:     p.Keterangan,
+		DitutupOleh:    p.DitutupOleh,
+		TanggalDitutup: p.TanggalDitutup,
+	}
+}
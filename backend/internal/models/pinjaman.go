@@ -0,0 +1,265 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MetodeBunga mendefinisikan metode perhitungan bunga (jasa) pinjaman
+type MetodeBunga string
+
+const (
+	BungaFlat    MetodeBunga = "FLAT"    // Bunga dihitung dari pokok awal, angsuran tetap
+	BungaEfektif MetodeBunga = "EFEKTIF" // Bunga dihitung dari sisa pokok (menurun), pokok tetap
+	BungaAnuitas MetodeBunga = "ANUITAS" // Bunga dari sisa pokok, total angsuran tetap
+)
+
+// StatusPinjaman mendefinisikan status pinjaman anggota
+type StatusPinjaman string
+
+const (
+	StatusPinjamanAktif StatusPinjaman = "AKTIF" // Sudah dicairkan dan masih berjalan
+	StatusPinjamanLunas StatusPinjaman = "LUNAS" // Seluruh angsuran sudah dibayar
+	StatusPinjamanBatal StatusPinjaman = "BATAL" // Pencairan dibatalkan dan jurnalnya sudah dibalik
+)
+
+// ProdukPinjaman merepresentasikan produk pinjaman yang ditawarkan koperasi
+type ProdukPinjaman struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_kode_produk_pinjaman" json:"idKoperasi" validate:"required"`
+	KodeProduk        string         `gorm:"type:varchar(20);not null;uniqueIndex:idx_koperasi_kode_produk_pinjaman" json:"kodeProduk" validate:"required"`
+	NamaProduk        string         `gorm:"type:varchar(255);not null" json:"namaProduk" validate:"required"`
+	Deskripsi         string         `gorm:"type:text" json:"deskripsi"`
+	MetodeBunga       MetodeBunga    `gorm:"type:varchar(20);not null" json:"metodeBunga" validate:"required,oneof=FLAT EFEKTIF ANUITAS"`
+	SukuBungaTahunan  float64        `gorm:"type:decimal(5,2);not null" json:"sukuBungaTahunan"` // Persen per tahun
+	TenorMinimal      int            `gorm:"type:int;not null;default:1" json:"tenorMinimal"`    // Dalam bulan
+	TenorMaksimal     int            `gorm:"type:int;not null" json:"tenorMaksimal"`             // Dalam bulan
+	PlafonMaksimal    Uang           `gorm:"type:decimal(15,2);not null" json:"plafonMaksimal"`
+	StatusAktif       bool           `gorm:"type:boolean;default:true" json:"statusAktif"`
+	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *ProdukPinjaman) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (ProdukPinjaman) TableName() string {
+	return "produk_pinjaman"
+}
+
+// Pinjaman merepresentasikan pinjaman yang diberikan kepada anggota
+type Pinjaman struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_nomor_pinjaman" json:"idKoperasi" validate:"required"`
+	IDAnggota         uuid.UUID      `gorm:"type:uuid;not null;index" json:"idAnggota" validate:"required"`
+	IDProdukPinjaman  uuid.UUID      `gorm:"type:uuid;not null;index" json:"idProdukPinjaman" validate:"required"`
+	NomorPinjaman     string         `gorm:"type:varchar(50);not null;uniqueIndex:idx_koperasi_nomor_pinjaman" json:"nomorPinjaman"`
+	TanggalPencairan  time.Time      `gorm:"type:date;not null;index" json:"tanggalPencairan" validate:"required"`
+	JumlahPokok       Uang           `gorm:"type:decimal(15,2);not null" json:"jumlahPokok" validate:"required,gt=0"`
+	MetodeBunga       MetodeBunga    `gorm:"type:varchar(20);not null" json:"metodeBunga"`            // Disalin dari produk saat pencairan
+	SukuBungaTahunan  float64        `gorm:"type:decimal(5,2);not null" json:"sukuBungaTahunan"`      // Disalin dari produk saat pencairan
+	TenorBulan        int            `gorm:"type:int;not null" json:"tenorBulan" validate:"required"` // Jangka waktu dalam bulan
+	SisaPokok         Uang           `gorm:"type:decimal(15,2);not null" json:"sisaPokok"`
+	Status            StatusPinjaman `gorm:"type:varchar(20);not null;default:'AKTIF'" json:"status"`
+	Keterangan        string         `gorm:"type:text" json:"keterangan"`
+	IDTransaksi       *uuid.UUID     `gorm:"type:uuid;index" json:"idTransaksi"` // Link ke jurnal pencairan
+	TanggalDibatalkan *time.Time     `json:"tanggalDibatalkan"`
+	DibatalkanOleh    *uuid.UUID     `gorm:"type:uuid" json:"dibatalkanOleh"`
+	AlasanPembatalan  string         `gorm:"type:text" json:"alasanPembatalan"`
+	DibuatOleh        uuid.UUID      `gorm:"type:uuid" json:"dibuatOleh"`
+	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relasi
+	Koperasi       Koperasi         `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	Anggota        Anggota          `gorm:"foreignKey:IDAnggota;constraint:OnDelete:CASCADE" json:"-"`
+	ProdukPinjaman ProdukPinjaman   `gorm:"foreignKey:IDProdukPinjaman" json:"-"`
+	Transaksi      *Transaksi       `gorm:"foreignKey:IDTransaksi" json:"-"`
+	JadwalAngsuran []JadwalAngsuran `gorm:"foreignKey:IDPinjaman;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *Pinjaman) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+
+	if p.Status == "" {
+		p.Status = StatusPinjamanAktif
+	}
+
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (Pinjaman) TableName() string {
+	return "pinjaman"
+}
+
+// JadwalAngsuran merepresentasikan satu baris jadwal angsuran pinjaman
+type JadwalAngsuran struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	IDPinjaman        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_pinjaman_angsuran_ke" json:"idPinjaman"`
+	IDKoperasi        uuid.UUID  `gorm:"type:uuid;not null;index" json:"idKoperasi"`
+	AngsuranKe        int        `gorm:"type:int;not null;uniqueIndex:idx_pinjaman_angsuran_ke" json:"angsuranKe"`
+	TanggalJatuhTempo time.Time  `gorm:"type:date;not null;index" json:"tanggalJatuhTempo"`
+	Pokok             Uang       `gorm:"type:decimal(15,2);not null" json:"pokok"`
+	Bunga             Uang       `gorm:"type:decimal(15,2);not null" json:"bunga"`
+	TotalAngsuran     Uang       `gorm:"type:decimal(15,2);not null" json:"totalAngsuran"`
+	SisaPokok         Uang       `gorm:"type:decimal(15,2);not null" json:"sisaPokok"` // Sisa pokok setelah angsuran ini dibayar
+	StatusLunas       bool       `gorm:"type:boolean;default:false" json:"statusLunas"`
+	TanggalBayar      *time.Time `gorm:"type:date" json:"tanggalBayar"`
+	IDTransaksi       *uuid.UUID `gorm:"type:uuid;index" json:"idTransaksi"` // Link ke jurnal pembayaran angsuran
+	TanggalDibuat     time.Time  `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time  `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+
+	// Relasi
+	Pinjaman  Pinjaman   `gorm:"foreignKey:IDPinjaman" json:"-"`
+	Transaksi *Transaksi `gorm:"foreignKey:IDTransaksi" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (j *JadwalAngsuran) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (JadwalAngsuran) TableName() string {
+	return "jadwal_angsuran"
+}
+
+// ProdukPinjamanResponse adalah response untuk API
+type ProdukPinjamanResponse struct {
+	ID               uuid.UUID   `json:"id"`
+	KodeProduk       string      `json:"kodeProduk"`
+	NamaProduk       string      `json:"namaProduk"`
+	Deskripsi        string      `json:"deskripsi"`
+	MetodeBunga      MetodeBunga `json:"metodeBunga"`
+	SukuBungaTahunan float64     `json:"sukuBungaTahunan"`
+	TenorMinimal     int         `json:"tenorMinimal"`
+	TenorMaksimal    int         `json:"tenorMaksimal"`
+	PlafonMaksimal   Uang        `json:"plafonMaksimal"`
+	StatusAktif      bool        `json:"statusAktif"`
+}
+
+// ToResponse mengkonversi ProdukPinjaman ke ProdukPinjamanResponse
+func (p *ProdukPinjaman) ToResponse() ProdukPinjamanResponse {
+	return ProdukPinjamanResponse{
+		ID:               p.ID,
+		KodeProduk:       p.KodeProduk,
+		NamaProduk:       p.NamaProduk,
+		Deskripsi:        p.Deskripsi,
+		MetodeBunga:      p.MetodeBunga,
+		SukuBungaTahunan: p.SukuBungaTahunan,
+		TenorMinimal:     p.TenorMinimal,
+		TenorMaksimal:    p.TenorMaksimal,
+		PlafonMaksimal:   p.PlafonMaksimal,
+		StatusAktif:      p.StatusAktif,
+	}
+}
+
+// JadwalAngsuranResponse adalah response untuk API
+type JadwalAngsuranResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	AngsuranKe        int        `json:"angsuranKe"`
+	TanggalJatuhTempo time.Time  `json:"tanggalJatuhTempo"`
+	Pokok             Uang       `json:"pokok"`
+	Bunga             Uang       `json:"bunga"`
+	TotalAngsuran     Uang       `json:"totalAngsuran"`
+	SisaPokok         Uang       `json:"sisaPokok"`
+	StatusLunas       bool       `json:"statusLunas"`
+	TanggalBayar      *time.Time `json:"tanggalBayar"`
+	IDTransaksi       *uuid.UUID `json:"idTransaksi"`
+}
+
+// ToResponse mengkonversi JadwalAngsuran ke JadwalAngsuranResponse
+func (j *JadwalAngsuran) ToResponse() JadwalAngsuranResponse {
+	return JadwalAngsuranResponse{
+		ID:                j.ID,
+		AngsuranKe:        j.AngsuranKe,
+		TanggalJatuhTempo: j.TanggalJatuhTempo,
+		Pokok:             j.Pokok,
+		Bunga:             j.Bunga,
+		TotalAngsuran:     j.TotalAngsuran,
+		SisaPokok:         j.SisaPokok,
+		StatusLunas:       j.StatusLunas,
+		TanggalBayar:      j.TanggalBayar,
+		IDTransaksi:       j.IDTransaksi,
+	}
+}
+
+// PinjamanResponse adalah response untuk API
+type PinjamanResponse struct {
+	ID               uuid.UUID                `json:"id"`
+	NomorPinjaman    string                   `json:"nomorPinjaman"`
+	IDAnggota        uuid.UUID                `json:"idAnggota"`
+	NomorAnggota     string                   `json:"nomorAnggota"`
+	NamaAnggota      string                   `json:"namaAnggota"`
+	IDProdukPinjaman uuid.UUID                `json:"idProdukPinjaman"`
+	NamaProduk       string                   `json:"namaProduk"`
+	TanggalPencairan time.Time                `json:"tanggalPencairan"`
+	JumlahPokok      Uang                     `json:"jumlahPokok"`
+	MetodeBunga      MetodeBunga              `json:"metodeBunga"`
+	SukuBungaTahunan float64                  `json:"sukuBungaTahunan"`
+	TenorBulan       int                      `json:"tenorBulan"`
+	SisaPokok        Uang                     `json:"sisaPokok"`
+	Status           StatusPinjaman           `json:"status"`
+	Keterangan       string                   `json:"keterangan"`
+	IDTransaksi      *uuid.UUID               `json:"idTransaksi"`
+	AlasanPembatalan string                   `json:"alasanPembatalan,omitempty"`
+	JadwalAngsuran   []JadwalAngsuranResponse `json:"jadwalAngsuran,omitempty"`
+}
+
+// ToResponse mengkonversi Pinjaman ke PinjamanResponse
+func (p *Pinjaman) ToResponse() PinjamanResponse {
+	resp := PinjamanResponse{
+		ID:               p.ID,
+		NomorPinjaman:    p.NomorPinjaman,
+		IDAnggota:        p.IDAnggota,
+		IDProdukPinjaman: p.IDProdukPinjaman,
+		TanggalPencairan: p.TanggalPencairan,
+		JumlahPokok:      p.JumlahPokok,
+		MetodeBunga:      p.MetodeBunga,
+		SukuBungaTahunan: p.SukuBungaTahunan,
+		TenorBulan:       p.TenorBulan,
+		SisaPokok:        p.SisaPokok,
+		Status:           p.Status,
+		Keterangan:       p.Keterangan,
+		IDTransaksi:      p.IDTransaksi,
+		AlasanPembatalan: p.AlasanPembatalan,
+	}
+
+	// Populate info anggota dan produk jika relasi sudah di-load
+	if p.Anggota.ID != uuid.Nil {
+		resp.NomorAnggota = p.Anggota.NomorAnggota
+		resp.NamaAnggota = p.Anggota.NamaLengkap
+	}
+	if p.ProdukPinjaman.ID != uuid.Nil {
+		resp.NamaProduk = p.ProdukPinjaman.NamaProduk
+	}
+
+	if len(p.JadwalAngsuran) > 0 {
+		resp.JadwalAngsuran = make([]JadwalAngsuranResponse, len(p.JadwalAngsuran))
+		for i := range p.JadwalAngsuran {
+			resp.JadwalAngsuran[i] = p.JadwalAngsuran[i].ToResponse()
+		}
+	}
+
+	return resp
+}
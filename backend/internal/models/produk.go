@@ -15,14 +15,16 @@ type Produk struct {
 	NamaProduk        string         `gorm:"type:varchar(255);not null" json:"namaProduk" validate:"required"`
 	Kategori          string         `gorm:"type:varchar(100)" json:"kategori"`
 	Deskripsi         string         `gorm:"type:text" json:"deskripsi"`
-	Harga             float64        `gorm:"type:decimal(15,2);not null" json:"harga" validate:"required,gte=0"`
-	HargaBeli         float64        `gorm:"type:decimal(15,2)" json:"hargaBeli" validate:"gte=0"` // Harga beli/HPP
+	Harga             Uang           `gorm:"type:decimal(15,2);not null" json:"harga" validate:"required,gte=0"`
+	HargaBeli         Uang           `gorm:"type:decimal(15,2)" json:"hargaBeli" validate:"gte=0"` // Harga beli/HPP
 	Stok              int            `gorm:"type:int;default:0" json:"stok"`
 	StokMinimum       int            `gorm:"type:int;default:0" json:"stokMinimum"`
 	Satuan            string         `gorm:"type:varchar(20);default:'pcs'" json:"satuan"` // pcs, kg, liter, dll
 	Barcode           string         `gorm:"type:varchar(100)" json:"barcode"`
 	GambarURL         string         `gorm:"type:varchar(500)" json:"gambarUrl"`
 	StatusAktif       bool           `gorm:"type:boolean;default:true" json:"statusAktif"`
+	IDUnitUsaha       *uuid.UUID     `gorm:"type:uuid;index" json:"idUnitUsaha"` // Unit usaha penjualan produk ini
+	KodePajak         KodePajak      `gorm:"type:varchar(20);not null;default:'NON_PPN'" json:"kodePajak"`
 	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
 	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
 	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -43,6 +45,10 @@ func (p *Produk) BeforeCreate(tx *gorm.DB) error {
 		p.Satuan = "pcs"
 	}
 
+	if p.KodePajak == "" {
+		p.KodePajak = KodePajakNonPPN
+	}
+
 	return nil
 }
 
@@ -53,19 +59,21 @@ func (Produk) TableName() string {
 
 // ProdukResponse adalah response untuk API
 type ProdukResponse struct {
-	ID          uuid.UUID `json:"id"`
-	KodeProduk  string    `json:"kodeProduk"`
-	NamaProduk  string    `json:"namaProduk"`
-	Kategori    string    `json:"kategori"`
-	Deskripsi   string    `json:"deskripsi"`
-	Harga       float64   `json:"harga"`
-	HargaBeli   float64   `json:"hargaBeli"`
-	Stok        int       `json:"stok"`
-	StokMinimum int       `json:"stokMinimum"`
-	Satuan      string    `json:"satuan"`
-	Barcode     string    `json:"barcode"`
-	GambarURL   string    `json:"gambarUrl"`
-	StatusAktif bool      `json:"statusAktif"`
+	ID          uuid.UUID  `json:"id"`
+	KodeProduk  string     `json:"kodeProduk"`
+	NamaProduk  string     `json:"namaProduk"`
+	Kategori    string     `json:"kategori"`
+	Deskripsi   string     `json:"deskripsi"`
+	Harga       Uang       `json:"harga"`
+	HargaBeli   Uang       `json:"hargaBeli"`
+	Stok        int        `json:"stok"`
+	StokMinimum int        `json:"stokMinimum"`
+	Satuan      string     `json:"satuan"`
+	Barcode     string     `json:"barcode"`
+	GambarURL   string     `json:"gambarUrl"`
+	StatusAktif bool       `json:"statusAktif"`
+	IDUnitUsaha *uuid.UUID `json:"idUnitUsaha,omitempty"`
+	KodePajak   KodePajak  `json:"kodePajak"`
 }
 
 // ToResponse mengkonversi Produk ke ProdukResponse
@@ -84,5 +92,7 @@ func (p *Produk) ToResponse() ProdukResponse {
 		Barcode:     p.Barcode,
 		GambarURL:   p.GambarURL,
 		StatusAktif: p.StatusAktif,
+		IDUnitUsaha: p.IDUnitUsaha,
+		KodePajak:   p.KodePajak,
 	}
 }
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FormatRekeningKoran mendefinisikan format berkas rekening koran yang dapat diimpor
+type FormatRekeningKoran string
+
+const (
+	FormatRekeningKoranCSV   FormatRekeningKoran = "CSV"   // Ekspor tabel internet banking
+	FormatRekeningKoranOFX   FormatRekeningKoran = "OFX"   // Open Financial Exchange (SGML atau XML)
+	FormatRekeningKoranMT940 FormatRekeningKoran = "MT940" // SWIFT MT940 customer statement
+)
+
+// StatusMutasiBank mendefinisikan status pencocokan mutasi rekening koran dengan buku besar
+type StatusMutasiBank string
+
+const (
+	StatusMutasiBelumCocok StatusMutasiBank = "BELUM_COCOK" // Belum ada baris jurnal pasangannya
+	StatusMutasiCocok      StatusMutasiBank = "COCOK"       // Sudah dipasangkan dengan satu baris jurnal
+)
+
+// MetodeCocokBank mendefinisikan cara mutasi bank dipasangkan dengan baris jurnal
+type MetodeCocokBank string
+
+const (
+	MetodeCocokOtomatis MetodeCocokBank = "OTOMATIS" // Dicocokkan sistem berdasarkan nominal, tanggal dan referensi
+	MetodeCocokManual   MetodeCocokBank = "MANUAL"   // Dipilih pengguna
+	MetodeCocokJurnal   MetodeCocokBank = "JURNAL"   // Jurnal dibuat dari mutasi (biaya bank, jasa giro)
+)
+
+// RekeningKoranBank mencatat satu berkas rekening koran yang diimpor untuk akun bank
+type RekeningKoranBank struct {
+	ID             uuid.UUID           `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi     uuid.UUID           `gorm:"type:uuid;not null;index" json:"idKoperasi" validate:"required"`
+	IDAkun         uuid.UUID           `gorm:"type:uuid;not null;index" json:"idAkun" validate:"required"`
+	NamaBerkas     string              `gorm:"type:varchar(255)" json:"namaBerkas"`
+	Format         FormatRekeningKoran `gorm:"type:varchar(10);not null" json:"format"`
+	NomorRekening  string              `gorm:"type:varchar(50)" json:"nomorRekening"` // Dari berkas OFX/MT940, jika ada
+	TanggalMulai   time.Time           `gorm:"type:date;not null" json:"tanggalMulai"`
+	TanggalAkhir   time.Time           `gorm:"type:date;not null" json:"tanggalAkhir"`
+	SaldoAwal      *Uang               `gorm:"type:decimal(15,2)" json:"saldoAwal"` // Kosong jika berkas tidak memuat saldo
+	SaldoAkhir     *Uang               `gorm:"type:decimal(15,2)" json:"saldoAkhir"`
+	JumlahMutasi   int                 `gorm:"type:int;not null;default:0" json:"jumlahMutasi"`   // Mutasi baru yang disimpan
+	JumlahDuplikat int                 `gorm:"type:int;not null;default:0" json:"jumlahDuplikat"` // Mutasi yang sudah ada dari impor sebelumnya
+	DiimporOleh    uuid.UUID           `gorm:"type:uuid" json:"diimporOleh"`
+	TanggalDibuat  time.Time           `gorm:"autoCreateTime" json:"tanggalDibuat"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	Akun     Akun     `gorm:"foreignKey:IDAkun;constraint:OnDelete:RESTRICT" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (r *RekeningKoranBank) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (RekeningKoranBank) TableName() string {
+	return "rekening_koran_bank"
+}
+
+// MutasiBank merepresentasikan satu baris mutasi rekening koran.
+// Jumlah positif berarti dana masuk ke rekening (kredit di sisi bank, debit akun bank di buku),
+// jumlah negatif berarti dana keluar. Unique index pada kunci mutasi mencegah mutasi yang sama
+// tersimpan dua kali saat rekening koran dengan periode tumpang tindih diimpor ulang, dan unique
+// index pada baris jurnal memastikan satu baris jurnal hanya dicocokkan dengan satu mutasi.
+type MutasiBank struct {
+	ID                uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_akun_kunci_mutasi_bank" json:"idKoperasi"`
+	IDAkun            uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_akun_kunci_mutasi_bank" json:"idAkun"`
+	IDRekeningKoran   uuid.UUID        `gorm:"type:uuid;not null;index" json:"idRekeningKoran"`
+	KunciMutasi       string           `gorm:"type:varchar(64);not null;uniqueIndex:idx_akun_kunci_mutasi_bank" json:"-"`
+	Tanggal           time.Time        `gorm:"type:date;not null;index" json:"tanggal"`
+	Keterangan        string           `gorm:"type:text" json:"keterangan"`
+	Referensi         string           `gorm:"type:varchar(100)" json:"referensi"`
+	Jumlah            Uang             `gorm:"type:decimal(15,2);not null" json:"jumlah"`
+	Status            StatusMutasiBank `gorm:"type:varchar(20);not null;default:'BELUM_COCOK';index" json:"status"`
+	IDBarisTransaksi  *uuid.UUID       `gorm:"type:uuid;uniqueIndex" json:"idBarisTransaksi"`
+	IDTransaksi       *uuid.UUID       `gorm:"type:uuid;index" json:"idTransaksi"`
+	MetodeCocok       MetodeCocokBank  `gorm:"type:varchar(20)" json:"metodeCocok"`
+	DicocokkanOleh    *uuid.UUID       `gorm:"type:uuid" json:"dicocokkanOleh"`
+	TanggalDicocokkan *time.Time       `json:"tanggalDicocokkan"`
+	TanggalDibuat     time.Time        `gorm:"autoCreateTime" json:"tanggalDibuat"`
+
+	// Relasi
+	RekeningKoran RekeningKoranBank `gorm:"foreignKey:IDRekeningKoran;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (m *MutasiBank) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	if m.Status == "" {
+		m.Status = StatusMutasiBelumCocok
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (MutasiBank) TableName() string {
+	return "mutasi_bank"
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SaldoBulananAkun menyimpan total mutasi debit dan kredit satu akun dalam satu bulan kalender
+// dari jurnal yang sudah di-post. Snapshot diperbarui dalam transaction yang sama dengan
+// posting jurnal, sehingga saldo akun pada suatu tanggal cukup dihitung dari snapshot bulan-bulan
+// sebelumnya ditambah jurnal sejak awal bulan tanggal tersebut.
+type SaldoBulananAkun struct {
+	ID                uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID `gorm:"type:uuid;not null;index" json:"idKoperasi" validate:"required"`
+	IDAkun            uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_akun_bulan_saldo_bulanan" json:"idAkun" validate:"required"`
+	Bulan             time.Time `gorm:"type:date;not null;uniqueIndex:idx_akun_bulan_saldo_bulanan;index" json:"bulan"` // Tanggal 1 bulan mutasi
+	MutasiDebit       Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"mutasiDebit"`
+	MutasiKredit      Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"mutasiKredit"`
+	TanggalDibuat     time.Time `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	Akun     Akun     `gorm:"foreignKey:IDAkun;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (s *SaldoBulananAkun) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (SaldoBulananAkun) TableName() string {
+	return "saldo_bulanan_akun"
+}
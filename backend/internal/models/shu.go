@@ -0,0 +1,199 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PengaturanSHU menyimpan persentase alokasi SHU per koperasi (sesuai AD/ART)
+// Total seluruh persentase harus 100
+type PengaturanSHU struct {
+	ID                   uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"idKoperasi" validate:"required"`
+	PersenDanaCadangan   float64   `gorm:"type:decimal(5,2);not null;default:0" json:"persenDanaCadangan"`
+	PersenJasaModal      float64   `gorm:"type:decimal(5,2);not null;default:0" json:"persenJasaModal"`
+	PersenJasaUsaha      float64   `gorm:"type:decimal(5,2);not null;default:0" json:"persenJasaUsaha"`
+	PersenDanaPengurus   float64   `gorm:"type:decimal(5,2);not null;default:0" json:"persenDanaPengurus"`
+	PersenDanaPendidikan float64   `gorm:"type:decimal(5,2);not null;default:0" json:"persenDanaPendidikan"`
+	PersenDanaSosial     float64   `gorm:"type:decimal(5,2);not null;default:0" json:"persenDanaSosial"`
+	TanggalDibuat        time.Time `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui    time.Time `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (p *PengaturanSHU) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (PengaturanSHU) TableName() string {
+	return "pengaturan_shu"
+}
+
+// TotalPersen menjumlahkan seluruh persentase alokasi
+func (p *PengaturanSHU) TotalPersen() float64 {
+	return p.PersenDanaCadangan + p.PersenJasaModal + p.PersenJasaUsaha +
+		p.PersenDanaPengurus + p.PersenDanaPendidikan + p.PersenDanaSosial
+}
+
+// SHU merepresentasikan penetapan Sisa Hasil Usaha untuk satu tahun buku
+type SHU struct {
+	ID                    uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi            uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_tahun_buku_shu" json:"idKoperasi" validate:"required"`
+	TahunBuku             int            `gorm:"type:int;not null;uniqueIndex:idx_koperasi_tahun_buku_shu" json:"tahunBuku" validate:"required"`
+	PeriodeMulai          time.Time      `gorm:"type:date;not null" json:"periodeMulai"`
+	PeriodeAkhir          time.Time      `gorm:"type:date;not null" json:"periodeAkhir"`
+	TotalSHU              Uang           `gorm:"type:decimal(15,2);not null" json:"totalShu"`
+	DanaCadangan          Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"danaCadangan"`
+	JasaModal             Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"jasaModal"`
+	JasaUsaha             Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"jasaUsaha"`
+	DanaPengurus          Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"danaPengurus"`
+	DanaPendidikan        Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"danaPendidikan"`
+	DanaSosial            Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"danaSosial"`
+	TotalSimpananRataRata Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"totalSimpananRataRata"` // Basis pembagian jasa modal
+	TotalBelanjaAnggota   Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"totalBelanjaAnggota"`   // Basis pembagian jasa usaha
+	TanggalPenetapan      time.Time      `gorm:"type:date;not null" json:"tanggalPenetapan"`                         // Tanggal RAT
+	IDTransaksi           *uuid.UUID     `gorm:"type:uuid;index" json:"idTransaksi"`                                 // Link ke jurnal pembagian SHU
+	DitetapkanOleh        uuid.UUID      `gorm:"type:uuid" json:"ditetapkanOleh"`
+	TanggalDibuat         time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui     time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relasi
+	Koperasi   Koperasi     `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	Transaksi  *Transaksi   `gorm:"foreignKey:IDTransaksi" json:"-"`
+	SHUAnggota []SHUAnggota `gorm:"foreignKey:IDSHU;constraint:OnDelete:CASCADE" json:"shuAnggota,omitempty"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (s *SHU) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (SHU) TableName() string {
+	return "shu"
+}
+
+// SHUAnggota merepresentasikan bagian SHU yang diterima satu anggota
+type SHUAnggota struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	IDSHU            uuid.UUID `gorm:"type:uuid;not null;index" json:"idShu"`
+	IDKoperasi       uuid.UUID `gorm:"type:uuid;not null;index" json:"idKoperasi"`
+	IDAnggota        uuid.UUID `gorm:"type:uuid;not null;index" json:"idAnggota"`
+	RataRataSimpanan Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"rataRataSimpanan"`
+	TotalBelanja     Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"totalBelanja"`
+	JasaModal        Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"jasaModal"`
+	JasaUsaha        Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"jasaUsaha"`
+	TotalSHU         Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"totalShu"`
+	TanggalDibuat    time.Time `gorm:"autoCreateTime" json:"tanggalDibuat"`
+
+	// Relasi
+	Anggota Anggota `gorm:"foreignKey:IDAnggota;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (s *SHUAnggota) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (SHUAnggota) TableName() string {
+	return "shu_anggota"
+}
+
+// SHUAnggotaResponse adalah response untuk bagian SHU per anggota
+type SHUAnggotaResponse struct {
+	IDAnggota        uuid.UUID `json:"idAnggota"`
+	NomorAnggota     string    `json:"nomorAnggota"`
+	NamaAnggota      string    `json:"namaAnggota"`
+	RataRataSimpanan Uang      `json:"rataRataSimpanan"`
+	TotalBelanja     Uang      `json:"totalBelanja"`
+	JasaModal        Uang      `json:"jasaModal"`
+	JasaUsaha        Uang      `json:"jasaUsaha"`
+	TotalSHU         Uang      `json:"totalShu"`
+}
+
+// ToResponse mengkonversi SHUAnggota ke SHUAnggotaResponse
+func (s *SHUAnggota) ToResponse() SHUAnggotaResponse {
+	resp := SHUAnggotaResponse{
+		IDAnggota:        s.IDAnggota,
+		RataRataSimpanan: s.RataRataSimpanan,
+		TotalBelanja:     s.TotalBelanja,
+		JasaModal:        s.JasaModal,
+		JasaUsaha:        s.JasaUsaha,
+		TotalSHU:         s.TotalSHU,
+	}
+
+	// Populate info anggota jika relasi sudah di-load
+	if s.Anggota.ID != uuid.Nil {
+		resp.NomorAnggota = s.Anggota.NomorAnggota
+		resp.NamaAnggota = s.Anggota.NamaLengkap
+	}
+
+	return resp
+}
+
+// SHUResponse adalah response untuk API
+type SHUResponse struct {
+	ID                    *uuid.UUID           `json:"id,omitempty"` // Kosong jika masih simulasi
+	TahunBuku             int                  `json:"tahunBuku"`
+	PeriodeMulai          time.Time            `json:"periodeMulai"`
+	PeriodeAkhir          time.Time            `json:"periodeAkhir"`
+	TotalSHU              Uang                 `json:"totalShu"`
+	DanaCadangan          Uang                 `json:"danaCadangan"`
+	JasaModal             Uang                 `json:"jasaModal"`
+	JasaUsaha             Uang                 `json:"jasaUsaha"`
+	DanaPengurus          Uang                 `json:"danaPengurus"`
+	DanaPendidikan        Uang                 `json:"danaPendidikan"`
+	DanaSosial            Uang                 `json:"danaSosial"`
+	TotalSimpananRataRata Uang                 `json:"totalSimpananRataRata"`
+	TotalBelanjaAnggota   Uang                 `json:"totalBelanjaAnggota"`
+	TanggalPenetapan      *time.Time           `json:"tanggalPenetapan,omitempty"`
+	IDTransaksi           *uuid.UUID           `json:"idTransaksi,omitempty"`
+	SHUAnggota            []SHUAnggotaResponse `json:"shuAnggota"`
+}
+
+// ToResponse mengkonversi SHU ke SHUResponse
+func (s *SHU) ToResponse() SHUResponse {
+	id := s.ID
+	tanggalPenetapan := s.TanggalPenetapan
+	resp := SHUResponse{
+		ID:                    &id,
+		TahunBuku:             s.TahunBuku,
+		PeriodeMulai:          s.PeriodeMulai,
+		PeriodeAkhir:          s.PeriodeAkhir,
+		TotalSHU:              s.TotalSHU,
+		DanaCadangan:          s.DanaCadangan,
+		JasaModal:             s.JasaModal,
+		JasaUsaha:             s.JasaUsaha,
+		DanaPengurus:          s.DanaPengurus,
+		DanaPendidikan:        s.DanaPendidikan,
+		DanaSosial:            s.DanaSosial,
+		TotalSimpananRataRata: s.TotalSimpananRataRata,
+		TotalBelanjaAnggota:   s.TotalBelanjaAnggota,
+		TanggalPenetapan:      &tanggalPenetapan,
+		IDTransaksi:           s.IDTransaksi,
+		SHUAnggota:            make([]SHUAnggotaResponse, len(s.SHUAnggota)),
+	}
+
+	for i := range s.SHUAnggota {
+		resp.SHUAnggota[i] = s.SHUAnggota[i].ToResponse()
+	}
+
+	return resp
+}
@@ -11,26 +11,42 @@ import (
 type TipeSimpanan string
 
 const (
-	SimpananPokok     TipeSimpanan = "POKOK"     // Simpanan pokok - dibayar sekali saat bergabung
-	SimpananWajib     TipeSimpanan = "WAJIB"     // Simpanan wajib - dibayar rutin (bulanan)
-	SimpananSukarela  TipeSimpanan = "SUKARELA"  // Simpanan sukarela - opsional
+	SimpananPokok    TipeSimpanan = "POKOK"    // Simpanan pokok - dibayar sekali saat bergabung
+	SimpananWajib    TipeSimpanan = "WAJIB"    // Simpanan wajib - dibayar rutin (bulanan)
+	SimpananSukarela TipeSimpanan = "SUKARELA" // Simpanan sukarela - opsional
+)
+
+// JenisTransaksiSimpanan mendefinisikan arah mutasi simpanan
+type JenisTransaksiSimpanan string
+
+const (
+	TransaksiSetoran   JenisTransaksiSimpanan = "SETORAN"   // Menambah saldo simpanan
+	TransaksiPenarikan JenisTransaksiSimpanan = "PENARIKAN" // Mengurangi saldo simpanan
 )
 
 // Simpanan merepresentasikan transaksi simpanan anggota
 type Simpanan struct {
-	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
-	IDKoperasi        uuid.UUID      `gorm:"type:uuid;not null;index" json:"idKoperasi" validate:"required"`
-	IDAnggota         uuid.UUID      `gorm:"type:uuid;not null;index" json:"idAnggota" validate:"required"`
-	TipeSimpanan      TipeSimpanan   `gorm:"type:varchar(20);not null" json:"tipeSimpanan" validate:"required,oneof=POKOK WAJIB SUKARELA"`
-	TanggalTransaksi  time.Time      `gorm:"type:date;not null;index" json:"tanggalTransaksi" validate:"required"`
-	JumlahSetoran     float64        `gorm:"type:decimal(15,2);not null" json:"jumlahSetoran" validate:"required,gt=0"`
-	Keterangan        string         `gorm:"type:text" json:"keterangan"`
-	NomorReferensi    string         `gorm:"type:varchar(50)" json:"nomorReferensi"` // Nomor bukti transaksi
-	IDTransaksi       *uuid.UUID     `gorm:"type:uuid;index" json:"idTransaksi"`     // Link ke jurnal akuntansi
-	DibuatOleh        uuid.UUID      `gorm:"type:uuid" json:"dibuatOleh"`            // ID pengguna yang membuat transaksi
-	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
-	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
-	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uuid.UUID              `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID              `gorm:"type:uuid;not null;index" json:"idKoperasi" validate:"required"`
+	IDAnggota         uuid.UUID              `gorm:"type:uuid;not null;index" json:"idAnggota" validate:"required"`
+	TipeSimpanan      TipeSimpanan           `gorm:"type:varchar(20);not null" json:"tipeSimpanan" validate:"required,oneof=POKOK WAJIB SUKARELA"`
+	JenisTransaksi    JenisTransaksiSimpanan `gorm:"type:varchar(20);not null;default:'SETORAN'" json:"jenisTransaksi"`
+	TanggalTransaksi  time.Time              `gorm:"type:date;not null;index" json:"tanggalTransaksi" validate:"required"`
+	JumlahSetoran     Uang                   `gorm:"type:decimal(15,2);not null" json:"jumlahSetoran" validate:"required,gt=0"` // Nominal mutasi, selalu positif (lihat JenisTransaksi)
+	Keterangan        string                 `gorm:"type:text" json:"keterangan"`
+	NomorReferensi    string                 `gorm:"type:varchar(50)" json:"nomorReferensi"` // Nomor bukti transaksi
+	IDTransaksi       *uuid.UUID             `gorm:"type:uuid;index" json:"idTransaksi"`     // Link ke jurnal akuntansi
+	IDUnitUsaha       *uuid.UUID             `gorm:"type:uuid;index" json:"idUnitUsaha"`     // Unit usaha untuk baris jurnal simpanan
+	DibuatOleh        uuid.UUID              `gorm:"type:uuid" json:"dibuatOleh"`            // ID pengguna yang membuat transaksi
+	TanggalDibuat     time.Time              `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time              `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus    gorm.DeletedAt         `gorm:"index" json:"-"`
+
+	// Pembatalan (void): simpanan tidak dihapus, jurnalnya dibalik dan tidak lagi dihitung dalam saldo
+	Dibatalkan        bool       `gorm:"type:boolean;not null;default:false;index" json:"dibatalkan"`
+	TanggalDibatalkan *time.Time `json:"tanggalDibatalkan"`
+	DibatalkanOleh    *uuid.UUID `gorm:"type:uuid" json:"dibatalkanOleh"`
+	AlasanPembatalan  string     `gorm:"type:text" json:"alasanPembatalan"`
 
 	// Relasi
 	Koperasi  Koperasi   `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
@@ -49,6 +65,11 @@ func (s *Simpanan) BeforeCreate(tx *gorm.DB) error {
 		s.TanggalTransaksi = time.Now()
 	}
 
+	// Transaksi tanpa jenis dianggap setoran
+	if s.JenisTransaksi == "" {
+		s.JenisTransaksi = TransaksiSetoran
+	}
+
 	return nil
 }
 
@@ -59,15 +80,19 @@ func (Simpanan) TableName() string {
 
 // SimpananResponse adalah response untuk API
 type SimpananResponse struct {
-	ID               uuid.UUID    `json:"id"`
-	IDAnggota        uuid.UUID    `json:"idAnggota"`
-	NamaAnggota      string       `json:"namaAnggota"`
-	NomorAnggota     string       `json:"nomorAnggota"`
-	TipeSimpanan     TipeSimpanan `json:"tipeSimpanan"`
-	TanggalTransaksi time.Time    `json:"tanggalTransaksi"`
-	JumlahSetoran    float64      `json:"jumlahSetoran"`
-	Keterangan       string       `json:"keterangan"`
-	NomorReferensi   string       `json:"nomorReferensi"`
+	ID               uuid.UUID              `json:"id"`
+	IDAnggota        uuid.UUID              `json:"idAnggota"`
+	NamaAnggota      string                 `json:"namaAnggota"`
+	NomorAnggota     string                 `json:"nomorAnggota"`
+	TipeSimpanan     TipeSimpanan           `json:"tipeSimpanan"`
+	JenisTransaksi   JenisTransaksiSimpanan `json:"jenisTransaksi"`
+	TanggalTransaksi time.Time              `json:"tanggalTransaksi"`
+	JumlahSetoran    Uang                   `json:"jumlahSetoran"`
+	Keterangan       string                 `json:"keterangan"`
+	NomorReferensi   string                 `json:"nomorReferensi"`
+	Dibatalkan       bool                   `json:"dibatalkan"`
+	AlasanPembatalan string                 `json:"alasanPembatalan,omitempty"`
+	IDUnitUsaha      *uuid.UUID             `json:"idUnitUsaha,omitempty"`
 }
 
 // ToResponse mengkonversi Simpanan ke SimpananResponse
@@ -76,10 +101,14 @@ func (s *Simpanan) ToResponse() SimpananResponse {
 		ID:               s.ID,
 		IDAnggota:        s.IDAnggota,
 		TipeSimpanan:     s.TipeSimpanan,
+		JenisTransaksi:   s.JenisTransaksi,
 		TanggalTransaksi: s.TanggalTransaksi,
 		JumlahSetoran:    s.JumlahSetoran,
 		Keterangan:       s.Keterangan,
 		NomorReferensi:   s.NomorReferensi,
+		Dibatalkan:       s.Dibatalkan,
+		AlasanPembatalan: s.AlasanPembatalan,
+		IDUnitUsaha:      s.IDUnitUsaha,
 	}
 
 	// Populate nama anggota jika relasi sudah di-load
@@ -93,20 +122,32 @@ func (s *Simpanan) ToResponse() SimpananResponse {
 
 // RingkasanSimpanan adalah struktur untuk summary simpanan koperasi
 type RingkasanSimpanan struct {
-	TotalSimpananPokok    float64 `json:"totalSimpananPokok"`
-	TotalSimpananWajib    float64 `json:"totalSimpananWajib"`
-	TotalSimpananSukarela float64 `json:"totalSimpananSukarela"`
-	TotalSemuaSimpanan    float64 `json:"totalSemuaSimpanan"`
-	JumlahAnggota         int64   `json:"jumlahAnggota"`
+	TotalSimpananPokok    Uang  `json:"totalSimpananPokok"`
+	TotalSimpananWajib    Uang  `json:"totalSimpananWajib"`
+	TotalSimpananSukarela Uang  `json:"totalSimpananSukarela"`
+	TotalSemuaSimpanan    Uang  `json:"totalSemuaSimpanan"`
+	JumlahAnggota         int64 `json:"jumlahAnggota"`
+}
+
+// JumlahBersih mengembalikan nominal bertanda: positif untuk setoran, negatif untuk penarikan.
+// Simpanan yang dibatalkan bernilai nol.
+func (s *Simpanan) JumlahBersih() Uang {
+	if s.Dibatalkan {
+		return 0
+	}
+	if s.JenisTransaksi == TransaksiPenarikan {
+		return -s.JumlahSetoran
+	}
+	return s.JumlahSetoran
 }
 
-// SaldoSimpananAnggota adalah struktur untuk saldo simpanan per anggota
+// SaldoSimpananAnggota adalah struktur untuk saldo simpanan per anggota (setoran dikurangi penarikan)
 type SaldoSimpananAnggota struct {
 	IDAnggota        uuid.UUID `json:"idAnggota"`
 	NomorAnggota     string    `json:"nomorAnggota"`
 	NamaAnggota      string    `json:"namaAnggota"`
-	SimpananPokok    float64   `json:"simpananPokok"`
-	SimpananWajib    float64   `json:"simpananWajib"`
-	SimpananSukarela float64   `json:"simpananSukarela"`
-	TotalSimpanan    float64   `json:"totalSimpanan"`
+	SimpananPokok    Uang      `json:"simpananPokok"`
+	SimpananWajib    Uang      `json:"simpananWajib"`
+	SimpananSukarela Uang      `json:"simpananSukarela"`
+	TotalSimpanan    Uang      `json:"totalSimpanan"`
 }
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FrekuensiJurnal mendefinisikan frekuensi jadwal jurnal berulang
+type FrekuensiJurnal string
+
+const (
+	FrekuensiBulanan    FrekuensiJurnal = "BULANAN"    // Setiap bulan
+	FrekuensiTriwulanan FrekuensiJurnal = "TRIWULANAN" // Setiap tiga bulan
+)
+
+// JarakBulan mengembalikan jarak antar jatuh tempo dalam bulan (0 untuk frekuensi tidak dikenal)
+func (f FrekuensiJurnal) JarakBulan() int {
+	switch f {
+	case FrekuensiBulanan:
+		return 1
+	case FrekuensiTriwulanan:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// TemplateJurnal merepresentasikan template jurnal umum yang dapat dipakai ulang, misalnya
+// sewa, gaji, atau listrik. Template yang memiliki frekuensi dijadwalkan sebagai jurnal
+// berulang dan dibuat otomatis oleh scheduler pada setiap tanggal jatuh tempo.
+type TemplateJurnal struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_kode_template" json:"idKoperasi" validate:"required"`
+	KodeTemplate string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_koperasi_kode_template" json:"kodeTemplate" validate:"required"`
+	NamaTemplate string    `gorm:"type:varchar(255);not null" json:"namaTemplate" validate:"required"`
+	Deskripsi    string    `gorm:"type:text;not null" json:"deskripsi" validate:"required"` // Deskripsi jurnal yang dibuat
+	StatusAktif  bool      `gorm:"type:boolean;not null;default:true" json:"statusAktif"`
+	TotalDebit   Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"totalDebit"`
+	TotalKredit  Uang      `gorm:"type:decimal(15,2);not null;default:0" json:"totalKredit"`
+
+	// Jadwal berulang: kosong berarti template hanya dipakai manual. Tanggal jatuh tempo
+	// mengikuti tanggal (hari) TanggalMulai dan dibatasi ke akhir bulan untuk bulan yang lebih pendek.
+	Frekuensi           FrekuensiJurnal `gorm:"type:varchar(20)" json:"frekuensi"`
+	TanggalMulai        *time.Time      `gorm:"type:date" json:"tanggalMulai"`
+	TanggalSelesai      *time.Time      `gorm:"type:date" json:"tanggalSelesai"`                            // Kosong: berulang tanpa batas
+	TanggalBerikutnya   *time.Time      `gorm:"type:date;index" json:"tanggalBerikutnya"`                   // Kosong: jadwal sudah selesai
+	TanggalTerakhir     *time.Time      `gorm:"type:date" json:"tanggalTerakhir"`                           // Jatuh tempo terakhir yang sudah diproses
+	PostingLangsung     bool            `gorm:"type:boolean;not null;default:false" json:"postingLangsung"` // False: jurnal dibuat sebagai DRAFT
+	IDTransaksiTerakhir *uuid.UUID      `gorm:"type:uuid" json:"idTransaksiTerakhir"`
+
+	DibuatOleh        uuid.UUID      `gorm:"type:uuid" json:"dibuatOleh"`
+	DiperbaruiOleh    uuid.UUID      `gorm:"type:uuid" json:"diperbaruiOleh"`
+	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relasi
+	Koperasi Koperasi              `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+	Baris    []BarisTemplateJurnal `gorm:"foreignKey:IDTemplateJurnal;constraint:OnDelete:CASCADE" json:"baris,omitempty"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (t *TemplateJurnal) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (TemplateJurnal) TableName() string {
+	return "template_jurnal"
+}
+
+// BarisTemplateJurnal merepresentasikan satu baris debit/kredit template jurnal
+type BarisTemplateJurnal struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	IDTemplateJurnal uuid.UUID  `gorm:"type:uuid;not null;index" json:"idTemplateJurnal"`
+	Urutan           int        `gorm:"type:int;not null" json:"urutan"`
+	IDAkun           uuid.UUID  `gorm:"type:uuid;not null;index" json:"idAkun"`
+	JumlahDebit      Uang       `gorm:"type:decimal(15,2);not null;default:0" json:"jumlahDebit"`
+	JumlahKredit     Uang       `gorm:"type:decimal(15,2);not null;default:0" json:"jumlahKredit"`
+	Keterangan       string     `gorm:"type:text" json:"keterangan"`
+	IDUnitUsaha      *uuid.UUID `gorm:"type:uuid" json:"idUnitUsaha"`
+
+	// Relasi
+	Akun Akun `gorm:"foreignKey:IDAkun;constraint:OnDelete:RESTRICT" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (b *BarisTemplateJurnal) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (BarisTemplateJurnal) TableName() string {
+	return "baris_template_jurnal"
+}
+
+// TemplateJurnalResponse adalah response untuk API
+type TemplateJurnalResponse struct {
+	ID                  uuid.UUID                     `json:"id"`
+	KodeTemplate        string                        `json:"kodeTemplate"`
+	NamaTemplate        string                        `json:"namaTemplate"`
+	Deskripsi           string                        `json:"deskripsi"`
+	StatusAktif         bool                          `json:"statusAktif"`
+	TotalDebit          Uang                          `json:"totalDebit"`
+	TotalKredit         Uang                          `json:"totalKredit"`
+	Frekuensi           FrekuensiJurnal               `json:"frekuensi,omitempty"`
+	TanggalMulai        *time.Time                    `json:"tanggalMulai,omitempty"`
+	TanggalSelesai      *time.Time                    `json:"tanggalSelesai,omitempty"`
+	TanggalBerikutnya   *time.Time                    `json:"tanggalBerikutnya,omitempty"`
+	TanggalTerakhir     *time.Time                    `json:"tanggalTerakhir,omitempty"`
+	PostingLangsung     bool                          `json:"postingLangsung"`
+	IDTransaksiTerakhir *uuid.UUID                    `json:"idTransaksiTerakhir,omitempty"`
+	Baris               []BarisTemplateJurnalResponse `json:"baris,omitempty"`
+}
+
+// BarisTemplateJurnalResponse adalah response untuk baris template jurnal
+type BarisTemplateJurnalResponse struct {
+	IDAkun       uuid.UUID  `json:"idAkun"`
+	KodeAkun     string     `json:"kodeAkun"`
+	NamaAkun     string     `json:"namaAkun"`
+	JumlahDebit  Uang       `json:"jumlahDebit"`
+	JumlahKredit Uang       `json:"jumlahKredit"`
+	Keterangan   string     `json:"keterangan"`
+	IDUnitUsaha  *uuid.UUID `json:"idUnitUsaha,omitempty"`
+}
+
+// ToResponse mengkonversi TemplateJurnal ke TemplateJurnalResponse
+func (t *TemplateJurnal) ToResponse() TemplateJurnalResponse {
+	baris := make([]BarisTemplateJurnalResponse, len(t.Baris))
+	for i, b := range t.Baris {
+		baris[i] = BarisTemplateJurnalResponse{
+			IDAkun:       b.IDAkun,
+			KodeAkun:     b.Akun.KodeAkun,
+			NamaAkun:     b.Akun.NamaAkun,
+			JumlahDebit:  b.JumlahDebit,
+			JumlahKredit: b.JumlahKredit,
+			Keterangan:   b.Keterangan,
+			IDUnitUsaha:  b.IDUnitUsaha,
+		}
+	}
+
+	return TemplateJurnalResponse{
+		ID:                  t.ID,
+		KodeTemplate:        t.KodeTemplate,
+		NamaTemplate:        t.NamaTemplate,
+		Deskripsi:           t.Deskripsi,
+		StatusAktif:         t.StatusAktif,
+		TotalDebit:          t.TotalDebit,
+		TotalKredit:         t.TotalKredit,
+		Frekuensi:           t.Frekuensi,
+		TanggalMulai:        t.TanggalMulai,
+		TanggalSelesai:      t.TanggalSelesai,
+		TanggalBerikutnya:   t.TanggalBerikutnya,
+		TanggalTerakhir:     t.TanggalTerakhir,
+		PostingLangsung:     t.PostingLangsung,
+		IDTransaksiTerakhir: t.IDTransaksiTerakhir,
+		Baris:               baris,
+	}
+}
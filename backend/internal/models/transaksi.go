@@ -1,25 +1,35 @@
 package models
 
 import (
-	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-const (
-	// EpsilonTolerance adalah toleransi untuk perbandingan floating-point dalam rupiah
-	// 0.01 = 1 sen toleransi untuk menangani masalah floating-point precision
-	EpsilonTolerance = 0.01
-)
-
 // Jenis transaksi akuntansi
 const (
 	TipeTransaksiJurnalUmum = "JURNAL_UMUM"  // Manual journal entry
 	TipeTransaksiSimpanan   = "SIMPANAN"     // Savings transaction
 	TipeTransaksiPenjualan  = "PENJUALAN"    // Sales transaction
 	TipeTransaksiPembelian  = "PEMBELIAN"    // Purchase transaction (Phase 2+)
+	TipeTransaksiSHU        = "SHU"          // SHU distribution
+	TipeTransaksiPinjaman   = "PINJAMAN"     // Loan disbursement and installment
+	TipeTransaksiPenutupan  = "PENUTUPAN"    // Year-end closing entry
+	TipeTransaksiSaldoAwal  = "SALDO_AWAL"   // Opening balance migration
+	TipeTransaksiAsetTetap  = "ASET_TETAP"   // Fixed asset acquisition, depreciation and disposal
+	TipeTransaksiBank       = "BANK"         // Bank charges and interest booked from a bank statement
+	TipeTransaksiPajak      = "PAJAK"        // Expense with PPh 21/23 withheld
+)
+
+// StatusJurnal mendefinisikan tahapan persetujuan jurnal (maker-checker)
+type StatusJurnal string
+
+const (
+	StatusJurnalDraft     StatusJurnal = "DRAFT"     // Masih dapat diubah oleh pembuat
+	StatusJurnalDiajukan  StatusJurnal = "DIAJUKAN"  // Menunggu persetujuan admin
+	StatusJurnalDisetujui StatusJurnal = "DISETUJUI" // Disetujui, menunggu posting
+	StatusJurnalPosted    StatusJurnal = "POSTED"    // Sudah di-post, dihitung dalam saldo dan laporan
 )
 
 // Transaksi merepresentasikan jurnal transaksi akuntansi (header)
@@ -31,8 +41,8 @@ type Transaksi struct {
 	Deskripsi         string         `gorm:"type:text;not null" json:"deskripsi" validate:"required"`
 	NomorReferensi    string         `gorm:"type:varchar(50)" json:"nomorReferensi"` // Nomor bukti transaksi eksternal
 	TipeTransaksi     string         `gorm:"type:varchar(50)" json:"tipeTransaksi"`  // manual, penjualan, simpanan, dll
-	TotalDebit        float64        `gorm:"type:decimal(15,2);not null;default:0" json:"totalDebit"`
-	TotalKredit       float64        `gorm:"type:decimal(15,2);not null;default:0" json:"totalKredit"`
+	TotalDebit        Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"totalDebit"`
+	TotalKredit       Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"totalKredit"`
 	StatusBalanced    bool           `gorm:"type:boolean;default:false" json:"statusBalanced"` // Apakah debit = kredit
 	DibuatOleh        uuid.UUID      `gorm:"type:uuid" json:"dibuatOleh"`
 	DiperbaruiOleh    uuid.UUID      `gorm:"type:uuid" json:"diperbaruiOleh"`
@@ -40,6 +50,26 @@ type Transaksi struct {
 	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
 	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Persetujuan: jurnal umum buatan kasir atau bendahara dimulai sebagai DRAFT dan baru
+	// dihitung dalam saldo setelah disetujui admin dan di-post. Jurnal sistem langsung POSTED.
+	Status           StatusJurnal `gorm:"type:varchar(20);not null;default:'POSTED';index" json:"status"`
+	DiajukanOleh     *uuid.UUID   `gorm:"type:uuid" json:"diajukanOleh"`
+	TanggalDiajukan  *time.Time   `json:"tanggalDiajukan"`
+	DisetujuiOleh    *uuid.UUID   `gorm:"type:uuid" json:"disetujuiOleh"`
+	TanggalDisetujui *time.Time   `json:"tanggalDisetujui"`
+	DipostingOleh    *uuid.UUID   `gorm:"type:uuid" json:"dipostingOleh"`
+	TanggalDiposting *time.Time   `json:"tanggalDiposting"`
+	DitolakOleh      *uuid.UUID   `gorm:"type:uuid" json:"ditolakOleh"`
+	TanggalDitolak   *time.Time   `json:"tanggalDitolak"`
+	AlasanPenolakan  string       `gorm:"type:text" json:"alasanPenolakan"`
+
+	// Pembalikan: jurnal yang sudah di-post tidak dihapus, melainkan dibalik dengan jurnal
+	// cermin (debit dan kredit ditukar). Jurnal asal dan jurnal pembalik saling terhubung.
+	Dibalik          bool       `gorm:"type:boolean;not null;default:false" json:"dibalik"` // Jurnal ini sudah dibalik
+	IDJurnalPembalik *uuid.UUID `gorm:"type:uuid;index" json:"idJurnalPembalik"`            // Jurnal yang membalik jurnal ini
+	IDJurnalAsal     *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"idJurnalAsal"`          // Jurnal asal yang dibalik oleh jurnal ini
+	AlasanPembalikan string     `gorm:"type:text" json:"alasanPembalikan"`
+
 	// Relasi
 	Koperasi       Koperasi         `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
 	BarisTransaksi []BarisTransaksi `gorm:"foreignKey:IDTransaksi;constraint:OnDelete:CASCADE" json:"barisTransaksi,omitempty"`
@@ -56,13 +86,24 @@ func (t *Transaksi) BeforeCreate(tx *gorm.DB) error {
 		t.TanggalTransaksi = time.Now()
 	}
 
+	// Jurnal tanpa status dianggap langsung di-post
+	if t.Status == "" {
+		t.Status = StatusJurnalPosted
+	}
+
 	return nil
 }
 
-// BeforeSave hook untuk menghitung total dan validasi balance
+// BeforeSave hook untuk menghitung total dan validasi balance.
+// Total hanya dihitung ulang jika baris transaksi ikut disimpan; service menulis header
+// lebih dulu dengan total yang sudah dihitung, lalu baris-barisnya secara terpisah.
 func (t *Transaksi) BeforeSave(tx *gorm.DB) error {
+	if len(t.BarisTransaksi) == 0 {
+		return nil
+	}
+
 	// Hitung total debit dan kredit dari baris transaksi
-	var totalDebit, totalKredit float64
+	var totalDebit, totalKredit Uang
 	for _, baris := range t.BarisTransaksi {
 		totalDebit += baris.JumlahDebit
 		totalKredit += baris.JumlahKredit
@@ -71,11 +112,8 @@ func (t *Transaksi) BeforeSave(tx *gorm.DB) error {
 	t.TotalDebit = totalDebit
 	t.TotalKredit = totalKredit
 
-	// Cek apakah transaksi balanced (debit = kredit) dengan epsilon tolerance
-	// Menggunakan math.Abs untuk mengatasi floating-point precision issues
-	isBalanced := math.Abs(totalDebit-totalKredit) <= EpsilonTolerance
-	hasValue := totalDebit >= EpsilonTolerance
-	t.StatusBalanced = isBalanced && hasValue
+	// Transaksi balanced jika debit = kredit (eksak dalam sen) dan bernilai
+	t.StatusBalanced = totalDebit == totalKredit && totalDebit > 0
 
 	return nil
 }
@@ -90,9 +128,10 @@ type BarisTransaksi struct {
 	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
 	IDTransaksi       uuid.UUID      `gorm:"type:uuid;not null;index" json:"idTransaksi" validate:"required"`
 	IDAkun            uuid.UUID      `gorm:"type:uuid;not null;index" json:"idAkun" validate:"required"`
-	JumlahDebit       float64        `gorm:"type:decimal(15,2);not null;default:0" json:"jumlahDebit" validate:"gte=0"`
-	JumlahKredit      float64        `gorm:"type:decimal(15,2);not null;default:0" json:"jumlahKredit" validate:"gte=0"`
+	JumlahDebit       Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"jumlahDebit" validate:"gte=0"`
+	JumlahKredit      Uang           `gorm:"type:decimal(15,2);not null;default:0" json:"jumlahKredit" validate:"gte=0"`
 	Keterangan        string         `gorm:"type:text" json:"keterangan"`
+	IDUnitUsaha       *uuid.UUID     `gorm:"type:uuid;index" json:"idUnitUsaha"` // Dimensi unit usaha, kosong = tanpa unit
 	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
 	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
 	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
@@ -123,13 +162,27 @@ type TransaksiResponse struct {
 	Deskripsi          string                   `json:"deskripsi"`
 	NomorReferensi     string                   `json:"nomorReferensi"`
 	TipeTransaksi      string                   `json:"tipeTransaksi"`
-	TotalDebit         float64                  `json:"totalDebit"`
-	TotalKredit        float64                  `json:"totalKredit"`
+	TotalDebit         Uang                     `json:"totalDebit"`
+	TotalKredit        Uang                     `json:"totalKredit"`
 	StatusBalanced     bool                     `json:"statusBalanced"`
 	DibuatOleh         uuid.UUID                `json:"dibuatOleh,omitempty"`
 	NamaDibuatOleh     string                   `json:"namaDibuatOleh,omitempty"`
 	DiperbaruiOleh     uuid.UUID                `json:"diperbaruiOleh,omitempty"`
 	NamaDiperbaruiOleh string                   `json:"namaDiperbaruiOleh,omitempty"`
+	Status             StatusJurnal             `json:"status"`
+	DiajukanOleh       *uuid.UUID               `json:"diajukanOleh,omitempty"`
+	TanggalDiajukan    *time.Time               `json:"tanggalDiajukan,omitempty"`
+	DisetujuiOleh      *uuid.UUID               `json:"disetujuiOleh,omitempty"`
+	TanggalDisetujui   *time.Time               `json:"tanggalDisetujui,omitempty"`
+	DipostingOleh      *uuid.UUID               `json:"dipostingOleh,omitempty"`
+	TanggalDiposting   *time.Time               `json:"tanggalDiposting,omitempty"`
+	DitolakOleh        *uuid.UUID               `json:"ditolakOleh,omitempty"`
+	TanggalDitolak     *time.Time               `json:"tanggalDitolak,omitempty"`
+	AlasanPenolakan    string                   `json:"alasanPenolakan,omitempty"`
+	Dibalik            bool                     `json:"dibalik"`
+	IDJurnalPembalik   *uuid.UUID               `json:"idJurnalPembalik,omitempty"`
+	IDJurnalAsal       *uuid.UUID               `json:"idJurnalAsal,omitempty"`
+	AlasanPembalikan   string                   `json:"alasanPembalikan,omitempty"`
 	TanggalDibuat      time.Time                `json:"tanggalDibuat,omitempty"`
 	TanggalDiperbarui  time.Time                `json:"tanggalDiperbarui,omitempty"`
 	BarisTransaksi     []BarisTransaksiResponse `json:"barisTransaksi,omitempty"`
@@ -137,13 +190,14 @@ type TransaksiResponse struct {
 
 // BarisTransaksiResponse adalah response untuk baris transaksi
 type BarisTransaksiResponse struct {
-	ID           uuid.UUID `json:"id"`
-	IDAkun       uuid.UUID `json:"idAkun"`
-	KodeAkun     string    `json:"kodeAkun"`
-	NamaAkun     string    `json:"namaAkun"`
-	JumlahDebit  float64   `json:"jumlahDebit"`
-	JumlahKredit float64   `json:"jumlahKredit"`
-	Keterangan   string    `json:"keterangan"`
+	ID           uuid.UUID  `json:"id"`
+	IDAkun       uuid.UUID  `json:"idAkun"`
+	KodeAkun     string     `json:"kodeAkun"`
+	NamaAkun     string     `json:"namaAkun"`
+	JumlahDebit  Uang       `json:"jumlahDebit"`
+	JumlahKredit Uang       `json:"jumlahKredit"`
+	Keterangan   string     `json:"keterangan"`
+	IDUnitUsaha  *uuid.UUID `json:"idUnitUsaha,omitempty"`
 }
 
 // ToResponse mengkonversi Transaksi ke TransaksiResponse
@@ -160,6 +214,20 @@ func (t *Transaksi) ToResponse() TransaksiResponse {
 		StatusBalanced:    t.StatusBalanced,
 		DibuatOleh:        t.DibuatOleh,
 		DiperbaruiOleh:    t.DiperbaruiOleh,
+		Status:            t.Status,
+		DiajukanOleh:      t.DiajukanOleh,
+		TanggalDiajukan:   t.TanggalDiajukan,
+		DisetujuiOleh:     t.DisetujuiOleh,
+		TanggalDisetujui:  t.TanggalDisetujui,
+		DipostingOleh:     t.DipostingOleh,
+		TanggalDiposting:  t.TanggalDiposting,
+		DitolakOleh:       t.DitolakOleh,
+		TanggalDitolak:    t.TanggalDitolak,
+		AlasanPenolakan:   t.AlasanPenolakan,
+		Dibalik:           t.Dibalik,
+		IDJurnalPembalik:  t.IDJurnalPembalik,
+		IDJurnalAsal:      t.IDJurnalAsal,
+		AlasanPembalikan:  t.AlasanPembalikan,
 		TanggalDibuat:     t.TanggalDibuat,
 		TanggalDiperbarui: t.TanggalDiperbarui,
 	}
@@ -174,6 +242,7 @@ func (t *Transaksi) ToResponse() TransaksiResponse {
 				JumlahDebit:  baris.JumlahDebit,
 				JumlahKredit: baris.JumlahKredit,
 				Keterangan:   baris.Keterangan,
+				IDUnitUsaha:  baris.IDUnitUsaha,
 			}
 
 			// Populate info akun jika relasi sudah di-load
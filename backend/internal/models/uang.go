@@ -0,0 +1,257 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Uang adalah nilai rupiah fixed-point dalam satuan sen (1/100 rupiah).
+//
+// Seluruh nominal disimpan sebagai bilangan bulat sehingga penjumlahan dan perbandingan
+// selalu eksak; tidak ada toleransi epsilon seperti pada float64. Nilai disimpan ke kolom
+// decimal(15,2) dan dikirim ke JSON sebagai angka desimal rupiah (misalnya 1500.50).
+type Uang int64
+
+// SenPerRupiah adalah jumlah sen dalam satu rupiah
+const SenPerRupiah = 100
+
+// Rupiah membuat Uang dari nominal rupiah bulat
+func Rupiah(rupiah int64) Uang {
+	return Uang(rupiah * SenPerRupiah)
+}
+
+// UangDariFloat membulatkan nominal rupiah float64 ke sen terdekat (half away from zero).
+// Hanya digunakan pada batas sistem, misalnya hasil perhitungan bunga atau input lama.
+func UangDariFloat(rupiah float64) Uang {
+	return Uang(math.Round(rupiah * SenPerRupiah))
+}
+
+// ParseUang mengurai nominal rupiah desimal (misalnya "1500", "-20.5", "1500.25") secara eksak.
+// Nominal dengan lebih dari 2 angka di belakang koma ditolak.
+func ParseUang(s string) (Uang, error) {
+	return parseUang(s, false)
+}
+
+// parseUang mengurai string desimal; jika bulatkan true, digit setelah sen dibulatkan
+// (dipakai untuk hasil agregasi database seperti AVG) alih-alih ditolak.
+func parseUang(s string, bulatkan bool) (Uang, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("nominal uang kosong")
+	}
+
+	negatif := false
+	switch s[0] {
+	case '-':
+		negatif = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	bulat, pecahan, _ := strings.Cut(s, ".")
+	if bulat == "" && pecahan == "" {
+		return 0, fmt.Errorf("nominal uang tidak valid: %q", s)
+	}
+	if bulat == "" {
+		bulat = "0"
+	}
+
+	rupiah, err := strconv.ParseInt(bulat, 10, 64)
+	if err != nil || rupiah < 0 {
+		return 0, fmt.Errorf("nominal uang tidak valid: %q", s)
+	}
+	if rupiah > math.MaxInt64/SenPerRupiah-1 {
+		return 0, fmt.Errorf("nominal uang terlalu besar: %q", s)
+	}
+
+	for _, c := range pecahan {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("nominal uang tidak valid: %q", s)
+		}
+	}
+
+	naik := false
+	if len(pecahan) > 2 {
+		lebih := strings.TrimRight(pecahan[2:], "0")
+		if lebih != "" && !bulatkan {
+			return 0, fmt.Errorf("nominal uang hanya boleh 2 angka di belakang koma: %q", s)
+		}
+		naik = lebih != "" && lebih[0] >= '5'
+		pecahan = pecahan[:2]
+	}
+	for len(pecahan) < 2 {
+		pecahan += "0"
+	}
+
+	sen, _ := strconv.ParseInt(pecahan, 10, 64)
+	nilai := rupiah*SenPerRupiah + sen
+	if naik {
+		nilai++
+	}
+	if negatif {
+		nilai = -nilai
+	}
+	return Uang(nilai), nil
+}
+
+// Float64 mengembalikan nominal dalam rupiah sebagai float64 (untuk rasio dan tampilan saja)
+func (u Uang) Float64() float64 {
+	return float64(u) / SenPerRupiah
+}
+
+// Abs mengembalikan nilai mutlak
+func (u Uang) Abs() Uang {
+	if u < 0 {
+		return -u
+	}
+	return u
+}
+
+// Kali mengalikan nominal dengan bilangan bulat (misalnya kuantitas)
+func (u Uang) Kali(n int) Uang {
+	return u * Uang(n)
+}
+
+// Persen menghitung persen% dari nominal, dibulatkan ke sen terdekat.
+// Persentase dengan maksimal 2 angka desimal (kolom decimal(5,2)) dihitung secara eksak.
+func (u Uang) Persen(persen float64) Uang {
+	return u.KaliPecahan(int64(math.Round(persen*100)), 10000)
+}
+
+// KaliPecahan menghitung u * pembilang / penyebut dengan pembulatan half away from zero
+func (u Uang) KaliPecahan(pembilang, penyebut int64) Uang {
+	if penyebut == 0 {
+		return 0
+	}
+	hasil := int64(u) * pembilang
+	if (hasil < 0) != (penyebut < 0) {
+		return Uang((hasil - penyebut/2) / penyebut)
+	}
+	return Uang((hasil + penyebut/2) / penyebut)
+}
+
+// String mengembalikan nominal rupiah dengan tepat 2 angka desimal, misalnya "-1500.05"
+func (u Uang) String() string {
+	tanda := ""
+	sen := int64(u)
+	if sen < 0 {
+		tanda = "-"
+		sen = -sen
+	}
+	return fmt.Sprintf("%s%d.%02d", tanda, sen/SenPerRupiah, sen%SenPerRupiah)
+}
+
+// MarshalJSON menulis nominal sebagai angka JSON dalam rupiah
+func (u Uang) MarshalJSON() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalJSON menerima angka atau string JSON dalam rupiah, maksimal 2 angka di belakang koma
+func (u *Uang) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		return nil
+	}
+
+	nilai, err := ParseUang(s)
+	if err != nil {
+		return err
+	}
+	*u = nilai
+	return nil
+}
+
+// Scan membaca nilai decimal dari database
+func (u *Uang) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*u = 0
+		return nil
+	case []byte:
+		nilai, err := parseUang(string(v), true)
+		if err != nil {
+			return err
+		}
+		*u = nilai
+		return nil
+	case string:
+		nilai, err := parseUang(v, true)
+		if err != nil {
+			return err
+		}
+		*u = nilai
+		return nil
+	case int64:
+		*u = Rupiah(v)
+		return nil
+	case float64:
+		*u = UangDariFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("tipe %T tidak dapat dibaca sebagai Uang", value)
+	}
+}
+
+// Value menulis nominal sebagai string desimal agar kolom decimal menerima nilai eksak
+func (u Uang) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// BagiProporsional membagi total ke setiap bobot secara proporsional (largest remainder),
+// sehingga jumlah seluruh bagian selalu sama persis dengan total.
+// Bobot dapat berupa nominal dalam sen maupun persentase dalam basis poin.
+// Bobot nol atau negatif tidak mendapat bagian; jika seluruh bobot nol, setiap bagian bernilai nol.
+func BagiProporsional(total Uang, bobot []int64) []Uang {
+	bagian := make([]Uang, len(bobot))
+	if total < 0 {
+		for i, b := range BagiProporsional(-total, bobot) {
+			bagian[i] = -b
+		}
+		return bagian
+	}
+
+	totalBobot := new(big.Int)
+	for _, b := range bobot {
+		if b > 0 {
+			totalBobot.Add(totalBobot, big.NewInt(b))
+		}
+	}
+	if totalBobot.Sign() == 0 || total == 0 {
+		return bagian
+	}
+
+	// Bagian dasar adalah hasil bagi bulat; sisa sen dibagikan ke bobot dengan sisa pembagian terbesar
+	type sisaBagian struct {
+		indeks int
+		sisa   *big.Int
+	}
+	var sisaList []sisaBagian
+	var terbagi Uang
+	for i, b := range bobot {
+		if b <= 0 {
+			continue
+		}
+		hasil, sisa := new(big.Int).QuoRem(
+			new(big.Int).Mul(big.NewInt(int64(total)), big.NewInt(b)), totalBobot, new(big.Int))
+		bagian[i] = Uang(hasil.Int64())
+		terbagi += bagian[i]
+		sisaList = append(sisaList, sisaBagian{indeks: i, sisa: sisa})
+	}
+
+	sort.SliceStable(sisaList, func(i, j int) bool {
+		return sisaList[i].sisa.Cmp(sisaList[j].sisa) > 0
+	})
+	for i := 0; terbagi < total; i++ {
+		bagian[sisaList[i].indeks]++
+		terbagi++
+	}
+
+	return bagian
+}
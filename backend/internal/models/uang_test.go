@@ -0,0 +1,131 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUang(t *testing.T) {
+	tests := []struct {
+		input       string
+		harapan     Uang
+		shouldError bool
+	}{
+		{"1500", Rupiah(1500), false},
+		{"1500.5", Uang(150050), false},
+		{"1500.25", Uang(150025), false},
+		{"-20.05", Uang(-2005), false},
+		{".75", Uang(75), false},
+		{"100.000", Rupiah(100), false},
+		{"0.1", Uang(10), false},
+		{"100.005", 0, true},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"1.2x", 0, true},
+		{"--5", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			hasil, err := ParseUang(tt.input)
+			if tt.shouldError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.harapan, hasil)
+		})
+	}
+}
+
+func TestUang_PenjumlahanEksak(t *testing.T) {
+	// 0.1 + 0.1 + 0.1 == 0.3 tanpa toleransi
+	sepuluhSen, _ := ParseUang("0.1")
+	tigaPuluhSen, _ := ParseUang("0.3")
+	assert.Equal(t, tigaPuluhSen, sepuluhSen+sepuluhSen+sepuluhSen)
+}
+
+func TestUang_String(t *testing.T) {
+	assert.Equal(t, "1500.00", Rupiah(1500).String())
+	assert.Equal(t, "-1500.05", Uang(-150005).String())
+	assert.Equal(t, "0.07", Uang(7).String())
+}
+
+func TestUang_JSON(t *testing.T) {
+	var data struct {
+		Jumlah Uang `json:"jumlah"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"jumlah": 1500.25}`), &data))
+	assert.Equal(t, Uang(150025), data.Jumlah)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"jumlah": "75000"}`), &data))
+	assert.Equal(t, Rupiah(75000), data.Jumlah)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"jumlah": 10.125}`), &data))
+
+	hasil, err := json.Marshal(data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jumlah": 75000.00}`, string(hasil))
+}
+
+func TestUang_Scan(t *testing.T) {
+	var u Uang
+
+	require.NoError(t, u.Scan([]byte("1234.56")))
+	assert.Equal(t, Uang(123456), u)
+
+	// Hasil AVG dari database dibulatkan ke sen terdekat
+	require.NoError(t, u.Scan("100.3333333333"))
+	assert.Equal(t, Uang(10033), u)
+
+	require.NoError(t, u.Scan(int64(5)))
+	assert.Equal(t, Rupiah(5), u)
+
+	require.NoError(t, u.Scan(nil))
+	assert.Equal(t, Uang(0), u)
+}
+
+func TestUang_Persen(t *testing.T) {
+	assert.Equal(t, Rupiah(400000), Rupiah(1000000).Persen(40))
+	assert.Equal(t, Uang(3704), Uang(12345).Persen(30))
+	assert.Equal(t, Uang(-3704), Uang(-12345).Persen(30))
+	assert.Equal(t, Rupiah(125), Rupiah(1000).Persen(12.5))
+}
+
+func TestBagiProporsional(t *testing.T) {
+	t.Run("jumlah hasil selalu sama dengan total", func(t *testing.T) {
+		hasil := BagiProporsional(Rupiah(100), []int64{1, 1, 1})
+		assert.Equal(t, []Uang{3334, 3333, 3333}, hasil)
+	})
+
+	t.Run("alokasi persentase default dalam basis poin", func(t *testing.T) {
+		total := Uang(123456789)
+		hasil := BagiProporsional(total, []int64{4000, 2000, 2500, 500, 500, 500})
+
+		var jumlah Uang
+		for _, h := range hasil {
+			jumlah += h
+		}
+		assert.Equal(t, total, jumlah)
+		assert.Equal(t, Uang(49382716), hasil[0])
+	})
+
+	t.Run("total negatif dibagi dengan tanda yang sama", func(t *testing.T) {
+		hasil := BagiProporsional(-Rupiah(100), []int64{1, 1, 1})
+		assert.Equal(t, []Uang{-3334, -3333, -3333}, hasil)
+	})
+
+	t.Run("bobot nol tidak mendapat bagian", func(t *testing.T) {
+		hasil := BagiProporsional(Rupiah(50), []int64{0, 3, 1})
+		assert.Equal(t, []Uang{0, 3750, 1250}, hasil)
+	})
+
+	t.Run("tanpa bobot menghasilkan nol", func(t *testing.T) {
+		hasil := BagiProporsional(Rupiah(50), []int64{0, 0})
+		assert.Equal(t, []Uang{0, 0}, hasil)
+	})
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UnitUsaha merepresentasikan unit usaha koperasi serba usaha (toko, simpan pinjam,
+// fotokopi, pertanian, dll). Unit usaha adalah dimensi pada baris jurnal sehingga
+// laporan keuangan dan kontribusi SHU dapat disajikan per unit.
+type UnitUsaha struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	IDKoperasi        uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_koperasi_kode_unit_usaha" json:"idKoperasi" validate:"required"`
+	KodeUnit          string         `gorm:"type:varchar(20);not null;uniqueIndex:idx_koperasi_kode_unit_usaha" json:"kodeUnit" validate:"required"`
+	NamaUnit          string         `gorm:"type:varchar(255);not null" json:"namaUnit" validate:"required"`
+	Deskripsi         string         `gorm:"type:text" json:"deskripsi"`
+	StatusAktif       bool           `gorm:"type:boolean;default:true" json:"statusAktif"`
+	TanggalDibuat     time.Time      `gorm:"autoCreateTime" json:"tanggalDibuat"`
+	TanggalDiperbarui time.Time      `gorm:"autoUpdateTime" json:"tanggalDiperbarui"`
+	TanggalDihapus    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relasi
+	Koperasi Koperasi `gorm:"foreignKey:IDKoperasi;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// BeforeCreate hook untuk generate UUID
+func (u *UnitUsaha) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName menentukan nama tabel di database
+func (UnitUsaha) TableName() string {
+	return "unit_usaha"
+}
+
+// UnitUsahaResponse adalah response untuk API
+type UnitUsahaResponse struct {
+	ID          uuid.UUID `json:"id"`
+	KodeUnit    string    `json:"kodeUnit"`
+	NamaUnit    string    `json:"namaUnit"`
+	Deskripsi   string    `json:"deskripsi"`
+	StatusAktif bool      `json:"statusAktif"`
+}
+
+// ToResponse mengkonversi UnitUsaha ke UnitUsahaResponse
+func (u *UnitUsaha) ToResponse() UnitUsahaResponse {
+	return UnitUsahaResponse{
+		ID:          u.ID,
+		KodeUnit:    u.KodeUnit,
+		NamaUnit:    u.NamaUnit,
+		Deskripsi:   u.Deskripsi,
+		StatusAktif: u.StatusAktif,
+	}
+}
@@ -23,12 +23,13 @@ func NewAkunService(db *gorm.DB) *AkunService {
 
 // BuatAkunRequest adalah struktur request untuk membuat akun
 type BuatAkunRequest struct {
-	KodeAkun    string          `json:"kodeAkun" binding:"required"`
-	NamaAkun    string          `json:"namaAkun" binding:"required"`
-	TipeAkun    models.TipeAkun `json:"tipeAkun" binding:"required"`
-	IDInduk     *uuid.UUID      `json:"idInduk"`
-	NormalSaldo string          `json:"normalSaldo"` // Optional, auto-detect dari tipe
-	Deskripsi   string          `json:"deskripsi"`
+	KodeAkun        string                 `json:"kodeAkun" binding:"required"`
+	NamaAkun        string                 `json:"namaAkun" binding:"required"`
+	TipeAkun        models.TipeAkun        `json:"tipeAkun" binding:"required"`
+	IDInduk         *uuid.UUID             `json:"idInduk"`
+	NormalSaldo     string                 `json:"normalSaldo"` // Optional, auto-detect dari tipe
+	Deskripsi       string                 `json:"deskripsi"`
+	KategoriArusKas models.KategoriArusKas `json:"kategoriArusKas"` // Optional, KAS/OPERASI/INVESTASI/PENDANAAN
 }
 
 // BuatAkun membuat akun baru
@@ -49,6 +50,10 @@ func (s *AkunService) BuatAkun(idKoperasi uuid.UUID, req *BuatAkunRequest) (*mod
 		return nil, err
 	}
 
+	if req.KategoriArusKas != "" && !req.KategoriArusKas.IsValid() {
+		return nil, errors.New("kategori arus kas tidak valid")
+	}
+
 	// Cek apakah kode akun sudah ada
 	var jumlah int64
 	s.db.Model(&models.Akun{}).
@@ -70,14 +75,15 @@ func (s *AkunService) BuatAkun(idKoperasi uuid.UUID, req *BuatAkunRequest) (*mod
 
 	// Buat akun baru
 	akun := &models.Akun{
-		IDKoperasi:  idKoperasi,
-		KodeAkun:    req.KodeAkun,
-		NamaAkun:    req.NamaAkun,
-		TipeAkun:    req.TipeAkun,
-		IDInduk:     req.IDInduk,
-		NormalSaldo: req.NormalSaldo,
-		Deskripsi:   req.Deskripsi,
-		StatusAktif: true,
+		IDKoperasi:      idKoperasi,
+		KodeAkun:        req.KodeAkun,
+		NamaAkun:        req.NamaAkun,
+		TipeAkun:        req.TipeAkun,
+		IDInduk:         req.IDInduk,
+		NormalSaldo:     req.NormalSaldo,
+		Deskripsi:       req.Deskripsi,
+		StatusAktif:     true,
+		KategoriArusKas: req.KategoriArusKas,
 	}
 
 	// BeforeCreate hook akan set normal saldo jika kosong
@@ -154,9 +160,10 @@ func (s *AkunService) DapatkanAkunByKode(idKoperasi uuid.UUID, kodeAkun string)
 
 // PerbaruiAkunRequest adalah struktur request untuk update akun
 type PerbaruiAkunRequest struct {
-	NamaAkun    string `json:"namaAkun"`
-	Deskripsi   string `json:"deskripsi"`
-	StatusAktif *bool  `json:"statusAktif"`
+	NamaAkun        string                 `json:"namaAkun"`
+	Deskripsi       string                 `json:"deskripsi"`
+	StatusAktif     *bool                  `json:"statusAktif"`
+	KategoriArusKas models.KategoriArusKas `json:"kategoriArusKas"`
 }
 
 // PerbaruiAkun mengupdate data akun
@@ -175,6 +182,10 @@ func (s *AkunService) PerbaruiAkun(idKoperasi, id uuid.UUID, req *PerbaruiAkunRe
 		return nil, err
 	}
 
+	if req.KategoriArusKas != "" && !req.KategoriArusKas.IsValid() {
+		return nil, errors.New("kategori arus kas tidak valid")
+	}
+
 	// Cek apakah akun ada DAN milik koperasi yang benar (multi-tenant validation)
 	var akun models.Akun
 	err := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&akun).Error
@@ -193,8 +204,15 @@ func (s *AkunService) PerbaruiAkun(idKoperasi, id uuid.UUID, req *PerbaruiAkunRe
 		akun.Deskripsi = req.Deskripsi
 	}
 	if req.StatusAktif != nil {
+		// Akun yang dipetakan di aturan posting tidak boleh dinonaktifkan
+		if !*req.StatusAktif && akun.StatusAktif && s.dipakaiAturanPosting(akun.ID) {
+			return nil, errors.New("tidak dapat menonaktifkan akun yang dipakai aturan posting")
+		}
 		akun.StatusAktif = *req.StatusAktif
 	}
+	if req.KategoriArusKas != "" {
+		akun.KategoriArusKas = req.KategoriArusKas
+	}
 
 	err = s.db.Save(&akun).Error
 	if err != nil {
@@ -233,6 +251,10 @@ func (s *AkunService) HapusAkun(idKoperasi, id uuid.UUID) error {
 		return errors.New("tidak dapat menghapus akun yang memiliki sub-akun")
 	}
 
+	if s.dipakaiAturanPosting(id) {
+		return errors.New("tidak dapat menghapus akun yang dipakai aturan posting")
+	}
+
 	// Soft delete
 	err = s.db.Delete(&akun).Error
 	if err != nil {
@@ -242,8 +264,17 @@ func (s *AkunService) HapusAkun(idKoperasi, id uuid.UUID) error {
 	return nil
 }
 
-// HitungSaldoAkun menghitung saldo akun sampai tanggal tertentu
-func (s *AkunService) HitungSaldoAkun(idAkun uuid.UUID, tanggalAkhir string) (float64, error) {
+// dipakaiAturanPosting memeriksa apakah akun dipetakan di aturan posting koperasi
+func (s *AkunService) dipakaiAturanPosting(idAkun uuid.UUID) bool {
+	var jumlah int64
+	s.db.Model(&models.AturanPosting{}).Where("id_akun = ?", idAkun).Count(&jumlah)
+	return jumlah > 0
+}
+
+// HitungSaldoAkun menghitung saldo akun sampai tanggal tertentu.
+// Mutasi bulan-bulan sebelumnya dibaca dari snapshot saldo bulanan, sehingga hanya jurnal
+// sejak awal bulan tanggalAkhir yang perlu dijumlahkan.
+func (s *AkunService) HitungSaldoAkun(idAkun uuid.UUID, tanggalAkhir string) (models.Uang, error) {
 	// Dapatkan akun untuk mengetahui normal saldo
 	var akun models.Akun
 	err := s.db.Where("id = ?", idAkun).First(&akun).Error
@@ -251,29 +282,14 @@ func (s *AkunService) HitungSaldoAkun(idAkun uuid.UUID, tanggalAkhir string) (fl
 		return 0, errors.New("akun tidak ditemukan")
 	}
 
-	// Hitung total debit dan kredit
-	type SaldoResult struct {
-		TotalDebit  float64
-		TotalKredit float64
-	}
-
-	var hasil SaldoResult
-	query := s.db.Model(&models.BarisTransaksi{}).
-		Select("COALESCE(SUM(jumlah_debit), 0) as total_debit, COALESCE(SUM(jumlah_kredit), 0) as total_kredit").
-		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
-		Where("baris_transaksi.id_akun = ?", idAkun)
-
-	if tanggalAkhir != "" {
-		query = query.Where("transaksi.tanggal_transaksi <= ?", tanggalAkhir)
-	}
-
-	err = query.Scan(&hasil).Error
+	mutasi, err := hitungTotalMutasiAkun(s.db, akun.IDKoperasi, &akun.ID, tanggalAkhir, nil)
 	if err != nil {
-		return 0, errors.New("gagal menghitung saldo")
+		return 0, err
 	}
+	hasil := mutasi[akun.ID]
 
 	// Hitung saldo berdasarkan normal saldo
-	var saldo float64
+	var saldo models.Uang
 	if akun.NormalSaldo == "DEBIT" {
 		saldo = hasil.TotalDebit - hasil.TotalKredit
 	} else {
@@ -283,58 +299,41 @@ func (s *AkunService) HitungSaldoAkun(idAkun uuid.UUID, tanggalAkhir string) (fl
 	return saldo, nil
 }
 
-// InisialisasiCOADefault membuat Chart of Accounts default untuk koperasi baru
+// InisialisasiCOADefault membuat Chart of Accounts default (template KSU) untuk koperasi baru
 func (s *AkunService) InisialisasiCOADefault(idKoperasi uuid.UUID) error {
-	// COA Default untuk Koperasi Indonesia
-	coaDefault := []models.Akun{
-		// ASET
-		{IDKoperasi: idKoperasi, KodeAkun: "1000", NamaAkun: "ASET", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "1100", NamaAkun: "Aset Lancar", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "1101", NamaAkun: "Kas", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "1102", NamaAkun: "Bank", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "1200", NamaAkun: "Piutang", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "1201", NamaAkun: "Piutang Anggota", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "1300", NamaAkun: "Persediaan", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "1301", NamaAkun: "Persediaan Barang Dagangan", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT"},
-
-		// KEWAJIBAN
-		{IDKoperasi: idKoperasi, KodeAkun: "2000", NamaAkun: "KEWAJIBAN", TipeAkun: models.AkunKewajiban, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "2100", NamaAkun: "Kewajiban Jangka Pendek", TipeAkun: models.AkunKewajiban, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "2101", NamaAkun: "Hutang Usaha", TipeAkun: models.AkunKewajiban, NormalSaldo: "KREDIT"},
-
-		// MODAL
-		{IDKoperasi: idKoperasi, KodeAkun: "3000", NamaAkun: "MODAL", TipeAkun: models.AkunModal, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "3100", NamaAkun: "Modal Koperasi", TipeAkun: models.AkunModal, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "3101", NamaAkun: "Simpanan Pokok", TipeAkun: models.AkunModal, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "3102", NamaAkun: "Simpanan Wajib", TipeAkun: models.AkunModal, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "3103", NamaAkun: "Simpanan Sukarela", TipeAkun: models.AkunModal, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "3200", NamaAkun: "Sisa Hasil Usaha (SHU)", TipeAkun: models.AkunModal, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "3201", NamaAkun: "SHU Tahun Berjalan", TipeAkun: models.AkunModal, NormalSaldo: "KREDIT"},
-
-		// PENDAPATAN
-		{IDKoperasi: idKoperasi, KodeAkun: "4000", NamaAkun: "PENDAPATAN", TipeAkun: models.AkunPendapatan, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "4100", NamaAkun: "Pendapatan Usaha", TipeAkun: models.AkunPendapatan, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "4101", NamaAkun: "Penjualan", TipeAkun: models.AkunPendapatan, NormalSaldo: "KREDIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "4200", NamaAkun: "Pendapatan Lain-lain", TipeAkun: models.AkunPendapatan, NormalSaldo: "KREDIT"},
-
-		// BEBAN
-		{IDKoperasi: idKoperasi, KodeAkun: "5000", NamaAkun: "BEBAN", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "5100", NamaAkun: "Beban Operasional", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "5101", NamaAkun: "Beban Gaji", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "5102", NamaAkun: "Beban Listrik", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "5103", NamaAkun: "Beban Air", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "5104", NamaAkun: "Beban Telepon & Internet", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "5200", NamaAkun: "Harga Pokok Penjualan", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
-		{IDKoperasi: idKoperasi, KodeAkun: "5201", NamaAkun: "HPP", TipeAkun: models.AkunBeban, NormalSaldo: "DEBIT"},
+	return s.InisialisasiCOA(idKoperasi, models.JenisKoperasiSerbaUsaha)
+}
+
+// InisialisasiCOA membuat Chart of Accounts dari template sesuai jenis koperasi.
+// Semua akun dibuat dalam satu transaksi; kode akun yang sudah ada membatalkan seluruh inisialisasi.
+func (s *AkunService) InisialisasiCOA(idKoperasi uuid.UUID, jenis models.JenisKoperasi) error {
+	template, ok := cariTemplateCOA(jenis)
+	if !ok {
+		return fmt.Errorf("template COA untuk jenis koperasi %s tidak tersedia", jenis)
 	}
 
 	// Insert semua akun dalam satu transaction
 	err := s.db.Transaction(func(tx *gorm.DB) error {
-		for _, akun := range coaDefault {
-			akun.StatusAktif = true
+		idByKode := make(map[string]uuid.UUID, len(template.Akun))
+		for _, baris := range template.Akun {
+			akun := models.Akun{
+				IDKoperasi:      idKoperasi,
+				KodeAkun:        baris.KodeAkun,
+				NamaAkun:        baris.NamaAkun,
+				TipeAkun:        baris.TipeAkun,
+				NormalSaldo:     baris.NormalSaldo,
+				Deskripsi:       baris.Deskripsi,
+				StatusAktif:     true,
+				KategoriArusKas: baris.KategoriArusKas,
+			}
+			if baris.KodeInduk != "" {
+				idInduk := idByKode[baris.KodeInduk]
+				akun.IDInduk = &idInduk
+			}
 			if err := tx.Create(&akun).Error; err != nil {
 				return err
 			}
+			idByKode[akun.KodeAkun] = akun.ID
 		}
 		return nil
 	})
@@ -346,6 +345,11 @@ func (s *AkunService) InisialisasiCOADefault(idKoperasi uuid.UUID) error {
 	return nil
 }
 
+// DaftarTemplateCOA mengembalikan template COA yang dapat dipilih saat inisialisasi
+func (s *AkunService) DaftarTemplateCOA() []TemplateCOA {
+	return daftarTemplateCOA
+}
+
 // DapatkanHierarkiAkun mengambil struktur hierarki COA
 func (s *AkunService) DapatkanHierarkiAkun(idKoperasi uuid.UUID) ([]models.AkunResponse, error) {
 	var akunList []models.Akun
@@ -416,19 +420,19 @@ func (s *AkunService) GetBukuBesar(idKoperasi, idAkun uuid.UUID, tanggalMulai, t
 
 	// Query baris transaksi untuk akun ini
 	type BukuBesarEntry struct {
-		TanggalTransaksi time.Time `json:"tanggalTransaksi"`
-		NomorJurnal      string    `json:"nomorJurnal"`
-		Deskripsi        string    `json:"deskripsi"`
-		JumlahDebit      float64   `json:"jumlahDebit"`
-		JumlahKredit     float64   `json:"jumlahKredit"`
-		Saldo            float64   `json:"saldo"`
+		TanggalTransaksi time.Time   `json:"tanggalTransaksi"`
+		NomorJurnal      string      `json:"nomorJurnal"`
+		Deskripsi        string      `json:"deskripsi"`
+		JumlahDebit      models.Uang `json:"jumlahDebit"`
+		JumlahKredit     models.Uang `json:"jumlahKredit"`
+		Saldo            models.Uang `json:"saldo"`
 	}
 
 	var entries []BukuBesarEntry
 	query := s.db.Table("baris_transaksi").
 		Select("transaksi.tanggal_transaksi, transaksi.nomor_jurnal, transaksi.deskripsi, baris_transaksi.jumlah_debit, baris_transaksi.jumlah_kredit").
 		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
-		Where("baris_transaksi.id_akun = ? AND transaksi.id_koperasi = ?", idAkun, idKoperasi)
+		Where("baris_transaksi.id_akun = ? AND transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idAkun, idKoperasi)
 
 	if tanggalMulai != "" {
 		query = query.Where("transaksi.tanggal_transaksi >= ?", tanggalMulai)
@@ -443,7 +447,7 @@ func (s *AkunService) GetBukuBesar(idKoperasi, idAkun uuid.UUID, tanggalMulai, t
 	}
 
 	// Calculate running balance
-	saldo := 0.0
+	var saldo models.Uang
 	for i := range entries {
 		if akun.NormalSaldo == "DEBIT" {
 			saldo += entries[i].JumlahDebit - entries[i].JumlahKredit
@@ -29,6 +29,9 @@ func setupAkunTestDB(t *testing.T) *gorm.DB {
 		&models.Koperasi{},
 		&models.Akun{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 		&models.Transaksi{},
 	)
 	if err != nil {
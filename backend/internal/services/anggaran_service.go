@@ -0,0 +1,650 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// jumlahBulanAnggaran adalah jumlah bulan dalam satu tahun buku anggaran
+const jumlahBulanAnggaran = 12
+
+// AnggaranService menangani Rencana Anggaran Pendapatan dan Belanja (RAPB) dan realisasinya
+type AnggaranService struct {
+	db             *gorm.DB
+	laporanService *LaporanService
+}
+
+// NewAnggaranService membuat instance baru AnggaranService
+func NewAnggaranService(db *gorm.DB, laporanService *LaporanService) *AnggaranService {
+	return &AnggaranService{
+		db:             db,
+		laporanService: laporanService,
+	}
+}
+
+// BarisAnggaranRequest adalah anggaran satu akun dalam request penyimpanan RAPB.
+// Isi Bulanan (12 nilai, dimulai dari bulan pertama tahun buku) untuk fase bulanan yang tidak rata;
+// jika kosong, Total dibagi rata ke 12 bulan.
+type BarisAnggaranRequest struct {
+	KodeAkun string        `json:"kodeAkun" binding:"required"`
+	Total    models.Uang   `json:"total"`
+	Bulanan  []models.Uang `json:"bulanan"`
+}
+
+// SimpanAnggaranRequest adalah struktur request untuk menyimpan RAPB satu tahun buku.
+// Seluruh baris anggaran sebelumnya diganti dengan baris pada request.
+type SimpanAnggaranRequest struct {
+	Keterangan string                 `json:"keterangan"`
+	Baris      []BarisAnggaranRequest `json:"baris" binding:"required,dive"`
+}
+
+// SalinAnggaranRequest adalah struktur request untuk menyalin RAPB tahun buku sebelumnya
+type SalinAnggaranRequest struct {
+	PersentasePertumbuhan float64 `json:"persentasePertumbuhan"` // Boleh negatif, misalnya -10 untuk penurunan 10%
+	Keterangan            string  `json:"keterangan"`
+}
+
+// SahkanAnggaranRequest adalah struktur request untuk mengesahkan RAPB
+type SahkanAnggaranRequest struct {
+	TanggalDisahkan time.Time `json:"tanggalDisahkan" binding:"required"` // Tanggal RAT
+}
+
+// AnggaranAkun adalah anggaran satu akun beserta fase bulanannya
+type AnggaranAkun struct {
+	IDAkun   uuid.UUID       `json:"idAkun"`
+	KodeAkun string          `json:"kodeAkun"`
+	NamaAkun string          `json:"namaAkun"`
+	TipeAkun models.TipeAkun `json:"tipeAkun"`
+	Bulanan  []models.Uang   `json:"bulanan"`
+	Total    models.Uang     `json:"total"`
+}
+
+// AnggaranResponse adalah RAPB satu tahun buku lengkap dengan anggaran per akun
+type AnggaranResponse struct {
+	ID              uuid.UUID             `json:"id"`
+	TahunBuku       int                   `json:"tahunBuku"`
+	PeriodeMulai    time.Time             `json:"periodeMulai"`
+	PeriodeAkhir    time.Time             `json:"periodeAkhir"`
+	Keterangan      string                `json:"keterangan"`
+	Status          models.StatusAnggaran `json:"status"`
+	TanggalDisahkan *time.Time            `json:"tanggalDisahkan"`
+	Pendapatan      []AnggaranAkun        `json:"pendapatan"`
+	TotalPendapatan models.Uang           `json:"totalPendapatan"`
+	Beban           []AnggaranAkun        `json:"beban"`
+	TotalBeban      models.Uang           `json:"totalBeban"`
+	SHUDianggarkan  models.Uang           `json:"shuDianggarkan"`
+}
+
+// ItemRealisasiAnggaran membandingkan anggaran dan realisasi satu akun (atau total satu kelompok).
+// Selisih = Realisasi - Anggaran. Persentase bernilai null jika anggarannya nol.
+type ItemRealisasiAnggaran struct {
+	KodeAkun            string      `json:"kodeAkun,omitempty"`
+	NamaAkun            string      `json:"namaAkun"`
+	AnggaranSetahun     models.Uang `json:"anggaranSetahun"`
+	Anggaran            models.Uang `json:"anggaran"` // Anggaran kumulatif sampai bulan laporan
+	Realisasi           models.Uang `json:"realisasi"`
+	Selisih             models.Uang `json:"selisih"`
+	PersentaseRealisasi *float64    `json:"persentaseRealisasi"`
+	PersentaseSelisih   *float64    `json:"persentaseSelisih"`
+	Menguntungkan       bool        `json:"menguntungkan"` // Pendapatan di atas atau beban di bawah anggaran
+}
+
+// LaporanRealisasiAnggaran adalah laporan perbandingan RAPB dengan laba rugi aktual
+type LaporanRealisasiAnggaran struct {
+	TahunBuku       int                     `json:"tahunBuku"`
+	SampaiBulan     int                     `json:"sampaiBulan"`
+	PeriodeMulai    time.Time               `json:"periodeMulai"`
+	PeriodeAkhir    time.Time               `json:"periodeAkhir"`
+	StatusAnggaran  models.StatusAnggaran   `json:"statusAnggaran"`
+	Pendapatan      []ItemRealisasiAnggaran `json:"pendapatan"`
+	TotalPendapatan ItemRealisasiAnggaran   `json:"totalPendapatan"`
+	Beban           []ItemRealisasiAnggaran `json:"beban"`
+	TotalBeban      ItemRealisasiAnggaran   `json:"totalBeban"`
+	SHU             ItemRealisasiAnggaran   `json:"shu"`
+}
+
+// DaftarAnggaran mengambil RAPB seluruh tahun buku koperasi (tanpa rincian akun)
+func (s *AnggaranService) DaftarAnggaran(idKoperasi uuid.UUID) ([]models.Anggaran, error) {
+	var anggaranList []models.Anggaran
+	err := s.db.Where("id_koperasi = ?", idKoperasi).
+		Order("tahun_buku DESC").
+		Find(&anggaranList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar anggaran")
+	}
+
+	return anggaranList, nil
+}
+
+// DapatkanAnggaran mengambil RAPB satu tahun buku beserta anggaran bulanan per akun
+func (s *AnggaranService) DapatkanAnggaran(idKoperasi uuid.UUID, tahunBuku int) (*AnggaranResponse, error) {
+	anggaran, err := s.ambilAnggaran(s.db, idKoperasi, tahunBuku)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.susunAnggaranResponse(anggaran)
+}
+
+// SimpanAnggaran membuat atau mengganti RAPB satu tahun buku. RAPB yang sudah disahkan tidak dapat diubah.
+func (s *AnggaranService) SimpanAnggaran(idKoperasi, idPengguna uuid.UUID, tahunBuku int, req *SimpanAnggaranRequest) (*AnggaranResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksOpsional(req.Keterangan, "keterangan", 500); err != nil {
+		return nil, err
+	}
+	if len(req.Baris) == 0 {
+		return nil, errors.New("anggaran minimal harus memiliki satu akun")
+	}
+
+	var hasil *models.Anggaran
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		anggaran, err := s.siapkanAnggaranDraft(tx, idKoperasi, idPengguna, tahunBuku, req.Keterangan)
+		if err != nil {
+			return err
+		}
+
+		barisList, err := s.susunBarisAnggaran(tx, idKoperasi, req.Baris)
+		if err != nil {
+			return err
+		}
+
+		if err := s.gantiBarisAnggaran(tx, anggaran.ID, barisList); err != nil {
+			return err
+		}
+
+		hasil = anggaran
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanAnggaran(idKoperasi, hasil.TahunBuku)
+}
+
+// SalinAnggaranTahunLalu membuat RAPB tahun buku dari RAPB tahun buku sebelumnya,
+// dengan setiap anggaran bulanan dinaikkan (atau diturunkan) sebesar persentase pertumbuhan.
+// RAPB tahun buku tujuan tidak boleh sudah ada.
+func (s *AnggaranService) SalinAnggaranTahunLalu(idKoperasi, idPengguna uuid.UUID, tahunBuku int, req *SalinAnggaranRequest) (*AnggaranResponse, error) {
+	if req.PersentasePertumbuhan <= -100 {
+		return nil, errors.New("persentase pertumbuhan harus lebih dari -100")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var jumlah int64
+		tx.Model(&models.Anggaran{}).
+			Where("id_koperasi = ? AND tahun_buku = ?", idKoperasi, tahunBuku).
+			Count(&jumlah)
+		if jumlah > 0 {
+			return fmt.Errorf("anggaran tahun buku %d sudah ada", tahunBuku)
+		}
+
+		sumber, err := s.ambilAnggaran(tx, idKoperasi, tahunBuku-1)
+		if err != nil {
+			return err
+		}
+
+		keterangan := req.Keterangan
+		if keterangan == "" {
+			keterangan = fmt.Sprintf("Disalin dari anggaran %d dengan pertumbuhan %.2f%%", sumber.TahunBuku, req.PersentasePertumbuhan)
+		}
+
+		anggaran, err := s.siapkanAnggaranDraft(tx, idKoperasi, idPengguna, tahunBuku, keterangan)
+		if err != nil {
+			return err
+		}
+
+		// Akun yang sudah dihapus dari bagan akun tidak ikut disalin
+		var barisSumber []models.BarisAnggaran
+		err = tx.Joins("JOIN akun ON akun.id = baris_anggaran.id_akun AND akun.tanggal_dihapus IS NULL").
+			Where("baris_anggaran.id_anggaran = ?", sumber.ID).
+			Find(&barisSumber).Error
+		if err != nil {
+			return errors.New("gagal mengambil anggaran tahun lalu")
+		}
+
+		barisList := make([]models.BarisAnggaran, 0, len(barisSumber))
+		for _, baris := range barisSumber {
+			barisList = append(barisList, models.BarisAnggaran{
+				IDAkun:  baris.IDAkun,
+				BulanKe: baris.BulanKe,
+				Jumlah:  baris.Jumlah.Persen(100 + req.PersentasePertumbuhan),
+			})
+		}
+
+		return s.gantiBarisAnggaran(tx, anggaran.ID, barisList)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanAnggaran(idKoperasi, tahunBuku)
+}
+
+// SahkanAnggaran mengunci RAPB setelah disahkan dalam RAT sehingga tidak dapat diubah lagi
+func (s *AnggaranService) SahkanAnggaran(idKoperasi, idPengguna uuid.UUID, tahunBuku int, req *SahkanAnggaranRequest) (*AnggaranResponse, error) {
+	anggaran, err := s.ambilAnggaran(s.db, idKoperasi, tahunBuku)
+	if err != nil {
+		return nil, err
+	}
+	if anggaran.Status == models.StatusAnggaranDisahkan {
+		return nil, fmt.Errorf("anggaran tahun buku %d sudah disahkan", tahunBuku)
+	}
+
+	var jumlahBaris int64
+	s.db.Model(&models.BarisAnggaran{}).Where("id_anggaran = ?", anggaran.ID).Count(&jumlahBaris)
+	if jumlahBaris == 0 {
+		return nil, errors.New("anggaran tanpa akun tidak dapat disahkan")
+	}
+
+	err = s.db.Model(anggaran).Updates(map[string]interface{}{
+		"status":           models.StatusAnggaranDisahkan,
+		"tanggal_disahkan": req.TanggalDisahkan,
+		"disahkan_oleh":    idPengguna,
+		"diperbarui_oleh":  idPengguna,
+	}).Error
+	if err != nil {
+		return nil, errors.New("gagal mengesahkan anggaran")
+	}
+
+	return s.DapatkanAnggaran(idKoperasi, tahunBuku)
+}
+
+// HapusAnggaran menghapus RAPB yang masih draft beserta seluruh barisnya
+func (s *AnggaranService) HapusAnggaran(idKoperasi uuid.UUID, tahunBuku int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		anggaran, err := s.ambilAnggaran(tx, idKoperasi, tahunBuku)
+		if err != nil {
+			return err
+		}
+		if anggaran.Status == models.StatusAnggaranDisahkan {
+			return errors.New("anggaran yang sudah disahkan tidak dapat dihapus")
+		}
+
+		if err := tx.Where("id_anggaran = ?", anggaran.ID).Delete(&models.BarisAnggaran{}).Error; err != nil {
+			return errors.New("gagal menghapus baris anggaran")
+		}
+		if err := tx.Delete(anggaran).Error; err != nil {
+			return errors.New("gagal menghapus anggaran")
+		}
+		return nil
+	})
+}
+
+// LaporanRealisasiAnggaran membandingkan RAPB dengan laba rugi aktual sejak awal tahun buku
+// sampai akhir bulan ke-sampaiBulan tahun buku (1-12, 0 berarti satu tahun buku penuh).
+// Angka realisasi diambil dari GenerateLaporanLabaRugi sehingga selalu sama dengan laporan laba rugi.
+func (s *AnggaranService) LaporanRealisasiAnggaran(idKoperasi uuid.UUID, tahunBuku, sampaiBulan int) (*LaporanRealisasiAnggaran, error) {
+	if sampaiBulan == 0 {
+		sampaiBulan = jumlahBulanAnggaran
+	}
+	if sampaiBulan < 1 || sampaiBulan > jumlahBulanAnggaran {
+		return nil, errors.New("bulan laporan harus antara 1 dan 12")
+	}
+
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", idKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+
+	anggaran, err := s.ambilAnggaran(s.db, idKoperasi, tahunBuku)
+	if err != nil {
+		return nil, err
+	}
+
+	periodeMulai, _ := RentangTahunBuku(koperasi.TahunBukuMulai, tahunBuku)
+	periodeAkhir := periodeMulai.AddDate(0, sampaiBulan, -1)
+
+	labaRugi, err := s.laporanService.GenerateLaporanLabaRugi(idKoperasi,
+		periodeMulai.Format("2006-01-02"), periodeAkhir.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	var barisList []struct {
+		KodeAkun string
+		NamaAkun string
+		TipeAkun models.TipeAkun
+		BulanKe  int
+		Jumlah   models.Uang
+	}
+	err = s.db.Table("baris_anggaran").
+		Select("akun.kode_akun, akun.nama_akun, akun.tipe_akun, baris_anggaran.bulan_ke, baris_anggaran.jumlah").
+		Joins("JOIN akun ON akun.id = baris_anggaran.id_akun").
+		Where("baris_anggaran.id_anggaran = ?", anggaran.ID).
+		Scan(&barisList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil baris anggaran")
+	}
+
+	// Gabungkan akun yang dianggarkan dengan akun yang memiliki realisasi
+	itemMap := map[string]*ItemRealisasiAnggaran{}
+	tipeAkun := map[string]models.TipeAkun{}
+	ambilItem := func(kodeAkun, namaAkun string, tipe models.TipeAkun) *ItemRealisasiAnggaran {
+		item, ada := itemMap[kodeAkun]
+		if !ada {
+			item = &ItemRealisasiAnggaran{KodeAkun: kodeAkun, NamaAkun: namaAkun}
+			itemMap[kodeAkun] = item
+			tipeAkun[kodeAkun] = tipe
+		}
+		return item
+	}
+
+	for _, baris := range barisList {
+		item := ambilItem(baris.KodeAkun, baris.NamaAkun, baris.TipeAkun)
+		item.AnggaranSetahun += baris.Jumlah
+		if baris.BulanKe <= sampaiBulan {
+			item.Anggaran += baris.Jumlah
+		}
+	}
+	for _, realisasi := range labaRugi.Pendapatan {
+		ambilItem(realisasi.KodeAkun, realisasi.NamaAkun, models.AkunPendapatan).Realisasi = realisasi.Saldo
+	}
+	for _, realisasi := range labaRugi.Beban {
+		ambilItem(realisasi.KodeAkun, realisasi.NamaAkun, models.AkunBeban).Realisasi = realisasi.Saldo
+	}
+
+	kodeList := make([]string, 0, len(itemMap))
+	for kodeAkun := range itemMap {
+		kodeList = append(kodeList, kodeAkun)
+	}
+	sort.Strings(kodeList)
+
+	laporan := &LaporanRealisasiAnggaran{
+		TahunBuku:       tahunBuku,
+		SampaiBulan:     sampaiBulan,
+		PeriodeMulai:    periodeMulai,
+		PeriodeAkhir:    periodeAkhir,
+		StatusAnggaran:  anggaran.Status,
+		Pendapatan:      []ItemRealisasiAnggaran{},
+		TotalPendapatan: ItemRealisasiAnggaran{NamaAkun: "Total Pendapatan"},
+		Beban:           []ItemRealisasiAnggaran{},
+		TotalBeban:      ItemRealisasiAnggaran{NamaAkun: "Total Beban"},
+		SHU:             ItemRealisasiAnggaran{NamaAkun: "Sisa Hasil Usaha"},
+	}
+
+	for _, kodeAkun := range kodeList {
+		item := itemMap[kodeAkun]
+		if tipeAkun[kodeAkun] == models.AkunPendapatan {
+			hitungSelisihRealisasi(item, true)
+			laporan.Pendapatan = append(laporan.Pendapatan, *item)
+			tambahkanRealisasi(&laporan.TotalPendapatan, item)
+		} else {
+			hitungSelisihRealisasi(item, false)
+			laporan.Beban = append(laporan.Beban, *item)
+			tambahkanRealisasi(&laporan.TotalBeban, item)
+		}
+	}
+	hitungSelisihRealisasi(&laporan.TotalPendapatan, true)
+	hitungSelisihRealisasi(&laporan.TotalBeban, false)
+
+	laporan.SHU.AnggaranSetahun = laporan.TotalPendapatan.AnggaranSetahun - laporan.TotalBeban.AnggaranSetahun
+	laporan.SHU.Anggaran = laporan.TotalPendapatan.Anggaran - laporan.TotalBeban.Anggaran
+	laporan.SHU.Realisasi = labaRugi.LabaRugiBersih
+	hitungSelisihRealisasi(&laporan.SHU, true)
+
+	return laporan, nil
+}
+
+// tambahkanRealisasi menjumlahkan anggaran dan realisasi item ke total kelompoknya
+func tambahkanRealisasi(total, item *ItemRealisasiAnggaran) {
+	total.AnggaranSetahun += item.AnggaranSetahun
+	total.Anggaran += item.Anggaran
+	total.Realisasi += item.Realisasi
+}
+
+// hitungSelisihRealisasi mengisi selisih dan persentase item. Untuk pendapatan (naikBaik=true)
+// realisasi di atas anggaran menguntungkan; untuk beban sebaliknya.
+func hitungSelisihRealisasi(item *ItemRealisasiAnggaran, naikBaik bool) {
+	item.Selisih = item.Realisasi - item.Anggaran
+	if naikBaik {
+		item.Menguntungkan = item.Selisih >= 0
+	} else {
+		item.Menguntungkan = item.Selisih <= 0
+	}
+
+	item.PersentaseRealisasi = nil
+	item.PersentaseSelisih = nil
+	if item.Anggaran == 0 {
+		return
+	}
+	persenRealisasi := bulatkanPersen(item.Realisasi.Float64() / item.Anggaran.Float64() * 100)
+	persenSelisih := bulatkanPersen(item.Selisih.Float64() / item.Anggaran.Abs().Float64() * 100)
+	item.PersentaseRealisasi = &persenRealisasi
+	item.PersentaseSelisih = &persenSelisih
+}
+
+// bulatkanPersen membulatkan persentase ke 2 angka desimal
+func bulatkanPersen(persen float64) float64 {
+	return math.Round(persen*100) / 100
+}
+
+// ambilAnggaran mengambil header RAPB satu tahun buku
+func (s *AnggaranService) ambilAnggaran(tx *gorm.DB, idKoperasi uuid.UUID, tahunBuku int) (*models.Anggaran, error) {
+	var anggaran models.Anggaran
+	err := tx.Where("id_koperasi = ? AND tahun_buku = ?", idKoperasi, tahunBuku).First(&anggaran).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("anggaran tahun buku %d tidak ditemukan", tahunBuku)
+		}
+		return nil, errors.New("gagal mengambil anggaran")
+	}
+
+	return &anggaran, nil
+}
+
+// siapkanAnggaranDraft mengambil atau membuat header RAPB dan memastikan statusnya masih draft
+func (s *AnggaranService) siapkanAnggaranDraft(tx *gorm.DB, idKoperasi, idPengguna uuid.UUID, tahunBuku int, keterangan string) (*models.Anggaran, error) {
+	if tahunBuku < 2000 || tahunBuku > 2100 {
+		return nil, errors.New("tahun buku tidak valid")
+	}
+
+	var anggaran models.Anggaran
+	err := tx.Where("id_koperasi = ? AND tahun_buku = ?", idKoperasi, tahunBuku).First(&anggaran).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("gagal mengambil anggaran")
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		anggaran = models.Anggaran{
+			IDKoperasi:     idKoperasi,
+			TahunBuku:      tahunBuku,
+			Keterangan:     keterangan,
+			Status:         models.StatusAnggaranDraft,
+			DibuatOleh:     idPengguna,
+			DiperbaruiOleh: idPengguna,
+		}
+		if err := tx.Create(&anggaran).Error; err != nil {
+			return nil, errors.New("gagal membuat anggaran")
+		}
+		return &anggaran, nil
+	}
+
+	if anggaran.Status == models.StatusAnggaranDisahkan {
+		return nil, fmt.Errorf("anggaran tahun buku %d sudah disahkan dan tidak dapat diubah", tahunBuku)
+	}
+
+	anggaran.Keterangan = keterangan
+	anggaran.DiperbaruiOleh = idPengguna
+	if err := tx.Save(&anggaran).Error; err != nil {
+		return nil, errors.New("gagal memperbarui anggaran")
+	}
+
+	return &anggaran, nil
+}
+
+// susunBarisAnggaran memvalidasi request dan menjabarkannya menjadi baris anggaran bulanan
+func (s *AnggaranService) susunBarisAnggaran(tx *gorm.DB, idKoperasi uuid.UUID, barisReq []BarisAnggaranRequest) ([]models.BarisAnggaran, error) {
+	validator := validasi.Baru()
+	sudahAda := map[string]bool{}
+	var barisList []models.BarisAnggaran
+
+	for i, baris := range barisReq {
+		if err := validator.KodeAkun(baris.KodeAkun); err != nil {
+			return nil, fmt.Errorf("baris %d: %v", i+1, err)
+		}
+		if sudahAda[baris.KodeAkun] {
+			return nil, fmt.Errorf("baris %d: akun %s dianggarkan lebih dari sekali", i+1, baris.KodeAkun)
+		}
+		sudahAda[baris.KodeAkun] = true
+
+		var akun models.Akun
+		err := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, baris.KodeAkun).First(&akun).Error
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: akun %s tidak ditemukan", i+1, baris.KodeAkun)
+		}
+		if akun.TipeAkun != models.AkunPendapatan && akun.TipeAkun != models.AkunBeban {
+			return nil, fmt.Errorf("baris %d: hanya akun pendapatan dan beban yang dapat dianggarkan", i+1)
+		}
+		if !akun.StatusAktif {
+			return nil, fmt.Errorf("baris %d: akun %s tidak aktif", i+1, baris.KodeAkun)
+		}
+
+		bulanan, err := jabarkanAnggaranBulanan(baris)
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: %v", i+1, err)
+		}
+
+		for bulanKe, jumlah := range bulanan {
+			barisList = append(barisList, models.BarisAnggaran{
+				IDAkun:  akun.ID,
+				BulanKe: bulanKe + 1,
+				Jumlah:  jumlah,
+			})
+		}
+	}
+
+	return barisList, nil
+}
+
+// jabarkanAnggaranBulanan menghasilkan 12 nilai anggaran bulanan dari Bulanan atau pembagian rata Total
+func jabarkanAnggaranBulanan(baris BarisAnggaranRequest) ([]models.Uang, error) {
+	if baris.Total < 0 {
+		return nil, errors.New("total anggaran tidak boleh negatif")
+	}
+
+	if len(baris.Bulanan) == 0 {
+		bobot := make([]int64, jumlahBulanAnggaran)
+		for i := range bobot {
+			bobot[i] = 1
+		}
+		return models.BagiProporsional(baris.Total, bobot), nil
+	}
+
+	if len(baris.Bulanan) != jumlahBulanAnggaran {
+		return nil, fmt.Errorf("anggaran bulanan harus berisi %d bulan", jumlahBulanAnggaran)
+	}
+
+	var total models.Uang
+	for _, jumlah := range baris.Bulanan {
+		if jumlah < 0 {
+			return nil, errors.New("anggaran bulanan tidak boleh negatif")
+		}
+		total += jumlah
+	}
+	if baris.Total != 0 && baris.Total != total {
+		return nil, fmt.Errorf("total anggaran (%s) tidak sama dengan jumlah anggaran bulanan (%s)", baris.Total, total)
+	}
+
+	return baris.Bulanan, nil
+}
+
+// gantiBarisAnggaran mengganti seluruh baris RAPB; bulan beranggaran nol tidak disimpan
+func (s *AnggaranService) gantiBarisAnggaran(tx *gorm.DB, idAnggaran uuid.UUID, barisList []models.BarisAnggaran) error {
+	if err := tx.Where("id_anggaran = ?", idAnggaran).Delete(&models.BarisAnggaran{}).Error; err != nil {
+		return errors.New("gagal menghapus baris anggaran lama")
+	}
+
+	simpan := make([]models.BarisAnggaran, 0, len(barisList))
+	for _, baris := range barisList {
+		if baris.Jumlah == 0 {
+			continue
+		}
+		baris.IDAnggaran = idAnggaran
+		simpan = append(simpan, baris)
+	}
+	if len(simpan) == 0 {
+		return nil
+	}
+
+	if err := tx.CreateInBatches(simpan, 500).Error; err != nil {
+		return errors.New("gagal menyimpan baris anggaran")
+	}
+	return nil
+}
+
+// susunAnggaranResponse mengelompokkan baris anggaran per akun dan menghitung totalnya
+func (s *AnggaranService) susunAnggaranResponse(anggaran *models.Anggaran) (*AnggaranResponse, error) {
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", anggaran.IDKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+	periodeMulai, periodeAkhir := RentangTahunBuku(koperasi.TahunBukuMulai, anggaran.TahunBuku)
+
+	var barisList []models.BarisAnggaran
+	err := s.db.Preload("Akun").
+		Where("id_anggaran = ?", anggaran.ID).
+		Find(&barisList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil baris anggaran")
+	}
+
+	akunMap := map[uuid.UUID]*AnggaranAkun{}
+	for _, baris := range barisList {
+		akun, ada := akunMap[baris.IDAkun]
+		if !ada {
+			akun = &AnggaranAkun{
+				IDAkun:   baris.IDAkun,
+				KodeAkun: baris.Akun.KodeAkun,
+				NamaAkun: baris.Akun.NamaAkun,
+				TipeAkun: baris.Akun.TipeAkun,
+				Bulanan:  make([]models.Uang, jumlahBulanAnggaran),
+			}
+			akunMap[baris.IDAkun] = akun
+		}
+		if baris.BulanKe >= 1 && baris.BulanKe <= jumlahBulanAnggaran {
+			akun.Bulanan[baris.BulanKe-1] += baris.Jumlah
+		}
+		akun.Total += baris.Jumlah
+	}
+
+	akunList := make([]*AnggaranAkun, 0, len(akunMap))
+	for _, akun := range akunMap {
+		akunList = append(akunList, akun)
+	}
+	sort.Slice(akunList, func(i, j int) bool {
+		return akunList[i].KodeAkun < akunList[j].KodeAkun
+	})
+
+	response := &AnggaranResponse{
+		ID:              anggaran.ID,
+		TahunBuku:       anggaran.TahunBuku,
+		PeriodeMulai:    periodeMulai,
+		PeriodeAkhir:    periodeAkhir,
+		Keterangan:      anggaran.Keterangan,
+		Status:          anggaran.Status,
+		TanggalDisahkan: anggaran.TanggalDisahkan,
+		Pendapatan:      []AnggaranAkun{},
+		Beban:           []AnggaranAkun{},
+	}
+	for _, akun := range akunList {
+		if akun.TipeAkun == models.AkunPendapatan {
+			response.Pendapatan = append(response.Pendapatan, *akun)
+			response.TotalPendapatan += akun.Total
+		} else {
+			response.Beban = append(response.Beban, *akun)
+			response.TotalBeban += akun.Total
+		}
+	}
+	response.SHUDianggarkan = response.TotalPendapatan - response.TotalBeban
+
+	return response, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJabarkanAnggaranBulanan(t *testing.T) {
+	t.Run("total dibagi rata dan sisa sen tidak hilang", func(t *testing.T) {
+		bulanan, err := jabarkanAnggaranBulanan(BarisAnggaranRequest{KodeAkun: "4101", Total: models.Rupiah(1000)})
+		require.NoError(t, err)
+		require.Len(t, bulanan, 12)
+
+		var total models.Uang
+		for _, jumlah := range bulanan {
+			total += jumlah
+		}
+		assert.Equal(t, models.Rupiah(1000), total)
+	})
+
+	t.Run("fase bulanan harus 12 bulan", func(t *testing.T) {
+		_, err := jabarkanAnggaranBulanan(BarisAnggaranRequest{KodeAkun: "4101", Bulanan: []models.Uang{models.Rupiah(100)}})
+		assert.Error(t, err)
+	})
+
+	t.Run("total harus sama dengan jumlah fase bulanan", func(t *testing.T) {
+		bulanan := make([]models.Uang, 12)
+		bulanan[0] = models.Rupiah(500)
+		_, err := jabarkanAnggaranBulanan(BarisAnggaranRequest{KodeAkun: "4101", Total: models.Rupiah(600), Bulanan: bulanan})
+		assert.Error(t, err)
+	})
+
+	t.Run("anggaran negatif ditolak", func(t *testing.T) {
+		_, err := jabarkanAnggaranBulanan(BarisAnggaranRequest{KodeAkun: "4101", Total: models.Rupiah(-1)})
+		assert.Error(t, err)
+	})
+}
+
+func TestHitungSelisihRealisasi(t *testing.T) {
+	pendapatan := &ItemRealisasiAnggaran{Anggaran: models.Rupiah(200000), Realisasi: models.Rupiah(150000)}
+	hitungSelisihRealisasi(pendapatan, true)
+	assert.Equal(t, models.Rupiah(-50000), pendapatan.Selisih)
+	assert.False(t, pendapatan.Menguntungkan)
+	assert.Equal(t, 75.0, *pendapatan.PersentaseRealisasi)
+	assert.Equal(t, -25.0, *pendapatan.PersentaseSelisih)
+
+	beban := &ItemRealisasiAnggaran{Anggaran: models.Rupiah(300000), Realisasi: models.Rupiah(100000)}
+	hitungSelisihRealisasi(beban, false)
+	assert.True(t, beban.Menguntungkan)
+	assert.Equal(t, 33.33, *beban.PersentaseRealisasi)
+
+	tanpaAnggaran := &ItemRealisasiAnggaran{Realisasi: models.Rupiah(1000)}
+	hitungSelisihRealisasi(tanpaAnggaran, false)
+	assert.Nil(t, tanpaAnggaran.PersentaseRealisasi)
+	assert.Nil(t, tanpaAnggaran.PersentaseSelisih)
+	assert.False(t, tanpaAnggaran.Menguntungkan)
+}
+
+func TestAnggaranService(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+		&models.Anggaran{},
+		&models.BarisAnggaran{},
+	)
+	require.NoError(t, err)
+
+	// Tahun buku dimulai dua bulan lalu agar jurnal realisasi lolos validasi tanggal transaksi
+	sekarang := time.Now()
+	bulanIni := time.Date(sekarang.Year(), sekarang.Month(), 1, 0, 0, 0, 0, time.UTC)
+	awalTahunBuku := bulanIni.AddDate(0, -2, 0)
+	tahunBuku := awalTahunBuku.Year()
+	tahunLalu := tahunBuku - 1
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Anggaran Koperasi", Alamat: "Test Address", TahunBukuMulai: int(awalTahunBuku.Month())}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	penjualan, _ := akunService.DapatkanAkunByKode(koperasi.ID, "4101")
+	gaji, _ := akunService.DapatkanAkunByKode(koperasi.ID, "5101")
+
+	transaksiService := NewTransaksiService(db)
+	laporanService := NewLaporanService(db, akunService, nil, nil)
+	anggaranService := NewAnggaranService(db, laporanService)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	// Anggaran beban dengan fase bulanan: gaji ke-13 dibayar di bulan terakhir
+	bulananGaji := make([]models.Uang, 12)
+	for i := range bulananGaji {
+		bulananGaji[i] = models.Rupiah(100000)
+	}
+	bulananGaji[11] = models.Rupiah(200000)
+
+	anggaran, err := anggaranService.SimpanAnggaran(koperasi.ID, admin, tahunLalu, &SimpanAnggaranRequest{
+		Baris: []BarisAnggaranRequest{
+			{KodeAkun: "4101", Total: models.Rupiah(2400000)},
+			{KodeAkun: "5101", Bulanan: bulananGaji},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusAnggaranDraft, anggaran.Status)
+	assert.Equal(t, models.Rupiah(2400000), anggaran.TotalPendapatan)
+	assert.Equal(t, models.Rupiah(1300000), anggaran.TotalBeban)
+	assert.Equal(t, models.Rupiah(200000), anggaran.Pendapatan[0].Bulanan[0])
+
+	t.Run("hanya akun pendapatan dan beban yang dapat dianggarkan", func(t *testing.T) {
+		_, err := anggaranService.SimpanAnggaran(koperasi.ID, admin, tahunLalu, &SimpanAnggaranRequest{
+			Baris: []BarisAnggaranRequest{{KodeAkun: "1101", Total: models.Rupiah(1000)}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("akun yang sama tidak boleh dianggarkan dua kali", func(t *testing.T) {
+		_, err := anggaranService.SimpanAnggaran(koperasi.ID, admin, tahunLalu, &SimpanAnggaranRequest{
+			Baris: []BarisAnggaranRequest{
+				{KodeAkun: "4101", Total: models.Rupiah(1000)},
+				{KodeAkun: "4101", Total: models.Rupiah(2000)},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("salin anggaran tahun lalu dengan pertumbuhan", func(t *testing.T) {
+		salinan, err := anggaranService.SalinAnggaranTahunLalu(koperasi.ID, admin, tahunBuku, &SalinAnggaranRequest{PersentasePertumbuhan: 10})
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(2640000), salinan.TotalPendapatan)
+		assert.Equal(t, models.Rupiah(1430000), salinan.TotalBeban)
+		assert.Equal(t, models.Rupiah(220000), salinan.Beban[0].Bulanan[11])
+
+		_, err = anggaranService.SalinAnggaranTahunLalu(koperasi.ID, admin, tahunBuku, &SalinAnggaranRequest{})
+		assert.Error(t, err, "anggaran tujuan yang sudah ada tidak boleh ditimpa")
+	})
+
+	t.Run("realisasi anggaran dibandingkan dengan laba rugi", func(t *testing.T) {
+		jurnal := func(tanggal time.Time, debit, kredit *models.AkunResponse, jumlah int64) {
+			_, err := transaksiService.BuatTransaksi(koperasi.ID, admin, &BuatTransaksiRequest{
+				TanggalTransaksi: tanggal,
+				Deskripsi:        "Transaksi test anggaran",
+				BarisTransaksi: []BuatBarisTransaksiRequest{
+					{IDAkun: debit.ID, JumlahDebit: models.Rupiah(jumlah)},
+					{IDAkun: kredit.ID, JumlahKredit: models.Rupiah(jumlah)},
+				},
+			})
+			require.NoError(t, err)
+		}
+		bulanKedua := awalTahunBuku.AddDate(0, 1, 0)
+		jurnal(awalTahunBuku.AddDate(0, 0, 14), kas, penjualan, 300000)
+		jurnal(bulanKedua.AddDate(0, 0, 14), kas, penjualan, 150000)
+		jurnal(bulanKedua.AddDate(0, 0, 24), gaji, kas, 200000)
+		// Bulan ketiga tahun buku berada di luar periode laporan
+		jurnal(bulanIni, kas, penjualan, 999000)
+
+		laporan, err := anggaranService.LaporanRealisasiAnggaran(koperasi.ID, tahunBuku, 2)
+		require.NoError(t, err)
+		assert.Equal(t, bulanIni.AddDate(0, 0, -1), laporan.PeriodeAkhir)
+
+		labaRugi, err := laporanService.GenerateLaporanLabaRugi(koperasi.ID,
+			awalTahunBuku.Format("2006-01-02"), laporan.PeriodeAkhir.Format("2006-01-02"))
+		require.NoError(t, err)
+		assert.Equal(t, labaRugi.TotalPendapatan, laporan.TotalPendapatan.Realisasi)
+		assert.Equal(t, labaRugi.LabaRugiBersih, laporan.SHU.Realisasi)
+
+		// Pendapatan: anggaran 2 bulan 440.000, realisasi 450.000
+		assert.Equal(t, models.Rupiah(440000), laporan.TotalPendapatan.Anggaran)
+		assert.Equal(t, models.Rupiah(10000), laporan.TotalPendapatan.Selisih)
+		assert.True(t, laporan.TotalPendapatan.Menguntungkan)
+		assert.Equal(t, 2.27, *laporan.TotalPendapatan.PersentaseSelisih)
+
+		// Beban: anggaran 2 bulan 220.000, realisasi 200.000
+		assert.Equal(t, models.Rupiah(220000), laporan.TotalBeban.Anggaran)
+		assert.Equal(t, models.Rupiah(-20000), laporan.TotalBeban.Selisih)
+		assert.True(t, laporan.TotalBeban.Menguntungkan)
+		assert.Equal(t, models.Rupiah(1430000), laporan.TotalBeban.AnggaranSetahun)
+
+		assert.Equal(t, models.Rupiah(220000), laporan.SHU.Anggaran)
+		assert.Equal(t, models.Rupiah(250000), laporan.SHU.Realisasi)
+	})
+
+	t.Run("anggaran yang disahkan tidak dapat diubah atau dihapus", func(t *testing.T) {
+		disahkan, err := anggaranService.SahkanAnggaran(koperasi.ID, admin, tahunBuku, &SahkanAnggaranRequest{
+			TanggalDisahkan: time.Date(tahunLalu, 12, 20, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusAnggaranDisahkan, disahkan.Status)
+
+		_, err = anggaranService.SimpanAnggaran(koperasi.ID, admin, tahunBuku, &SimpanAnggaranRequest{
+			Baris: []BarisAnggaranRequest{{KodeAkun: "4101", Total: models.Rupiah(1000)}},
+		})
+		assert.Error(t, err)
+		assert.Error(t, anggaranService.HapusAnggaran(koperasi.ID, tahunBuku))
+
+		require.NoError(t, anggaranService.HapusAnggaran(koperasi.ID, tahunLalu))
+		_, err = anggaranService.DapatkanAnggaran(koperasi.ID, tahunLalu)
+		assert.Error(t, err)
+	})
+}
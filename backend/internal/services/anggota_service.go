@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/pkg/validasi"
 	"errors"
@@ -23,6 +24,14 @@ func NewAnggotaService(db *gorm.DB) *AnggotaService {
 	return &AnggotaService{db: db}
 }
 
+// DenganKonteks mengembalikan salinan service yang menjalankan query dengan ctx,
+// sehingga log audit mencatat pengguna, alamat IP dan request ID dari request tersebut
+func (s *AnggotaService) DenganKonteks(ctx context.Context) *AnggotaService {
+	salinan := *s
+	salinan.db = s.db.WithContext(ctx)
+	return &salinan
+}
+
 // BuatAnggotaRequest adalah struktur request untuk membuat anggota
 type BuatAnggotaRequest struct {
 	NamaLengkap      string     `json:"namaLengkap" binding:"required"`
@@ -67,7 +67,7 @@ func TestHapusAnggota_WithSimpananTransactions(t *testing.T) {
 		IDAnggota:        member.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    100000,
+		JumlahSetoran:    models.Rupiah(100000),
 		NomorReferensi:   "SMP-TEST-001",
 	}
 	db.Create(simpanan)
@@ -145,9 +145,9 @@ func TestHapusAnggota_WithPenjualanTransactions(t *testing.T) {
 		IDAnggota:        &member.ID,
 		TanggalPenjualan: time.Now(),
 		NomorPenjualan:   "POS-TEST-001",
-		TotalBelanja:     50000,
+		TotalBelanja:     models.Rupiah(50000),
 		MetodePembayaran: models.PembayaranTunai,
-		JumlahBayar:      50000,
+		JumlahBayar:      models.Rupiah(50000),
 		IDKasir:          kasir.ID,
 	}
 	if err := db.Create(penjualan).Error; err != nil {
@@ -203,7 +203,7 @@ func TestHapusAnggota_WithBothTransactionTypes(t *testing.T) {
 		IDAnggota:        member.ID,
 		TipeSimpanan:     models.SimpananWajib,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    50000,
+		JumlahSetoran:    models.Rupiah(50000),
 		NomorReferensi:   "SMP-TEST-002",
 	}
 	db.Create(simpanan)
@@ -213,9 +213,9 @@ func TestHapusAnggota_WithBothTransactionTypes(t *testing.T) {
 		IDAnggota:        &member.ID,
 		TanggalPenjualan: time.Now(),
 		NomorPenjualan:   "POS-TEST-002",
-		TotalBelanja:     75000,
+		TotalBelanja:     models.Rupiah(75000),
 		MetodePembayaran: models.PembayaranTunai,
-		JumlahBayar:      75000,
+		JumlahBayar:      models.Rupiah(75000),
 		IDKasir:          member.ID, // Use member ID as kasir for test
 	}
 	db.Create(penjualan)
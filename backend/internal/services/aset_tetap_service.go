@@ -0,0 +1,899 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AsetTetapService menangani register aset tetap, penyusutan bulanan dan pelepasan aset
+type AsetTetapService struct {
+	db               *gorm.DB
+	transaksiService *TransaksiService
+}
+
+// NewAsetTetapService membuat instance baru AsetTetapService
+func NewAsetTetapService(db *gorm.DB, transaksiService *TransaksiService) *AsetTetapService {
+	return &AsetTetapService{
+		db:               db,
+		transaksiService: transaksiService,
+	}
+}
+
+// akunKelompokAset adalah kode akun COA default untuk satu kelompok aset tetap
+type akunKelompokAset struct {
+	Aset      string
+	Akumulasi string // Kosong untuk kelompok yang tidak disusutkan
+	Beban     string
+}
+
+// akunDefaultKelompokAset memetakan kelompok aset ke akun COA default (lihat coaDasar)
+var akunDefaultKelompokAset = map[models.KelompokAsetTetap]akunKelompokAset{
+	models.KelompokAsetTanah:     {Aset: "1403"},
+	models.KelompokAsetBangunan:  {Aset: "1404", Akumulasi: "1405", Beban: "5105"},
+	models.KelompokAsetKendaraan: {Aset: "1406", Akumulasi: "1407", Beban: "5105"},
+	models.KelompokAsetPeralatan: {Aset: "1401", Akumulasi: "1402", Beban: "5105"},
+}
+
+// RegistrasiAsetTetapRequest adalah struktur request untuk mendaftarkan aset tetap
+type RegistrasiAsetTetapRequest struct {
+	KodeAset                string                   `json:"kodeAset" binding:"required"`
+	NamaAset                string                   `json:"namaAset" binding:"required"`
+	Kelompok                models.KelompokAsetTetap `json:"kelompok" binding:"required"`
+	Deskripsi               string                   `json:"deskripsi"`
+	TanggalPerolehan        time.Time                `json:"tanggalPerolehan" binding:"required"`
+	HargaPerolehan          models.Uang              `json:"hargaPerolehan" binding:"required,gt=0"`
+	NilaiResidu             models.Uang              `json:"nilaiResidu"`
+	UmurEkonomisBulan       int                      `json:"umurEkonomisBulan"`       // Wajib kecuali tanah
+	MetodePenyusutan        models.MetodePenyusutan  `json:"metodePenyusutan"`        // Default GARIS_LURUS
+	AkumulasiPenyusutanAwal models.Uang              `json:"akumulasiPenyusutanAwal"` // Untuk aset yang sudah dimiliki sebelum memakai sistem
+	IDAkunPembayaran        *uuid.UUID               `json:"idAkunPembayaran"`        // Diisi untuk membuat jurnal perolehan (kas, bank, hutang)
+	IDAkunAset              *uuid.UUID               `json:"idAkunAset"`              // Kosong: akun default kelompok
+	IDAkunAkumulasi         *uuid.UUID               `json:"idAkunAkumulasi"`         // Kosong: akun default kelompok
+	IDAkunBeban             *uuid.UUID               `json:"idAkunBeban"`             // Kosong: akun default kelompok
+	IDUnitUsaha             *uuid.UUID               `json:"idUnitUsaha"`
+}
+
+// LepasAsetTetapRequest adalah struktur request untuk penjualan atau penghapusan aset tetap
+type LepasAsetTetapRequest struct {
+	TanggalPelepasan time.Time   `json:"tanggalPelepasan" binding:"required"`
+	NilaiPelepasan   models.Uang `json:"nilaiPelepasan"`   // 0 untuk penghapusan tanpa hasil penjualan
+	IDAkunPenerimaan *uuid.UUID  `json:"idAkunPenerimaan"` // Wajib jika nilai pelepasan lebih dari 0
+	Keterangan       string      `json:"keterangan"`
+}
+
+// BatalkanAsetTetapRequest adalah struktur request untuk membatalkan registrasi, penyusutan atau pelepasan aset tetap
+type BatalkanAsetTetapRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// BatalkanPenyusutanRequest adalah struktur request untuk membatalkan penyusutan satu bulan
+type BatalkanPenyusutanRequest struct {
+	Tahun  int    `json:"tahun" binding:"required"`
+	Bulan  int    `json:"bulan" binding:"required,min=1,max=12"`
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// PostingPenyusutanRequest adalah struktur request untuk posting penyusutan satu bulan
+type PostingPenyusutanRequest struct {
+	Tahun int `json:"tahun" binding:"required"`
+	Bulan int `json:"bulan" binding:"required,min=1,max=12"`
+}
+
+// HasilPostingPenyusutan adalah ringkasan posting penyusutan satu bulan
+type HasilPostingPenyusutan struct {
+	Tahun           int         `json:"tahun"`
+	Bulan           int         `json:"bulan"`
+	JumlahAset      int         `json:"jumlahAset"`
+	TotalPenyusutan models.Uang `json:"totalPenyusutan"`
+	IDTransaksi     *uuid.UUID  `json:"idTransaksi"` // Kosong jika tidak ada aset yang disusutkan
+}
+
+// RegistrasiAsetTetap mendaftarkan aset tetap baru.
+//
+// Jika IDAkunPembayaran diisi, jurnal perolehan dibuat pada tanggal perolehan:
+//   - Debit akun aset sebesar harga perolehan
+//   - Kredit akun pembayaran (kas, bank, atau hutang)
+//
+// Tanpa IDAkunPembayaran aset hanya dicatat di register; saldo akunnya diasumsikan
+// sudah masuk melalui saldo awal, dan akumulasi penyusutan awal boleh diisi.
+func (s *AsetTetapService) RegistrasiAsetTetap(idKoperasi, idPengguna uuid.UUID, req *RegistrasiAsetTetapRequest) (*models.AsetTetapResponse, error) {
+	if err := validasiRegistrasiAset(req); err != nil {
+		return nil, err
+	}
+
+	aset := &models.AsetTetap{
+		IDKoperasi:          idKoperasi,
+		KodeAset:            req.KodeAset,
+		NamaAset:            req.NamaAset,
+		Kelompok:            req.Kelompok,
+		Deskripsi:           req.Deskripsi,
+		TanggalPerolehan:    req.TanggalPerolehan,
+		HargaPerolehan:      req.HargaPerolehan,
+		NilaiResidu:         req.NilaiResidu,
+		UmurEkonomisBulan:   req.UmurEkonomisBulan,
+		MetodePenyusutan:    req.MetodePenyusutan,
+		AkumulasiPenyusutan: req.AkumulasiPenyusutanAwal,
+		IDUnitUsaha:         req.IDUnitUsaha,
+		Status:              models.StatusAsetAktif,
+		DibuatOleh:          idPengguna,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Validasi kode aset unique (termasuk yang sudah dihapus karena unique index)
+		var count int64
+		tx.Unscoped().Model(&models.AsetTetap{}).
+			Where("id_koperasi = ? AND kode_aset = ?", idKoperasi, req.KodeAset).
+			Count(&count)
+		if count > 0 {
+			return errors.New("kode aset sudah digunakan")
+		}
+
+		if err := validasiUnitUsahaAktifWithTx(tx, idKoperasi, req.IDUnitUsaha); err != nil {
+			return err
+		}
+
+		// Resolusi akun aset, akumulasi dan beban dari request atau default kelompok
+		kodeDefault := akunDefaultKelompokAset[req.Kelompok]
+		idAkunAset, err := akunAsetWithTx(tx, idKoperasi, req.IDAkunAset, kodeDefault.Aset, models.AkunAktiva, "aset")
+		if err != nil {
+			return err
+		}
+		aset.IDAkunAset = *idAkunAset
+
+		if req.UmurEkonomisBulan > 0 {
+			aset.IDAkunAkumulasi, err = akunAsetWithTx(tx, idKoperasi, req.IDAkunAkumulasi, kodeDefault.Akumulasi, models.AkunAktiva, "akumulasi penyusutan")
+			if err != nil {
+				return err
+			}
+			aset.IDAkunBeban, err = akunAsetWithTx(tx, idKoperasi, req.IDAkunBeban, kodeDefault.Beban, models.AkunBeban, "beban penyusutan")
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.IDAkunPembayaran != nil {
+			keterangan := fmt.Sprintf("Perolehan aset tetap %s - %s", req.KodeAset, req.NamaAset)
+			transaksi, jurnalErr := s.transaksiService.BuatTransaksiWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+				TanggalTransaksi: req.TanggalPerolehan,
+				Deskripsi:        keterangan,
+				NomorReferensi:   req.KodeAset,
+				TipeTransaksi:    models.TipeTransaksiAsetTetap,
+				BarisTransaksi: []BuatBarisTransaksiRequest{
+					{IDAkun: aset.IDAkunAset, JumlahDebit: req.HargaPerolehan, Keterangan: keterangan, IDUnitUsaha: req.IDUnitUsaha},
+					{IDAkun: *req.IDAkunPembayaran, JumlahKredit: req.HargaPerolehan, Keterangan: keterangan, IDUnitUsaha: req.IDUnitUsaha},
+				},
+			})
+			if jurnalErr != nil {
+				return fmt.Errorf("gagal posting jurnal perolehan: %w", jurnalErr)
+			}
+			aset.IDTransaksiPerolehan = &transaksi.ID
+		}
+
+		if createErr := tx.Create(aset).Error; createErr != nil {
+			return errors.New("gagal mendaftarkan aset tetap")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := aset.ToResponse()
+	return &response, nil
+}
+
+// validasiRegistrasiAset memvalidasi request registrasi aset tetap dan mengisi metode default
+func validasiRegistrasiAset(req *RegistrasiAsetTetapRequest) error {
+	validator := validasi.Baru()
+
+	if err := validator.TeksWajib(req.KodeAset, "kode aset", 1, 30); err != nil {
+		return err
+	}
+	if err := validator.TeksWajib(req.NamaAset, "nama aset", 3, 255); err != nil {
+		return err
+	}
+	if err := validator.TeksOpsional(req.Deskripsi, "deskripsi", 1000); err != nil {
+		return err
+	}
+
+	if _, ok := akunDefaultKelompokAset[req.Kelompok]; !ok {
+		return fmt.Errorf("kelompok aset %s tidak dikenal", req.Kelompok)
+	}
+	if req.TanggalPerolehan.After(time.Now()) {
+		return errors.New("tanggal perolehan tidak boleh di masa depan")
+	}
+	if req.HargaPerolehan <= 0 {
+		return errors.New("harga perolehan harus lebih dari 0")
+	}
+	if req.NilaiResidu < 0 || req.NilaiResidu >= req.HargaPerolehan {
+		return errors.New("nilai residu harus di antara 0 dan harga perolehan")
+	}
+
+	// Tanah tidak disusutkan; kelompok lain wajib memiliki umur ekonomis
+	if req.Kelompok == models.KelompokAsetTanah {
+		if req.UmurEkonomisBulan != 0 || req.AkumulasiPenyusutanAwal != 0 {
+			return errors.New("tanah tidak disusutkan, umur ekonomis dan akumulasi penyusutan harus 0")
+		}
+	} else if req.UmurEkonomisBulan <= 0 || req.UmurEkonomisBulan > 600 {
+		return errors.New("umur ekonomis harus antara 1 dan 600 bulan")
+	}
+
+	switch req.MetodePenyusutan {
+	case "":
+		req.MetodePenyusutan = models.PenyusutanGarisLurus
+	case models.PenyusutanGarisLurus, models.PenyusutanSaldoMenurun:
+	default:
+		return fmt.Errorf("metode penyusutan %s tidak dikenal", req.MetodePenyusutan)
+	}
+
+	if req.AkumulasiPenyusutanAwal < 0 || req.AkumulasiPenyusutanAwal > req.HargaPerolehan-req.NilaiResidu {
+		return errors.New("akumulasi penyusutan awal tidak boleh melebihi harga perolehan dikurangi nilai residu")
+	}
+	if req.AkumulasiPenyusutanAwal > 0 && req.IDAkunPembayaran != nil {
+		return errors.New("akumulasi penyusutan awal hanya untuk aset lama yang dicatat tanpa jurnal perolehan")
+	}
+
+	return nil
+}
+
+// akunAsetWithTx meresolusi akun yang dipakai aset: akun pilihan jika diisi, selain itu
+// akun COA default berdasarkan kode. Akun harus milik koperasi, aktif, dan bertipe sesuai.
+func akunAsetWithTx(tx *gorm.DB, idKoperasi uuid.UUID, idAkun *uuid.UUID, kodeDefault string, tipe models.TipeAkun, nama string) (*uuid.UUID, error) {
+	query := tx.Where("id_koperasi = ?", idKoperasi)
+	if idAkun != nil {
+		query = query.Where("id = ?", *idAkun)
+	} else {
+		query = query.Where("kode_akun = ?", kodeDefault)
+	}
+
+	var akun models.Akun
+	if err := query.First(&akun).Error; err != nil {
+		return nil, fmt.Errorf("akun %s tidak ditemukan", nama)
+	}
+	if !akun.StatusAktif {
+		return nil, fmt.Errorf("akun %s (%s) tidak aktif", nama, akun.KodeAkun)
+	}
+	if akun.TipeAkun != tipe {
+		return nil, fmt.Errorf("akun %s (%s) harus bertipe %s", nama, akun.KodeAkun, tipe)
+	}
+
+	return &akun.ID, nil
+}
+
+// DapatkanSemuaAsetTetap mengambil register aset tetap, diurutkan berdasarkan kode aset
+func (s *AsetTetapService) DapatkanSemuaAsetTetap(idKoperasi uuid.UUID, status, kelompok string) ([]models.AsetTetapResponse, error) {
+	query := s.db.Where("id_koperasi = ?", idKoperasi)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if kelompok != "" {
+		query = query.Where("kelompok = ?", kelompok)
+	}
+
+	var asetList []models.AsetTetap
+	if err := query.Order("kode_aset ASC").Find(&asetList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar aset tetap")
+	}
+
+	responses := make([]models.AsetTetapResponse, len(asetList))
+	for i := range asetList {
+		responses[i] = asetList[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// DapatkanAsetTetap mengambil detail aset tetap beserta riwayat penyusutannya
+func (s *AsetTetapService) DapatkanAsetTetap(id, idKoperasi uuid.UUID) (*models.AsetTetapResponse, error) {
+	var aset models.AsetTetap
+	err := s.db.Preload("Penyusutan", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tahun ASC, bulan ASC")
+	}).
+		Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+		First(&aset).Error
+	if err != nil {
+		return nil, errors.New("aset tetap tidak ditemukan")
+	}
+
+	response := aset.ToResponse()
+	return &response, nil
+}
+
+// indeksBulan mengubah tahun dan bulan menjadi nomor urut bulan agar mudah dibandingkan
+func indeksBulan(tahun int, bulan time.Month) int {
+	return tahun*12 + int(bulan) - 1
+}
+
+// hitungPenyusutanBulan menghitung beban penyusutan aset untuk bulan ke-bulanKe sejak bulan
+// perolehan (bulan perolehan adalah bulan ke-1), berdasarkan akumulasi penyusutan saat ini.
+//
+// Metode penyusutan:
+//   - GARIS_LURUS: (harga perolehan - nilai residu) / umur ekonomis
+//   - SALDO_MENURUN: nilai buku x 2 / umur ekonomis (saldo menurun ganda)
+//
+// Penyusutan tidak pernah membuat nilai buku di bawah nilai residu, dan bulan terakhir
+// umur ekonomis menyerap seluruh sisa sehingga aset habis disusutkan tepat waktu.
+func hitungPenyusutanBulan(aset *models.AsetTetap, bulanKe int) models.Uang {
+	if aset.UmurEkonomisBulan <= 0 || bulanKe < 1 {
+		return 0
+	}
+
+	sisa := aset.HargaPerolehan - aset.NilaiResidu - aset.AkumulasiPenyusutan
+	if sisa <= 0 {
+		return 0
+	}
+	if bulanKe >= aset.UmurEkonomisBulan {
+		return sisa
+	}
+
+	var jumlah models.Uang
+	switch aset.MetodePenyusutan {
+	case models.PenyusutanSaldoMenurun:
+		jumlah = aset.NilaiBuku().KaliPecahan(2, int64(aset.UmurEkonomisBulan))
+	default:
+		jumlah = (aset.HargaPerolehan - aset.NilaiResidu).KaliPecahan(1, int64(aset.UmurEkonomisBulan))
+	}
+
+	if jumlah > sisa {
+		jumlah = sisa
+	}
+	return jumlah
+}
+
+// PostingPenyusutan memposting jurnal penyusutan seluruh aset aktif untuk satu bulan.
+//
+// Jurnal dibuat satu per bulan dengan tanggal akhir bulan, berisi untuk setiap aset:
+//   - Debit beban penyusutan
+//   - Kredit akumulasi penyusutan
+//
+// Aset yang sudah disusutkan untuk bulan tersebut (atau bulan sesudahnya) dilewati,
+// sehingga posting yang diulang tidak menghasilkan penyusutan ganda.
+func (s *AsetTetapService) PostingPenyusutan(idKoperasi, idPengguna uuid.UUID, tahun, bulan int) (*HasilPostingPenyusutan, error) {
+	if bulan < 1 || bulan > 12 {
+		return nil, errors.New("bulan harus antara 1 dan 12")
+	}
+
+	akhirBulan := time.Date(tahun, time.Month(bulan)+1, 0, 0, 0, 0, 0, time.UTC)
+	if !akhirBulan.Before(hariIniUTC()) {
+		return nil, fmt.Errorf("penyusutan %02d/%d baru dapat diposting setelah bulan berakhir", bulan, tahun)
+	}
+
+	hasil := &HasilPostingPenyusutan{Tahun: tahun, Bulan: bulan}
+	indeksTarget := indeksBulan(tahun, time.Month(bulan))
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci aset agar posting bersamaan tidak menyusutkan aset yang sama dua kali
+		var asetList []models.AsetTetap
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id_koperasi = ? AND status = ? AND umur_ekonomis_bulan > 0 AND tanggal_perolehan <= ?",
+				idKoperasi, models.StatusAsetAktif, akhirBulan.Format("2006-01-02")).
+			Order("kode_aset ASC").
+			Find(&asetList).Error
+		if err != nil {
+			return errors.New("gagal mengambil daftar aset tetap")
+		}
+
+		// Aset yang sudah disusutkan untuk bulan ini atau bulan sesudahnya
+		var sudahDisusutkan []uuid.UUID
+		err = tx.Model(&models.PenyusutanAsetTetap{}).
+			Where("id_koperasi = ? AND tahun * 12 + bulan - 1 >= ?", idKoperasi, indeksTarget).
+			Distinct().Pluck("id_aset_tetap", &sudahDisusutkan).Error
+		if err != nil {
+			return errors.New("gagal memeriksa riwayat penyusutan")
+		}
+		lewati := make(map[uuid.UUID]bool, len(sudahDisusutkan))
+		for _, id := range sudahDisusutkan {
+			lewati[id] = true
+		}
+
+		type penyusutanAset struct {
+			aset   *models.AsetTetap
+			jumlah models.Uang
+		}
+		var daftar []penyusutanAset
+		var barisTransaksi []BuatBarisTransaksiRequest
+		for i := range asetList {
+			aset := &asetList[i]
+			if lewati[aset.ID] {
+				continue
+			}
+
+			bulanKe := indeksTarget - indeksBulan(aset.TanggalPerolehan.Year(), aset.TanggalPerolehan.Month()) + 1
+			jumlah := hitungPenyusutanBulan(aset, bulanKe)
+			if jumlah <= 0 {
+				continue
+			}
+
+			keterangan := fmt.Sprintf("Penyusutan %s - %s", aset.KodeAset, aset.NamaAset)
+			barisTransaksi = append(barisTransaksi,
+				BuatBarisTransaksiRequest{IDAkun: *aset.IDAkunBeban, JumlahDebit: jumlah, Keterangan: keterangan, IDUnitUsaha: aset.IDUnitUsaha},
+				BuatBarisTransaksiRequest{IDAkun: *aset.IDAkunAkumulasi, JumlahKredit: jumlah, Keterangan: keterangan, IDUnitUsaha: aset.IDUnitUsaha},
+			)
+			daftar = append(daftar, penyusutanAset{aset: aset, jumlah: jumlah})
+			hasil.TotalPenyusutan += jumlah
+		}
+
+		if len(daftar) == 0 {
+			return nil
+		}
+
+		transaksi, err := s.transaksiService.BuatTransaksiWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+			TanggalTransaksi: akhirBulan,
+			Deskripsi:        fmt.Sprintf("Penyusutan aset tetap %02d/%d", bulan, tahun),
+			NomorReferensi:   fmt.Sprintf("SUSUT-%d%02d", tahun, bulan),
+			TipeTransaksi:    models.TipeTransaksiAsetTetap,
+			BarisTransaksi:   barisTransaksi,
+		})
+		if err != nil {
+			return fmt.Errorf("gagal posting jurnal penyusutan: %w", err)
+		}
+
+		for _, item := range daftar {
+			item.aset.AkumulasiPenyusutan += item.jumlah
+			penyusutan := models.PenyusutanAsetTetap{
+				IDKoperasi:     idKoperasi,
+				IDAsetTetap:    item.aset.ID,
+				Tahun:          tahun,
+				Bulan:          bulan,
+				Jumlah:         item.jumlah,
+				NilaiBukuAkhir: item.aset.NilaiBuku(),
+				IDTransaksi:    transaksi.ID,
+			}
+			if createErr := tx.Create(&penyusutan).Error; createErr != nil {
+				return errors.New("gagal mencatat riwayat penyusutan")
+			}
+			if updateErr := tx.Model(item.aset).Update("akumulasi_penyusutan", item.aset.AkumulasiPenyusutan).Error; updateErr != nil {
+				return errors.New("gagal memperbarui akumulasi penyusutan")
+			}
+		}
+
+		hasil.JumlahAset = len(daftar)
+		hasil.IDTransaksi = &transaksi.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hasil, nil
+}
+
+// PostingPenyusutanOtomatis memposting penyusutan setiap koperasi untuk seluruh bulan yang
+// sudah berakhir dan belum disusutkan, dimulai dari bulan tertunda paling awal.
+// Bulan yang gagal (misalnya periode sudah ditutup) dicatat ke log dan menghentikan
+// posting bulan berikutnya untuk koperasi tersebut agar urutan penyusutan terjaga.
+func (s *AsetTetapService) PostingPenyusutanOtomatis() {
+	var koperasiList []models.Koperasi
+	if err := s.db.Find(&koperasiList).Error; err != nil {
+		log.Printf("Penyusutan otomatis: gagal mengambil daftar koperasi: %v", err)
+		return
+	}
+
+	hariIni := hariIniUTC()
+	indeksAkhir := indeksBulan(hariIni.Year(), hariIni.Month()) - 1
+
+	for _, koperasi := range koperasiList {
+		indeksMulai, ada, err := s.bulanPenyusutanTertunda(koperasi.ID)
+		if err != nil {
+			log.Printf("Penyusutan otomatis: gagal memeriksa aset koperasi %s: %v", koperasi.ID, err)
+			continue
+		}
+		if !ada {
+			continue
+		}
+
+		for indeks := indeksMulai; indeks <= indeksAkhir; indeks++ {
+			tahun, bulan := indeks/12, indeks%12+1
+			if _, err := s.PostingPenyusutan(koperasi.ID, uuid.Nil, tahun, bulan); err != nil {
+				log.Printf("Penyusutan otomatis: gagal memposting penyusutan %s %02d/%d: %v", koperasi.ID, bulan, tahun, err)
+				break
+			}
+		}
+	}
+}
+
+// bulanPenyusutanTertunda mencari bulan paling awal yang belum disusutkan di antara aset aktif
+// koperasi: bulan setelah penyusutan terakhir, atau bulan perolehan untuk aset yang belum pernah disusutkan.
+func (s *AsetTetapService) bulanPenyusutanTertunda(idKoperasi uuid.UUID) (int, bool, error) {
+	var asetList []models.AsetTetap
+	err := s.db.Where("id_koperasi = ? AND status = ? AND umur_ekonomis_bulan > 0 AND akumulasi_penyusutan < harga_perolehan - nilai_residu",
+		idKoperasi, models.StatusAsetAktif).
+		Find(&asetList).Error
+	if err != nil {
+		return 0, false, err
+	}
+	if len(asetList) == 0 {
+		return 0, false, nil
+	}
+
+	type terakhir struct {
+		IDAsetTetap uuid.UUID
+		Indeks      int
+	}
+	var terakhirList []terakhir
+	err = s.db.Model(&models.PenyusutanAsetTetap{}).
+		Select("id_aset_tetap, MAX(tahun * 12 + bulan - 1) as indeks").
+		Where("id_koperasi = ?", idKoperasi).
+		Group("id_aset_tetap").
+		Scan(&terakhirList).Error
+	if err != nil {
+		return 0, false, err
+	}
+	indeksTerakhir := make(map[uuid.UUID]int, len(terakhirList))
+	for _, item := range terakhirList {
+		indeksTerakhir[item.IDAsetTetap] = item.Indeks
+	}
+
+	indeksMulai := -1
+	for _, aset := range asetList {
+		indeks := indeksBulan(aset.TanggalPerolehan.Year(), aset.TanggalPerolehan.Month())
+		if terakhir, ok := indeksTerakhir[aset.ID]; ok {
+			indeks = terakhir + 1
+		}
+		if indeksMulai < 0 || indeks < indeksMulai {
+			indeksMulai = indeks
+		}
+	}
+
+	return indeksMulai, true, nil
+}
+
+// MulaiPenyusutanOtomatis menjalankan PostingPenyusutanOtomatis secara berkala di background.
+// Panggil fungsi stop yang dikembalikan untuk menghentikan goroutine.
+func (s *AsetTetapService) MulaiPenyusutanOtomatis(interval time.Duration) (stop func()) {
+	stopChan := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.PostingPenyusutanOtomatis()
+		for {
+			select {
+			case <-ticker.C:
+				s.PostingPenyusutanOtomatis()
+			case <-stopChan:
+				return // Graceful shutdown
+			}
+		}
+	}()
+
+	return func() { close(stopChan) }
+}
+
+// LepasAsetTetap mencatat penjualan atau penghapusan aset tetap beserta laba/ruginya.
+//
+// Jurnal yang dibuat:
+//   - Debit akumulasi penyusutan sebesar akumulasi penyusutan aset
+//   - Debit akun penerimaan sebesar nilai pelepasan (jika ada)
+//   - Kredit akun aset sebesar harga perolehan
+//   - Kredit laba atau debit rugi pelepasan sebesar selisih nilai pelepasan dan nilai buku
+//
+// Akun laba/rugi ditentukan oleh aturan posting PELEPASAN_ASET. Penyusutan sampai bulan
+// sebelum pelepasan harus sudah diposting agar nilai buku yang dihapus sudah benar.
+func (s *AsetTetapService) LepasAsetTetap(idKoperasi, idPengguna, id uuid.UUID, req *LepasAsetTetapRequest) (*models.AsetTetapResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TanggalTransaksi(req.TanggalPelepasan); err != nil {
+		return nil, err
+	}
+	if err := validator.TeksOpsional(req.Keterangan, "keterangan", 500); err != nil {
+		return nil, err
+	}
+	if req.NilaiPelepasan < 0 {
+		return nil, errors.New("nilai pelepasan tidak boleh negatif")
+	}
+	if req.NilaiPelepasan > 0 && req.IDAkunPenerimaan == nil {
+		return nil, errors.New("akun penerimaan wajib diisi jika nilai pelepasan lebih dari 0")
+	}
+
+	var aset models.AsetTetap
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&aset).Error
+		if err != nil {
+			return errors.New("aset tetap tidak ditemukan")
+		}
+		if aset.Status == models.StatusAsetBatal {
+			return errors.New("registrasi aset tetap sudah dibatalkan")
+		}
+		if aset.Status != models.StatusAsetAktif {
+			return errors.New("aset tetap sudah dilepas")
+		}
+		if req.TanggalPelepasan.Before(aset.TanggalPerolehan) {
+			return errors.New("tanggal pelepasan tidak boleh sebelum tanggal perolehan")
+		}
+
+		// Tolak jika penyusutan bulan sebelum pelepasan belum diposting
+		if aset.UmurEkonomisBulan > 0 {
+			indeksSebelum := indeksBulan(req.TanggalPelepasan.Year(), req.TanggalPelepasan.Month()) - 1
+			bulanKe := indeksSebelum - indeksBulan(aset.TanggalPerolehan.Year(), aset.TanggalPerolehan.Month()) + 1
+
+			var jumlah int64
+			tx.Model(&models.PenyusutanAsetTetap{}).
+				Where("id_aset_tetap = ? AND tahun * 12 + bulan - 1 >= ?", aset.ID, indeksSebelum).
+				Count(&jumlah)
+			if jumlah == 0 && hitungPenyusutanBulan(&aset, bulanKe) > 0 {
+				return fmt.Errorf("posting penyusutan sampai %02d/%d terlebih dahulu", indeksSebelum%12+1, indeksSebelum/12)
+			}
+		}
+
+		keterangan := fmt.Sprintf("Pelepasan aset tetap %s - %s", aset.KodeAset, aset.NamaAset)
+		if req.Keterangan != "" {
+			keterangan = fmt.Sprintf("%s: %s", keterangan, req.Keterangan)
+		}
+
+		var barisTransaksi []BuatBarisTransaksiRequest
+		if aset.AkumulasiPenyusutan > 0 {
+			barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{
+				IDAkun: *aset.IDAkunAkumulasi, JumlahDebit: aset.AkumulasiPenyusutan, Keterangan: keterangan, IDUnitUsaha: aset.IDUnitUsaha,
+			})
+		}
+		if req.NilaiPelepasan > 0 {
+			barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{
+				IDAkun: *req.IDAkunPenerimaan, JumlahDebit: req.NilaiPelepasan, Keterangan: keterangan, IDUnitUsaha: aset.IDUnitUsaha,
+			})
+		}
+		barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{
+			IDAkun: aset.IDAkunAset, JumlahKredit: aset.HargaPerolehan, Keterangan: keterangan, IDUnitUsaha: aset.IDUnitUsaha,
+		})
+
+		// Selisih nilai pelepasan dengan nilai buku adalah laba (positif) atau rugi (negatif)
+		if selisih := req.NilaiPelepasan - aset.NilaiBuku(); selisih != 0 {
+			akunPosting, akunErr := akunPostingWithTx(tx, idKoperasi, models.PeristiwaPelepasanAset)
+			if akunErr != nil {
+				return akunErr
+			}
+			if selisih > 0 {
+				barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{
+					IDAkun: akunPosting[models.PeranAkunLabaPelepasan].ID, JumlahKredit: selisih, Keterangan: keterangan, IDUnitUsaha: aset.IDUnitUsaha,
+				})
+			} else {
+				barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{
+					IDAkun: akunPosting[models.PeranAkunRugiPelepasan].ID, JumlahDebit: -selisih, Keterangan: keterangan, IDUnitUsaha: aset.IDUnitUsaha,
+				})
+			}
+		}
+
+		transaksi, err := s.transaksiService.BuatTransaksiWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+			TanggalTransaksi: req.TanggalPelepasan,
+			Deskripsi:        keterangan,
+			NomorReferensi:   aset.KodeAset,
+			TipeTransaksi:    models.TipeTransaksiAsetTetap,
+			BarisTransaksi:   barisTransaksi,
+		})
+		if err != nil {
+			return fmt.Errorf("gagal posting jurnal pelepasan: %w", err)
+		}
+
+		updateErr := tx.Model(&aset).Updates(map[string]interface{}{
+			"status":                 models.StatusAsetDilepas,
+			"tanggal_pelepasan":      req.TanggalPelepasan,
+			"nilai_pelepasan":        req.NilaiPelepasan,
+			"id_transaksi_pelepasan": transaksi.ID,
+		}).Error
+		if updateErr != nil {
+			return errors.New("gagal memperbarui status aset tetap")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanAsetTetap(aset.ID, idKoperasi)
+}
+
+// BatalkanRegistrasiAsetTetap membatalkan (void) registrasi aset tetap yang belum pernah
+// disusutkan maupun dilepas.
+//
+// Aset tidak dihapus: jurnal perolehannya (jika ada) dibalik dengan jurnal pembalik tertanggal
+// hari ini dan aset ditandai BATAL sehingga tidak ikut disusutkan atau dilepas. Penyusutan yang
+// sudah diposting harus dibatalkan lebih dulu melalui BatalkanPenyusutan.
+func (s *AsetTetapService) BatalkanRegistrasiAsetTetap(idKoperasi, idPengguna, id uuid.UUID, req *BatalkanAsetTetapRequest) (*models.AsetTetapResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci aset agar tidak bersamaan dengan posting penyusutan atau pelepasan
+		var aset models.AsetTetap
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&aset).Error
+		if lockErr != nil {
+			return errors.New("aset tetap tidak ditemukan")
+		}
+
+		switch aset.Status {
+		case models.StatusAsetBatal:
+			return errors.New("registrasi aset tetap sudah dibatalkan")
+		case models.StatusAsetDilepas:
+			return errors.New("aset tetap yang sudah dilepas tidak dapat dibatalkan, batalkan pelepasannya terlebih dahulu")
+		}
+
+		var jumlahPenyusutan int64
+		if countErr := tx.Model(&models.PenyusutanAsetTetap{}).
+			Where("id_aset_tetap = ?", aset.ID).
+			Count(&jumlahPenyusutan).Error; countErr != nil {
+			return errors.New("gagal memeriksa riwayat penyusutan")
+		}
+		if jumlahPenyusutan > 0 {
+			return errors.New("aset tetap yang sudah disusutkan tidak dapat dibatalkan, batalkan penyusutannya terlebih dahulu")
+		}
+
+		// Balik jurnal perolehan dalam transaction yang sama
+		sekarang := time.Now()
+		if aset.IDTransaksiPerolehan != nil {
+			if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *aset.IDTransaksiPerolehan,
+				sekarang, req.Alasan, models.TipeTransaksiAsetTetap); balikErr != nil {
+				return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+			}
+		}
+
+		if updateErr := tx.Model(&aset).Updates(map[string]interface{}{
+			"status":             models.StatusAsetBatal,
+			"tanggal_dibatalkan": sekarang,
+			"dibatalkan_oleh":    idPengguna,
+			"alasan_pembatalan":  req.Alasan,
+		}).Error; updateErr != nil {
+			return errors.New("gagal membatalkan registrasi aset tetap")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanAsetTetap(id, idKoperasi)
+}
+
+// BatalkanPenyusutan membatalkan (void) posting penyusutan satu bulan.
+//
+// Jurnal penyusutan bulan tersebut dibalik dengan jurnal pembalik tertanggal hari ini, riwayat
+// penyusutannya dihapus dan akumulasi penyusutan setiap aset dikurangi kembali. Hanya bulan
+// penyusutan terakhir yang dapat dibatalkan, dan hanya jika belum ada aset di dalamnya yang
+// dilepas, agar nilai buku yang dipakai posting dan pelepasan berikutnya tetap benar.
+func (s *AsetTetapService) BatalkanPenyusutan(idKoperasi, idPengguna uuid.UUID, req *BatalkanPenyusutanRequest) (*HasilPostingPenyusutan, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+	if req.Bulan < 1 || req.Bulan > 12 {
+		return nil, errors.New("bulan harus antara 1 dan 12")
+	}
+
+	hasil := &HasilPostingPenyusutan{Tahun: req.Tahun, Bulan: req.Bulan}
+	indeksTarget := indeksBulan(req.Tahun, time.Month(req.Bulan))
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var penyusutanList []models.PenyusutanAsetTetap
+		err := tx.Where("id_koperasi = ? AND tahun = ? AND bulan = ?", idKoperasi, req.Tahun, req.Bulan).
+			Find(&penyusutanList).Error
+		if err != nil {
+			return errors.New("gagal mengambil riwayat penyusutan")
+		}
+		if len(penyusutanList) == 0 {
+			return fmt.Errorf("penyusutan %02d/%d belum diposting", req.Bulan, req.Tahun)
+		}
+
+		var jumlahSesudah int64
+		if countErr := tx.Model(&models.PenyusutanAsetTetap{}).
+			Where("id_koperasi = ? AND tahun * 12 + bulan - 1 > ?", idKoperasi, indeksTarget).
+			Count(&jumlahSesudah).Error; countErr != nil {
+			return errors.New("gagal memeriksa riwayat penyusutan")
+		}
+		if jumlahSesudah > 0 {
+			return errors.New("hanya penyusutan bulan terakhir yang dapat dibatalkan, batalkan penyusutan bulan sesudahnya terlebih dahulu")
+		}
+
+		// Kunci aset agar tidak bersamaan dengan posting penyusutan atau pelepasan
+		idAsetList := make([]uuid.UUID, 0, len(penyusutanList))
+		for _, penyusutan := range penyusutanList {
+			idAsetList = append(idAsetList, penyusutan.IDAsetTetap)
+		}
+		var asetList []models.AsetTetap
+		if lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", idAsetList).
+			Find(&asetList).Error; lockErr != nil {
+			return errors.New("gagal mengambil daftar aset tetap")
+		}
+		asetByID := make(map[uuid.UUID]*models.AsetTetap, len(asetList))
+		for i := range asetList {
+			if asetList[i].Status != models.StatusAsetAktif {
+				return fmt.Errorf("aset %s sudah dilepas, batalkan pelepasannya terlebih dahulu", asetList[i].KodeAset)
+			}
+			asetByID[asetList[i].ID] = &asetList[i]
+		}
+
+		// Satu bulan penyusutan selalu diposting dalam satu jurnal
+		idTransaksi := penyusutanList[0].IDTransaksi
+		if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, idTransaksi,
+			time.Now(), req.Alasan, models.TipeTransaksiAsetTetap); balikErr != nil {
+			return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+		}
+
+		for _, penyusutan := range penyusutanList {
+			aset := asetByID[penyusutan.IDAsetTetap]
+			if aset == nil {
+				return errors.New("aset tetap tidak ditemukan")
+			}
+			aset.AkumulasiPenyusutan -= penyusutan.Jumlah
+			if updateErr := tx.Model(aset).Update("akumulasi_penyusutan", aset.AkumulasiPenyusutan).Error; updateErr != nil {
+				return errors.New("gagal memperbarui akumulasi penyusutan")
+			}
+			hasil.TotalPenyusutan += penyusutan.Jumlah
+		}
+
+		if deleteErr := tx.Where("id_koperasi = ? AND tahun = ? AND bulan = ?", idKoperasi, req.Tahun, req.Bulan).
+			Delete(&models.PenyusutanAsetTetap{}).Error; deleteErr != nil {
+			return errors.New("gagal menghapus riwayat penyusutan")
+		}
+
+		hasil.JumlahAset = len(penyusutanList)
+		hasil.IDTransaksi = &idTransaksi
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hasil, nil
+}
+
+// BatalkanPelepasan membatalkan (void) pelepasan aset tetap.
+//
+// Jurnal pelepasan dibalik dengan jurnal pembalik tertanggal hari ini dan aset kembali AKTIF
+// dengan akumulasi penyusutan seperti sebelum dilepas. Penyusutan bulan-bulan yang terlewat
+// selama aset berstatus DILEPAS akan diposting oleh posting penyusutan berikutnya.
+func (s *AsetTetapService) BatalkanPelepasan(idKoperasi, idPengguna, id uuid.UUID, req *BatalkanAsetTetapRequest) (*models.AsetTetapResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var aset models.AsetTetap
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&aset).Error
+		if lockErr != nil {
+			return errors.New("aset tetap tidak ditemukan")
+		}
+		if aset.Status != models.StatusAsetDilepas || aset.IDTransaksiPelepasan == nil {
+			return errors.New("aset tetap belum dilepas")
+		}
+
+		// Balik jurnal pelepasan dalam transaction yang sama
+		if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *aset.IDTransaksiPelepasan,
+			time.Now(), req.Alasan, models.TipeTransaksiAsetTetap); balikErr != nil {
+			return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+		}
+
+		if updateErr := tx.Model(&aset).Updates(map[string]interface{}{
+			"status":                 models.StatusAsetAktif,
+			"tanggal_pelepasan":      nil,
+			"nilai_pelepasan":        0,
+			"id_transaksi_pelepasan": nil,
+		}).Error; updateErr != nil {
+			return errors.New("gagal membatalkan pelepasan aset tetap")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanAsetTetap(id, idKoperasi)
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHitungPenyusutanBulan(t *testing.T) {
+	garisLurus := &models.AsetTetap{
+		HargaPerolehan:    models.Rupiah(12000000),
+		UmurEkonomisBulan: 12,
+		MetodePenyusutan:  models.PenyusutanGarisLurus,
+	}
+	assert.Equal(t, models.Rupiah(1000000), hitungPenyusutanBulan(garisLurus, 1))
+
+	// Sisa yang lebih kecil dari beban bulanan hanya disusutkan sebesar sisanya
+	garisLurus.AkumulasiPenyusutan = models.Rupiah(11500000)
+	assert.Equal(t, models.Rupiah(500000), hitungPenyusutanBulan(garisLurus, 11))
+
+	// Bulan terakhir umur ekonomis menyerap seluruh sisa sampai nilai residu
+	denganResidu := &models.AsetTetap{
+		HargaPerolehan:      models.Rupiah(10000000),
+		NilaiResidu:         models.Rupiah(1000000),
+		UmurEkonomisBulan:   7,
+		AkumulasiPenyusutan: models.Rupiah(7714285),
+		MetodePenyusutan:    models.PenyusutanGarisLurus,
+	}
+	assert.Equal(t, models.Rupiah(1285715), hitungPenyusutanBulan(denganResidu, 7))
+	assert.Equal(t, models.Rupiah(1285715), hitungPenyusutanBulan(denganResidu, 20))
+
+	saldoMenurun := &models.AsetTetap{
+		HargaPerolehan:      models.Rupiah(12000000),
+		UmurEkonomisBulan:   24,
+		AkumulasiPenyusutan: models.Rupiah(1000000),
+		MetodePenyusutan:    models.PenyusutanSaldoMenurun,
+	}
+	assert.Equal(t, models.Uang(91666667), hitungPenyusutanBulan(saldoMenurun, 2))
+
+	// Aset yang sudah habis disusutkan atau tidak disusutkan (tanah) tidak dibebani lagi
+	habis := &models.AsetTetap{HargaPerolehan: models.Rupiah(5000000), AkumulasiPenyusutan: models.Rupiah(5000000), UmurEkonomisBulan: 12}
+	assert.Equal(t, models.Uang(0), hitungPenyusutanBulan(habis, 5))
+	tanah := &models.AsetTetap{HargaPerolehan: models.Rupiah(50000000)}
+	assert.Equal(t, models.Uang(0), hitungPenyusutanBulan(tanah, 1))
+}
+
+func TestAkunDefaultKelompokAset_AdaDiSemuaTemplate(t *testing.T) {
+	for _, template := range daftarTemplateCOA {
+		kode := map[string]bool{}
+		for _, baris := range template.Akun {
+			kode[baris.KodeAkun] = true
+		}
+
+		for kelompok, akun := range akunDefaultKelompokAset {
+			for _, kodeAkun := range []string{akun.Aset, akun.Akumulasi, akun.Beban} {
+				if kodeAkun != "" {
+					assert.True(t, kode[kodeAkun], "template %s tidak memuat akun %s untuk kelompok %s",
+						template.JenisKoperasi, kodeAkun, kelompok)
+				}
+			}
+		}
+	}
+}
+
+func TestAsetTetapService(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+		&models.UnitUsaha{},
+		&models.AsetTetap{},
+		&models.PenyusutanAsetTetap{},
+	)
+	require.NoError(t, err)
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Aset Tetap Koperasi", Alamat: "Test Address", TahunBukuMulai: 1}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	simpananPokok, _ := akunService.DapatkanAkunByKode(koperasi.ID, "3101")
+
+	transaksiService := NewTransaksiService(db)
+	laporanService := NewLaporanService(db, akunService, nil, nil)
+	asetTetapService := NewAsetTetapService(db, transaksiService)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	// Aset diperoleh dua bulan lalu sehingga dua bulan penyusutan sudah dapat diposting
+	sekarang := time.Now()
+	awalBulan := time.Date(sekarang.Year(), sekarang.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -2, 0)
+	bulanLalu := awalBulan.AddDate(0, 1, 0)
+
+	_, err = transaksiService.BuatTransaksi(koperasi.ID, admin, &BuatTransaksiRequest{
+		TanggalTransaksi: awalBulan,
+		Deskripsi:        "Setoran modal awal",
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: kas.ID, JumlahDebit: models.Rupiah(30000000)},
+			{IDAkun: simpananPokok.ID, JumlahKredit: models.Rupiah(30000000)},
+		},
+	})
+	require.NoError(t, err)
+
+	kendaraan, err := asetTetapService.RegistrasiAsetTetap(koperasi.ID, admin, &RegistrasiAsetTetapRequest{
+		KodeAset:          "KND-001",
+		NamaAset:          "Mobil Operasional",
+		Kelompok:          models.KelompokAsetKendaraan,
+		TanggalPerolehan:  awalBulan,
+		HargaPerolehan:    models.Rupiah(24000000),
+		UmurEkonomisBulan: 48,
+		IDAkunPembayaran:  &kas.ID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, kendaraan.IDTransaksiPerolehan)
+	assert.Equal(t, models.PenyusutanGarisLurus, kendaraan.MetodePenyusutan)
+
+	peralatan, err := asetTetapService.RegistrasiAsetTetap(koperasi.ID, admin, &RegistrasiAsetTetapRequest{
+		KodeAset:          "PRL-001",
+		NamaAset:          "Mesin Fotokopi",
+		Kelompok:          models.KelompokAsetPeralatan,
+		TanggalPerolehan:  awalBulan,
+		HargaPerolehan:    models.Rupiah(6000000),
+		UmurEkonomisBulan: 24,
+		MetodePenyusutan:  models.PenyusutanSaldoMenurun,
+		IDAkunPembayaran:  &kas.ID,
+	})
+	require.NoError(t, err)
+
+	t.Run("validasi registrasi", func(t *testing.T) {
+		_, err := asetTetapService.RegistrasiAsetTetap(koperasi.ID, admin, &RegistrasiAsetTetapRequest{
+			KodeAset: "KND-001", NamaAset: "Kode Ganda", Kelompok: models.KelompokAsetKendaraan,
+			TanggalPerolehan: awalBulan, HargaPerolehan: models.Rupiah(1000000), UmurEkonomisBulan: 12,
+		})
+		assert.Error(t, err, "kode aset harus unik")
+
+		_, err = asetTetapService.RegistrasiAsetTetap(koperasi.ID, admin, &RegistrasiAsetTetapRequest{
+			KodeAset: "TNH-001", NamaAset: "Tanah Kantor", Kelompok: models.KelompokAsetTanah,
+			TanggalPerolehan: awalBulan, HargaPerolehan: models.Rupiah(1000000), UmurEkonomisBulan: 12,
+		})
+		assert.Error(t, err, "tanah tidak disusutkan")
+	})
+
+	t.Run("posting penyusutan bulanan tidak ganda", func(t *testing.T) {
+		hasil, err := asetTetapService.PostingPenyusutan(koperasi.ID, admin, awalBulan.Year(), int(awalBulan.Month()))
+		require.NoError(t, err)
+		assert.Equal(t, 2, hasil.JumlahAset)
+		assert.Equal(t, models.Rupiah(1000000), hasil.TotalPenyusutan)
+		require.NotNil(t, hasil.IDTransaksi)
+
+		ulang, err := asetTetapService.PostingPenyusutan(koperasi.ID, admin, awalBulan.Year(), int(awalBulan.Month()))
+		require.NoError(t, err)
+		assert.Equal(t, 0, ulang.JumlahAset)
+		assert.Nil(t, ulang.IDTransaksi)
+
+		_, err = asetTetapService.PostingPenyusutan(koperasi.ID, admin, sekarang.Year(), int(sekarang.Month()))
+		assert.Error(t, err, "bulan berjalan belum berakhir")
+	})
+
+	t.Run("posting otomatis menyusul bulan yang tertunda", func(t *testing.T) {
+		asetTetapService.PostingPenyusutanOtomatis()
+
+		detail, err := asetTetapService.DapatkanAsetTetap(kendaraan.ID, koperasi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(1000000), detail.AkumulasiPenyusutan)
+		require.Len(t, detail.Penyusutan, 2)
+		assert.Equal(t, int(bulanLalu.Month()), detail.Penyusutan[1].Bulan)
+
+		// Saldo menurun ganda: 6.000.000 x 2/24, lalu 5.500.000 x 2/24
+		detail, err = asetTetapService.DapatkanAsetTetap(peralatan.ID, koperasi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(500000)+models.Uang(45833333), detail.AkumulasiPenyusutan)
+	})
+
+	t.Run("akumulasi penyusutan mengurangi aset di neraca", func(t *testing.T) {
+		neraca, err := laporanService.GenerateLaporanPosisiKeuangan(koperasi.ID, "")
+		require.NoError(t, err)
+
+		totalPenyusutan := models.Rupiah(1500000) + models.Uang(45833333)
+		assert.Equal(t, models.Rupiah(30000000)-totalPenyusutan, neraca.TotalAset)
+		assert.Equal(t, neraca.TotalAset, neraca.TotalKewajiban+neraca.TotalModal)
+	})
+
+	t.Run("pelepasan aset mencatat laba pelepasan", func(t *testing.T) {
+		hasil, err := asetTetapService.LepasAsetTetap(koperasi.ID, admin, kendaraan.ID, &LepasAsetTetapRequest{
+			TanggalPelepasan: hariIniUTC(),
+			NilaiPelepasan:   models.Rupiah(24000000),
+			IDAkunPenerimaan: &kas.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusAsetDilepas, hasil.Status)
+		require.NotNil(t, hasil.IDTransaksiPelepasan)
+
+		jurnal, err := transaksiService.DapatkanTransaksi(koperasi.ID, *hasil.IDTransaksiPelepasan)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(25000000), jurnal.TotalDebit)
+		var laba models.Uang
+		for _, baris := range jurnal.BarisTransaksi {
+			if baris.KodeAkun == "4201" {
+				laba += baris.JumlahKredit
+			}
+		}
+		assert.Equal(t, models.Rupiah(1000000), laba)
+
+		_, err = asetTetapService.LepasAsetTetap(koperasi.ID, admin, kendaraan.ID, &LepasAsetTetapRequest{TanggalPelepasan: hariIniUTC()})
+		assert.Error(t, err, "aset yang sudah dilepas tidak dapat dilepas lagi")
+	})
+
+	t.Run("pembatalan pelepasan, penyusutan dan registrasi", func(t *testing.T) {
+		alasan := &BatalkanAsetTetapRequest{Alasan: "Salah input aset"}
+
+		_, err := asetTetapService.BatalkanPelepasan(koperasi.ID, admin, peralatan.ID, alasan)
+		assert.Error(t, err, "aset yang belum dilepas tidak dapat dibatalkan pelepasannya")
+
+		_, err = asetTetapService.BatalkanPenyusutan(koperasi.ID, admin, &BatalkanPenyusutanRequest{
+			Tahun: bulanLalu.Year(), Bulan: int(bulanLalu.Month()), Alasan: "Salah input aset",
+		})
+		assert.Error(t, err, "penyusutan aset yang sudah dilepas tidak dapat dibatalkan")
+
+		dilepas, err := asetTetapService.DapatkanAsetTetap(kendaraan.ID, koperasi.ID)
+		require.NoError(t, err)
+		hasil, err := asetTetapService.BatalkanPelepasan(koperasi.ID, admin, kendaraan.ID, alasan)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusAsetAktif, hasil.Status)
+		assert.Nil(t, hasil.IDTransaksiPelepasan)
+		assert.Nil(t, hasil.TanggalPelepasan)
+		assert.Equal(t, models.Rupiah(1000000), hasil.AkumulasiPenyusutan)
+
+		jurnal, err := transaksiService.DapatkanTransaksi(koperasi.ID, *dilepas.IDTransaksiPelepasan)
+		require.NoError(t, err)
+		assert.True(t, jurnal.Dibalik)
+
+		_, err = asetTetapService.BatalkanRegistrasiAsetTetap(koperasi.ID, admin, kendaraan.ID, alasan)
+		assert.Error(t, err, "aset yang sudah disusutkan tidak dapat dibatalkan registrasinya")
+
+		_, err = asetTetapService.BatalkanPenyusutan(koperasi.ID, admin, &BatalkanPenyusutanRequest{
+			Tahun: awalBulan.Year(), Bulan: int(awalBulan.Month()), Alasan: "Salah input aset",
+		})
+		assert.Error(t, err, "hanya penyusutan bulan terakhir yang dapat dibatalkan")
+
+		for _, bulan := range []time.Time{bulanLalu, awalBulan} {
+			batal, err := asetTetapService.BatalkanPenyusutan(koperasi.ID, admin, &BatalkanPenyusutanRequest{
+				Tahun: bulan.Year(), Bulan: int(bulan.Month()), Alasan: "Salah input aset",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 2, batal.JumlahAset)
+		}
+
+		detail, err := asetTetapService.DapatkanAsetTetap(peralatan.ID, koperasi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Uang(0), detail.AkumulasiPenyusutan)
+		assert.Empty(t, detail.Penyusutan)
+
+		batal, err := asetTetapService.BatalkanRegistrasiAsetTetap(koperasi.ID, admin, kendaraan.ID, alasan)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusAsetBatal, batal.Status)
+		assert.Equal(t, "Salah input aset", batal.AlasanPembatalan)
+
+		_, err = asetTetapService.LepasAsetTetap(koperasi.ID, admin, kendaraan.ID, &LepasAsetTetapRequest{TanggalPelepasan: hariIniUTC()})
+		assert.Error(t, err, "aset yang registrasinya dibatalkan tidak dapat dilepas")
+
+		// Tersisa kas setelah pembelian mesin fotokopi dan mesin fotokopi tanpa penyusutan
+		neraca, err := laporanService.GenerateLaporanPosisiKeuangan(koperasi.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(30000000), neraca.TotalAset)
+		assert.Equal(t, neraca.TotalAset, neraca.TotalKewajiban+neraca.TotalModal)
+	})
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// definisiPeranPosting menjelaskan satu peran akun dalam jurnal otomatis suatu peristiwa
+type definisiPeranPosting struct {
+	Peran       models.PeranAkun
+	Posisi      string // DEBIT atau KREDIT pada transaksi normal (setoran, penjualan, pencairan)
+	KodeDefault string // Kode akun COA default yang dipakai jika peran belum dipetakan
+	Nama        string // Nama peran untuk pesan kesalahan, misalnya "kas"
+}
+
+// definisiAturanPosting adalah daftar peristiwa yang dijurnal otomatis beserta peran akunnya.
+// Urutan peristiwa mengikuti daftarPeristiwaPosting.
+var definisiAturanPosting = map[models.JenisPeristiwa][]definisiPeranPosting{
+	models.PeristiwaPenjualan: {
+		{models.PeranAkunKas, "DEBIT", "1101", "kas"},
+		{models.PeranAkunPendapatan, "KREDIT", "4101", "penjualan"},
+		{models.PeranAkunHPP, "DEBIT", "5201", "HPP"},
+		{models.PeranAkunPersediaan, "KREDIT", "1301", "persediaan"},
+	},
+	models.PeristiwaSimpananPokok: {
+		{models.PeranAkunKas, "DEBIT", "1101", "kas"},
+		{models.PeranAkunSimpanan, "KREDIT", "3101", "simpanan pokok"},
+	},
+	models.PeristiwaSimpananWajib: {
+		{models.PeranAkunKas, "DEBIT", "1101", "kas"},
+		{models.PeranAkunSimpanan, "KREDIT", "3102", "simpanan wajib"},
+	},
+	models.PeristiwaSimpananSukarela: {
+		{models.PeranAkunKas, "DEBIT", "1101", "kas"},
+		{models.PeranAkunSimpanan, "KREDIT", "3103", "simpanan sukarela"},
+	},
+	models.PeristiwaPencairanPinjaman: {
+		{models.PeranAkunPiutangPinjaman, "DEBIT", "1202", "piutang pinjaman"},
+		{models.PeranAkunKas, "KREDIT", "1101", "kas"},
+	},
+	models.PeristiwaAngsuranPinjaman: {
+		{models.PeranAkunKas, "DEBIT", "1101", "kas"},
+		{models.PeranAkunPiutangPinjaman, "KREDIT", "1202", "piutang pinjaman"},
+		{models.PeranAkunPendapatanJasa, "KREDIT", "4102", "pendapatan jasa pinjaman"},
+	},
+	models.PeristiwaPembagianSHU: {
+		{models.PeranAkunSHUTahunBerjalan, "DEBIT", "3201", "SHU tahun berjalan"},
+		{models.PeranAkunDanaCadangan, "KREDIT", "3202", "dana cadangan"},
+		{models.PeranAkunSHUBagianAnggota, "KREDIT", "2102", "SHU bagian anggota"},
+		{models.PeranAkunDanaPengurus, "KREDIT", "2103", "dana pengurus"},
+		{models.PeranAkunDanaPendidikan, "KREDIT", "2104", "dana pendidikan"},
+		{models.PeranAkunDanaSosial, "KREDIT", "2105", "dana sosial"},
+	},
+	models.PeristiwaPenutupanTahun: {
+		{models.PeranAkunSHUTahunBerjalan, "KREDIT", "3201", "SHU tahun berjalan"},
+	},
+	models.PeristiwaPelepasanAset: {
+		{models.PeranAkunLabaPelepasan, "KREDIT", "4201", "laba pelepasan aset tetap"},
+		{models.PeranAkunRugiPelepasan, "DEBIT", "5301", "rugi pelepasan aset tetap"},
+	},
+	models.PeristiwaMutasiBank: {
+		{models.PeranAkunBiayaBank, "DEBIT", "5302", "biaya administrasi bank"},
+		{models.PeranAkunJasaGiro, "KREDIT", "4202", "pendapatan jasa giro"},
+	},
+	models.PeristiwaPPNKeluaran: {
+		{models.PeranAkunPPNKeluaran, "KREDIT", "2111", "PPN keluaran"},
+	},
+	models.PeristiwaPemotonganPPh21: {
+		{models.PeranAkunUtangPPh, "KREDIT", "2112", "utang PPh 21"},
+	},
+	models.PeristiwaPemotonganPPh23: {
+		{models.PeranAkunUtangPPh, "KREDIT", "2113", "utang PPh 23"},
+	},
+}
+
+// daftarPeristiwaPosting menentukan urutan tampilan aturan posting
+var daftarPeristiwaPosting = []models.JenisPeristiwa{
+	models.PeristiwaPenjualan,
+	models.PeristiwaSimpananPokok,
+	models.PeristiwaSimpananWajib,
+	models.PeristiwaSimpananSukarela,
+	models.PeristiwaPencairanPinjaman,
+	models.PeristiwaAngsuranPinjaman,
+	models.PeristiwaPembagianSHU,
+	models.PeristiwaPenutupanTahun,
+	models.PeristiwaPelepasanAset,
+	models.PeristiwaMutasiBank,
+	models.PeristiwaPPNKeluaran,
+	models.PeristiwaPemotonganPPh21,
+	models.PeristiwaPemotonganPPh23,
+}
+
+// peristiwaSimpanan memetakan tipe simpanan ke peristiwa posting
+var peristiwaSimpanan = map[models.TipeSimpanan]models.JenisPeristiwa{
+	models.SimpananPokok:    models.PeristiwaSimpananPokok,
+	models.SimpananWajib:    models.PeristiwaSimpananWajib,
+	models.SimpananSukarela: models.PeristiwaSimpananSukarela,
+}
+
+// peristiwaPPh memetakan jenis PPh ke peristiwa posting utang PPh-nya
+var peristiwaPPh = map[models.JenisPPh]models.JenisPeristiwa{
+	models.PPh21: models.PeristiwaPemotonganPPh21,
+	models.PPh23: models.PeristiwaPemotonganPPh23,
+}
+
+// cariDefinisiPeran mencari definisi peran akun dalam suatu peristiwa
+func cariDefinisiPeran(peristiwa models.JenisPeristiwa, peran models.PeranAkun) (definisiPeranPosting, bool) {
+	for _, definisi := range definisiAturanPosting[peristiwa] {
+		if definisi.Peran == peran {
+			return definisi, true
+		}
+	}
+	return definisiPeranPosting{}, false
+}
+
+// akunPostingWithTx meresolusi seluruh akun yang diperlukan jurnal otomatis suatu peristiwa.
+//
+// Peran yang dipetakan di aturan posting memakai akun yang dipetakan; peran lainnya memakai
+// kode akun COA default. Akun yang tidak ditemukan atau tidak aktif menghasilkan error.
+func akunPostingWithTx(tx *gorm.DB, idKoperasi uuid.UUID, peristiwa models.JenisPeristiwa) (map[models.PeranAkun]models.Akun, error) {
+	definisiList, ok := definisiAturanPosting[peristiwa]
+	if !ok {
+		return nil, fmt.Errorf("peristiwa posting %s tidak dikenal", peristiwa)
+	}
+
+	var aturanList []models.AturanPosting
+	if err := tx.Where("id_koperasi = ? AND jenis_peristiwa = ?", idKoperasi, peristiwa).Find(&aturanList).Error; err != nil {
+		return nil, errors.New("gagal mengambil aturan posting")
+	}
+
+	idAkunByPeran := make(map[models.PeranAkun]uuid.UUID, len(aturanList))
+	for _, aturan := range aturanList {
+		idAkunByPeran[aturan.PeranAkun] = aturan.IDAkun
+	}
+
+	akunByPeran := make(map[models.PeranAkun]models.Akun, len(definisiList))
+	for _, definisi := range definisiList {
+		query := tx.Where("id_koperasi = ?", idKoperasi)
+		if idAkun, dipetakan := idAkunByPeran[definisi.Peran]; dipetakan {
+			query = query.Where("id = ?", idAkun)
+		} else {
+			query = query.Where("kode_akun = ?", definisi.KodeDefault)
+		}
+
+		var akun models.Akun
+		if err := query.First(&akun).Error; err != nil {
+			return nil, fmt.Errorf("akun %s tidak ditemukan", definisi.Nama)
+		}
+		if !akun.StatusAktif {
+			return nil, fmt.Errorf("akun %s (%s) tidak aktif", definisi.Nama, akun.KodeAkun)
+		}
+		akunByPeran[definisi.Peran] = akun
+	}
+
+	return akunByPeran, nil
+}
+
+// AturanPostingService menangani pemetaan akun untuk jurnal otomatis
+type AturanPostingService struct {
+	db *gorm.DB
+}
+
+// NewAturanPostingService membuat instance baru AturanPostingService
+func NewAturanPostingService(db *gorm.DB) *AturanPostingService {
+	return &AturanPostingService{db: db}
+}
+
+// ItemAturanPosting adalah akun efektif untuk satu peran dalam suatu peristiwa
+type ItemAturanPosting struct {
+	JenisPeristiwa models.JenisPeristiwa `json:"jenisPeristiwa"`
+	PeranAkun      models.PeranAkun      `json:"peranAkun"`
+	Posisi         string                `json:"posisi"`
+	IDAkun         *uuid.UUID            `json:"idAkun"`
+	KodeAkun       string                `json:"kodeAkun"`
+	NamaAkun       string                `json:"namaAkun"`
+	Default        bool                  `json:"default"` // true jika memakai kode akun COA default
+}
+
+// DapatkanAturanPosting mengambil akun efektif untuk setiap peran pada setiap peristiwa
+func (s *AturanPostingService) DapatkanAturanPosting(idKoperasi uuid.UUID) ([]ItemAturanPosting, error) {
+	var aturanList []models.AturanPosting
+	if err := s.db.Preload("Akun").Where("id_koperasi = ?", idKoperasi).Find(&aturanList).Error; err != nil {
+		return nil, errors.New("gagal mengambil aturan posting")
+	}
+
+	type kunciAturan struct {
+		peristiwa models.JenisPeristiwa
+		peran     models.PeranAkun
+	}
+	aturanByKunci := make(map[kunciAturan]models.AturanPosting, len(aturanList))
+	for _, aturan := range aturanList {
+		aturanByKunci[kunciAturan{aturan.JenisPeristiwa, aturan.PeranAkun}] = aturan
+	}
+
+	var akunList []models.Akun
+	if err := s.db.Where("id_koperasi = ?", idKoperasi).Find(&akunList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar akun")
+	}
+	akunByKode := make(map[string]models.Akun, len(akunList))
+	for _, akun := range akunList {
+		akunByKode[akun.KodeAkun] = akun
+	}
+
+	var hasil []ItemAturanPosting
+	for _, peristiwa := range daftarPeristiwaPosting {
+		for _, definisi := range definisiAturanPosting[peristiwa] {
+			item := ItemAturanPosting{
+				JenisPeristiwa: peristiwa,
+				PeranAkun:      definisi.Peran,
+				Posisi:         definisi.Posisi,
+			}
+
+			if aturan, ok := aturanByKunci[kunciAturan{peristiwa, definisi.Peran}]; ok {
+				idAkun := aturan.IDAkun
+				item.IDAkun = &idAkun
+				item.KodeAkun = aturan.Akun.KodeAkun
+				item.NamaAkun = aturan.Akun.NamaAkun
+			} else {
+				item.Default = true
+				item.KodeAkun = definisi.KodeDefault
+				if akun, ada := akunByKode[definisi.KodeDefault]; ada {
+					idAkun := akun.ID
+					item.IDAkun = &idAkun
+					item.NamaAkun = akun.NamaAkun
+				}
+			}
+
+			hasil = append(hasil, item)
+		}
+	}
+
+	return hasil, nil
+}
+
+// ItemAturanPostingRequest adalah pemetaan satu peran ke akun
+type ItemAturanPostingRequest struct {
+	JenisPeristiwa models.JenisPeristiwa `json:"jenisPeristiwa" binding:"required"`
+	PeranAkun      models.PeranAkun      `json:"peranAkun" binding:"required"`
+	IDAkun         *uuid.UUID            `json:"idAkun"` // Kosong: kembali ke akun COA default
+}
+
+// PerbaruiAturanPostingRequest adalah struktur request untuk memperbarui aturan posting
+type PerbaruiAturanPostingRequest struct {
+	Aturan []ItemAturanPostingRequest `json:"aturan" binding:"required,min=1,dive"`
+}
+
+// PerbaruiAturanPosting menyimpan pemetaan akun untuk jurnal otomatis.
+// Setiap akun yang dipetakan harus milik koperasi dan aktif; seluruh pemetaan disimpan atomik.
+func (s *AturanPostingService) PerbaruiAturanPosting(idKoperasi uuid.UUID, req *PerbaruiAturanPostingRequest) ([]ItemAturanPosting, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range req.Aturan {
+			definisi, ok := cariDefinisiPeran(item.JenisPeristiwa, item.PeranAkun)
+			if !ok {
+				return fmt.Errorf("peran %s tidak dikenal untuk peristiwa %s", item.PeranAkun, item.JenisPeristiwa)
+			}
+
+			// Tanpa akun: hapus pemetaan sehingga peran kembali memakai akun default
+			if item.IDAkun == nil {
+				if err := tx.Where("id_koperasi = ? AND jenis_peristiwa = ? AND peran_akun = ?", idKoperasi, item.JenisPeristiwa, item.PeranAkun).
+					Delete(&models.AturanPosting{}).Error; err != nil {
+					return errors.New("gagal menghapus aturan posting")
+				}
+				continue
+			}
+
+			var akun models.Akun
+			if err := tx.Where("id = ? AND id_koperasi = ?", *item.IDAkun, idKoperasi).First(&akun).Error; err != nil {
+				return fmt.Errorf("akun untuk %s pada peristiwa %s tidak ditemukan", definisi.Nama, item.JenisPeristiwa)
+			}
+			if !akun.StatusAktif {
+				return fmt.Errorf("akun %s tidak aktif", akun.KodeAkun)
+			}
+
+			aturan := models.AturanPosting{
+				IDKoperasi:     idKoperasi,
+				JenisPeristiwa: item.JenisPeristiwa,
+				PeranAkun:      item.PeranAkun,
+				IDAkun:         akun.ID,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id_koperasi"}, {Name: "jenis_peristiwa"}, {Name: "peran_akun"}},
+				DoUpdates: clause.AssignmentColumns([]string{"id_akun", "tanggal_diperbarui"}),
+			}).Create(&aturan).Error; err != nil {
+				return errors.New("gagal menyimpan aturan posting")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanAturanPosting(idKoperasi)
+}
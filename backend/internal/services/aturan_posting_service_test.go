@@ -0,0 +1,145 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinisiAturanPosting_Lengkap(t *testing.T) {
+	assert.Len(t, daftarPeristiwaPosting, len(definisiAturanPosting))
+
+	for _, peristiwa := range daftarPeristiwaPosting {
+		definisiList, ok := definisiAturanPosting[peristiwa]
+		require.True(t, ok, "peristiwa %s belum didefinisikan", peristiwa)
+
+		peranUnik := map[models.PeranAkun]bool{}
+		for _, definisi := range definisiList {
+			assert.False(t, peranUnik[definisi.Peran], "peran %s ganda pada %s", definisi.Peran, peristiwa)
+			peranUnik[definisi.Peran] = true
+			assert.Contains(t, []string{"DEBIT", "KREDIT"}, definisi.Posisi)
+			assert.NotEmpty(t, definisi.KodeDefault)
+		}
+	}
+}
+
+func TestAturanPosting_PostingOtomatisMemakaiAkunYangDipetakan(t *testing.T) {
+	db := setupPeriodeTestDB(t)
+	if db == nil {
+		return
+	}
+	require.NoError(t, db.AutoMigrate(&models.Anggota{}, &models.Simpanan{}, &models.AturanPosting{}))
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test Aturan Posting", Email: "posting@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	// Koperasi menomori ulang akun kas dan simpanan wajib
+	kasToko, err := akunService.BuatAkun(koperasi.ID, &BuatAkunRequest{KodeAkun: "1110", NamaAkun: "Kas Toko", TipeAkun: models.AkunAktiva})
+	require.NoError(t, err)
+	simpananWajib, err := akunService.BuatAkun(koperasi.ID, &BuatAkunRequest{KodeAkun: "3150", NamaAkun: "Simpanan Wajib Anggota", TipeAkun: models.AkunModal})
+	require.NoError(t, err)
+
+	aturanService := NewAturanPostingService(db)
+	aturan, err := aturanService.PerbaruiAturanPosting(koperasi.ID, &PerbaruiAturanPostingRequest{
+		Aturan: []ItemAturanPostingRequest{
+			{JenisPeristiwa: models.PeristiwaSimpananWajib, PeranAkun: models.PeranAkunKas, IDAkun: &kasToko.ID},
+			{JenisPeristiwa: models.PeristiwaSimpananWajib, PeranAkun: models.PeranAkunSimpanan, IDAkun: &simpananWajib.ID},
+		},
+	})
+	require.NoError(t, err)
+
+	for _, item := range aturan {
+		if item.JenisPeristiwa == models.PeristiwaSimpananWajib {
+			assert.False(t, item.Default)
+			assert.Contains(t, []string{"1110", "3150"}, item.KodeAkun)
+		}
+		if item.JenisPeristiwa == models.PeristiwaSimpananPokok && item.PeranAkun == models.PeranAkunKas {
+			assert.True(t, item.Default)
+			assert.Equal(t, "1101", item.KodeAkun)
+		}
+	}
+
+	anggota := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "A0001", NamaLengkap: "Anggota Posting", Status: models.StatusAktif}
+	require.NoError(t, db.Create(anggota).Error)
+	pengguna := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	simpananService := NewSimpananService(db, NewTransaksiService(db))
+	setoran, err := simpananService.CatatSetoran(koperasi.ID, pengguna, &CatatSetoranRequest{
+		IDAnggota:        anggota.ID,
+		TipeSimpanan:     models.SimpananWajib,
+		TanggalTransaksi: time.Now(),
+		JumlahSetoran:    models.Rupiah(50000),
+	})
+	require.NoError(t, err)
+
+	var simpanan models.Simpanan
+	require.NoError(t, db.First(&simpanan, setoran.ID).Error)
+	require.NotNil(t, simpanan.IDTransaksi)
+
+	var barisList []models.BarisTransaksi
+	require.NoError(t, db.Where("id_transaksi = ?", *simpanan.IDTransaksi).Find(&barisList).Error)
+	require.Len(t, barisList, 2)
+	for _, baris := range barisList {
+		if baris.JumlahDebit > 0 {
+			assert.Equal(t, kasToko.ID, baris.IDAkun)
+		} else {
+			assert.Equal(t, simpananWajib.ID, baris.IDAkun)
+		}
+	}
+
+	t.Run("akun yang dipetakan tidak dapat dinonaktifkan", func(t *testing.T) {
+		nonaktif := false
+		_, err := akunService.PerbaruiAkun(koperasi.ID, kasToko.ID, &PerbaruiAkunRequest{StatusAktif: &nonaktif})
+		assert.Error(t, err)
+	})
+
+	t.Run("akun tidak aktif ditolak", func(t *testing.T) {
+		nonaktif := false
+		kasLama, err := akunService.BuatAkun(koperasi.ID, &BuatAkunRequest{KodeAkun: "1199", NamaAkun: "Kas Lama", TipeAkun: models.AkunAktiva})
+		require.NoError(t, err)
+		_, err = akunService.PerbaruiAkun(koperasi.ID, kasLama.ID, &PerbaruiAkunRequest{StatusAktif: &nonaktif})
+		require.NoError(t, err)
+
+		_, err = aturanService.PerbaruiAturanPosting(koperasi.ID, &PerbaruiAturanPostingRequest{
+			Aturan: []ItemAturanPostingRequest{{JenisPeristiwa: models.PeristiwaPenjualan, PeranAkun: models.PeranAkunKas, IDAkun: &kasLama.ID}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("akun koperasi lain ditolak", func(t *testing.T) {
+		idAsing := uuid.New()
+		_, err := aturanService.PerbaruiAturanPosting(koperasi.ID, &PerbaruiAturanPostingRequest{
+			Aturan: []ItemAturanPostingRequest{{JenisPeristiwa: models.PeristiwaPenjualan, PeranAkun: models.PeranAkunKas, IDAkun: &idAsing}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("peran tidak dikenal ditolak", func(t *testing.T) {
+		_, err := aturanService.PerbaruiAturanPosting(koperasi.ID, &PerbaruiAturanPostingRequest{
+			Aturan: []ItemAturanPostingRequest{{JenisPeristiwa: models.PeristiwaPenjualan, PeranAkun: models.PeranAkunSimpanan, IDAkun: &kasToko.ID}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("tanpa akun kembali ke akun default", func(t *testing.T) {
+		aturan, err := aturanService.PerbaruiAturanPosting(koperasi.ID, &PerbaruiAturanPostingRequest{
+			Aturan: []ItemAturanPostingRequest{{JenisPeristiwa: models.PeristiwaSimpananWajib, PeranAkun: models.PeranAkunKas}},
+		})
+		require.NoError(t, err)
+
+		for _, item := range aturan {
+			if item.JenisPeristiwa == models.PeristiwaSimpananWajib && item.PeranAkun == models.PeranAkunKas {
+				assert.True(t, item.Default)
+				assert.Equal(t, "1101", item.KodeAkun)
+			}
+		}
+	})
+}
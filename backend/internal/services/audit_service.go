@@ -0,0 +1,278 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/audit"
+	"cooperative-erp-lite/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ukuranBatchLogAudit membatasi banyaknya entri yang disegel atau diverifikasi sekaligus
+const ukuranBatchLogAudit = 1000
+
+// maksMasalahLogAudit membatasi banyaknya masalah yang dilaporkan satu verifikasi
+const maksMasalahLogAudit = 100
+
+// AuditService menangani penyegelan, penelusuran dan verifikasi log audit.
+// Entri log audit ditulis oleh plugin audit pada setiap perubahan data; service ini tidak
+// pernah mengubah isi entri, hanya mengisi Urutan dan Hash saat penyegelan.
+type AuditService struct {
+	db *gorm.DB
+}
+
+// NewAuditService membuat instance baru AuditService
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// FilterLogAudit adalah filter penelusuran log audit
+type FilterLogAudit struct {
+	Entitas    string
+	IDEntitas  *uuid.UUID
+	IDPengguna *uuid.UUID
+	IDRequest  string
+}
+
+// MasalahLogAudit adalah satu temuan verifikasi rantai hash
+type MasalahLogAudit struct {
+	Urutan     int64     `json:"urutan"`
+	IDLog      uuid.UUID `json:"idLog"`
+	Keterangan string    `json:"keterangan"`
+}
+
+// HasilVerifikasiLogAudit adalah hasil verifikasi rantai hash log audit satu koperasi.
+// HashTerakhir dapat dicatat di luar sistem (misalnya di berita acara pemeriksaan) sebagai
+// jangkar: penghapusan entri paling akhir hanya terdeteksi dengan membandingkannya.
+type HasilVerifikasiLogAudit struct {
+	Valid              bool              `json:"valid"`
+	JumlahEntri        int64             `json:"jumlahEntri"`
+	JumlahBelumDisegel int64             `json:"jumlahBelumDisegel"`
+	HashTerakhir       string            `json:"hashTerakhir"`
+	Masalah            []MasalahLogAudit `json:"masalah"`
+	DiverifikasiPada   time.Time         `json:"diverifikasiPada"`
+}
+
+// SegelLogAudit merangkai entri log audit koperasi yang belum disegel ke rantai hash,
+// berurutan sesuai urutan penulisannya. Mengembalikan jumlah entri yang disegel.
+func (s *AuditService) SegelLogAudit(idKoperasi uuid.UUID) (int, error) {
+	total := 0
+	for {
+		jumlah, err := s.segelBatchLogAudit(idKoperasi)
+		total += jumlah
+		if err != nil {
+			return total, err
+		}
+		if jumlah < ukuranBatchLogAudit {
+			return total, nil
+		}
+	}
+}
+
+func (s *AuditService) segelBatchLogAudit(idKoperasi uuid.UUID) (int, error) {
+	var jumlah int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Satu penyegel per koperasi agar dua proses tidak memberi urutan yang sama
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", generateAdvisoryLockKey(idKoperasi, "log_audit")).Error; err != nil {
+			return errors.New("gagal mengunci log audit")
+		}
+
+		var entriList []models.LogAudit
+		err := tx.Where("id_koperasi = ? AND hash IS NULL", idKoperasi).
+			Order("nomor ASC").
+			Limit(ukuranBatchLogAudit).
+			Find(&entriList).Error
+		if err != nil {
+			return errors.New("gagal mengambil log audit yang belum disegel")
+		}
+		if len(entriList) == 0 {
+			return nil
+		}
+
+		var urutan int64
+		var hashSebelumnya string
+		var terakhir models.LogAudit
+		err = tx.Where("id_koperasi = ? AND urutan IS NOT NULL", idKoperasi).Order("urutan DESC").Take(&terakhir).Error
+		if err == nil {
+			urutan = *terakhir.Urutan
+			if terakhir.Hash != nil {
+				hashSebelumnya = *terakhir.Hash
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("gagal mengambil log audit terakhir")
+		}
+
+		for i := range entriList {
+			urutan++
+			entri := &entriList[i]
+			entri.Urutan = &urutan
+			entri.HashSebelumnya = hashSebelumnya
+			hash := audit.HashEntri(entri)
+
+			err := tx.Model(&models.LogAudit{}).
+				Where("id = ? AND hash IS NULL", entri.ID).
+				Updates(map[string]interface{}{
+					"urutan":          urutan,
+					"hash_sebelumnya": hashSebelumnya,
+					"hash":            hash,
+				}).Error
+			if err != nil {
+				return errors.New("gagal menyegel log audit")
+			}
+			hashSebelumnya = hash
+		}
+		jumlah = len(entriList)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return jumlah, nil
+}
+
+// SegelSemuaLogAudit menyegel log audit semua koperasi yang memiliki entri belum disegel
+func (s *AuditService) SegelSemuaLogAudit() {
+	var koperasiList []uuid.UUID
+	if err := s.db.Model(&models.LogAudit{}).Where("hash IS NULL").Distinct().Pluck("id_koperasi", &koperasiList).Error; err != nil {
+		log.Printf("Gagal mengambil koperasi dengan log audit belum disegel: %v", err)
+		return
+	}
+
+	for _, idKoperasi := range koperasiList {
+		if _, err := s.SegelLogAudit(idKoperasi); err != nil {
+			log.Printf("Gagal menyegel log audit koperasi %s: %v", idKoperasi, err)
+		}
+	}
+}
+
+// MulaiPenyegelanOtomatis menjalankan SegelSemuaLogAudit secara berkala di background.
+// Panggil fungsi stop yang dikembalikan untuk menghentikan goroutine.
+func (s *AuditService) MulaiPenyegelanOtomatis(interval time.Duration) (stop func()) {
+	stopChan := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.SegelSemuaLogAudit()
+		for {
+			select {
+			case <-ticker.C:
+				s.SegelSemuaLogAudit()
+			case <-stopChan:
+				return // Graceful shutdown
+			}
+		}
+	}()
+
+	return func() { close(stopChan) }
+}
+
+// DapatkanLogAudit mengambil log audit koperasi, terbaru lebih dulu
+func (s *AuditService) DapatkanLogAudit(idKoperasi uuid.UUID, filter FilterLogAudit, page, pageSize int) ([]models.LogAudit, int64, error) {
+	query := s.db.Model(&models.LogAudit{}).Where("id_koperasi = ?", idKoperasi)
+	if filter.Entitas != "" {
+		query = query.Where("entitas = ?", filter.Entitas)
+	}
+	if filter.IDEntitas != nil {
+		query = query.Where("id_entitas = ?", *filter.IDEntitas)
+	}
+	if filter.IDPengguna != nil {
+		query = query.Where("id_pengguna = ?", *filter.IDPengguna)
+	}
+	if filter.IDRequest != "" {
+		query = query.Where("id_request = ?", filter.IDRequest)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.New("gagal menghitung log audit")
+	}
+
+	var logList []models.LogAudit
+	offset := (page - 1) * pageSize
+	if err := query.Order("nomor DESC").Offset(offset).Limit(pageSize).Find(&logList).Error; err != nil {
+		return nil, 0, errors.New("gagal mengambil log audit")
+	}
+
+	return logList, total, nil
+}
+
+// VerifikasiLogAudit menyegel entri yang belum disegel lalu menghitung ulang rantai hash log
+// audit koperasi dari awal. Entri yang isinya diubah, dihapus, disisipkan atau ditukar urutannya
+// setelah disegel dilaporkan sebagai masalah.
+func (s *AuditService) VerifikasiLogAudit(idKoperasi uuid.UUID) (*HasilVerifikasiLogAudit, error) {
+	if _, err := s.SegelLogAudit(idKoperasi); err != nil {
+		return nil, err
+	}
+
+	hasil := &HasilVerifikasiLogAudit{Masalah: []MasalahLogAudit{}, DiverifikasiPada: time.Now()}
+	catat := func(entri *models.LogAudit, keterangan string) {
+		if len(hasil.Masalah) >= maksMasalahLogAudit {
+			return
+		}
+		masalah := MasalahLogAudit{IDLog: entri.ID, Keterangan: keterangan}
+		if entri.Urutan != nil {
+			masalah.Urutan = *entri.Urutan
+		}
+		hasil.Masalah = append(hasil.Masalah, masalah)
+	}
+
+	var urutanSebelumnya int64
+	var hashSebelumnya string
+	for {
+		var entriList []models.LogAudit
+		err := s.db.Where("id_koperasi = ? AND urutan > ?", idKoperasi, urutanSebelumnya).
+			Order("urutan ASC").
+			Limit(ukuranBatchLogAudit).
+			Find(&entriList).Error
+		if err != nil {
+			return nil, errors.New("gagal mengambil log audit")
+		}
+
+		for i := range entriList {
+			entri := &entriList[i]
+			urutan := *entri.Urutan
+			if urutan != urutanSebelumnya+1 {
+				catat(entri, fmt.Sprintf("entri urutan %d sampai %d hilang", urutanSebelumnya+1, urutan-1))
+			}
+			if entri.HashSebelumnya != hashSebelumnya {
+				catat(entri, "hash sebelumnya tidak cocok dengan entri sebelumnya")
+			}
+			if entri.Hash == nil {
+				catat(entri, "entri bernomor urut tidak memiliki hash")
+			} else if *entri.Hash != audit.HashEntri(entri) {
+				catat(entri, "isi entri berubah setelah disegel")
+			}
+
+			urutanSebelumnya = urutan
+			if entri.Hash != nil {
+				hashSebelumnya = *entri.Hash
+			}
+			hasil.JumlahEntri++
+		}
+
+		if len(entriList) < ukuranBatchLogAudit {
+			break
+		}
+	}
+	hasil.HashTerakhir = hashSebelumnya
+
+	// Hash tanpa urutan tidak pernah dibuat penyegel
+	var tanpaUrutan []models.LogAudit
+	s.db.Where("id_koperasi = ? AND urutan IS NULL AND hash IS NOT NULL", idKoperasi).
+		Limit(maksMasalahLogAudit).
+		Find(&tanpaUrutan)
+	for i := range tanpaUrutan {
+		catat(&tanpaUrutan[i], "entri memiliki hash tetapi tidak memiliki nomor urut")
+	}
+
+	s.db.Model(&models.LogAudit{}).Where("id_koperasi = ? AND hash IS NULL", idKoperasi).Count(&hasil.JumlahBelumDisegel)
+
+	hasil.Valid = len(hasil.Masalah) == 0
+	return hasil, nil
+}
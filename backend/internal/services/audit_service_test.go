@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"cooperative-erp-lite/internal/audit"
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	require.NoError(t, db.AutoMigrate(&models.Pengguna{}, &models.Produk{}, &models.Penjualan{}, &models.ItemPenjualan{}, &models.LogAudit{}))
+	require.NoError(t, db.Use(audit.Plugin{}))
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Audit Koperasi", Alamat: "Test Address"}
+	require.NoError(t, db.Create(koperasi).Error)
+	defer cleanupTestData(db, koperasi.ID)
+
+	admin := uuid.New()
+	ctx := audit.DenganInfo(context.Background(), audit.Info{IDPengguna: &admin, AlamatIP: "10.0.0.7", IDRequest: "req-audit-1"})
+	anggotaService := NewAnggotaService(db).DenganKonteks(ctx)
+	auditService := NewAuditService(db)
+
+	anggota, err := anggotaService.BuatAnggota(koperasi.ID, &BuatAnggotaRequest{NamaLengkap: "Siti Aminah"})
+	require.NoError(t, err)
+	_, err = anggotaService.PerbaruiAnggota(koperasi.ID, anggota.ID, &PerbaruiAnggotaRequest{NamaLengkap: "Siti Aminah Putri"})
+	require.NoError(t, err)
+	require.NoError(t, anggotaService.HapusAnggota(koperasi.ID, anggota.ID))
+
+	logList, total, err := auditService.DapatkanLogAudit(koperasi.ID, FilterLogAudit{Entitas: "anggota", IDEntitas: &anggota.ID}, 1, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+
+	// Terbaru lebih dulu
+	assert.Equal(t, models.AksiAuditHapus, logList[0].Aksi)
+	assert.Equal(t, models.AksiAuditUbah, logList[1].Aksi)
+	assert.Equal(t, models.AksiAuditBuat, logList[2].Aksi)
+	assert.Contains(t, logList[1].DataSebelum, `"Siti Aminah"`)
+	assert.Contains(t, logList[1].DataSesudah, `"Siti Aminah Putri"`)
+	assert.Empty(t, logList[0].DataSesudah)
+	for _, entri := range logList {
+		require.NotNil(t, entri.IDPengguna)
+		assert.Equal(t, admin, *entri.IDPengguna)
+		assert.Equal(t, "10.0.0.7", entri.AlamatIP)
+		assert.Equal(t, "req-audit-1", entri.IDRequest)
+		assert.NotContains(t, entri.DataSesudah, "pin_portal", "kolom rahasia tidak dicatat")
+	}
+
+	t.Run("rantai utuh", func(t *testing.T) {
+		hasil, err := auditService.VerifikasiLogAudit(koperasi.ID)
+		require.NoError(t, err)
+		assert.True(t, hasil.Valid, "masalah: %v", hasil.Masalah)
+		assert.Equal(t, int64(4), hasil.JumlahEntri, "koperasi dan tiga perubahan anggota")
+		assert.Zero(t, hasil.JumlahBelumDisegel)
+		assert.Len(t, hasil.HashTerakhir, 64)
+	})
+
+	t.Run("isi entri diubah terdeteksi", func(t *testing.T) {
+		require.NoError(t, db.Exec("UPDATE log_audit SET data_sesudah = ? WHERE id = ?", `{"nama_lengkap":"Budi"}`, logList[1].ID).Error)
+
+		hasil, err := auditService.VerifikasiLogAudit(koperasi.ID)
+		require.NoError(t, err)
+		assert.False(t, hasil.Valid)
+		require.Len(t, hasil.Masalah, 1)
+		assert.Equal(t, logList[1].ID, hasil.Masalah[0].IDLog)
+	})
+
+	t.Run("entri dihapus terdeteksi", func(t *testing.T) {
+		require.NoError(t, db.Exec("DELETE FROM log_audit WHERE id = ?", logList[1].ID).Error)
+
+		hasil, err := auditService.VerifikasiLogAudit(koperasi.ID)
+		require.NoError(t, err)
+		assert.False(t, hasil.Valid)
+		assert.NotEmpty(t, hasil.Masalah)
+		assert.Equal(t, logList[0].ID, hasil.Masalah[0].IDLog)
+	})
+
+	t.Run("item penjualan dicatat dengan koperasi penjualannya", func(t *testing.T) {
+		kasir := buatPenggunaTest(t, db, koperasi.ID, models.PeranKasir)
+		produk := &models.Produk{IDKoperasi: koperasi.ID, KodeProduk: "AUD-001", NamaProduk: "Beras 5kg", Harga: models.Rupiah(75000)}
+		require.NoError(t, db.Create(produk).Error)
+
+		penjualan := &models.Penjualan{
+			IDKoperasi:       koperasi.ID,
+			NomorPenjualan:   "AUD-20250101-0001",
+			TanggalPenjualan: time.Now(),
+			TotalBelanja:     models.Rupiah(75000),
+			JumlahBayar:      models.Rupiah(75000),
+			IDKasir:          kasir,
+			ItemPenjualan: []models.ItemPenjualan{
+				{IDProduk: produk.ID, NamaProduk: produk.NamaProduk, Kuantitas: 1, HargaSatuan: models.Rupiah(75000)},
+			},
+		}
+		require.NoError(t, db.WithContext(ctx).Create(penjualan).Error)
+
+		_, total, err := auditService.DapatkanLogAudit(koperasi.ID, FilterLogAudit{Entitas: "penjualan", IDEntitas: &penjualan.ID}, 1, 20)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+
+		idItem := penjualan.ItemPenjualan[0].ID
+		itemList, total, err := auditService.DapatkanLogAudit(koperasi.ID, FilterLogAudit{Entitas: "item_penjualan", IDEntitas: &idItem}, 1, 20)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total)
+		assert.Equal(t, models.AksiAuditBuat, itemList[0].Aksi)
+		assert.Contains(t, itemList[0].DataSesudah, penjualan.ID.String())
+	})
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"cooperative-erp-lite/pkg/xlsx"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FormatBerkas mendefinisikan format berkas untuk impor dan ekspor data
+type FormatBerkas string
+
+const (
+	FormatCSV  FormatBerkas = "csv"
+	FormatXLSX FormatBerkas = "xlsx"
+	FormatPDF  FormatBerkas = "pdf" // Hanya untuk ekspor laporan
+)
+
+// IsValid memeriksa apakah format berkas didukung untuk impor dan ekspor data
+func (f FormatBerkas) IsValid() bool {
+	return f == FormatCSV || f == FormatXLSX
+}
+
+// IsValidLaporan memeriksa apakah format berkas didukung untuk ekspor laporan
+func (f FormatBerkas) IsValidLaporan() bool {
+	return f == FormatCSV || f == FormatXLSX || f == FormatPDF
+}
+
+// TipeKonten mengembalikan MIME type berkas untuk header Content-Type
+func (f FormatBerkas) TipeKonten() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// tabelImpor adalah isi berkas impor tabular: header dipetakan ke indeks kolom,
+// baris berisi data mulai dari baris kedua berkas
+type tabelImpor struct {
+	indeks map[string]int
+	baris  [][]string
+}
+
+// bacaTabelImpor membaca berkas CSV atau XLSX dengan header pada baris pertama.
+// Nama kolom dinormalisasi agar "Kode Akun", "kode_akun" dan "kodeAkun" dikenali sama;
+// kolomWajib yang tidak ada di header menghasilkan error.
+func bacaTabelImpor(format FormatBerkas, data []byte, kolomWajib ...string) (*tabelImpor, error) {
+	var (
+		sel [][]string
+		err error
+	)
+	switch format {
+	case FormatCSV:
+		r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		sel, err = r.ReadAll()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("berkas CSV tidak valid pada baris %d", parseErr.Line)
+			}
+			return nil, errors.New("berkas CSV tidak valid")
+		}
+	case FormatXLSX:
+		sel, err = xlsx.Baca(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("format %s tidak didukung", format)
+	}
+
+	if len(sel) == 0 {
+		return nil, errors.New("berkas impor kosong")
+	}
+
+	tabel := &tabelImpor{
+		indeks: make(map[string]int, len(sel[0])),
+		baris:  sel[1:],
+	}
+	for i, header := range sel[0] {
+		tabel.indeks[normalisasiHeader(header)] = i
+	}
+	for _, wajib := range kolomWajib {
+		if _, ada := tabel.indeks[normalisasiHeader(wajib)]; !ada {
+			return nil, fmt.Errorf("kolom %s tidak ditemukan di header berkas", wajib)
+		}
+	}
+
+	return tabel, nil
+}
+
+// ambil mengembalikan isi kolom pada baris, atau string kosong jika kolom tidak ada
+func (t *tabelImpor) ambil(baris []string, kolom string) string {
+	i, ada := t.indeks[normalisasiHeader(kolom)]
+	if !ada || i >= len(baris) {
+		return ""
+	}
+	return strings.TrimSpace(baris[i])
+}
+
+// normalisasiHeader menyeragamkan nama kolom: huruf kecil tanpa spasi, garis bawah atau tanda hubung
+func normalisasiHeader(header string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(header)))
+}
+
+// barisKosong memeriksa apakah semua sel dalam baris kosong
+func barisKosong(baris []string) bool {
+	for _, nilai := range baris {
+		if strings.TrimSpace(nilai) != "" {
+			return false
+		}
+	}
+	return true
+}
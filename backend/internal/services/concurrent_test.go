@@ -27,6 +27,20 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&models.Anggota{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+		&models.Anggaran{},
+		&models.BarisAnggaran{},
+		&models.UnitUsaha{},
+		&models.AsetTetap{},
+		&models.PenyusutanAsetTetap{},
+		&models.TemplateJurnal{},
+		&models.BarisTemplateJurnal{},
+		&models.RekeningKoranBank{},
+		&models.MutasiBank{},
+		&models.Lampiran{},
+		&models.LogAudit{},
 		&models.Simpanan{},
 		&models.Penjualan{},
 		&models.ItemPenjualan{},
@@ -43,15 +57,29 @@ func setupTestDB(t *testing.T) *gorm.DB {
 // cleanupTestData removes test data including soft-deleted records
 func cleanupTestData(db *gorm.DB, koperasiID uuid.UUID) {
 	// Delete in correct order to respect foreign keys
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Lampiran{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.MutasiBank{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.RekeningKoranBank{})
 	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Penjualan{})
 	db.Unscoped().Exec("DELETE FROM baris_transaksi WHERE id_transaksi IN (SELECT id FROM transaksi WHERE id_koperasi = ?)", koperasiID)
 	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Simpanan{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.PemotonganPPh{})
 	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Transaksi{})
 	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Produk{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.AturanPosting{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.SaldoBulananAkun{})
+	db.Unscoped().Exec("DELETE FROM baris_anggaran WHERE id_anggaran IN (SELECT id FROM anggaran WHERE id_koperasi = ?)", koperasiID)
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Anggaran{})
+	db.Unscoped().Exec("DELETE FROM baris_template_jurnal WHERE id_template_jurnal IN (SELECT id FROM template_jurnal WHERE id_koperasi = ?)", koperasiID)
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.TemplateJurnal{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.PenyusutanAsetTetap{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.AsetTetap{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.UnitUsaha{})
 	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Akun{})
 	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Anggota{})
 	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.Pengguna{})
 	db.Unscoped().Where("id = ?", koperasiID).Delete(&models.Koperasi{})
+	db.Unscoped().Where("id_koperasi = ?", koperasiID).Delete(&models.LogAudit{})
 }
 
 // TestGenerateNomorAnggota_Concurrent tests concurrent member number generation
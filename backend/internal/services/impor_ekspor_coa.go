@@ -0,0 +1,376 @@
+package services
+
+import (
+	"bytes"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"cooperative-erp-lite/pkg/xlsx"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AksiImporCOA menjelaskan apa yang dilakukan impor terhadap satu baris
+type AksiImporCOA string
+
+const (
+	AksiImporBaru      AksiImporCOA = "BARU"       // Akun baru dibuat
+	AksiImporPerbarui  AksiImporCOA = "PERBARUI"   // Akun dengan kode yang sama diperbarui
+	AksiImporTidakUbah AksiImporCOA = "TIDAK_UBAH" // Akun sudah sama persis
+)
+
+// kolomCOA adalah urutan kolom berkas ekspor COA, sekaligus header yang dikenali saat impor
+var kolomCOA = []string{"kodeAkun", "namaAkun", "tipeAkun", "kodeInduk", "normalSaldo", "kategoriArusKas", "deskripsi", "statusAktif"}
+
+// KesalahanImporCOA adalah kesalahan validasi pada satu baris berkas impor
+type KesalahanImporCOA struct {
+	Baris    int    `json:"baris"`
+	KodeAkun string `json:"kodeAkun,omitempty"`
+	Pesan    string `json:"pesan"`
+}
+
+// RincianImporCOA adalah rencana atau hasil impor untuk satu baris berkas
+type RincianImporCOA struct {
+	Baris    int          `json:"baris"`
+	KodeAkun string       `json:"kodeAkun"`
+	NamaAkun string       `json:"namaAkun"`
+	Aksi     AksiImporCOA `json:"aksi"`
+}
+
+// HasilImporCOA adalah ringkasan impor COA. Jika ada kesalahan, tidak ada akun yang disimpan.
+type HasilImporCOA struct {
+	DryRun           bool                `json:"dryRun"`
+	Disimpan         bool                `json:"disimpan"`
+	JumlahBaris      int                 `json:"jumlahBaris"`
+	JumlahBaru       int                 `json:"jumlahBaru"`
+	JumlahDiperbarui int                 `json:"jumlahDiperbarui"`
+	Kesalahan        []KesalahanImporCOA `json:"kesalahan"`
+	Rincian          []RincianImporCOA   `json:"rincian"`
+}
+
+// barisImporCOA adalah baris berkas impor beserta nomor barisnya
+type barisImporCOA struct {
+	BarisCOA
+	Nomor       int
+	StatusAktif *bool // nil: kolom statusAktif kosong
+}
+
+// EksporCOA menghasilkan berkas CSV atau XLSX berisi seluruh akun koperasi
+func (s *AkunService) EksporCOA(idKoperasi uuid.UUID, format FormatBerkas) ([]byte, error) {
+	if !format.IsValid() {
+		return nil, fmt.Errorf("format %s tidak didukung", format)
+	}
+
+	var akunList []models.Akun
+	err := s.db.Where("id_koperasi = ?", idKoperasi).
+		Preload("AkunInduk").
+		Order("kode_akun ASC").
+		Find(&akunList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar akun")
+	}
+
+	baris := make([][]string, 0, len(akunList)+1)
+	baris = append(baris, kolomCOA)
+	for _, akun := range akunList {
+		kodeInduk := ""
+		if akun.AkunInduk != nil {
+			kodeInduk = akun.AkunInduk.KodeAkun
+		}
+		baris = append(baris, []string{
+			akun.KodeAkun,
+			akun.NamaAkun,
+			string(akun.TipeAkun),
+			kodeInduk,
+			akun.NormalSaldo,
+			string(akun.KategoriArusKas),
+			akun.Deskripsi,
+			strconv.FormatBool(akun.StatusAktif),
+		})
+	}
+
+	var buf bytes.Buffer
+	if format == FormatXLSX {
+		err = xlsx.Tulis(&buf, "COA", baris)
+	} else {
+		w := csv.NewWriter(&buf)
+		err = w.WriteAll(baris)
+	}
+	if err != nil {
+		return nil, errors.New("gagal membuat berkas ekspor COA")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ImporCOA memvalidasi dan menyimpan akun dari berkas CSV atau XLSX.
+//
+// Baris pertama berkas adalah header dengan nama kolom seperti pada ekspor (urutan bebas);
+// kodeAkun, namaAkun dan tipeAkun wajib ada. Akun yang kodenya sudah ada diperbarui,
+// selain itu dibuat baru. Kode induk boleh merujuk akun di berkas yang sama maupun akun
+// yang sudah ada. Dengan dryRun, atau jika ada kesalahan, tidak ada perubahan yang disimpan.
+func (s *AkunService) ImporCOA(idKoperasi uuid.UUID, format FormatBerkas, data []byte, dryRun bool) (*HasilImporCOA, error) {
+	barisList, err := bacaBerkasCOA(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var akunList []models.Akun
+	if err := s.db.Where("id_koperasi = ?", idKoperasi).Find(&akunList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar akun")
+	}
+
+	hasil := &HasilImporCOA{
+		DryRun:      dryRun,
+		JumlahBaris: len(barisList),
+		Kesalahan:   []KesalahanImporCOA{},
+		Rincian:     []RincianImporCOA{},
+	}
+	s.validasiImporCOA(barisList, akunList, hasil)
+
+	if len(hasil.Kesalahan) > 0 || dryRun {
+		return hasil, nil
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return simpanImporCOA(tx, idKoperasi, barisList, akunList)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan impor COA: %w", err)
+	}
+
+	hasil.Disimpan = true
+	return hasil, nil
+}
+
+// validasiImporCOA memeriksa setiap baris dan mengisi rincian serta kesalahan hasil impor
+func (s *AkunService) validasiImporCOA(barisList []barisImporCOA, akunList []models.Akun, hasil *HasilImporCOA) {
+	validator := validasi.Baru()
+
+	tambahKesalahan := func(baris barisImporCOA, pesan string) {
+		hasil.Kesalahan = append(hasil.Kesalahan, KesalahanImporCOA{Baris: baris.Nomor, KodeAkun: baris.KodeAkun, Pesan: pesan})
+	}
+
+	akunByKode := make(map[string]models.Akun, len(akunList))
+	kodeByID := make(map[uuid.UUID]string, len(akunList))
+	for _, akun := range akunList {
+		akunByKode[akun.KodeAkun] = akun
+		kodeByID[akun.ID] = akun.KodeAkun
+	}
+
+	// Hierarki efektif setelah impor: akun yang ada, ditimpa oleh isi berkas
+	tipeByKode := make(map[string]models.TipeAkun, len(akunList)+len(barisList))
+	indukByKode := make(map[string]string, len(akunList)+len(barisList))
+	for _, akun := range akunList {
+		tipeByKode[akun.KodeAkun] = akun.TipeAkun
+		if akun.IDInduk != nil {
+			indukByKode[akun.KodeAkun] = kodeByID[*akun.IDInduk]
+		}
+	}
+
+	barisByKode := make(map[string]int, len(barisList))
+	for _, baris := range barisList {
+		if nomor, ganda := barisByKode[baris.KodeAkun]; ganda && baris.KodeAkun != "" {
+			tambahKesalahan(baris, fmt.Sprintf("kode akun ganda, sudah dipakai di baris %d", nomor))
+			continue
+		}
+		barisByKode[baris.KodeAkun] = baris.Nomor
+		tipeByKode[baris.KodeAkun] = baris.TipeAkun
+		indukByKode[baris.KodeAkun] = baris.KodeInduk
+	}
+
+	for _, baris := range barisList {
+		if baris.KodeAkun != "" && barisByKode[baris.KodeAkun] != baris.Nomor {
+			continue // kode ganda sudah dilaporkan
+		}
+
+		if err := validator.KodeAkun(baris.KodeAkun); err != nil {
+			tambahKesalahan(baris, err.Error())
+			continue
+		}
+		if err := validator.TeksWajib(baris.NamaAkun, "nama akun", 3, 255); err != nil {
+			tambahKesalahan(baris, err.Error())
+		}
+		if err := validator.TeksOpsional(baris.Deskripsi, "deskripsi", 500); err != nil {
+			tambahKesalahan(baris, err.Error())
+		}
+		if err := validator.Enum(string(baris.TipeAkun), "tipe akun", []string{
+			string(models.AkunAktiva), string(models.AkunKewajiban), string(models.AkunModal),
+			string(models.AkunPendapatan), string(models.AkunBeban),
+		}); err != nil {
+			tambahKesalahan(baris, err.Error())
+		}
+		if baris.NormalSaldo != "" && baris.NormalSaldo != "DEBIT" && baris.NormalSaldo != "KREDIT" {
+			tambahKesalahan(baris, "normal saldo harus DEBIT atau KREDIT")
+		}
+		if baris.KategoriArusKas != "" && !baris.KategoriArusKas.IsValid() {
+			tambahKesalahan(baris, "kategori arus kas tidak valid")
+		}
+
+		if baris.KodeInduk != "" {
+			tipeInduk, adaInduk := tipeByKode[baris.KodeInduk]
+			switch {
+			case baris.KodeInduk == baris.KodeAkun:
+				tambahKesalahan(baris, "akun tidak boleh menjadi induk dirinya sendiri")
+			case !adaInduk:
+				tambahKesalahan(baris, fmt.Sprintf("akun induk %s tidak ditemukan", baris.KodeInduk))
+			case tipeInduk != baris.TipeAkun:
+				tambahKesalahan(baris, fmt.Sprintf("tipe akun induk %s (%s) berbeda dengan tipe akun", baris.KodeInduk, tipeInduk))
+			case adaSiklusInduk(baris.KodeAkun, indukByKode):
+				tambahKesalahan(baris, "hierarki akun induk membentuk siklus")
+			}
+		}
+
+		aksi := AksiImporBaru
+		if akun, ada := akunByKode[baris.KodeAkun]; ada {
+			if akun.TipeAkun != baris.TipeAkun {
+				tambahKesalahan(baris, fmt.Sprintf("tipe akun tidak dapat diubah dari %s", akun.TipeAkun))
+			}
+			if baris.NormalSaldo != "" && baris.NormalSaldo != akun.NormalSaldo {
+				tambahKesalahan(baris, fmt.Sprintf("normal saldo tidak dapat diubah dari %s", akun.NormalSaldo))
+			}
+			if baris.StatusAktif != nil && !*baris.StatusAktif && akun.StatusAktif && s.dipakaiAturanPosting(akun.ID) {
+				tambahKesalahan(baris, "tidak dapat menonaktifkan akun yang dipakai aturan posting")
+			}
+
+			aksi = AksiImporTidakUbah
+			if akun.NamaAkun != baris.NamaAkun ||
+				akun.Deskripsi != baris.Deskripsi ||
+				akun.KategoriArusKas != baris.KategoriArusKas ||
+				indukByKode[baris.KodeAkun] != kodeIndukAkun(akun, kodeByID) ||
+				(baris.StatusAktif != nil && *baris.StatusAktif != akun.StatusAktif) {
+				aksi = AksiImporPerbarui
+			}
+		}
+
+		switch aksi {
+		case AksiImporBaru:
+			hasil.JumlahBaru++
+		case AksiImporPerbarui:
+			hasil.JumlahDiperbarui++
+		}
+		hasil.Rincian = append(hasil.Rincian, RincianImporCOA{
+			Baris:    baris.Nomor,
+			KodeAkun: baris.KodeAkun,
+			NamaAkun: baris.NamaAkun,
+			Aksi:     aksi,
+		})
+	}
+}
+
+// simpanImporCOA membuat dan memperbarui akun hasil impor yang sudah tervalidasi.
+// Akun disimpan dulu tanpa induk, lalu induk diresolusi setelah semua kode punya ID,
+// sehingga urutan baris di berkas tidak berpengaruh.
+func simpanImporCOA(tx *gorm.DB, idKoperasi uuid.UUID, barisList []barisImporCOA, akunList []models.Akun) error {
+	akunByKode := make(map[string]models.Akun, len(akunList)+len(barisList))
+	for _, akun := range akunList {
+		akunByKode[akun.KodeAkun] = akun
+	}
+
+	for _, baris := range barisList {
+		akun, ada := akunByKode[baris.KodeAkun]
+		if !ada {
+			akun = models.Akun{
+				IDKoperasi:  idKoperasi,
+				KodeAkun:    baris.KodeAkun,
+				TipeAkun:    baris.TipeAkun,
+				NormalSaldo: baris.NormalSaldo,
+				StatusAktif: true,
+			}
+		}
+		akun.NamaAkun = baris.NamaAkun
+		akun.Deskripsi = baris.Deskripsi
+		akun.KategoriArusKas = baris.KategoriArusKas
+		if baris.StatusAktif != nil {
+			akun.StatusAktif = *baris.StatusAktif
+		}
+		akun.AkunInduk = nil
+
+		if err := tx.Save(&akun).Error; err != nil {
+			return fmt.Errorf("akun %s: %w", baris.KodeAkun, err)
+		}
+		akunByKode[akun.KodeAkun] = akun
+	}
+
+	for _, baris := range barisList {
+		var idInduk *uuid.UUID
+		if baris.KodeInduk != "" {
+			id := akunByKode[baris.KodeInduk].ID
+			idInduk = &id
+		}
+		if err := tx.Model(&models.Akun{}).
+			Where("id = ?", akunByKode[baris.KodeAkun].ID).
+			Update("id_induk", idInduk).Error; err != nil {
+			return fmt.Errorf("akun induk %s: %w", baris.KodeAkun, err)
+		}
+	}
+
+	return nil
+}
+
+// bacaBerkasCOA mem-parse berkas impor menjadi baris COA berdasarkan header kolom
+func bacaBerkasCOA(format FormatBerkas, data []byte) ([]barisImporCOA, error) {
+	tabel, err := bacaTabelImpor(format, data, "kodeAkun", "namaAkun", "tipeAkun")
+	if err != nil {
+		return nil, err
+	}
+
+	var hasil []barisImporCOA
+	for i, baris := range tabel.baris {
+		if barisKosong(baris) {
+			continue
+		}
+
+		item := barisImporCOA{
+			Nomor: i + 2, // baris 1 adalah header
+			BarisCOA: BarisCOA{
+				KodeAkun:        tabel.ambil(baris, "kodeAkun"),
+				NamaAkun:        tabel.ambil(baris, "namaAkun"),
+				TipeAkun:        models.TipeAkun(strings.ToUpper(tabel.ambil(baris, "tipeAkun"))),
+				KodeInduk:       tabel.ambil(baris, "kodeInduk"),
+				NormalSaldo:     strings.ToUpper(tabel.ambil(baris, "normalSaldo")),
+				KategoriArusKas: models.KategoriArusKas(strings.ToUpper(tabel.ambil(baris, "kategoriArusKas"))),
+				Deskripsi:       tabel.ambil(baris, "deskripsi"),
+			},
+		}
+		if status := tabel.ambil(baris, "statusAktif"); status != "" {
+			aktif, err := strconv.ParseBool(status)
+			if err != nil {
+				return nil, fmt.Errorf("status aktif tidak valid pada baris %d", item.Nomor)
+			}
+			item.StatusAktif = &aktif
+		}
+		hasil = append(hasil, item)
+	}
+
+	if len(hasil) == 0 {
+		return nil, errors.New("berkas impor tidak berisi data akun")
+	}
+
+	return hasil, nil
+}
+
+// adaSiklusInduk memeriksa apakah rantai induk suatu akun kembali ke akun itu sendiri
+func adaSiklusInduk(kodeAkun string, indukByKode map[string]string) bool {
+	dikunjungi := map[string]bool{kodeAkun: true}
+	for kode := indukByKode[kodeAkun]; kode != ""; kode = indukByKode[kode] {
+		if dikunjungi[kode] {
+			return true
+		}
+		dikunjungi[kode] = true
+	}
+	return false
+}
+
+// kodeIndukAkun mengembalikan kode akun induk dari akun yang sudah ada
+func kodeIndukAkun(akun models.Akun, kodeByID map[uuid.UUID]string) string {
+	if akun.IDInduk == nil {
+		return ""
+	}
+	return kodeByID[*akun.IDInduk]
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bytes"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/xlsx"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacaBerkasCOA_CSV(t *testing.T) {
+	data := []byte("\xef\xbb\xbfKode Akun;Nama Akun\n")
+	_, err := bacaBerkasCOA(FormatCSV, data)
+	assert.Error(t, err, "header tanpa kolom tipe akun harus ditolak")
+
+	data = []byte("nama_akun,kode_akun,tipe_akun,kode_induk,status_aktif\n" +
+		"Aset,1000,aktiva,,\n" +
+		",,,,\n" +
+		"Kas Kecil,1103,AKTIVA,1000,false\n")
+	barisList, err := bacaBerkasCOA(FormatCSV, data)
+	require.NoError(t, err)
+	require.Len(t, barisList, 2)
+
+	assert.Equal(t, 2, barisList[0].Nomor)
+	assert.Equal(t, "1000", barisList[0].KodeAkun)
+	assert.Equal(t, models.AkunAktiva, barisList[0].TipeAkun)
+	assert.Nil(t, barisList[0].StatusAktif)
+
+	assert.Equal(t, 4, barisList[1].Nomor, "nomor baris mengikuti berkas termasuk baris kosong")
+	assert.Equal(t, "1000", barisList[1].KodeInduk)
+	require.NotNil(t, barisList[1].StatusAktif)
+	assert.False(t, *barisList[1].StatusAktif)
+
+	_, err = bacaBerkasCOA(FormatCSV, []byte("kodeAkun,namaAkun,tipeAkun\n"))
+	assert.Error(t, err, "berkas tanpa data akun harus ditolak")
+}
+
+func TestBacaBerkasCOA_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, xlsx.Tulis(&buf, "COA", [][]string{
+		{"kodeAkun", "namaAkun", "tipeAkun", "kategoriArusKas"},
+		{"1101-01", "Kas Toko", "AKTIVA", "kas"},
+	}))
+
+	barisList, err := bacaBerkasCOA(FormatXLSX, buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, barisList, 1)
+	assert.Equal(t, "1101-01", barisList[0].KodeAkun)
+	assert.Equal(t, models.ArusKasKas, barisList[0].KategoriArusKas)
+
+	_, err = bacaBerkasCOA(FormatXLSX, []byte("kodeAkun,namaAkun,tipeAkun\n"))
+	assert.ErrorIs(t, err, xlsx.ErrBukanXLSX)
+}
+
+func TestValidasiImporCOA(t *testing.T) {
+	service := &AkunService{}
+	idAset := uuid.New()
+	akunList := []models.Akun{
+		{ID: idAset, KodeAkun: "1000", NamaAkun: "ASET", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT", StatusAktif: true},
+		{ID: uuid.New(), KodeAkun: "1101", NamaAkun: "Kas", TipeAkun: models.AkunAktiva, NormalSaldo: "DEBIT", IDInduk: &idAset, StatusAktif: true},
+	}
+
+	baris := func(nomor int, kode, nama string, tipe models.TipeAkun, induk string) barisImporCOA {
+		return barisImporCOA{Nomor: nomor, BarisCOA: BarisCOA{KodeAkun: kode, NamaAkun: nama, TipeAkun: tipe, KodeInduk: induk}}
+	}
+
+	t.Run("rencana baru, perbarui dan tidak berubah", func(t *testing.T) {
+		hasil := &HasilImporCOA{}
+		service.validasiImporCOA([]barisImporCOA{
+			baris(2, "1101", "Kas", models.AkunAktiva, "1000"),
+			baris(3, "1000", "Aset", models.AkunAktiva, ""),
+			baris(4, "1102-01", "Bank BRI", models.AkunAktiva, "1102"),
+			baris(5, "1102", "Bank", models.AkunAktiva, "1000"),
+		}, akunList, hasil)
+
+		assert.Empty(t, hasil.Kesalahan)
+		assert.Equal(t, 2, hasil.JumlahBaru)
+		assert.Equal(t, 1, hasil.JumlahDiperbarui)
+		require.Len(t, hasil.Rincian, 4)
+		assert.Equal(t, AksiImporTidakUbah, hasil.Rincian[0].Aksi)
+		assert.Equal(t, AksiImporPerbarui, hasil.Rincian[1].Aksi)
+		assert.Equal(t, AksiImporBaru, hasil.Rincian[2].Aksi, "induk boleh didefinisikan setelah sub-akun")
+	})
+
+	t.Run("kesalahan validasi", func(t *testing.T) {
+		hasil := &HasilImporCOA{}
+		service.validasiImporCOA([]barisImporCOA{
+			baris(2, "2000", "Kewajiban", models.AkunKewajiban, ""),
+			baris(3, "2000", "Kewajiban Lagi", models.AkunKewajiban, ""),
+			baris(4, "12", "Kode Salah", models.AkunAktiva, ""),
+			baris(5, "2101", "Hutang", models.AkunKewajiban, "9999"),
+			baris(6, "2102", "Hutang Lain", models.AkunKewajiban, "1000"),
+			baris(7, "1101", "Kas", models.AkunModal, ""),
+			baris(8, "5100", "Beban A", models.AkunBeban, "5200"),
+			baris(9, "5200", "Beban B", models.AkunBeban, "5100"),
+		}, akunList, hasil)
+
+		kesalahanPerBaris := map[int]bool{}
+		for _, kesalahan := range hasil.Kesalahan {
+			kesalahanPerBaris[kesalahan.Baris] = true
+		}
+		assert.False(t, kesalahanPerBaris[2])
+		for _, nomor := range []int{3, 4, 5, 6, 7, 8, 9} {
+			assert.True(t, kesalahanPerBaris[nomor], "baris %d seharusnya tidak valid", nomor)
+		}
+	})
+}
+
+func TestImporCOA_EksporLaluImporUlang(t *testing.T) {
+	db := setupAkunTestDB(t)
+	if db == nil {
+		return
+	}
+
+	service := NewAkunService(db)
+	asal := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Koperasi Asal", Email: "asal@test.com", NoTelepon: "081234567890"}
+	tujuan := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Koperasi Tujuan", Email: "tujuan@test.com", NoTelepon: "081234567891"}
+	db.Create(asal)
+	db.Create(tujuan)
+	defer cleanupTestData(db, asal.ID)
+	defer cleanupTestData(db, tujuan.ID)
+
+	require.NoError(t, service.InisialisasiCOA(asal.ID, models.JenisKoperasiSimpanPinjam))
+	akunAsal, err := service.DapatkanSemuaAkun(asal.ID, "", nil)
+	require.NoError(t, err)
+
+	data, err := service.EksporCOA(asal.ID, FormatXLSX)
+	require.NoError(t, err)
+
+	// Dry run tidak menyimpan apa pun
+	hasil, err := service.ImporCOA(tujuan.ID, FormatXLSX, data, true)
+	require.NoError(t, err)
+	assert.Empty(t, hasil.Kesalahan)
+	assert.False(t, hasil.Disimpan)
+	assert.Equal(t, len(akunAsal), hasil.JumlahBaru)
+	akunTujuan, _ := service.DapatkanSemuaAkun(tujuan.ID, "", nil)
+	assert.Empty(t, akunTujuan)
+
+	hasil, err = service.ImporCOA(tujuan.ID, FormatXLSX, data, false)
+	require.NoError(t, err)
+	assert.True(t, hasil.Disimpan)
+
+	kas, err := service.DapatkanAkunByKode(tujuan.ID, "1101")
+	require.NoError(t, err)
+	assert.Equal(t, "Aset Lancar", kas.NamaInduk)
+
+	penyisihan, err := service.DapatkanAkunByKode(tujuan.ID, "1203")
+	require.NoError(t, err)
+	assert.Equal(t, "KREDIT", penyisihan.NormalSaldo)
+
+	// Impor ulang berkas yang sama tidak mengubah apa pun
+	hasil, err = service.ImporCOA(tujuan.ID, FormatXLSX, data, false)
+	require.NoError(t, err)
+	assert.Zero(t, hasil.JumlahBaru)
+	assert.Zero(t, hasil.JumlahDiperbarui)
+
+	// Berkas dengan kesalahan tidak menyimpan baris yang valid
+	hasil, err = service.ImporCOA(tujuan.ID, FormatCSV, []byte("kodeAkun,namaAkun,tipeAkun\n1999,Aset Lain,AKTIVA\n1101,Kas,BEBAN\n"), false)
+	require.NoError(t, err)
+	assert.Len(t, hasil.Kesalahan, 1)
+	assert.False(t, hasil.Disimpan)
+	_, err = service.DapatkanAkunByKode(tujuan.ID, "1999")
+	assert.Error(t, err)
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
 	"errors"
 
 	"github.com/google/uuid"
@@ -18,6 +20,14 @@ func NewKoperasiService(db *gorm.DB) *KoperasiService {
 	return &KoperasiService{db: db}
 }
 
+// DenganKonteks mengembalikan salinan service yang menjalankan query dengan ctx,
+// sehingga log audit mencatat pengguna, alamat IP dan request ID dari request tersebut
+func (s *KoperasiService) DenganKonteks(ctx context.Context) *KoperasiService {
+	salinan := *s
+	salinan.db = s.db.WithContext(ctx)
+	return &salinan
+}
+
 // BuatKoperasiRequest adalah struktur request untuk membuat koperasi
 type BuatKoperasiRequest struct {
 	NamaKoperasi   string `json:"namaKoperasi" binding:"required"`
@@ -69,6 +79,12 @@ type PerbaruiKoperasiRequest struct {
 	Email          string `json:"email"`
 	LogoURL        string `json:"logoUrl"`
 	TahunBukuMulai int    `json:"tahunBukuMulai"`
+
+	// Pengaturan pajak; nil berarti tidak diubah
+	NPWP             *string  `json:"npwp"`
+	PKP              *bool    `json:"pkp"`
+	TarifPPN         *float64 `json:"tarifPPN"`
+	HargaTermasukPPN *bool    `json:"hargaTermasukPPN"`
 }
 
 // PerbaruiKoperasi mengupdate data koperasi
@@ -99,6 +115,35 @@ func (s *KoperasiService) PerbaruiKoperasi(idKoperasi uuid.UUID, req *PerbaruiKo
 		koperasi.TahunBukuMulai = req.TahunBukuMulai
 	}
 
+	// Pengaturan pajak
+	if req.NPWP != nil {
+		npwp := normalisasiNPWP(*req.NPWP)
+		if npwp != "" {
+			if err := validasiNPWP(npwp, "NPWP koperasi"); err != nil {
+				return nil, err
+			}
+		}
+		koperasi.NPWP = npwp
+	}
+	if req.TarifPPN != nil {
+		if *req.TarifPPN <= 0 {
+			return nil, errors.New("tarif PPN harus lebih dari 0")
+		}
+		if err := validasi.Baru().Persentase(*req.TarifPPN, "tarif PPN"); err != nil {
+			return nil, err
+		}
+		koperasi.TarifPPN = *req.TarifPPN
+	}
+	if req.HargaTermasukPPN != nil {
+		koperasi.HargaTermasukPPN = *req.HargaTermasukPPN
+	}
+	if req.PKP != nil {
+		koperasi.PKP = *req.PKP
+	}
+	if koperasi.PKP && koperasi.NPWP == "" {
+		return nil, errors.New("koperasi PKP wajib memiliki NPWP")
+	}
+
 	// Simpan perubahan
 	err = s.db.Save(koperasi).Error
 	if err != nil {
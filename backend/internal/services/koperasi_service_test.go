@@ -371,8 +371,8 @@ func TestDapatkanStatistikKoperasi(t *testing.T) {
 			IDKoperasi:  koperasi.ID,
 			NamaProduk:  "Product",
 			KodeProduk:  "P001",
-			Harga:       10000,
-			HargaBeli:   8000,
+			Harga:       models.Rupiah(10000),
+			HargaBeli:   models.Rupiah(8000),
 			Stok:        100,
 			StatusAktif: true,
 		}
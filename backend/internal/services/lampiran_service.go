@@ -0,0 +1,241 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/penyimpanan"
+	"cooperative-erp-lite/pkg/validasi"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UkuranMaksLampiran adalah batas ukuran satu berkas lampiran (10 MB)
+const UkuranMaksLampiran = 10 << 20
+
+// jumlahMaksLampiranDokumen membatasi banyaknya lampiran aktif pada satu dokumen
+const jumlahMaksLampiranDokumen = 20
+
+// ekstensiTipeLampiran memetakan tipe konten yang diizinkan ke ekstensi berkasnya.
+// Tipe konten ditentukan dari isi berkas, bukan dari header unggahan, dan ekstensi nama
+// berkas harus sesuai agar berkas HTML/skrip tidak dapat menyamar sebagai bukti.
+var ekstensiTipeLampiran = map[string][]string{
+	"application/pdf": {".pdf"},
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/png":       {".png"},
+	"image/webp":      {".webp"},
+}
+
+// LampiranService menangani unggah, unduh dan hapus lampiran bukti dokumen
+type LampiranService struct {
+	db          *gorm.DB
+	penyimpanan penyimpanan.Penyimpanan
+}
+
+// NewLampiranService membuat instance baru LampiranService
+func NewLampiranService(db *gorm.DB, penyimpanan penyimpanan.Penyimpanan) *LampiranService {
+	return &LampiranService{
+		db:          db,
+		penyimpanan: penyimpanan,
+	}
+}
+
+// UnggahLampiranRequest adalah berkas yang akan dilampirkan pada dokumen
+type UnggahLampiranRequest struct {
+	JenisDokumen models.JenisDokumenLampiran
+	IDDokumen    uuid.UUID
+	NamaBerkas   string
+	Keterangan   string
+	Data         []byte
+}
+
+// deteksiTipeLampiran menentukan tipe konten dari isi berkas dan memastikan tipe tersebut
+// diizinkan dan sesuai dengan ekstensi nama berkas
+func deteksiTipeLampiran(namaBerkas string, data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", errors.New("berkas lampiran kosong")
+	}
+	if len(data) > UkuranMaksLampiran {
+		return "", fmt.Errorf("ukuran berkas lampiran maksimal %d MB", UkuranMaksLampiran>>20)
+	}
+
+	tipe := http.DetectContentType(data)
+	if i := strings.Index(tipe, ";"); i >= 0 {
+		tipe = tipe[:i]
+	}
+	ekstensiList, ok := ekstensiTipeLampiran[tipe]
+	if !ok {
+		return "", errors.New("berkas lampiran harus berupa PDF, JPG, PNG atau WEBP")
+	}
+
+	ekstensi := strings.ToLower(filepath.Ext(namaBerkas))
+	for _, e := range ekstensiList {
+		if e == ekstensi {
+			return tipe, nil
+		}
+	}
+	return "", fmt.Errorf("ekstensi berkas %s tidak sesuai dengan isinya (%s)", ekstensi, tipe)
+}
+
+// cekDokumenLampiranWithTx memastikan dokumen yang diberi lampiran ada di koperasi
+func cekDokumenLampiranWithTx(tx *gorm.DB, idKoperasi uuid.UUID, jenis models.JenisDokumenLampiran, idDokumen uuid.UUID) error {
+	var model interface{}
+	switch jenis {
+	case models.DokumenTransaksi:
+		model = &models.Transaksi{}
+	case models.DokumenSimpanan:
+		model = &models.Simpanan{}
+	case models.DokumenPenjualan:
+		model = &models.Penjualan{}
+	case models.DokumenAnggota:
+		model = &models.Anggota{}
+	default:
+		return fmt.Errorf("jenis dokumen %s tidak dikenal", jenis)
+	}
+
+	var count int64
+	if err := tx.Model(model).Where("id = ? AND id_koperasi = ?", idDokumen, idKoperasi).Count(&count).Error; err != nil {
+		return errors.New("gagal memeriksa dokumen")
+	}
+	if count == 0 {
+		return fmt.Errorf("dokumen %s tidak ditemukan", strings.ToLower(string(jenis)))
+	}
+	return nil
+}
+
+// kunciLampiran menyusun kunci penyimpanan per koperasi:
+// <idKoperasi>/<jenis dokumen>/<idDokumen>/<idLampiran><ekstensi>
+func kunciLampiran(lampiran *models.Lampiran, ekstensi string) string {
+	return fmt.Sprintf("%s/%s/%s/%s%s", lampiran.IDKoperasi, strings.ToLower(string(lampiran.JenisDokumen)),
+		lampiran.IDDokumen, lampiran.ID, ekstensi)
+}
+
+// UnggahLampiran menyimpan berkas bukti dan mencatat metadatanya.
+// Berkas ditulis ke penyimpanan lebih dulu; jika pencatatan di database gagal, berkas dihapus lagi.
+func (s *LampiranService) UnggahLampiran(idKoperasi, idPengguna uuid.UUID, req *UnggahLampiranRequest) (*models.Lampiran, error) {
+	if !req.JenisDokumen.IsValid() {
+		return nil, fmt.Errorf("jenis dokumen %s tidak dikenal", req.JenisDokumen)
+	}
+
+	validator := validasi.Baru()
+	namaBerkas := filepath.Base(strings.TrimSpace(req.NamaBerkas))
+	if err := validator.TeksWajib(namaBerkas, "nama berkas", 1, 255); err != nil {
+		return nil, err
+	}
+	if err := validator.TeksOpsional(req.Keterangan, "keterangan", 500); err != nil {
+		return nil, err
+	}
+
+	tipeKonten, err := deteksiTipeLampiran(namaBerkas, req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cekDokumenLampiranWithTx(s.db, idKoperasi, req.JenisDokumen, req.IDDokumen); err != nil {
+		return nil, err
+	}
+
+	var jumlah int64
+	s.db.Model(&models.Lampiran{}).
+		Where("id_koperasi = ? AND jenis_dokumen = ? AND id_dokumen = ?", idKoperasi, req.JenisDokumen, req.IDDokumen).
+		Count(&jumlah)
+	if jumlah >= jumlahMaksLampiranDokumen {
+		return nil, fmt.Errorf("dokumen sudah memiliki %d lampiran (maksimal)", jumlahMaksLampiranDokumen)
+	}
+
+	checksum := sha256.Sum256(req.Data)
+	lampiran := &models.Lampiran{
+		ID:             uuid.New(),
+		IDKoperasi:     idKoperasi,
+		JenisDokumen:   req.JenisDokumen,
+		IDDokumen:      req.IDDokumen,
+		NamaBerkas:     namaBerkas,
+		TipeKonten:     tipeKonten,
+		Ukuran:         int64(len(req.Data)),
+		ChecksumSHA256: hex.EncodeToString(checksum[:]),
+		Keterangan:     req.Keterangan,
+		DiunggahOleh:   idPengguna,
+	}
+	lampiran.KunciPenyimpanan = kunciLampiran(lampiran, strings.ToLower(filepath.Ext(namaBerkas)))
+
+	ctx := context.Background()
+	if err := s.penyimpanan.Simpan(ctx, lampiran.KunciPenyimpanan, bytes.NewReader(req.Data), lampiran.Ukuran, tipeKonten); err != nil {
+		log.Printf("Gagal menyimpan berkas lampiran %s: %v", lampiran.KunciPenyimpanan, err)
+		return nil, errors.New("gagal menyimpan berkas lampiran")
+	}
+
+	if err := s.db.Create(lampiran).Error; err != nil {
+		if hapusErr := s.penyimpanan.Hapus(ctx, lampiran.KunciPenyimpanan); hapusErr != nil {
+			log.Printf("Gagal menghapus berkas lampiran yatim %s: %v", lampiran.KunciPenyimpanan, hapusErr)
+		}
+		return nil, errors.New("gagal menyimpan lampiran")
+	}
+
+	return lampiran, nil
+}
+
+// DapatkanLampiranDokumen mengambil lampiran aktif sebuah dokumen, terlama lebih dulu
+func (s *LampiranService) DapatkanLampiranDokumen(idKoperasi uuid.UUID, jenis models.JenisDokumenLampiran, idDokumen uuid.UUID) ([]models.Lampiran, error) {
+	if !jenis.IsValid() {
+		return nil, fmt.Errorf("jenis dokumen %s tidak dikenal", jenis)
+	}
+
+	var lampiranList []models.Lampiran
+	err := s.db.Where("id_koperasi = ? AND jenis_dokumen = ? AND id_dokumen = ?", idKoperasi, jenis, idDokumen).
+		Order("tanggal_dibuat ASC").
+		Find(&lampiranList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar lampiran")
+	}
+
+	return lampiranList, nil
+}
+
+// BukaLampiran mengambil metadata lampiran dan membuka berkasnya untuk diunduh.
+// Pemanggil wajib menutup reader yang dikembalikan.
+func (s *LampiranService) BukaLampiran(id, idKoperasi uuid.UUID) (*models.Lampiran, io.ReadCloser, error) {
+	var lampiran models.Lampiran
+	if err := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&lampiran).Error; err != nil {
+		return nil, nil, errors.New("lampiran tidak ditemukan")
+	}
+
+	isi, err := s.penyimpanan.Buka(context.Background(), lampiran.KunciPenyimpanan)
+	if err != nil {
+		if errors.Is(err, penyimpanan.ErrTidakDitemukan) {
+			return nil, nil, errors.New("berkas lampiran tidak ditemukan di penyimpanan")
+		}
+		log.Printf("Gagal membuka berkas lampiran %s: %v", lampiran.KunciPenyimpanan, err)
+		return nil, nil, errors.New("gagal membuka berkas lampiran")
+	}
+
+	return &lampiran, isi, nil
+}
+
+// HapusLampiran melepas lampiran dari dokumennya (soft delete). Berkasnya tetap disimpan
+// sebagai jejak audit.
+func (s *LampiranService) HapusLampiran(id, idKoperasi, idPengguna uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var lampiran models.Lampiran
+		if err := tx.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&lampiran).Error; err != nil {
+			return errors.New("lampiran tidak ditemukan")
+		}
+
+		if err := tx.Model(&lampiran).Update("dihapus_oleh", idPengguna).Error; err != nil {
+			return errors.New("gagal menghapus lampiran")
+		}
+		if err := tx.Delete(&lampiran).Error; err != nil {
+			return errors.New("gagal menghapus lampiran")
+		}
+		return nil
+	})
+}
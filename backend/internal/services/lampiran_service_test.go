@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/penyimpanan"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	isiPDFTest = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+	isiPNGTest = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+)
+
+func TestDeteksiTipeLampiran(t *testing.T) {
+	tipe, err := deteksiTipeLampiran("nota.PDF", isiPDFTest)
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", tipe)
+
+	tipe, err = deteksiTipeLampiran("ktp.png", isiPNGTest)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", tipe)
+
+	_, err = deteksiTipeLampiran("nota.pdf", isiPNGTest)
+	assert.Error(t, err, "ekstensi harus sesuai dengan isi berkas")
+
+	_, err = deteksiTipeLampiran("nota.pdf", []byte("<html><script>alert(1)</script></html>"))
+	assert.Error(t, err, "HTML tidak diizinkan walaupun bernama .pdf")
+
+	_, err = deteksiTipeLampiran("kosong.pdf", nil)
+	assert.Error(t, err)
+
+	besar := append(append([]byte{}, isiPDFTest...), make([]byte, UkuranMaksLampiran)...)
+	_, err = deteksiTipeLampiran("besar.pdf", besar)
+	assert.Error(t, err, "berkas melebihi batas ukuran")
+}
+
+func TestLampiranService(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Anggota{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.Lampiran{},
+	)
+	require.NoError(t, err)
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Lampiran Koperasi", Alamat: "Test Address"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	koperasiLain := &models.Koperasi{NamaKoperasi: "Test Lampiran Koperasi Lain", Alamat: "Test Address"}
+	db.Create(koperasiLain)
+	defer cleanupTestData(db, koperasiLain.ID)
+
+	store, err := penyimpanan.BaruLokal(t.TempDir())
+	require.NoError(t, err)
+	lampiranService := NewLampiranService(db, store)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	transaksi := &models.Transaksi{
+		IDKoperasi:       koperasi.ID,
+		NomorJurnal:      "JU-LAMPIRAN-001",
+		TanggalTransaksi: hariIniUTC(),
+		Deskripsi:        "Pembelian alat tulis",
+		DibuatOleh:       admin,
+	}
+	require.NoError(t, db.Create(transaksi).Error)
+
+	lampiran, err := lampiranService.UnggahLampiran(koperasi.ID, admin, &UnggahLampiranRequest{
+		JenisDokumen: models.DokumenTransaksi,
+		IDDokumen:    transaksi.ID,
+		NamaBerkas:   "../../nota.pdf",
+		Keterangan:   "Nota toko",
+		Data:         isiPDFTest,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "nota.pdf", lampiran.NamaBerkas, "path dari klien dibuang")
+	assert.Equal(t, "application/pdf", lampiran.TipeKonten)
+	assert.True(t, strings.HasPrefix(lampiran.KunciPenyimpanan, koperasi.ID.String()+"/transaksi/"+transaksi.ID.String()+"/"))
+
+	t.Run("dokumen harus milik koperasi", func(t *testing.T) {
+		_, err := lampiranService.UnggahLampiran(koperasiLain.ID, admin, &UnggahLampiranRequest{
+			JenisDokumen: models.DokumenTransaksi, IDDokumen: transaksi.ID, NamaBerkas: "nota.pdf", Data: isiPDFTest,
+		})
+		assert.Error(t, err)
+
+		_, err = lampiranService.UnggahLampiran(koperasi.ID, admin, &UnggahLampiranRequest{
+			JenisDokumen: models.DokumenAnggota, IDDokumen: uuid.New(), NamaBerkas: "ktp.png", Data: isiPNGTest,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("unduh hanya dari koperasi sendiri", func(t *testing.T) {
+		_, _, err := lampiranService.BukaLampiran(lampiran.ID, koperasiLain.ID)
+		assert.Error(t, err)
+
+		meta, isi, err := lampiranService.BukaLampiran(lampiran.ID, koperasi.ID)
+		require.NoError(t, err)
+		defer isi.Close()
+		data, _ := io.ReadAll(isi)
+		assert.True(t, bytes.Equal(isiPDFTest, data))
+		assert.Equal(t, int64(len(isiPDFTest)), meta.Ukuran)
+	})
+
+	t.Run("hapus lampiran menyimpan berkas untuk audit", func(t *testing.T) {
+		require.NoError(t, lampiranService.HapusLampiran(lampiran.ID, koperasi.ID, admin))
+
+		lampiranList, err := lampiranService.DapatkanLampiranDokumen(koperasi.ID, models.DokumenTransaksi, transaksi.ID)
+		require.NoError(t, err)
+		assert.Empty(t, lampiranList)
+
+		var terhapus models.Lampiran
+		require.NoError(t, db.Unscoped().First(&terhapus, "id = ?", lampiran.ID).Error)
+		require.NotNil(t, terhapus.DihapusOleh)
+		assert.Equal(t, admin, *terhapus.DihapusOleh)
+
+		isi, err := store.Buka(context.Background(), terhapus.KunciPenyimpanan)
+		require.NoError(t, err)
+		isi.Close()
+	})
+}
@@ -0,0 +1,650 @@
+package services
+
+import (
+	"bytes"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/xlsx"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// selLaporan adalah satu sel tabel ekspor laporan: teks, nominal uang, atau bilangan lain
+type selLaporan struct {
+	teks   string
+	uang   *models.Uang
+	angka  *float64
+	persen bool // angka adalah persentase
+}
+
+func selTeks(teks string) selLaporan {
+	return selLaporan{teks: teks}
+}
+
+func selUang(nilai models.Uang) selLaporan {
+	return selLaporan{uang: &nilai}
+}
+
+func selAngka(nilai float64) selLaporan {
+	return selLaporan{angka: &nilai}
+}
+
+// jenisKolom membedakan kolom data dari kolom yang dihitung dari dua kolom lain
+type jenisKolom int
+
+const (
+	kolomData    jenisKolom = iota
+	kolomSelisih            // Nilai kolom ke dikurangi nilai kolom dari
+	kolomPersen             // Selisih dalam persen dari nilai kolom dari
+)
+
+// kolomTabelLaporan mendefinisikan satu kolom tabel ekspor laporan
+type kolomTabelLaporan struct {
+	judul    string
+	angka    bool    // Rata kanan
+	dijumlah bool    // Dijumlahkan pada baris total bagian dan baris ringkasan
+	lebar    float64 // Bobot lebar kolom pada PDF
+	jenis    jenisKolom
+	dari, ke int // Kolom sumber untuk kolomSelisih dan kolomPersen
+}
+
+// bagianTabelLaporan adalah sekelompok baris (misalnya Aset) dengan baris total opsional
+type bagianTabelLaporan struct {
+	judul string // Kosong berarti tanpa baris judul bagian
+	baris [][]selLaporan
+	total string // Label baris total; kosong berarti tanpa baris total
+}
+
+// komponenRingkasan menambahkan (tanda 1) atau mengurangkan (tanda -1) total satu bagian
+type komponenRingkasan struct {
+	bagian int
+	tanda  int
+}
+
+// ringkasanTabelLaporan adalah baris akhir tabel. Kolom yang dijumlah dihitung dari total bagian
+// sesuai komponen; jika komponen kosong, nilai diambil dari sel.
+type ringkasanTabelLaporan struct {
+	label    string
+	komponen []komponenRingkasan
+	sel      []selLaporan
+}
+
+// tabelLaporan adalah bentuk laporan yang netral terhadap format berkas. Satu tabel disusun
+// sekali lalu ditulis sebagai CSV, XLSX atau PDF.
+type tabelLaporan struct {
+	judul      string
+	keterangan []string // Tanggal atau periode laporan, akun buku besar
+	unitUsaha  string   // Nama unit usaha jika laporan dikelompokkan per unit usaha
+	kolom      []kolomTabelLaporan
+	kolomLabel int // Kolom tempat label baris total dan ringkasan ditulis
+	bagian     []bagianTabelLaporan
+	ringkasan  []ringkasanTabelLaporan
+}
+
+// Jenis baris tabel yang sudah disusun, menentukan gaya penulisannya
+const (
+	barisHeader = iota
+	barisJudulBagian
+	barisData
+	barisTotal
+)
+
+// barisTersusun adalah baris tabel dengan semua nilai sudah dihitung. Rumus merujuk sel
+// lembar kerja XLSX dan diabaikan oleh format lainnya.
+type barisTersusun struct {
+	jenis int
+	sel   []selLaporan
+	rumus []string
+}
+
+// susun menghitung total bagian, ringkasan dan kolom selisih. barisAwal adalah indeks baris
+// (0-based) header tabel pada lembar kerja, dipakai untuk rumus XLSX.
+func (t *tabelLaporan) susun(barisAwal int) []barisTersusun {
+	var hasil []barisTersusun
+	barisBaru := func(jenis int) *barisTersusun {
+		hasil = append(hasil, barisTersusun{
+			jenis: jenis,
+			sel:   make([]selLaporan, len(t.kolom)),
+			rumus: make([]string, len(t.kolom)),
+		})
+		return &hasil[len(hasil)-1]
+	}
+	nomorBaris := func() int {
+		return barisAwal + len(hasil) - 1
+	}
+
+	header := barisBaru(barisHeader)
+	for j, kolom := range t.kolom {
+		header.sel[j] = selTeks(kolom.judul)
+	}
+
+	barisTotalBagian := make([]int, len(t.bagian))
+	totalBagian := make([][]models.Uang, len(t.bagian))
+	for i, bagian := range t.bagian {
+		if bagian.judul != "" {
+			barisBaru(barisJudulBagian).sel[0] = selTeks(bagian.judul)
+		}
+
+		total := make([]models.Uang, len(t.kolom))
+		awal := barisAwal + len(hasil)
+		for _, sel := range bagian.baris {
+			baris := barisBaru(barisData)
+			copy(baris.sel, sel)
+			for j, kolom := range t.kolom {
+				if kolom.dijumlah && baris.sel[j].uang != nil {
+					total[j] += *baris.sel[j].uang
+				}
+			}
+			t.hitungKolomTurunan(baris, nomorBaris())
+		}
+		akhir := barisAwal + len(hasil) - 1
+		totalBagian[i] = total
+
+		barisTotalBagian[i] = -1
+		if bagian.total != "" {
+			baris := barisBaru(barisTotal)
+			baris.sel[t.kolomLabel] = selTeks(bagian.total)
+			for j, kolom := range t.kolom {
+				if !kolom.dijumlah {
+					continue
+				}
+				baris.sel[j] = selUang(total[j])
+				if akhir >= awal {
+					baris.rumus[j] = "SUM(" + xlsx.Referensi(awal, j) + ":" + xlsx.Referensi(akhir, j) + ")"
+				}
+			}
+			barisTotalBagian[i] = nomorBaris()
+			t.hitungKolomTurunan(baris, barisTotalBagian[i])
+		}
+	}
+
+	for _, ringkasan := range t.ringkasan {
+		baris := barisBaru(barisTotal)
+		copy(baris.sel, ringkasan.sel)
+		baris.sel[t.kolomLabel] = selTeks(ringkasan.label)
+		if len(ringkasan.komponen) > 0 {
+			for j, kolom := range t.kolom {
+				if !kolom.dijumlah {
+					continue
+				}
+				var nilai models.Uang
+				var rumus strings.Builder
+				for _, komponen := range ringkasan.komponen {
+					nilai += models.Uang(komponen.tanda) * totalBagian[komponen.bagian][j]
+					if komponen.tanda < 0 {
+						rumus.WriteString("-")
+					} else if rumus.Len() > 0 {
+						rumus.WriteString("+")
+					}
+					rumus.WriteString(xlsx.Referensi(barisTotalBagian[komponen.bagian], j))
+				}
+				baris.sel[j] = selUang(nilai)
+				baris.rumus[j] = rumus.String()
+			}
+		}
+		t.hitungKolomTurunan(baris, nomorBaris())
+	}
+
+	return hasil
+}
+
+// hitungKolomTurunan mengisi kolom selisih dan persen dari kolom sumbernya pada baris yang sama
+func (t *tabelLaporan) hitungKolomTurunan(baris *barisTersusun, nomorBaris int) {
+	for j, kolom := range t.kolom {
+		if kolom.jenis == kolomData {
+			continue
+		}
+		dari, ke := baris.sel[kolom.dari].uang, baris.sel[kolom.ke].uang
+		if dari == nil || ke == nil {
+			continue
+		}
+
+		refDari, refKe := xlsx.Referensi(nomorBaris, kolom.dari), xlsx.Referensi(nomorBaris, kolom.ke)
+		switch kolom.jenis {
+		case kolomSelisih:
+			baris.sel[j] = selUang(*ke - *dari)
+			baris.rumus[j] = refKe + "-" + refDari
+		case kolomPersen:
+			if *dari != 0 {
+				persen := bulatkanPersen(float64(*ke-*dari) / math.Abs(float64(*dari)) * 100)
+				baris.sel[j] = selLaporan{angka: &persen, persen: true}
+			}
+			baris.rumus[j] = fmt.Sprintf(`IF(%s=0,"",ROUND((%s-%s)/ABS(%s)*100,2))`, refDari, refKe, refDari, refDari)
+		}
+	}
+}
+
+// EksporLaporan menulis laporan hasil Generate* sebagai berkas CSV, XLSX atau PDF.
+//
+// Laporan yang didukung: neraca, laba rugi (termasuk versi komparatif), neraca saldo, buku besar,
+// transaksi harian, saldo simpanan anggota, serta laporan-laporan tersebut yang dikelompokkan per
+// unit usaha. XLSX menyimpan total bagian sebagai rumus SUM dan PDF dicetak dengan kop koperasi.
+func (s *LaporanService) EksporLaporan(idKoperasi uuid.UUID, judul string, laporan interface{}, format FormatBerkas) ([]byte, error) {
+	if !format.IsValidLaporan() {
+		return nil, fmt.Errorf("format %s tidak didukung", format)
+	}
+
+	tabelList, err := tabelDariLaporan(laporan)
+	if err != nil {
+		return nil, err
+	}
+	for _, tabel := range tabelList {
+		tabel.judul = judul
+	}
+
+	if format == FormatCSV {
+		return tulisCSVLaporan(tabelList)
+	}
+
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", idKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+	if format == FormatXLSX {
+		return tulisXLSXLaporan(&koperasi, tabelList)
+	}
+	return tulisPDFLaporan(&koperasi, ambilLogoKoperasi(koperasi.LogoURL), tabelList)
+}
+
+// tabelDariLaporan menyusun tabel ekspor dari hasil Generate*
+func tabelDariLaporan(laporan interface{}) ([]*tabelLaporan, error) {
+	switch l := laporan.(type) {
+	case *LaporanPosisiKeuangan:
+		return []*tabelLaporan{tabelPosisiKeuangan(l)}, nil
+	case *LaporanLabaRugi:
+		return []*tabelLaporan{tabelLabaRugi(l)}, nil
+	case *LaporanKomparatif:
+		return []*tabelLaporan{tabelKomparatif(l)}, nil
+	case *LaporanTransaksiHarian:
+		return []*tabelLaporan{tabelTransaksiHarian(l)}, nil
+	case []models.SaldoSimpananAnggota:
+		return []*tabelLaporan{tabelSaldoAnggota(l)}, nil
+	case map[string]interface{}:
+		if items, ok := l["items"].([]ItemNeracaSaldo); ok {
+			return []*tabelLaporan{tabelNeracaSaldo(l, items)}, nil
+		}
+		if transaksi, ok := l["transaksi"].([]BarisBukuBesar); ok {
+			return []*tabelLaporan{tabelBukuBesar(l, transaksi)}, nil
+		}
+	case []LaporanPerUnitUsaha:
+		var hasil []*tabelLaporan
+		for _, unit := range l {
+			tabelUnit, err := tabelDariLaporan(unit.Laporan)
+			if err != nil {
+				return nil, err
+			}
+			namaUnit := unit.NamaUnit
+			if unit.KodeUnit != "" {
+				namaUnit = unit.KodeUnit + " - " + unit.NamaUnit
+			}
+			for _, tabel := range tabelUnit {
+				tabel.unitUsaha = namaUnit
+			}
+			hasil = append(hasil, tabelUnit...)
+		}
+		return hasil, nil
+	}
+	return nil, errors.New("laporan ini tidak dapat diekspor")
+}
+
+// kolomAkun adalah kolom kode dan nama akun yang mengawali laporan keuangan
+var kolomAkun = []kolomTabelLaporan{
+	{judul: "Kode Akun", lebar: 1},
+	{judul: "Nama Akun", lebar: 3.5},
+}
+
+// kolomUang membuat kolom nominal yang dijumlahkan pada baris total
+func kolomUang(judul string) kolomTabelLaporan {
+	return kolomTabelLaporan{judul: judul, angka: true, dijumlah: true, lebar: 1.6}
+}
+
+func barisItemLaporan(itemList []ItemLaporanKeuangan) [][]selLaporan {
+	baris := make([][]selLaporan, len(itemList))
+	for i, item := range itemList {
+		baris[i] = []selLaporan{selTeks(item.KodeAkun), selTeks(item.NamaAkun), selUang(item.Saldo)}
+	}
+	return baris
+}
+
+func tabelPosisiKeuangan(l *LaporanPosisiKeuangan) *tabelLaporan {
+	return &tabelLaporan{
+		keterangan: []string{"Per " + formatTanggalIndonesia(l.TanggalLaporan)},
+		kolom:      append(append([]kolomTabelLaporan{}, kolomAkun...), kolomUang("Saldo")),
+		kolomLabel: 1,
+		bagian: []bagianTabelLaporan{
+			{judul: "ASET", baris: barisItemLaporan(l.Aset), total: "Total Aset"},
+			{judul: "KEWAJIBAN", baris: barisItemLaporan(l.Kewajiban), total: "Total Kewajiban"},
+			{judul: "MODAL", baris: barisItemLaporan(l.Modal), total: "Total Modal"},
+		},
+		ringkasan: []ringkasanTabelLaporan{
+			{label: ringkasanTotalKewajibanModal, komponen: []komponenRingkasan{{bagian: 1, tanda: 1}, {bagian: 2, tanda: 1}}},
+		},
+	}
+}
+
+func tabelLabaRugi(l *LaporanLabaRugi) *tabelLaporan {
+	return &tabelLaporan{
+		keterangan: []string{"Periode " + formatTanggalIndonesia(l.PeriodeMulai) + " s.d. " + formatTanggalIndonesia(l.PeriodeAkhir)},
+		kolom:      append(append([]kolomTabelLaporan{}, kolomAkun...), kolomUang("Jumlah")),
+		kolomLabel: 1,
+		bagian: []bagianTabelLaporan{
+			{judul: "PENDAPATAN", baris: barisItemLaporan(l.Pendapatan), total: "Total Pendapatan"},
+			{judul: "BEBAN", baris: barisItemLaporan(l.Beban), total: "Total Beban"},
+		},
+		ringkasan: []ringkasanTabelLaporan{
+			{label: ringkasanLabaRugiBersih, komponen: []komponenRingkasan{{bagian: 0, tanda: 1}, {bagian: 1, tanda: -1}}},
+		},
+	}
+}
+
+// tabelKomparatif menyajikan satu kolom nilai untuk setiap periode, diikuti selisih dan persentase
+// periode terakhir terhadap periode sebelumnya
+func tabelKomparatif(l *LaporanKomparatif) *tabelLaporan {
+	tabel := &tabelLaporan{
+		kolom:      append([]kolomTabelLaporan{}, kolomAkun...),
+		kolomLabel: 1,
+	}
+	if len(l.Kolom) > 0 {
+		pertama, terakhir := l.Kolom[0], l.Kolom[len(l.Kolom)-1]
+		periode := func(k KolomLaporan) string {
+			if k.TanggalMulai == nil {
+				return "Per " + formatTanggalIndonesia(k.TanggalAkhir)
+			}
+			return "Periode " + formatTanggalIndonesia(*k.TanggalMulai) + " s.d. " + formatTanggalIndonesia(k.TanggalAkhir)
+		}
+		switch {
+		case l.Pembanding == PembandingTahunLalu:
+			tabel.keterangan = []string{periode(terakhir), "Pembanding: " + periode(pertama)}
+		case pertama.TanggalMulai == nil:
+			tabel.keterangan = []string{"Posisi akhir bulan " + formatTanggalIndonesia(pertama.TanggalAkhir) + " s.d. " + formatTanggalIndonesia(terakhir.TanggalAkhir)}
+		default:
+			tabel.keterangan = []string{"Periode " + formatTanggalIndonesia(*pertama.TanggalMulai) + " s.d. " + formatTanggalIndonesia(terakhir.TanggalAkhir) + " per bulan"}
+		}
+	}
+
+	awal := len(tabel.kolom)
+	for _, kolom := range l.Kolom {
+		tabel.kolom = append(tabel.kolom, kolomUang(kolom.Label))
+	}
+	if len(l.Kolom) >= 2 {
+		terakhir := awal + len(l.Kolom) - 1
+		tabel.kolom = append(tabel.kolom,
+			kolomTabelLaporan{judul: "Selisih", angka: true, lebar: 1.6, jenis: kolomSelisih, dari: terakhir - 1, ke: terakhir},
+			kolomTabelLaporan{judul: "%", angka: true, lebar: 0.8, jenis: kolomPersen, dari: terakhir - 1, ke: terakhir},
+		)
+	}
+
+	barisKomparatif := func(baris BarisLaporanKomparatif) []selLaporan {
+		sel := []selLaporan{selTeks(baris.KodeAkun), selTeks(baris.NamaAkun)}
+		for _, nilai := range baris.Nilai {
+			sel = append(sel, selUang(nilai.Nilai))
+		}
+		return sel
+	}
+
+	indeksBagian := map[string]int{}
+	for i, bagian := range l.Bagian {
+		indeksBagian[bagian.Nama] = i
+		baris := make([][]selLaporan, len(bagian.Baris))
+		for j, b := range bagian.Baris {
+			baris[j] = barisKomparatif(b)
+		}
+		tabel.bagian = append(tabel.bagian, bagianTabelLaporan{
+			judul: strings.ToUpper(bagian.Nama),
+			baris: baris,
+			total: "Total " + bagian.Nama,
+		})
+	}
+
+	// Ringkasan yang merupakan penjumlahan total bagian ditulis sebagai rumus
+	komponenDikenal := map[string][]komponenRingkasan{
+		ringkasanTotalKewajibanModal: {{bagian: indeksBagian["Kewajiban"], tanda: 1}, {bagian: indeksBagian["Modal"], tanda: 1}},
+		ringkasanLabaRugiBersih:      {{bagian: indeksBagian["Pendapatan"], tanda: 1}, {bagian: indeksBagian["Beban"], tanda: -1}},
+	}
+	for _, baris := range l.Ringkasan {
+		tabel.ringkasan = append(tabel.ringkasan, ringkasanTabelLaporan{
+			label:    baris.NamaAkun,
+			komponen: komponenDikenal[baris.NamaAkun],
+			sel:      barisKomparatif(baris),
+		})
+	}
+	return tabel
+}
+
+func tabelNeracaSaldo(l map[string]interface{}, items []ItemNeracaSaldo) *tabelLaporan {
+	baris := make([][]selLaporan, len(items))
+	for i, item := range items {
+		baris[i] = []selLaporan{selTeks(item.KodeAkun), selTeks(item.NamaAkun), selUang(item.SaldoDebit), selUang(item.SaldoKredit)}
+	}
+
+	tanggalPer, _ := l["tanggalPer"].(string)
+	return &tabelLaporan{
+		keterangan: []string{"Per " + formatTanggalString(tanggalPer)},
+		kolom:      append(append([]kolomTabelLaporan{}, kolomAkun...), kolomUang("Debit"), kolomUang("Kredit")),
+		kolomLabel: 1,
+		bagian:     []bagianTabelLaporan{{baris: baris, total: "Total"}},
+	}
+}
+
+func tabelBukuBesar(l map[string]interface{}, transaksi []BarisBukuBesar) *tabelLaporan {
+	tabel := &tabelLaporan{
+		kolom: []kolomTabelLaporan{
+			{judul: "Tanggal", lebar: 1.2},
+			{judul: "No. Jurnal", lebar: 1.4},
+			{judul: "Keterangan", lebar: 3},
+			kolomUang("Debit"),
+			kolomUang("Kredit"),
+			{judul: "Saldo", angka: true, lebar: 1.6},
+		},
+		kolomLabel: 2,
+	}
+
+	if akun, ok := l["akun"].(map[string]interface{}); ok {
+		tabel.keterangan = append(tabel.keterangan, fmt.Sprintf("Akun: %v - %v", akun["kode"], akun["nama"]))
+	}
+	periode, _ := l["periode"].(map[string]string)
+	if periode["tanggalMulai"] != "" || periode["tanggalAkhir"] != "" {
+		tabel.keterangan = append(tabel.keterangan, "Periode "+formatTanggalString(periode["tanggalMulai"])+" s.d. "+formatTanggalString(periode["tanggalAkhir"]))
+	}
+
+	saldoAwal, _ := l["saldoAwal"].(models.Uang)
+	saldoAkhir, _ := l["saldoAkhir"].(models.Uang)
+	baris := [][]selLaporan{{selTeks(periode["tanggalMulai"]), {}, selTeks("Saldo Awal"), {}, {}, selUang(saldoAwal)}}
+	for _, t := range transaksi {
+		baris = append(baris, []selLaporan{
+			selTeks(t.Tanggal), selTeks(t.NoJurnal), selTeks(t.Keterangan), selUang(t.Debit), selUang(t.Kredit), selUang(t.Saldo),
+		})
+	}
+	tabel.bagian = []bagianTabelLaporan{{baris: baris, total: "Total Mutasi"}}
+	tabel.ringkasan = []ringkasanTabelLaporan{
+		{label: "Saldo Akhir", sel: []selLaporan{{}, {}, {}, {}, {}, selUang(saldoAkhir)}},
+	}
+	return tabel
+}
+
+func tabelTransaksiHarian(l *LaporanTransaksiHarian) *tabelLaporan {
+	return &tabelLaporan{
+		keterangan: []string{"Tanggal " + formatTanggalIndonesia(l.Tanggal)},
+		kolom: []kolomTabelLaporan{
+			{judul: "Keterangan", lebar: 3},
+			{judul: "Jumlah", angka: true, lebar: 1.6},
+		},
+		bagian: []bagianTabelLaporan{{baris: [][]selLaporan{
+			{selTeks("Total Kas Masuk"), selUang(l.TotalKasMasuk)},
+			{selTeks("Total Kas Keluar"), selUang(l.TotalKasKeluar)},
+			{selTeks("Saldo Kas Akhir"), selUang(l.SaldoKasAkhir)},
+			{selTeks("Jumlah Transaksi Penjualan"), selAngka(float64(l.JumlahPenjualan))},
+			{selTeks("Jumlah Transaksi Simpanan"), selAngka(float64(l.JumlahSimpanan))},
+		}}},
+	}
+}
+
+func tabelSaldoAnggota(l []models.SaldoSimpananAnggota) *tabelLaporan {
+	baris := make([][]selLaporan, len(l))
+	for i, saldo := range l {
+		baris[i] = []selLaporan{
+			selTeks(saldo.NomorAnggota), selTeks(saldo.NamaAnggota),
+			selUang(saldo.SimpananPokok), selUang(saldo.SimpananWajib), selUang(saldo.SimpananSukarela), selUang(saldo.TotalSimpanan),
+		}
+	}
+
+	return &tabelLaporan{
+		keterangan: []string{"Per " + formatTanggalIndonesia(time.Now())},
+		kolom: []kolomTabelLaporan{
+			{judul: "No. Anggota", lebar: 1.2},
+			{judul: "Nama Anggota", lebar: 2.5},
+			kolomUang("Simpanan Pokok"),
+			kolomUang("Simpanan Wajib"),
+			kolomUang("Simpanan Sukarela"),
+			kolomUang("Total Simpanan"),
+		},
+		kolomLabel: 1,
+		bagian:     []bagianTabelLaporan{{baris: baris, total: "Total"}},
+	}
+}
+
+// teksCSV mengubah sel menjadi teks mesin: nominal dengan titik desimal tanpa pemisah ribuan
+func (sel selLaporan) teksCSV() string {
+	switch {
+	case sel.uang != nil:
+		return sel.uang.String()
+	case sel.angka != nil:
+		return strconv.FormatFloat(*sel.angka, 'f', -1, 64)
+	default:
+		return sel.teks
+	}
+}
+
+// tulisCSVLaporan menulis tabel sebagai CSV. Tabel per unit usaha diawali baris nama unit
+// dan dipisahkan baris kosong.
+func tulisCSVLaporan(tabelList []*tabelLaporan) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for i, tabel := range tabelList {
+		if i > 0 {
+			w.Write([]string{""})
+		}
+		if tabel.unitUsaha != "" {
+			w.Write([]string{"Unit Usaha: " + tabel.unitUsaha})
+		}
+		for _, baris := range tabel.susun(0) {
+			sel := make([]string, len(baris.sel))
+			for j := range baris.sel {
+				sel[j] = baris.sel[j].teksCSV()
+			}
+			w.Write(sel)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, errors.New("gagal membuat berkas CSV laporan")
+	}
+	return buf.Bytes(), nil
+}
+
+// kopSurat mengembalikan baris kop koperasi: nama, alamat, lalu telepon dan email jika ada
+func kopSurat(koperasi *models.Koperasi) []string {
+	kop := []string{koperasi.NamaKoperasi}
+	if koperasi.Alamat != "" {
+		kop = append(kop, koperasi.Alamat)
+	}
+	var kontak []string
+	if koperasi.NoTelepon != "" {
+		kontak = append(kontak, "Telp. "+koperasi.NoTelepon)
+	}
+	if koperasi.Email != "" {
+		kontak = append(kontak, "Email: "+koperasi.Email)
+	}
+	if len(kontak) > 0 {
+		kop = append(kop, strings.Join(kontak, " | "))
+	}
+	return kop
+}
+
+// tulisXLSXLaporan menulis kop dan tabel ke satu lembar kerja; total bagian, ringkasan dan
+// kolom selisih ditulis sebagai rumus
+func tulisXLSXLaporan(koperasi *models.Koperasi, tabelList []*tabelLaporan) ([]byte, error) {
+	var baris [][]xlsx.Sel
+	for i, kop := range kopSurat(koperasi) {
+		baris = append(baris, []xlsx.Sel{{Nilai: kop, Tebal: i == 0}})
+	}
+
+	for _, tabel := range tabelList {
+		baris = append(baris, nil, []xlsx.Sel{{Nilai: tabel.judul, Tebal: true}})
+		for _, keterangan := range tabel.keterangan {
+			baris = append(baris, []xlsx.Sel{{Nilai: keterangan}})
+		}
+		if tabel.unitUsaha != "" {
+			baris = append(baris, []xlsx.Sel{{Nilai: "Unit Usaha: " + tabel.unitUsaha}})
+		}
+		baris = append(baris, nil)
+
+		for _, tersusun := range tabel.susun(len(baris)) {
+			tebal := tersusun.jenis != barisData
+			sel := make([]xlsx.Sel, len(tersusun.sel))
+			for j, s := range tersusun.sel {
+				sel[j] = xlsx.Sel{Nilai: s.teks, Rumus: tersusun.rumus[j], Tebal: tebal}
+				if s.uang != nil || s.angka != nil || (tersusun.rumus[j] != "" && tersusun.jenis != barisHeader) {
+					sel[j].Nilai = s.teksCSV()
+					sel[j].Angka = true
+				}
+			}
+			baris = append(baris, sel)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := xlsx.TulisSel(&buf, namaSheetLaporan(tabelList), baris); err != nil {
+		return nil, errors.New("gagal membuat berkas XLSX laporan")
+	}
+	return buf.Bytes(), nil
+}
+
+// namaSheetLaporan membuat nama lembar kerja dari judul laporan: maksimal 31 karakter tanpa
+// karakter yang dilarang Excel
+func namaSheetLaporan(tabelList []*tabelLaporan) string {
+	if len(tabelList) == 0 {
+		return "Laporan"
+	}
+	nama := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, tabelList[0].judul)
+	if runes := []rune(nama); len(runes) > 31 {
+		nama = string(runes[:31])
+	}
+	if strings.TrimSpace(nama) == "" {
+		return "Laporan"
+	}
+	return nama
+}
+
+var namaBulanIndonesia = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// formatTanggalIndonesia memformat tanggal seperti "31 Desember 2025"
+func formatTanggalIndonesia(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), namaBulanIndonesia[t.Month()-1], t.Year())
+}
+
+// formatTanggalString memformat tanggal YYYY-MM-DD; tanggal kosong berarti hari ini
+func formatTanggalString(tanggal string) string {
+	if tanggal == "" {
+		return formatTanggalIndonesia(time.Now())
+	}
+	t, err := time.Parse("2006-01-02", tanggal)
+	if err != nil {
+		return tanggal
+	}
+	return formatTanggalIndonesia(t)
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"bytes"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/pdf"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	marginPDF           = 40.0
+	ukuranFontMaksPDF   = 9.0
+	ukuranFontMinPDF    = 5.0
+	jarakKolomPDF       = 6.0 // Jarak antar kolom tabel
+	tinggiLogoPDF       = 48.0
+	maksKolomPortrait   = 6 // Tabel dengan kolom lebih banyak dicetak landscape
+	tinggiFooterPDF     = 24.0
+	ukuranFontFooterPDF = 7.0
+)
+
+// tulisPDFLaporan mencetak setiap tabel mulai dari halaman baru dengan kop koperasi,
+// lalu menambahkan waktu cetak dan nomor halaman di kaki setiap halaman
+func tulisPDFLaporan(koperasi *models.Koperasi, logo []byte, tabelList []*tabelLaporan) ([]byte, error) {
+	lebar, tinggi := pdf.LebarA4, pdf.TinggiA4
+	for _, tabel := range tabelList {
+		if len(tabel.kolom) > maksKolomPortrait {
+			lebar, tinggi = tinggi, lebar
+			break
+		}
+	}
+
+	dok := pdf.Baru(lebar, tinggi)
+	for _, tabel := range tabelList {
+		tulisTabelPDF(dok, koperasi, logo, tabel)
+	}
+
+	sekarang := time.Now()
+	dicetak := "Dicetak " + formatTanggalIndonesia(sekarang) + " " + sekarang.Format("15:04")
+	jumlahHalaman := dok.JumlahHalaman()
+	for i := 1; i <= jumlahHalaman; i++ {
+		dok.KeHalaman(i)
+		y := tinggi - tinggiFooterPDF
+		dok.Teks(marginPDF, y, ukuranFontFooterPDF, false, dicetak)
+		halaman := fmt.Sprintf("Halaman %d dari %d", i, jumlahHalaman)
+		dok.Teks(lebar-marginPDF-pdf.LebarTeks(halaman, ukuranFontFooterPDF, false), y, ukuranFontFooterPDF, false, halaman)
+	}
+
+	var buf bytes.Buffer
+	if err := dok.Tulis(&buf); err != nil {
+		return nil, errors.New("gagal membuat berkas PDF laporan")
+	}
+	return buf.Bytes(), nil
+}
+
+// tulisKopPDF mencetak kop surat (logo, nama, alamat, kontak) diakhiri garis ganda dan
+// mengembalikan posisi y di bawahnya
+func tulisKopPDF(dok *pdf.Dokumen, koperasi *models.Koperasi, logo []byte, y float64) float64 {
+	xTeks := marginPDF
+	tinggiKop := 0.0
+	if logo != nil {
+		lebarLogo, err := dok.Gambar(logo, marginPDF, y, tinggiLogoPDF*3, tinggiLogoPDF)
+		if err == nil {
+			xTeks += lebarLogo + 10
+			tinggiKop = tinggiLogoPDF
+		}
+	}
+
+	yTeks := y
+	for i, baris := range kopSurat(koperasi) {
+		ukuran := 9.0
+		if i == 0 {
+			ukuran = 14
+		}
+		yTeks += ukuran + 3
+		dok.Teks(xTeks, yTeks, ukuran, i == 0, potongTeksPDF(baris, ukuran, i == 0, dok.Lebar()-marginPDF-xTeks))
+	}
+	tinggiKop = math.Max(tinggiKop, yTeks-y+4)
+
+	yGaris := y + tinggiKop + 4
+	dok.Garis(marginPDF, yGaris, dok.Lebar()-marginPDF, yGaris, 1.5)
+	dok.Garis(marginPDF, yGaris+2.5, dok.Lebar()-marginPDF, yGaris+2.5, 0.5)
+	return yGaris + 2.5
+}
+
+// tulisTabelPDF mencetak judul dan tabel; header tabel diulang di setiap halaman lanjutan
+func tulisTabelPDF(dok *pdf.Dokumen, koperasi *models.Koperasi, logo []byte, tabel *tabelLaporan) {
+	dok.TambahHalaman()
+	y := tulisKopPDF(dok, koperasi, logo, marginPDF)
+
+	tengah := func(teks string, ukuran float64, tebal bool) {
+		y += ukuran + 5
+		dok.Teks((dok.Lebar()-pdf.LebarTeks(teks, ukuran, tebal))/2, y, ukuran, tebal, teks)
+	}
+	y += 8
+	tengah(tabel.judul, 12, true)
+	for _, keterangan := range tabel.keterangan {
+		tengah(keterangan, 9, false)
+	}
+	if tabel.unitUsaha != "" {
+		tengah("Unit Usaha: "+tabel.unitUsaha, 9, false)
+	}
+	y += 12
+
+	// Lebar kolom sebanding bobotnya
+	lebarTabel := dok.Lebar() - 2*marginPDF
+	totalBobot := 0.0
+	for _, kolom := range tabel.kolom {
+		totalBobot += kolom.lebar
+	}
+	xKolom := make([]float64, len(tabel.kolom))
+	lebarKolom := make([]float64, len(tabel.kolom))
+	x := marginPDF
+	for j, kolom := range tabel.kolom {
+		xKolom[j] = x
+		lebarKolom[j] = lebarTabel*kolom.lebar/totalBobot - jarakKolomPDF
+		x += lebarKolom[j] + jarakKolomPDF
+	}
+
+	baris := tabel.susun(0)
+	ukuran := ukuranFontPDF(tabel, baris, lebarKolom)
+	tinggiBaris := ukuran * 1.7
+	batasBawah := dok.Tinggi() - marginPDF - tinggiFooterPDF
+
+	tulisBaris := func(b barisTersusun) {
+		tebal := b.jenis != barisData
+		garisDasar := y + ukuran*1.2
+		if b.jenis == barisJudulBagian {
+			dok.Teks(xKolom[0], garisDasar, ukuran, true, potongTeksPDF(b.sel[0].teks, ukuran, true, lebarTabel))
+			y += tinggiBaris
+			return
+		}
+		for j, sel := range b.sel {
+			teks := sel.teksPDF()
+			if teks == "" {
+				continue
+			}
+			teks = potongTeksPDF(teks, ukuran, tebal, lebarKolom[j])
+			xTeks := xKolom[j]
+			if tabel.kolom[j].angka {
+				xTeks += lebarKolom[j] - pdf.LebarTeks(teks, ukuran, tebal)
+			}
+			dok.Teks(xTeks, garisDasar, ukuran, tebal, teks)
+		}
+		y += tinggiBaris
+	}
+	tulisHeader := func() {
+		dok.Garis(marginPDF, y, marginPDF+lebarTabel, y, 0.5)
+		tulisBaris(baris[0])
+		dok.Garis(marginPDF, y, marginPDF+lebarTabel, y, 0.5)
+	}
+
+	tulisHeader()
+	for _, b := range baris[1:] {
+		if y+tinggiBaris > batasBawah {
+			dok.TambahHalaman()
+			y = marginPDF
+			tulisHeader()
+		}
+		if b.jenis == barisTotal {
+			dok.Garis(marginPDF, y, marginPDF+lebarTabel, y, 0.3)
+		}
+		tulisBaris(b)
+	}
+	dok.Garis(marginPDF, y, marginPDF+lebarTabel, y, 0.5)
+}
+
+// ukuranFontPDF memilih ukuran font terbesar yang membuat semua angka muat di kolomnya.
+// Teks yang tetap tidak muat dipotong oleh potongTeksPDF.
+func ukuranFontPDF(tabel *tabelLaporan, baris []barisTersusun, lebarKolom []float64) float64 {
+	ukuran := ukuranFontMaksPDF
+	for _, b := range baris {
+		for j, sel := range b.sel {
+			if !tabel.kolom[j].angka || b.jenis == barisHeader {
+				continue
+			}
+			lebarPerPoint := pdf.LebarTeks(sel.teksPDF(), 1, b.jenis != barisData)
+			if lebarPerPoint > 0 && lebarKolom[j]/lebarPerPoint < ukuran {
+				ukuran = lebarKolom[j] / lebarPerPoint
+			}
+		}
+	}
+	return math.Max(math.Floor(ukuran*10)/10, ukuranFontMinPDF)
+}
+
+// potongTeksPDF memotong teks dengan "..." agar muat di lebar yang tersedia
+func potongTeksPDF(teks string, ukuran float64, tebal bool, lebar float64) string {
+	if pdf.LebarTeks(teks, ukuran, tebal) <= lebar {
+		return teks
+	}
+	runes := []rune(teks)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if potongan := string(runes) + "..."; pdf.LebarTeks(potongan, ukuran, tebal) <= lebar {
+			return potongan
+		}
+	}
+	return ""
+}
+
+// teksPDF mengubah sel menjadi teks cetak dengan format Indonesia: 1.234.567,89,
+// nominal negatif dalam kurung
+func (sel selLaporan) teksPDF() string {
+	switch {
+	case sel.uang != nil:
+		sen := int64(*sel.uang)
+		negatif := sen < 0
+		if negatif {
+			sen = -sen
+		}
+		teks := formatRibuan(sen/models.SenPerRupiah) + fmt.Sprintf(",%02d", sen%models.SenPerRupiah)
+		if negatif {
+			return "(" + teks + ")"
+		}
+		return teks
+	case sel.angka != nil && sel.persen:
+		return strings.Replace(strconv.FormatFloat(*sel.angka, 'f', 2, 64), ".", ",", 1) + "%"
+	case sel.angka != nil:
+		bulat := int64(math.Round(*sel.angka))
+		if bulat < 0 {
+			return "-" + formatRibuan(-bulat)
+		}
+		return formatRibuan(bulat)
+	default:
+		return sel.teks
+	}
+}
+
+// formatRibuan memformat bilangan bulat tidak negatif dengan titik pemisah ribuan
+func formatRibuan(nilai int64) string {
+	teks := strconv.FormatInt(nilai, 10)
+	var sb strings.Builder
+	for i, ch := range teks {
+		if i > 0 && (len(teks)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(ch)
+	}
+	return sb.String()
+}
+
+// maksUkuranLogo membatasi ukuran gambar logo yang diunduh untuk kop PDF
+const maksUkuranLogo = 2 << 20
+
+// klienLogo mengunduh logo koperasi. Alamat jaringan internal ditolak saat koneksi dibuka
+// (termasuk setelah redirect) agar LogoURL tidak dapat dipakai untuk mengakses layanan internal.
+var klienLogo = &http.Client{
+	Timeout: 5 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{Timeout: 3 * time.Second, Control: tolakAlamatInternal}).DialContext,
+	},
+}
+
+func tolakAlamatInternal(_, alamat string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(alamat)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errors.New("alamat logo tidak diizinkan")
+	}
+	return nil
+}
+
+// ambilLogoKoperasi mengunduh logo dari LogoURL http(s). Logo yang gagal diunduh tidak
+// menggagalkan ekspor; kop dicetak tanpa logo.
+func ambilLogoKoperasi(logoURL string) []byte {
+	u, err := url.Parse(logoURL)
+	if logoURL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+
+	resp, err := klienLogo.Get(logoURL)
+	if err != nil {
+		log.Printf("Gagal mengunduh logo koperasi %s: %v", logoURL, err)
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Gagal mengunduh logo koperasi %s: status %d", logoURL, resp.StatusCode)
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maksUkuranLogo+1))
+	if err != nil || len(data) > maksUkuranLogo {
+		log.Printf("Logo koperasi %s tidak dapat dibaca atau melebihi %d byte", logoURL, maksUkuranLogo)
+		return nil
+	}
+	return data
+}
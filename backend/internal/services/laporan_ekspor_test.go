@@ -0,0 +1,141 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contohLabaRugi() *LaporanLabaRugi {
+	return &LaporanLabaRugi{
+		PeriodeMulai: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodeAkhir: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		Pendapatan: []ItemLaporanKeuangan{
+			{KodeAkun: "4101", NamaAkun: "Penjualan", Saldo: models.Rupiah(1000)},
+			{KodeAkun: "4102", NamaAkun: "Jasa", Saldo: models.Rupiah(500)},
+		},
+		Beban: []ItemLaporanKeuangan{
+			{KodeAkun: "5101", NamaAkun: "Gaji", Saldo: models.Rupiah(1700)},
+		},
+	}
+}
+
+func TestTabelLaporan_Susun(t *testing.T) {
+	baris := tabelLabaRugi(contohLabaRugi()).susun(5)
+	// Header, judul bagian, 2 data, total, judul bagian, 1 data, total, ringkasan
+	require.Len(t, baris, 9)
+
+	assert.Equal(t, barisHeader, baris[0].jenis)
+	assert.Equal(t, "PENDAPATAN", baris[1].sel[0].teks)
+
+	totalPendapatan := baris[4]
+	assert.Equal(t, "Total Pendapatan", totalPendapatan.sel[1].teks)
+	assert.Equal(t, models.Rupiah(1500), *totalPendapatan.sel[2].uang)
+	assert.Equal(t, "SUM(C8:C9)", totalPendapatan.rumus[2], "baris data ke-2 dan ke-3 setelah header di baris 6")
+
+	labaRugi := baris[8]
+	assert.Equal(t, ringkasanLabaRugiBersih, labaRugi.sel[1].teks)
+	assert.Equal(t, models.Rupiah(-200), *labaRugi.sel[2].uang)
+	assert.Equal(t, "C10-C13", labaRugi.rumus[2])
+}
+
+func TestTabelKomparatif_KolomTurunan(t *testing.T) {
+	nilai := func(uang ...models.Uang) []NilaiKolom {
+		return nilaiKomparatif(uang)
+	}
+	tabel := tabelKomparatif(&LaporanKomparatif{
+		Pembanding: PembandingTahunLalu,
+		Kolom: []KolomLaporan{
+			{Label: "2024-12-31", TanggalAkhir: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+			{Label: "2025-12-31", TanggalAkhir: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		Bagian: []BagianLaporanKomparatif{{
+			Nama: "Aset",
+			Baris: []BarisLaporanKomparatif{
+				{KodeAkun: "1101", NamaAkun: "Kas", Nilai: nilai(models.Rupiah(400), models.Rupiah(500))},
+				{KodeAkun: "1102", NamaAkun: "Bank", Nilai: nilai(0, models.Rupiah(100))},
+			},
+		}},
+	})
+
+	require.Len(t, tabel.kolom, 6)
+	assert.Equal(t, []string{"Per 31 Desember 2025", "Pembanding: Per 31 Desember 2024"}, tabel.keterangan)
+
+	baris := tabel.susun(0)
+	kas, bank, total := baris[2], baris[3], baris[4]
+
+	assert.Equal(t, models.Rupiah(100), *kas.sel[4].uang)
+	assert.Equal(t, "D3-C3", kas.rumus[4])
+	assert.Equal(t, 25.0, *kas.sel[5].angka)
+	assert.Equal(t, `IF(C3=0,"",ROUND((D3-C3)/ABS(C3)*100,2))`, kas.rumus[5])
+
+	assert.Nil(t, bank.sel[5].angka, "persentase kosong jika periode pembanding nol")
+
+	assert.Equal(t, "SUM(C3:C4)", total.rumus[2])
+	assert.Equal(t, models.Rupiah(200), *total.sel[4].uang)
+	assert.Equal(t, "D5-C5", total.rumus[4])
+}
+
+func TestTulisCSVLaporan_PerUnitUsaha(t *testing.T) {
+	tabelList, err := tabelDariLaporan([]LaporanPerUnitUsaha{
+		{KodeUnit: "TK", NamaUnit: "Toko", Laporan: contohLabaRugi()},
+		{NamaUnit: "Tanpa Unit Usaha", Laporan: &LaporanLabaRugi{}},
+	})
+	require.NoError(t, err)
+	require.Len(t, tabelList, 2)
+
+	data, err := tulisCSVLaporan(tabelList)
+	require.NoError(t, err)
+
+	baris := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, "Unit Usaha: TK - Toko", baris[0])
+	assert.Equal(t, "Kode Akun,Nama Akun,Jumlah", baris[1])
+	assert.Contains(t, baris, "4101,Penjualan,1000.00")
+	assert.Contains(t, baris, ",Laba (Rugi) Bersih,-200.00")
+	assert.Contains(t, baris, "Unit Usaha: Tanpa Unit Usaha")
+}
+
+func TestTabelDariLaporan_TidakDidukung(t *testing.T) {
+	_, err := tabelDariLaporan(map[string]interface{}{"arusKasOperasional": models.Uang(0)})
+	assert.Error(t, err)
+}
+
+func TestTeksPDF(t *testing.T) {
+	assert.Equal(t, "1.234.567,89", selUang(models.Uang(123456789)).teksPDF())
+	assert.Equal(t, "(1.500,05)", selUang(models.Uang(-150005)).teksPDF())
+	assert.Equal(t, "0,00", selUang(0).teksPDF())
+	assert.Equal(t, "12.000", selAngka(12000).teksPDF())
+
+	persen := -12.5
+	assert.Equal(t, "-12,50%", selLaporan{angka: &persen, persen: true}.teksPDF())
+}
+
+func TestTulisPDFLaporan(t *testing.T) {
+	koperasi := &models.Koperasi{NamaKoperasi: "Koperasi Maju Bersama", Alamat: "Jl. Merdeka No. 1"}
+
+	// Tabel panjang harus berlanjut ke halaman berikutnya
+	laporan := contohLabaRugi()
+	for i := 0; i < 80; i++ {
+		laporan.Beban = append(laporan.Beban, ItemLaporanKeuangan{KodeAkun: "5199", NamaAkun: "Beban Lain", Saldo: models.Rupiah(1)})
+	}
+	tabel := tabelLabaRugi(laporan)
+	tabel.judul = "Laporan Laba Rugi"
+
+	data, err := tulisPDFLaporan(koperasi, nil, []*tabelLaporan{tabel})
+	require.NoError(t, err)
+
+	isi := string(data)
+	assert.True(t, strings.HasPrefix(isi, "%PDF-"))
+	assert.Contains(t, isi, "(Koperasi Maju Bersama) Tj")
+	assert.Contains(t, isi, "(Halaman 2 dari 2) Tj")
+}
+
+func TestNamaSheetLaporan(t *testing.T) {
+	assert.Equal(t, "Laporan Posisi Keuangan (Neraca", namaSheetLaporan([]*tabelLaporan{{judul: "Laporan Posisi Keuangan (Neraca)"}}))
+	assert.Equal(t, "Laba Rugi 2025draft", namaSheetLaporan([]*tabelLaporan{{judul: "Laba Rugi 2025/[draft]"}}))
+	assert.Equal(t, "Laporan", namaSheetLaporan(nil))
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JenisPembanding menentukan kolom pembanding laporan komparatif
+type JenisPembanding string
+
+const (
+	PembandingTahunLalu JenisPembanding = "tahunLalu" // Tanggal atau periode yang sama tahun sebelumnya
+	PembandingBulanan   JenisPembanding = "bulanan"   // Setiap bulan (month-over-month)
+)
+
+// IsValid memeriksa apakah jenis pembanding dikenal
+func (p JenisPembanding) IsValid() bool {
+	return p == PembandingTahunLalu || p == PembandingBulanan
+}
+
+// jumlahBulanKomparatifDefault adalah banyaknya kolom bulanan laporan per tanggal jika tidak ditentukan
+const jumlahBulanKomparatifDefault = 12
+
+// maksKolomKomparatif membatasi banyaknya kolom satu laporan komparatif
+const maksKolomKomparatif = 24
+
+// Nama baris ringkasan laporan komparatif
+const (
+	ringkasanTotalKewajibanModal = "Total Kewajiban dan Modal"
+	ringkasanLabaRugiBersih      = "Laba (Rugi) Bersih"
+	ringkasanTotalDebit          = "Total Debit"
+	ringkasanTotalKredit         = "Total Kredit"
+)
+
+// KolomLaporan adalah satu periode (kolom) laporan komparatif
+type KolomLaporan struct {
+	Label        string     `json:"label"`
+	TanggalMulai *time.Time `json:"tanggalMulai,omitempty"` // Kosong untuk laporan posisi per tanggal
+	TanggalAkhir time.Time  `json:"tanggalAkhir"`
+}
+
+// NilaiKolom adalah nilai satu baris pada satu kolom beserta selisihnya terhadap kolom sebelumnya
+type NilaiKolom struct {
+	Nilai      models.Uang  `json:"nilai"`
+	Selisih    *models.Uang `json:"selisih,omitempty"`    // Kosong pada kolom pertama
+	Persentase *float64     `json:"persentase,omitempty"` // Selisih dalam persen dari nilai kolom sebelumnya, kosong jika nilai tersebut nol
+}
+
+// BarisLaporanKomparatif adalah satu akun (atau baris ringkasan) dengan nilai untuk setiap kolom
+type BarisLaporanKomparatif struct {
+	KodeAkun string       `json:"kodeAkun"`
+	NamaAkun string       `json:"namaAkun"`
+	Nilai    []NilaiKolom `json:"nilai"`
+}
+
+// BagianLaporanKomparatif adalah satu kelompok akun (misalnya Aset) beserta totalnya per kolom
+type BagianLaporanKomparatif struct {
+	Nama  string                   `json:"nama"`
+	Baris []BarisLaporanKomparatif `json:"baris"`
+	Total []NilaiKolom             `json:"total"`
+}
+
+// LaporanKomparatif menyajikan satu laporan untuk beberapa periode berdampingan. Kolom urut dari
+// periode terlama; kolom terakhir adalah periode yang diminta. Selisih setiap kolom dihitung
+// terhadap kolom sebelumnya, sehingga pembanding tahunLalu menyajikan kenaikan/penurunan tahun
+// berjalan terhadap tahun lalu dan pembanding bulanan menyajikan perubahan antar bulan.
+type LaporanKomparatif struct {
+	Pembanding JenisPembanding           `json:"pembanding"`
+	Kolom      []KolomLaporan            `json:"kolom"`
+	Bagian     []BagianLaporanKomparatif `json:"bagian"`
+	Ringkasan  []BarisLaporanKomparatif  `json:"ringkasan"`
+}
+
+// tanggalTahunLalu mengembalikan tanggal yang sama setahun sebelumnya. Akhir bulan tetap menjadi
+// akhir bulan (28 Februari 2025 menjadi 29 Februari 2024, 29 Februari 2024 menjadi 28 Februari 2023).
+func tanggalTahunLalu(t time.Time) time.Time {
+	akhirBulanLalu := time.Date(t.Year()-1, t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	hari := t.Day()
+	if hari > akhirBulanLalu || hari == time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		hari = akhirBulanLalu
+	}
+	return time.Date(t.Year()-1, t.Month(), hari, 0, 0, 0, 0, time.UTC)
+}
+
+// kolomPerTanggal menyusun kolom laporan posisi per tanggal (neraca, neraca saldo).
+// Pembanding bulanan memakai akhir bulan dari jumlahBulan-1 bulan sebelumnya ditambah tanggalPer.
+func kolomPerTanggal(tanggalPer string, pembanding JenisPembanding, jumlahBulan int) ([]KolomLaporan, error) {
+	acuan := time.Now().UTC()
+	if tanggalPer != "" {
+		var err error
+		acuan, err = time.Parse("2006-01-02", tanggalPer)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid")
+		}
+	}
+	acuan = time.Date(acuan.Year(), acuan.Month(), acuan.Day(), 0, 0, 0, 0, time.UTC)
+
+	var tanggalList []time.Time
+	switch pembanding {
+	case PembandingTahunLalu:
+		tanggalList = []time.Time{tanggalTahunLalu(acuan), acuan}
+	case PembandingBulanan:
+		if jumlahBulan == 0 {
+			jumlahBulan = jumlahBulanKomparatifDefault
+		}
+		if jumlahBulan < 2 || jumlahBulan > maksKolomKomparatif {
+			return nil, fmt.Errorf("jumlah bulan pembanding harus antara 2 dan %d", maksKolomKomparatif)
+		}
+		for i := jumlahBulan - 1; i >= 1; i-- {
+			tanggalList = append(tanggalList, time.Date(acuan.Year(), acuan.Month()-time.Month(i)+1, 0, 0, 0, 0, 0, time.UTC))
+		}
+		tanggalList = append(tanggalList, acuan)
+	default:
+		return nil, fmt.Errorf("pembanding %s tidak dikenal", pembanding)
+	}
+
+	kolom := make([]KolomLaporan, len(tanggalList))
+	for i, tanggal := range tanggalList {
+		kolom[i] = KolomLaporan{Label: tanggal.Format("2006-01-02"), TanggalAkhir: tanggal}
+	}
+	return kolom, nil
+}
+
+// kolomPeriode menyusun kolom laporan periode (laba rugi). Pembanding bulanan membagi periode
+// menjadi bulan kalender; bulan pertama dan terakhir dipotong sesuai tanggal periode.
+func kolomPeriode(tanggalMulai, tanggalAkhir string, pembanding JenisPembanding) ([]KolomLaporan, error) {
+	mulai, err := time.Parse("2006-01-02", tanggalMulai)
+	if err != nil {
+		return nil, errors.New("format tanggal mulai tidak valid")
+	}
+	akhir, err := time.Parse("2006-01-02", tanggalAkhir)
+	if err != nil {
+		return nil, errors.New("format tanggal akhir tidak valid")
+	}
+	if akhir.Before(mulai) {
+		return nil, errors.New("tanggal akhir tidak boleh sebelum tanggal mulai")
+	}
+
+	var kolom []KolomLaporan
+	switch pembanding {
+	case PembandingTahunLalu:
+		mulaiLalu, akhirLalu := tanggalTahunLalu(mulai), tanggalTahunLalu(akhir)
+		kolom = []KolomLaporan{
+			{Label: mulaiLalu.Format("2006-01-02") + " s.d. " + akhirLalu.Format("2006-01-02"), TanggalMulai: &mulaiLalu, TanggalAkhir: akhirLalu},
+			{Label: tanggalMulai + " s.d. " + tanggalAkhir, TanggalMulai: &mulai, TanggalAkhir: akhir},
+		}
+	case PembandingBulanan:
+		for awalBulan := time.Date(mulai.Year(), mulai.Month(), 1, 0, 0, 0, 0, time.UTC); !awalBulan.After(akhir); awalBulan = awalBulan.AddDate(0, 1, 0) {
+			if len(kolom) == maksKolomKomparatif {
+				return nil, fmt.Errorf("periode pembanding bulanan maksimal %d bulan", maksKolomKomparatif)
+			}
+			kolomMulai, kolomAkhir := awalBulan, awalBulan.AddDate(0, 1, -1)
+			if kolomMulai.Before(mulai) {
+				kolomMulai = mulai
+			}
+			if kolomAkhir.After(akhir) {
+				kolomAkhir = akhir
+			}
+			kolom = append(kolom, KolomLaporan{Label: awalBulan.Format("2006-01"), TanggalMulai: &kolomMulai, TanggalAkhir: kolomAkhir})
+		}
+	default:
+		return nil, fmt.Errorf("pembanding %s tidak dikenal", pembanding)
+	}
+	return kolom, nil
+}
+
+// nilaiKomparatif menghitung selisih setiap kolom terhadap kolom sebelumnya
+func nilaiKomparatif(nilai []models.Uang) []NilaiKolom {
+	hasil := make([]NilaiKolom, len(nilai))
+	for i, n := range nilai {
+		hasil[i].Nilai = n
+		if i == 0 {
+			continue
+		}
+		selisih := n - nilai[i-1]
+		hasil[i].Selisih = &selisih
+		if nilai[i-1] != 0 {
+			persen := bulatkanPersen(float64(selisih) / math.Abs(float64(nilai[i-1])) * 100)
+			hasil[i].Persentase = &persen
+		}
+	}
+	return hasil
+}
+
+// penyusunKomparatif menggabungkan laporan per kolom menjadi LaporanKomparatif. Akun yang hanya
+// muncul pada sebagian kolom bernilai nol pada kolom lainnya.
+type penyusunKomparatif struct {
+	bagian    []*bagianKomparatif
+	ringkasan *bagianKomparatif
+}
+
+type bagianKomparatif struct {
+	nama  string
+	baris map[string]*barisKomparatif
+	total []models.Uang
+}
+
+type barisKomparatif struct {
+	kodeAkun string
+	namaAkun string
+	nilai    []models.Uang
+}
+
+func baruPenyusunKomparatif(jumlahKolom int, namaBagian ...string) *penyusunKomparatif {
+	p := &penyusunKomparatif{ringkasan: baruBagianKomparatif("", jumlahKolom)}
+	for _, nama := range namaBagian {
+		p.bagian = append(p.bagian, baruBagianKomparatif(nama, jumlahKolom))
+	}
+	return p
+}
+
+func baruBagianKomparatif(nama string, jumlahKolom int) *bagianKomparatif {
+	return &bagianKomparatif{nama: nama, baris: map[string]*barisKomparatif{}, total: make([]models.Uang, jumlahKolom)}
+}
+
+// tambah mencatat nilai satu akun pada kolom tertentu. Baris tanpa kode akun (misalnya SHU periode
+// berjalan) dikenali dari namanya.
+func (b *bagianKomparatif) tambah(kolom int, kodeAkun, namaAkun string, nilai models.Uang) {
+	kunci := kodeAkun + "|" + namaAkun
+	baris, ok := b.baris[kunci]
+	if !ok {
+		baris = &barisKomparatif{kodeAkun: kodeAkun, namaAkun: namaAkun, nilai: make([]models.Uang, len(b.total))}
+		b.baris[kunci] = baris
+	}
+	baris.nilai[kolom] += nilai
+	b.total[kolom] += nilai
+}
+
+func (p *penyusunKomparatif) tambahItem(bagian, kolom int, itemList []ItemLaporanKeuangan) {
+	for _, item := range itemList {
+		p.bagian[bagian].tambah(kolom, item.KodeAkun, item.NamaAkun, item.Saldo)
+	}
+}
+
+func (p *penyusunKomparatif) tambahRingkasan(kolom int, nama string, nilai models.Uang) {
+	p.ringkasan.tambah(kolom, "", nama, nilai)
+}
+
+// barisUrut mengurutkan baris menurut kode akun; baris tanpa kode ditaruh paling akhir
+func (b *bagianKomparatif) barisUrut() []BarisLaporanKomparatif {
+	barisList := make([]*barisKomparatif, 0, len(b.baris))
+	for _, baris := range b.baris {
+		barisList = append(barisList, baris)
+	}
+	sort.Slice(barisList, func(i, j int) bool {
+		a, c := barisList[i], barisList[j]
+		if (a.kodeAkun == "") != (c.kodeAkun == "") {
+			return c.kodeAkun == ""
+		}
+		if a.kodeAkun != c.kodeAkun {
+			return a.kodeAkun < c.kodeAkun
+		}
+		return a.namaAkun < c.namaAkun
+	})
+
+	hasil := make([]BarisLaporanKomparatif, len(barisList))
+	for i, baris := range barisList {
+		hasil[i] = BarisLaporanKomparatif{KodeAkun: baris.kodeAkun, NamaAkun: baris.namaAkun, Nilai: nilaiKomparatif(baris.nilai)}
+	}
+	return hasil
+}
+
+func (p *penyusunKomparatif) hasil(pembanding JenisPembanding, kolom []KolomLaporan, urutanRingkasan ...string) *LaporanKomparatif {
+	laporan := &LaporanKomparatif{
+		Pembanding: pembanding,
+		Kolom:      kolom,
+		Bagian:     make([]BagianLaporanKomparatif, len(p.bagian)),
+		Ringkasan:  make([]BarisLaporanKomparatif, 0, len(urutanRingkasan)),
+	}
+	for i, bagian := range p.bagian {
+		laporan.Bagian[i] = BagianLaporanKomparatif{Nama: bagian.nama, Baris: bagian.barisUrut(), Total: nilaiKomparatif(bagian.total)}
+	}
+	for _, nama := range urutanRingkasan {
+		if baris, ok := p.ringkasan.baris["|"+nama]; ok {
+			laporan.Ringkasan = append(laporan.Ringkasan, BarisLaporanKomparatif{NamaAkun: nama, Nilai: nilaiKomparatif(baris.nilai)})
+		}
+	}
+	return laporan
+}
+
+// GenerateLaporanPosisiKeuanganKomparatif membuat neraca untuk tanggalPer dan tanggal pembandingnya.
+// Setiap kolom dihitung dengan GenerateLaporanPosisiKeuangan.
+func (s *LaporanService) GenerateLaporanPosisiKeuanganKomparatif(idKoperasi uuid.UUID, tanggalPer string, pembanding JenisPembanding, jumlahBulan int) (*LaporanKomparatif, error) {
+	kolom, err := kolomPerTanggal(tanggalPer, pembanding, jumlahBulan)
+	if err != nil {
+		return nil, err
+	}
+
+	penyusun := baruPenyusunKomparatif(len(kolom), "Aset", "Kewajiban", "Modal")
+	for i, k := range kolom {
+		laporan, err := s.GenerateLaporanPosisiKeuangan(idKoperasi, k.TanggalAkhir.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		penyusun.tambahItem(0, i, laporan.Aset)
+		penyusun.tambahItem(1, i, laporan.Kewajiban)
+		penyusun.tambahItem(2, i, laporan.Modal)
+		penyusun.tambahRingkasan(i, ringkasanTotalKewajibanModal, laporan.TotalKewajiban+laporan.TotalModal)
+	}
+
+	return penyusun.hasil(pembanding, kolom, ringkasanTotalKewajibanModal), nil
+}
+
+// GenerateLaporanLabaRugiKomparatif membuat laporan laba rugi untuk periode dan periode pembandingnya.
+// Setiap kolom dihitung dengan GenerateLaporanLabaRugi.
+func (s *LaporanService) GenerateLaporanLabaRugiKomparatif(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir string, pembanding JenisPembanding) (*LaporanKomparatif, error) {
+	kolom, err := kolomPeriode(tanggalMulai, tanggalAkhir, pembanding)
+	if err != nil {
+		return nil, err
+	}
+
+	penyusun := baruPenyusunKomparatif(len(kolom), "Pendapatan", "Beban")
+	for i, k := range kolom {
+		laporan, err := s.GenerateLaporanLabaRugi(idKoperasi, k.TanggalMulai.Format("2006-01-02"), k.TanggalAkhir.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		penyusun.tambahItem(0, i, laporan.Pendapatan)
+		penyusun.tambahItem(1, i, laporan.Beban)
+		penyusun.tambahRingkasan(i, ringkasanLabaRugiBersih, laporan.LabaRugiBersih)
+	}
+
+	return penyusun.hasil(pembanding, kolom, ringkasanLabaRugiBersih), nil
+}
+
+// GenerateNeracaSaldoKomparatif membuat neraca saldo untuk tanggalPer dan tanggal pembandingnya.
+// Baris berisi saldo setiap akun menurut saldo normalnya, dikelompokkan per tipe akun; ringkasan
+// berisi total debit dan total kredit setiap kolom seperti GenerateNeracaSaldo.
+func (s *LaporanService) GenerateNeracaSaldoKomparatif(idKoperasi uuid.UUID, tanggalPer string, pembanding JenisPembanding, jumlahBulan int) (*LaporanKomparatif, error) {
+	kolom, err := kolomPerTanggal(tanggalPer, pembanding, jumlahBulan)
+	if err != nil {
+		return nil, err
+	}
+
+	akunList, err := s.akunService.DapatkanSemuaAkun(idKoperasi, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	indeksBagian := map[models.TipeAkun]int{
+		models.AkunAktiva:     0,
+		models.AkunKewajiban:  1,
+		models.AkunModal:      2,
+		models.AkunPendapatan: 3,
+		models.AkunBeban:      4,
+	}
+	penyusun := baruPenyusunKomparatif(len(kolom), "Aset", "Kewajiban", "Modal", "Pendapatan", "Beban")
+	for i, k := range kolom {
+		mutasi, err := hitungTotalMutasiAkun(s.db, idKoperasi, nil, k.TanggalAkhir.Format("2006-01-02"), s.idUnitUsaha)
+		if err != nil {
+			return nil, err
+		}
+
+		var debit, kredit models.Uang
+		for _, akun := range akunList {
+			total := mutasi[akun.ID]
+			saldoDebit := total.TotalDebit - total.TotalKredit
+			saldo := saldoDebit
+			if akun.NormalSaldo != "DEBIT" {
+				saldo = -saldoDebit
+			}
+
+			if bagian, ok := indeksBagian[akun.TipeAkun]; ok {
+				penyusun.bagian[bagian].tambah(i, akun.KodeAkun, akun.NamaAkun, saldo)
+			}
+			if saldoDebit > 0 {
+				debit += saldoDebit
+			} else {
+				kredit += -saldoDebit
+			}
+		}
+		penyusun.tambahRingkasan(i, ringkasanTotalDebit, debit)
+		penyusun.tambahRingkasan(i, ringkasanTotalKredit, kredit)
+	}
+
+	return penyusun.hasil(pembanding, kolom, ringkasanTotalDebit, ringkasanTotalKredit), nil
+}
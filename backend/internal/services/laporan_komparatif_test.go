@@ -0,0 +1,140 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTanggalTahunLalu(t *testing.T) {
+	tanggal := func(s string) time.Time {
+		hasil, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return hasil
+	}
+
+	assert.Equal(t, tanggal("2024-03-15"), tanggalTahunLalu(tanggal("2025-03-15")))
+	assert.Equal(t, tanggal("2024-12-31"), tanggalTahunLalu(tanggal("2025-12-31")))
+	assert.Equal(t, tanggal("2024-02-29"), tanggalTahunLalu(tanggal("2025-02-28")), "akhir bulan tetap akhir bulan")
+	assert.Equal(t, tanggal("2023-02-28"), tanggalTahunLalu(tanggal("2024-02-29")))
+}
+
+func TestKolomPerTanggal(t *testing.T) {
+	t.Run("tahun lalu", func(t *testing.T) {
+		kolom, err := kolomPerTanggal("2025-12-31", PembandingTahunLalu, 0)
+		require.NoError(t, err)
+		require.Len(t, kolom, 2)
+		assert.Equal(t, "2024-12-31", kolom[0].Label)
+		assert.Equal(t, "2025-12-31", kolom[1].Label)
+		assert.Nil(t, kolom[0].TanggalMulai)
+	})
+
+	t.Run("bulanan memakai akhir bulan sebelumnya", func(t *testing.T) {
+		kolom, err := kolomPerTanggal("2025-03-15", PembandingBulanan, 3)
+		require.NoError(t, err)
+		require.Len(t, kolom, 3)
+		assert.Equal(t, "2025-01-31", kolom[0].Label)
+		assert.Equal(t, "2025-02-28", kolom[1].Label)
+		assert.Equal(t, "2025-03-15", kolom[2].Label)
+	})
+
+	t.Run("bulanan default 12 kolom", func(t *testing.T) {
+		kolom, err := kolomPerTanggal("2025-12-31", PembandingBulanan, 0)
+		require.NoError(t, err)
+		require.Len(t, kolom, 12)
+		assert.Equal(t, "2025-01-31", kolom[0].Label)
+	})
+
+	t.Run("jumlah bulan di luar batas ditolak", func(t *testing.T) {
+		_, err := kolomPerTanggal("2025-12-31", PembandingBulanan, 1)
+		assert.Error(t, err)
+		_, err = kolomPerTanggal("2025-12-31", PembandingBulanan, maksKolomKomparatif+1)
+		assert.Error(t, err)
+	})
+
+	t.Run("pembanding dan tanggal tidak valid ditolak", func(t *testing.T) {
+		_, err := kolomPerTanggal("2025-12-31", "kuartalan", 0)
+		assert.Error(t, err)
+		_, err = kolomPerTanggal("31-12-2025", PembandingTahunLalu, 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestKolomPeriode(t *testing.T) {
+	t.Run("tahun lalu", func(t *testing.T) {
+		kolom, err := kolomPeriode("2025-01-01", "2025-12-31", PembandingTahunLalu)
+		require.NoError(t, err)
+		require.Len(t, kolom, 2)
+		assert.Equal(t, "2024-01-01", kolom[0].TanggalMulai.Format("2006-01-02"))
+		assert.Equal(t, "2024-12-31", kolom[0].TanggalAkhir.Format("2006-01-02"))
+		assert.Equal(t, "2025-01-01 s.d. 2025-12-31", kolom[1].Label)
+	})
+
+	t.Run("bulanan dipotong sesuai periode", func(t *testing.T) {
+		kolom, err := kolomPeriode("2025-01-15", "2025-03-10", PembandingBulanan)
+		require.NoError(t, err)
+		require.Len(t, kolom, 3)
+		assert.Equal(t, "2025-01", kolom[0].Label)
+		assert.Equal(t, "2025-01-15", kolom[0].TanggalMulai.Format("2006-01-02"))
+		assert.Equal(t, "2025-01-31", kolom[0].TanggalAkhir.Format("2006-01-02"))
+		assert.Equal(t, "2025-02-01", kolom[1].TanggalMulai.Format("2006-01-02"))
+		assert.Equal(t, "2025-02-28", kolom[1].TanggalAkhir.Format("2006-01-02"))
+		assert.Equal(t, "2025-03-10", kolom[2].TanggalAkhir.Format("2006-01-02"))
+	})
+
+	t.Run("bulanan maksimal 24 bulan", func(t *testing.T) {
+		_, err := kolomPeriode("2023-01-01", "2025-01-31", PembandingBulanan)
+		assert.Error(t, err)
+	})
+
+	t.Run("tanggal akhir sebelum tanggal mulai ditolak", func(t *testing.T) {
+		_, err := kolomPeriode("2025-12-31", "2025-01-01", PembandingTahunLalu)
+		assert.Error(t, err)
+	})
+}
+
+func TestNilaiKomparatif(t *testing.T) {
+	nilai := nilaiKomparatif([]models.Uang{0, models.Rupiah(1000), models.Rupiah(750)})
+	require.Len(t, nilai, 3)
+
+	assert.Nil(t, nilai[0].Selisih)
+	assert.Nil(t, nilai[0].Persentase)
+
+	assert.Equal(t, models.Rupiah(1000), *nilai[1].Selisih)
+	assert.Nil(t, nilai[1].Persentase, "persentase kosong jika kolom sebelumnya nol")
+
+	assert.Equal(t, models.Rupiah(-250), *nilai[2].Selisih)
+	assert.Equal(t, -25.0, *nilai[2].Persentase)
+}
+
+func TestPenyusunKomparatif(t *testing.T) {
+	penyusun := baruPenyusunKomparatif(2, "Aset")
+	penyusun.tambahItem(0, 0, []ItemLaporanKeuangan{
+		{KodeAkun: "1102", NamaAkun: "Bank", Saldo: models.Rupiah(500)},
+		{KodeAkun: "1101", NamaAkun: "Kas", Saldo: models.Rupiah(100)},
+	})
+	penyusun.tambahItem(0, 1, []ItemLaporanKeuangan{
+		{KodeAkun: "1101", NamaAkun: "Kas", Saldo: models.Rupiah(300)},
+	})
+	penyusun.tambahRingkasan(0, "Total", models.Rupiah(600))
+	penyusun.tambahRingkasan(1, "Total", models.Rupiah(300))
+
+	laporan := penyusun.hasil(PembandingTahunLalu, []KolomLaporan{{Label: "a"}, {Label: "b"}}, "Total", "Tidak Ada")
+	require.Len(t, laporan.Bagian, 1)
+
+	baris := laporan.Bagian[0].Baris
+	require.Len(t, baris, 2)
+	assert.Equal(t, "1101", baris[0].KodeAkun)
+	assert.Equal(t, models.Rupiah(200), *baris[0].Nilai[1].Selisih)
+	assert.Equal(t, "1102", baris[1].KodeAkun)
+	assert.Equal(t, models.Uang(0), baris[1].Nilai[1].Nilai, "akun yang tidak muncul pada kolom bernilai nol")
+
+	assert.Equal(t, models.Rupiah(600), laporan.Bagian[0].Total[0].Nilai)
+	assert.Equal(t, models.Rupiah(300), laporan.Bagian[0].Total[1].Nilai)
+
+	require.Len(t, laporan.Ringkasan, 1, "ringkasan yang tidak dicatat dilewati")
+	assert.Equal(t, models.Rupiah(-300), *laporan.Ringkasan[0].Nilai[1].Selisih)
+}
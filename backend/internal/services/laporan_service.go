@@ -15,6 +15,9 @@ type LaporanService struct {
 	akunService      *AkunService
 	simpananService  *SimpananService
 	penjualanService *PenjualanService
+
+	// idUnitUsaha membatasi laporan berbasis jurnal ke satu unit usaha (lihat UntukUnitUsaha)
+	idUnitUsaha *uuid.UUID
 }
 
 // NewLaporanService membuat instance baru LaporanService
@@ -27,26 +30,36 @@ func NewLaporanService(db *gorm.DB, akunService *AkunService, simpananService *S
 	}
 }
 
+// UntukUnitUsaha mengembalikan salinan LaporanService yang laporan berbasis jurnalnya (posisi keuangan,
+// laba rugi, arus kas, perubahan modal, buku besar, neraca saldo, dan transaksi harian) hanya
+// menghitung baris jurnal satu unit usaha. nil berarti seluruh unit usaha; uuid.Nil berarti
+// baris jurnal tanpa unit usaha.
+func (s *LaporanService) UntukUnitUsaha(idUnitUsaha *uuid.UUID) *LaporanService {
+	salinan := *s
+	salinan.idUnitUsaha = idUnitUsaha
+	return &salinan
+}
+
 // LaporanPosisiKeuangan adalah struktur untuk Balance Sheet
 type LaporanPosisiKeuangan struct {
 	TanggalLaporan time.Time             `json:"tanggalLaporan"`
 	Aset           []ItemLaporanKeuangan `json:"aset"`
-	TotalAset      float64               `json:"totalAset"`
+	TotalAset      models.Uang           `json:"totalAset"`
 	Kewajiban      []ItemLaporanKeuangan `json:"kewajiban"`
-	TotalKewajiban float64               `json:"totalKewajiban"`
+	TotalKewajiban models.Uang           `json:"totalKewajiban"`
 	Modal          []ItemLaporanKeuangan `json:"modal"`
-	TotalModal     float64               `json:"totalModal"`
+	TotalModal     models.Uang           `json:"totalModal"`
 }
 
 // ItemLaporanKeuangan adalah struktur untuk item dalam laporan
 type ItemLaporanKeuangan struct {
-	KodeAkun string  `json:"kodeAkun"`
-	NamaAkun string  `json:"namaAkun"`
-	Saldo    float64 `json:"saldo"`
+	KodeAkun string      `json:"kodeAkun"`
+	NamaAkun string      `json:"namaAkun"`
+	Saldo    models.Uang `json:"saldo"`
 }
 
-// GenerateLaporanPosisiKeuangan membuat laporan neraca/balance sheet
-// Optimized version using single query with aggregation to eliminate N+1 query problem
+// GenerateLaporanPosisiKeuangan membuat laporan neraca/balance sheet.
+// Saldo akun dibaca dari snapshot saldo bulanan ditambah jurnal bulan laporan (lihat hitungTotalMutasiAkun).
 func (s *LaporanService) GenerateLaporanPosisiKeuangan(idKoperasi uuid.UUID, tanggalPer string) (*LaporanPosisiKeuangan, error) {
 	// Parse tanggal
 	var tanggalLaporan time.Time
@@ -67,54 +80,56 @@ func (s *LaporanService) GenerateLaporanPosisiKeuangan(idKoperasi uuid.UUID, tan
 		Modal:          []ItemLaporanKeuangan{},
 	}
 
-	// Structure to hold aggregated account balances
+	// Structure to hold account data
 	type AccountBalance struct {
+		ID          uuid.UUID
 		KodeAkun    string
 		NamaAkun    string
 		TipeAkun    models.TipeAkun
 		NormalSaldo string
-		TotalDebit  float64
-		TotalKredit float64
 	}
 
 	var balances []AccountBalance
-
-	// Single optimized query with JOIN and aggregation
-	// This replaces the N+1 pattern (1 query for accounts + N queries for balances)
-	query := s.db.Table("akun").
-		Select(`
-			akun.kode_akun,
-			akun.nama_akun,
-			akun.tipe_akun,
-			akun.normal_saldo,
-			COALESCE(SUM(baris_transaksi.jumlah_debit), 0) as total_debit,
-			COALESCE(SUM(baris_transaksi.jumlah_kredit), 0) as total_kredit
-		`).
-		Joins("LEFT JOIN baris_transaksi ON baris_transaksi.id_akun = akun.id").
-		Joins("LEFT JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
-		Where("akun.id_koperasi = ?", idKoperasi)
-
-	// Apply date filter if provided
-	if tanggalPer != "" {
-		query = query.Where("transaksi.tanggal_transaksi <= ? OR transaksi.id IS NULL", tanggalPer)
+	err := s.db.Table("akun").
+		Select("akun.id, akun.kode_akun, akun.nama_akun, akun.tipe_akun, akun.normal_saldo").
+		Where("akun.id_koperasi = ?", idKoperasi).
+		Order("akun.kode_akun ASC").
+		Scan(&balances).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil data laporan posisi keuangan")
 	}
 
-	query = query.Group("akun.id, akun.kode_akun, akun.nama_akun, akun.tipe_akun, akun.normal_saldo").
-		Order("akun.kode_akun ASC")
-
-	err := query.Scan(&balances).Error
+	// Total mutasi seluruh akun dari snapshot saldo bulanan ditambah jurnal sejak awal bulan laporan,
+	// sehingga jumlah baris jurnal yang dibaca tidak bertambah seiring umur data
+	mutasi, err := hitungTotalMutasiAkun(s.db, idKoperasi, nil, tanggalPer, s.idUnitUsaha)
 	if err != nil {
 		return nil, errors.New("gagal mengambil data laporan posisi keuangan")
 	}
 
+	// Saldo pendapatan dan beban yang belum ditutup menjadi SHU periode berjalan
+	var shuBelumDitutup models.Uang
+
 	// Process balances and categorize by account type
 	for _, balance := range balances {
 		// Calculate balance based on normal balance
-		var saldo float64
+		total := mutasi[balance.ID]
+		var saldo models.Uang
 		if balance.NormalSaldo == "DEBIT" {
-			saldo = balance.TotalDebit - balance.TotalKredit
+			saldo = total.TotalDebit - total.TotalKredit
 		} else {
-			saldo = balance.TotalKredit - balance.TotalDebit
+			saldo = total.TotalKredit - total.TotalDebit
+		}
+
+		switch balance.TipeAkun {
+		case models.AkunPendapatan:
+			shuBelumDitutup += saldo
+		case models.AkunBeban:
+			shuBelumDitutup -= saldo
+		}
+
+		// Akun kontra aset (misalnya akumulasi penyusutan) bersaldo normal kredit mengurangi total aset
+		if balance.TipeAkun == models.AkunAktiva && balance.NormalSaldo == "KREDIT" {
+			saldo = -saldo
 		}
 
 		// Skip accounts with zero balance
@@ -142,6 +157,15 @@ func (s *LaporanService) GenerateLaporanPosisiKeuangan(idKoperasi uuid.UUID, tan
 		}
 	}
 
+	// SHU tahun yang sudah ditutup sudah berada di akun 3201; sisanya adalah SHU yang belum ditutup
+	if shuBelumDitutup != 0 {
+		laporan.Modal = append(laporan.Modal, ItemLaporanKeuangan{
+			NamaAkun: "SHU Periode Berjalan (belum ditutup)",
+			Saldo:    shuBelumDitutup,
+		})
+		laporan.TotalModal += shuBelumDitutup
+	}
+
 	return laporan, nil
 }
 
@@ -150,10 +174,10 @@ type LaporanLabaRugi struct {
 	PeriodeMulai    time.Time             `json:"periodeMulai"`
 	PeriodeAkhir    time.Time             `json:"periodeAkhir"`
 	Pendapatan      []ItemLaporanKeuangan `json:"pendapatan"`
-	TotalPendapatan float64               `json:"totalPendapatan"`
+	TotalPendapatan models.Uang           `json:"totalPendapatan"`
 	Beban           []ItemLaporanKeuangan `json:"beban"`
-	TotalBeban      float64               `json:"totalBeban"`
-	LabaRugiBersih  float64               `json:"labaRugiBersih"`
+	TotalBeban      models.Uang           `json:"totalBeban"`
+	LabaRugiBersih  models.Uang           `json:"labaRugiBersih"`
 }
 
 // GenerateLaporanLabaRugi membuat laporan laba rugi
@@ -178,17 +202,21 @@ func (s *LaporanService) GenerateLaporanLabaRugi(idKoperasi uuid.UUID, tanggalMu
 	}
 
 	// Structure to hold aggregated income and expense data
+	// Jurnal penutupan tahun buku dikecualikan agar laba rugi tahun yang sudah ditutup tetap terlihat
 	type IncomeExpenseBalance struct {
 		KodeAkun    string
 		NamaAkun    string
 		TipeAkun    models.TipeAkun
 		NormalSaldo string
-		TotalDebit  float64
-		TotalKredit float64
+		TotalDebit  models.Uang
+		TotalKredit models.Uang
 	}
 
 	var balances []IncomeExpenseBalance
 
+	kondisiUnit, argsUnit := kondisiUnitUsaha(s.idUnitUsaha)
+	argsJoin := append([]interface{}{tanggalMulai, tanggalAkhir, models.TipeTransaksiPenutupan}, argsUnit...)
+
 	// Single optimized query for period-specific balances
 	// This replaces the N+1 pattern (fetching accounts + calculating balance for each)
 	err = s.db.Table("akun").
@@ -197,11 +225,12 @@ func (s *LaporanService) GenerateLaporanLabaRugi(idKoperasi uuid.UUID, tanggalMu
 			akun.nama_akun,
 			akun.tipe_akun,
 			akun.normal_saldo,
-			COALESCE(SUM(baris_transaksi.jumlah_debit), 0) as total_debit,
-			COALESCE(SUM(baris_transaksi.jumlah_kredit), 0) as total_kredit
+			COALESCE(SUM(CASE WHEN transaksi.id IS NOT NULL THEN baris_transaksi.jumlah_debit END), 0) as total_debit,
+			COALESCE(SUM(CASE WHEN transaksi.id IS NOT NULL THEN baris_transaksi.jumlah_kredit END), 0) as total_kredit
 		`).
 		Joins("LEFT JOIN baris_transaksi ON baris_transaksi.id_akun = akun.id").
-		Joins("LEFT JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi AND transaksi.tanggal_transaksi BETWEEN ? AND ?", tanggalMulai, tanggalAkhir).
+		Joins("LEFT JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi AND "+kondisiJurnalPosted+" AND transaksi.tanggal_transaksi BETWEEN ? AND ? AND COALESCE(transaksi.tipe_transaksi, '') <> ? AND "+kondisiUnit,
+			argsJoin...).
 		Where("akun.id_koperasi = ? AND akun.tipe_akun IN (?)", idKoperasi, []models.TipeAkun{models.AkunPendapatan, models.AkunBeban}).
 		Group("akun.id, akun.kode_akun, akun.nama_akun, akun.tipe_akun, akun.normal_saldo").
 		Order("akun.kode_akun ASC").
@@ -214,7 +243,7 @@ func (s *LaporanService) GenerateLaporanLabaRugi(idKoperasi uuid.UUID, tanggalMu
 	// Process balances and categorize
 	for _, balance := range balances {
 		// Calculate balance based on normal balance
-		var saldoPeriode float64
+		var saldoPeriode models.Uang
 		if balance.NormalSaldo == "DEBIT" {
 			saldoPeriode = balance.TotalDebit - balance.TotalKredit
 		} else {
@@ -249,23 +278,38 @@ func (s *LaporanService) GenerateLaporanLabaRugi(idKoperasi uuid.UUID, tanggalMu
 	return laporan, nil
 }
 
+// MetodeArusKas menentukan cara penyajian arus kas dari aktivitas operasi
+type MetodeArusKas string
+
+const (
+	MetodeArusKasLangsung      MetodeArusKas = "LANGSUNG"       // Direct method: dari baris jurnal yang menyentuh kas
+	MetodeArusKasTidakLangsung MetodeArusKas = "TIDAK_LANGSUNG" // Indirect method: dari laba rugi bersih dan perubahan akun
+)
+
 // LaporanArusKas adalah struktur untuk Cash Flow Statement
 type LaporanArusKas struct {
 	PeriodeMulai       time.Time             `json:"periodeMulai"`
 	PeriodeAkhir       time.Time             `json:"periodeAkhir"`
+	Metode             MetodeArusKas         `json:"metode"`
 	ArusKasOperasional []ItemLaporanKeuangan `json:"arusKasOperasional"`
-	TotalOperasional   float64               `json:"totalOperasional"`
+	TotalOperasional   models.Uang           `json:"totalOperasional"`
 	ArusKasInvestasi   []ItemLaporanKeuangan `json:"arusKasInvestasi"`
-	TotalInvestasi     float64               `json:"totalInvestasi"`
+	TotalInvestasi     models.Uang           `json:"totalInvestasi"`
 	ArusKasPendanaan   []ItemLaporanKeuangan `json:"arusKasPendanaan"`
-	TotalPendanaan     float64               `json:"totalPendanaan"`
-	KenaikanKasBersih  float64               `json:"kenaikanKasBersih"`
-	SaldoKasAwal       float64               `json:"saldoKasAwal"`
-	SaldoKasAkhir      float64               `json:"saldoKasAkhir"`
+	TotalPendanaan     models.Uang           `json:"totalPendanaan"`
+	KenaikanKasBersih  models.Uang           `json:"kenaikanKasBersih"`
+	SaldoKasAwal       models.Uang           `json:"saldoKasAwal"`
+	SaldoKasAkhir      models.Uang           `json:"saldoKasAkhir"`
+	KasDanSetaraKas    []ItemLaporanKeuangan `json:"kasDanSetaraKas"` // Saldo akhir per akun kas dan setara kas
 }
 
-// GenerateLaporanArusKas membuat laporan arus kas
-func (s *LaporanService) GenerateLaporanArusKas(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir string) (*LaporanArusKas, error) {
+// GenerateLaporanArusKas membuat laporan arus kas berdasarkan klasifikasi arus kas setiap akun.
+//
+// Metode langsung mengelompokkan lawan transaksi dari setiap jurnal yang menyentuh akun kas dan setara kas.
+// Metode tidak langsung menyajikan aktivitas operasi dari laba rugi bersih ditambah perubahan akun operasi;
+// aktivitas investasi dan pendanaan sama pada kedua metode. Jurnal penutupan tahun buku diabaikan
+// karena tidak melibatkan kas.
+func (s *LaporanService) GenerateLaporanArusKas(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir string, metode MetodeArusKas) (*LaporanArusKas, error) {
 	// Parse tanggal
 	periodeMulai, err := time.Parse("2006-01-02", tanggalMulai)
 	if err != nil {
@@ -277,33 +321,180 @@ func (s *LaporanService) GenerateLaporanArusKas(idKoperasi uuid.UUID, tanggalMul
 		return nil, errors.New("format tanggal akhir tidak valid")
 	}
 
-	// Dapatkan akun kas (1101)
-	akunKas, err := s.akunService.DapatkanAkunByKode(idKoperasi, "1101")
-	if err != nil {
-		return nil, errors.New("akun kas tidak ditemukan")
+	if metode == "" {
+		metode = MetodeArusKasLangsung
+	}
+	if metode != MetodeArusKasLangsung && metode != MetodeArusKasTidakLangsung {
+		return nil, errors.New("metode arus kas tidak valid")
 	}
 
-	// Saldo kas awal periode
-	tanggalSebelum := periodeMulai.AddDate(0, 0, -1).Format("2006-01-02")
-	saldoKasAwal, _ := s.akunService.HitungSaldoAkun(akunKas.ID, tanggalSebelum)
+	// Klasifikasi arus kas setiap akun
+	var akunList []models.Akun
+	if err := s.db.Where("id_koperasi = ?", idKoperasi).Order("kode_akun ASC").Find(&akunList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar akun")
+	}
 
-	// Saldo kas akhir periode
-	saldoKasAkhir, _ := s.akunService.HitungSaldoAkun(akunKas.ID, tanggalAkhir)
+	akunByID := make(map[uuid.UUID]models.Akun, len(akunList))
+	var idAkunKas []uuid.UUID
+	for _, akun := range akunList {
+		akunByID[akun.ID] = akun
+		if akun.KategoriArusKasEfektif() == models.ArusKasKas {
+			idAkunKas = append(idAkunKas, akun.ID)
+		}
+	}
+	if len(idAkunKas) == 0 {
+		return nil, errors.New("akun kas tidak ditemukan")
+	}
 
-	// Untuk MVP, simplified cash flow (langsung dari mutasi kas)
 	laporan := &LaporanArusKas{
 		PeriodeMulai:       periodeMulai,
 		PeriodeAkhir:       periodeAkhir,
-		SaldoKasAwal:       saldoKasAwal,
-		SaldoKasAkhir:      saldoKasAkhir,
-		KenaikanKasBersih:  saldoKasAkhir - saldoKasAwal,
+		Metode:             metode,
 		ArusKasOperasional: []ItemLaporanKeuangan{},
 		ArusKasInvestasi:   []ItemLaporanKeuangan{},
 		ArusKasPendanaan:   []ItemLaporanKeuangan{},
+		KasDanSetaraKas:    []ItemLaporanKeuangan{},
+	}
+
+	// Saldo kas dan setara kas awal dan akhir periode. Jurnal saldo awal migrasi selalu
+	// dihitung sebagai saldo awal, bukan arus kas periode.
+	type saldoKas struct {
+		IDAkun     uuid.UUID
+		SaldoAwal  models.Uang
+		SaldoAkhir models.Uang
+	}
+	kondisiUnit, argsUnit := kondisiUnitUsaha(s.idUnitUsaha)
+
+	var saldoKasList []saldoKas
+	err = s.db.Table("baris_transaksi").
+		Select(`
+			baris_transaksi.id_akun,
+			COALESCE(SUM(CASE WHEN transaksi.tanggal_transaksi < ? OR transaksi.tipe_transaksi = ? THEN baris_transaksi.jumlah_debit - baris_transaksi.jumlah_kredit END), 0) as saldo_awal,
+			COALESCE(SUM(baris_transaksi.jumlah_debit - baris_transaksi.jumlah_kredit), 0) as saldo_akhir
+		`, tanggalMulai, models.TipeTransaksiSaldoAwal).
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+		Where("transaksi.tanggal_transaksi <= ? AND baris_transaksi.id_akun IN ?", tanggalAkhir, idAkunKas).
+		Where(kondisiUnit, argsUnit...).
+		Group("baris_transaksi.id_akun").
+		Scan(&saldoKasList).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung saldo kas")
+	}
+
+	saldoKasAkhir := make(map[uuid.UUID]models.Uang)
+	for _, saldo := range saldoKasList {
+		laporan.SaldoKasAwal += saldo.SaldoAwal
+		laporan.SaldoKasAkhir += saldo.SaldoAkhir
+		saldoKasAkhir[saldo.IDAkun] = saldo.SaldoAkhir
+	}
+	laporan.KenaikanKasBersih = laporan.SaldoKasAkhir - laporan.SaldoKasAwal
+
+	for _, idAkun := range idAkunKas {
+		akun := akunByID[idAkun]
+		laporan.KasDanSetaraKas = append(laporan.KasDanSetaraKas, ItemLaporanKeuangan{
+			KodeAkun: akun.KodeAkun,
+			NamaAkun: akun.NamaAkun,
+			Saldo:    saldoKasAkhir[idAkun],
+		})
+	}
+
+	// Mutasi (kredit - debit) setiap akun non-kas selama periode, dipisah antara jurnal yang menyentuh kas
+	// dan jurnal non-kas. Pada jurnal kas, jumlah kredit - debit lawan transaksi sama dengan kas yang diterima.
+	type mutasiAkun struct {
+		IDAkun       uuid.UUID
+		MutasiKas    models.Uang
+		MutasiNonKas models.Uang
 	}
+	jurnalKas := s.db.Table("baris_transaksi").Select("id_transaksi").Where("id_akun IN ?", idAkunKas)
 
-	// Simplified: kenaikan = total operasional + investasi + pendanaan
-	laporan.TotalOperasional = laporan.KenaikanKasBersih
+	var mutasiList []mutasiAkun
+	err = s.db.Table("baris_transaksi").
+		Select(`
+			baris_transaksi.id_akun,
+			COALESCE(SUM(CASE WHEN transaksi.id IN (?) THEN baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit END), 0) as mutasi_kas,
+			COALESCE(SUM(CASE WHEN transaksi.id NOT IN (?) THEN baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit END), 0) as mutasi_non_kas
+		`, jurnalKas, jurnalKas).
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+		Where("transaksi.tanggal_transaksi BETWEEN ? AND ?", tanggalMulai, tanggalAkhir).
+		Where("COALESCE(transaksi.tipe_transaksi, '') NOT IN ?", []string{models.TipeTransaksiPenutupan, models.TipeTransaksiSaldoAwal}).
+		Where("baris_transaksi.id_akun NOT IN ?", idAkunKas).
+		Where(kondisiUnit, argsUnit...).
+		Group("baris_transaksi.id_akun").
+		Scan(&mutasiList).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung mutasi arus kas")
+	}
+
+	mutasiByAkun := make(map[uuid.UUID]mutasiAkun, len(mutasiList))
+	for _, mutasi := range mutasiList {
+		mutasiByAkun[mutasi.IDAkun] = mutasi
+	}
+
+	// Laba rugi bersih dan penyesuaian pos investasi/pendanaan (metode tidak langsung)
+	var labaRugiBersih, penyesuaianNonKas models.Uang
+
+	for _, akun := range akunList {
+		mutasi, ok := mutasiByAkun[akun.ID]
+		if !ok {
+			continue
+		}
+
+		kategori := akun.KategoriArusKasEfektif()
+		investasiAtauPendanaan := kategori == models.ArusKasInvestasi || kategori == models.ArusKasPendanaan
+
+		// Aktivitas investasi dan pendanaan hanya berasal dari jurnal yang menyentuh kas
+		jumlah := mutasi.MutasiKas
+		if metode == MetodeArusKasTidakLangsung {
+			if akun.TipeAkun == models.AkunPendapatan || akun.TipeAkun == models.AkunBeban {
+				labaRugiBersih += mutasi.MutasiKas + mutasi.MutasiNonKas
+				if !investasiAtauPendanaan {
+					continue
+				}
+				// Laba rugi yang tergolong investasi/pendanaan dikeluarkan dari aktivitas operasi
+				penyesuaianNonKas -= mutasi.MutasiKas + mutasi.MutasiNonKas
+			}
+			if investasiAtauPendanaan {
+				penyesuaianNonKas += mutasi.MutasiNonKas
+			} else {
+				jumlah += mutasi.MutasiNonKas
+			}
+		}
+
+		if jumlah == 0 {
+			continue
+		}
+
+		item := ItemLaporanKeuangan{KodeAkun: akun.KodeAkun, NamaAkun: akun.NamaAkun, Saldo: jumlah}
+		switch kategori {
+		case models.ArusKasInvestasi:
+			laporan.ArusKasInvestasi = append(laporan.ArusKasInvestasi, item)
+			laporan.TotalInvestasi += jumlah
+		case models.ArusKasPendanaan:
+			laporan.ArusKasPendanaan = append(laporan.ArusKasPendanaan, item)
+			laporan.TotalPendanaan += jumlah
+		default:
+			laporan.ArusKasOperasional = append(laporan.ArusKasOperasional, item)
+			laporan.TotalOperasional += jumlah
+		}
+	}
+
+	if metode == MetodeArusKasTidakLangsung {
+		// Laba rugi bersih menjadi baris pertama aktivitas operasi, diikuti penyesuaian
+		penyesuaian := []ItemLaporanKeuangan{{NamaAkun: "Laba (rugi) bersih", Saldo: labaRugiBersih}}
+		laporan.ArusKasOperasional = append(penyesuaian, laporan.ArusKasOperasional...)
+		laporan.TotalOperasional += labaRugiBersih
+
+		// Transaksi non-kas dan laba rugi yang berkaitan dengan pos investasi/pendanaan tidak menghasilkan kas operasi
+		if penyesuaianNonKas != 0 {
+			laporan.ArusKasOperasional = append(laporan.ArusKasOperasional, ItemLaporanKeuangan{
+				NamaAkun: "Penyesuaian pos investasi dan pendanaan",
+				Saldo:    penyesuaianNonKas,
+			})
+			laporan.TotalOperasional += penyesuaianNonKas
+		}
+	}
 
 	return laporan, nil
 }
@@ -317,9 +508,9 @@ func (s *LaporanService) GenerateLaporanSaldoAnggota(idKoperasi uuid.UUID) ([]mo
 type LaporanPenjualan struct {
 	PeriodeMulai      time.Time                `json:"periodeMulai"`
 	PeriodeAkhir      time.Time                `json:"periodeAkhir"`
-	TotalPenjualan    float64                  `json:"totalPenjualan"`
+	TotalPenjualan    models.Uang              `json:"totalPenjualan"`
 	JumlahTransaksi   int64                    `json:"jumlahTransaksi"`
-	RataRataTransaksi float64                  `json:"rataRataTransaksi"`
+	RataRataTransaksi models.Uang              `json:"rataRataTransaksi"`
 	TopProduk         []map[string]interface{} `json:"topProduk"`
 }
 
@@ -350,9 +541,9 @@ func (s *LaporanService) GenerateLaporanPenjualan(idKoperasi uuid.UUID, tanggalM
 	laporan := &LaporanPenjualan{
 		PeriodeMulai:      periodeMulai,
 		PeriodeAkhir:      periodeAkhir,
-		TotalPenjualan:    summary["totalPenjualan"].(float64),
+		TotalPenjualan:    summary["totalPenjualan"].(models.Uang),
 		JumlahTransaksi:   summary["jumlahTransaksi"].(int64),
-		RataRataTransaksi: summary["rataRata"].(float64),
+		RataRataTransaksi: summary["rataRata"].(models.Uang),
 		TopProduk:         topProduk,
 	}
 
@@ -361,12 +552,12 @@ func (s *LaporanService) GenerateLaporanPenjualan(idKoperasi uuid.UUID, tanggalM
 
 // LaporanTransaksiHarian adalah struktur untuk daily transaction report
 type LaporanTransaksiHarian struct {
-	Tanggal         time.Time `json:"tanggal"`
-	TotalKasMasuk   float64   `json:"totalKasMasuk"`
-	TotalKasKeluar  float64   `json:"totalKasKeluar"`
-	SaldoKasAkhir   float64   `json:"saldoKasAkhir"`
-	JumlahPenjualan int64     `json:"jumlahPenjualan"`
-	JumlahSimpanan  int64     `json:"jumlahSimpanan"`
+	Tanggal         time.Time   `json:"tanggal"`
+	TotalKasMasuk   models.Uang `json:"totalKasMasuk"`
+	TotalKasKeluar  models.Uang `json:"totalKasKeluar"`
+	SaldoKasAkhir   models.Uang `json:"saldoKasAkhir"`
+	JumlahPenjualan int64       `json:"jumlahPenjualan"`
+	JumlahSimpanan  int64       `json:"jumlahSimpanan"`
 }
 
 // GenerateLaporanTransaksiHarian membuat laporan transaksi harian
@@ -376,24 +567,32 @@ func (s *LaporanService) GenerateLaporanTransaksiHarian(idKoperasi uuid.UUID, ta
 		return nil, errors.New("format tanggal tidak valid")
 	}
 
-	// Dapatkan akun kas
-	akunKas, err := s.akunService.DapatkanAkunByKode(idKoperasi, "1101")
+	// Dapatkan akun kas dan setara kas
+	akunKasList, err := s.akunKasDanSetaraKas(idKoperasi)
 	if err != nil {
-		return nil, errors.New("akun kas tidak ditemukan")
+		return nil, err
 	}
 
 	// Hitung saldo kas akhir hari
-	saldoKas, _ := s.akunService.HitungSaldoAkun(akunKas.ID, tanggal)
+	var saldoKas models.Uang
+	idAkunKas := make([]uuid.UUID, len(akunKasList))
+	for i := range akunKasList {
+		saldo, _ := s.hitungSaldoAkun(&akunKasList[i], tanggal)
+		saldoKas += saldo
+		idAkunKas[i] = akunKasList[i].ID
+	}
+	kondisiUnit, argsUnit := kondisiUnitUsaha(s.idUnitUsaha)
 
 	// Hitung total kas masuk (debit ke kas)
 	type KasResult struct {
-		Total float64
+		Total models.Uang
 	}
 	var kasMasuk KasResult
 	s.db.Model(&models.BarisTransaksi{}).
 		Select("COALESCE(SUM(jumlah_debit), 0) as total").
 		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
-		Where("baris_transaksi.id_akun = ? AND DATE(transaksi.tanggal_transaksi) = ?", akunKas.ID, tanggal).
+		Where("baris_transaksi.id_akun IN ? AND DATE(transaksi.tanggal_transaksi) = ? AND "+kondisiJurnalPosted, idAkunKas, tanggal).
+		Where(kondisiUnit, argsUnit...).
 		Scan(&kasMasuk)
 
 	// Hitung total kas keluar (kredit dari kas)
@@ -401,18 +600,24 @@ func (s *LaporanService) GenerateLaporanTransaksiHarian(idKoperasi uuid.UUID, ta
 	s.db.Model(&models.BarisTransaksi{}).
 		Select("COALESCE(SUM(jumlah_kredit), 0) as total").
 		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
-		Where("baris_transaksi.id_akun = ? AND DATE(transaksi.tanggal_transaksi) = ?", akunKas.ID, tanggal).
+		Where("baris_transaksi.id_akun IN ? AND DATE(transaksi.tanggal_transaksi) = ? AND "+kondisiJurnalPosted, idAkunKas, tanggal).
+		Where(kondisiUnit, argsUnit...).
 		Scan(&kasKeluar)
 
-	// Hitung jumlah transaksi
+	// Hitung jumlah transaksi. Dengan filter unit usaha, hanya dokumen yang jurnalnya
+	// memiliki baris unit usaha tersebut yang dihitung.
 	var jumlahPenjualan, jumlahSimpanan int64
-	s.db.Model(&models.Penjualan{}).
-		Where("id_koperasi = ? AND DATE(tanggal_penjualan) = ?", idKoperasi, tanggal).
-		Count(&jumlahPenjualan)
-
-	s.db.Model(&models.Simpanan{}).
-		Where("id_koperasi = ? AND DATE(tanggal_transaksi) = ?", idKoperasi, tanggal).
-		Count(&jumlahSimpanan)
+	queryPenjualan := s.db.Model(&models.Penjualan{}).
+		Where("id_koperasi = ? AND dibatalkan = ? AND DATE(tanggal_penjualan) = ?", idKoperasi, false, tanggal)
+	querySimpanan := s.db.Model(&models.Simpanan{}).
+		Where("id_koperasi = ? AND dibatalkan = ? AND DATE(tanggal_transaksi) = ?", idKoperasi, false, tanggal)
+	if s.idUnitUsaha != nil {
+		jurnalUnit := s.db.Table("baris_transaksi").Select("id_transaksi").Where(kondisiUnit, argsUnit...)
+		queryPenjualan = queryPenjualan.Where("id_transaksi IN (?)", jurnalUnit)
+		querySimpanan = querySimpanan.Where("id_transaksi IN (?)", jurnalUnit)
+	}
+	queryPenjualan.Count(&jumlahPenjualan)
+	querySimpanan.Count(&jumlahSimpanan)
 
 	laporan := &LaporanTransaksiHarian{
 		Tanggal:         tgl,
@@ -450,23 +655,214 @@ func (s *LaporanService) GetDashboardStats(idKoperasi uuid.UUID) (map[string]int
 		stats["transaksiHariIni"] = penjualanHariIni["jumlahTransaksi"]
 	}
 
-	// Saldo kas
-	akunKas, err := s.akunService.DapatkanAkunByKode(idKoperasi, "1101")
+	// Saldo kas dan setara kas
+	akunKasList, err := s.akunKasDanSetaraKas(idKoperasi)
 	if err == nil {
-		saldoKas, _ := s.akunService.HitungSaldoAkun(akunKas.ID, "")
+		var saldoKas models.Uang
+		for _, akun := range akunKasList {
+			saldo, _ := s.akunService.HitungSaldoAkun(akun.ID, "")
+			saldoKas += saldo
+		}
 		stats["saldoKas"] = saldoKas
 	}
 
 	return stats, nil
 }
 
-// GenerateLaporanPerubahanModal generates statement of changes in equity
-func (s *LaporanService) GenerateLaporanPerubahanModal(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir string) (map[string]interface{}, error) {
-	// This is a placeholder implementation
-	// TODO: Implement full statement of changes in equity
-	return map[string]interface{}{
-		"message": "Laporan Perubahan Modal - Not yet implemented",
-	}, nil
+// akunKasDanSetaraKas mengambil akun yang diklasifikasikan sebagai kas dan setara kas,
+// sama seperti penentuan akun kas pada laporan arus kas
+func (s *LaporanService) akunKasDanSetaraKas(idKoperasi uuid.UUID) ([]models.Akun, error) {
+	var akunList []models.Akun
+	if err := s.db.Where("id_koperasi = ?", idKoperasi).Order("kode_akun ASC").Find(&akunList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar akun")
+	}
+
+	var akunKas []models.Akun
+	for _, akun := range akunList {
+		if akun.KategoriArusKasEfektif() == models.ArusKasKas {
+			akunKas = append(akunKas, akun)
+		}
+	}
+	if len(akunKas) == 0 {
+		return nil, errors.New("akun kas tidak ditemukan")
+	}
+
+	return akunKas, nil
+}
+
+// LaporanPerubahanModal adalah struktur untuk Statement of Changes in Equity
+type LaporanPerubahanModal struct {
+	PeriodeMulai time.Time            `json:"periodeMulai"`
+	PeriodeAkhir time.Time            `json:"periodeAkhir"`
+	Rincian      []ItemPerubahanModal `json:"rincian"`
+	Total        ItemPerubahanModal   `json:"total"`
+}
+
+// ItemPerubahanModal adalah mutasi satu akun modal selama periode laporan.
+// SaldoAkhir = SaldoAwal + Setoran - Penarikan + SHUPeriode + PembagianSHU + MutasiLain
+type ItemPerubahanModal struct {
+	KodeAkun     string      `json:"kodeAkun"`
+	NamaAkun     string      `json:"namaAkun"`
+	SaldoAwal    models.Uang `json:"saldoAwal"`
+	Setoran      models.Uang `json:"setoran"`      // Setoran simpanan pokok, wajib, dan sukarela
+	Penarikan    models.Uang `json:"penarikan"`    // Penarikan simpanan
+	SHUPeriode   models.Uang `json:"shuPeriode"`   // SHU periode berjalan dan penutupan tahun buku
+	PembagianSHU models.Uang `json:"pembagianShu"` // Alokasi SHU ke cadangan dan bagian anggota
+	MutasiLain   models.Uang `json:"mutasiLain"`   // Jurnal umum dan mutasi lainnya
+	SaldoAkhir   models.Uang `json:"saldoAkhir"`
+}
+
+// tambah menjumlahkan seluruh kolom mutasi item lain ke item ini
+func (i *ItemPerubahanModal) tambah(lain ItemPerubahanModal) {
+	i.SaldoAwal += lain.SaldoAwal
+	i.Setoran += lain.Setoran
+	i.Penarikan += lain.Penarikan
+	i.SHUPeriode += lain.SHUPeriode
+	i.PembagianSHU += lain.PembagianSHU
+	i.MutasiLain += lain.MutasiLain
+	i.SaldoAkhir += lain.SaldoAkhir
+}
+
+// GenerateLaporanPerubahanModal membuat laporan perubahan modal (ekuitas).
+// Saldo awal dan saldo akhir mengikuti GenerateLaporanPosisiKeuangan pada tanggal sebelum periode
+// dan pada akhir periode, termasuk SHU periode berjalan yang belum ditutup, sehingga Total.SaldoAkhir
+// selalu sama dengan TotalModal neraca per tanggalAkhir.
+func (s *LaporanService) GenerateLaporanPerubahanModal(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir string) (*LaporanPerubahanModal, error) {
+	periodeMulai, err := time.Parse("2006-01-02", tanggalMulai)
+	if err != nil {
+		return nil, errors.New("format tanggal mulai tidak valid")
+	}
+
+	periodeAkhir, err := time.Parse("2006-01-02", tanggalAkhir)
+	if err != nil {
+		return nil, errors.New("format tanggal akhir tidak valid")
+	}
+
+	if periodeAkhir.Before(periodeMulai) {
+		return nil, errors.New("tanggal akhir tidak boleh sebelum tanggal mulai")
+	}
+
+	// Seluruh nilai dihitung sebagai kredit - debit. Jurnal pembalik (id_jurnal_asal terisi)
+	// mengurangi kolom jurnal yang dibaliknya, misalnya pembatalan setoran mengurangi Setoran.
+	type mutasiAkunModal struct {
+		KodeAkun     string
+		NamaAkun     string
+		TipeAkun     models.TipeAkun
+		NormalSaldo  string
+		SaldoAwal    models.Uang
+		Setoran      models.Uang
+		Penarikan    models.Uang
+		SHUPeriode   models.Uang
+		PembagianSHU models.Uang
+		Mutasi       models.Uang
+	}
+
+	// Jurnal saldo awal migrasi masuk ke saldo awal walaupun bertanggal di dalam periode
+	const dalamPeriode = "transaksi.tanggal_transaksi >= @mulai AND COALESCE(transaksi.tipe_transaksi, '') <> @saldoAwal"
+
+	kondisiUnit, argsUnit := kondisiUnitUsaha(s.idUnitUsaha)
+	argsJoin := append([]interface{}{tanggalAkhir}, argsUnit...)
+
+	var mutasiList []mutasiAkunModal
+	err = s.db.Table("akun").
+		Select(`
+			akun.kode_akun,
+			akun.nama_akun,
+			akun.tipe_akun,
+			akun.normal_saldo,
+			COALESCE(SUM(CASE WHEN transaksi.tanggal_transaksi < @mulai OR transaksi.tipe_transaksi = @saldoAwal THEN baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit END), 0) as saldo_awal,
+			COALESCE(SUM(CASE WHEN `+dalamPeriode+` AND transaksi.tipe_transaksi = @simpanan THEN
+				CASE WHEN transaksi.id_jurnal_asal IS NULL THEN baris_transaksi.jumlah_kredit ELSE -baris_transaksi.jumlah_debit END END), 0) as setoran,
+			COALESCE(SUM(CASE WHEN `+dalamPeriode+` AND transaksi.tipe_transaksi = @simpanan THEN
+				CASE WHEN transaksi.id_jurnal_asal IS NULL THEN baris_transaksi.jumlah_debit ELSE -baris_transaksi.jumlah_kredit END END), 0) as penarikan,
+			COALESCE(SUM(CASE WHEN `+dalamPeriode+` AND transaksi.tipe_transaksi = @penutupan THEN baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit END), 0) as shu_periode,
+			COALESCE(SUM(CASE WHEN `+dalamPeriode+` AND transaksi.tipe_transaksi = @shu THEN baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit END), 0) as pembagian_shu,
+			COALESCE(SUM(CASE WHEN `+dalamPeriode+` THEN baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit END), 0) as mutasi
+		`, map[string]interface{}{
+			"mulai":     tanggalMulai,
+			"simpanan":  models.TipeTransaksiSimpanan,
+			"penutupan": models.TipeTransaksiPenutupan,
+			"shu":       models.TipeTransaksiSHU,
+			"saldoAwal": models.TipeTransaksiSaldoAwal,
+		}).
+		Joins("LEFT JOIN baris_transaksi ON baris_transaksi.id_akun = akun.id").
+		Joins("LEFT JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi AND "+kondisiJurnalPosted+" AND transaksi.tanggal_transaksi <= ? AND "+kondisiUnit, argsJoin...).
+		Where("akun.id_koperasi = ? AND akun.tipe_akun IN (?)", idKoperasi, []models.TipeAkun{models.AkunModal, models.AkunPendapatan, models.AkunBeban}).
+		Group("akun.id, akun.kode_akun, akun.nama_akun, akun.tipe_akun, akun.normal_saldo").
+		Order("akun.kode_akun ASC").
+		Scan(&mutasiList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil data laporan perubahan modal")
+	}
+
+	laporan := &LaporanPerubahanModal{
+		PeriodeMulai: periodeMulai,
+		PeriodeAkhir: periodeAkhir,
+		Rincian:      []ItemPerubahanModal{},
+	}
+
+	// Pendapatan dan beban yang belum ditutup ditampilkan sebagai satu baris SHU periode berjalan,
+	// sama seperti pada neraca. Jurnal penutupan memindahkannya ke akun SHU Tahun Berjalan.
+	shuBelumDitutup := ItemPerubahanModal{NamaAkun: "SHU Periode Berjalan (belum ditutup)"}
+
+	for _, mutasi := range mutasiList {
+		// Ikuti saldo normal seperti neraca; akun beban mengurangi SHU
+		tanda := models.Uang(1)
+		if mutasi.NormalSaldo == "DEBIT" {
+			tanda = -1
+		}
+		if mutasi.TipeAkun == models.AkunBeban {
+			tanda = -tanda
+		}
+
+		saldoAwal := mutasi.SaldoAwal * tanda
+		saldoAkhir := (mutasi.SaldoAwal + mutasi.Mutasi) * tanda
+
+		if mutasi.TipeAkun != models.AkunModal {
+			shuBelumDitutup.SaldoAwal += saldoAwal
+			shuBelumDitutup.SHUPeriode += mutasi.Mutasi * tanda
+			shuBelumDitutup.SaldoAkhir += saldoAkhir
+			continue
+		}
+
+		item := ItemPerubahanModal{
+			KodeAkun:     mutasi.KodeAkun,
+			NamaAkun:     mutasi.NamaAkun,
+			SaldoAwal:    saldoAwal,
+			Setoran:      mutasi.Setoran * tanda,
+			Penarikan:    mutasi.Penarikan * tanda,
+			SHUPeriode:   mutasi.SHUPeriode * tanda,
+			PembagianSHU: mutasi.PembagianSHU * tanda,
+			MutasiLain:   (mutasi.Mutasi - mutasi.Setoran + mutasi.Penarikan - mutasi.SHUPeriode - mutasi.PembagianSHU) * tanda,
+			SaldoAkhir:   saldoAkhir,
+		}
+
+		// Lewati akun tanpa saldo dan tanpa mutasi
+		if item == (ItemPerubahanModal{KodeAkun: item.KodeAkun, NamaAkun: item.NamaAkun}) {
+			continue
+		}
+
+		laporan.Rincian = append(laporan.Rincian, item)
+		laporan.Total.tambah(item)
+	}
+
+	if shuBelumDitutup.SaldoAwal != 0 || shuBelumDitutup.SHUPeriode != 0 || shuBelumDitutup.SaldoAkhir != 0 {
+		laporan.Rincian = append(laporan.Rincian, shuBelumDitutup)
+		laporan.Total.tambah(shuBelumDitutup)
+	}
+	laporan.Total.NamaAkun = "Total Modal"
+
+	return laporan, nil
+}
+
+// BarisBukuBesar adalah satu baris jurnal pada buku besar beserta saldo berjalannya
+type BarisBukuBesar struct {
+	Tanggal    string      `json:"tanggal"`
+	NoJurnal   string      `json:"noJurnal"`
+	Keterangan string      `json:"keterangan"`
+	Debit      models.Uang `json:"debit"`
+	Kredit     models.Uang `json:"kredit"`
+	Saldo      models.Uang `json:"saldo"`
 }
 
 // GenerateBukuBesar generates general ledger for an account
@@ -488,48 +884,46 @@ func (s *LaporanService) GenerateBukuBesar(idKoperasi, idAkun uuid.UUID, tanggal
 	}
 
 	// Get all transaction lines for this account within date range
-	type TransactionDetail struct {
-		Tanggal    string  `json:"tanggal"`
-		NoJurnal   string  `json:"noJurnal"`
-		Keterangan string  `json:"keterangan"`
-		Debit      float64 `json:"debit"`
-		Kredit     float64 `json:"kredit"`
-		Saldo      float64 `json:"saldo"`
-	}
-
-	var details []TransactionDetail
-	var runningBalance float64
+	var details []BarisBukuBesar
+	var runningBalance, saldoAwal models.Uang
+	kondisiUnit, argsUnit := kondisiUnitUsaha(s.idUnitUsaha)
 
-	// Get starting balance (before tanggalMulai)
+	// Get starting balance (sampai sehari sebelum tanggalMulai)
 	if tanggalMulai != "" {
-		runningBalance, _ = s.akunService.HitungSaldoAkun(idAkun, tanggalMulai)
+		mulai, parseErr := time.Parse("2006-01-02", tanggalMulai)
+		if parseErr != nil {
+			return nil, errors.New("format tanggal mulai tidak valid (gunakan YYYY-MM-DD)")
+		}
+		runningBalance, _ = s.hitungSaldoAkun(&akunModel, mulai.AddDate(0, 0, -1).Format("2006-01-02"))
+		saldoAwal = runningBalance
 	}
 
-	// Query transaction lines
-	query := s.db.Table("jurnal_detail").
-		Select("jurnal.tanggal, jurnal.no_jurnal, jurnal.keterangan, jurnal_detail.debit, jurnal_detail.kredit").
-		Joins("JOIN jurnal ON jurnal_detail.id_jurnal = jurnal.id").
-		Where("jurnal_detail.id_akun = ? AND jurnal.id_koperasi = ?", idAkun, idKoperasi)
+	// Query transaction lines (hanya jurnal yang sudah di-post)
+	query := s.db.Table("baris_transaksi").
+		Select("TO_CHAR(transaksi.tanggal_transaksi, 'YYYY-MM-DD') as tanggal, transaksi.nomor_jurnal as no_jurnal, COALESCE(NULLIF(baris_transaksi.keterangan, ''), transaksi.deskripsi) as keterangan, baris_transaksi.jumlah_debit as debit, baris_transaksi.jumlah_kredit as kredit").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("baris_transaksi.id_akun = ? AND transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idAkun, idKoperasi).
+		Where(kondisiUnit, argsUnit...)
 
 	if tanggalMulai != "" {
-		query = query.Where("jurnal.tanggal >= ?", tanggalMulai)
+		query = query.Where("transaksi.tanggal_transaksi >= ?", tanggalMulai)
 	}
 	if tanggalAkhir != "" {
-		query = query.Where("jurnal.tanggal <= ?", tanggalAkhir)
+		query = query.Where("transaksi.tanggal_transaksi <= ?", tanggalAkhir)
 	}
 
-	query = query.Order("jurnal.tanggal ASC, jurnal.created_at ASC")
+	query = query.Order("transaksi.tanggal_transaksi ASC, transaksi.nomor_jurnal ASC")
 
 	var rows []struct {
 		Tanggal    string
 		NoJurnal   string
 		Keterangan string
-		Debit      float64
-		Kredit     float64
+		Debit      models.Uang
+		Kredit     models.Uang
 	}
 
-	if err := query.Find(&rows).Error; err != nil {
-		return nil, err
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, errors.New("gagal mengambil buku besar")
 	}
 
 	// Process each transaction
@@ -541,7 +935,7 @@ func (s *LaporanService) GenerateBukuBesar(idKoperasi, idAkun uuid.UUID, tanggal
 			runningBalance += row.Kredit - row.Debit
 		}
 
-		details = append(details, TransactionDetail{
+		details = append(details, BarisBukuBesar{
 			Tanggal:    row.Tanggal,
 			NoJurnal:   row.NoJurnal,
 			Keterangan: row.Keterangan,
@@ -561,11 +955,36 @@ func (s *LaporanService) GenerateBukuBesar(idKoperasi, idAkun uuid.UUID, tanggal
 			"tanggalMulai": tanggalMulai,
 			"tanggalAkhir": tanggalAkhir,
 		},
+		"saldoAwal":  saldoAwal,
 		"transaksi":  details,
 		"saldoAkhir": runningBalance,
 	}, nil
 }
 
+// hitungSaldoAkun menghitung saldo akun sampai tanggal sesuai saldo normalnya, dibatasi
+// unit usaha laporan jika ada
+func (s *LaporanService) hitungSaldoAkun(akun *models.Akun, tanggal string) (models.Uang, error) {
+	mutasi, err := hitungTotalMutasiAkun(s.db, akun.IDKoperasi, &akun.ID, tanggal, s.idUnitUsaha)
+	if err != nil {
+		return 0, err
+	}
+
+	total := mutasi[akun.ID]
+	if akun.NormalSaldo == "DEBIT" {
+		return total.TotalDebit - total.TotalKredit, nil
+	}
+	return total.TotalKredit - total.TotalDebit, nil
+}
+
+// ItemNeracaSaldo adalah saldo satu akun pada neraca saldo
+type ItemNeracaSaldo struct {
+	KodeAkun    string      `json:"kodeAkun"`
+	NamaAkun    string      `json:"namaAkun"`
+	TipeAkun    string      `json:"tipeAkun"`
+	SaldoDebit  models.Uang `json:"saldoDebit"`
+	SaldoKredit models.Uang `json:"saldoKredit"`
+}
+
 // GenerateNeracaSaldo generates trial balance
 func (s *LaporanService) GenerateNeracaSaldo(idKoperasi uuid.UUID, tanggalPer string) (map[string]interface{}, error) {
 	// Get all active accounts
@@ -574,34 +993,39 @@ func (s *LaporanService) GenerateNeracaSaldo(idKoperasi uuid.UUID, tanggalPer st
 		return nil, err
 	}
 
-	type NeracaSaldoItem struct {
-		KodeAkun    string  `json:"kodeAkun"`
-		NamaAkun    string  `json:"namaAkun"`
-		TipeAkun    string  `json:"tipeAkun"`
-		SaldoDebit  float64 `json:"saldoDebit"`
-		SaldoKredit float64 `json:"saldoKredit"`
+	// Satu perhitungan untuk semua akun, bukan satu query per akun
+	mutasi, err := hitungTotalMutasiAkun(s.db, idKoperasi, nil, tanggalPer, s.idUnitUsaha)
+	if err != nil {
+		return nil, err
 	}
 
-	var items []NeracaSaldoItem
-	var totalDebit, totalKredit float64
+	var items []ItemNeracaSaldo
+	var totalDebit, totalKredit models.Uang
 
 	for _, akun := range akunList {
-		saldo, _ := s.akunService.HitungSaldoAkun(akun.ID, tanggalPer)
+		total := mutasi[akun.ID]
+		saldo := total.TotalKredit - total.TotalDebit
+		if akun.NormalSaldo == "DEBIT" {
+			saldo = total.TotalDebit - total.TotalKredit
+		}
 
-		item := NeracaSaldoItem{
+		item := ItemNeracaSaldo{
 			KodeAkun: akun.KodeAkun,
 			NamaAkun: akun.NamaAkun,
 			TipeAkun: string(akun.TipeAkun),
 		}
 
-		if saldo > 0 {
-			if akun.NormalSaldo == "DEBIT" {
-				item.SaldoDebit = saldo
-				totalDebit += saldo
-			} else {
-				item.SaldoKredit = saldo
-				totalKredit += saldo
-			}
+		// Saldo negatif (berlawanan dengan normal saldo) ditampilkan di sisi sebaliknya
+		saldoDebit := saldo
+		if akun.NormalSaldo != "DEBIT" {
+			saldoDebit = -saldo
+		}
+		if saldoDebit > 0 {
+			item.SaldoDebit = saldoDebit
+			totalDebit += saldoDebit
+		} else {
+			item.SaldoKredit = -saldoDebit
+			totalKredit += -saldoDebit
 		}
 
 		items = append(items, item)
@@ -30,6 +30,9 @@ func setupLaporanBenchmarkDB(b *testing.B) (*gorm.DB, uuid.UUID) {
 		&models.Akun{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 	)
 	if err != nil {
 		b.Fatalf("Failed to migrate test database: %v", err)
@@ -84,8 +87,9 @@ func seedAccounts(db *gorm.DB, idKoperasi uuid.UUID, count int) []models.Akun {
 	return accounts
 }
 
-// seedTransactions creates test transactions with line items
-func seedTransactions(db *gorm.DB, idKoperasi uuid.UUID, accounts []models.Akun, transactionCount int) {
+// seedTransactions creates test transactions with line items spread over three years,
+// then rebuilds the monthly balance snapshot because the rows bypass journal posting
+func seedTransactions(b *testing.B, db *gorm.DB, idKoperasi uuid.UUID, accounts []models.Akun, transactionCount int) {
 	// Clear existing transactions
 	db.Exec("DELETE FROM baris_transaksi WHERE id_transaksi IN (SELECT id FROM transaksi WHERE id_koperasi = ?)", idKoperasi)
 	db.Exec("DELETE FROM transaksi WHERE id_koperasi = ?", idKoperasi)
@@ -95,10 +99,10 @@ func seedTransactions(db *gorm.DB, idKoperasi uuid.UUID, accounts []models.Akun,
 		transactions[i] = models.Transaksi{
 			IDKoperasi:       idKoperasi,
 			NomorJurnal:      fmt.Sprintf("TRX-%04d", i+1),
-			TanggalTransaksi: time.Now().AddDate(0, 0, -(i % 30)), // Spread over 30 days
+			TanggalTransaksi: time.Now().AddDate(0, 0, -(i % (3 * 365))), // Spread over three years
 			Deskripsi:        fmt.Sprintf("Test Transaction %d", i+1),
-			TotalDebit:       100000,
-			TotalKredit:      100000,
+			TotalDebit:       models.Rupiah(100000),
+			TotalKredit:      models.Rupiah(100000),
 		}
 	}
 	db.CreateInBatches(transactions, 100)
@@ -111,7 +115,7 @@ func seedTransactions(db *gorm.DB, idKoperasi uuid.UUID, accounts []models.Akun,
 		lineItems = append(lineItems, models.BarisTransaksi{
 			IDTransaksi:  txn.ID,
 			IDAkun:       debitAccount.ID,
-			JumlahDebit:  100000,
+			JumlahDebit:  models.Rupiah(100000),
 			JumlahKredit: 0,
 		})
 
@@ -121,10 +125,14 @@ func seedTransactions(db *gorm.DB, idKoperasi uuid.UUID, accounts []models.Akun,
 			IDTransaksi:  txn.ID,
 			IDAkun:       creditAccount.ID,
 			JumlahDebit:  0,
-			JumlahKredit: 100000,
+			JumlahKredit: models.Rupiah(100000),
 		})
 	}
 	db.CreateInBatches(lineItems, 100)
+
+	if _, err := NewAkunService(db).BangunUlangSaldoBulanan(idKoperasi); err != nil {
+		b.Fatalf("Failed to rebuild monthly balances: %v", err)
+	}
 }
 
 // BenchmarkGenerateLaporanPosisiKeuangan_100Accounts benchmarks balance sheet with 100 accounts
@@ -135,7 +143,7 @@ func BenchmarkGenerateLaporanPosisiKeuangan_100Accounts(b *testing.B) {
 	}
 
 	accounts := seedAccounts(db, idKoperasi, 100)
-	seedTransactions(db, idKoperasi, accounts, 500)
+	seedTransactions(b, db, idKoperasi, accounts, 500)
 
 	akunService := NewAkunService(db)
 	service := NewLaporanService(db, akunService, nil, nil)
@@ -158,7 +166,7 @@ func BenchmarkGenerateLaporanPosisiKeuangan_500Accounts(b *testing.B) {
 	}
 
 	accounts := seedAccounts(db, idKoperasi, 500)
-	seedTransactions(db, idKoperasi, accounts, 2000)
+	seedTransactions(b, db, idKoperasi, accounts, 2000)
 
 	akunService := NewAkunService(db)
 	service := NewLaporanService(db, akunService, nil, nil)
@@ -180,7 +188,7 @@ func BenchmarkGenerateLaporanLabaRugi_100Accounts(b *testing.B) {
 	}
 
 	accounts := seedAccounts(db, idKoperasi, 100)
-	seedTransactions(db, idKoperasi, accounts, 500)
+	seedTransactions(b, db, idKoperasi, accounts, 500)
 
 	akunService := NewAkunService(db)
 	service := NewLaporanService(db, akunService, nil, nil)
@@ -206,7 +214,7 @@ func BenchmarkGenerateLaporanLabaRugi_500Accounts(b *testing.B) {
 	}
 
 	accounts := seedAccounts(db, idKoperasi, 500)
-	seedTransactions(db, idKoperasi, accounts, 2000)
+	seedTransactions(b, db, idKoperasi, accounts, 2000)
 
 	akunService := NewAkunService(db)
 	service := NewLaporanService(db, akunService, nil, nil)
@@ -222,3 +230,75 @@ func BenchmarkGenerateLaporanLabaRugi_500Accounts(b *testing.B) {
 		}
 	}
 }
+
+// BenchmarkGenerateLaporanPosisiKeuangan_MultiYear benchmarks balance sheet on three years of journals.
+// Compare with BenchmarkSaldoAkunTanpaSnapshot_MultiYear, which sums every journal line.
+func BenchmarkGenerateLaporanPosisiKeuangan_MultiYear(b *testing.B) {
+	db, idKoperasi := setupLaporanBenchmarkDB(b)
+	if db == nil {
+		return
+	}
+
+	accounts := seedAccounts(db, idKoperasi, 100)
+	seedTransactions(b, db, idKoperasi, accounts, 20000)
+
+	akunService := NewAkunService(db)
+	service := NewLaporanService(db, akunService, nil, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := service.GenerateLaporanPosisiKeuangan(idKoperasi, "")
+		if err != nil {
+			b.Fatalf("Error generating report: %v", err)
+		}
+	}
+}
+
+// BenchmarkGenerateNeracaSaldo_MultiYear benchmarks trial balance on three years of journals
+func BenchmarkGenerateNeracaSaldo_MultiYear(b *testing.B) {
+	db, idKoperasi := setupLaporanBenchmarkDB(b)
+	if db == nil {
+		return
+	}
+
+	accounts := seedAccounts(db, idKoperasi, 100)
+	seedTransactions(b, db, idKoperasi, accounts, 20000)
+
+	akunService := NewAkunService(db)
+	service := NewLaporanService(db, akunService, nil, nil)
+	tanggalPer := time.Now().Format("2006-01-02")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := service.GenerateNeracaSaldo(idKoperasi, tanggalPer)
+		if err != nil {
+			b.Fatalf("Error generating report: %v", err)
+		}
+	}
+}
+
+// BenchmarkSaldoAkunTanpaSnapshot_MultiYear is the baseline: per-account balances aggregated
+// from every journal line since the beginning, as the reports did before the snapshot
+func BenchmarkSaldoAkunTanpaSnapshot_MultiYear(b *testing.B) {
+	db, idKoperasi := setupLaporanBenchmarkDB(b)
+	if db == nil {
+		return
+	}
+
+	accounts := seedAccounts(db, idKoperasi, 100)
+	seedTransactions(b, db, idKoperasi, accounts, 20000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var saldo []totalMutasiAkun
+		err := db.Table("baris_transaksi").
+			Select("baris_transaksi.id_akun, COALESCE(SUM(baris_transaksi.jumlah_debit), 0) as total_debit, COALESCE(SUM(baris_transaksi.jumlah_kredit), 0) as total_kredit").
+			Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+			Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+			Group("baris_transaksi.id_akun").
+			Scan(&saldo).Error
+		if err != nil {
+			b.Fatalf("Error aggregating balances: %v", err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// namaTanpaUnitUsaha adalah nama kelompok baris jurnal yang tidak memiliki unit usaha
+const namaTanpaUnitUsaha = "Tanpa Unit Usaha"
+
+// LaporanPerUnitUsaha adalah satu laporan dalam penyajian yang dikelompokkan per unit usaha
+type LaporanPerUnitUsaha struct {
+	IDUnitUsaha *uuid.UUID  `json:"idUnitUsaha"` // Kosong untuk baris jurnal tanpa unit usaha
+	KodeUnit    string      `json:"kodeUnit"`
+	NamaUnit    string      `json:"namaUnit"`
+	Laporan     interface{} `json:"laporan"`
+}
+
+// KelompokkanPerUnitUsaha menjalankan generate sekali untuk setiap unit usaha koperasi (termasuk
+// yang nonaktif) dan sekali untuk baris jurnal tanpa unit usaha. generate menerima LaporanService
+// yang sudah dibatasi ke unit tersebut (lihat UntukUnitUsaha).
+func (s *LaporanService) KelompokkanPerUnitUsaha(idKoperasi uuid.UUID, generate func(*LaporanService) (interface{}, error)) ([]LaporanPerUnitUsaha, error) {
+	var unitList []models.UnitUsaha
+	if err := s.db.Where("id_koperasi = ?", idKoperasi).Order("kode_unit ASC").Find(&unitList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar unit usaha")
+	}
+
+	hasil := make([]LaporanPerUnitUsaha, 0, len(unitList)+1)
+	for _, unit := range unitList {
+		idUnitUsaha := unit.ID
+		laporan, err := generate(s.UntukUnitUsaha(&idUnitUsaha))
+		if err != nil {
+			return nil, err
+		}
+		hasil = append(hasil, LaporanPerUnitUsaha{
+			IDUnitUsaha: &idUnitUsaha,
+			KodeUnit:    unit.KodeUnit,
+			NamaUnit:    unit.NamaUnit,
+			Laporan:     laporan,
+		})
+	}
+
+	tanpaUnit := uuid.Nil
+	laporan, err := generate(s.UntukUnitUsaha(&tanpaUnit))
+	if err != nil {
+		return nil, err
+	}
+	hasil = append(hasil, LaporanPerUnitUsaha{NamaUnit: namaTanpaUnitUsaha, Laporan: laporan})
+
+	return hasil, nil
+}
+
+// LaporanKontribusiUnitUsaha menyajikan kontribusi setiap unit usaha terhadap SHU periode
+type LaporanKontribusiUnitUsaha struct {
+	PeriodeMulai    time.Time                 `json:"periodeMulai"`
+	PeriodeAkhir    time.Time                 `json:"periodeAkhir"`
+	UnitUsaha       []ItemKontribusiUnitUsaha `json:"unitUsaha"`
+	TotalPendapatan models.Uang               `json:"totalPendapatan"`
+	TotalBeban      models.Uang               `json:"totalBeban"`
+	TotalSHU        models.Uang               `json:"totalShu"` // Sama dengan LabaRugiBersih laporan laba rugi periode
+}
+
+// ItemKontribusiUnitUsaha adalah pendapatan, beban, dan SHU satu unit usaha
+type ItemKontribusiUnitUsaha struct {
+	IDUnitUsaha          *uuid.UUID  `json:"idUnitUsaha"` // Kosong untuk baris jurnal tanpa unit usaha
+	KodeUnit             string      `json:"kodeUnit"`
+	NamaUnit             string      `json:"namaUnit"`
+	TotalPendapatan      models.Uang `json:"totalPendapatan"`
+	TotalBeban           models.Uang `json:"totalBeban"`
+	SHU                  models.Uang `json:"shu"`
+	PersentaseKontribusi *float64    `json:"persentaseKontribusi"` // Persen dari total SHU, kosong jika total SHU nol
+}
+
+// GenerateKontribusiUnitUsaha menghitung pendapatan, beban, dan SHU setiap unit usaha selama periode
+// dengan aturan yang sama seperti GenerateLaporanLabaRugi (jurnal penutupan dikecualikan), sehingga
+// jumlah seluruh unit ditambah baris tanpa unit usaha sama dengan laba rugi bersih koperasi.
+// Unit nonaktif dan baris tanpa unit usaha hanya ditampilkan jika memiliki mutasi.
+func (s *LaporanService) GenerateKontribusiUnitUsaha(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir string) (*LaporanKontribusiUnitUsaha, error) {
+	periodeMulai, err := time.Parse("2006-01-02", tanggalMulai)
+	if err != nil {
+		return nil, errors.New("format tanggal mulai tidak valid")
+	}
+
+	periodeAkhir, err := time.Parse("2006-01-02", tanggalAkhir)
+	if err != nil {
+		return nil, errors.New("format tanggal akhir tidak valid")
+	}
+
+	type mutasiUnit struct {
+		IDUnitUsaha *uuid.UUID
+		TipeAkun    models.TipeAkun
+		NormalSaldo string
+		TotalDebit  models.Uang
+		TotalKredit models.Uang
+	}
+
+	var mutasiList []mutasiUnit
+	err = s.db.Table("baris_transaksi").
+		Select("baris_transaksi.id_unit_usaha, akun.tipe_akun, akun.normal_saldo, COALESCE(SUM(baris_transaksi.jumlah_debit), 0) as total_debit, COALESCE(SUM(baris_transaksi.jumlah_kredit), 0) as total_kredit").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Joins("JOIN akun ON akun.id = baris_transaksi.id_akun").
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+		Where("transaksi.tanggal_transaksi BETWEEN ? AND ? AND COALESCE(transaksi.tipe_transaksi, '') <> ?", tanggalMulai, tanggalAkhir, models.TipeTransaksiPenutupan).
+		Where("akun.tipe_akun IN ?", []models.TipeAkun{models.AkunPendapatan, models.AkunBeban}).
+		Group("baris_transaksi.id_unit_usaha, akun.tipe_akun, akun.normal_saldo").
+		Scan(&mutasiList).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung kontribusi unit usaha")
+	}
+
+	var unitList []models.UnitUsaha
+	if err := s.db.Where("id_koperasi = ?", idKoperasi).Order("kode_unit ASC").Find(&unitList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar unit usaha")
+	}
+
+	// Kunci uuid.Nil menampung baris jurnal tanpa unit usaha
+	itemByUnit := make(map[uuid.UUID]*ItemKontribusiUnitUsaha, len(unitList)+1)
+	for _, mutasi := range mutasiList {
+		kunci := uuid.Nil
+		if mutasi.IDUnitUsaha != nil {
+			kunci = *mutasi.IDUnitUsaha
+		}
+		item, ok := itemByUnit[kunci]
+		if !ok {
+			item = &ItemKontribusiUnitUsaha{}
+			itemByUnit[kunci] = item
+		}
+
+		saldo := mutasi.TotalKredit - mutasi.TotalDebit
+		if mutasi.NormalSaldo == "DEBIT" {
+			saldo = -saldo
+		}
+		if mutasi.TipeAkun == models.AkunPendapatan {
+			item.TotalPendapatan += saldo
+		} else {
+			item.TotalBeban += saldo
+		}
+	}
+
+	laporan := &LaporanKontribusiUnitUsaha{
+		PeriodeMulai: periodeMulai,
+		PeriodeAkhir: periodeAkhir,
+		UnitUsaha:    []ItemKontribusiUnitUsaha{},
+	}
+
+	tambahkan := func(item ItemKontribusiUnitUsaha) {
+		item.SHU = item.TotalPendapatan - item.TotalBeban
+		laporan.UnitUsaha = append(laporan.UnitUsaha, item)
+		laporan.TotalPendapatan += item.TotalPendapatan
+		laporan.TotalBeban += item.TotalBeban
+		laporan.TotalSHU += item.SHU
+	}
+
+	for _, unit := range unitList {
+		item, ok := itemByUnit[unit.ID]
+		if !ok {
+			if !unit.StatusAktif {
+				continue
+			}
+			item = &ItemKontribusiUnitUsaha{}
+		}
+		idUnitUsaha := unit.ID
+		item.IDUnitUsaha = &idUnitUsaha
+		item.KodeUnit = unit.KodeUnit
+		item.NamaUnit = unit.NamaUnit
+		tambahkan(*item)
+	}
+
+	if item, ok := itemByUnit[uuid.Nil]; ok {
+		item.NamaUnit = namaTanpaUnitUsaha
+		tambahkan(*item)
+	}
+
+	if laporan.TotalSHU != 0 {
+		for i := range laporan.UnitUsaha {
+			persen := bulatkanPersen(float64(laporan.UnitUsaha[i].SHU) / float64(laporan.TotalSHU) * 100)
+			laporan.UnitUsaha[i].PersentaseKontribusi = &persen
+		}
+	}
+
+	return laporan, nil
+}
@@ -415,7 +415,7 @@ func TestPerbaruiProduk_CrossTenantBlocked(t *testing.T) {
 		IDKoperasi:  koperasiA,
 		KodeProduk:  "P001",
 		NamaProduk:  "Product A",
-		Harga:       10000,
+		Harga:       models.Rupiah(10000),
 		Stok:        100,
 		StatusAktif: true,
 	}
@@ -463,7 +463,7 @@ func TestHapusProduk_CrossTenantBlocked(t *testing.T) {
 		IDKoperasi:  koperasiA,
 		KodeProduk:  "P001",
 		NamaProduk:  "Product A",
-		Harga:       10000,
+		Harga:       models.Rupiah(10000),
 		Stok:        100,
 		StatusAktif: true,
 	}
@@ -635,7 +635,7 @@ func TestMultiTenant_ComprehensiveSecurity(t *testing.T) {
 			IDKoperasi:  koopID,
 			KodeProduk:  fmt.Sprintf("P00%d", i+1),
 			NamaProduk:  fmt.Sprintf("Product %d", i+1),
-			Harga:       10000 * float64(i+1),
+			Harga:       models.Rupiah(10000).Kali(i + 1),
 			Stok:        100,
 			StatusAktif: true,
 		}
@@ -0,0 +1,536 @@
+package services
+
+import (
+	"bytes"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PajakService menangani pemotongan PPh 21/23, laporan pajak bulanan dan ekspor e-Faktur.
+// PPN penjualan dihitung oleh PenjualanService dan dijurnal bersama jurnal penjualan.
+type PajakService struct {
+	db               *gorm.DB
+	transaksiService *TransaksiService
+}
+
+// NewPajakService membuat instance baru PajakService
+func NewPajakService(db *gorm.DB, transaksiService *TransaksiService) *PajakService {
+	return &PajakService{
+		db:               db,
+		transaksiService: transaksiService,
+	}
+}
+
+const (
+	tarifPPh23Default     = 2.0 // Tarif PPh 23 atas sewa dan jasa
+	pengaliTanpaNPWPPPh21 = 1.2 // PPh 21: tarif 20% lebih tinggi tanpa NPWP
+	pengaliTanpaNPWPPPh23 = 2.0 // PPh 23: tarif 100% lebih tinggi tanpa NPWP
+	npwpKosongEFaktur     = "000000000000000"
+)
+
+// hitungPPN memisahkan DPP dan PPN dari nilai penjualan. Jika harga sudah termasuk PPN,
+// DPP = nilai × 100 / (100 + tarif) dan PPN adalah sisanya, sehingga DPP + PPN selalu sama
+// dengan nilai penjualan. Jika belum, DPP = nilai dan PPN ditambahkan di atasnya.
+func hitungPPN(nilai models.Uang, tarifPersen float64, termasukPPN bool) (dpp, ppn models.Uang) {
+	if termasukPPN {
+		basisPoin := int64(math.Round(tarifPersen * 100))
+		dpp = nilai.KaliPecahan(10000, 10000+basisPoin)
+		return dpp, nilai - dpp
+	}
+	return nilai, nilai.Persen(tarifPersen)
+}
+
+// hitungPemotonganPPh menghitung tarif efektif dan PPh yang dipotong dari jumlah bruto.
+// Penerima tanpa NPWP dikenai tarif lebih tinggi; PPh dibulatkan ke bawah ke rupiah penuh.
+func hitungPemotonganPPh(jenis models.JenisPPh, tarifPersen float64, adaNPWP bool, bruto models.Uang) (float64, models.Uang) {
+	tarif := tarifPersen
+	if !adaNPWP {
+		switch jenis {
+		case models.PPh21:
+			tarif *= pengaliTanpaNPWPPPh21
+		case models.PPh23:
+			tarif *= pengaliTanpaNPWPPPh23
+		}
+	}
+	tarif = math.Round(tarif*100) / 100
+
+	sen := int64(bruto) * int64(math.Round(tarif*100)) / 10000
+	return tarif, models.Uang(sen / models.SenPerRupiah * models.SenPerRupiah)
+}
+
+// normalisasiNPWP menghapus tanda baca pada NPWP, misalnya 01.234.567.8-901.000
+func normalisasiNPWP(npwp string) string {
+	return strings.NewReplacer(".", "", "-", "", " ", "").Replace(npwp)
+}
+
+// validasiNPWP memastikan NPWP berupa 15 digit atau 16 digit (NIK sebagai NPWP)
+func validasiNPWP(npwp, namaField string) error {
+	if len(npwp) != 15 && len(npwp) != 16 {
+		return fmt.Errorf("%s harus 15 atau 16 digit", namaField)
+	}
+	for _, ch := range npwp {
+		if ch < '0' || ch > '9' {
+			return fmt.Errorf("%s hanya boleh berisi angka", namaField)
+		}
+	}
+	return nil
+}
+
+// validasiKodePajak memastikan kode pajak produk dikenal
+func validasiKodePajak(validator *validasi.Validasi, kode models.KodePajak) error {
+	return validator.Enum(string(kode), "kode pajak",
+		[]string{string(models.KodePajakNonPPN), string(models.KodePajakPPN)})
+}
+
+// BuatPemotonganPPhRequest adalah struktur request untuk mencatat beban dengan PPh dipotong
+type BuatPemotonganPPhRequest struct {
+	JenisPPh         models.JenisPPh `json:"jenisPPh" binding:"required"`
+	TanggalTransaksi time.Time       `json:"tanggalTransaksi" binding:"required"`
+	NamaPenerima     string          `json:"namaPenerima" binding:"required"`
+	NPWPPenerima     string          `json:"npwpPenerima"` // Kosong: tarif lebih tinggi tanpa NPWP
+	Deskripsi        string          `json:"deskripsi"`
+	IDAkunBeban      uuid.UUID       `json:"idAkunBeban" binding:"required"`
+	IDAkunPembayaran uuid.UUID       `json:"idAkunPembayaran" binding:"required"` // Kas, bank, atau utang kepada penerima
+	JumlahBruto      models.Uang     `json:"jumlahBruto" binding:"required,gt=0"`
+	TarifPersen      float64         `json:"tarifPersen"` // Tarif dengan NPWP; wajib untuk PPh 21, default 2 untuk PPh 23
+	IDUnitUsaha      *uuid.UUID      `json:"idUnitUsaha"`
+}
+
+// BatalkanPemotonganPPhRequest adalah struktur request untuk membatalkan bukti potong
+type BatalkanPemotonganPPhRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// BuatPemotonganPPh mencatat beban yang dipotong PPh 21/23 beserta jurnalnya:
+//
+//	Debit  akun beban       (bruto)
+//	Kredit akun pembayaran  (netto)
+//	Kredit utang PPh 21/23  (PPh dipotong)
+//
+// Tarif PPh 21 diisi pengguna (misalnya tarif efektif bulanan); koperasi hanya menambahkan
+// tarif lebih tinggi bagi penerima tanpa NPWP.
+func (s *PajakService) BuatPemotonganPPh(idKoperasi, idPengguna uuid.UUID, req *BuatPemotonganPPhRequest) (*models.PemotonganPPh, error) {
+	validator := validasi.Baru()
+
+	if err := validator.Enum(string(req.JenisPPh), "jenis PPh",
+		[]string{string(models.PPh21), string(models.PPh23)}); err != nil {
+		return nil, err
+	}
+	if err := validator.TeksWajib(req.NamaPenerima, "nama penerima", 3, 255); err != nil {
+		return nil, err
+	}
+	if err := validator.TeksOpsional(req.Deskripsi, "deskripsi", 200); err != nil {
+		return nil, err
+	}
+	if err := validator.TanggalTransaksi(req.TanggalTransaksi); err != nil {
+		return nil, err
+	}
+	if req.JumlahBruto <= 0 {
+		return nil, errors.New("jumlah bruto harus lebih dari 0")
+	}
+
+	npwp := normalisasiNPWP(req.NPWPPenerima)
+	if npwp != "" {
+		if err := validasiNPWP(npwp, "NPWP penerima"); err != nil {
+			return nil, err
+		}
+	}
+
+	tarif := req.TarifPersen
+	if tarif == 0 && req.JenisPPh == models.PPh23 {
+		tarif = tarifPPh23Default
+	}
+	if tarif <= 0 {
+		return nil, errors.New("tarif PPh harus lebih dari 0")
+	}
+	if err := validator.Persentase(tarif, "tarif PPh"); err != nil {
+		return nil, err
+	}
+
+	tarifEfektif, pph := hitungPemotonganPPh(req.JenisPPh, tarif, npwp != "", req.JumlahBruto)
+	if pph <= 0 {
+		return nil, errors.New("PPh yang dipotong kurang dari Rp 1")
+	}
+
+	pemotongan := &models.PemotonganPPh{
+		IDKoperasi:       idKoperasi,
+		JenisPPh:         req.JenisPPh,
+		TanggalTransaksi: req.TanggalTransaksi,
+		NamaPenerima:     req.NamaPenerima,
+		NPWPPenerima:     npwp,
+		Deskripsi:        req.Deskripsi,
+		IDAkunBeban:      req.IDAkunBeban,
+		IDAkunPembayaran: req.IDAkunPembayaran,
+		IDUnitUsaha:      req.IDUnitUsaha,
+		JumlahBruto:      req.JumlahBruto,
+		TarifPersen:      tarifEfektif,
+		JumlahPPh:        pph,
+		JumlahNetto:      req.JumlahBruto - pph,
+		DibuatOleh:       idPengguna,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := validasiUnitUsahaAktifWithTx(tx, idKoperasi, req.IDUnitUsaha); err != nil {
+			return err
+		}
+		if _, err := akunAsetWithTx(tx, idKoperasi, &req.IDAkunBeban, "", models.AkunBeban, "beban"); err != nil {
+			return err
+		}
+		akunPosting, err := akunPostingWithTx(tx, idKoperasi, peristiwaPPh[req.JenisPPh])
+		if err != nil {
+			return err
+		}
+		akunUtangPPh := akunPosting[models.PeranAkunUtangPPh]
+
+		keterangan := fmt.Sprintf("Pemotongan %s - %s", namaJenisPPh(req.JenisPPh), req.NamaPenerima)
+		deskripsi := keterangan
+		if req.Deskripsi != "" {
+			deskripsi = req.Deskripsi + " (" + keterangan + ")"
+		}
+		transaksi, jurnalErr := s.transaksiService.BuatTransaksiWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+			TanggalTransaksi: req.TanggalTransaksi,
+			Deskripsi:        deskripsi,
+			TipeTransaksi:    models.TipeTransaksiPajak,
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: req.IDAkunBeban, JumlahDebit: pemotongan.JumlahBruto, Keterangan: keterangan, IDUnitUsaha: req.IDUnitUsaha},
+				{IDAkun: req.IDAkunPembayaran, JumlahKredit: pemotongan.JumlahNetto, Keterangan: keterangan, IDUnitUsaha: req.IDUnitUsaha},
+				{IDAkun: akunUtangPPh.ID, JumlahKredit: pemotongan.JumlahPPh, Keterangan: keterangan, IDUnitUsaha: req.IDUnitUsaha},
+			},
+		})
+		if jurnalErr != nil {
+			return fmt.Errorf("gagal posting jurnal pemotongan PPh: %w", jurnalErr)
+		}
+		pemotongan.IDTransaksi = transaksi.ID
+
+		if createErr := tx.Create(pemotongan).Error; createErr != nil {
+			return errors.New("gagal mencatat pemotongan PPh")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pemotongan, nil
+}
+
+// namaJenisPPh mengembalikan nama jenis PPh untuk keterangan jurnal, misalnya "PPh 23"
+func namaJenisPPh(jenis models.JenisPPh) string {
+	return "PPh " + strings.TrimPrefix(string(jenis), "PPH")
+}
+
+// DapatkanSemuaPemotonganPPh mengambil bukti potong PPh; tahun, bulan dan jenis bersifat opsional
+func (s *PajakService) DapatkanSemuaPemotonganPPh(idKoperasi uuid.UUID, tahun, bulan int, jenis string) ([]models.PemotonganPPh, error) {
+	query := s.db.Where("id_koperasi = ?", idKoperasi)
+	if tahun > 0 {
+		awal := time.Date(tahun, 1, 1, 0, 0, 0, 0, time.UTC)
+		akhir := awal.AddDate(1, 0, 0)
+		if bulan > 0 {
+			awal = time.Date(tahun, time.Month(bulan), 1, 0, 0, 0, 0, time.UTC)
+			akhir = awal.AddDate(0, 1, 0)
+		}
+		query = query.Where("tanggal_transaksi >= ? AND tanggal_transaksi < ?", awal.Format("2006-01-02"), akhir.Format("2006-01-02"))
+	}
+	if jenis != "" {
+		query = query.Where("jenis_pph = ?", jenis)
+	}
+
+	var daftar []models.PemotonganPPh
+	if err := query.Order("tanggal_transaksi ASC, tanggal_dibuat ASC").Find(&daftar).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar pemotongan PPh")
+	}
+	return daftar, nil
+}
+
+// BatalkanPemotonganPPh membatalkan bukti potong dengan membalik jurnalnya tertanggal hari ini.
+// Bukti potong tetap tersimpan dan tidak lagi dihitung dalam laporan pajak.
+func (s *PajakService) BatalkanPemotonganPPh(idKoperasi, idPengguna, id uuid.UUID, req *BatalkanPemotonganPPhRequest) (*models.PemotonganPPh, error) {
+	if err := validasi.Baru().TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	var pemotongan models.PemotonganPPh
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&pemotongan).Error
+		if lockErr != nil {
+			if errors.Is(lockErr, gorm.ErrRecordNotFound) {
+				return errors.New("pemotongan PPh tidak ditemukan")
+			}
+			return errors.New("gagal mengambil pemotongan PPh")
+		}
+		if pemotongan.Dibatalkan {
+			return errors.New("pemotongan PPh sudah dibatalkan")
+		}
+
+		if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, pemotongan.IDTransaksi,
+			hariIniUTC(), req.Alasan, models.TipeTransaksiPajak); balikErr != nil {
+			return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+		}
+
+		sekarang := time.Now()
+		pemotongan.Dibatalkan = true
+		pemotongan.TanggalDibatalkan = &sekarang
+		pemotongan.DibatalkanOleh = &idPengguna
+		pemotongan.AlasanPembatalan = req.Alasan
+		if saveErr := tx.Save(&pemotongan).Error; saveErr != nil {
+			return errors.New("gagal membatalkan pemotongan PPh")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pemotongan, nil
+}
+
+// RingkasanPPNKeluaran adalah rekap PPN yang dipungut atas penjualan dalam satu masa pajak
+type RingkasanPPNKeluaran struct {
+	JumlahFaktur    int64       `json:"jumlahFaktur"` // Penjualan yang memungut PPN
+	TotalDPP        models.Uang `json:"totalDPP"`
+	TotalPPN        models.Uang `json:"totalPPN"`
+	PenjualanNonPPN models.Uang `json:"penjualanNonPPN"` // Nilai item NON_PPN
+}
+
+// RingkasanPPh adalah rekap bukti potong satu jenis PPh dalam satu masa pajak
+type RingkasanPPh struct {
+	JenisPPh          models.JenisPPh `json:"jenisPPh"`
+	JumlahBuktiPotong int64           `json:"jumlahBuktiPotong"`
+	JumlahBruto       models.Uang     `json:"jumlahBruto"`
+	JumlahPPh         models.Uang     `json:"jumlahPPh"`
+}
+
+// LaporanPajakBulanan merangkum PPN keluaran dan PPh yang dipotong dalam satu masa pajak,
+// sebagai dasar penyetoran dan pelaporan SPT Masa
+type LaporanPajakBulanan struct {
+	Tahun       int                    `json:"tahun"`
+	Bulan       int                    `json:"bulan"`
+	NPWP        string                 `json:"npwp"`
+	PKP         bool                   `json:"pkp"`
+	TarifPPN    float64                `json:"tarifPPN"`
+	PPNKeluaran RingkasanPPNKeluaran   `json:"ppnKeluaran"`
+	PPh         []RingkasanPPh         `json:"pph"`
+	TotalPPh    models.Uang            `json:"totalPPh"`
+	BuktiPotong []models.PemotonganPPh `json:"buktiPotong"`
+}
+
+// rentangMasaPajak mengembalikan tanggal awal (inklusif) dan akhir (eksklusif) satu masa pajak
+func rentangMasaPajak(tahun, bulan int) (string, string, error) {
+	if tahun < 2000 || tahun > 9999 || bulan < 1 || bulan > 12 {
+		return "", "", errors.New("masa pajak tidak valid")
+	}
+	awal := time.Date(tahun, time.Month(bulan), 1, 0, 0, 0, 0, time.UTC)
+	return awal.Format("2006-01-02"), awal.AddDate(0, 1, 0).Format("2006-01-02"), nil
+}
+
+// LaporanPajakBulanan menyusun laporan pajak satu masa. Penjualan dan bukti potong yang
+// dibatalkan tidak dihitung.
+func (s *PajakService) LaporanPajakBulanan(idKoperasi uuid.UUID, tahun, bulan int) (*LaporanPajakBulanan, error) {
+	awal, akhir, err := rentangMasaPajak(tahun, bulan)
+	if err != nil {
+		return nil, err
+	}
+
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", idKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+
+	laporan := &LaporanPajakBulanan{
+		Tahun:    tahun,
+		Bulan:    bulan,
+		NPWP:     koperasi.NPWP,
+		PKP:      koperasi.PKP,
+		TarifPPN: koperasi.TarifPPN,
+	}
+
+	err = s.db.Model(&models.ItemPenjualan{}).
+		Select(`COUNT(DISTINCT CASE WHEN item_penjualan.ppn > 0 THEN item_penjualan.id_penjualan END) AS jumlah_faktur,
+			COALESCE(SUM(CASE WHEN item_penjualan.kode_pajak = ? THEN item_penjualan.dpp ELSE 0 END), 0) AS total_dpp,
+			COALESCE(SUM(CASE WHEN item_penjualan.kode_pajak = ? THEN item_penjualan.ppn ELSE 0 END), 0) AS total_ppn,
+			COALESCE(SUM(CASE WHEN item_penjualan.kode_pajak = ? THEN 0 ELSE item_penjualan.subtotal END), 0) AS penjualan_non_ppn`,
+			models.KodePajakPPN, models.KodePajakPPN, models.KodePajakPPN).
+		Joins("JOIN penjualan ON penjualan.id = item_penjualan.id_penjualan AND penjualan.tanggal_dihapus IS NULL").
+		Where("penjualan.id_koperasi = ? AND penjualan.dibatalkan = ?", idKoperasi, false).
+		Where("penjualan.tanggal_penjualan >= ? AND penjualan.tanggal_penjualan < ?", awal, akhir).
+		Scan(&laporan.PPNKeluaran).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung PPN keluaran")
+	}
+
+	err = s.db.Model(&models.PemotonganPPh{}).
+		Select("jenis_pph, COUNT(*) AS jumlah_bukti_potong, SUM(jumlah_bruto) AS jumlah_bruto, SUM(jumlah_pph) AS jumlah_pph").
+		Where("id_koperasi = ? AND dibatalkan = ?", idKoperasi, false).
+		Where("tanggal_transaksi >= ? AND tanggal_transaksi < ?", awal, akhir).
+		Group("jenis_pph").
+		Order("jenis_pph ASC").
+		Scan(&laporan.PPh).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung PPh yang dipotong")
+	}
+	for _, ringkasan := range laporan.PPh {
+		laporan.TotalPPh += ringkasan.JumlahPPh
+	}
+
+	err = s.db.Where("id_koperasi = ? AND dibatalkan = ?", idKoperasi, false).
+		Where("tanggal_transaksi >= ? AND tanggal_transaksi < ?", awal, akhir).
+		Order("jenis_pph ASC, tanggal_transaksi ASC").
+		Find(&laporan.BuktiPotong).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil bukti potong PPh")
+	}
+
+	return laporan, nil
+}
+
+// EksporEFaktur menghasilkan berkas CSV impor e-Faktur berisi satu faktur keluaran untuk
+// setiap penjualan yang memungut PPN dalam masa pajak. nomorFakturAwal (13 digit nomor seri
+// faktur pajak) bersifat opsional; jika diisi, nomor faktur berikutnya dinaikkan berurutan.
+func (s *PajakService) EksporEFaktur(idKoperasi uuid.UUID, tahun, bulan int, nomorFakturAwal string) ([]byte, error) {
+	awal, akhir, err := rentangMasaPajak(tahun, bulan)
+	if err != nil {
+		return nil, err
+	}
+
+	var nomorAwal int64
+	if nomorFakturAwal = normalisasiNPWP(nomorFakturAwal); nomorFakturAwal != "" {
+		if len(nomorFakturAwal) != 13 {
+			return nil, errors.New("nomor faktur awal harus 13 digit")
+		}
+		if nomorAwal, err = strconv.ParseInt(nomorFakturAwal, 10, 64); err != nil {
+			return nil, errors.New("nomor faktur awal hanya boleh berisi angka")
+		}
+	}
+
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", idKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+	if !koperasi.PKP {
+		return nil, errors.New("koperasi belum berstatus PKP")
+	}
+
+	var penjualanList []models.Penjualan
+	err = s.db.Preload("ItemPenjualan", "kode_pajak = ? AND ppn > 0", models.KodePajakPPN).
+		Preload("ItemPenjualan.Produk").
+		Preload("Anggota").
+		Where("id_koperasi = ? AND dibatalkan = ? AND total_ppn > 0", idKoperasi, false).
+		Where("tanggal_penjualan >= ? AND tanggal_penjualan < ?", awal, akhir).
+		Order("tanggal_penjualan ASC, nomor_penjualan ASC").
+		Find(&penjualanList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil penjualan kena PPN")
+	}
+
+	return tulisEFaktur(penjualanList, nomorAwal)
+}
+
+// tulisEFaktur menulis CSV impor faktur keluaran e-Faktur: tiga baris judul (FK, LT, OF),
+// lalu baris FK untuk setiap faktur diikuti baris OF untuk setiap barangnya.
+// Nilai rupiah dibulatkan ke bawah; total FK adalah jumlah baris OF-nya.
+func tulisEFaktur(penjualanList []models.Penjualan, nomorAwal int64) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	judul := [][]string{
+		{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR",
+			"NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "ID_KETERANGAN_TAMBAHAN",
+			"FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI", "KODE_DOKUMEN_PENDUKUNG"},
+		{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN", "KABUPATEN",
+			"PROPINSI", "KODE_POS", "NOMOR_TELEPON"},
+		{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON", "DPP", "PPN",
+			"TARIF_PPNBM", "PPNBM"},
+	}
+	if err := w.WriteAll(judul); err != nil {
+		return nil, errors.New("gagal menulis berkas e-Faktur")
+	}
+
+	for i, penjualan := range penjualanList {
+		var barisOF [][]string
+		var totalDPP, totalPPN int64
+		for _, item := range penjualan.ItemPenjualan {
+			dpp, ppn := rupiahBawah(item.DPP), rupiahBawah(item.PPN)
+			totalDPP += dpp
+			totalPPN += ppn
+			barisOF = append(barisOF, []string{
+				"OF",
+				item.Produk.KodeProduk,
+				item.NamaProduk,
+				models.Uang(int64(item.DPP) / int64(item.Kuantitas)).String(),
+				strconv.Itoa(item.Kuantitas),
+				strconv.FormatInt(dpp, 10),
+				"0",
+				strconv.FormatInt(dpp, 10),
+				strconv.FormatInt(ppn, 10),
+				"0",
+				"0",
+			})
+		}
+
+		nomorFaktur := ""
+		if nomorAwal > 0 {
+			nomorFaktur = fmt.Sprintf("%013d", nomorAwal+int64(i))
+		}
+		nama, alamat := pembeliEFaktur(penjualan.Anggota)
+		fk := []string{
+			"FK",
+			"01",
+			"0",
+			nomorFaktur,
+			strconv.Itoa(int(penjualan.TanggalPenjualan.Month())),
+			strconv.Itoa(penjualan.TanggalPenjualan.Year()),
+			penjualan.TanggalPenjualan.Format("02/01/2006"),
+			npwpKosongEFaktur,
+			nama,
+			alamat,
+			strconv.FormatInt(totalDPP, 10),
+			strconv.FormatInt(totalPPN, 10),
+			"0", "", "0", "0", "0", "0",
+			penjualan.NomorPenjualan,
+			"",
+		}
+		if err := w.Write(fk); err != nil {
+			return nil, errors.New("gagal menulis berkas e-Faktur")
+		}
+		if err := w.WriteAll(barisOF); err != nil {
+			return nil, errors.New("gagal menulis berkas e-Faktur")
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, errors.New("gagal menulis berkas e-Faktur")
+	}
+	return buf.Bytes(), nil
+}
+
+// pembeliEFaktur mengisi identitas pembeli faktur. Anggota dengan NIK memakai format
+// "NIK#NIK#NAMA#Nama" dengan NPWP nol; pembeli lain dicatat sebagai pembeli eceran.
+func pembeliEFaktur(anggota *models.Anggota) (nama, alamat string) {
+	if anggota == nil || len(anggota.NIK) != 16 {
+		return "PEMBELI ECERAN", "-"
+	}
+	alamat = strings.TrimSpace(anggota.Alamat)
+	if alamat == "" {
+		alamat = "-"
+	}
+	return anggota.NIK + "#NIK#NAMA#" + anggota.NamaLengkap, alamat
+}
+
+// rupiahBawah membulatkan nilai ke bawah ke rupiah penuh
+func rupiahBawah(u models.Uang) int64 {
+	return int64(u) / models.SenPerRupiah
+}
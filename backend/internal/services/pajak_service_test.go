@@ -0,0 +1,250 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHitungPPN(t *testing.T) {
+	dpp, ppn := hitungPPN(models.Rupiah(100000), 11, false)
+	assert.Equal(t, models.Rupiah(100000), dpp)
+	assert.Equal(t, models.Rupiah(11000), ppn)
+
+	dpp, ppn = hitungPPN(models.Rupiah(111000), 11, true)
+	assert.Equal(t, models.Rupiah(100000), dpp)
+	assert.Equal(t, models.Rupiah(11000), ppn)
+
+	// Harga termasuk PPN yang tidak habis dibagi: DPP dibulatkan, PPN menyerap sisanya
+	dpp, ppn = hitungPPN(models.Rupiah(10000), 11, true)
+	assert.Equal(t, models.Uang(900901), dpp)
+	assert.Equal(t, models.Uang(99099), ppn)
+	assert.Equal(t, models.Rupiah(10000), dpp+ppn)
+}
+
+func TestHitungPemotonganPPh(t *testing.T) {
+	tarif, pph := hitungPemotonganPPh(models.PPh23, 2, true, models.Rupiah(1000000))
+	assert.Equal(t, 2.0, tarif)
+	assert.Equal(t, models.Rupiah(20000), pph)
+
+	// Tanpa NPWP: PPh 23 dua kali lipat, PPh 21 20% lebih tinggi
+	tarif, pph = hitungPemotonganPPh(models.PPh23, 2, false, models.Rupiah(1000000))
+	assert.Equal(t, 4.0, tarif)
+	assert.Equal(t, models.Rupiah(40000), pph)
+
+	tarif, pph = hitungPemotonganPPh(models.PPh21, 5, false, models.Uang(123456789))
+	assert.Equal(t, 6.0, tarif)
+	assert.Equal(t, models.Rupiah(74074), pph, "PPh dibulatkan ke bawah ke rupiah penuh")
+}
+
+func TestValidasiNPWP(t *testing.T) {
+	npwp := normalisasiNPWP("01.234.567.8-901.000")
+	assert.Equal(t, "012345678901000", npwp)
+	assert.NoError(t, validasiNPWP(npwp, "NPWP"))
+	assert.NoError(t, validasiNPWP("3201234567890001", "NPWP"))
+	assert.Error(t, validasiNPWP("12345", "NPWP"))
+	assert.Error(t, validasiNPWP("01234567890100A", "NPWP"))
+}
+
+func TestBarisJurnalPenjualan_PPN(t *testing.T) {
+	idKas, idPenjualan, idHPP, idPersediaan, idPPN := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	items := []models.ItemPenjualan{
+		{Kuantitas: 1, HargaSatuan: models.Rupiah(111000), KodePajak: models.KodePajakPPN, DPP: models.Rupiah(100000),
+			PPN: models.Rupiah(11000), Produk: models.Produk{HargaBeli: models.Rupiah(80000)}},
+		{Kuantitas: 2, HargaSatuan: models.Rupiah(2500), KodePajak: models.KodePajakNonPPN},
+	}
+
+	baris := barisJurnalPenjualan(items, idKas, idPenjualan, idHPP, idPersediaan, idPPN)
+
+	// Kas, penjualan, PPN keluaran, HPP, persediaan
+	require.Len(t, baris, 5)
+	assert.Equal(t, models.Rupiah(116000), baris[0].JumlahDebit)
+	assert.Equal(t, models.Rupiah(105000), baris[1].JumlahKredit, "pendapatan item kena PPN sebesar DPP")
+	assert.Equal(t, idPPN, baris[2].IDAkun)
+	assert.Equal(t, models.Rupiah(11000), baris[2].JumlahKredit)
+	assert.Equal(t, models.Rupiah(80000), baris[3].JumlahDebit)
+}
+
+func TestTulisEFaktur(t *testing.T) {
+	tanggal := time.Date(2025, 3, 7, 10, 0, 0, 0, time.UTC)
+	penjualanList := []models.Penjualan{
+		{
+			NomorPenjualan:   "POS-20250307-0001",
+			TanggalPenjualan: tanggal,
+			Anggota:          &models.Anggota{NIK: "3201234567890001", NamaLengkap: "Siti Aminah", Alamat: "Jl. Mawar 1"},
+			ItemPenjualan: []models.ItemPenjualan{
+				{NamaProduk: "Beras 5kg", Kuantitas: 3, DPP: models.Uang(20000050), PPN: models.Uang(2200006),
+					Produk: models.Produk{KodeProduk: "BRS-5"}},
+			},
+		},
+		{
+			NomorPenjualan:   "POS-20250307-0002",
+			TanggalPenjualan: tanggal,
+			ItemPenjualan: []models.ItemPenjualan{
+				{NamaProduk: "Minyak, 2L", Kuantitas: 1, DPP: models.Rupiah(30000), PPN: models.Rupiah(3300),
+					Produk: models.Produk{KodeProduk: "MYK-2"}},
+			},
+		},
+	}
+
+	data, err := tulisEFaktur(penjualanList, 101250000009)
+	require.NoError(t, err)
+
+	baris := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, baris, 7)
+	assert.True(t, strings.HasPrefix(baris[0], "FK,KD_JENIS_TRANSAKSI,"))
+	assert.True(t, strings.HasPrefix(baris[1], "LT,"))
+	assert.True(t, strings.HasPrefix(baris[2], "OF,"))
+
+	assert.Equal(t, "FK,01,0,0101250000009,3,2025,07/03/2025,000000000000000,3201234567890001#NIK#NAMA#Siti Aminah,"+
+		"Jl. Mawar 1,200000,22000,0,,0,0,0,0,POS-20250307-0001,", baris[3])
+	assert.Equal(t, "OF,BRS-5,Beras 5kg,66666.83,3,200000,0,200000,22000,0,0", baris[4])
+	assert.Equal(t, "FK,01,0,0101250000010,3,2025,07/03/2025,000000000000000,PEMBELI ECERAN,"+
+		"-,30000,3300,0,,0,0,0,0,POS-20250307-0002,", baris[5])
+	assert.Equal(t, `OF,MYK-2,"Minyak, 2L",30000.00,1,30000,0,30000,3300,0,0`, baris[6])
+}
+
+func TestPajakService(t *testing.T) {
+	db := setupPenjualanTestDB(t)
+	if db == nil {
+		return
+	}
+	require.NoError(t, db.AutoMigrate(&models.UnitUsaha{}, &models.PemotonganPPh{}))
+
+	koperasi := &models.Koperasi{
+		NamaKoperasi: "Test Pajak Koperasi",
+		NPWP:         "012345678901000",
+		PKP:          true,
+		TarifPPN:     11,
+	}
+	require.NoError(t, db.Create(koperasi).Error)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	bebanGaji, _ := akunService.DapatkanAkunByKode(koperasi.ID, "5101")
+	ppnKeluaran, _ := akunService.DapatkanAkunByKode(koperasi.ID, "2111")
+	utangPPh21, _ := akunService.DapatkanAkunByKode(koperasi.ID, "2112")
+
+	transaksiService := NewTransaksiService(db)
+	produkService := NewProdukService(db)
+	penjualanService := NewPenjualanService(db, produkService, transaksiService)
+	pajakService := NewPajakService(db, transaksiService)
+	kasir := buatPenggunaTest(t, db, koperasi.ID, models.PeranKasir)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	produkPPN, err := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
+		KodeProduk: "PPN-01", NamaProduk: "Sabun Cuci", Harga: models.Rupiah(20000), Stok: 10, KodePajak: models.KodePajakPPN,
+	})
+	require.NoError(t, err)
+	produkBebas, err := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
+		KodeProduk: "NON-01", NamaProduk: "Beras Curah", Harga: models.Rupiah(12000), Stok: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.KodePajakNonPPN, produkBebas.KodePajak)
+
+	// PPN di luar harga menambah total belanja
+	penjualan, err := penjualanService.ProsesPenjualan(koperasi.ID, kasir, &ProsesPenjualanRequest{
+		Items: []ItemPenjualanRequest{
+			{IDProduk: produkPPN.ID, Kuantitas: 2, HargaSatuan: models.Rupiah(20000)},
+			{IDProduk: produkBebas.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(12000)},
+		},
+		JumlahBayar: models.Rupiah(60000),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(40000), penjualan.TotalDPP)
+	assert.Equal(t, models.Rupiah(4400), penjualan.TotalPPN)
+	assert.Equal(t, models.Rupiah(56400), penjualan.TotalBelanja)
+	assert.Equal(t, models.Rupiah(3600), penjualan.Kembalian)
+
+	var saldoPPN models.Uang
+	db.Model(&models.BarisTransaksi{}).Select("COALESCE(SUM(jumlah_kredit), 0)").
+		Where("id_akun = ?", ppnKeluaran.ID).Scan(&saldoPPN)
+	assert.Equal(t, models.Rupiah(4400), saldoPPN)
+
+	t.Run("pemotongan PPh 21 tanpa NPWP", func(t *testing.T) {
+		pemotongan, err := pajakService.BuatPemotonganPPh(koperasi.ID, admin, &BuatPemotonganPPhRequest{
+			JenisPPh:         models.PPh21,
+			TanggalTransaksi: time.Now(),
+			NamaPenerima:     "Budi Santoso",
+			IDAkunBeban:      bebanGaji.ID,
+			IDAkunPembayaran: kas.ID,
+			JumlahBruto:      models.Rupiah(1000000),
+			TarifPersen:      5,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 6.0, pemotongan.TarifPersen)
+		assert.Equal(t, models.Rupiah(60000), pemotongan.JumlahPPh)
+		assert.Equal(t, models.Rupiah(940000), pemotongan.JumlahNetto)
+
+		var baris []models.BarisTransaksi
+		require.NoError(t, db.Where("id_transaksi = ?", pemotongan.IDTransaksi).Find(&baris).Error)
+		require.Len(t, baris, 3)
+		kreditUtang := models.Uang(0)
+		for _, b := range baris {
+			if b.IDAkun == utangPPh21.ID {
+				kreditUtang += b.JumlahKredit
+			}
+		}
+		assert.Equal(t, models.Rupiah(60000), kreditUtang)
+
+		// PPh 21 tanpa tarif ditolak, akun pembayaran tidak boleh dipakai sebagai akun beban
+		_, err = pajakService.BuatPemotonganPPh(koperasi.ID, admin, &BuatPemotonganPPhRequest{
+			JenisPPh: models.PPh21, TanggalTransaksi: time.Now(), NamaPenerima: "Budi Santoso",
+			IDAkunBeban: bebanGaji.ID, IDAkunPembayaran: kas.ID, JumlahBruto: models.Rupiah(1000000),
+		})
+		assert.Error(t, err)
+		_, err = pajakService.BuatPemotonganPPh(koperasi.ID, admin, &BuatPemotonganPPhRequest{
+			JenisPPh: models.PPh23, TanggalTransaksi: time.Now(), NamaPenerima: "CV Jasa Bersih",
+			IDAkunBeban: kas.ID, IDAkunPembayaran: kas.ID, JumlahBruto: models.Rupiah(1000000),
+		})
+		assert.Error(t, err)
+
+		// Jurnal pemotongan hanya dapat dibalik melalui pembatalan bukti potong
+		_, err = transaksiService.BalikTransaksi(pemotongan.IDTransaksi, koperasi.ID, admin, &BalikTransaksiRequest{Alasan: "Salah input"})
+		assert.Error(t, err)
+	})
+
+	t.Run("laporan bulanan dan e-Faktur", func(t *testing.T) {
+		sekarang := time.Now()
+		laporan, err := pajakService.LaporanPajakBulanan(koperasi.ID, sekarang.Year(), int(sekarang.Month()))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), laporan.PPNKeluaran.JumlahFaktur)
+		assert.Equal(t, models.Rupiah(4400), laporan.PPNKeluaran.TotalPPN)
+		assert.Equal(t, models.Rupiah(12000), laporan.PPNKeluaran.PenjualanNonPPN)
+		require.Len(t, laporan.PPh, 1)
+		assert.Equal(t, models.Rupiah(60000), laporan.TotalPPh)
+
+		data, err := pajakService.EksporEFaktur(koperasi.ID, sekarang.Year(), int(sekarang.Month()), "")
+		require.NoError(t, err)
+		assert.Contains(t, string(data), ",PEMBELI ECERAN,-,40000,4400,")
+		assert.Contains(t, string(data), "OF,PPN-01,Sabun Cuci,20000.00,2,40000,0,40000,4400,0,0")
+
+		_, err = pajakService.EksporEFaktur(koperasi.ID, sekarang.Year(), int(sekarang.Month()), "123")
+		assert.Error(t, err)
+	})
+
+	t.Run("pembatalan bukti potong", func(t *testing.T) {
+		daftar, err := pajakService.DapatkanSemuaPemotonganPPh(koperasi.ID, 0, 0, string(models.PPh21))
+		require.NoError(t, err)
+		require.Len(t, daftar, 1)
+
+		_, err = pajakService.BatalkanPemotonganPPh(koperasi.ID, admin, daftar[0].ID, &BatalkanPemotonganPPhRequest{Alasan: "Honor batal dibayar"})
+		require.NoError(t, err)
+		_, err = pajakService.BatalkanPemotonganPPh(koperasi.ID, admin, daftar[0].ID, &BatalkanPemotonganPPhRequest{Alasan: "Honor batal dibayar"})
+		assert.Error(t, err)
+
+		sekarang := time.Now()
+		laporan, err := pajakService.LaporanPajakBulanan(koperasi.ID, sekarang.Year(), int(sekarang.Month()))
+		require.NoError(t, err)
+		assert.Empty(t, laporan.PPh)
+		assert.Equal(t, models.Uang(0), laporan.TotalPPh)
+	})
+}
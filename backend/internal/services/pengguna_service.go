@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/pkg/validasi"
 	"errors"
@@ -20,6 +21,14 @@ func NewPenggunaService(db *gorm.DB) *PenggunaService {
 	return &PenggunaService{db: db}
 }
 
+// DenganKonteks mengembalikan salinan service yang menjalankan query dengan ctx,
+// sehingga log audit mencatat pengguna, alamat IP dan request ID dari request tersebut
+func (s *PenggunaService) DenganKonteks(ctx context.Context) *PenggunaService {
+	salinan := *s
+	salinan.db = s.db.WithContext(ctx)
+	return &salinan
+}
+
 // BuatPenggunaRequest adalah struktur request untuk membuat pengguna
 type BuatPenggunaRequest struct {
 	NamaLengkap  string               `json:"namaLengkap" binding:"required"`
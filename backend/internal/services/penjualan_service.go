@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/pkg/validasi"
 	"errors"
@@ -28,18 +29,28 @@ func NewPenjualanService(db *gorm.DB, produkService *ProdukService, transaksiSer
 	}
 }
 
+// DenganKonteks mengembalikan salinan service yang menjalankan query dengan ctx,
+// sehingga log audit mencatat pengguna, alamat IP dan request ID dari request tersebut
+func (s *PenjualanService) DenganKonteks(ctx context.Context) *PenjualanService {
+	salinan := *s
+	salinan.db = s.db.WithContext(ctx)
+	salinan.produkService = s.produkService.DenganKonteks(ctx)
+	salinan.transaksiService = s.transaksiService.DenganKonteks(ctx)
+	return &salinan
+}
+
 // ItemPenjualanRequest adalah struktur untuk item dalam penjualan
 type ItemPenjualanRequest struct {
-	IDProduk    uuid.UUID `json:"idProduk" binding:"required"`
-	Kuantitas   int       `json:"kuantitas" binding:"required,gt=0"`
-	HargaSatuan float64   `json:"hargaSatuan" binding:"required,gt=0"`
+	IDProduk    uuid.UUID   `json:"idProduk" binding:"required"`
+	Kuantitas   int         `json:"kuantitas" binding:"required,gt=0"`
+	HargaSatuan models.Uang `json:"hargaSatuan" binding:"required,gt=0"`
 }
 
 // ProsesPenjualanRequest adalah struktur request untuk proses penjualan
 type ProsesPenjualanRequest struct {
 	IDAnggota   *uuid.UUID             `json:"idAnggota"` // Optional
 	Items       []ItemPenjualanRequest `json:"items" binding:"required,min=1"`
-	JumlahBayar float64                `json:"jumlahBayar" binding:"required,gt=0"`
+	JumlahBayar models.Uang            `json:"jumlahBayar" binding:"required,gt=0"`
 	Catatan     string                 `json:"catatan"`
 }
 
@@ -49,7 +60,7 @@ func (s *PenjualanService) ProsesPenjualan(idKoperasi, idKasir uuid.UUID, req *P
 	validator := validasi.Baru()
 
 	// Validasi business logic
-	if err := validator.Jumlah(req.JumlahBayar, "jumlah bayar"); err != nil {
+	if err := validator.Jumlah(req.JumlahBayar.Float64(), "jumlah bayar"); err != nil {
 		return nil, err
 	}
 
@@ -62,11 +73,23 @@ func (s *PenjualanService) ProsesPenjualan(idKoperasi, idKasir uuid.UUID, req *P
 		return nil, err
 	}
 
-	// Hitung total belanja
-	var totalBelanja float64
-	for _, item := range req.Items {
-		totalBelanja += item.HargaSatuan * float64(item.Kuantitas)
+	// Hitung DPP dan PPN setiap item sesuai kode pajak produk
+	itemPajak, err := s.hitungPajakItem(idKoperasi, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hitung total belanja; PPN di luar harga menambah total yang dibayar
+	var totalBelanja, totalDPP, totalPPN models.Uang
+	for i, item := range req.Items {
+		if itemPajak[i].KodePajak == models.KodePajakPPN {
+			totalDPP += itemPajak[i].DPP
+			totalPPN += itemPajak[i].PPN
+			continue
+		}
+		totalBelanja += item.HargaSatuan.Kali(item.Kuantitas)
 	}
+	totalBelanja += totalDPP + totalPPN
 
 	// Validasi pembayaran
 	if err := s.ValidasiPembayaran(totalBelanja, req.JumlahBayar); err != nil {
@@ -94,6 +117,8 @@ func (s *PenjualanService) ProsesPenjualan(idKoperasi, idKasir uuid.UUID, req *P
 			TanggalPenjualan: time.Now(),
 			IDAnggota:        req.IDAnggota,
 			TotalBelanja:     totalBelanja,
+			TotalDPP:         totalDPP,
+			TotalPPN:         totalPPN,
 			MetodePembayaran: models.PembayaranTunai,
 			JumlahBayar:      req.JumlahBayar,
 			Kembalian:        kembalian,
@@ -106,7 +131,7 @@ func (s *PenjualanService) ProsesPenjualan(idKoperasi, idKasir uuid.UUID, req *P
 		}
 
 		// Step 2: Buat item penjualan dan update stok
-		for _, itemReq := range req.Items {
+		for i, itemReq := range req.Items {
 			// Dapatkan produk untuk snapshot nama
 			var produk models.Produk
 			if findErr := tx.Where("id = ?", itemReq.IDProduk).First(&produk).Error; findErr != nil {
@@ -120,6 +145,9 @@ func (s *PenjualanService) ProsesPenjualan(idKoperasi, idKasir uuid.UUID, req *P
 				NamaProduk:  produk.NamaProduk,
 				Kuantitas:   itemReq.Kuantitas,
 				HargaSatuan: itemReq.HargaSatuan,
+				KodePajak:   itemPajak[i].KodePajak,
+				DPP:         itemPajak[i].DPP,
+				PPN:         itemPajak[i].PPN,
 			}
 
 			if itemErr := tx.Create(&item).Error; itemErr != nil {
@@ -151,6 +179,83 @@ func (s *PenjualanService) ProsesPenjualan(idKoperasi, idKasir uuid.UUID, req *P
 	return &response, nil
 }
 
+// BatalkanPenjualanRequest adalah struktur request untuk membatalkan penjualan
+type BatalkanPenjualanRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// BatalkanPenjualan membatalkan (void) penjualan yang sudah diproses.
+//
+// Penjualan tidak dihapus: stok setiap item dikembalikan, jurnal penjualannya dibalik
+// dengan jurnal pembalik tertanggal hari ini, dan penjualan ditandai dibatalkan sehingga
+// tidak lagi dihitung dalam laporan penjualan maupun SHU jasa usaha.
+func (s *PenjualanService) BatalkanPenjualan(idKoperasi, idPengguna, id uuid.UUID, req *BatalkanPenjualanRequest) (*models.PenjualanResponse, error) {
+	validator := validasi.Baru()
+
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	var penjualan models.Penjualan
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Step 1: Kunci penjualan agar tidak dibatalkan dua kali secara bersamaan
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&penjualan).Error
+		if lockErr != nil {
+			if errors.Is(lockErr, gorm.ErrRecordNotFound) {
+				return errors.New("penjualan tidak ditemukan")
+			}
+			return errors.New("gagal mengambil penjualan")
+		}
+
+		if penjualan.Dibatalkan {
+			return errors.New("penjualan sudah dibatalkan")
+		}
+
+		// Step 2: Kembalikan stok setiap item
+		var items []models.ItemPenjualan
+		if findErr := tx.Where("id_penjualan = ?", penjualan.ID).Find(&items).Error; findErr != nil {
+			return errors.New("gagal mengambil item penjualan")
+		}
+		for _, item := range items {
+			if stokErr := s.produkService.TambahStokWithTx(tx, item.IDProduk, item.Kuantitas); stokErr != nil {
+				return fmt.Errorf("gagal mengembalikan stok: %w", stokErr)
+			}
+		}
+
+		// Step 3: Balik jurnal penjualan dalam transaction yang sama
+		sekarang := time.Now()
+		if penjualan.IDTransaksi != nil {
+			if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *penjualan.IDTransaksi,
+				sekarang, req.Alasan, models.TipeTransaksiPenjualan); balikErr != nil {
+				return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+			}
+		}
+
+		// Step 4: Tandai penjualan dibatalkan
+		penjualan.Dibatalkan = true
+		penjualan.TanggalDibatalkan = &sekarang
+		penjualan.DibatalkanOleh = &idPengguna
+		penjualan.AlasanPembatalan = req.Alasan
+		if saveErr := tx.Save(&penjualan).Error; saveErr != nil {
+			return errors.New("gagal membatalkan penjualan")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload dengan relasi
+	s.db.Preload("ItemPenjualan.Produk").Preload("Kasir").Preload("Anggota").First(&penjualan, penjualan.ID)
+
+	response := penjualan.ToResponse()
+	return &response, nil
+}
+
 // ValidasiItemPenjualan memvalidasi semua item (stok tersedia dan format)
 func (s *PenjualanService) ValidasiItemPenjualan(items []ItemPenjualanRequest) error {
 	validator := validasi.Baru()
@@ -162,7 +267,7 @@ func (s *PenjualanService) ValidasiItemPenjualan(items []ItemPenjualanRequest) e
 		}
 
 		// Validasi harga satuan
-		if err := validator.Jumlah(item.HargaSatuan, fmt.Sprintf("harga satuan item ke-%d", i+1)); err != nil {
+		if err := validator.Jumlah(item.HargaSatuan.Float64(), fmt.Sprintf("harga satuan item ke-%d", i+1)); err != nil {
 			return err
 		}
 
@@ -180,10 +285,38 @@ func (s *PenjualanService) ValidasiItemPenjualan(items []ItemPenjualanRequest) e
 	return nil
 }
 
+// hitungPajakItem menghitung kode pajak, DPP, dan PPN setiap item sesuai urutan request.
+// PPN hanya dipungut jika koperasi berstatus PKP dan produk berkode pajak PPN; item lainnya
+// tercatat NON_PPN tanpa DPP.
+func (s *PenjualanService) hitungPajakItem(idKoperasi uuid.UUID, items []ItemPenjualanRequest) ([]models.ItemPenjualan, error) {
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", idKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+
+	hasil := make([]models.ItemPenjualan, len(items))
+	for i, item := range items {
+		hasil[i].KodePajak = models.KodePajakNonPPN
+		if !koperasi.PKP {
+			continue
+		}
+
+		var produk models.Produk
+		if err := s.db.Select("id", "kode_pajak").Where("id = ?", item.IDProduk).First(&produk).Error; err != nil {
+			return nil, fmt.Errorf("produk %s tidak ditemukan", item.IDProduk)
+		}
+		if produk.KodePajak == models.KodePajakPPN {
+			hasil[i].KodePajak = models.KodePajakPPN
+			hasil[i].DPP, hasil[i].PPN = hitungPPN(item.HargaSatuan.Kali(item.Kuantitas), koperasi.TarifPPN, koperasi.HargaTermasukPPN)
+		}
+	}
+	return hasil, nil
+}
+
 // ValidasiPembayaran memvalidasi jumlah bayar cukup
-func (s *PenjualanService) ValidasiPembayaran(totalBelanja, jumlahBayar float64) error {
+func (s *PenjualanService) ValidasiPembayaran(totalBelanja, jumlahBayar models.Uang) error {
 	if jumlahBayar < totalBelanja {
-		return fmt.Errorf("jumlah bayar (%.2f) kurang dari total belanja (%.2f)", jumlahBayar, totalBelanja)
+		return fmt.Errorf("jumlah bayar (%s) kurang dari total belanja (%s)", jumlahBayar, totalBelanja)
 	}
 	return nil
 }
@@ -303,14 +436,14 @@ func (s *PenjualanService) DapatkanStruk(id uuid.UUID) (*models.PenjualanRespons
 // HitungTotalPenjualan menghitung total penjualan dalam periode
 func (s *PenjualanService) HitungTotalPenjualan(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir string) (map[string]interface{}, error) {
 	type SalesResult struct {
-		TotalPenjualan  float64
+		TotalPenjualan  models.Uang
 		JumlahTransaksi int64
 	}
 
 	var result SalesResult
 	query := s.db.Model(&models.Penjualan{}).
 		Select("COALESCE(SUM(total_belanja), 0) as total_penjualan, COUNT(*) as jumlah_transaksi").
-		Where("id_koperasi = ?", idKoperasi)
+		Where("id_koperasi = ? AND dibatalkan = ?", idKoperasi, false)
 
 	if tanggalMulai != "" {
 		query = query.Where("tanggal_penjualan >= ?", tanggalMulai)
@@ -327,11 +460,11 @@ func (s *PenjualanService) HitungTotalPenjualan(idKoperasi uuid.UUID, tanggalMul
 	summary := map[string]interface{}{
 		"totalPenjualan":  result.TotalPenjualan,
 		"jumlahTransaksi": result.JumlahTransaksi,
-		"rataRata":        float64(0),
+		"rataRata":        models.Uang(0),
 	}
 
 	if result.JumlahTransaksi > 0 {
-		summary["rataRata"] = result.TotalPenjualan / float64(result.JumlahTransaksi)
+		summary["rataRata"] = result.TotalPenjualan.KaliPecahan(1, result.JumlahTransaksi)
 	}
 
 	return summary, nil
@@ -349,14 +482,14 @@ func (s *PenjualanService) DapatkanTopProduk(idKoperasi uuid.UUID, limit int) ([
 		IDProduk     uuid.UUID
 		NamaProduk   string
 		TotalTerjual int
-		TotalNilai   float64
+		TotalNilai   models.Uang
 	}
 
 	var results []TopProduk
 	err := s.db.Model(&models.ItemPenjualan{}).
 		Select("item_penjualan.id_produk, item_penjualan.nama_produk, SUM(item_penjualan.kuantitas) as total_terjual, SUM(item_penjualan.subtotal) as total_nilai").
 		Joins("JOIN penjualan ON penjualan.id = item_penjualan.id_penjualan").
-		Where("penjualan.id_koperasi = ?", idKoperasi).
+		Where("penjualan.id_koperasi = ? AND penjualan.dibatalkan = ?", idKoperasi, false).
 		Group("item_penjualan.id_produk, item_penjualan.nama_produk").
 		Order("total_terjual DESC").
 		Limit(limit).
@@ -37,6 +37,9 @@ func setupPenjualanTestDB(t *testing.T) *gorm.DB {
 		&models.Akun{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate database: %v", err)
@@ -90,7 +93,7 @@ func TestProsesPenjualan_Success(t *testing.T) {
 	produkReq := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       100,
 	}
 	produk, _ := produkService.BuatProduk(koperasi.ID, produkReq)
@@ -101,10 +104,10 @@ func TestProsesPenjualan_Success(t *testing.T) {
 			{
 				IDProduk:    produk.ID,
 				Kuantitas:   2,
-				HargaSatuan: 50000,
+				HargaSatuan: models.Rupiah(50000),
 			},
 		},
-		JumlahBayar: 100000,
+		JumlahBayar: models.Rupiah(100000),
 		Catatan:     "Test sale",
 	}
 
@@ -113,8 +116,8 @@ func TestProsesPenjualan_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.NotEmpty(t, result.NomorPenjualan)
-	assert.Equal(t, 100000.0, result.TotalBelanja)
-	assert.Equal(t, 0.0, result.Kembalian)
+	assert.Equal(t, models.Rupiah(100000), result.TotalBelanja)
+	assert.Equal(t, models.Uang(0), result.Kembalian)
 	assert.Len(t, result.ItemPenjualan, 1)
 
 	// Verify stock reduced
@@ -122,6 +125,77 @@ func TestProsesPenjualan_Success(t *testing.T) {
 	assert.Equal(t, 98, updatedProduk.Stok) // 100 - 2
 }
 
+// TestBatalkanPenjualan tests voiding a sale restores stock and reverses its journal
+func TestBatalkanPenjualan(t *testing.T) {
+	db := setupPenjualanTestDB(t)
+	if db == nil {
+		return
+	}
+
+	produkService := NewProdukService(db)
+	transaksiService := NewTransaksiService(db)
+	service := NewPenjualanService(db, produkService, transaksiService)
+
+	koperasi := &models.Koperasi{
+		ID:           uuid.New(),
+		NamaKoperasi: "Test Koperasi",
+		Email:        "test@koperasi.com",
+		NoTelepon:    "081234567890",
+	}
+	db.Create(koperasi)
+	assert.NoError(t, NewAkunService(db).InisialisasiCOADefault(koperasi.ID))
+
+	kasir := &models.Pengguna{
+		IDKoperasi:   koperasi.ID,
+		NamaPengguna: "kasir01",
+		Email:        "kasir@test.com",
+		NamaLengkap:  "Kasir Test",
+		Peran:        models.PeranKasir,
+		StatusAktif:  true,
+	}
+	db.Create(kasir)
+
+	produk, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
+		KodeProduk: "PRD001",
+		NamaProduk: "Test Product",
+		Harga:      models.Rupiah(50000),
+		Stok:       100,
+	})
+
+	penjualan, err := service.ProsesPenjualan(koperasi.ID, kasir.ID, &ProsesPenjualanRequest{
+		Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 2, HargaSatuan: models.Rupiah(50000)}},
+		JumlahBayar: models.Rupiah(100000),
+	})
+	assert.NoError(t, err)
+
+	// Jurnal penjualan tidak dapat dibalik langsung
+	var jurnal models.Transaksi
+	db.Where("nomor_referensi = ?", penjualan.NomorPenjualan).First(&jurnal)
+	_, err = transaksiService.BalikTransaksi(jurnal.ID, koperasi.ID, kasir.ID, &BalikTransaksiRequest{Alasan: "Salah input"})
+	assert.Error(t, err)
+
+	hasil, err := service.BatalkanPenjualan(koperasi.ID, kasir.ID, penjualan.ID, &BatalkanPenjualanRequest{Alasan: "Pelanggan membatalkan"})
+	assert.NoError(t, err)
+	assert.True(t, hasil.Dibatalkan)
+
+	// Stok kembali dan jurnal asal ditandai dibalik
+	updatedProduk, _ := produkService.DapatkanProduk(produk.ID)
+	assert.Equal(t, 100, updatedProduk.Stok)
+
+	db.First(&jurnal, jurnal.ID)
+	assert.True(t, jurnal.Dibalik)
+	assert.NotNil(t, jurnal.IDJurnalPembalik)
+
+	// Penjualan yang dibatalkan tidak dihitung dalam total penjualan
+	summary, err := service.HitungTotalPenjualan(koperasi.ID, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Uang(0), summary["totalPenjualan"])
+
+	// Pembatalan kedua ditolak
+	_, err = service.BatalkanPenjualan(koperasi.ID, kasir.ID, penjualan.ID, &BatalkanPenjualanRequest{Alasan: "Pelanggan membatalkan"})
+	assert.Error(t, err)
+}
+
 // TestProsesPenjualan_ValidationErrors tests sales validation
 func TestProsesPenjualan_ValidationErrors(t *testing.T) {
 	db := setupPenjualanTestDB(t)
@@ -138,7 +212,7 @@ func TestProsesPenjualan_ValidationErrors(t *testing.T) {
 	db.Create(koperasi)
 	db.Create(kasir)
 
-	produkReq := &BuatProdukRequest{KodeProduk: "PRD001", NamaProduk: "Test Product", Harga: 50000, Stok: 10}
+	produkReq := &BuatProdukRequest{KodeProduk: "PRD001", NamaProduk: "Test Product", Harga: models.Rupiah(50000), Stok: 10}
 	produk, _ := produkService.BuatProduk(koperasi.ID, produkReq)
 
 	tests := []struct {
@@ -149,40 +223,40 @@ func TestProsesPenjualan_ValidationErrors(t *testing.T) {
 		{
 			name: "negative payment",
 			req: &ProsesPenjualanRequest{
-				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: 50000}},
-				JumlahBayar: -1000,
+				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(50000)}},
+				JumlahBayar: models.Rupiah(-1000),
 			},
 			wantErr: true,
 		},
 		{
 			name: "payment too large",
 			req: &ProsesPenjualanRequest{
-				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: 50000}},
-				JumlahBayar: 1000000000, // > 999,999,999
+				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(50000)}},
+				JumlahBayar: models.Rupiah(1000000000), // > 999,999,999
 			},
 			wantErr: true,
 		},
 		{
 			name: "insufficient payment",
 			req: &ProsesPenjualanRequest{
-				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 2, HargaSatuan: 50000}},
-				JumlahBayar: 50000, // Less than 100000
+				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 2, HargaSatuan: models.Rupiah(50000)}},
+				JumlahBayar: models.Rupiah(50000), // Less than 100000
 			},
 			wantErr: true,
 		},
 		{
 			name: "insufficient stock",
 			req: &ProsesPenjualanRequest{
-				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 100, HargaSatuan: 50000}},
-				JumlahBayar: 5000000,
+				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 100, HargaSatuan: models.Rupiah(50000)}},
+				JumlahBayar: models.Rupiah(5000000),
 			},
 			wantErr: true,
 		},
 		{
 			name: "zero quantity",
 			req: &ProsesPenjualanRequest{
-				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 0, HargaSatuan: 50000}},
-				JumlahBayar: 50000,
+				Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 0, HargaSatuan: models.Rupiah(50000)}},
+				JumlahBayar: models.Rupiah(50000),
 			},
 			wantErr: true,
 		},
@@ -216,22 +290,22 @@ func TestProsesPenjualan_WithChange(t *testing.T) {
 	db.Create(koperasi)
 	db.Create(kasir)
 
-	produkReq := &BuatProdukRequest{KodeProduk: "PRD001", NamaProduk: "Test Product", Harga: 25000, Stok: 100}
+	produkReq := &BuatProdukRequest{KodeProduk: "PRD001", NamaProduk: "Test Product", Harga: models.Rupiah(25000), Stok: 100}
 	produk, _ := produkService.BuatProduk(koperasi.ID, produkReq)
 
 	saleReq := &ProsesPenjualanRequest{
 		Items: []ItemPenjualanRequest{
-			{IDProduk: produk.ID, Kuantitas: 3, HargaSatuan: 25000},
+			{IDProduk: produk.ID, Kuantitas: 3, HargaSatuan: models.Rupiah(25000)},
 		},
-		JumlahBayar: 100000, // Total: 75000, Change: 25000
+		JumlahBayar: models.Rupiah(100000), // Total: 75000, Change: 25000
 	}
 
 	result, err := service.ProsesPenjualan(koperasi.ID, kasir.ID, saleReq)
 
 	assert.NoError(t, err)
-	assert.Equal(t, 75000.0, result.TotalBelanja)
-	assert.Equal(t, 100000.0, result.JumlahBayar)
-	assert.Equal(t, 25000.0, result.Kembalian)
+	assert.Equal(t, models.Rupiah(75000), result.TotalBelanja)
+	assert.Equal(t, models.Rupiah(100000), result.JumlahBayar)
+	assert.Equal(t, models.Rupiah(25000), result.Kembalian)
 }
 
 // TestProsesPenjualan_MultipleItems tests sale with multiple items
@@ -251,25 +325,25 @@ func TestProsesPenjualan_MultipleItems(t *testing.T) {
 	db.Create(kasir)
 
 	// Create multiple products
-	produk1, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{KodeProduk: "PRD001", NamaProduk: "Product 1", Harga: 10000, Stok: 100})
-	produk2, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{KodeProduk: "PRD002", NamaProduk: "Product 2", Harga: 20000, Stok: 100})
-	produk3, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{KodeProduk: "PRD003", NamaProduk: "Product 3", Harga: 30000, Stok: 100})
+	produk1, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{KodeProduk: "PRD001", NamaProduk: "Product 1", Harga: models.Rupiah(10000), Stok: 100})
+	produk2, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{KodeProduk: "PRD002", NamaProduk: "Product 2", Harga: models.Rupiah(20000), Stok: 100})
+	produk3, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{KodeProduk: "PRD003", NamaProduk: "Product 3", Harga: models.Rupiah(30000), Stok: 100})
 
 	saleReq := &ProsesPenjualanRequest{
 		Items: []ItemPenjualanRequest{
-			{IDProduk: produk1.ID, Kuantitas: 2, HargaSatuan: 10000},
-			{IDProduk: produk2.ID, Kuantitas: 1, HargaSatuan: 20000},
-			{IDProduk: produk3.ID, Kuantitas: 3, HargaSatuan: 30000},
+			{IDProduk: produk1.ID, Kuantitas: 2, HargaSatuan: models.Rupiah(10000)},
+			{IDProduk: produk2.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(20000)},
+			{IDProduk: produk3.ID, Kuantitas: 3, HargaSatuan: models.Rupiah(30000)},
 		},
-		JumlahBayar: 150000, // Total: 2*10k + 1*20k + 3*30k = 130k
+		JumlahBayar: models.Rupiah(150000), // Total: 2*10k + 1*20k + 3*30k = 130k
 	}
 
 	result, err := service.ProsesPenjualan(koperasi.ID, kasir.ID, saleReq)
 
 	assert.NoError(t, err)
-	assert.Equal(t, 130000.0, result.TotalBelanja)
+	assert.Equal(t, models.Rupiah(130000), result.TotalBelanja)
 	assert.Len(t, result.ItemPenjualan, 3)
-	assert.Equal(t, 20000.0, result.Kembalian)
+	assert.Equal(t, models.Rupiah(20000), result.Kembalian)
 
 	// Verify all stock reduced correctly
 	p1, _ := produkService.DapatkanProduk(produk1.ID)
@@ -308,8 +382,8 @@ func TestGenerateNomorPenjualan_Sequential(t *testing.T) {
 		IDKoperasi:       koperasi.ID,
 		NomorPenjualan:   nomor1,
 		TanggalPenjualan: today,
-		TotalBelanja:     50000,
-		JumlahBayar:      50000,
+		TotalBelanja:     models.Rupiah(50000),
+		JumlahBayar:      models.Rupiah(50000),
 		IDKasir:          uuid.New(),
 	}
 	db.Create(penjualan1)
@@ -339,7 +413,7 @@ func TestProsesPenjualan_Concurrent(t *testing.T) {
 	produk, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       1000,
 	})
 
@@ -355,9 +429,9 @@ func TestProsesPenjualan_Concurrent(t *testing.T) {
 
 			req := &ProsesPenjualanRequest{
 				Items: []ItemPenjualanRequest{
-					{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: 50000},
+					{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(50000)},
 				},
-				JumlahBayar: 50000,
+				JumlahBayar: models.Rupiah(50000),
 			}
 
 			result, err := service.ProsesPenjualan(koperasi.ID, kasir.ID, req)
@@ -405,15 +479,15 @@ func TestDapatkanSemuaPenjualan(t *testing.T) {
 	produk, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       1000,
 	})
 
 	// Create multiple sales
 	for i := 0; i < 5; i++ {
 		req := &ProsesPenjualanRequest{
-			Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: 50000}},
-			JumlahBayar: 50000,
+			Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(50000)}},
+			JumlahBayar: models.Rupiah(50000),
 		}
 		service.ProsesPenjualan(koperasi.ID, kasir.ID, req)
 	}
@@ -458,15 +532,15 @@ func TestHitungTotalPenjualan(t *testing.T) {
 	produk, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       1000,
 	})
 
 	// Create 3 sales
 	for i := 0; i < 3; i++ {
 		req := &ProsesPenjualanRequest{
-			Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 2, HargaSatuan: 50000}},
-			JumlahBayar: 100000,
+			Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 2, HargaSatuan: models.Rupiah(50000)}},
+			JumlahBayar: models.Rupiah(100000),
 		}
 		service.ProsesPenjualan(koperasi.ID, kasir.ID, req)
 	}
@@ -476,9 +550,9 @@ func TestHitungTotalPenjualan(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NotNil(t, summary)
-	assert.Equal(t, 300000.0, summary["totalPenjualan"])
+	assert.Equal(t, models.Rupiah(300000), summary["totalPenjualan"])
 	assert.Equal(t, int64(3), summary["jumlahTransaksi"])
-	assert.Equal(t, 100000.0, summary["rataRata"])
+	assert.Equal(t, models.Rupiah(100000), summary["rataRata"])
 }
 
 // TestDapatkanPenjualanHariIni tests today's sales summary
@@ -500,13 +574,13 @@ func TestDapatkanPenjualanHariIni(t *testing.T) {
 	produk, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       1000,
 	})
 
 	req := &ProsesPenjualanRequest{
-		Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: 50000}},
-		JumlahBayar: 50000,
+		Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(50000)}},
+		JumlahBayar: models.Rupiah(50000),
 	}
 	service.ProsesPenjualan(koperasi.ID, kasir.ID, req)
 
@@ -514,7 +588,7 @@ func TestDapatkanPenjualanHariIni(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NotNil(t, summary)
-	assert.Equal(t, 50000.0, summary["totalPenjualan"])
+	assert.Equal(t, models.Rupiah(50000), summary["totalPenjualan"])
 	assert.Equal(t, int64(1), summary["jumlahTransaksi"])
 }
 
@@ -548,7 +622,7 @@ func BenchmarkProsesPenjualan(b *testing.B) {
 	produk, _ := produkService.BuatProduk(koperasi.ID, &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       100000,
 	})
 
@@ -556,8 +630,8 @@ func BenchmarkProsesPenjualan(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		req := &ProsesPenjualanRequest{
-			Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: 50000}},
-			JumlahBayar: 50000,
+			Items:       []ItemPenjualanRequest{{IDProduk: produk.ID, Kuantitas: 1, HargaSatuan: models.Rupiah(50000)}},
+			JumlahBayar: models.Rupiah(50000),
 		}
 		_, _ = service.ProsesPenjualan(koperasi.ID, kasir.ID, req)
 	}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrPeriodeDitutup dikembalikan ketika jurnal menyentuh tanggal di dalam periode yang sudah ditutup
+var ErrPeriodeDitutup = errors.New("periode akuntansi sudah ditutup")
+
+// PeriodeService menangani penutupan periode akuntansi (bulanan dan tahun buku)
+// beserta jurnal penutupan dan saldo awal tahun buku berikutnya
+type PeriodeService struct {
+	db               *gorm.DB
+	transaksiService *TransaksiService
+}
+
+// NewPeriodeService membuat instance baru PeriodeService
+func NewPeriodeService(db *gorm.DB, transaksiService *TransaksiService) *PeriodeService {
+	return &PeriodeService{
+		db:               db,
+		transaksiService: transaksiService,
+	}
+}
+
+// TutupBulanRequest adalah struktur request untuk menutup satu bulan kalender
+type TutupBulanRequest struct {
+	Tahun      int    `json:"tahun" binding:"required"`
+	Bulan      int    `json:"bulan" binding:"required,min=1,max=12"`
+	Keterangan string `json:"keterangan"`
+}
+
+// TutupTahunBukuRequest adalah struktur request untuk menutup satu tahun buku
+type TutupTahunBukuRequest struct {
+	TahunBuku  int    `json:"tahunBuku" binding:"required"`
+	Keterangan string `json:"keterangan"`
+}
+
+// JurnalPenutupanRequest adalah struktur request untuk membuat jurnal penutupan tahun buku
+type JurnalPenutupanRequest struct {
+	TahunBuku int `json:"tahunBuku" binding:"required"`
+}
+
+// TahunBukuDariTanggal mengembalikan label tahun buku yang memuat tanggal tertentu
+func TahunBukuDariTanggal(bulanMulai int, tanggal time.Time) int {
+	if bulanMulai < 1 || bulanMulai > 12 {
+		bulanMulai = 1
+	}
+	if int(tanggal.Month()) >= bulanMulai {
+		return tanggal.Year()
+	}
+	return tanggal.Year() - 1
+}
+
+// TutupBulan menutup satu bulan kalender sehingga jurnal di bulan tersebut tidak dapat diubah lagi
+func (s *PeriodeService) TutupBulan(idKoperasi, idPengguna uuid.UUID, req *TutupBulanRequest) (*models.PeriodeAkuntansiResponse, error) {
+	if req.Bulan < 1 || req.Bulan > 12 {
+		return nil, errors.New("bulan harus antara 1 dan 12")
+	}
+
+	koperasi, err := s.ambilKoperasi(idKoperasi)
+	if err != nil {
+		return nil, err
+	}
+
+	mulai := time.Date(req.Tahun, time.Month(req.Bulan), 1, 0, 0, 0, 0, time.UTC)
+	periode := &models.PeriodeAkuntansi{
+		IDKoperasi:   idKoperasi,
+		TipePeriode:  models.PeriodeBulanan,
+		TanggalMulai: mulai,
+		TanggalAkhir: mulai.AddDate(0, 1, -1),
+		TahunBuku:    TahunBukuDariTanggal(koperasi.TahunBukuMulai, mulai),
+		Bulan:        req.Bulan,
+		Keterangan:   req.Keterangan,
+		DitutupOleh:  idPengguna,
+	}
+
+	if err := s.simpanPenutupan(periode, nil); err != nil {
+		return nil, err
+	}
+
+	response := periode.ToResponse()
+	return &response, nil
+}
+
+// TutupTahunBuku menutup seluruh tahun buku berdasarkan Koperasi.TahunBukuMulai.
+// Dalam satu transaction: jurnal penutupan dibuat ulang agar mencakup seluruh jurnal penyesuaian,
+// saldo awal tahun buku berikutnya disimpan, lalu tahun buku dikunci.
+func (s *PeriodeService) TutupTahunBuku(idKoperasi, idPengguna uuid.UUID, req *TutupTahunBukuRequest) (*models.PeriodeAkuntansiResponse, error) {
+	koperasi, err := s.ambilKoperasi(idKoperasi)
+	if err != nil {
+		return nil, err
+	}
+
+	mulai, akhir := RentangTahunBuku(koperasi.TahunBukuMulai, req.TahunBuku)
+	periode := &models.PeriodeAkuntansi{
+		IDKoperasi:   idKoperasi,
+		TipePeriode:  models.PeriodeTahunan,
+		TanggalMulai: mulai,
+		TanggalAkhir: akhir,
+		TahunBuku:    req.TahunBuku,
+		Keterangan:   req.Keterangan,
+		DitutupOleh:  idPengguna,
+	}
+
+	err = s.simpanPenutupan(periode, func(tx *gorm.DB) error {
+		_, jurnalErr := s.buatJurnalPenutupanWithTx(tx, idKoperasi, idPengguna, req.TahunBuku, akhir)
+		return jurnalErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := periode.ToResponse()
+	return &response, nil
+}
+
+// DapatkanSemuaPeriode mengambil daftar periode yang sudah ditutup, terbaru lebih dulu
+func (s *PeriodeService) DapatkanSemuaPeriode(idKoperasi uuid.UUID) ([]models.PeriodeAkuntansiResponse, error) {
+	var periodeList []models.PeriodeAkuntansi
+	err := s.db.Where("id_koperasi = ?", idKoperasi).
+		Order("tanggal_mulai DESC, tipe_periode DESC").
+		Find(&periodeList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar periode")
+	}
+
+	responses := make([]models.PeriodeAkuntansiResponse, len(periodeList))
+	for i, periode := range periodeList {
+		responses[i] = periode.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// CekPeriodeTerbuka memastikan tanggal tidak berada di dalam periode yang sudah ditutup
+func (s *PeriodeService) CekPeriodeTerbuka(idKoperasi uuid.UUID, tanggal time.Time) error {
+	return cekPeriodeTerbukaWithTx(s.db, idKoperasi, tanggal)
+}
+
+// BuatJurnalPenutupan membuat jurnal penutupan tahun buku yang menolkan seluruh akun
+// PENDAPATAN dan BEBAN ke akun SHU Tahun Berjalan (3201).
+// Jika jurnal penutupan tahun tersebut sudah ada, jurnal lama dibalik dan diganti
+// sehingga jurnal penyesuaian yang dibuat setelahnya ikut tertutup. Tahun buku yang sudah
+// dikunci tidak dapat dibuatkan ulang jurnal penutupannya.
+// Mengembalikan nil tanpa error jika tidak ada saldo pendapatan maupun beban.
+func (s *PeriodeService) BuatJurnalPenutupan(idKoperasi, idPengguna uuid.UUID, tahunBuku int) (*models.TransaksiResponse, error) {
+	koperasi, err := s.ambilKoperasi(idKoperasi)
+	if err != nil {
+		return nil, err
+	}
+
+	mulai, akhir := RentangTahunBuku(koperasi.TahunBukuMulai, tahunBuku)
+	if !akhir.Before(hariIniUTC()) {
+		return nil, fmt.Errorf("tahun buku %d belum berakhir", tahunBuku)
+	}
+
+	var transaksi *models.Transaksi
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var jumlah int64
+		if countErr := tx.Model(&models.PeriodeAkuntansi{}).
+			Where("id_koperasi = ? AND tipe_periode = ? AND tanggal_mulai = ?", idKoperasi, models.PeriodeTahunan, mulai.Format("2006-01-02")).
+			Count(&jumlah).Error; countErr != nil {
+			return errors.New("gagal memeriksa periode akuntansi")
+		}
+		if jumlah > 0 {
+			return fmt.Errorf("%w: tahun buku %d", ErrPeriodeDitutup, tahunBuku)
+		}
+
+		var jurnalErr error
+		transaksi, jurnalErr = s.buatJurnalPenutupanWithTx(tx, idKoperasi, idPengguna, tahunBuku, akhir)
+		return jurnalErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	if transaksi == nil {
+		return nil, nil
+	}
+
+	// Reload dengan baris transaksi
+	s.db.Preload("BarisTransaksi.Akun").First(transaksi, transaksi.ID)
+
+	response := transaksi.ToResponse()
+	return &response, nil
+}
+
+// BuatJurnalPenutupanOtomatis membuat jurnal penutupan untuk tahun buku terakhir yang sudah berakhir
+// pada setiap koperasi yang belum memilikinya. Tahun buku yang sudah dikunci dilewati.
+func (s *PeriodeService) BuatJurnalPenutupanOtomatis() {
+	var koperasiList []models.Koperasi
+	if err := s.db.Find(&koperasiList).Error; err != nil {
+		log.Printf("Penutupan otomatis: gagal mengambil daftar koperasi: %v", err)
+		return
+	}
+
+	for _, koperasi := range koperasiList {
+		tahunBuku := TahunBukuDariTanggal(koperasi.TahunBukuMulai, time.Now()) - 1
+
+		var jumlah int64
+		s.db.Model(&models.Transaksi{}).
+			Where("id_koperasi = ? AND tipe_transaksi = ? AND nomor_referensi = ?",
+				koperasi.ID, models.TipeTransaksiPenutupan, nomorReferensiPenutupan(tahunBuku)).
+			Count(&jumlah)
+		if jumlah > 0 {
+			continue
+		}
+
+		_, err := s.BuatJurnalPenutupan(koperasi.ID, uuid.Nil, tahunBuku)
+		if err != nil && !errors.Is(err, ErrPeriodeDitutup) {
+			log.Printf("Penutupan otomatis: gagal membuat jurnal penutupan %s tahun buku %d: %v", koperasi.ID, tahunBuku, err)
+		}
+	}
+}
+
+// MulaiPenutupanOtomatis menjalankan BuatJurnalPenutupanOtomatis secara berkala di background.
+// Panggil fungsi stop yang dikembalikan untuk menghentikan goroutine.
+func (s *PeriodeService) MulaiPenutupanOtomatis(interval time.Duration) (stop func()) {
+	stopChan := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.BuatJurnalPenutupanOtomatis()
+		for {
+			select {
+			case <-ticker.C:
+				s.BuatJurnalPenutupanOtomatis()
+			case <-stopChan:
+				return // Graceful shutdown
+			}
+		}
+	}()
+
+	return func() { close(stopChan) }
+}
+
+// simpanPenutupan memvalidasi lalu menyimpan catatan penutupan periode.
+// prosesTambahan (opsional) dijalankan dalam transaction yang sama sebelum periode dikunci.
+func (s *PeriodeService) simpanPenutupan(periode *models.PeriodeAkuntansi, prosesTambahan func(tx *gorm.DB) error) error {
+	// Periode hanya dapat ditutup setelah tanggal akhirnya lewat
+	if !periode.TanggalAkhir.Before(hariIniUTC()) {
+		return fmt.Errorf("periode %s belum berakhir", labelPeriode(periode))
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Tolak jika periode (atau periode yang melingkupinya) sudah ditutup
+		var jumlah int64
+		err := tx.Model(&models.PeriodeAkuntansi{}).
+			Where("id_koperasi = ? AND tanggal_mulai <= ? AND tanggal_akhir >= ?",
+				periode.IDKoperasi, periode.TanggalMulai.Format("2006-01-02"), periode.TanggalAkhir.Format("2006-01-02")).
+			Where("tipe_periode = ? OR tipe_periode = ?", periode.TipePeriode, models.PeriodeTahunan).
+			Count(&jumlah).Error
+		if err != nil {
+			return errors.New("gagal memeriksa periode akuntansi")
+		}
+		if jumlah > 0 {
+			return fmt.Errorf("periode %s sudah ditutup", labelPeriode(periode))
+		}
+
+		// Jurnal yang masih dalam proses persetujuan tidak dapat di-post setelah periodenya ditutup
+		var jumlahBelumPosted int64
+		err = tx.Model(&models.Transaksi{}).
+			Where("id_koperasi = ? AND status <> ? AND tanggal_transaksi BETWEEN ? AND ?",
+				periode.IDKoperasi, models.StatusJurnalPosted, periode.TanggalMulai.Format("2006-01-02"), periode.TanggalAkhir.Format("2006-01-02")).
+			Count(&jumlahBelumPosted).Error
+		if err != nil {
+			return errors.New("gagal memeriksa jurnal yang belum di-post")
+		}
+		if jumlahBelumPosted > 0 {
+			return fmt.Errorf("masih ada %d jurnal yang belum di-post pada periode %s", jumlahBelumPosted, labelPeriode(periode))
+		}
+
+		if prosesTambahan != nil {
+			if err := prosesTambahan(tx); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(periode).Error; err != nil {
+			return errors.New("gagal menutup periode")
+		}
+		return nil
+	})
+}
+
+// ambilKoperasi mengambil data koperasi untuk membaca bulan mulai tahun buku
+func (s *PeriodeService) ambilKoperasi(idKoperasi uuid.UUID) (*models.Koperasi, error) {
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", idKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+	return &koperasi, nil
+}
+
+// buatJurnalPenutupanWithTx mengganti jurnal penutupan tahun buku dengan saldo PENDAPATAN dan BEBAN terkini.
+// Jurnal penutupan lama tidak dihapus, tetapi dibalik dengan jurnal pembalik tertanggal akhir tahun buku.
+// Jurnal ditulis tanpa pemeriksaan periode karena bulan terakhir tahun buku umumnya sudah ditutup lebih dulu.
+func (s *PeriodeService) buatJurnalPenutupanWithTx(tx *gorm.DB, idKoperasi, idPengguna uuid.UUID, tahunBuku int, akhir time.Time) (*models.Transaksi, error) {
+	nomorReferensi := nomorReferensiPenutupan(tahunBuku)
+
+	// Balik jurnal penutupan lama agar pendapatan dan beban kembali ke saldo sebelum ditutup
+	var jurnalLama []models.Transaksi
+	if err := tx.Where("id_koperasi = ? AND tipe_transaksi = ? AND nomor_referensi = ? AND status = ? AND dibalik = ?",
+		idKoperasi, models.TipeTransaksiPenutupan, nomorReferensi, models.StatusJurnalPosted, false).Find(&jurnalLama).Error; err != nil {
+		return nil, errors.New("gagal mengambil jurnal penutupan lama")
+	}
+	for _, jurnal := range jurnalLama {
+		alasan := fmt.Sprintf("Penutupan ulang tahun buku %d", tahunBuku)
+		if _, err := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, jurnal.ID, akhir, alasan, models.TipeTransaksiPenutupan); err != nil {
+			return nil, fmt.Errorf("gagal membalik jurnal penutupan lama: %w", err)
+		}
+	}
+
+	// Saldo seluruh akun pendapatan dan beban sampai akhir tahun buku, per unit usaha
+	type saldoAkunNominal struct {
+		IDAkun      uuid.UUID
+		IDUnitUsaha *uuid.UUID
+		TotalDebit  models.Uang
+		TotalKredit models.Uang
+	}
+
+	var saldoList []saldoAkunNominal
+	err := tx.Table("baris_transaksi").
+		Select("baris_transaksi.id_akun, baris_transaksi.id_unit_usaha, COALESCE(SUM(baris_transaksi.jumlah_debit), 0) as total_debit, COALESCE(SUM(baris_transaksi.jumlah_kredit), 0) as total_kredit").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Joins("JOIN akun ON akun.id = baris_transaksi.id_akun").
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+		Where("transaksi.tanggal_transaksi <= ?", akhir.Format("2006-01-02")).
+		Where("akun.tipe_akun IN ?", []models.TipeAkun{models.AkunPendapatan, models.AkunBeban}).
+		Group("baris_transaksi.id_akun, baris_transaksi.id_unit_usaha, akun.kode_akun").
+		Order("akun.kode_akun ASC, baris_transaksi.id_unit_usaha ASC").
+		Scan(&saldoList).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung saldo pendapatan dan beban")
+	}
+
+	// Nolkan setiap akun dengan posisi sebaliknya. SHU dihitung per unit usaha agar
+	// kontribusi setiap unit tetap terbaca di akun SHU Tahun Berjalan.
+	keterangan := fmt.Sprintf("Penutupan tahun buku %d", tahunBuku)
+	var barisTransaksi []BuatBarisTransaksiRequest
+	var urutanUnit []*uuid.UUID
+	shuPerUnit := make(map[uuid.UUID]models.Uang)
+	for _, saldo := range saldoList {
+		selisih := saldo.TotalDebit - saldo.TotalKredit
+		switch {
+		case selisih > 0:
+			barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{IDAkun: saldo.IDAkun, JumlahKredit: selisih, Keterangan: keterangan, IDUnitUsaha: saldo.IDUnitUsaha})
+		case selisih < 0:
+			barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{IDAkun: saldo.IDAkun, JumlahDebit: -selisih, Keterangan: keterangan, IDUnitUsaha: saldo.IDUnitUsaha})
+		}
+
+		kunci := uuid.Nil
+		if saldo.IDUnitUsaha != nil {
+			kunci = *saldo.IDUnitUsaha
+		}
+		if _, ok := shuPerUnit[kunci]; !ok {
+			urutanUnit = append(urutanUnit, saldo.IDUnitUsaha)
+		}
+		shuPerUnit[kunci] -= selisih
+	}
+
+	if len(barisTransaksi) == 0 {
+		return nil, nil
+	}
+
+	// Selisih pendapatan dan beban setiap unit usaha dipindahkan ke SHU Tahun Berjalan
+	var idAkunSHU uuid.UUID
+	for _, idUnitUsaha := range urutanUnit {
+		kunci := uuid.Nil
+		if idUnitUsaha != nil {
+			kunci = *idUnitUsaha
+		}
+		shu := shuPerUnit[kunci]
+		if shu == 0 {
+			continue
+		}
+
+		if idAkunSHU == uuid.Nil {
+			akunPosting, err := akunPostingWithTx(tx, idKoperasi, models.PeristiwaPenutupanTahun)
+			if err != nil {
+				return nil, err
+			}
+			idAkunSHU = akunPosting[models.PeranAkunSHUTahunBerjalan].ID
+		}
+
+		barisSHU := BuatBarisTransaksiRequest{IDAkun: idAkunSHU, Keterangan: keterangan, IDUnitUsaha: idUnitUsaha}
+		if shu > 0 {
+			barisSHU.JumlahKredit = shu
+		} else {
+			barisSHU.JumlahDebit = -shu
+		}
+		barisTransaksi = append(barisTransaksi, barisSHU)
+	}
+
+	transaksi, err := s.transaksiService.tulisJurnalWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: akhir,
+		Deskripsi:        fmt.Sprintf("Jurnal penutupan tahun buku %d", tahunBuku),
+		NomorReferensi:   nomorReferensi,
+		TipeTransaksi:    models.TipeTransaksiPenutupan,
+		BarisTransaksi:   barisTransaksi,
+	}, models.StatusJurnalPosted)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat jurnal penutupan: %w", err)
+	}
+
+	return transaksi, nil
+}
+
+// nomorReferensiPenutupan membuat nomor referensi jurnal penutupan untuk satu tahun buku
+func nomorReferensiPenutupan(tahunBuku int) string {
+	return fmt.Sprintf("PENUTUPAN-%d", tahunBuku)
+}
+
+// hariIniUTC mengembalikan tanggal hari ini (tanpa jam) dalam UTC, sejajar dengan RentangTahunBuku
+func hariIniUTC() time.Time {
+	sekarang := time.Now()
+	return time.Date(sekarang.Year(), sekarang.Month(), sekarang.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// cekPeriodeTerbukaWithTx mengembalikan ErrPeriodeDitutup jika tanggal berada di dalam periode yang sudah ditutup.
+// Dipanggil oleh semua jalur yang membuat, mengubah, atau menghapus jurnal.
+func cekPeriodeTerbukaWithTx(tx *gorm.DB, idKoperasi uuid.UUID, tanggal time.Time) error {
+	tanggalStr := tanggal.Format("2006-01-02")
+
+	var periode models.PeriodeAkuntansi
+	err := tx.Where("id_koperasi = ? AND tanggal_mulai <= ? AND tanggal_akhir >= ?", idKoperasi, tanggalStr, tanggalStr).
+		Order("tanggal_akhir DESC").
+		First(&periode).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return errors.New("gagal memeriksa periode akuntansi")
+	}
+
+	return fmt.Errorf("%w: tanggal %s termasuk periode %s", ErrPeriodeDitutup, tanggalStr, labelPeriode(&periode))
+}
+
+// labelPeriode membuat label periode yang mudah dibaca, misalnya "2024-03" atau "tahun buku 2024"
+func labelPeriode(periode *models.PeriodeAkuntansi) string {
+	if periode.TipePeriode == models.PeriodeTahunan {
+		return fmt.Sprintf("tahun buku %d", periode.TahunBuku)
+	}
+	return periode.TanggalMulai.Format("2006-01")
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupPeriodeTestDB creates a test database for periode service
+func setupPeriodeTestDB(t *testing.T) *gorm.DB {
+	dsn := "host=localhost user=postgres password=postgres dbname=koperasi_erp_test port=5432 sslmode=disable TimeZone=Asia/Jakarta"
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Skipf("Skipping test: cannot connect to test database: %v", err)
+		return nil
+	}
+
+	err = db.AutoMigrate(
+		&models.Koperasi{},
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	db.Exec("TRUNCATE TABLE periode_akuntansi CASCADE")
+
+	return db
+}
+
+func TestTahunBukuDariTanggal(t *testing.T) {
+	assert.Equal(t, 2024, TahunBukuDariTanggal(1, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2024, TahunBukuDariTanggal(7, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2024, TahunBukuDariTanggal(7, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2025, TahunBukuDariTanggal(7, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestTutupBulan_MenolakPerubahanJurnal(t *testing.T) {
+	db := setupPeriodeTestDB(t)
+	if db == nil {
+		return
+	}
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test Periode", Email: "periode@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	var akunKas, akunModal models.Akun
+	require.NoError(t, db.Where("id_koperasi = ? AND kode_akun = ?", koperasi.ID, "1101").First(&akunKas).Error)
+	require.NoError(t, db.Where("id_koperasi = ? AND kode_akun = ?", koperasi.ID, "3103").First(&akunModal).Error)
+
+	transaksiService := NewTransaksiService(db)
+	periodeService := NewPeriodeService(db, transaksiService)
+	pengguna := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	// Jurnal di bulan lalu dibuat sebelum periode ditutup
+	bulanLalu := time.Now().AddDate(0, -1, 0)
+	mulaiBulanLalu := time.Date(bulanLalu.Year(), bulanLalu.Month(), 1, 0, 0, 0, 0, time.UTC)
+	tanggalJurnal := mulaiBulanLalu.AddDate(0, 0, 9)
+
+	jurnalRequest := func(tanggal time.Time) *BuatTransaksiRequest {
+		return &BuatTransaksiRequest{
+			TanggalTransaksi: tanggal,
+			Deskripsi:        "Setoran modal awal",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: akunKas.ID, JumlahDebit: models.Rupiah(100000)},
+				{IDAkun: akunModal.ID, JumlahKredit: models.Rupiah(100000)},
+			},
+		}
+	}
+
+	jurnal, err := transaksiService.BuatTransaksi(koperasi.ID, pengguna, jurnalRequest(tanggalJurnal))
+	require.NoError(t, err)
+
+	periode, err := periodeService.TutupBulan(koperasi.ID, pengguna, &TutupBulanRequest{
+		Tahun: mulaiBulanLalu.Year(),
+		Bulan: int(mulaiBulanLalu.Month()),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.PeriodeBulanan, periode.TipePeriode)
+
+	t.Run("menutup ulang ditolak", func(t *testing.T) {
+		_, err := periodeService.TutupBulan(koperasi.ID, pengguna, &TutupBulanRequest{
+			Tahun: mulaiBulanLalu.Year(),
+			Bulan: int(mulaiBulanLalu.Month()),
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("bulan berjalan belum dapat ditutup", func(t *testing.T) {
+		sekarang := time.Now()
+		_, err := periodeService.TutupBulan(koperasi.ID, pengguna, &TutupBulanRequest{
+			Tahun: sekarang.Year(),
+			Bulan: int(sekarang.Month()),
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("jurnal baru di periode tertutup ditolak", func(t *testing.T) {
+		_, err := transaksiService.BuatTransaksi(koperasi.ID, pengguna, jurnalRequest(tanggalJurnal))
+		assert.ErrorIs(t, err, ErrPeriodeDitutup)
+	})
+
+	t.Run("jurnal tidak dapat dipindah ke periode terbuka", func(t *testing.T) {
+		_, err := transaksiService.PerbaruiTransaksi(jurnal.ID, koperasi.ID, pengguna, jurnalRequest(time.Now()))
+		assert.ErrorIs(t, err, ErrStatusJurnalTidakSesuai)
+	})
+
+	t.Run("jurnal tidak dapat dibalik di periode tertutup", func(t *testing.T) {
+		_, err := transaksiService.BalikTransaksi(jurnal.ID, koperasi.ID, pengguna, &BalikTransaksiRequest{
+			TanggalPembalikan: &tanggalJurnal,
+			Alasan:            "Koreksi salah input",
+		})
+		assert.ErrorIs(t, err, ErrPeriodeDitutup)
+	})
+
+	t.Run("jurnal di periode terbuka tetap diterima", func(t *testing.T) {
+		_, err := transaksiService.BuatTransaksi(koperasi.ID, pengguna, jurnalRequest(time.Now()))
+		assert.NoError(t, err)
+	})
+}
+
+func TestTutupTahunBuku_JurnalPenutupanDanSaldoAwal(t *testing.T) {
+	db := setupPeriodeTestDB(t)
+	if db == nil {
+		return
+	}
+
+	// Tahun buku dimulai bulan ini sehingga tahun buku sebelumnya baru saja berakhir
+	sekarang := time.Now()
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test Penutupan", Email: "penutupan@test.com", NoTelepon: "081234567890", TahunBukuMulai: int(sekarang.Month())}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	akun := map[string]models.Akun{}
+	for _, kode := range []string{"1101", "3201", "4101", "5101"} {
+		var a models.Akun
+		require.NoError(t, db.Where("id_koperasi = ? AND kode_akun = ?", koperasi.ID, kode).First(&a).Error)
+		akun[kode] = a
+	}
+
+	transaksiService := NewTransaksiService(db)
+	periodeService := NewPeriodeService(db, transaksiService)
+	laporanService := NewLaporanService(db, akunService, nil, nil)
+	pengguna := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	tahunBuku := TahunBukuDariTanggal(koperasi.TahunBukuMulai, sekarang) - 1
+	mulai, akhir := RentangTahunBuku(koperasi.TahunBukuMulai, tahunBuku)
+	tanggalJurnal := akhir.AddDate(0, 0, -5)
+
+	_, err := transaksiService.BuatTransaksi(koperasi.ID, pengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: tanggalJurnal,
+		Deskripsi:        "Pendapatan penjualan",
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: akun["1101"].ID, JumlahDebit: models.Rupiah(500000)},
+			{IDAkun: akun["4101"].ID, JumlahKredit: models.Rupiah(500000)},
+		},
+	})
+	require.NoError(t, err)
+	_, err = transaksiService.BuatTransaksi(koperasi.ID, pengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: tanggalJurnal,
+		Deskripsi:        "Pembayaran gaji",
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: akun["5101"].ID, JumlahDebit: models.Rupiah(200000)},
+			{IDAkun: akun["1101"].ID, JumlahKredit: models.Rupiah(200000)},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = periodeService.TutupTahunBuku(koperasi.ID, pengguna, &TutupTahunBukuRequest{TahunBuku: tahunBuku})
+	require.NoError(t, err)
+
+	// Jurnal penutupan menolkan pendapatan dan beban ke SHU Tahun Berjalan
+	var jurnal models.Transaksi
+	require.NoError(t, db.Where("id_koperasi = ? AND tipe_transaksi = ?", koperasi.ID, models.TipeTransaksiPenutupan).First(&jurnal).Error)
+	assert.Equal(t, models.Rupiah(500000), jurnal.TotalDebit)
+	assert.Equal(t, jurnal.TotalDebit, jurnal.TotalKredit)
+
+	for kode, harapan := range map[string]models.Uang{"4101": 0, "5101": 0, "3201": models.Rupiah(300000), "1101": models.Rupiah(300000)} {
+		saldo, err := akunService.HitungSaldoAkun(akun[kode].ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, harapan, saldo, "saldo akun %s", kode)
+	}
+
+	// Saldo awal tahun buku berikutnya dibaca dari jurnal dan snapshot saldo bulanan
+	saldoAwalKas, err := akunService.HitungSaldoAkun(akun["1101"].ID, akhir.AddDate(0, 0, 1).Format("2006-01-02"))
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(300000), saldoAwalKas)
+
+	// Laba rugi tahun yang sudah ditutup tetap menampilkan hasil usaha
+	labaRugi, err := laporanService.GenerateLaporanLabaRugi(koperasi.ID, mulai.Format("2006-01-02"), akhir.Format("2006-01-02"))
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(300000), labaRugi.LabaRugiBersih)
+
+	// Neraca menampilkan SHU yang sudah ditutup dan tetap seimbang
+	neraca, err := laporanService.GenerateLaporanPosisiKeuangan(koperasi.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, neraca.TotalAset, neraca.TotalKewajiban+neraca.TotalModal)
+
+	// Tahun buku yang sudah dikunci tidak dapat dibuatkan ulang jurnal penutupannya
+	_, err = periodeService.BuatJurnalPenutupan(koperasi.ID, pengguna, tahunBuku)
+	assert.ErrorIs(t, err, ErrPeriodeDitutup)
+}
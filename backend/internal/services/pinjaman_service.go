@@ -0,0 +1,707 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PinjamanService menangani logika bisnis produk pinjaman dan pinjaman anggota
+type PinjamanService struct {
+	db               *gorm.DB
+	transaksiService *TransaksiService
+}
+
+// NewPinjamanService membuat instance baru PinjamanService
+func NewPinjamanService(db *gorm.DB, transaksiService *TransaksiService) *PinjamanService {
+	return &PinjamanService{
+		db:               db,
+		transaksiService: transaksiService,
+	}
+}
+
+// BuatProdukPinjamanRequest adalah struktur request untuk membuat/memperbarui produk pinjaman
+type BuatProdukPinjamanRequest struct {
+	KodeProduk       string             `json:"kodeProduk" binding:"required"`
+	NamaProduk       string             `json:"namaProduk" binding:"required"`
+	Deskripsi        string             `json:"deskripsi"`
+	MetodeBunga      models.MetodeBunga `json:"metodeBunga" binding:"required"`
+	SukuBungaTahunan float64            `json:"sukuBungaTahunan"`
+	TenorMinimal     int                `json:"tenorMinimal"`
+	TenorMaksimal    int                `json:"tenorMaksimal" binding:"required,gt=0"`
+	PlafonMaksimal   models.Uang        `json:"plafonMaksimal" binding:"required,gt=0"`
+	StatusAktif      *bool              `json:"statusAktif"`
+}
+
+// CairkanPinjamanRequest adalah struktur request untuk pencairan pinjaman anggota
+type CairkanPinjamanRequest struct {
+	IDAnggota        uuid.UUID   `json:"idAnggota" binding:"required"`
+	IDProdukPinjaman uuid.UUID   `json:"idProdukPinjaman" binding:"required"`
+	TanggalPencairan time.Time   `json:"tanggalPencairan" binding:"required"`
+	JumlahPokok      models.Uang `json:"jumlahPokok" binding:"required,gt=0"`
+	TenorBulan       int         `json:"tenorBulan" binding:"required,gt=0"`
+	Keterangan       string      `json:"keterangan"`
+}
+
+// BayarAngsuranRequest adalah struktur request untuk pembayaran angsuran
+type BayarAngsuranRequest struct {
+	TanggalBayar time.Time `json:"tanggalBayar" binding:"required"`
+}
+
+// validasiProdukPinjaman memvalidasi request produk pinjaman
+func validasiProdukPinjaman(req *BuatProdukPinjamanRequest) error {
+	validator := validasi.Baru()
+
+	if err := validator.TeksWajib(req.KodeProduk, "kode produk", 1, 20); err != nil {
+		return err
+	}
+	if err := validator.TeksWajib(req.NamaProduk, "nama produk", 3, 255); err != nil {
+		return err
+	}
+	if err := validator.TeksOpsional(req.Deskripsi, "deskripsi", 1000); err != nil {
+		return err
+	}
+	if err := validator.Enum(string(req.MetodeBunga), "metode bunga",
+		[]string{string(models.BungaFlat), string(models.BungaEfektif), string(models.BungaAnuitas)}); err != nil {
+		return err
+	}
+	if err := validator.Persentase(req.SukuBungaTahunan, "suku bunga tahunan"); err != nil {
+		return err
+	}
+	if err := validator.Jumlah(req.PlafonMaksimal.Float64(), "plafon maksimal"); err != nil {
+		return err
+	}
+
+	if req.TenorMinimal <= 0 {
+		req.TenorMinimal = 1
+	}
+	if req.TenorMaksimal < req.TenorMinimal {
+		return errors.New("tenor maksimal tidak boleh lebih kecil dari tenor minimal")
+	}
+	if req.TenorMaksimal > 360 {
+		return errors.New("tenor maksimal tidak boleh lebih dari 360 bulan")
+	}
+
+	return nil
+}
+
+// BuatProdukPinjaman membuat produk pinjaman baru
+func (s *PinjamanService) BuatProdukPinjaman(idKoperasi uuid.UUID, req *BuatProdukPinjamanRequest) (*models.ProdukPinjamanResponse, error) {
+	if err := validasiProdukPinjaman(req); err != nil {
+		return nil, err
+	}
+
+	// Validasi kode produk unik per koperasi
+	var count int64
+	s.db.Model(&models.ProdukPinjaman{}).
+		Where("id_koperasi = ? AND kode_produk = ?", idKoperasi, req.KodeProduk).
+		Count(&count)
+	if count > 0 {
+		return nil, errors.New("kode produk pinjaman sudah digunakan")
+	}
+
+	produk := &models.ProdukPinjaman{
+		IDKoperasi:       idKoperasi,
+		KodeProduk:       req.KodeProduk,
+		NamaProduk:       req.NamaProduk,
+		Deskripsi:        req.Deskripsi,
+		MetodeBunga:      req.MetodeBunga,
+		SukuBungaTahunan: req.SukuBungaTahunan,
+		TenorMinimal:     req.TenorMinimal,
+		TenorMaksimal:    req.TenorMaksimal,
+		PlafonMaksimal:   req.PlafonMaksimal,
+		StatusAktif:      true,
+	}
+
+	if err := s.db.Create(produk).Error; err != nil {
+		return nil, errors.New("gagal membuat produk pinjaman")
+	}
+
+	response := produk.ToResponse()
+	return &response, nil
+}
+
+// PerbaruiProdukPinjaman memperbarui produk pinjaman.
+// Perubahan tidak mempengaruhi pinjaman yang sudah dicairkan karena
+// metode dan suku bunga disalin ke pinjaman saat pencairan.
+func (s *PinjamanService) PerbaruiProdukPinjaman(id, idKoperasi uuid.UUID, req *BuatProdukPinjamanRequest) (*models.ProdukPinjamanResponse, error) {
+	if err := validasiProdukPinjaman(req); err != nil {
+		return nil, err
+	}
+
+	var produk models.ProdukPinjaman
+	if err := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&produk).Error; err != nil {
+		return nil, errors.New("produk pinjaman tidak ditemukan")
+	}
+
+	if req.KodeProduk != produk.KodeProduk {
+		var count int64
+		s.db.Model(&models.ProdukPinjaman{}).
+			Where("id_koperasi = ? AND kode_produk = ? AND id != ?", idKoperasi, req.KodeProduk, id).
+			Count(&count)
+		if count > 0 {
+			return nil, errors.New("kode produk pinjaman sudah digunakan")
+		}
+	}
+
+	produk.KodeProduk = req.KodeProduk
+	produk.NamaProduk = req.NamaProduk
+	produk.Deskripsi = req.Deskripsi
+	produk.MetodeBunga = req.MetodeBunga
+	produk.SukuBungaTahunan = req.SukuBungaTahunan
+	produk.TenorMinimal = req.TenorMinimal
+	produk.TenorMaksimal = req.TenorMaksimal
+	produk.PlafonMaksimal = req.PlafonMaksimal
+	if req.StatusAktif != nil {
+		produk.StatusAktif = *req.StatusAktif
+	}
+
+	if err := s.db.Save(&produk).Error; err != nil {
+		return nil, errors.New("gagal memperbarui produk pinjaman")
+	}
+
+	response := produk.ToResponse()
+	return &response, nil
+}
+
+// DapatkanSemuaProdukPinjaman mengambil daftar produk pinjaman koperasi
+func (s *PinjamanService) DapatkanSemuaProdukPinjaman(idKoperasi uuid.UUID, hanyaAktif bool) ([]models.ProdukPinjamanResponse, error) {
+	var produkList []models.ProdukPinjaman
+
+	query := s.db.Where("id_koperasi = ?", idKoperasi)
+	if hanyaAktif {
+		query = query.Where("status_aktif = ?", true)
+	}
+
+	if err := query.Order("kode_produk ASC").Find(&produkList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar produk pinjaman")
+	}
+
+	responses := make([]models.ProdukPinjamanResponse, len(produkList))
+	for i := range produkList {
+		responses[i] = produkList[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// SimulasiJadwalAngsuran menghitung jadwal angsuran tanpa menyimpan pinjaman
+func (s *PinjamanService) SimulasiJadwalAngsuran(idKoperasi, idProdukPinjaman uuid.UUID, jumlahPokok models.Uang, tenorBulan int, tanggalPencairan time.Time) ([]models.JadwalAngsuranResponse, error) {
+	var produk models.ProdukPinjaman
+	if err := s.db.Where("id = ? AND id_koperasi = ?", idProdukPinjaman, idKoperasi).First(&produk).Error; err != nil {
+		return nil, errors.New("produk pinjaman tidak ditemukan")
+	}
+
+	if err := validasiPengajuanPinjaman(&produk, jumlahPokok, tenorBulan); err != nil {
+		return nil, err
+	}
+
+	jadwal := HitungJadwalAngsuran(produk.MetodeBunga, jumlahPokok, produk.SukuBungaTahunan, tenorBulan, tanggalPencairan)
+
+	responses := make([]models.JadwalAngsuranResponse, len(jadwal))
+	for i := range jadwal {
+		responses[i] = jadwal[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// validasiPengajuanPinjaman memvalidasi jumlah dan tenor terhadap ketentuan produk
+func validasiPengajuanPinjaman(produk *models.ProdukPinjaman, jumlahPokok models.Uang, tenorBulan int) error {
+	validator := validasi.Baru()
+
+	if err := validator.Jumlah(jumlahPokok.Float64(), "jumlah pokok"); err != nil {
+		return err
+	}
+	if jumlahPokok > produk.PlafonMaksimal {
+		return fmt.Errorf("jumlah pokok melebihi plafon produk (maksimal %s)", produk.PlafonMaksimal)
+	}
+	if tenorBulan < produk.TenorMinimal || tenorBulan > produk.TenorMaksimal {
+		return fmt.Errorf("tenor harus antara %d dan %d bulan", produk.TenorMinimal, produk.TenorMaksimal)
+	}
+
+	return nil
+}
+
+// CairkanPinjaman mencatat pinjaman anggota, membuat jadwal angsuran dan
+// memposting jurnal pencairan dalam satu transaction
+func (s *PinjamanService) CairkanPinjaman(idKoperasi, idPengguna uuid.UUID, req *CairkanPinjamanRequest) (*models.PinjamanResponse, error) {
+	validator := validasi.Baru()
+
+	if err := validator.TanggalTransaksi(req.TanggalPencairan); err != nil {
+		return nil, err
+	}
+	if err := validator.TeksOpsional(req.Keterangan, "keterangan", 500); err != nil {
+		return nil, err
+	}
+
+	// Validasi anggota exists dan aktif
+	var anggota models.Anggota
+	err := s.db.Where("id = ? AND id_koperasi = ? AND status = ?", req.IDAnggota, idKoperasi, models.StatusAktif).
+		First(&anggota).Error
+	if err != nil {
+		return nil, errors.New("anggota tidak ditemukan atau tidak aktif")
+	}
+
+	// Validasi produk pinjaman aktif
+	var produk models.ProdukPinjaman
+	err = s.db.Where("id = ? AND id_koperasi = ? AND status_aktif = ?", req.IDProdukPinjaman, idKoperasi, true).
+		First(&produk).Error
+	if err != nil {
+		return nil, errors.New("produk pinjaman tidak ditemukan atau tidak aktif")
+	}
+
+	if err := validasiPengajuanPinjaman(&produk, req.JumlahPokok, req.TenorBulan); err != nil {
+		return nil, err
+	}
+
+	pinjaman := &models.Pinjaman{
+		IDKoperasi:       idKoperasi,
+		IDAnggota:        req.IDAnggota,
+		IDProdukPinjaman: produk.ID,
+		TanggalPencairan: req.TanggalPencairan,
+		JumlahPokok:      req.JumlahPokok,
+		MetodeBunga:      produk.MetodeBunga,
+		SukuBungaTahunan: produk.SukuBungaTahunan,
+		TenorBulan:       req.TenorBulan,
+		SisaPokok:        req.JumlahPokok,
+		Status:           models.StatusPinjamanAktif,
+		Keterangan:       req.Keterangan,
+		DibuatOleh:       idPengguna,
+	}
+
+	// Simpan pinjaman, jadwal angsuran dan jurnal pencairan secara atomik
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Step 1: Generate nomor pinjaman di dalam transaction
+		nomorPinjaman, genErr := s.generateNomorPinjamanInTx(tx, idKoperasi, req.TanggalPencairan)
+		if genErr != nil {
+			return genErr
+		}
+		pinjaman.NomorPinjaman = nomorPinjaman
+
+		// Step 2: Simpan record pinjaman
+		if createErr := tx.Create(pinjaman).Error; createErr != nil {
+			return errors.New("gagal mencatat pinjaman")
+		}
+
+		// Step 3: Simpan jadwal angsuran
+		jadwal := HitungJadwalAngsuran(pinjaman.MetodeBunga, pinjaman.JumlahPokok, pinjaman.SukuBungaTahunan, pinjaman.TenorBulan, pinjaman.TanggalPencairan)
+		for i := range jadwal {
+			jadwal[i].IDPinjaman = pinjaman.ID
+			jadwal[i].IDKoperasi = idKoperasi
+		}
+		if createErr := tx.Create(&jadwal).Error; createErr != nil {
+			return errors.New("gagal membuat jadwal angsuran")
+		}
+
+		// Step 4: Posting otomatis jurnal pencairan dalam transaction yang sama
+		if postErr := s.transaksiService.PostingOtomatisPencairanPinjamanWithTx(tx, idKoperasi, idPengguna, pinjaman.ID); postErr != nil {
+			return fmt.Errorf("gagal posting ke jurnal: %w", postErr)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanPinjaman(pinjaman.ID, idKoperasi)
+}
+
+// BayarAngsuran membayar angsuran berikutnya yang belum lunas dan memposting jurnalnya.
+// Baris pinjaman dikunci selama proses agar pembayaran ganda yang bersamaan tidak
+// melunasi angsuran yang sama dua kali.
+func (s *PinjamanService) BayarAngsuran(idKoperasi, idPengguna, idPinjaman uuid.UUID, req *BayarAngsuranRequest) (*models.JadwalAngsuranResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TanggalTransaksi(req.TanggalBayar); err != nil {
+		return nil, err
+	}
+
+	var jadwal models.JadwalAngsuran
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci pinjaman agar pembayaran diproses satu per satu
+		var pinjaman models.Pinjaman
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", idPinjaman, idKoperasi).
+			First(&pinjaman).Error
+		if lockErr != nil {
+			return errors.New("pinjaman tidak ditemukan")
+		}
+
+		if pinjaman.Status == models.StatusPinjamanLunas {
+			return errors.New("pinjaman sudah lunas")
+		}
+		if pinjaman.Status == models.StatusPinjamanBatal {
+			return errors.New("pinjaman sudah dibatalkan")
+		}
+
+		if req.TanggalBayar.Before(pinjaman.TanggalPencairan) {
+			return errors.New("tanggal bayar tidak boleh sebelum tanggal pencairan")
+		}
+
+		// Ambil angsuran berikutnya yang belum lunas
+		findErr := tx.Where("id_pinjaman = ? AND status_lunas = ?", pinjaman.ID, false).
+			Order("angsuran_ke ASC").
+			First(&jadwal).Error
+		if findErr != nil {
+			return errors.New("tidak ada angsuran yang belum dibayar")
+		}
+
+		// Posting otomatis jurnal angsuran dalam transaction yang sama
+		if postErr := s.transaksiService.PostingOtomatisAngsuranWithTx(tx, idKoperasi, idPengguna, jadwal.ID, req.TanggalBayar); postErr != nil {
+			return fmt.Errorf("gagal posting ke jurnal: %w", postErr)
+		}
+
+		// Tandai angsuran lunas
+		tanggalBayar := req.TanggalBayar
+		if updateErr := tx.Model(&jadwal).Updates(map[string]interface{}{
+			"status_lunas":  true,
+			"tanggal_bayar": tanggalBayar,
+		}).Error; updateErr != nil {
+			return errors.New("gagal memperbarui jadwal angsuran")
+		}
+
+		// Perbarui sisa pokok dan status pinjaman
+		pinjaman.SisaPokok = jadwal.SisaPokok
+		if pinjaman.SisaPokok <= 0 {
+			pinjaman.SisaPokok = 0
+			pinjaman.Status = models.StatusPinjamanLunas
+		}
+		if updateErr := tx.Model(&pinjaman).Updates(map[string]interface{}{
+			"sisa_pokok": pinjaman.SisaPokok,
+			"status":     pinjaman.Status,
+		}).Error; updateErr != nil {
+			return errors.New("gagal memperbarui pinjaman")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.First(&jadwal, "id = ?", jadwal.ID)
+
+	response := jadwal.ToResponse()
+	return &response, nil
+}
+
+// BatalkanPinjamanRequest adalah struktur request untuk membatalkan pencairan atau angsuran pinjaman
+type BatalkanPinjamanRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// BatalkanPencairan membatalkan (void) pencairan pinjaman yang belum pernah diangsur.
+//
+// Pinjaman tidak dihapus: jurnal pencairannya dibalik dengan jurnal pembalik tertanggal hari ini
+// dan pinjaman ditandai BATAL sehingga tidak dapat diangsur lagi. Angsuran yang sudah dibayar
+// harus dibatalkan lebih dulu melalui BatalkanAngsuran.
+func (s *PinjamanService) BatalkanPencairan(idKoperasi, idPengguna, idPinjaman uuid.UUID, req *BatalkanPinjamanRequest) (*models.PinjamanResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci pinjaman agar tidak bersamaan dengan pembayaran angsuran
+		var pinjaman models.Pinjaman
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", idPinjaman, idKoperasi).
+			First(&pinjaman).Error
+		if lockErr != nil {
+			return errors.New("pinjaman tidak ditemukan")
+		}
+
+		if pinjaman.Status == models.StatusPinjamanBatal {
+			return errors.New("pinjaman sudah dibatalkan")
+		}
+
+		var jumlahLunas int64
+		if countErr := tx.Model(&models.JadwalAngsuran{}).
+			Where("id_pinjaman = ? AND status_lunas = ?", pinjaman.ID, true).
+			Count(&jumlahLunas).Error; countErr != nil {
+			return errors.New("gagal memeriksa jadwal angsuran")
+		}
+		if jumlahLunas > 0 {
+			return errors.New("pinjaman yang sudah diangsur tidak dapat dibatalkan, batalkan angsurannya terlebih dahulu")
+		}
+
+		// Balik jurnal pencairan dalam transaction yang sama
+		sekarang := time.Now()
+		if pinjaman.IDTransaksi != nil {
+			if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *pinjaman.IDTransaksi,
+				sekarang, req.Alasan, models.TipeTransaksiPinjaman); balikErr != nil {
+				return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+			}
+		}
+
+		if updateErr := tx.Model(&pinjaman).Updates(map[string]interface{}{
+			"status":             models.StatusPinjamanBatal,
+			"sisa_pokok":         0,
+			"tanggal_dibatalkan": sekarang,
+			"dibatalkan_oleh":    idPengguna,
+			"alasan_pembatalan":  req.Alasan,
+		}).Error; updateErr != nil {
+			return errors.New("gagal membatalkan pinjaman")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanPinjaman(idPinjaman, idKoperasi)
+}
+
+// BatalkanAngsuran membatalkan (void) pembayaran angsuran terakhir yang sudah lunas.
+//
+// Jurnal angsuran dibalik dengan jurnal pembalik tertanggal hari ini, angsuran kembali
+// berstatus belum lunas dan sisa pokok pinjaman dikembalikan ke posisi sebelum pembayaran.
+// Hanya angsuran terakhir yang dapat dibatalkan agar urutan pembayaran tetap utuh.
+func (s *PinjamanService) BatalkanAngsuran(idKoperasi, idPengguna, idPinjaman uuid.UUID, req *BatalkanPinjamanRequest) (*models.JadwalAngsuranResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	var jadwal models.JadwalAngsuran
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci pinjaman agar tidak bersamaan dengan pembayaran angsuran
+		var pinjaman models.Pinjaman
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", idPinjaman, idKoperasi).
+			First(&pinjaman).Error
+		if lockErr != nil {
+			return errors.New("pinjaman tidak ditemukan")
+		}
+
+		if pinjaman.Status == models.StatusPinjamanBatal {
+			return errors.New("pinjaman sudah dibatalkan")
+		}
+
+		// Ambil angsuran terakhir yang sudah lunas
+		findErr := tx.Where("id_pinjaman = ? AND status_lunas = ?", pinjaman.ID, true).
+			Order("angsuran_ke DESC").
+			First(&jadwal).Error
+		if findErr != nil {
+			return errors.New("tidak ada angsuran yang sudah dibayar")
+		}
+
+		// Balik jurnal angsuran dalam transaction yang sama
+		if jadwal.IDTransaksi != nil {
+			if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *jadwal.IDTransaksi,
+				time.Now(), req.Alasan, models.TipeTransaksiPinjaman); balikErr != nil {
+				return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+			}
+		}
+
+		// Kembalikan angsuran ke status belum lunas
+		if updateErr := tx.Model(&jadwal).Updates(map[string]interface{}{
+			"status_lunas":  false,
+			"tanggal_bayar": nil,
+			"id_transaksi":  nil,
+		}).Error; updateErr != nil {
+			return errors.New("gagal memperbarui jadwal angsuran")
+		}
+
+		// Sisa pokok sebelum angsuran ini dibayar
+		if updateErr := tx.Model(&pinjaman).Updates(map[string]interface{}{
+			"sisa_pokok": jadwal.SisaPokok + jadwal.Pokok,
+			"status":     models.StatusPinjamanAktif,
+		}).Error; updateErr != nil {
+			return errors.New("gagal memperbarui pinjaman")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.First(&jadwal, "id = ?", jadwal.ID)
+
+	response := jadwal.ToResponse()
+	return &response, nil
+}
+
+// DapatkanPinjaman mengambil detail pinjaman beserta jadwal angsurannya
+func (s *PinjamanService) DapatkanPinjaman(id, idKoperasi uuid.UUID) (*models.PinjamanResponse, error) {
+	var pinjaman models.Pinjaman
+	err := s.db.Preload("Anggota").
+		Preload("ProdukPinjaman").
+		Preload("JadwalAngsuran", func(db *gorm.DB) *gorm.DB {
+			return db.Order("angsuran_ke ASC")
+		}).
+		Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+		First(&pinjaman).Error
+
+	if err != nil {
+		return nil, errors.New("pinjaman tidak ditemukan")
+	}
+
+	response := pinjaman.ToResponse()
+	return &response, nil
+}
+
+// DapatkanSemuaPinjaman mengambil daftar pinjaman dengan filter dan pagination
+func (s *PinjamanService) DapatkanSemuaPinjaman(idKoperasi uuid.UUID, status string, idAnggota *uuid.UUID, page, pageSize int) ([]models.PinjamanResponse, int64, error) {
+	var pinjamanList []models.Pinjaman
+	var total int64
+
+	query := s.db.Model(&models.Pinjaman{}).Where("id_koperasi = ?", idKoperasi)
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if idAnggota != nil {
+		query = query.Where("id_anggota = ?", *idAnggota)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).
+		Order("tanggal_pencairan DESC, nomor_pinjaman DESC").
+		Preload("Anggota").
+		Preload("ProdukPinjaman").
+		Find(&pinjamanList).Error
+
+	if err != nil {
+		return nil, 0, errors.New("gagal mengambil daftar pinjaman")
+	}
+
+	responses := make([]models.PinjamanResponse, len(pinjamanList))
+	for i := range pinjamanList {
+		responses[i] = pinjamanList[i].ToResponse()
+	}
+
+	return responses, total, nil
+}
+
+// generateNomorPinjamanInTx menghasilkan nomor pinjaman di dalam transaction
+// Format: PJM-YYYYMMDD-NNNN
+func (s *PinjamanService) generateNomorPinjamanInTx(tx *gorm.DB, idKoperasi uuid.UUID, tanggal time.Time) (string, error) {
+	tanggalStr := tanggal.Format("20060102")
+
+	// Advisory lock per koperasi+tanggal mencegah nomor ganda saat request bersamaan
+	lockKey := generateAdvisoryLockKey(idKoperasi, "PJM"+tanggalStr)
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockKey).Error; err != nil {
+		return "", errors.New("gagal generate nomor pinjaman")
+	}
+
+	var lastPinjaman models.Pinjaman
+	queryErr := tx.Unscoped().
+		Where("id_koperasi = ? AND nomor_pinjaman LIKE ?", idKoperasi, "PJM-"+tanggalStr+"-%").
+		Order("nomor_pinjaman DESC").
+		First(&lastPinjaman).Error
+
+	nomorUrut := 1
+	if queryErr == nil {
+		var parsedUrut int
+		if _, scanErr := fmt.Sscanf(lastPinjaman.NomorPinjaman, "PJM-"+tanggalStr+"-%04d", &parsedUrut); scanErr == nil {
+			nomorUrut = parsedUrut + 1
+		}
+	} else if !errors.Is(queryErr, gorm.ErrRecordNotFound) {
+		return "", errors.New("gagal generate nomor pinjaman")
+	}
+
+	return fmt.Sprintf("PJM-%s-%04d", tanggalStr, nomorUrut), nil
+}
+
+// HitungJadwalAngsuran menghitung jadwal angsuran bulanan untuk satu pinjaman.
+//
+// Metode bunga:
+//   - FLAT: bunga per bulan = pokok awal x bunga bulanan, pokok dibagi rata
+//   - EFEKTIF: bunga per bulan = sisa pokok x bunga bulanan, pokok dibagi rata
+//   - ANUITAS: total angsuran tetap, porsi bunga = sisa pokok x bunga bulanan
+//
+// Semua nilai dibulatkan ke sen. Selisih pembulatan pokok diserap oleh angsuran
+// terakhir sehingga total pokok tepat sama dengan jumlah pinjaman.
+func HitungJadwalAngsuran(metode models.MetodeBunga, jumlahPokok models.Uang, sukuBungaTahunan float64, tenorBulan int, tanggalPencairan time.Time) []models.JadwalAngsuran {
+	if tenorBulan <= 0 || jumlahPokok <= 0 {
+		return []models.JadwalAngsuran{}
+	}
+
+	// Bunga bulanan = nominal x (suku bunga tahunan / 100 / 12), dihitung eksak dalam basis poin
+	basisPoinTahunan := int64(math.Round(sukuBungaTahunan * 100))
+	bungaDari := func(nominal models.Uang) models.Uang {
+		return nominal.KaliPecahan(basisPoinTahunan, 12*10000)
+	}
+	pokokRata := jumlahPokok.KaliPecahan(1, int64(tenorBulan))
+
+	var angsuranAnuitas models.Uang
+	if metode == models.BungaAnuitas {
+		if basisPoinTahunan > 0 {
+			// Rumus anuitas memerlukan pangkat pecahan; hasilnya dibulatkan ke sen
+			bungaBulanan := sukuBungaTahunan / 100 / 12
+			angsuranAnuitas = models.UangDariFloat(jumlahPokok.Float64() * bungaBulanan / (1 - math.Pow(1+bungaBulanan, -float64(tenorBulan))))
+		} else {
+			angsuranAnuitas = pokokRata
+		}
+	}
+
+	jadwal := make([]models.JadwalAngsuran, tenorBulan)
+	sisaPokok := jumlahPokok
+
+	for i := 0; i < tenorBulan; i++ {
+		var pokok, bunga models.Uang
+
+		switch metode {
+		case models.BungaFlat:
+			bunga = bungaDari(jumlahPokok)
+			pokok = pokokRata
+		case models.BungaAnuitas:
+			bunga = bungaDari(sisaPokok)
+			pokok = angsuranAnuitas - bunga
+		default: // EFEKTIF
+			bunga = bungaDari(sisaPokok)
+			pokok = pokokRata
+		}
+
+		// Angsuran terakhir melunasi seluruh sisa pokok
+		if i == tenorBulan-1 || pokok > sisaPokok {
+			pokok = sisaPokok
+		}
+
+		sisaPokok -= pokok
+
+		jadwal[i] = models.JadwalAngsuran{
+			AngsuranKe:        i + 1,
+			TanggalJatuhTempo: tambahBulan(tanggalPencairan, i+1),
+			Pokok:             pokok,
+			Bunga:             bunga,
+			TotalAngsuran:     pokok + bunga,
+			SisaPokok:         sisaPokok,
+		}
+	}
+
+	return jadwal
+}
+
+// tambahBulan menambahkan n bulan ke tanggal tanpa melewati akhir bulan
+// (misalnya 31 Januari + 1 bulan = 28/29 Februari, bukan 2/3 Maret)
+func tambahBulan(tanggal time.Time, n int) time.Time {
+	awalBulan := time.Date(tanggal.Year(), tanggal.Month(), 1, 0, 0, 0, 0, tanggal.Location()).AddDate(0, n, 0)
+	hariTerakhir := awalBulan.AddDate(0, 1, -1).Day()
+
+	hari := tanggal.Day()
+	if hari > hariTerakhir {
+		hari = hariTerakhir
+	}
+
+	return time.Date(awalBulan.Year(), awalBulan.Month(), hari, 0, 0, 0, 0, tanggal.Location())
+}
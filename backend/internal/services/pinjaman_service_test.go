@@ -0,0 +1,263 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupPinjamanTestDB creates a test database for pinjaman service
+func setupPinjamanTestDB(t *testing.T) *gorm.DB {
+	dsn := "host=localhost user=postgres password=postgres dbname=koperasi_erp_test port=5432 sslmode=disable TimeZone=Asia/Jakarta"
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Skipf("Skipping test: cannot connect to test database: %v", err)
+		return nil
+	}
+
+	err = db.AutoMigrate(
+		&models.Koperasi{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+		&models.Anggota{},
+		&models.ProdukPinjaman{},
+		&models.Pinjaman{},
+		&models.JadwalAngsuran{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	db.Exec("TRUNCATE TABLE jadwal_angsuran CASCADE")
+	db.Exec("TRUNCATE TABLE pinjaman CASCADE")
+	db.Exec("TRUNCATE TABLE produk_pinjaman CASCADE")
+
+	return db
+}
+
+// totalPokokJadwal menjumlahkan porsi pokok seluruh angsuran
+func totalPokokJadwal(jadwal []models.JadwalAngsuran) models.Uang {
+	var total models.Uang
+	for _, j := range jadwal {
+		total += j.Pokok
+	}
+	return total
+}
+
+func TestHitungJadwalAngsuran_Flat(t *testing.T) {
+	tanggal := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	jadwal := HitungJadwalAngsuran(models.BungaFlat, models.Rupiah(12000000), 12, 12, tanggal)
+
+	require.Len(t, jadwal, 12)
+	for _, j := range jadwal {
+		assert.Equal(t, models.Rupiah(1000000), j.Pokok)
+		assert.Equal(t, models.Rupiah(120000), j.Bunga)
+		assert.Equal(t, models.Rupiah(1120000), j.TotalAngsuran)
+	}
+	assert.Equal(t, models.Uang(0), jadwal[11].SisaPokok)
+	assert.Equal(t, time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), jadwal[0].TanggalJatuhTempo)
+	assert.Equal(t, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), jadwal[11].TanggalJatuhTempo)
+}
+
+func TestHitungJadwalAngsuran_Efektif(t *testing.T) {
+	tanggal := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	jadwal := HitungJadwalAngsuran(models.BungaEfektif, models.Rupiah(12000000), 12, 12, tanggal)
+
+	require.Len(t, jadwal, 12)
+	// Bunga bulan pertama dari pokok penuh, bulan terakhir dari sisa satu angsuran pokok
+	assert.Equal(t, models.Rupiah(120000), jadwal[0].Bunga)
+	assert.Equal(t, models.Rupiah(10000), jadwal[11].Bunga)
+	for i := 1; i < len(jadwal); i++ {
+		assert.Less(t, jadwal[i].Bunga, jadwal[i-1].Bunga)
+	}
+	assert.Equal(t, models.Rupiah(12000000), totalPokokJadwal(jadwal))
+}
+
+func TestHitungJadwalAngsuran_Anuitas(t *testing.T) {
+	tanggal := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	jadwal := HitungJadwalAngsuran(models.BungaAnuitas, models.Rupiah(10000000), 12, 12, tanggal)
+
+	require.Len(t, jadwal, 12)
+	// Angsuran anuitas 10 juta, 1% per bulan, 12 bulan = 888.487,89
+	for i := 0; i < 11; i++ {
+		assert.Equal(t, mustUang("888487.89"), jadwal[i].TotalAngsuran)
+	}
+	// Angsuran terakhir menyerap selisih pembulatan
+	assert.LessOrEqual(t, (jadwal[11].TotalAngsuran - mustUang("888487.89")).Abs(), mustUang("0.10"))
+	assert.Equal(t, models.Rupiah(10000000), totalPokokJadwal(jadwal))
+	assert.Equal(t, models.Uang(0), jadwal[11].SisaPokok)
+}
+
+func TestHitungJadwalAngsuran_PembulatanPokok(t *testing.T) {
+	tanggal := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	jadwal := HitungJadwalAngsuran(models.BungaFlat, models.Rupiah(1000000), 0, 3, tanggal)
+
+	require.Len(t, jadwal, 3)
+	assert.Equal(t, mustUang("333333.33"), jadwal[0].Pokok)
+	assert.Equal(t, mustUang("333333.34"), jadwal[2].Pokok)
+	assert.Equal(t, models.Rupiah(1000000), totalPokokJadwal(jadwal))
+}
+
+func TestTambahBulan_AkhirBulan(t *testing.T) {
+	tanggal := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), tambahBulan(tanggal, 1))
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), tambahBulan(tanggal, 2))
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), tambahBulan(tanggal, 13))
+}
+
+func TestCairkanDanBayarAngsuran(t *testing.T) {
+	db := setupPinjamanTestDB(t)
+	if db == nil {
+		return
+	}
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test Pinjaman", Email: "pinjaman@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	transaksiService := NewTransaksiService(db)
+	service := NewPinjamanService(db, transaksiService)
+	pengguna := uuid.New()
+
+	anggota := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "PJM-001", NamaLengkap: "Peminjam", TanggalBergabung: time.Now()}
+	db.Create(anggota)
+
+	produk, err := service.BuatProdukPinjaman(koperasi.ID, &BuatProdukPinjamanRequest{
+		KodeProduk: "PJ-REG", NamaProduk: "Pinjaman Reguler", MetodeBunga: models.BungaFlat,
+		SukuBungaTahunan: 12, TenorMinimal: 1, TenorMaksimal: 24, PlafonMaksimal: models.Rupiah(50000000),
+	})
+	require.NoError(t, err)
+
+	t.Run("melebihi plafon ditolak", func(t *testing.T) {
+		_, err := service.CairkanPinjaman(koperasi.ID, pengguna, &CairkanPinjamanRequest{
+			IDAnggota: anggota.ID, IDProdukPinjaman: produk.ID, TanggalPencairan: time.Now(),
+			JumlahPokok: models.Rupiah(60000000), TenorBulan: 12,
+		})
+		assert.Error(t, err)
+	})
+
+	pinjaman, err := service.CairkanPinjaman(koperasi.ID, pengguna, &CairkanPinjamanRequest{
+		IDAnggota: anggota.ID, IDProdukPinjaman: produk.ID, TanggalPencairan: time.Now(),
+		JumlahPokok: models.Rupiah(1200000), TenorBulan: 2,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, pinjaman.IDTransaksi)
+	assert.Len(t, pinjaman.JadwalAngsuran, 2)
+	assert.Equal(t, models.Rupiah(1200000), pinjaman.SisaPokok)
+
+	// Jurnal pencairan: Dr Piutang Pinjaman, Cr Kas
+	var jurnal models.Transaksi
+	require.NoError(t, db.First(&jurnal, "id = ?", *pinjaman.IDTransaksi).Error)
+	assert.Equal(t, models.TipeTransaksiPinjaman, jurnal.TipeTransaksi)
+	assert.Equal(t, models.Rupiah(1200000), jurnal.TotalDebit)
+
+	angsuran, err := service.BayarAngsuran(koperasi.ID, pengguna, pinjaman.ID, &BayarAngsuranRequest{TanggalBayar: time.Now()})
+	require.NoError(t, err)
+	assert.Equal(t, 1, angsuran.AngsuranKe)
+	assert.True(t, angsuran.StatusLunas)
+	require.NotNil(t, angsuran.IDTransaksi)
+
+	_, err = service.BayarAngsuran(koperasi.ID, pengguna, pinjaman.ID, &BayarAngsuranRequest{TanggalBayar: time.Now()})
+	require.NoError(t, err)
+
+	detail, err := service.DapatkanPinjaman(pinjaman.ID, koperasi.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPinjamanLunas, detail.Status)
+	assert.Equal(t, models.Uang(0), detail.SisaPokok)
+
+	_, err = service.BayarAngsuran(koperasi.ID, pengguna, pinjaman.ID, &BayarAngsuranRequest{TanggalBayar: time.Now()})
+	assert.Error(t, err)
+}
+
+func TestBatalkanPencairanDanAngsuran(t *testing.T) {
+	db := setupPinjamanTestDB(t)
+	if db == nil {
+		return
+	}
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test Batal Pinjaman", Email: "batal-pinjaman@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	transaksiService := NewTransaksiService(db)
+	service := NewPinjamanService(db, transaksiService)
+	pengguna := uuid.New()
+
+	anggota := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "PJM-002", NamaLengkap: "Peminjam Batal", TanggalBergabung: time.Now()}
+	db.Create(anggota)
+
+	produk, err := service.BuatProdukPinjaman(koperasi.ID, &BuatProdukPinjamanRequest{
+		KodeProduk: "PJ-BTL", NamaProduk: "Pinjaman Batal", MetodeBunga: models.BungaEfektif,
+		SukuBungaTahunan: 12, TenorMinimal: 1, TenorMaksimal: 24, PlafonMaksimal: models.Rupiah(50000000),
+	})
+	require.NoError(t, err)
+
+	pinjaman, err := service.CairkanPinjaman(koperasi.ID, pengguna, &CairkanPinjamanRequest{
+		IDAnggota: anggota.ID, IDProdukPinjaman: produk.ID, TanggalPencairan: time.Now(),
+		JumlahPokok: models.Rupiah(1200000), TenorBulan: 3,
+	})
+	require.NoError(t, err)
+
+	angsuran, err := service.BayarAngsuran(koperasi.ID, pengguna, pinjaman.ID, &BayarAngsuranRequest{TanggalBayar: time.Now()})
+	require.NoError(t, err)
+
+	t.Run("pencairan yang sudah diangsur ditolak", func(t *testing.T) {
+		_, err := service.BatalkanPencairan(koperasi.ID, pengguna, pinjaman.ID, &BatalkanPinjamanRequest{Alasan: "Salah input anggota"})
+		assert.Error(t, err)
+	})
+
+	t.Run("pembatalan angsuran mengembalikan sisa pokok", func(t *testing.T) {
+		batal, err := service.BatalkanAngsuran(koperasi.ID, pengguna, pinjaman.ID, &BatalkanPinjamanRequest{Alasan: "Pembayaran ganda"})
+		require.NoError(t, err)
+		assert.Equal(t, angsuran.ID, batal.ID)
+		assert.False(t, batal.StatusLunas)
+		assert.Nil(t, batal.TanggalBayar)
+		assert.Nil(t, batal.IDTransaksi)
+
+		var jurnal models.Transaksi
+		require.NoError(t, db.First(&jurnal, "id = ?", *angsuran.IDTransaksi).Error)
+		assert.True(t, jurnal.Dibalik)
+
+		detail, err := service.DapatkanPinjaman(pinjaman.ID, koperasi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(1200000), detail.SisaPokok)
+		assert.Equal(t, models.StatusPinjamanAktif, detail.Status)
+
+		_, err = service.BatalkanAngsuran(koperasi.ID, pengguna, pinjaman.ID, &BatalkanPinjamanRequest{Alasan: "Pembayaran ganda"})
+		assert.Error(t, err)
+	})
+
+	t.Run("pembatalan pencairan membalik jurnal", func(t *testing.T) {
+		batal, err := service.BatalkanPencairan(koperasi.ID, pengguna, pinjaman.ID, &BatalkanPinjamanRequest{Alasan: "Salah input anggota"})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusPinjamanBatal, batal.Status)
+		assert.Equal(t, models.Uang(0), batal.SisaPokok)
+
+		var jurnal models.Transaksi
+		require.NoError(t, db.First(&jurnal, "id = ?", *pinjaman.IDTransaksi).Error)
+		assert.True(t, jurnal.Dibalik)
+
+		_, err = service.BayarAngsuran(koperasi.ID, pengguna, pinjaman.ID, &BayarAngsuranRequest{TanggalBayar: time.Now()})
+		assert.Error(t, err)
+	})
+}
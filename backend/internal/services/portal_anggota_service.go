@@ -94,20 +94,20 @@ func (s *PortalAnggotaService) GetInfoAnggota(idKoperasi, idAnggota uuid.UUID) (
 	return &response, nil
 }
 
-// GetSaldoAnggota mengambil saldo simpanan anggota
+// GetSaldoAnggota mengambil saldo bersih simpanan anggota (setoran dikurangi penarikan)
 func (s *PortalAnggotaService) GetSaldoAnggota(idKoperasi, idAnggota uuid.UUID) (*models.SaldoSimpananAnggota, error) {
 	var saldo models.SaldoSimpananAnggota
 
-	// Query untuk menghitung total simpanan per jenis
+	// Query untuk menghitung saldo bersih simpanan per jenis
 	err := s.db.Table("simpanan").
 		Select(`
 			? as id_anggota,
-			COALESCE(SUM(CASE WHEN tipe_simpanan = 'pokok' THEN jumlah_setoran ELSE 0 END), 0) as simpanan_pokok,
-			COALESCE(SUM(CASE WHEN tipe_simpanan = 'wajib' THEN jumlah_setoran ELSE 0 END), 0) as simpanan_wajib,
-			COALESCE(SUM(CASE WHEN tipe_simpanan = 'sukarela' THEN jumlah_setoran ELSE 0 END), 0) as simpanan_sukarela,
-			COALESCE(SUM(jumlah_setoran), 0) as total_simpanan
+			COALESCE(SUM(CASE WHEN tipe_simpanan = 'POKOK' THEN `+ekspresiJumlahBersihSimpanan+` ELSE 0 END), 0) as simpanan_pokok,
+			COALESCE(SUM(CASE WHEN tipe_simpanan = 'WAJIB' THEN `+ekspresiJumlahBersihSimpanan+` ELSE 0 END), 0) as simpanan_wajib,
+			COALESCE(SUM(CASE WHEN tipe_simpanan = 'SUKARELA' THEN `+ekspresiJumlahBersihSimpanan+` ELSE 0 END), 0) as simpanan_sukarela,
+			COALESCE(SUM(`+ekspresiJumlahBersihSimpanan+`), 0) as total_simpanan
 		`, idAnggota).
-		Where("id_koperasi = ? AND id_anggota = ? AND deleted_at IS NULL", idKoperasi, idAnggota).
+		Where("id_koperasi = ? AND id_anggota = ? AND tanggal_dihapus IS NULL", idKoperasi, idAnggota).
 		Scan(&saldo).Error
 
 	if err != nil {
@@ -132,7 +132,8 @@ type RiwayatTransaksiAnggota struct {
 	ID               uuid.UUID           `json:"id"`
 	TanggalTransaksi string              `json:"tanggalTransaksi"`
 	TipeSimpanan     models.TipeSimpanan `json:"tipeSimpanan"`
-	Jumlah           float64             `json:"jumlah"`
+	JenisTransaksi   string              `json:"jenisTransaksi"`
+	Jumlah           models.Uang         `json:"jumlah"` // Negatif untuk penarikan
 	Keterangan       string              `json:"keterangan"`
 	NomorReferensi   string              `json:"nomorReferensi"`
 }
@@ -144,7 +145,7 @@ func (s *PortalAnggotaService) GetRiwayatTransaksi(idKoperasi, idAnggota uuid.UU
 
 	// Query count
 	err := s.db.Table("simpanan").
-		Where("id_koperasi = ? AND id_anggota = ? AND deleted_at IS NULL", idKoperasi, idAnggota).
+		Where("id_koperasi = ? AND id_anggota = ? AND dibatalkan = ? AND tanggal_dihapus IS NULL", idKoperasi, idAnggota, false).
 		Count(&total).Error
 
 	if err != nil {
@@ -157,11 +158,12 @@ func (s *PortalAnggotaService) GetRiwayatTransaksi(idKoperasi, idAnggota uuid.UU
 			id,
 			TO_CHAR(tanggal_transaksi, 'YYYY-MM-DD') as tanggal_transaksi,
 			tipe_simpanan,
-			jumlah_setoran as jumlah,
+			jenis_transaksi,
+			`+ekspresiJumlahBersihSimpanan+` as jumlah,
 			keterangan,
 			nomor_referensi
 		`).
-		Where("id_koperasi = ? AND id_anggota = ? AND deleted_at IS NULL", idKoperasi, idAnggota).
+		Where("id_koperasi = ? AND id_anggota = ? AND dibatalkan = ? AND tanggal_dihapus IS NULL", idKoperasi, idAnggota, false).
 		Order("tanggal_transaksi DESC, tanggal_dibuat DESC").
 		Limit(limit).
 		Offset(offset).
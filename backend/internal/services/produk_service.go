@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/pkg/validasi"
 	"errors"
@@ -20,19 +21,29 @@ func NewProdukService(db *gorm.DB) *ProdukService {
 	return &ProdukService{db: db}
 }
 
+// DenganKonteks mengembalikan salinan service yang menjalankan query dengan ctx,
+// sehingga log audit mencatat pengguna, alamat IP dan request ID dari request tersebut
+func (s *ProdukService) DenganKonteks(ctx context.Context) *ProdukService {
+	salinan := *s
+	salinan.db = s.db.WithContext(ctx)
+	return &salinan
+}
+
 // BuatProdukRequest adalah struktur request untuk membuat produk
 type BuatProdukRequest struct {
-	KodeProduk  string  `json:"kodeProduk" binding:"required"`
-	NamaProduk  string  `json:"namaProduk" binding:"required"`
-	Kategori    string  `json:"kategori"`
-	Deskripsi   string  `json:"deskripsi"`
-	Harga       float64 `json:"harga" binding:"required,gte=0"`
-	HargaBeli   float64 `json:"hargaBeli" binding:"gte=0"`
-	Stok        int     `json:"stok"`
-	StokMinimum int     `json:"stokMinimum"`
-	Satuan      string  `json:"satuan"`
-	Barcode     string  `json:"barcode"`
-	GambarURL   string  `json:"gambarUrl"`
+	KodeProduk  string           `json:"kodeProduk" binding:"required"`
+	NamaProduk  string           `json:"namaProduk" binding:"required"`
+	Kategori    string           `json:"kategori"`
+	Deskripsi   string           `json:"deskripsi"`
+	Harga       models.Uang      `json:"harga" binding:"required,gte=0"`
+	HargaBeli   models.Uang      `json:"hargaBeli" binding:"gte=0"`
+	Stok        int              `json:"stok"`
+	StokMinimum int              `json:"stokMinimum"`
+	Satuan      string           `json:"satuan"`
+	Barcode     string           `json:"barcode"`
+	GambarURL   string           `json:"gambarUrl"`
+	IDUnitUsaha *uuid.UUID       `json:"idUnitUsaha"` // Opsional, unit usaha yang menjual produk
+	KodePajak   models.KodePajak `json:"kodePajak"`   // Opsional, default NON_PPN
 }
 
 // BuatProduk membuat produk baru
@@ -49,12 +60,12 @@ func (s *ProdukService) BuatProduk(idKoperasi uuid.UUID, req *BuatProdukRequest)
 		return nil, err
 	}
 
-	if err := validator.Jumlah(req.Harga, "harga"); err != nil {
+	if err := validator.Jumlah(req.Harga.Float64(), "harga"); err != nil {
 		return nil, err
 	}
 
 	if req.HargaBeli > 0 {
-		if err := validator.Jumlah(req.HargaBeli, "harga beli"); err != nil {
+		if err := validator.Jumlah(req.HargaBeli.Float64(), "harga beli"); err != nil {
 			return nil, err
 		}
 	}
@@ -76,6 +87,16 @@ func (s *ProdukService) BuatProduk(idKoperasi uuid.UUID, req *BuatProdukRequest)
 		return nil, err
 	}
 
+	if err := validasiUnitUsahaAktifWithTx(s.db, idKoperasi, req.IDUnitUsaha); err != nil {
+		return nil, err
+	}
+
+	if req.KodePajak != "" {
+		if err := validasiKodePajak(validator, req.KodePajak); err != nil {
+			return nil, err
+		}
+	}
+
 	// Validasi kode produk unique
 	var count int64
 	s.db.Model(&models.Produk{}).
@@ -101,6 +122,8 @@ func (s *ProdukService) BuatProduk(idKoperasi uuid.UUID, req *BuatProdukRequest)
 		Barcode:     req.Barcode,
 		GambarURL:   req.GambarURL,
 		StatusAktif: true,
+		IDUnitUsaha: req.IDUnitUsaha,
+		KodePajak:   req.KodePajak,
 	}
 
 	err := s.db.Create(produk).Error
@@ -200,16 +223,18 @@ func (s *ProdukService) DapatkanProdukByBarcode(idKoperasi uuid.UUID, barcode st
 
 // PerbaruiProdukRequest adalah struktur request untuk update produk
 type PerbaruiProdukRequest struct {
-	NamaProduk  string  `json:"namaProduk"`
-	Kategori    string  `json:"kategori"`
-	Deskripsi   string  `json:"deskripsi"`
-	Harga       float64 `json:"harga"`
-	HargaBeli   float64 `json:"hargaBeli"`
-	StokMinimum int     `json:"stokMinimum"`
-	Satuan      string  `json:"satuan"`
-	Barcode     string  `json:"barcode"`
-	GambarURL   string  `json:"gambarUrl"`
-	StatusAktif *bool   `json:"statusAktif"`
+	NamaProduk  string           `json:"namaProduk"`
+	Kategori    string           `json:"kategori"`
+	Deskripsi   string           `json:"deskripsi"`
+	Harga       models.Uang      `json:"harga"`
+	HargaBeli   models.Uang      `json:"hargaBeli"`
+	StokMinimum int              `json:"stokMinimum"`
+	Satuan      string           `json:"satuan"`
+	Barcode     string           `json:"barcode"`
+	GambarURL   string           `json:"gambarUrl"`
+	StatusAktif *bool            `json:"statusAktif"`
+	IDUnitUsaha *uuid.UUID       `json:"idUnitUsaha"`
+	KodePajak   models.KodePajak `json:"kodePajak"`
 }
 
 // PerbaruiProduk mengupdate data produk
@@ -225,13 +250,13 @@ func (s *ProdukService) PerbaruiProduk(idKoperasi, id uuid.UUID, req *PerbaruiPr
 	}
 
 	if req.Harga > 0 {
-		if err := validator.Jumlah(req.Harga, "harga"); err != nil {
+		if err := validator.Jumlah(req.Harga.Float64(), "harga"); err != nil {
 			return nil, err
 		}
 	}
 
 	if req.HargaBeli > 0 {
-		if err := validator.Jumlah(req.HargaBeli, "harga beli"); err != nil {
+		if err := validator.Jumlah(req.HargaBeli.Float64(), "harga beli"); err != nil {
 			return nil, err
 		}
 	}
@@ -253,6 +278,12 @@ func (s *ProdukService) PerbaruiProduk(idKoperasi, id uuid.UUID, req *PerbaruiPr
 		return nil, err
 	}
 
+	if req.KodePajak != "" {
+		if err := validasiKodePajak(validator, req.KodePajak); err != nil {
+			return nil, err
+		}
+	}
+
 	// Cek apakah produk ada DAN milik koperasi yang benar (multi-tenant validation)
 	var produk models.Produk
 	err := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&produk).Error
@@ -263,6 +294,10 @@ func (s *ProdukService) PerbaruiProduk(idKoperasi, id uuid.UUID, req *PerbaruiPr
 		return nil, err
 	}
 
+	if err := validasiUnitUsahaAktifWithTx(s.db, idKoperasi, req.IDUnitUsaha); err != nil {
+		return nil, err
+	}
+
 	// Update fields
 	if req.NamaProduk != "" {
 		produk.NamaProduk = req.NamaProduk
@@ -294,6 +329,12 @@ func (s *ProdukService) PerbaruiProduk(idKoperasi, id uuid.UUID, req *PerbaruiPr
 	if req.StatusAktif != nil {
 		produk.StatusAktif = *req.StatusAktif
 	}
+	if req.IDUnitUsaha != nil {
+		produk.IDUnitUsaha = req.IDUnitUsaha
+	}
+	if req.KodePajak != "" {
+		produk.KodePajak = req.KodePajak
+	}
 
 	err = s.db.Save(&produk).Error
 	if err != nil {
@@ -386,17 +427,20 @@ func (s *ProdukService) KurangiStok(id uuid.UUID, jumlah int) error {
 	})
 }
 
-// TambahStok menambah stok produk
-func (s *ProdukService) TambahStok(id uuid.UUID, jumlah int) error {
+// TambahStokWithTx menambah stok produk menggunakan transaction yang diberikan.
+//
+// Dipakai saat pembatalan penjualan sehingga pengembalian stok dan jurnal pembalik
+// tersimpan atau di-rollback bersama-sama.
+func (s *ProdukService) TambahStokWithTx(tx *gorm.DB, id uuid.UUID, jumlah int) error {
 	var produk models.Produk
-	err := s.db.Where("id = ?", id).First(&produk).Error
+	err := tx.Where("id = ?", id).First(&produk).Error
 	if err != nil {
 		return errors.New("produk tidak ditemukan")
 	}
 
 	// Tambah stok
 	produk.Stok += jumlah
-	err = s.db.Save(&produk).Error
+	err = tx.Save(&produk).Error
 	if err != nil {
 		return errors.New("gagal menambah stok")
 	}
@@ -404,6 +448,13 @@ func (s *ProdukService) TambahStok(id uuid.UUID, jumlah int) error {
 	return nil
 }
 
+// TambahStok menambah stok produk
+func (s *ProdukService) TambahStok(id uuid.UUID, jumlah int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.TambahStokWithTx(tx, id, jumlah)
+	})
+}
+
 // CekStokTersedia mengecek apakah stok tersedia
 func (s *ProdukService) CekStokTersedia(id uuid.UUID, jumlah int) (bool, error) {
 	var produk models.Produk
@@ -65,8 +65,8 @@ func TestBuatProduk_Success(t *testing.T) {
 		NamaProduk: "Test Product",
 		Kategori:   "Elektronik",
 		Deskripsi:  "Test product description",
-		Harga:      50000,
-		HargaBeli:  40000,
+		Harga:      models.Rupiah(50000),
+		HargaBeli:  models.Rupiah(40000),
 		Stok:       100,
 		StokMinimum: 10,
 		Satuan:     "pcs",
@@ -80,7 +80,7 @@ func TestBuatProduk_Success(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, result.ID)
 	assert.Equal(t, "PRD001", result.KodeProduk)
 	assert.Equal(t, "Test Product", result.NamaProduk)
-	assert.Equal(t, 50000.0, result.Harga)
+	assert.Equal(t, models.Rupiah(50000), result.Harga)
 	assert.Equal(t, 100, result.Stok)
 	assert.True(t, result.StatusAktif)
 }
@@ -107,7 +107,7 @@ func TestBuatProduk_ValidationErrors(t *testing.T) {
 			req: &BuatProdukRequest{
 				KodeProduk: "",
 				NamaProduk: "Test Product",
-				Harga:      50000,
+				Harga:      models.Rupiah(50000),
 			},
 			wantErr: true,
 		},
@@ -116,7 +116,7 @@ func TestBuatProduk_ValidationErrors(t *testing.T) {
 			req: &BuatProdukRequest{
 				KodeProduk: "PRD001",
 				NamaProduk: "AB",
-				Harga:      50000,
+				Harga:      models.Rupiah(50000),
 			},
 			wantErr: true,
 		},
@@ -125,7 +125,7 @@ func TestBuatProduk_ValidationErrors(t *testing.T) {
 			req: &BuatProdukRequest{
 				KodeProduk: "PRD001",
 				NamaProduk: "Test Product",
-				Harga:      -1000,
+				Harga:      models.Rupiah(-1000),
 			},
 			wantErr: true,
 		},
@@ -134,7 +134,7 @@ func TestBuatProduk_ValidationErrors(t *testing.T) {
 			req: &BuatProdukRequest{
 				KodeProduk: "PRD001",
 				NamaProduk: "Test Product",
-				Harga:      1000000000, // > 999,999,999
+				Harga:      models.Rupiah(1000000000), // > 999,999,999
 			},
 			wantErr: true,
 		},
@@ -143,7 +143,7 @@ func TestBuatProduk_ValidationErrors(t *testing.T) {
 			req: &BuatProdukRequest{
 				KodeProduk: "PRD002",
 				NamaProduk: "Valid Product",
-				Harga:      1000,
+				Harga:      models.Rupiah(1000),
 			},
 			wantErr: false,
 		},
@@ -177,7 +177,7 @@ func TestBuatProduk_DuplicateKode(t *testing.T) {
 	req1 := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Product 1",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 	}
 	_, err := service.BuatProduk(koperasi.ID, req1)
 	assert.NoError(t, err)
@@ -186,7 +186,7 @@ func TestBuatProduk_DuplicateKode(t *testing.T) {
 	req2 := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Product 2",
-		Harga:      60000,
+		Harga:      models.Rupiah(60000),
 	}
 	_, err = service.BuatProduk(koperasi.ID, req2)
 	assert.Error(t, err)
@@ -212,7 +212,7 @@ func TestBuatProduk_MultiTenant(t *testing.T) {
 	req1 := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Product Koperasi 1",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 	}
 	result1, err := service.BuatProduk(koperasi1.ID, req1)
 	assert.NoError(t, err)
@@ -221,7 +221,7 @@ func TestBuatProduk_MultiTenant(t *testing.T) {
 	req2 := &BuatProdukRequest{
 		KodeProduk: "PRD001",  // Same code but different cooperative
 		NamaProduk: "Product Koperasi 2",
-		Harga:      60000,
+		Harga:      models.Rupiah(60000),
 	}
 	result2, err := service.BuatProduk(koperasi2.ID, req2)
 	assert.NoError(t, err)
@@ -244,9 +244,9 @@ func TestDapatkanSemuaProduk(t *testing.T) {
 
 	// Create test products
 	products := []BuatProdukRequest{
-		{KodeProduk: "EL001", NamaProduk: "Laptop", Kategori: "Elektronik", Harga: 5000000},
-		{KodeProduk: "EL002", NamaProduk: "Mouse", Kategori: "Elektronik", Harga: 50000},
-		{KodeProduk: "FD001", NamaProduk: "Mie Instant", Kategori: "Makanan", Harga: 3000},
+		{KodeProduk: "EL001", NamaProduk: "Laptop", Kategori: "Elektronik", Harga: models.Rupiah(5000000)},
+		{KodeProduk: "EL002", NamaProduk: "Mouse", Kategori: "Elektronik", Harga: models.Rupiah(50000)},
+		{KodeProduk: "FD001", NamaProduk: "Mie Instant", Kategori: "Makanan", Harga: models.Rupiah(3000)},
 	}
 
 	for _, p := range products {
@@ -298,22 +298,22 @@ func TestPerbaruiProduk(t *testing.T) {
 	createReq := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Original Name",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 	}
 	produk, _ := service.BuatProduk(koperasi.ID, createReq)
 
 	t.Run("update price", func(t *testing.T) {
 		updateReq := &PerbaruiProdukRequest{
-			Harga: 60000,
+			Harga: models.Rupiah(60000),
 		}
 		result, err := service.PerbaruiProduk(koperasi.ID, produk.ID, updateReq)
 		assert.NoError(t, err)
-		assert.Equal(t, 60000.0, result.Harga)
+		assert.Equal(t, models.Rupiah(60000), result.Harga)
 	})
 
 	t.Run("update invalid price", func(t *testing.T) {
 		updateReq := &PerbaruiProdukRequest{
-			Harga: 1000000000, // Too large
+			Harga: models.Rupiah(1000000000), // Too large
 		}
 		_, err := service.PerbaruiProduk(koperasi.ID, produk.ID, updateReq)
 		assert.Error(t, err)
@@ -345,7 +345,7 @@ func TestKurangiStok(t *testing.T) {
 	createReq := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       100,
 	}
 	produk, _ := service.BuatProduk(koperasi.ID, createReq)
@@ -385,7 +385,7 @@ func TestTambahStok(t *testing.T) {
 	createReq := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       100,
 	}
 	produk, _ := service.BuatProduk(koperasi.ID, createReq)
@@ -413,7 +413,7 @@ func TestCekStokTersedia(t *testing.T) {
 	createReq := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       100,
 	}
 	produk, _ := service.BuatProduk(koperasi.ID, createReq)
@@ -452,7 +452,7 @@ func TestHapusProduk(t *testing.T) {
 		createReq := &BuatProdukRequest{
 			KodeProduk: "PRD001",
 			NamaProduk: "Test Product",
-			Harga:      50000,
+			Harga:      models.Rupiah(50000),
 		}
 		produk, _ := service.BuatProduk(koperasi.ID, createReq)
 
@@ -468,7 +468,7 @@ func TestHapusProduk(t *testing.T) {
 		createReq := &BuatProdukRequest{
 			KodeProduk: "PRD002",
 			NamaProduk: "Test Product 2",
-			Harga:      50000,
+			Harga:      models.Rupiah(50000),
 		}
 		produk, _ := service.BuatProduk(koperasi.ID, createReq)
 
@@ -476,8 +476,8 @@ func TestHapusProduk(t *testing.T) {
 		penjualan := &models.Penjualan{
 			IDKoperasi:       koperasi.ID,
 			NomorPenjualan:   "POS-001",
-			TotalBelanja:     50000,
-			JumlahBayar:      50000,
+			TotalBelanja:     models.Rupiah(50000),
+			JumlahBayar:      models.Rupiah(50000),
 			IDKasir:          uuid.New(),
 		}
 		db.Create(penjualan)
@@ -487,7 +487,7 @@ func TestHapusProduk(t *testing.T) {
 			IDProduk:    produk.ID,
 			NamaProduk:  produk.NamaProduk,
 			Kuantitas:   1,
-			HargaSatuan: 50000,
+			HargaSatuan: models.Rupiah(50000),
 		}
 		db.Create(itemPenjualan)
 
@@ -510,9 +510,9 @@ func TestDapatkanProdukStokRendah(t *testing.T) {
 
 	// Create products with varying stock levels
 	products := []BuatProdukRequest{
-		{KodeProduk: "PRD001", NamaProduk: "Low Stock 1", Harga: 10000, Stok: 5, StokMinimum: 10},
-		{KodeProduk: "PRD002", NamaProduk: "Low Stock 2", Harga: 10000, Stok: 3, StokMinimum: 5},
-		{KodeProduk: "PRD003", NamaProduk: "Good Stock", Harga: 10000, Stok: 100, StokMinimum: 10},
+		{KodeProduk: "PRD001", NamaProduk: "Low Stock 1", Harga: models.Rupiah(10000), Stok: 5, StokMinimum: 10},
+		{KodeProduk: "PRD002", NamaProduk: "Low Stock 2", Harga: models.Rupiah(10000), Stok: 3, StokMinimum: 5},
+		{KodeProduk: "PRD003", NamaProduk: "Good Stock", Harga: models.Rupiah(10000), Stok: 100, StokMinimum: 10},
 	}
 
 	for _, p := range products {
@@ -549,7 +549,7 @@ func BenchmarkBuatProduk(b *testing.B) {
 		req := &BuatProdukRequest{
 			KodeProduk: fmt.Sprintf("PRD%05d", i),
 			NamaProduk: fmt.Sprintf("Product %d", i),
-			Harga:      50000,
+			Harga:      models.Rupiah(50000),
 		}
 		_, _ = service.BuatProduk(koperasi.ID, req)
 	}
@@ -577,7 +577,7 @@ func BenchmarkKurangiStok(b *testing.B) {
 	req := &BuatProdukRequest{
 		KodeProduk: "PRD001",
 		NamaProduk: "Test Product",
-		Harga:      50000,
+		Harga:      models.Rupiah(50000),
 		Stok:       1000000, // Large stock for benchmarking
 	}
 	produk, _ := service.BuatProduk(koperasi.ID, req)
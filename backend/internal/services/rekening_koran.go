@@ -0,0 +1,410 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"cooperative-erp-lite/internal/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RekeningKoranTerbaca adalah isi berkas rekening koran setelah diurai
+type RekeningKoranTerbaca struct {
+	NomorRekening string
+	SaldoAwal     *models.Uang
+	SaldoAkhir    *models.Uang
+	Mutasi        []MutasiTerbaca
+}
+
+// MutasiTerbaca adalah satu mutasi rekening koran. Jumlah positif berarti dana masuk.
+type MutasiTerbaca struct {
+	Baris      int // Nomor baris (CSV) atau urutan transaksi (OFX/MT940) untuk pesan error
+	Tanggal    time.Time
+	Keterangan string
+	Referensi  string
+	IDBank     string // ID transaksi dari bank (FITID OFX, referensi bank MT940), jika ada
+	Jumlah     models.Uang
+}
+
+// Kolom berkas CSV rekening koran. Nominal ditulis sebagai satu kolom jumlah bertanda
+// (positif = masuk) atau dua kolom debit (keluar) dan kredit (masuk) seperti rekening koran bank.
+const (
+	kolomRekeningKoranTanggal    = "tanggal"
+	kolomRekeningKoranKeterangan = "keterangan"
+	kolomRekeningKoranReferensi  = "referensi"
+	kolomRekeningKoranJumlah     = "jumlah"
+	kolomRekeningKoranDebit      = "debit"
+	kolomRekeningKoranKredit     = "kredit"
+	kolomRekeningKoranSaldo      = "saldo"
+)
+
+// BacaRekeningKoran mengurai berkas rekening koran sesuai formatnya. Saldo awal atau saldo akhir
+// yang tidak ada di berkas dihitung dari saldo lainnya dan total mutasi; jika keduanya ada,
+// saldo awal ditambah mutasi harus sama dengan saldo akhir.
+func BacaRekeningKoran(format models.FormatRekeningKoran, data []byte) (*RekeningKoranTerbaca, error) {
+	var (
+		hasil *RekeningKoranTerbaca
+		err   error
+	)
+	switch format {
+	case models.FormatRekeningKoranCSV:
+		hasil, err = bacaRekeningKoranCSV(data)
+	case models.FormatRekeningKoranOFX:
+		hasil, err = bacaRekeningKoranOFX(data)
+	case models.FormatRekeningKoranMT940:
+		hasil, err = bacaRekeningKoranMT940(data)
+	default:
+		return nil, fmt.Errorf("format rekening koran %s tidak didukung", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(hasil.Mutasi) == 0 {
+		return nil, errors.New("rekening koran tidak memuat mutasi")
+	}
+
+	var total models.Uang
+	for _, mutasi := range hasil.Mutasi {
+		total += mutasi.Jumlah
+	}
+	switch {
+	case hasil.SaldoAwal != nil && hasil.SaldoAkhir != nil:
+		if *hasil.SaldoAwal+total != *hasil.SaldoAkhir {
+			return nil, fmt.Errorf("saldo awal (%s) ditambah mutasi (%s) tidak sama dengan saldo akhir (%s)",
+				*hasil.SaldoAwal, total, *hasil.SaldoAkhir)
+		}
+	case hasil.SaldoAwal != nil:
+		saldoAkhir := *hasil.SaldoAwal + total
+		hasil.SaldoAkhir = &saldoAkhir
+	case hasil.SaldoAkhir != nil:
+		saldoAwal := *hasil.SaldoAkhir - total
+		hasil.SaldoAwal = &saldoAwal
+	}
+
+	return hasil, nil
+}
+
+// bacaRekeningKoranCSV membaca rekening koran CSV dengan header pada baris pertama.
+// Kolom saldo (opsional) adalah saldo setelah mutasi pada baris tersebut.
+func bacaRekeningKoranCSV(data []byte) (*RekeningKoranTerbaca, error) {
+	tabel, err := bacaTabelImpor(FormatCSV, data, kolomRekeningKoranTanggal)
+	if err != nil {
+		return nil, err
+	}
+	_, adaJumlah := tabel.indeks[kolomRekeningKoranJumlah]
+	_, adaDebit := tabel.indeks[kolomRekeningKoranDebit]
+	_, adaKredit := tabel.indeks[kolomRekeningKoranKredit]
+	if !adaJumlah && !(adaDebit && adaKredit) {
+		return nil, errors.New("kolom jumlah, atau kolom debit dan kredit, tidak ditemukan di header berkas")
+	}
+	_, adaSaldo := tabel.indeks[kolomRekeningKoranSaldo]
+
+	hasil := &RekeningKoranTerbaca{}
+	for i, sel := range tabel.baris {
+		if barisKosong(sel) {
+			continue
+		}
+		nomor := i + 2
+
+		tanggal, err := parseTanggalRekeningKoran(tabel.ambil(sel, kolomRekeningKoranTanggal))
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: %v", nomor, err)
+		}
+
+		var jumlah models.Uang
+		if adaJumlah {
+			jumlah, err = parseNominalImpor(tabel.ambil(sel, kolomRekeningKoranJumlah))
+			if err != nil {
+				return nil, fmt.Errorf("baris %d: jumlah tidak valid", nomor)
+			}
+		} else {
+			debit, debitErr := parseNominalImpor(tabel.ambil(sel, kolomRekeningKoranDebit))
+			kredit, kreditErr := parseNominalImpor(tabel.ambil(sel, kolomRekeningKoranKredit))
+			if debitErr != nil || kreditErr != nil || debit < 0 || kredit < 0 {
+				return nil, fmt.Errorf("baris %d: debit atau kredit tidak valid", nomor)
+			}
+			jumlah = kredit - debit
+		}
+		if jumlah == 0 {
+			return nil, fmt.Errorf("baris %d: jumlah mutasi tidak boleh 0", nomor)
+		}
+
+		if adaSaldo {
+			if nilai := tabel.ambil(sel, kolomRekeningKoranSaldo); nilai != "" {
+				saldo, err := parseNominalImpor(nilai)
+				if err != nil {
+					return nil, fmt.Errorf("baris %d: saldo tidak valid", nomor)
+				}
+				if hasil.SaldoAwal == nil {
+					saldoAwal := saldo - jumlah
+					hasil.SaldoAwal = &saldoAwal
+				}
+				hasil.SaldoAkhir = &saldo
+			}
+		}
+
+		hasil.Mutasi = append(hasil.Mutasi, MutasiTerbaca{
+			Baris:      nomor,
+			Tanggal:    tanggal,
+			Keterangan: tabel.ambil(sel, kolomRekeningKoranKeterangan),
+			Referensi:  tabel.ambil(sel, kolomRekeningKoranReferensi),
+			Jumlah:     jumlah,
+		})
+	}
+
+	return hasil, nil
+}
+
+// parseTanggalRekeningKoran menerima tanggal YYYY-MM-DD, DD/MM/YYYY atau DD-MM-YYYY
+func parseTanggalRekeningKoran(nilai string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "02-01-2006"} {
+		if tanggal, err := time.Parse(layout, nilai); err == nil {
+			return tanggal, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("tanggal %q tidak valid (gunakan YYYY-MM-DD atau DD/MM/YYYY)", nilai)
+}
+
+// bacaRekeningKoranOFX membaca OFX versi 1 (SGML, tag tanpa penutup) maupun versi 2 (XML).
+// Setiap <STMTTRN> menjadi satu mutasi; <LEDGERBAL> menjadi saldo akhir.
+func bacaRekeningKoranOFX(data []byte) (*RekeningKoranTerbaca, error) {
+	isi := string(data)
+	if !strings.Contains(strings.ToUpper(isi), "<OFX>") {
+		return nil, errors.New("berkas OFX tidak valid")
+	}
+
+	hasil := &RekeningKoranTerbaca{NomorRekening: nilaiTagOFX(isi, "ACCTID")}
+	if saldo := blokTagOFX(isi, "LEDGERBAL"); len(saldo) > 0 {
+		jumlah, err := parseNominalBank(nilaiTagOFX(saldo[0], "BALAMT"))
+		if err != nil {
+			return nil, errors.New("saldo akhir OFX tidak valid")
+		}
+		hasil.SaldoAkhir = &jumlah
+	}
+
+	for i, blok := range blokTagOFX(isi, "STMTTRN") {
+		nomor := i + 1
+
+		tanggalStr := nilaiTagOFX(blok, "DTPOSTED")
+		if len(tanggalStr) < 8 {
+			return nil, fmt.Errorf("transaksi %d: tanggal tidak valid", nomor)
+		}
+		tanggal, err := time.Parse("20060102", tanggalStr[:8])
+		if err != nil {
+			return nil, fmt.Errorf("transaksi %d: tanggal tidak valid", nomor)
+		}
+
+		jumlah, err := parseNominalBank(nilaiTagOFX(blok, "TRNAMT"))
+		if err != nil || jumlah == 0 {
+			return nil, fmt.Errorf("transaksi %d: jumlah tidak valid", nomor)
+		}
+
+		keterangan := strings.TrimSpace(nilaiTagOFX(blok, "NAME") + " " + nilaiTagOFX(blok, "MEMO"))
+		referensi := nilaiTagOFX(blok, "CHECKNUM")
+		if referensi == "" {
+			referensi = nilaiTagOFX(blok, "REFNUM")
+		}
+
+		hasil.Mutasi = append(hasil.Mutasi, MutasiTerbaca{
+			Baris:      nomor,
+			Tanggal:    tanggal,
+			Keterangan: keterangan,
+			Referensi:  referensi,
+			IDBank:     nilaiTagOFX(blok, "FITID"),
+			Jumlah:     jumlah,
+		})
+	}
+
+	return hasil, nil
+}
+
+// blokTagOFX mengembalikan isi setiap agregat <tag>...</tag>. Agregat OFX SGML tetap memiliki
+// tag penutup; hanya elemen data yang tidak ditutup.
+func blokTagOFX(isi, tag string) []string {
+	buka, tutup := "<"+tag+">", "</"+tag+">"
+	atas := strings.ToUpper(isi)
+
+	var blok []string
+	for {
+		awal := strings.Index(atas, buka)
+		if awal < 0 {
+			return blok
+		}
+		atas, isi = atas[awal+len(buka):], isi[awal+len(buka):]
+
+		akhir := strings.Index(atas, tutup)
+		if akhir < 0 {
+			return append(blok, isi)
+		}
+		blok = append(blok, isi[:akhir])
+		atas, isi = atas[akhir+len(tutup):], isi[akhir+len(tutup):]
+	}
+}
+
+// nilaiTagOFX mengambil nilai elemen data <tag> pertama: teks sampai tag berikutnya
+func nilaiTagOFX(isi, tag string) string {
+	buka := "<" + tag + ">"
+	awal := strings.Index(strings.ToUpper(isi), buka)
+	if awal < 0 {
+		return ""
+	}
+	nilai := isi[awal+len(buka):]
+	if akhir := strings.Index(nilai, "<"); akhir >= 0 {
+		nilai = nilai[:akhir]
+	}
+	return strings.TrimSpace(nilai)
+}
+
+// parseNominalBank membaca nominal dari berkas bank yang memakai titik atau koma sebagai
+// pemisah desimal (tanpa pemisah ribuan), misalnya "-1500.00" atau "1500,00"
+func parseNominalBank(nilai string) (models.Uang, error) {
+	nilai = strings.TrimSpace(nilai)
+	if strings.Contains(nilai, ",") {
+		if strings.Contains(nilai, ".") {
+			return 0, fmt.Errorf("nominal %q tidak valid", nilai)
+		}
+		nilai = strings.Replace(nilai, ",", ".", 1)
+	}
+	return models.ParseUang(nilai)
+}
+
+// Pola field MT940. Baris :61: berisi tanggal valuta (YYMMDD), tanggal buku opsional (MMDD),
+// tanda debit/kredit (D, C, RD, RC), kode dana opsional, nominal, kode transaksi,
+// referensi nasabah, lalu opsional //referensi bank.
+var (
+	polaSaldoMT940  = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})([\d,]+)$`)
+	polaMutasiMT940 = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?([\d,]+)([NF][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+)
+
+// bacaRekeningKoranMT940 membaca SWIFT MT940. Saldo awal dari :60F:/:60M:, saldo akhir dari
+// :62F:/:62M:, mutasi dari :61: dengan keterangan dari :86: sesudahnya. Jika berkas memuat
+// beberapa statement, saldo awal diambil dari statement pertama dan saldo akhir dari yang terakhir.
+func bacaRekeningKoranMT940(data []byte) (*RekeningKoranTerbaca, error) {
+	// Gabungkan baris lanjutan ke field sebelumnya
+	type fieldMT940 struct {
+		tag   string
+		nilai []string
+	}
+	var fields []fieldMT940
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		baris := strings.TrimRight(scanner.Text(), "\r ")
+		if strings.HasPrefix(baris, ":") {
+			if akhirTag := strings.Index(baris[1:], ":"); akhirTag > 0 {
+				fields = append(fields, fieldMT940{tag: baris[1 : akhirTag+1], nilai: []string{baris[akhirTag+2:]}})
+				continue
+			}
+		}
+		if len(fields) > 0 && baris != "" && baris != "-" && !strings.HasPrefix(baris, "{") {
+			fields[len(fields)-1].nilai = append(fields[len(fields)-1].nilai, baris)
+		}
+	}
+	if err := scanner.Err(); err != nil || len(fields) == 0 {
+		return nil, errors.New("berkas MT940 tidak valid")
+	}
+
+	hasil := &RekeningKoranTerbaca{}
+	for _, field := range fields {
+		nilai := strings.TrimSpace(field.nilai[0])
+		switch field.tag {
+		case "25":
+			if hasil.NomorRekening == "" {
+				hasil.NomorRekening = nilai
+			}
+		case "60F", "60M":
+			if hasil.SaldoAwal == nil {
+				saldo, err := parseSaldoMT940(nilai)
+				if err != nil {
+					return nil, fmt.Errorf("saldo awal MT940 tidak valid: %v", err)
+				}
+				hasil.SaldoAwal = &saldo
+			}
+		case "62F", "62M":
+			saldo, err := parseSaldoMT940(nilai)
+			if err != nil {
+				return nil, fmt.Errorf("saldo akhir MT940 tidak valid: %v", err)
+			}
+			hasil.SaldoAkhir = &saldo
+		case "61":
+			nomor := len(hasil.Mutasi) + 1
+			cocok := polaMutasiMT940.FindStringSubmatch(nilai)
+			if cocok == nil {
+				return nil, fmt.Errorf("transaksi %d: baris :61: tidak valid", nomor)
+			}
+			tanggal, err := time.Parse("060102", cocok[1])
+			if err != nil {
+				return nil, fmt.Errorf("transaksi %d: tanggal tidak valid", nomor)
+			}
+			jumlah, err := parseNominalBank(cocok[5])
+			if err != nil || jumlah == 0 {
+				return nil, fmt.Errorf("transaksi %d: jumlah tidak valid", nomor)
+			}
+			// Debit dan pembatalan kredit mengurangi saldo rekening
+			if cocok[3] == "D" || cocok[3] == "RC" {
+				jumlah = -jumlah
+			}
+			referensi := strings.TrimSpace(cocok[7])
+			if strings.EqualFold(referensi, "NONREF") {
+				referensi = ""
+			}
+
+			mutasi := MutasiTerbaca{
+				Baris:     nomor,
+				Tanggal:   tanggal,
+				Referensi: referensi,
+				IDBank:    strings.TrimSpace(cocok[8]),
+				Jumlah:    jumlah,
+			}
+			// Baris kedua :61: adalah keterangan tambahan
+			if len(field.nilai) > 1 {
+				mutasi.Keterangan = strings.TrimSpace(strings.Join(field.nilai[1:], " "))
+			}
+			hasil.Mutasi = append(hasil.Mutasi, mutasi)
+		case "86":
+			if len(hasil.Mutasi) > 0 {
+				mutasi := &hasil.Mutasi[len(hasil.Mutasi)-1]
+				keterangan := strings.TrimSpace(strings.Join(field.nilai, " "))
+				mutasi.Keterangan = strings.TrimSpace(mutasi.Keterangan + " " + keterangan)
+			}
+		}
+	}
+
+	return hasil, nil
+}
+
+// parseSaldoMT940 membaca saldo MT940 seperti "C260131IDR1500000,00"
+func parseSaldoMT940(nilai string) (models.Uang, error) {
+	cocok := polaSaldoMT940.FindStringSubmatch(nilai)
+	if cocok == nil {
+		return 0, fmt.Errorf("format saldo %q tidak dikenal", nilai)
+	}
+	saldo, err := parseNominalBank(cocok[4])
+	if err != nil {
+		return 0, err
+	}
+	if cocok[1] == "D" {
+		saldo = -saldo
+	}
+	return saldo, nil
+}
+
+// kunciMutasiBank menghasilkan kunci unik mutasi untuk mendeteksi mutasi yang sudah pernah
+// diimpor. ID transaksi dari bank dipakai jika ada; selain itu tanggal, nominal, referensi dan
+// keterangan ditambah urutan kemunculannya di berkas, sehingga dua mutasi identik di hari yang
+// sama tetap tersimpan keduanya.
+func kunciMutasiBank(mutasi MutasiTerbaca, kemunculan int) string {
+	var sumber string
+	if mutasi.IDBank != "" {
+		sumber = "ID|" + mutasi.IDBank
+	} else {
+		sumber = fmt.Sprintf("%s|%d|%s|%s|%d", mutasi.Tanggal.Format("2006-01-02"), int64(mutasi.Jumlah),
+			mutasi.Referensi, mutasi.Keterangan, kemunculan)
+	}
+	jumlah := sha256.Sum256([]byte(sumber))
+	return hex.EncodeToString(jumlah[:])
+}
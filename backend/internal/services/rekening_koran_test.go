@@ -0,0 +1,157 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacaRekeningKoranCSV(t *testing.T) {
+	t.Run("kolom debit dan kredit dengan saldo", func(t *testing.T) {
+		data := []byte("Tanggal,Keterangan,Referensi,Debit,Kredit,Saldo\n" +
+			"02/01/2026,Setoran tunai,SET-01,0,1500000,11500000\n" +
+			"\n" +
+			"05/01/2026,Biaya administrasi,,15000,0,11485000\n")
+
+		hasil, err := BacaRekeningKoran(models.FormatRekeningKoranCSV, data)
+		require.NoError(t, err)
+		require.Len(t, hasil.Mutasi, 2)
+		assert.Equal(t, mustParseTime("2026-01-02"), hasil.Mutasi[0].Tanggal)
+		assert.Equal(t, models.Rupiah(1500000), hasil.Mutasi[0].Jumlah)
+		assert.Equal(t, "SET-01", hasil.Mutasi[0].Referensi)
+		assert.Equal(t, models.Rupiah(-15000), hasil.Mutasi[1].Jumlah)
+		require.NotNil(t, hasil.SaldoAwal)
+		require.NotNil(t, hasil.SaldoAkhir)
+		assert.Equal(t, models.Rupiah(10000000), *hasil.SaldoAwal)
+		assert.Equal(t, models.Rupiah(11485000), *hasil.SaldoAkhir)
+	})
+
+	t.Run("kolom jumlah bertanda tanpa saldo", func(t *testing.T) {
+		data := []byte("tanggal,keterangan,jumlah\n2026-01-02,Transfer keluar,-250000.50\n")
+
+		hasil, err := BacaRekeningKoran(models.FormatRekeningKoranCSV, data)
+		require.NoError(t, err)
+		require.Len(t, hasil.Mutasi, 1)
+		assert.Equal(t, models.Rupiah(-250000)-50, hasil.Mutasi[0].Jumlah)
+		assert.Nil(t, hasil.SaldoAwal)
+		assert.Nil(t, hasil.SaldoAkhir)
+	})
+
+	t.Run("berkas tidak valid", func(t *testing.T) {
+		_, err := BacaRekeningKoran(models.FormatRekeningKoranCSV, []byte("tanggal,keterangan\n2026-01-02,Tanpa nominal\n"))
+		assert.Error(t, err, "kolom nominal wajib ada")
+
+		_, err = BacaRekeningKoran(models.FormatRekeningKoranCSV, []byte("tanggal,jumlah\n2026-13-45,1000\n"))
+		assert.Error(t, err, "tanggal tidak valid")
+
+		_, err = BacaRekeningKoran(models.FormatRekeningKoranCSV, []byte("tanggal,jumlah\n2026-01-02,1.000.000\n"))
+		assert.Error(t, err, "pemisah ribuan ditolak")
+
+		_, err = BacaRekeningKoran(models.FormatRekeningKoranCSV, []byte("tanggal,jumlah\n"))
+		assert.Error(t, err, "rekening koran tanpa mutasi")
+	})
+}
+
+func TestBacaRekeningKoranOFX(t *testing.T) {
+	// OFX 1.x (SGML): elemen data tidak memiliki tag penutup
+	data := []byte(`OFXHEADER:100
+DATA:OFXSGML
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<BANKACCTFROM><BANKID>014<ACCTID>1234567890<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20260110120000[+7:WIB]
+<TRNAMT>2000000.00
+<FITID>TRX-001
+<NAME>Setoran anggota
+<MEMO>SP-0001
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20260131
+<TRNAMT>-7500
+<FITID>TRX-002
+<NAME>Biaya admin
+<CHECKNUM>778899
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>12992500.00<DTASOF>20260131</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`)
+
+	hasil, err := BacaRekeningKoran(models.FormatRekeningKoranOFX, data)
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", hasil.NomorRekening)
+	require.Len(t, hasil.Mutasi, 2)
+
+	assert.Equal(t, mustParseTime("2026-01-10"), hasil.Mutasi[0].Tanggal)
+	assert.Equal(t, models.Rupiah(2000000), hasil.Mutasi[0].Jumlah)
+	assert.Equal(t, "TRX-001", hasil.Mutasi[0].IDBank)
+	assert.Equal(t, "Setoran anggota SP-0001", hasil.Mutasi[0].Keterangan)
+
+	assert.Equal(t, models.Rupiah(-7500), hasil.Mutasi[1].Jumlah)
+	assert.Equal(t, "778899", hasil.Mutasi[1].Referensi)
+
+	require.NotNil(t, hasil.SaldoAwal)
+	assert.Equal(t, models.Rupiah(11000000), *hasil.SaldoAwal, "saldo awal dihitung dari saldo akhir dikurangi mutasi")
+
+	_, err = BacaRekeningKoran(models.FormatRekeningKoranOFX, []byte("bukan berkas ofx"))
+	assert.Error(t, err)
+}
+
+func TestBacaRekeningKoranMT940(t *testing.T) {
+	data := []byte(":20:STMT260131\r\n" +
+		":25:0140001234567\r\n" +
+		":28C:00001/001\r\n" +
+		":60F:C260101IDR10000000,00\r\n" +
+		":61:2601050105C1500000,00NTRFSET-01//BNK0001\r\n" +
+		":86:Setoran tunai\r\n" +
+		"anggota A-001\r\n" +
+		":61:260131D15000,NCHGNONREF\r\n" +
+		":86:Biaya administrasi bulanan\r\n" +
+		":62F:C260131IDR11485000,00\r\n" +
+		"-\r\n")
+
+	hasil, err := BacaRekeningKoran(models.FormatRekeningKoranMT940, data)
+	require.NoError(t, err)
+	assert.Equal(t, "0140001234567", hasil.NomorRekening)
+	require.NotNil(t, hasil.SaldoAwal)
+	assert.Equal(t, models.Rupiah(10000000), *hasil.SaldoAwal)
+	require.NotNil(t, hasil.SaldoAkhir)
+	assert.Equal(t, models.Rupiah(11485000), *hasil.SaldoAkhir)
+
+	require.Len(t, hasil.Mutasi, 2)
+	assert.Equal(t, mustParseTime("2026-01-05"), hasil.Mutasi[0].Tanggal)
+	assert.Equal(t, models.Rupiah(1500000), hasil.Mutasi[0].Jumlah)
+	assert.Equal(t, "SET-01", hasil.Mutasi[0].Referensi)
+	assert.Equal(t, "BNK0001", hasil.Mutasi[0].IDBank)
+	assert.Equal(t, "Setoran tunai anggota A-001", hasil.Mutasi[0].Keterangan)
+
+	assert.Equal(t, models.Rupiah(-15000), hasil.Mutasi[1].Jumlah)
+	assert.Empty(t, hasil.Mutasi[1].Referensi, "NONREF berarti tanpa referensi")
+
+	// Saldo akhir yang tidak sesuai dengan saldo awal ditambah mutasi ditolak
+	rusak := []byte(":25:0140001234567\n:60F:C260101IDR10000000,00\n:61:260105C1500000,00NTRFNONREF\n:62F:C260131IDR9000000,00\n")
+	_, err = BacaRekeningKoran(models.FormatRekeningKoranMT940, rusak)
+	assert.Error(t, err)
+}
+
+func TestKunciMutasiBank(t *testing.T) {
+	mutasi := MutasiTerbaca{Tanggal: mustParseTime("2026-01-05"), Jumlah: models.Rupiah(-15000), Keterangan: "Biaya admin"}
+
+	assert.Equal(t, kunciMutasiBank(mutasi, 1), kunciMutasiBank(mutasi, 1), "kunci harus deterministik")
+	assert.NotEqual(t, kunciMutasiBank(mutasi, 1), kunciMutasiBank(mutasi, 2), "mutasi identik dibedakan urutannya")
+
+	// ID transaksi bank mengabaikan keterangan yang dapat berubah antar ekspor
+	mutasi.IDBank = "TRX-002"
+	lain := mutasi
+	lain.Keterangan = "BIAYA ADMIN"
+	assert.Equal(t, kunciMutasiBank(mutasi, 1), kunciMutasiBank(lain, 1))
+	assert.Len(t, kunciMutasiBank(mutasi, 1), 64)
+}
@@ -0,0 +1,811 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// kodeAkunBankDefault adalah akun "Bank" pada COA default (lihat coaDasar)
+const kodeAkunBankDefault = "1102"
+
+// Jendela tanggal pencocokan otomatis. Mutasi dengan referensi yang sama dengan nomor referensi
+// jurnal boleh berjarak lebih jauh (misalnya cek yang baru dicairkan) daripada pencocokan
+// berdasarkan nominal dan tanggal saja.
+const (
+	jendelaCocokReferensiHari = 31
+	jendelaCocokTanggalHari   = 3
+)
+
+// RekonsiliasiBankService menangani impor rekening koran, pencocokan mutasi bank dengan
+// buku besar, dan laporan rekonsiliasi bank
+type RekonsiliasiBankService struct {
+	db               *gorm.DB
+	transaksiService *TransaksiService
+}
+
+// NewRekonsiliasiBankService membuat instance baru RekonsiliasiBankService
+func NewRekonsiliasiBankService(db *gorm.DB, transaksiService *TransaksiService) *RekonsiliasiBankService {
+	return &RekonsiliasiBankService{
+		db:               db,
+		transaksiService: transaksiService,
+	}
+}
+
+// ImporRekeningKoranRequest adalah isi berkas rekening koran yang akan diimpor
+type ImporRekeningKoranRequest struct {
+	KodeAkun   string // Kosong: akun bank default (1102)
+	Format     models.FormatRekeningKoran
+	NamaBerkas string
+	Data       []byte
+}
+
+// HasilImporRekeningKoran adalah ringkasan impor satu berkas rekening koran
+type HasilImporRekeningKoran struct {
+	RekeningKoran  models.RekeningKoranBank `json:"rekeningKoran"`
+	JumlahBaru     int                      `json:"jumlahBaru"`
+	JumlahDuplikat int                      `json:"jumlahDuplikat"`
+	JumlahCocok    int                      `json:"jumlahCocok"` // Mutasi yang langsung dicocokkan otomatis
+}
+
+// FilterMutasiBank adalah filter daftar mutasi bank
+type FilterMutasiBank struct {
+	KodeAkun        string
+	Status          models.StatusMutasiBank
+	IDRekeningKoran *uuid.UUID
+	TanggalMulai    string
+	TanggalAkhir    string
+}
+
+// CocokkanMutasiRequest adalah struktur request pencocokan manual
+type CocokkanMutasiRequest struct {
+	IDBarisTransaksi uuid.UUID `json:"idBarisTransaksi" binding:"required"`
+}
+
+// BatalkanCocokRequest adalah struktur request pembatalan pencocokan
+type BatalkanCocokRequest struct {
+	Alasan string `json:"alasan"` // Wajib jika jurnal mutasi ikut dibalik
+}
+
+// BuatJurnalMutasiRequest adalah struktur request pembuatan jurnal dari mutasi bank
+type BuatJurnalMutasiRequest struct {
+	IDAkunLawan *uuid.UUID `json:"idAkunLawan"` // Kosong: akun jasa giro (dana masuk) atau biaya bank (dana keluar)
+	Deskripsi   string     `json:"deskripsi"`   // Kosong: keterangan mutasi
+}
+
+// ItemRekonsiliasiBuku adalah baris jurnal akun bank yang belum tercatat di rekening koran
+type ItemRekonsiliasiBuku struct {
+	IDBarisTransaksi uuid.UUID   `json:"idBarisTransaksi"`
+	IDTransaksi      uuid.UUID   `json:"idTransaksi"`
+	NomorJurnal      string      `json:"nomorJurnal"`
+	NomorReferensi   string      `json:"nomorReferensi"`
+	TanggalTransaksi time.Time   `json:"tanggalTransaksi"`
+	Deskripsi        string      `json:"deskripsi"`
+	Jumlah           models.Uang `json:"jumlah"` // Debit - kredit akun bank
+}
+
+// LaporanRekonsiliasiBank membandingkan saldo akun bank di buku besar dengan saldo rekening koran.
+//
+// Saldo bank disesuaikan = saldo bank + setoran dalam perjalanan - pengeluaran belum dicairkan
+// Saldo buku disesuaikan = saldo buku + penerimaan bank belum dicatat - pengeluaran bank belum dicatat
+type LaporanRekonsiliasiBank struct {
+	KodeAkun                       string                 `json:"kodeAkun"`
+	NamaAkun                       string                 `json:"namaAkun"`
+	TanggalPer                     time.Time              `json:"tanggalPer"`
+	TanggalMulai                   *time.Time             `json:"tanggalMulai"` // Awal rekening koran pertama
+	SaldoBuku                      models.Uang            `json:"saldoBuku"`
+	SaldoBank                      models.Uang            `json:"saldoBank"`
+	SetoranDalamPerjalanan         []ItemRekonsiliasiBuku `json:"setoranDalamPerjalanan"`
+	PengeluaranBelumDicairkan      []ItemRekonsiliasiBuku `json:"pengeluaranBelumDicairkan"`
+	PenerimaanBankBelumDicatat     []models.MutasiBank    `json:"penerimaanBankBelumDicatat"`
+	PengeluaranBankBelumDicatat    []models.MutasiBank    `json:"pengeluaranBankBelumDicatat"`
+	TotalSetoranDalamPerjalanan    models.Uang            `json:"totalSetoranDalamPerjalanan"`
+	TotalPengeluaranBelumDicairkan models.Uang            `json:"totalPengeluaranBelumDicairkan"`
+	TotalPenerimaanBelumDicatat    models.Uang            `json:"totalPenerimaanBelumDicatat"`
+	TotalPengeluaranBelumDicatat   models.Uang            `json:"totalPengeluaranBelumDicatat"`
+	SaldoBankDisesuaikan           models.Uang            `json:"saldoBankDisesuaikan"`
+	SaldoBukuDisesuaikan           models.Uang            `json:"saldoBukuDisesuaikan"`
+	Selisih                        models.Uang            `json:"selisih"`
+	Seimbang                       bool                   `json:"seimbang"`
+	Peringatan                     []string               `json:"peringatan"`
+}
+
+// akunBankWithTx mengambil akun bank berdasarkan kode (kosong berarti 1102).
+// Akun harus aktif dan bertipe aktiva.
+func akunBankWithTx(tx *gorm.DB, idKoperasi uuid.UUID, kodeAkun string) (*models.Akun, error) {
+	if kodeAkun == "" {
+		kodeAkun = kodeAkunBankDefault
+	}
+
+	var akun models.Akun
+	if err := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, kodeAkun).First(&akun).Error; err != nil {
+		return nil, fmt.Errorf("akun bank %s tidak ditemukan", kodeAkun)
+	}
+	if !akun.StatusAktif {
+		return nil, fmt.Errorf("akun bank %s tidak aktif", kodeAkun)
+	}
+	if akun.TipeAkun != models.AkunAktiva {
+		return nil, fmt.Errorf("akun %s bukan akun aktiva", kodeAkun)
+	}
+
+	return &akun, nil
+}
+
+// ImporRekeningKoran mengimpor berkas rekening koran ke akun bank lalu mencocokkan mutasinya
+// secara otomatis. Mutasi yang sudah pernah diimpor (berkas dengan periode tumpang tindih)
+// dilewati, sehingga rekening koran dapat diimpor ulang dengan aman.
+func (s *RekonsiliasiBankService) ImporRekeningKoran(idKoperasi, idPengguna uuid.UUID, req *ImporRekeningKoranRequest) (*HasilImporRekeningKoran, error) {
+	terbaca, err := BacaRekeningKoran(req.Format, req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	rekeningKoran := models.RekeningKoranBank{
+		IDKoperasi:    idKoperasi,
+		NamaBerkas:    req.NamaBerkas,
+		Format:        req.Format,
+		NomorRekening: terbaca.NomorRekening,
+		TanggalMulai:  terbaca.Mutasi[0].Tanggal,
+		TanggalAkhir:  terbaca.Mutasi[0].Tanggal,
+		SaldoAwal:     terbaca.SaldoAwal,
+		SaldoAkhir:    terbaca.SaldoAkhir,
+		DiimporOleh:   idPengguna,
+	}
+	for _, mutasi := range terbaca.Mutasi {
+		if mutasi.Tanggal.Before(rekeningKoran.TanggalMulai) {
+			rekeningKoran.TanggalMulai = mutasi.Tanggal
+		}
+		if mutasi.Tanggal.After(rekeningKoran.TanggalAkhir) {
+			rekeningKoran.TanggalAkhir = mutasi.Tanggal
+		}
+	}
+	if rekeningKoran.TanggalAkhir.After(time.Now()) {
+		return nil, errors.New("rekening koran memuat mutasi di masa depan")
+	}
+
+	hasil := &HasilImporRekeningKoran{}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		akun, err := akunBankWithTx(tx, idKoperasi, req.KodeAkun)
+		if err != nil {
+			return err
+		}
+		rekeningKoran.IDAkun = akun.ID
+
+		if createErr := tx.Create(&rekeningKoran).Error; createErr != nil {
+			return errors.New("gagal menyimpan rekening koran")
+		}
+
+		// Mutasi identik di dalam satu berkas dibedakan dengan urutan kemunculannya
+		kemunculan := make(map[string]int)
+		for _, mutasi := range terbaca.Mutasi {
+			sidik := fmt.Sprintf("%s|%d|%s|%s", mutasi.Tanggal.Format("2006-01-02"), int64(mutasi.Jumlah), mutasi.Referensi, mutasi.Keterangan)
+			kemunculan[sidik]++
+
+			baris := models.MutasiBank{
+				IDKoperasi:      idKoperasi,
+				IDAkun:          akun.ID,
+				IDRekeningKoran: rekeningKoran.ID,
+				KunciMutasi:     kunciMutasiBank(mutasi, kemunculan[sidik]),
+				Tanggal:         mutasi.Tanggal,
+				Keterangan:      mutasi.Keterangan,
+				Referensi:       potongTeks(mutasi.Referensi, 100),
+				Jumlah:          mutasi.Jumlah,
+			}
+			simpan := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&baris)
+			if simpan.Error != nil {
+				return fmt.Errorf("baris %d: gagal menyimpan mutasi", mutasi.Baris)
+			}
+			if simpan.RowsAffected == 0 {
+				hasil.JumlahDuplikat++
+			} else {
+				hasil.JumlahBaru++
+			}
+		}
+
+		rekeningKoran.JumlahMutasi = hasil.JumlahBaru
+		rekeningKoran.JumlahDuplikat = hasil.JumlahDuplikat
+		return tx.Model(&rekeningKoran).Updates(map[string]interface{}{
+			"jumlah_mutasi":   hasil.JumlahBaru,
+			"jumlah_duplikat": hasil.JumlahDuplikat,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	hasil.RekeningKoran = rekeningKoran
+
+	// Pencocokan otomatis tidak membatalkan impor; mutasi tetap dapat dicocokkan belakangan
+	if hasil.JumlahBaru > 0 {
+		jumlahCocok, cocokErr := s.cocokkanOtomatisAkun(idKoperasi, rekeningKoran.IDAkun)
+		if cocokErr != nil {
+			log.Printf("Gagal mencocokkan otomatis mutasi rekening koran %s: %v", rekeningKoran.ID, cocokErr)
+		}
+		hasil.JumlahCocok = jumlahCocok
+	}
+
+	return hasil, nil
+}
+
+// potongTeks memotong teks ke panjang maksimum kolom
+func potongTeks(teks string, maks int) string {
+	if len(teks) <= maks {
+		return teks
+	}
+	return teks[:maks]
+}
+
+// DapatkanRekeningKoran mengambil daftar rekening koran yang sudah diimpor, terbaru lebih dulu
+func (s *RekonsiliasiBankService) DapatkanRekeningKoran(idKoperasi uuid.UUID, kodeAkun string) ([]models.RekeningKoranBank, error) {
+	akun, err := akunBankWithTx(s.db, idKoperasi, kodeAkun)
+	if err != nil {
+		return nil, err
+	}
+
+	var rekeningKoranList []models.RekeningKoranBank
+	err = s.db.Where("id_koperasi = ? AND id_akun = ?", idKoperasi, akun.ID).
+		Order("tanggal_mulai DESC, tanggal_dibuat DESC").
+		Find(&rekeningKoranList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar rekening koran")
+	}
+
+	return rekeningKoranList, nil
+}
+
+// HapusRekeningKoran menghapus rekening koran beserta mutasinya, misalnya karena salah berkas.
+// Rekening koran yang mutasinya sudah dicocokkan harus dibatalkan pencocokannya lebih dulu.
+func (s *RekonsiliasiBankService) HapusRekeningKoran(id, idKoperasi uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var rekeningKoran models.RekeningKoranBank
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&rekeningKoran).Error
+		if err != nil {
+			return errors.New("rekening koran tidak ditemukan")
+		}
+
+		var jumlahCocok int64
+		tx.Model(&models.MutasiBank{}).
+			Where("id_rekening_koran = ? AND status = ?", id, models.StatusMutasiCocok).
+			Count(&jumlahCocok)
+		if jumlahCocok > 0 {
+			return fmt.Errorf("%d mutasi rekening koran sudah dicocokkan, batalkan pencocokannya terlebih dahulu", jumlahCocok)
+		}
+
+		if deleteErr := tx.Where("id_rekening_koran = ?", id).Delete(&models.MutasiBank{}).Error; deleteErr != nil {
+			return errors.New("gagal menghapus mutasi rekening koran")
+		}
+		if deleteErr := tx.Delete(&rekeningKoran).Error; deleteErr != nil {
+			return errors.New("gagal menghapus rekening koran")
+		}
+		return nil
+	})
+}
+
+// DapatkanMutasiBank mengambil mutasi rekening koran akun bank, diurutkan berdasarkan tanggal
+func (s *RekonsiliasiBankService) DapatkanMutasiBank(idKoperasi uuid.UUID, filter FilterMutasiBank) ([]models.MutasiBank, error) {
+	akun, err := akunBankWithTx(s.db, idKoperasi, filter.KodeAkun)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("id_koperasi = ? AND id_akun = ?", idKoperasi, akun.ID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.IDRekeningKoran != nil {
+		query = query.Where("id_rekening_koran = ?", *filter.IDRekeningKoran)
+	}
+	if filter.TanggalMulai != "" {
+		query = query.Where("tanggal >= ?", filter.TanggalMulai)
+	}
+	if filter.TanggalAkhir != "" {
+		query = query.Where("tanggal <= ?", filter.TanggalAkhir)
+	}
+
+	var mutasiList []models.MutasiBank
+	if err := query.Order("tanggal ASC, tanggal_dibuat ASC").Find(&mutasiList).Error; err != nil {
+		return nil, errors.New("gagal mengambil mutasi bank")
+	}
+
+	return mutasiList, nil
+}
+
+// kandidatCocokBank adalah baris jurnal akun bank yang belum dicocokkan dengan mutasi
+type kandidatCocokBank struct {
+	IDBarisTransaksi uuid.UUID
+	IDTransaksi      uuid.UUID
+	TanggalTransaksi time.Time
+	NomorReferensi   string
+	Jumlah           models.Uang
+}
+
+// queryKandidatCocokBank memilih baris jurnal posted akun bank yang dapat dicocokkan:
+// belum dipasangkan dengan mutasi, dan bukan bagian dari jurnal yang dibalik maupun jurnal
+// pembaliknya (keduanya saling meniadakan dan tidak pernah muncul di rekening koran).
+func queryKandidatCocokBank(tx *gorm.DB, idKoperasi, idAkun uuid.UUID) *gorm.DB {
+	return tx.Table("baris_transaksi").
+		Select("baris_transaksi.id as id_baris_transaksi, transaksi.id as id_transaksi, transaksi.tanggal_transaksi, "+
+			"transaksi.nomor_referensi, baris_transaksi.jumlah_debit - baris_transaksi.jumlah_kredit as jumlah").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND baris_transaksi.id_akun = ? AND "+kondisiJurnalPosted, idKoperasi, idAkun).
+		Where("transaksi.dibalik = ? AND transaksi.id_jurnal_asal IS NULL", false).
+		Where("NOT EXISTS (SELECT 1 FROM mutasi_bank WHERE mutasi_bank.id_baris_transaksi = baris_transaksi.id)")
+}
+
+// CocokkanOtomatis mencocokkan mutasi bank yang belum cocok dengan baris jurnal akun bank.
+// Nominal harus sama persis (dana masuk dengan debit, dana keluar dengan kredit), lalu:
+//  1. Referensi: nomor referensi jurnal sama dengan referensi mutasi atau tercantum di
+//     keterangannya, dalam jendela ±31 hari
+//  2. Tanggal: jurnal dengan selisih tanggal terkecil dalam ±3 hari
+//
+// Mutasi hanya dicocokkan jika kandidat terbaiknya tunggal; sisanya dicocokkan manual.
+func (s *RekonsiliasiBankService) CocokkanOtomatis(idKoperasi uuid.UUID, kodeAkun string) (int, error) {
+	akun, err := akunBankWithTx(s.db, idKoperasi, kodeAkun)
+	if err != nil {
+		return 0, err
+	}
+	return s.cocokkanOtomatisAkun(idKoperasi, akun.ID)
+}
+
+// cocokkanOtomatisAkun menjalankan pencocokan otomatis untuk satu akun bank
+func (s *RekonsiliasiBankService) cocokkanOtomatisAkun(idKoperasi, idAkun uuid.UUID) (int, error) {
+	jumlahCocok := 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var mutasiList []models.MutasiBank
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id_koperasi = ? AND id_akun = ? AND status = ?", idKoperasi, idAkun, models.StatusMutasiBelumCocok).
+			Order("tanggal ASC, tanggal_dibuat ASC").
+			Find(&mutasiList).Error
+		if err != nil {
+			return errors.New("gagal mengambil mutasi bank")
+		}
+		if len(mutasiList) == 0 {
+			return nil
+		}
+
+		awal := mutasiList[0].Tanggal.AddDate(0, 0, -jendelaCocokReferensiHari)
+		akhir := mutasiList[len(mutasiList)-1].Tanggal.AddDate(0, 0, jendelaCocokReferensiHari)
+		var kandidatList []kandidatCocokBank
+		err = queryKandidatCocokBank(tx, idKoperasi, idAkun).
+			Where("transaksi.tanggal_transaksi BETWEEN ? AND ?", awal.Format("2006-01-02"), akhir.Format("2006-01-02")).
+			Order("transaksi.tanggal_transaksi ASC, transaksi.nomor_jurnal ASC").
+			Scan(&kandidatList).Error
+		if err != nil {
+			return errors.New("gagal mengambil baris jurnal akun bank")
+		}
+
+		pasangan := pasangkanMutasiBank(mutasiList, kandidatList)
+		sekarang := time.Now()
+		for i := range mutasiList {
+			kandidat, ada := pasangan[mutasiList[i].ID]
+			if !ada {
+				continue
+			}
+			if err := tandaiCocokWithTx(tx, &mutasiList[i], kandidat.IDBarisTransaksi, kandidat.IDTransaksi, models.MetodeCocokOtomatis, nil, sekarang); err != nil {
+				return err
+			}
+			jumlahCocok++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return jumlahCocok, nil
+}
+
+// pasangkanMutasiBank memasangkan mutasi dengan kandidat baris jurnal (lihat CocokkanOtomatis).
+// Setiap kandidat hanya dipakai sekali; tahap referensi diselesaikan untuk semua mutasi
+// sebelum tahap tanggal agar pasangan berreferensi tidak direbut mutasi lain.
+func pasangkanMutasiBank(mutasiList []models.MutasiBank, kandidatList []kandidatCocokBank) map[uuid.UUID]kandidatCocokBank {
+	pasangan := make(map[uuid.UUID]kandidatCocokBank)
+	terpakai := make(map[uuid.UUID]bool)
+
+	selisihHari := func(mutasi *models.MutasiBank, kandidat *kandidatCocokBank) int {
+		hari := int(mutasi.Tanggal.Sub(kandidat.TanggalTransaksi).Hours() / 24)
+		if hari < 0 {
+			return -hari
+		}
+		return hari
+	}
+	referensiCocok := func(mutasi *models.MutasiBank, kandidat *kandidatCocokBank) bool {
+		nomor := strings.ToUpper(strings.TrimSpace(kandidat.NomorReferensi))
+		if nomor == "" {
+			return false
+		}
+		return nomor == strings.ToUpper(strings.TrimSpace(mutasi.Referensi)) ||
+			strings.Contains(strings.ToUpper(mutasi.Keterangan), nomor)
+	}
+
+	tahap := func(cocok func(mutasi *models.MutasiBank, kandidat *kandidatCocokBank) bool, jendela int) {
+		for i := range mutasiList {
+			mutasi := &mutasiList[i]
+			if _, ada := pasangan[mutasi.ID]; ada {
+				continue
+			}
+
+			terbaik, jumlahTerbaik, selisihTerbaik := -1, 0, 0
+			for j := range kandidatList {
+				kandidat := &kandidatList[j]
+				if terpakai[kandidat.IDBarisTransaksi] || kandidat.Jumlah != mutasi.Jumlah || !cocok(mutasi, kandidat) {
+					continue
+				}
+				selisih := selisihHari(mutasi, kandidat)
+				switch {
+				case selisih > jendela:
+				case terbaik < 0 || selisih < selisihTerbaik:
+					terbaik, jumlahTerbaik, selisihTerbaik = j, 1, selisih
+				case selisih == selisihTerbaik:
+					jumlahTerbaik++
+				}
+			}
+			if terbaik >= 0 && jumlahTerbaik == 1 {
+				pasangan[mutasi.ID] = kandidatList[terbaik]
+				terpakai[kandidatList[terbaik].IDBarisTransaksi] = true
+			}
+		}
+	}
+
+	tahap(referensiCocok, jendelaCocokReferensiHari)
+	tahap(func(*models.MutasiBank, *kandidatCocokBank) bool { return true }, jendelaCocokTanggalHari)
+
+	return pasangan
+}
+
+// tandaiCocokWithTx menyimpan pasangan mutasi dan baris jurnal. Unique index pada
+// id_baris_transaksi menolak baris jurnal yang sudah dipasangkan dengan mutasi lain.
+func tandaiCocokWithTx(tx *gorm.DB, mutasi *models.MutasiBank, idBarisTransaksi, idTransaksi uuid.UUID, metode models.MetodeCocokBank, idPengguna *uuid.UUID, waktu time.Time) error {
+	hasil := tx.Model(&models.MutasiBank{}).
+		Where("id = ? AND status = ?", mutasi.ID, models.StatusMutasiBelumCocok).
+		Updates(map[string]interface{}{
+			"status":             models.StatusMutasiCocok,
+			"id_baris_transaksi": idBarisTransaksi,
+			"id_transaksi":       idTransaksi,
+			"metode_cocok":       metode,
+			"dicocokkan_oleh":    idPengguna,
+			"tanggal_dicocokkan": waktu,
+		})
+	if hasil.Error != nil {
+		return errors.New("gagal mencocokkan mutasi bank, baris jurnal mungkin sudah dicocokkan dengan mutasi lain")
+	}
+	if hasil.RowsAffected == 0 {
+		return errors.New("mutasi bank sudah dicocokkan")
+	}
+
+	mutasi.Status = models.StatusMutasiCocok
+	mutasi.IDBarisTransaksi = &idBarisTransaksi
+	mutasi.IDTransaksi = &idTransaksi
+	mutasi.MetodeCocok = metode
+	mutasi.DicocokkanOleh = idPengguna
+	mutasi.TanggalDicocokkan = &waktu
+	return nil
+}
+
+// mutasiBankTerkunciWithTx mengambil dan mengunci mutasi bank milik koperasi
+func mutasiBankTerkunciWithTx(tx *gorm.DB, idKoperasi, idMutasi uuid.UUID) (*models.MutasiBank, error) {
+	var mutasi models.MutasiBank
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND id_koperasi = ?", idMutasi, idKoperasi).
+		First(&mutasi).Error
+	if err != nil {
+		return nil, errors.New("mutasi bank tidak ditemukan")
+	}
+	return &mutasi, nil
+}
+
+// CocokkanManual memasangkan mutasi bank dengan baris jurnal pilihan pengguna.
+// Baris jurnal harus milik akun bank yang sama, sudah di-post, dan bernominal sama.
+func (s *RekonsiliasiBankService) CocokkanManual(idKoperasi, idPengguna, idMutasi uuid.UUID, req *CocokkanMutasiRequest) (*models.MutasiBank, error) {
+	var mutasi *models.MutasiBank
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		mutasi, err = mutasiBankTerkunciWithTx(tx, idKoperasi, idMutasi)
+		if err != nil {
+			return err
+		}
+		if mutasi.Status == models.StatusMutasiCocok {
+			return errors.New("mutasi bank sudah dicocokkan")
+		}
+
+		var kandidat kandidatCocokBank
+		err = queryKandidatCocokBank(tx, idKoperasi, mutasi.IDAkun).
+			Where("baris_transaksi.id = ?", req.IDBarisTransaksi).
+			Scan(&kandidat).Error
+		if err != nil {
+			return errors.New("gagal mengambil baris jurnal")
+		}
+		if kandidat.IDBarisTransaksi == uuid.Nil {
+			return errors.New("baris jurnal tidak ditemukan di akun bank, belum di-post, sudah dibalik, atau sudah dicocokkan")
+		}
+		if kandidat.Jumlah != mutasi.Jumlah {
+			return fmt.Errorf("nominal baris jurnal (%s) berbeda dengan mutasi bank (%s)", kandidat.Jumlah, mutasi.Jumlah)
+		}
+
+		return tandaiCocokWithTx(tx, mutasi, kandidat.IDBarisTransaksi, kandidat.IDTransaksi, models.MetodeCocokManual, &idPengguna, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mutasi, nil
+}
+
+// BatalkanCocok melepas pasangan mutasi bank dengan baris jurnal. Jika jurnalnya dibuat dari
+// mutasi ini (BuatJurnalMutasi), jurnal tersebut ikut dibalik pada tanggal hari ini.
+func (s *RekonsiliasiBankService) BatalkanCocok(idKoperasi, idPengguna, idMutasi uuid.UUID, req *BatalkanCocokRequest) (*models.MutasiBank, error) {
+	var mutasi *models.MutasiBank
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		mutasi, err = mutasiBankTerkunciWithTx(tx, idKoperasi, idMutasi)
+		if err != nil {
+			return err
+		}
+		if mutasi.Status != models.StatusMutasiCocok {
+			return errors.New("mutasi bank belum dicocokkan")
+		}
+
+		if mutasi.MetodeCocok == models.MetodeCocokJurnal && mutasi.IDTransaksi != nil {
+			if validasiErr := validasi.Baru().TeksWajib(req.Alasan, "alasan pembalikan", 5, 500); validasiErr != nil {
+				return validasiErr
+			}
+			if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *mutasi.IDTransaksi, hariIniUTC(), req.Alasan, models.TipeTransaksiBank); balikErr != nil {
+				return fmt.Errorf("gagal membalik jurnal mutasi bank: %w", balikErr)
+			}
+		}
+
+		updateErr := tx.Model(&models.MutasiBank{}).Where("id = ?", mutasi.ID).
+			Updates(map[string]interface{}{
+				"status":             models.StatusMutasiBelumCocok,
+				"id_baris_transaksi": nil,
+				"id_transaksi":       nil,
+				"metode_cocok":       "",
+				"dicocokkan_oleh":    nil,
+				"tanggal_dicocokkan": nil,
+			}).Error
+		if updateErr != nil {
+			return errors.New("gagal membatalkan pencocokan mutasi bank")
+		}
+
+		mutasi.Status = models.StatusMutasiBelumCocok
+		mutasi.IDBarisTransaksi = nil
+		mutasi.IDTransaksi = nil
+		mutasi.MetodeCocok = ""
+		mutasi.DicocokkanOleh = nil
+		mutasi.TanggalDicocokkan = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mutasi, nil
+}
+
+// BuatJurnalMutasi membuat jurnal untuk mutasi bank yang belum tercatat di buku, seperti biaya
+// administrasi bank atau jasa giro, lalu langsung mencocokkan mutasi dengan jurnal tersebut.
+//
+// Jurnal yang dibuat (tanggal mutasi):
+//   - Dana masuk:  Debit akun bank, Kredit akun lawan (default jasa giro)
+//   - Dana keluar: Debit akun lawan (default biaya bank), Kredit akun bank
+func (s *RekonsiliasiBankService) BuatJurnalMutasi(idKoperasi, idPengguna, idMutasi uuid.UUID, req *BuatJurnalMutasiRequest) (*models.MutasiBank, error) {
+	var mutasi *models.MutasiBank
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		mutasi, err = mutasiBankTerkunciWithTx(tx, idKoperasi, idMutasi)
+		if err != nil {
+			return err
+		}
+		if mutasi.Status == models.StatusMutasiCocok {
+			return errors.New("mutasi bank sudah dicocokkan")
+		}
+
+		var idAkunLawan uuid.UUID
+		if req.IDAkunLawan != nil {
+			var akunLawan models.Akun
+			if findErr := tx.Where("id = ? AND id_koperasi = ?", *req.IDAkunLawan, idKoperasi).First(&akunLawan).Error; findErr != nil {
+				return errors.New("akun lawan tidak ditemukan")
+			}
+			if !akunLawan.StatusAktif {
+				return fmt.Errorf("akun lawan %s tidak aktif", akunLawan.KodeAkun)
+			}
+			idAkunLawan = akunLawan.ID
+		} else {
+			akunPosting, akunErr := akunPostingWithTx(tx, idKoperasi, models.PeristiwaMutasiBank)
+			if akunErr != nil {
+				return akunErr
+			}
+			peran := models.PeranAkunBiayaBank
+			if mutasi.Jumlah > 0 {
+				peran = models.PeranAkunJasaGiro
+			}
+			idAkunLawan = akunPosting[peran].ID
+		}
+		if idAkunLawan == mutasi.IDAkun {
+			return errors.New("akun lawan tidak boleh sama dengan akun bank")
+		}
+
+		deskripsi := strings.TrimSpace(req.Deskripsi)
+		if deskripsi == "" {
+			deskripsi = strings.TrimSpace(mutasi.Keterangan)
+		}
+		if len(deskripsi) < 5 {
+			deskripsi = fmt.Sprintf("Mutasi bank %s", mutasi.Tanggal.Format("02/01/2006"))
+		}
+		deskripsi = potongTeks(deskripsi, 500)
+
+		barisBank := BuatBarisTransaksiRequest{IDAkun: mutasi.IDAkun, Keterangan: deskripsi}
+		barisLawan := BuatBarisTransaksiRequest{IDAkun: idAkunLawan, Keterangan: deskripsi}
+		if mutasi.Jumlah > 0 {
+			barisBank.JumlahDebit, barisLawan.JumlahKredit = mutasi.Jumlah, mutasi.Jumlah
+		} else {
+			barisLawan.JumlahDebit, barisBank.JumlahKredit = -mutasi.Jumlah, -mutasi.Jumlah
+		}
+
+		transaksi, err := s.transaksiService.BuatTransaksiWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+			TanggalTransaksi: mutasi.Tanggal,
+			Deskripsi:        deskripsi,
+			NomorReferensi:   potongTeks(mutasi.Referensi, 50),
+			TipeTransaksi:    models.TipeTransaksiBank,
+			BarisTransaksi:   []BuatBarisTransaksiRequest{barisBank, barisLawan},
+		})
+		if err != nil {
+			return fmt.Errorf("gagal posting jurnal mutasi bank: %w", err)
+		}
+
+		var baris models.BarisTransaksi
+		if findErr := tx.Where("id_transaksi = ? AND id_akun = ?", transaksi.ID, mutasi.IDAkun).First(&baris).Error; findErr != nil {
+			return errors.New("gagal mengambil baris jurnal akun bank")
+		}
+
+		return tandaiCocokWithTx(tx, mutasi, baris.ID, transaksi.ID, models.MetodeCocokJurnal, &idPengguna, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mutasi, nil
+}
+
+// LaporanRekonsiliasiBank menyusun rekonsiliasi bank akun kodeAkun per tanggalPer (kosong berarti hari ini).
+//
+// Saldo bank dihitung dari saldo awal rekening koran pertama ditambah seluruh mutasi sampai
+// tanggalPer. Baris jurnal akun bank sejak awal rekening koran pertama yang belum dicocokkan
+// dengan mutasi sampai tanggalPer menjadi setoran dalam perjalanan atau pengeluaran yang belum
+// dicairkan; mutasi sampai tanggalPer yang belum dicocokkan (atau pasangannya dijurnal setelah
+// tanggalPer) menjadi penerimaan atau pengeluaran bank yang belum dicatat. Transaksi sebelum
+// rekening koran pertama dianggap sudah tercermin di saldo awalnya.
+func (s *RekonsiliasiBankService) LaporanRekonsiliasiBank(idKoperasi uuid.UUID, kodeAkun, tanggalPer string) (*LaporanRekonsiliasiBank, error) {
+	tanggal := hariIniUTC()
+	if tanggalPer != "" {
+		var err error
+		tanggal, err = time.Parse("2006-01-02", tanggalPer)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid")
+		}
+	}
+	per := tanggal.Format("2006-01-02")
+
+	akun, err := akunBankWithTx(s.db, idKoperasi, kodeAkun)
+	if err != nil {
+		return nil, err
+	}
+
+	laporan := &LaporanRekonsiliasiBank{
+		KodeAkun:                    akun.KodeAkun,
+		NamaAkun:                    akun.NamaAkun,
+		TanggalPer:                  tanggal,
+		SetoranDalamPerjalanan:      []ItemRekonsiliasiBuku{},
+		PengeluaranBelumDicairkan:   []ItemRekonsiliasiBuku{},
+		PenerimaanBankBelumDicatat:  []models.MutasiBank{},
+		PengeluaranBankBelumDicatat: []models.MutasiBank{},
+		Peringatan:                  []string{},
+	}
+
+	// Step 1: Saldo buku akun bank (debit - kredit)
+	mutasiAkun, err := hitungTotalMutasiAkun(s.db, idKoperasi, &akun.ID, per, nil)
+	if err != nil {
+		return nil, err
+	}
+	laporan.SaldoBuku = mutasiAkun[akun.ID].TotalDebit - mutasiAkun[akun.ID].TotalKredit
+
+	// Step 2: Saldo bank dari rekening koran pertama
+	var rekeningKoranPertama models.RekeningKoranBank
+	err = s.db.Where("id_koperasi = ? AND id_akun = ? AND tanggal_mulai <= ?", idKoperasi, akun.ID, per).
+		Order("tanggal_mulai ASC, tanggal_dibuat ASC").
+		First(&rekeningKoranPertama).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("gagal mengambil rekening koran")
+		}
+		laporan.Peringatan = append(laporan.Peringatan, "Belum ada rekening koran yang diimpor sampai tanggal ini")
+		laporan.SaldoBank = laporan.SaldoBuku
+		laporan.SaldoBankDisesuaikan = laporan.SaldoBuku
+		laporan.SaldoBukuDisesuaikan = laporan.SaldoBuku
+		laporan.Seimbang = true
+		return laporan, nil
+	}
+	mulai := rekeningKoranPertama.TanggalMulai
+	laporan.TanggalMulai = &mulai
+
+	if rekeningKoranPertama.SaldoAwal == nil {
+		laporan.Peringatan = append(laporan.Peringatan,
+			fmt.Sprintf("Rekening koran %s tidak memuat saldo, saldo bank dihitung dari 0", rekeningKoranPertama.NamaBerkas))
+	} else {
+		laporan.SaldoBank = *rekeningKoranPertama.SaldoAwal
+	}
+
+	var totalMutasi struct{ Total models.Uang }
+	err = s.db.Model(&models.MutasiBank{}).
+		Select("COALESCE(SUM(jumlah), 0) as total").
+		Where("id_koperasi = ? AND id_akun = ? AND tanggal <= ?", idKoperasi, akun.ID, per).
+		Scan(&totalMutasi).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung saldo bank")
+	}
+	laporan.SaldoBank += totalMutasi.Total
+
+	// Step 3: Baris jurnal yang belum tercatat di rekening koran sampai tanggalPer
+	var itemBuku []ItemRekonsiliasiBuku
+	err = s.db.Table("baris_transaksi").
+		Select("baris_transaksi.id as id_baris_transaksi, transaksi.id as id_transaksi, transaksi.nomor_jurnal, "+
+			"transaksi.nomor_referensi, transaksi.tanggal_transaksi, transaksi.deskripsi, "+
+			"baris_transaksi.jumlah_debit - baris_transaksi.jumlah_kredit as jumlah").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND baris_transaksi.id_akun = ? AND "+kondisiJurnalPosted, idKoperasi, akun.ID).
+		Where("transaksi.tanggal_transaksi BETWEEN ? AND ?", mulai.Format("2006-01-02"), per).
+		Where("baris_transaksi.jumlah_debit <> baris_transaksi.jumlah_kredit").
+		Where("NOT EXISTS (SELECT 1 FROM mutasi_bank WHERE mutasi_bank.id_baris_transaksi = baris_transaksi.id AND mutasi_bank.tanggal <= ?)", per).
+		Order("transaksi.tanggal_transaksi ASC, transaksi.nomor_jurnal ASC").
+		Scan(&itemBuku).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil baris jurnal akun bank")
+	}
+	for _, item := range itemBuku {
+		if item.Jumlah > 0 {
+			laporan.SetoranDalamPerjalanan = append(laporan.SetoranDalamPerjalanan, item)
+			laporan.TotalSetoranDalamPerjalanan += item.Jumlah
+		} else {
+			item.Jumlah = -item.Jumlah
+			laporan.PengeluaranBelumDicairkan = append(laporan.PengeluaranBelumDicairkan, item)
+			laporan.TotalPengeluaranBelumDicairkan += item.Jumlah
+		}
+	}
+
+	// Step 4: Mutasi bank yang belum tercatat di buku sampai tanggalPer
+	var mutasiList []models.MutasiBank
+	err = s.db.Where("id_koperasi = ? AND id_akun = ? AND tanggal <= ?", idKoperasi, akun.ID, per).
+		Where("(id_transaksi IS NULL OR id_transaksi IN (?))",
+			s.db.Model(&models.Transaksi{}).Select("id").Where("tanggal_transaksi > ?", per)).
+		Order("tanggal ASC, tanggal_dibuat ASC").
+		Find(&mutasiList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil mutasi bank")
+	}
+	for _, mutasi := range mutasiList {
+		if mutasi.Jumlah > 0 {
+			laporan.PenerimaanBankBelumDicatat = append(laporan.PenerimaanBankBelumDicatat, mutasi)
+			laporan.TotalPenerimaanBelumDicatat += mutasi.Jumlah
+		} else {
+			laporan.PengeluaranBankBelumDicatat = append(laporan.PengeluaranBankBelumDicatat, mutasi)
+			laporan.TotalPengeluaranBelumDicatat -= mutasi.Jumlah
+		}
+	}
+
+	// Step 5: Saldo disesuaikan
+	laporan.SaldoBankDisesuaikan = laporan.SaldoBank + laporan.TotalSetoranDalamPerjalanan - laporan.TotalPengeluaranBelumDicairkan
+	laporan.SaldoBukuDisesuaikan = laporan.SaldoBuku + laporan.TotalPenerimaanBelumDicatat - laporan.TotalPengeluaranBelumDicatat
+	laporan.Selisih = laporan.SaldoBankDisesuaikan - laporan.SaldoBukuDisesuaikan
+	laporan.Seimbang = laporan.Selisih == 0
+
+	return laporan, nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasangkanMutasiBank(t *testing.T) {
+	tanggal := mustParseTime("2026-03-10")
+	mutasiRef := models.MutasiBank{ID: uuid.New(), Tanggal: tanggal, Jumlah: models.Rupiah(500000), Keterangan: "TRF INV-77 PT MAJU"}
+	mutasiTanggal := models.MutasiBank{ID: uuid.New(), Tanggal: tanggal, Jumlah: models.Rupiah(500000)}
+	mutasiGanda := models.MutasiBank{ID: uuid.New(), Tanggal: tanggal, Jumlah: models.Rupiah(-75000)}
+
+	kandidatRef := kandidatCocokBank{IDBarisTransaksi: uuid.New(), TanggalTransaksi: tanggal.AddDate(0, 0, -20), NomorReferensi: "inv-77", Jumlah: models.Rupiah(500000)}
+	kandidatDekat := kandidatCocokBank{IDBarisTransaksi: uuid.New(), TanggalTransaksi: tanggal.AddDate(0, 0, -1), Jumlah: models.Rupiah(500000)}
+	kandidatJauh := kandidatCocokBank{IDBarisTransaksi: uuid.New(), TanggalTransaksi: tanggal.AddDate(0, 0, -3), Jumlah: models.Rupiah(500000)}
+	kandidatGanda1 := kandidatCocokBank{IDBarisTransaksi: uuid.New(), TanggalTransaksi: tanggal.AddDate(0, 0, 2), Jumlah: models.Rupiah(-75000)}
+	kandidatGanda2 := kandidatCocokBank{IDBarisTransaksi: uuid.New(), TanggalTransaksi: tanggal.AddDate(0, 0, -2), Jumlah: models.Rupiah(-75000)}
+
+	pasangan := pasangkanMutasiBank(
+		[]models.MutasiBank{mutasiTanggal, mutasiRef, mutasiGanda},
+		[]kandidatCocokBank{kandidatDekat, kandidatRef, kandidatJauh, kandidatGanda1, kandidatGanda2},
+	)
+
+	// Referensi di keterangan menang walaupun tanggalnya lebih jauh
+	assert.Equal(t, kandidatRef.IDBarisTransaksi, pasangan[mutasiRef.ID].IDBarisTransaksi)
+	// Tanpa referensi dipilih selisih tanggal terkecil
+	assert.Equal(t, kandidatDekat.IDBarisTransaksi, pasangan[mutasiTanggal.ID].IDBarisTransaksi)
+	// Dua kandidat dengan selisih tanggal sama tidak dicocokkan otomatis
+	_, ada := pasangan[mutasiGanda.ID]
+	assert.False(t, ada)
+
+	// Kandidat di luar jendela tanggal tidak dipakai
+	pasangan = pasangkanMutasiBank(
+		[]models.MutasiBank{mutasiTanggal},
+		[]kandidatCocokBank{{IDBarisTransaksi: uuid.New(), TanggalTransaksi: tanggal.AddDate(0, 0, -4), Jumlah: models.Rupiah(500000)}},
+	)
+	assert.Empty(t, pasangan)
+}
+
+func TestRekonsiliasiBankService(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.SaldoBulananAkun{},
+		&models.UnitUsaha{},
+		&models.AturanPosting{},
+		&models.RekeningKoranBank{},
+		&models.MutasiBank{},
+	)
+	require.NoError(t, err)
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Rekonsiliasi Bank Koperasi", Alamat: "Test Address", TahunBukuMulai: 1}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	bank, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1102")
+	biayaBank, _ := akunService.DapatkanAkunByKode(koperasi.ID, "5302")
+
+	transaksiService := NewTransaksiService(db)
+	rekonsiliasiBankService := NewRekonsiliasiBankService(db, transaksiService)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	hariIni := hariIniUTC()
+	tgl := func(hari int) string { return hariIni.AddDate(0, 0, hari).Format("2006-01-02") }
+	jurnal := func(hari int, referensi string, debit, kredit uuid.UUID, jumlah int64) {
+		_, err := transaksiService.BuatTransaksi(koperasi.ID, admin, &BuatTransaksiRequest{
+			TanggalTransaksi: hariIni.AddDate(0, 0, hari),
+			Deskripsi:        "Mutasi kas dan bank",
+			NomorReferensi:   referensi,
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: debit, JumlahDebit: models.Rupiah(jumlah)},
+				{IDAkun: kredit, JumlahKredit: models.Rupiah(jumlah)},
+			},
+		})
+		require.NoError(t, err)
+	}
+	jurnal(-10, "SET-01", bank.ID, kas.ID, 1000000) // Setoran, dicatat bank 3 hari kemudian
+	jurnal(-8, "", kas.ID, bank.ID, 250000)         // Penarikan
+	jurnal(-2, "", bank.ID, kas.ID, 300000)         // Setoran dalam perjalanan
+
+	berkas := []byte(fmt.Sprintf("tanggal,keterangan,referensi,debit,kredit,saldo\n"+
+		"%s,Setoran SET-01,,0,1000000,1000000\n"+
+		"%s,Tarik tunai,,250000,0,750000\n"+
+		"%s,Biaya administrasi,,15000,0,735000\n"+
+		"%s,Jasa giro,,0,5000,740000\n", tgl(-7), tgl(-7), tgl(-1), tgl(-1)))
+
+	hasil, err := rekonsiliasiBankService.ImporRekeningKoran(koperasi.ID, admin, &ImporRekeningKoranRequest{
+		Format: models.FormatRekeningKoranCSV, NamaBerkas: "rekening-koran.csv", Data: berkas,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, hasil.JumlahBaru)
+	assert.Equal(t, 2, hasil.JumlahCocok, "setoran dicocokkan lewat referensi, penarikan lewat tanggal")
+	require.NotNil(t, hasil.RekeningKoran.SaldoAwal)
+	assert.Equal(t, models.Uang(0), *hasil.RekeningKoran.SaldoAwal)
+
+	t.Run("impor ulang melewati mutasi yang sudah ada", func(t *testing.T) {
+		ulang, err := rekonsiliasiBankService.ImporRekeningKoran(koperasi.ID, admin, &ImporRekeningKoranRequest{
+			Format: models.FormatRekeningKoranCSV, NamaBerkas: "rekening-koran.csv", Data: berkas,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, ulang.JumlahBaru)
+		assert.Equal(t, 4, ulang.JumlahDuplikat)
+		require.NoError(t, rekonsiliasiBankService.HapusRekeningKoran(ulang.RekeningKoran.ID, koperasi.ID))
+
+		assert.Error(t, rekonsiliasiBankService.HapusRekeningKoran(hasil.RekeningKoran.ID, koperasi.ID),
+			"rekening koran dengan mutasi yang sudah dicocokkan tidak dapat dihapus")
+	})
+
+	belumCocok, err := rekonsiliasiBankService.DapatkanMutasiBank(koperasi.ID, FilterMutasiBank{Status: models.StatusMutasiBelumCocok})
+	require.NoError(t, err)
+	require.Len(t, belumCocok, 2)
+	var mutasiBiaya, mutasiJasaGiro models.MutasiBank
+	for _, mutasi := range belumCocok {
+		if mutasi.Jumlah < 0 {
+			mutasiBiaya = mutasi
+		} else {
+			mutasiJasaGiro = mutasi
+		}
+	}
+
+	t.Run("laporan rekonsiliasi seimbang", func(t *testing.T) {
+		laporan, err := rekonsiliasiBankService.LaporanRekonsiliasiBank(koperasi.ID, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(1050000), laporan.SaldoBuku)
+		assert.Equal(t, models.Rupiah(740000), laporan.SaldoBank)
+		require.Len(t, laporan.SetoranDalamPerjalanan, 1)
+		assert.Equal(t, models.Rupiah(300000), laporan.TotalSetoranDalamPerjalanan)
+		assert.Equal(t, models.Rupiah(5000), laporan.TotalPenerimaanBelumDicatat)
+		assert.Equal(t, models.Rupiah(15000), laporan.TotalPengeluaranBelumDicatat)
+		assert.Equal(t, models.Rupiah(1040000), laporan.SaldoBankDisesuaikan)
+		assert.True(t, laporan.Seimbang)
+	})
+
+	t.Run("cocokkan manual memeriksa nominal", func(t *testing.T) {
+		var barisSetoran models.BarisTransaksi
+		require.NoError(t, db.Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+			Where("transaksi.id_koperasi = ? AND baris_transaksi.id_akun = ? AND baris_transaksi.jumlah_debit = ?", koperasi.ID, bank.ID, models.Rupiah(300000)).
+			First(&barisSetoran).Error)
+
+		_, err := rekonsiliasiBankService.CocokkanManual(koperasi.ID, admin, mutasiJasaGiro.ID, &CocokkanMutasiRequest{IDBarisTransaksi: barisSetoran.ID})
+		assert.Error(t, err)
+	})
+
+	t.Run("jurnal biaya bank dari mutasi", func(t *testing.T) {
+		mutasi, err := rekonsiliasiBankService.BuatJurnalMutasi(koperasi.ID, admin, mutasiBiaya.ID, &BuatJurnalMutasiRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusMutasiCocok, mutasi.Status)
+		assert.Equal(t, models.MetodeCocokJurnal, mutasi.MetodeCocok)
+		require.NotNil(t, mutasi.IDTransaksi)
+
+		var jurnalBiaya models.Transaksi
+		require.NoError(t, db.Preload("BarisTransaksi").First(&jurnalBiaya, "id = ?", *mutasi.IDTransaksi).Error)
+		assert.Equal(t, models.TipeTransaksiBank, jurnalBiaya.TipeTransaksi)
+		assert.Equal(t, models.StatusJurnalPosted, jurnalBiaya.Status)
+		for _, baris := range jurnalBiaya.BarisTransaksi {
+			if baris.IDAkun == biayaBank.ID {
+				assert.Equal(t, models.Rupiah(15000), baris.JumlahDebit)
+			}
+		}
+
+		laporan, err := rekonsiliasiBankService.LaporanRekonsiliasiBank(koperasi.ID, "", "")
+		require.NoError(t, err)
+		assert.Empty(t, laporan.PengeluaranBankBelumDicatat)
+		assert.True(t, laporan.Seimbang)
+
+		// Jurnal mutasi bank hanya dapat dibatalkan melalui pembatalan pencocokan
+		_, err = transaksiService.BalikTransaksi(jurnalBiaya.ID, koperasi.ID, admin, &BalikTransaksiRequest{Alasan: "Salah akun"})
+		assert.Error(t, err)
+
+		_, err = rekonsiliasiBankService.BatalkanCocok(koperasi.ID, admin, mutasiBiaya.ID, &BatalkanCocokRequest{})
+		assert.Error(t, err, "alasan wajib untuk membalik jurnal mutasi")
+
+		batal, err := rekonsiliasiBankService.BatalkanCocok(koperasi.ID, admin, mutasiBiaya.ID, &BatalkanCocokRequest{Alasan: "Salah akun"})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusMutasiBelumCocok, batal.Status)
+
+		db.First(&jurnalBiaya, "id = ?", jurnalBiaya.ID)
+		assert.True(t, jurnalBiaya.Dibalik)
+	})
+}
@@ -0,0 +1,502 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JenisSelisihSimpanan mengelompokkan penyebab buku pembantu simpanan tidak cocok dengan buku besar
+type JenisSelisihSimpanan string
+
+const (
+	SelisihTanpaJurnal         JenisSelisihSimpanan = "TANPA_JURNAL"          // Simpanan berlaku tanpa IDTransaksi
+	SelisihJurnalDihapus       JenisSelisihSimpanan = "JURNAL_DIHAPUS"        // Jurnal simpanan dihapus atau tidak ditemukan
+	SelisihJurnalTidakCocok    JenisSelisihSimpanan = "JURNAL_TIDAK_COCOK"    // Nominal atau akun jurnal berbeda dengan simpanan
+	SelisihJurnalBelumDibalik  JenisSelisihSimpanan = "JURNAL_BELUM_DIBALIK"  // Simpanan dibatalkan tetapi jurnalnya masih berlaku
+	SelisihJurnalDibalik       JenisSelisihSimpanan = "JURNAL_DIBALIK"        // Jurnal dibalik tetapi simpanan masih berlaku
+	SelisihJurnalTanpaSimpanan JenisSelisihSimpanan = "JURNAL_TANPA_SIMPANAN" // Jurnal ke akun simpanan tanpa simpanan anggota
+)
+
+// RekonsiliasiAkunSimpanan membandingkan saldo akun kontrol simpanan dengan total simpanan anggota
+type RekonsiliasiAkunSimpanan struct {
+	KodeAkun          string                `json:"kodeAkun"`
+	NamaAkun          string                `json:"namaAkun"`
+	TipeSimpanan      []models.TipeSimpanan `json:"tipeSimpanan"`
+	SaldoBukuBesar    models.Uang           `json:"saldoBukuBesar"`
+	SaldoBukuPembantu models.Uang           `json:"saldoBukuPembantu"`
+	Selisih           models.Uang           `json:"selisih"`
+}
+
+// ItemSelisihSimpanan adalah satu simpanan atau jurnal yang menyebabkan selisih
+type ItemSelisihSimpanan struct {
+	Jenis           JenisSelisihSimpanan `json:"jenis"`
+	IDSimpanan      *uuid.UUID           `json:"idSimpanan,omitempty"`
+	IDTransaksi     *uuid.UUID           `json:"idTransaksi,omitempty"`
+	NomorJurnal     string               `json:"nomorJurnal,omitempty"`
+	NomorReferensi  string               `json:"nomorReferensi,omitempty"`
+	NomorAnggota    string               `json:"nomorAnggota,omitempty"`
+	TipeSimpanan    models.TipeSimpanan  `json:"tipeSimpanan,omitempty"`
+	Tanggal         time.Time            `json:"tanggal"`
+	Jumlah          models.Uang          `json:"jumlah"` // Pengaruh terhadap saldo simpanan (kredit - debit)
+	DapatDiperbaiki bool                 `json:"dapatDiperbaiki"`
+	Keterangan      string               `json:"keterangan"`
+}
+
+// LaporanRekonsiliasiSimpanan adalah hasil rekonsiliasi buku pembantu simpanan dengan buku besar
+type LaporanRekonsiliasiSimpanan struct {
+	TanggalPer        time.Time                  `json:"tanggalPer"`
+	Akun              []RekonsiliasiAkunSimpanan `json:"akun"`
+	TotalBukuBesar    models.Uang                `json:"totalBukuBesar"`
+	TotalBukuPembantu models.Uang                `json:"totalBukuPembantu"`
+	Selisih           models.Uang                `json:"selisih"`
+	Cocok             bool                       `json:"cocok"`
+	Rincian           []ItemSelisihSimpanan      `json:"rincian"`
+}
+
+// simpananRekonsiliasi adalah baris simpanan beserta status jurnal yang terhubung
+type simpananRekonsiliasi struct {
+	ID                uuid.UUID
+	NomorAnggota      string
+	TipeSimpanan      models.TipeSimpanan
+	JenisTransaksi    models.JenisTransaksiSimpanan
+	TanggalTransaksi  time.Time
+	JumlahSetoran     models.Uang
+	NomorReferensi    string
+	Dibatalkan        bool
+	TanggalDibatalkan *time.Time
+	IDTransaksi       *uuid.UUID
+	JurnalAda         bool
+	JurnalDihapus     bool
+	NomorJurnal       string
+	TipeJurnal        string
+	JurnalDibalik     bool
+	TanggalPembalikan *time.Time
+}
+
+// RekonsiliasiSimpanan membandingkan total simpanan anggota (buku pembantu) dengan saldo akun
+// kontrol simpanan di buku besar per tanggalPer (kosong berarti hari ini).
+//
+// Akun kontrol diambil dari aturan posting setiap tipe simpanan. Simpanan yang dibatalkan
+// tetap dihitung sampai tanggal jurnal pembaliknya. Selain ringkasan per akun, laporan
+// memuat simpanan dan jurnal penyebab selisih; yang dapat diperbaiki otomatis ditandai
+// DapatDiperbaiki (lihat PerbaikiRekonsiliasiSimpanan).
+func (s *SimpananService) RekonsiliasiSimpanan(idKoperasi uuid.UUID, tanggalPer string) (*LaporanRekonsiliasiSimpanan, error) {
+	tanggal := time.Now()
+	if tanggalPer != "" {
+		var err error
+		tanggal, err = time.Parse("2006-01-02", tanggalPer)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid")
+		}
+	}
+	per := tanggal.Format("2006-01-02")
+
+	laporan := &LaporanRekonsiliasiSimpanan{
+		TanggalPer: tanggal,
+		Akun:       []RekonsiliasiAkunSimpanan{},
+		Rincian:    []ItemSelisihSimpanan{},
+	}
+
+	// Step 1: Akun kontrol setiap tipe simpanan; beberapa tipe dapat dipetakan ke akun yang sama
+	tipeList := []models.TipeSimpanan{models.SimpananPokok, models.SimpananWajib, models.SimpananSukarela}
+	akunByTipe := make(map[models.TipeSimpanan]models.Akun, len(tipeList))
+	indeksByAkun := make(map[uuid.UUID]int)
+	var idAkunSimpanan []uuid.UUID
+	for _, tipe := range tipeList {
+		akunPosting, err := akunPostingWithTx(s.db, idKoperasi, peristiwaSimpanan[tipe])
+		if err != nil {
+			return nil, fmt.Errorf("gagal menentukan akun simpanan %s: %w", tipe, err)
+		}
+		akun := akunPosting[models.PeranAkunSimpanan]
+		akunByTipe[tipe] = akun
+
+		indeks, ada := indeksByAkun[akun.ID]
+		if !ada {
+			indeks = len(laporan.Akun)
+			indeksByAkun[akun.ID] = indeks
+			idAkunSimpanan = append(idAkunSimpanan, akun.ID)
+			laporan.Akun = append(laporan.Akun, RekonsiliasiAkunSimpanan{KodeAkun: akun.KodeAkun, NamaAkun: akun.NamaAkun})
+		}
+		laporan.Akun[indeks].TipeSimpanan = append(laporan.Akun[indeks].TipeSimpanan, tipe)
+	}
+
+	// Step 2: Saldo buku besar akun kontrol (kredit - debit)
+	var saldoAkunList []struct {
+		IDAkun uuid.UUID
+		Saldo  models.Uang
+	}
+	err := s.db.Table("baris_transaksi").
+		Select("baris_transaksi.id_akun, COALESCE(SUM(baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit), 0) as saldo").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+		Where("transaksi.tanggal_transaksi <= ? AND baris_transaksi.id_akun IN ?", per, idAkunSimpanan).
+		Group("baris_transaksi.id_akun").
+		Scan(&saldoAkunList).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung saldo akun simpanan")
+	}
+	for _, saldo := range saldoAkunList {
+		laporan.Akun[indeksByAkun[saldo.IDAkun]].SaldoBukuBesar = saldo.Saldo
+	}
+
+	// Step 3: Simpanan anggota beserta status jurnalnya (termasuk jurnal yang sudah dihapus)
+	var simpananList []simpananRekonsiliasi
+	err = s.db.Table("simpanan").
+		Select(`
+			simpanan.id, anggota.nomor_anggota, simpanan.tipe_simpanan, simpanan.jenis_transaksi,
+			simpanan.tanggal_transaksi, simpanan.jumlah_setoran, simpanan.nomor_referensi,
+			simpanan.dibatalkan, simpanan.tanggal_dibatalkan, simpanan.id_transaksi,
+			asal.id IS NOT NULL as jurnal_ada,
+			COALESCE(asal.tanggal_dihapus IS NOT NULL, false) as jurnal_dihapus,
+			COALESCE(asal.nomor_jurnal, '') as nomor_jurnal,
+			COALESCE(asal.tipe_transaksi, '') as tipe_jurnal,
+			COALESCE(asal.dibalik, false) as jurnal_dibalik,
+			pembalik.tanggal_transaksi as tanggal_pembalikan
+		`).
+		Joins("LEFT JOIN anggota ON anggota.id = simpanan.id_anggota").
+		Joins("LEFT JOIN transaksi asal ON asal.id = simpanan.id_transaksi").
+		Joins("LEFT JOIN transaksi pembalik ON pembalik.id = asal.id_jurnal_pembalik AND pembalik.tanggal_dihapus IS NULL").
+		Where("simpanan.id_koperasi = ? AND simpanan.tanggal_dihapus IS NULL AND simpanan.tanggal_transaksi <= ?", idKoperasi, per).
+		Order("simpanan.tanggal_transaksi ASC, simpanan.tanggal_dibuat ASC").
+		Scan(&simpananList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil data simpanan")
+	}
+
+	// Pengaruh jurnal SIMPANAN terhadap akun kontrol tipenya, untuk memeriksa nominal jurnal
+	mutasiJurnal, err := s.mutasiJurnalSimpanan(idKoperasi, idAkunSimpanan)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, simpanan := range simpananList {
+		jumlah := simpanan.JumlahSetoran
+		if simpanan.JenisTransaksi == models.TransaksiPenarikan {
+			jumlah = -jumlah
+		}
+
+		// Pembatalan berlaku sejak tanggal jurnal pembaliknya (atau tanggal pembatalan jika belum dibalik)
+		dibalikPer := simpanan.JurnalDibalik && simpanan.TanggalPembalikan != nil &&
+			simpanan.TanggalPembalikan.Format("2006-01-02") <= per
+		dibatalkanPer := false
+		if simpanan.Dibatalkan {
+			switch {
+			case simpanan.TanggalPembalikan != nil:
+				dibatalkanPer = simpanan.TanggalPembalikan.Format("2006-01-02") <= per
+			case simpanan.TanggalDibatalkan != nil:
+				dibatalkanPer = simpanan.TanggalDibatalkan.Format("2006-01-02") <= per
+			default:
+				dibatalkanPer = true
+			}
+		}
+		if !dibatalkanPer {
+			laporan.Akun[indeksByAkun[akunByTipe[simpanan.TipeSimpanan].ID]].SaldoBukuPembantu += jumlah
+		}
+
+		item := ItemSelisihSimpanan{
+			IDTransaksi:    simpanan.IDTransaksi,
+			NomorJurnal:    simpanan.NomorJurnal,
+			NomorReferensi: simpanan.NomorReferensi,
+			NomorAnggota:   simpanan.NomorAnggota,
+			TipeSimpanan:   simpanan.TipeSimpanan,
+			Tanggal:        simpanan.TanggalTransaksi,
+			Jumlah:         jumlah,
+		}
+		id := simpanan.ID
+		item.IDSimpanan = &id
+
+		switch {
+		case dibatalkanPer:
+			// Simpanan batal harus sudah dibalik jurnalnya (jika punya jurnal)
+			if simpanan.IDTransaksi == nil || !simpanan.JurnalAda || simpanan.JurnalDihapus || simpanan.JurnalDibalik {
+				continue
+			}
+			item.Jenis = SelisihJurnalBelumDibalik
+			item.Jumlah = -jumlah
+			item.DapatDiperbaiki = simpanan.TipeJurnal == models.TipeTransaksiSimpanan
+			item.Keterangan = fmt.Sprintf("simpanan sudah dibatalkan tetapi jurnal %s belum dibalik", simpanan.NomorJurnal)
+		case simpanan.IDTransaksi == nil:
+			item.Jenis = SelisihTanpaJurnal
+			item.DapatDiperbaiki = true
+			item.Keterangan = "simpanan tidak memiliki jurnal"
+		case !simpanan.JurnalAda || simpanan.JurnalDihapus:
+			item.Jenis = SelisihJurnalDihapus
+			item.DapatDiperbaiki = true
+			item.Keterangan = "jurnal simpanan sudah dihapus"
+		case dibalikPer:
+			item.Jenis = SelisihJurnalDibalik
+			item.Keterangan = fmt.Sprintf("jurnal %s sudah dibalik tetapi simpanan masih berlaku", simpanan.NomorJurnal)
+		case simpanan.TipeJurnal == models.TipeTransaksiSimpanan:
+			mutasi := mutasiJurnal[*simpanan.IDTransaksi][akunByTipe[simpanan.TipeSimpanan].ID]
+			if mutasi == jumlah {
+				continue
+			}
+			item.Jenis = SelisihJurnalTidakCocok
+			item.Keterangan = fmt.Sprintf("jurnal %s mencatat %s pada akun %s, simpanan %s",
+				simpanan.NomorJurnal, mutasi, akunByTipe[simpanan.TipeSimpanan].KodeAkun, jumlah)
+		default:
+			continue
+		}
+		laporan.Rincian = append(laporan.Rincian, item)
+	}
+
+	// Step 4: Jurnal ke akun kontrol yang tidak berasal dari simpanan anggota
+	jurnalTanpaSimpanan, err := s.jurnalTanpaSimpanan(idKoperasi, idAkunSimpanan, per)
+	if err != nil {
+		return nil, err
+	}
+	laporan.Rincian = append(laporan.Rincian, jurnalTanpaSimpanan...)
+	sort.SliceStable(laporan.Rincian, func(i, j int) bool {
+		return laporan.Rincian[i].Tanggal.Before(laporan.Rincian[j].Tanggal)
+	})
+
+	adaSelisihAkun := false
+	for i := range laporan.Akun {
+		akun := &laporan.Akun[i]
+		akun.Selisih = akun.SaldoBukuBesar - akun.SaldoBukuPembantu
+		laporan.TotalBukuBesar += akun.SaldoBukuBesar
+		laporan.TotalBukuPembantu += akun.SaldoBukuPembantu
+		adaSelisihAkun = adaSelisihAkun || akun.Selisih != 0
+	}
+	laporan.Selisih = laporan.TotalBukuBesar - laporan.TotalBukuPembantu
+	laporan.Cocok = !adaSelisihAkun && len(laporan.Rincian) == 0
+
+	return laporan, nil
+}
+
+// mutasiJurnalSimpanan menghitung mutasi (kredit - debit) jurnal SIMPANAN yang terhubung ke simpanan
+// pada setiap akun kontrol, dipetakan per jurnal lalu per akun
+func (s *SimpananService) mutasiJurnalSimpanan(idKoperasi uuid.UUID, idAkunSimpanan []uuid.UUID) (map[uuid.UUID]map[uuid.UUID]models.Uang, error) {
+	var mutasiList []struct {
+		IDTransaksi uuid.UUID
+		IDAkun      uuid.UUID
+		Mutasi      models.Uang
+	}
+	err := s.db.Table("baris_transaksi").
+		Select("baris_transaksi.id_transaksi, baris_transaksi.id_akun, SUM(baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit) as mutasi").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND transaksi.tipe_transaksi = ? AND transaksi.id_jurnal_asal IS NULL",
+			idKoperasi, models.TipeTransaksiSimpanan).
+		Where("baris_transaksi.id_akun IN ?", idAkunSimpanan).
+		Group("baris_transaksi.id_transaksi, baris_transaksi.id_akun").
+		Scan(&mutasiList).Error
+	if err != nil {
+		return nil, errors.New("gagal menghitung mutasi jurnal simpanan")
+	}
+
+	mutasiByJurnal := make(map[uuid.UUID]map[uuid.UUID]models.Uang, len(mutasiList))
+	for _, mutasi := range mutasiList {
+		if mutasiByJurnal[mutasi.IDTransaksi] == nil {
+			mutasiByJurnal[mutasi.IDTransaksi] = make(map[uuid.UUID]models.Uang)
+		}
+		mutasiByJurnal[mutasi.IDTransaksi][mutasi.IDAkun] = mutasi.Mutasi
+	}
+	return mutasiByJurnal, nil
+}
+
+// jurnalTanpaSimpanan mencari jurnal posted sampai tanggal per yang mengubah saldo akun kontrol
+// simpanan tetapi tidak terhubung ke simpanan anggota mana pun. Jurnal yang sudah dibalik per
+// tanggal tersebut beserta jurnal pembaliknya saling meniadakan sehingga dilewati.
+func (s *SimpananService) jurnalTanpaSimpanan(idKoperasi uuid.UUID, idAkunSimpanan []uuid.UUID, per string) ([]ItemSelisihSimpanan, error) {
+	terhubung := s.db.Table("simpanan").Select("id_transaksi").
+		Where("id_koperasi = ? AND id_transaksi IS NOT NULL AND tanggal_dihapus IS NULL", idKoperasi)
+
+	var jurnalList []struct {
+		ID               uuid.UUID
+		NomorJurnal      string
+		NomorReferensi   string
+		TanggalTransaksi time.Time
+		Deskripsi        string
+		Mutasi           models.Uang
+	}
+	err := s.db.Table("baris_transaksi").
+		Select(`transaksi.id, transaksi.nomor_jurnal, transaksi.nomor_referensi, transaksi.tanggal_transaksi, transaksi.deskripsi,
+			SUM(baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit) as mutasi`).
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+		Where("transaksi.tanggal_transaksi <= ? AND baris_transaksi.id_akun IN ?", per, idAkunSimpanan).
+		Where("transaksi.id NOT IN (?) AND transaksi.id_jurnal_asal IS NULL", terhubung).
+		Where(`NOT EXISTS (SELECT 1 FROM transaksi pembalik WHERE pembalik.id = transaksi.id_jurnal_pembalik
+			AND pembalik.tanggal_dihapus IS NULL AND pembalik.tanggal_transaksi <= ?)`, per).
+		Group("transaksi.id, transaksi.nomor_jurnal, transaksi.nomor_referensi, transaksi.tanggal_transaksi, transaksi.deskripsi").
+		Having("SUM(baris_transaksi.jumlah_kredit - baris_transaksi.jumlah_debit) <> 0").
+		Scan(&jurnalList).Error
+	if err != nil {
+		return nil, errors.New("gagal mencari jurnal simpanan tanpa simpanan anggota")
+	}
+
+	itemList := make([]ItemSelisihSimpanan, 0, len(jurnalList))
+	for _, jurnal := range jurnalList {
+		id := jurnal.ID
+		itemList = append(itemList, ItemSelisihSimpanan{
+			Jenis:          SelisihJurnalTanpaSimpanan,
+			IDTransaksi:    &id,
+			NomorJurnal:    jurnal.NomorJurnal,
+			NomorReferensi: jurnal.NomorReferensi,
+			Tanggal:        jurnal.TanggalTransaksi,
+			Jumlah:         jurnal.Mutasi,
+			Keterangan:     fmt.Sprintf("jurnal %s (%s) mengubah saldo simpanan tanpa simpanan anggota", jurnal.NomorJurnal, jurnal.Deskripsi),
+		})
+	}
+	return itemList, nil
+}
+
+// PerbaikiRekonsiliasiSimpananRequest adalah struktur request untuk perbaikan selisih simpanan
+type PerbaikiRekonsiliasiSimpananRequest struct {
+	IDSimpanan []uuid.UUID `json:"idSimpanan"` // Opsional; kosong berarti semua selisih yang dapat diperbaiki
+	Alasan     string      `json:"alasan" binding:"required"`
+}
+
+// GagalPerbaikanSimpanan adalah selisih yang gagal diperbaiki beserta penyebabnya
+type GagalPerbaikanSimpanan struct {
+	ItemSelisihSimpanan
+	Pesan string `json:"pesan"`
+}
+
+// HasilPerbaikanRekonsiliasiSimpanan adalah hasil perbaikan selisih simpanan
+type HasilPerbaikanRekonsiliasiSimpanan struct {
+	Diperbaiki []ItemSelisihSimpanan        `json:"diperbaiki"`
+	Gagal      []GagalPerbaikanSimpanan     `json:"gagal"`
+	Laporan    *LaporanRekonsiliasiSimpanan `json:"laporan"` // Rekonsiliasi setelah perbaikan
+}
+
+// PerbaikiRekonsiliasiSimpanan memperbaiki selisih yang dapat diperbaiki otomatis:
+//   - Simpanan tanpa jurnal atau dengan jurnal yang dihapus diposting ulang pada tanggal simpanannya
+//   - Jurnal simpanan yang sudah dibatalkan tetapi belum dibalik dibalik dengan tanggal hari ini
+//
+// Setiap simpanan diperbaiki dalam transaction sendiri sehingga kegagalan satu simpanan (misalnya
+// periodenya sudah ditutup) tidak membatalkan perbaikan lainnya. Selisih lain harus diteliti manual.
+func (s *SimpananService) PerbaikiRekonsiliasiSimpanan(idKoperasi, idPengguna uuid.UUID, req *PerbaikiRekonsiliasiSimpananRequest) (*HasilPerbaikanRekonsiliasiSimpanan, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan perbaikan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	laporan, err := s.RekonsiliasiSimpanan(idKoperasi, "")
+	if err != nil {
+		return nil, err
+	}
+
+	dipilih := make(map[uuid.UUID]bool, len(req.IDSimpanan))
+	for _, id := range req.IDSimpanan {
+		dipilih[id] = true
+	}
+
+	hasil := &HasilPerbaikanRekonsiliasiSimpanan{
+		Diperbaiki: []ItemSelisihSimpanan{},
+		Gagal:      []GagalPerbaikanSimpanan{},
+	}
+	for _, item := range laporan.Rincian {
+		if !item.DapatDiperbaiki || item.IDSimpanan == nil {
+			continue
+		}
+		if len(dipilih) > 0 && !dipilih[*item.IDSimpanan] {
+			continue
+		}
+
+		if perbaikiErr := s.perbaikiSelisihSimpanan(idKoperasi, idPengguna, item, req.Alasan); perbaikiErr != nil {
+			hasil.Gagal = append(hasil.Gagal, GagalPerbaikanSimpanan{ItemSelisihSimpanan: item, Pesan: perbaikiErr.Error()})
+			continue
+		}
+		hasil.Diperbaiki = append(hasil.Diperbaiki, item)
+	}
+
+	hasil.Laporan, err = s.RekonsiliasiSimpanan(idKoperasi, "")
+	if err != nil {
+		return nil, err
+	}
+	return hasil, nil
+}
+
+// perbaikiSelisihSimpanan memperbaiki satu selisih setelah memastikan kondisinya belum berubah
+func (s *SimpananService) perbaikiSelisihSimpanan(idKoperasi, idPengguna uuid.UUID, item ItemSelisihSimpanan, alasan string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var simpanan models.Simpanan
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", *item.IDSimpanan, idKoperasi).
+			First(&simpanan).Error; err != nil {
+			return errors.New("simpanan tidak ditemukan")
+		}
+
+		switch item.Jenis {
+		case SelisihTanpaJurnal, SelisihJurnalDihapus:
+			if simpanan.Dibatalkan {
+				return errors.New("simpanan sudah dibatalkan")
+			}
+			if simpanan.IDTransaksi != nil {
+				var jumlah int64
+				if err := tx.Model(&models.Transaksi{}).Where("id = ?", *simpanan.IDTransaksi).Count(&jumlah).Error; err != nil {
+					return errors.New("gagal memeriksa jurnal simpanan")
+				}
+				if jumlah > 0 {
+					return errors.New("simpanan sudah memiliki jurnal")
+				}
+			}
+			return s.transaksiService.PostingOtomatisSimpananWithTx(tx, idKoperasi, idPengguna, simpanan.ID)
+
+		case SelisihJurnalBelumDibalik:
+			if !simpanan.Dibatalkan || simpanan.IDTransaksi == nil {
+				return errors.New("simpanan tidak lagi memerlukan pembalikan jurnal")
+			}
+			_, err := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *simpanan.IDTransaksi,
+				time.Now(), "Perbaikan rekonsiliasi simpanan: "+alasan, models.TipeTransaksiSimpanan)
+			return err
+		}
+
+		return fmt.Errorf("selisih %s tidak dapat diperbaiki otomatis", item.Jenis)
+	})
+}
+
+// PeriksaRekonsiliasiOtomatis merekonsiliasi simpanan setiap koperasi per hari ini dan mencatat
+// koperasi yang buku pembantu simpanannya tidak cocok dengan buku besar ke log
+func (s *SimpananService) PeriksaRekonsiliasiOtomatis() {
+	var koperasiList []models.Koperasi
+	if err := s.db.Find(&koperasiList).Error; err != nil {
+		log.Printf("Rekonsiliasi simpanan: gagal mengambil daftar koperasi: %v", err)
+		return
+	}
+
+	for _, koperasi := range koperasiList {
+		laporan, err := s.RekonsiliasiSimpanan(koperasi.ID, "")
+		if err != nil {
+			log.Printf("Rekonsiliasi simpanan: gagal merekonsiliasi koperasi %s: %v", koperasi.ID, err)
+			continue
+		}
+		if !laporan.Cocok {
+			log.Printf("Rekonsiliasi simpanan: koperasi %s tidak cocok, selisih %s dengan %d rincian",
+				koperasi.ID, laporan.Selisih, len(laporan.Rincian))
+		}
+	}
+}
+
+// MulaiRekonsiliasiOtomatis menjalankan PeriksaRekonsiliasiOtomatis secara berkala di background.
+// Panggil fungsi stop yang dikembalikan untuk menghentikan goroutine.
+func (s *SimpananService) MulaiRekonsiliasiOtomatis(interval time.Duration) (stop func()) {
+	stopChan := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.PeriksaRekonsiliasiOtomatis()
+		for {
+			select {
+			case <-ticker.C:
+				s.PeriksaRekonsiliasiOtomatis()
+			case <-stopChan:
+				return // Graceful shutdown
+			}
+		}
+	}()
+
+	return func() { close(stopChan) }
+}
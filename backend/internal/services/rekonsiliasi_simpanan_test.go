@@ -0,0 +1,130 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRekonsiliasiSimpanan(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+	)
+	require.NoError(t, err)
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Rekonsiliasi Koperasi", Alamat: "Test Address"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	member := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "A0001", NamaLengkap: "Test Member", Status: models.StatusAktif}
+	db.Create(member)
+
+	idPengguna := member.ID
+	transaksiService := NewTransaksiService(db)
+	service := NewSimpananService(db, transaksiService)
+
+	setor := func(tipe models.TipeSimpanan, jumlah int64) *models.SimpananResponse {
+		simpanan, setorErr := service.CatatSetoran(koperasi.ID, idPengguna, &CatatSetoranRequest{
+			IDAnggota:        member.ID,
+			TipeSimpanan:     tipe,
+			TanggalTransaksi: time.Now(),
+			JumlahSetoran:    models.Rupiah(jumlah),
+		})
+		require.NoError(t, setorErr)
+		return simpanan
+	}
+
+	setor(models.SimpananSukarela, 100000)
+
+	laporan, err := service.RekonsiliasiSimpanan(koperasi.ID, "")
+	require.NoError(t, err)
+	assert.True(t, laporan.Cocok)
+	assert.Equal(t, models.Rupiah(100000), laporan.TotalBukuBesar)
+
+	// Jurnal setoran wajib terhapus
+	wajib := setor(models.SimpananWajib, 50000)
+	var simpananWajib models.Simpanan
+	require.NoError(t, db.First(&simpananWajib, "id = ?", wajib.ID).Error)
+	db.Delete(&models.Transaksi{}, "id = ?", *simpananWajib.IDTransaksi)
+
+	// Simpanan dicatat langsung tanpa jurnal
+	db.Create(&models.Simpanan{
+		IDKoperasi:       koperasi.ID,
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananSukarela,
+		TanggalTransaksi: time.Now(),
+		JumlahSetoran:    models.Rupiah(30000),
+	})
+
+	// Simpanan dibatalkan tanpa membalik jurnalnya
+	batal := setor(models.SimpananSukarela, 40000)
+	db.Model(&models.Simpanan{}).Where("id = ?", batal.ID).
+		Updates(map[string]interface{}{"dibatalkan": true, "tanggal_dibatalkan": time.Now()})
+
+	// Jurnal manual langsung ke akun simpanan sukarela
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	sukarela, _ := akunService.DapatkanAkunByKode(koperasi.ID, "3103")
+	_, err = transaksiService.BuatTransaksi(koperasi.ID, idPengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: time.Now(),
+		Deskripsi:        "Koreksi simpanan manual",
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: kas.ID, JumlahDebit: models.Rupiah(20000)},
+			{IDAkun: sukarela.ID, JumlahKredit: models.Rupiah(20000)},
+		},
+	})
+	require.NoError(t, err)
+
+	laporan, err = service.RekonsiliasiSimpanan(koperasi.ID, "")
+	require.NoError(t, err)
+	assert.False(t, laporan.Cocok)
+
+	jenis := map[JenisSelisihSimpanan]ItemSelisihSimpanan{}
+	for _, item := range laporan.Rincian {
+		jenis[item.Jenis] = item
+	}
+	assert.Len(t, laporan.Rincian, 4)
+	assert.True(t, jenis[SelisihJurnalDihapus].DapatDiperbaiki)
+	assert.True(t, jenis[SelisihTanpaJurnal].DapatDiperbaiki)
+	assert.True(t, jenis[SelisihJurnalBelumDibalik].DapatDiperbaiki)
+	assert.False(t, jenis[SelisihJurnalTanpaSimpanan].DapatDiperbaiki)
+	assert.Equal(t, models.Rupiah(20000), jenis[SelisihJurnalTanpaSimpanan].Jumlah)
+
+	// Buku besar 3103: 100.000 + 40.000 + 20.000; buku pembantu: 100.000 + 30.000
+	for _, akun := range laporan.Akun {
+		if akun.KodeAkun == "3103" {
+			assert.Equal(t, models.Rupiah(160000), akun.SaldoBukuBesar)
+			assert.Equal(t, models.Rupiah(130000), akun.SaldoBukuPembantu)
+		}
+	}
+
+	// Per kemarin belum ada simpanan maupun jurnal
+	kemarin, err := service.RekonsiliasiSimpanan(koperasi.ID, time.Now().AddDate(0, 0, -1).Format("2006-01-02"))
+	require.NoError(t, err)
+	assert.True(t, kemarin.Cocok)
+
+	// Perbaikan menyelesaikan semua selisih kecuali jurnal manual
+	hasil, err := service.PerbaikiRekonsiliasiSimpanan(koperasi.ID, idPengguna, &PerbaikiRekonsiliasiSimpananRequest{Alasan: "Perbaikan data migrasi"})
+	require.NoError(t, err)
+	assert.Len(t, hasil.Diperbaiki, 3)
+	assert.Empty(t, hasil.Gagal)
+	require.Len(t, hasil.Laporan.Rincian, 1)
+	assert.Equal(t, SelisihJurnalTanpaSimpanan, hasil.Laporan.Rincian[0].Jenis)
+	assert.Equal(t, models.Rupiah(20000), hasil.Laporan.Selisih)
+}
@@ -0,0 +1,610 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Bagian data saldo awal tempat kesalahan ditemukan
+const (
+	BagianSaldoAwalAkun         = "AKUN"
+	BagianSaldoAwalSimpanan     = "SIMPANAN"
+	BagianSaldoAwalNeraca       = "NERACA"
+	BagianSaldoAwalRekonsiliasi = "REKONSILIASI"
+)
+
+// keteranganSaldoAwal dipakai pada baris jurnal dan simpanan hasil migrasi saldo awal
+const keteranganSaldoAwal = "Saldo awal migrasi"
+
+// SaldoAwalService menangani migrasi saldo awal koperasi yang beralih dari pembukuan manual:
+// satu jurnal pembukaan dari neraca saldo ditambah rincian simpanan per anggota
+type SaldoAwalService struct {
+	db               *gorm.DB
+	transaksiService *TransaksiService
+}
+
+// NewSaldoAwalService membuat instance baru SaldoAwalService
+func NewSaldoAwalService(db *gorm.DB, transaksiService *TransaksiService) *SaldoAwalService {
+	return &SaldoAwalService{
+		db:               db,
+		transaksiService: transaksiService,
+	}
+}
+
+// BarisSaldoAwalAkun adalah saldo satu akun pada neraca saldo awal. Hanya salah satu sisi yang diisi.
+type BarisSaldoAwalAkun struct {
+	Baris       int         `json:"baris,omitempty"` // Nomor baris berkas; diisi otomatis untuk request JSON
+	KodeAkun    string      `json:"kodeAkun"`
+	SaldoDebit  models.Uang `json:"saldoDebit"`
+	SaldoKredit models.Uang `json:"saldoKredit"`
+}
+
+// BarisSaldoAwalSimpanan adalah saldo satu jenis simpanan milik satu anggota
+type BarisSaldoAwalSimpanan struct {
+	Baris        int                 `json:"baris,omitempty"`
+	NomorAnggota string              `json:"nomorAnggota"`
+	TipeSimpanan models.TipeSimpanan `json:"tipeSimpanan"`
+	Jumlah       models.Uang         `json:"jumlah"`
+}
+
+// ImporSaldoAwalRequest adalah struktur request untuk impor saldo awal
+type ImporSaldoAwalRequest struct {
+	TanggalSaldo time.Time                `json:"tanggalSaldo" binding:"required"`
+	Akun         []BarisSaldoAwalAkun     `json:"akun" binding:"required,min=2"`
+	Simpanan     []BarisSaldoAwalSimpanan `json:"simpanan"`
+	DryRun       bool                     `json:"dryRun"`
+}
+
+// KesalahanSaldoAwal adalah satu kesalahan validasi saldo awal
+type KesalahanSaldoAwal struct {
+	Bagian string `json:"bagian"`
+	Baris  int    `json:"baris,omitempty"`
+	Kode   string `json:"kode,omitempty"` // Kode akun atau nomor anggota
+	Pesan  string `json:"pesan"`
+}
+
+// RekonsiliasiSaldoAwalSimpanan membandingkan saldo akun simpanan di neraca dengan total simpanan anggota
+type RekonsiliasiSaldoAwalSimpanan struct {
+	KodeAkun             string                `json:"kodeAkun"`
+	NamaAkun             string                `json:"namaAkun"`
+	TipeSimpanan         []models.TipeSimpanan `json:"tipeSimpanan"`
+	SaldoAkun            models.Uang           `json:"saldoAkun"`
+	TotalSimpananAnggota models.Uang           `json:"totalSimpananAnggota"`
+	Selisih              models.Uang           `json:"selisih"`
+}
+
+// HasilImporSaldoAwal adalah hasil validasi dan penyimpanan saldo awal
+type HasilImporSaldoAwal struct {
+	DryRun         bool                            `json:"dryRun"`
+	Disimpan       bool                            `json:"disimpan"`
+	TanggalSaldo   time.Time                       `json:"tanggalSaldo"`
+	JumlahAkun     int                             `json:"jumlahAkun"`
+	JumlahSimpanan int                             `json:"jumlahSimpanan"`
+	TotalDebit     models.Uang                     `json:"totalDebit"`
+	TotalKredit    models.Uang                     `json:"totalKredit"`
+	Rekonsiliasi   []RekonsiliasiSaldoAwalSimpanan `json:"rekonsiliasi"`
+	Kesalahan      []KesalahanSaldoAwal            `json:"kesalahan"`
+	IDTransaksi    *uuid.UUID                      `json:"idTransaksi,omitempty"`
+	NomorJurnal    string                          `json:"nomorJurnal,omitempty"`
+}
+
+func (h *HasilImporSaldoAwal) tambahKesalahan(bagian string, baris int, kode, pesan string) {
+	h.Kesalahan = append(h.Kesalahan, KesalahanSaldoAwal{Bagian: bagian, Baris: baris, Kode: kode, Pesan: pesan})
+}
+
+// ImporSaldoAwal memvalidasi dan menyimpan saldo awal koperasi.
+//
+// Neraca saldo dijurnal sebagai satu jurnal SALDO_AWAL melalui TransaksiService, dan saldo
+// simpanan setiap anggota dicatat sebagai setoran yang terhubung ke jurnal tersebut. Total
+// simpanan anggota per akun simpanan harus sama dengan saldo akun itu di neraca sehingga buku
+// pembantu simpanan langsung cocok dengan buku besar. Saldo awal hanya dapat diimpor sekali dan
+// harus mendahului semua jurnal lain. Kesalahan per baris dikumpulkan di hasil; jika ada
+// kesalahan, atau DryRun bernilai true, tidak ada data yang disimpan.
+func (s *SaldoAwalService) ImporSaldoAwal(idKoperasi, idPengguna uuid.UUID, req *ImporSaldoAwalRequest) (*HasilImporSaldoAwal, error) {
+	validator := validasi.Baru()
+	if err := validator.TanggalTransaksi(req.TanggalSaldo); err != nil {
+		return nil, err
+	}
+
+	hasil := &HasilImporSaldoAwal{
+		DryRun:       req.DryRun,
+		TanggalSaldo: req.TanggalSaldo,
+		Rekonsiliasi: []RekonsiliasiSaldoAwalSimpanan{},
+		Kesalahan:    []KesalahanSaldoAwal{},
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci koperasi agar dua impor saldo awal tidak berjalan bersamaan
+		var koperasi models.Koperasi
+		if lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", idKoperasi).First(&koperasi).Error; lockErr != nil {
+			return errors.New("koperasi tidak ditemukan")
+		}
+
+		if cekErr := s.cekSaldoAwalDapatDiimporWithTx(tx, idKoperasi, req.TanggalSaldo); cekErr != nil {
+			return cekErr
+		}
+
+		barisJurnal, validasiErr := s.validasiNeracaWithTx(tx, idKoperasi, req.Akun, hasil)
+		if validasiErr != nil {
+			return validasiErr
+		}
+
+		simpananList, validasiErr := s.validasiSimpananWithTx(tx, idKoperasi, req.Simpanan, hasil)
+		if validasiErr != nil {
+			return validasiErr
+		}
+
+		s.rekonsiliasiSimpananWithTx(tx, idKoperasi, barisJurnal, simpananList, hasil)
+
+		if len(hasil.Kesalahan) > 0 || req.DryRun {
+			return nil
+		}
+
+		// Jurnal pembukaan melalui jalur jurnal yang sama dengan transaksi lain
+		jurnal, jurnalErr := s.transaksiService.BuatTransaksiWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+			TanggalTransaksi: req.TanggalSaldo,
+			Deskripsi:        fmt.Sprintf("Saldo awal per %s", req.TanggalSaldo.Format("2006-01-02")),
+			TipeTransaksi:    models.TipeTransaksiSaldoAwal,
+			BarisTransaksi:   barisJurnal,
+		})
+		if jurnalErr != nil {
+			return fmt.Errorf("gagal membuat jurnal saldo awal: %w", jurnalErr)
+		}
+
+		for i := range simpananList {
+			simpananList[i].IDTransaksi = &jurnal.ID
+			simpananList[i].NomorReferensi = jurnal.NomorJurnal
+			simpananList[i].DibuatOleh = idPengguna
+		}
+		if len(simpananList) > 0 {
+			if createErr := tx.Create(&simpananList).Error; createErr != nil {
+				return errors.New("gagal mencatat simpanan saldo awal")
+			}
+		}
+
+		hasil.Disimpan = true
+		hasil.IDTransaksi = &jurnal.ID
+		hasil.NomorJurnal = jurnal.NomorJurnal
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hasil, nil
+}
+
+// cekSaldoAwalDapatDiimporWithTx memastikan belum ada saldo awal aktif, belum ada jurnal pada atau
+// sebelum tanggal saldo, dan periode tanggal saldo masih terbuka
+func (s *SaldoAwalService) cekSaldoAwalDapatDiimporWithTx(tx *gorm.DB, idKoperasi uuid.UUID, tanggalSaldo time.Time) error {
+	aktif, err := s.jurnalSaldoAwalAktifWithTx(tx, idKoperasi)
+	if err != nil {
+		return err
+	}
+	if aktif != nil {
+		return fmt.Errorf("saldo awal sudah diimpor dengan jurnal %s, batalkan terlebih dahulu untuk mengimpor ulang", aktif.NomorJurnal)
+	}
+
+	var jumlahJurnal int64
+	if countErr := tx.Model(&models.Transaksi{}).
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi).
+		Where("transaksi.tanggal_transaksi <= ? AND COALESCE(transaksi.tipe_transaksi, '') <> ?",
+			tanggalSaldo.Format("2006-01-02"), models.TipeTransaksiSaldoAwal).
+		Count(&jumlahJurnal).Error; countErr != nil {
+		return errors.New("gagal memeriksa jurnal yang sudah ada")
+	}
+	if jumlahJurnal > 0 {
+		return fmt.Errorf("sudah ada %d jurnal pada atau sebelum tanggal saldo awal %s", jumlahJurnal, tanggalSaldo.Format("2006-01-02"))
+	}
+
+	return cekPeriodeTerbukaWithTx(tx, idKoperasi, tanggalSaldo)
+}
+
+// jurnalSaldoAwalAktifWithTx mengambil jurnal saldo awal yang belum dibalik, atau nil jika tidak ada
+func (s *SaldoAwalService) jurnalSaldoAwalAktifWithTx(tx *gorm.DB, idKoperasi uuid.UUID) (*models.Transaksi, error) {
+	var jurnal models.Transaksi
+	err := tx.Where("id_koperasi = ? AND tipe_transaksi = ? AND id_jurnal_asal IS NULL AND dibalik = ?",
+		idKoperasi, models.TipeTransaksiSaldoAwal, false).
+		First(&jurnal).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("gagal memeriksa jurnal saldo awal")
+	}
+	return &jurnal, nil
+}
+
+// validasiNeracaWithTx memvalidasi neraca saldo dan menyusunnya menjadi baris jurnal pembukaan.
+// Akun dengan saldo nol dilewati.
+func (s *SaldoAwalService) validasiNeracaWithTx(tx *gorm.DB, idKoperasi uuid.UUID, barisList []BarisSaldoAwalAkun, hasil *HasilImporSaldoAwal) ([]BuatBarisTransaksiRequest, error) {
+	var akunList []models.Akun
+	if err := tx.Where("id_koperasi = ?", idKoperasi).Find(&akunList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar akun")
+	}
+	akunByKode := make(map[string]models.Akun, len(akunList))
+	for _, akun := range akunList {
+		akunByKode[akun.KodeAkun] = akun
+	}
+
+	barisJurnal := []BuatBarisTransaksiRequest{}
+	barisByKode := make(map[string]int, len(barisList))
+	for i, baris := range barisList {
+		nomor := baris.Baris
+		if nomor == 0 {
+			nomor = i + 1
+		}
+		kode := strings.TrimSpace(baris.KodeAkun)
+
+		if kode == "" {
+			hasil.tambahKesalahan(BagianSaldoAwalAkun, nomor, "", "kode akun wajib diisi")
+			continue
+		}
+		if sebelumnya, ganda := barisByKode[kode]; ganda {
+			hasil.tambahKesalahan(BagianSaldoAwalAkun, nomor, kode, fmt.Sprintf("akun %s sudah ada di baris %d", kode, sebelumnya))
+			continue
+		}
+		barisByKode[kode] = nomor
+
+		akun, ada := akunByKode[kode]
+		switch {
+		case !ada:
+			hasil.tambahKesalahan(BagianSaldoAwalAkun, nomor, kode, fmt.Sprintf("akun %s tidak ditemukan", kode))
+			continue
+		case !akun.StatusAktif:
+			hasil.tambahKesalahan(BagianSaldoAwalAkun, nomor, kode, fmt.Sprintf("akun %s tidak aktif", kode))
+			continue
+		case baris.SaldoDebit < 0 || baris.SaldoKredit < 0:
+			hasil.tambahKesalahan(BagianSaldoAwalAkun, nomor, kode, "saldo tidak boleh negatif")
+			continue
+		case baris.SaldoDebit > 0 && baris.SaldoKredit > 0:
+			hasil.tambahKesalahan(BagianSaldoAwalAkun, nomor, kode, "isi saldo debit atau saldo kredit saja, tidak keduanya")
+			continue
+		case baris.SaldoDebit == 0 && baris.SaldoKredit == 0:
+			continue
+		}
+
+		hasil.JumlahAkun++
+		hasil.TotalDebit += baris.SaldoDebit
+		hasil.TotalKredit += baris.SaldoKredit
+		barisJurnal = append(barisJurnal, BuatBarisTransaksiRequest{
+			IDAkun:       akun.ID,
+			JumlahDebit:  baris.SaldoDebit,
+			JumlahKredit: baris.SaldoKredit,
+			Keterangan:   keteranganSaldoAwal,
+		})
+	}
+
+	if len(barisJurnal) == 0 {
+		hasil.tambahKesalahan(BagianSaldoAwalNeraca, 0, "", "neraca saldo awal tidak memuat akun bersaldo")
+	}
+	if hasil.TotalDebit != hasil.TotalKredit {
+		hasil.tambahKesalahan(BagianSaldoAwalNeraca, 0, "", fmt.Sprintf("total debit %s tidak sama dengan total kredit %s (selisih %s)",
+			hasil.TotalDebit, hasil.TotalKredit, (hasil.TotalDebit - hasil.TotalKredit).Abs()))
+	}
+
+	return barisJurnal, nil
+}
+
+// validasiSimpananWithTx memvalidasi saldo simpanan anggota dan menyusunnya menjadi setoran saldo awal
+func (s *SaldoAwalService) validasiSimpananWithTx(tx *gorm.DB, idKoperasi uuid.UUID, barisList []BarisSaldoAwalSimpanan, hasil *HasilImporSaldoAwal) ([]models.Simpanan, error) {
+	nomorList := make([]string, 0, len(barisList))
+	for _, baris := range barisList {
+		nomorList = append(nomorList, strings.TrimSpace(baris.NomorAnggota))
+	}
+
+	var anggotaList []models.Anggota
+	if len(nomorList) > 0 {
+		if err := tx.Where("id_koperasi = ? AND nomor_anggota IN ?", idKoperasi, nomorList).Find(&anggotaList).Error; err != nil {
+			return nil, errors.New("gagal mengambil data anggota")
+		}
+	}
+	anggotaByNomor := make(map[string]models.Anggota, len(anggotaList))
+	for _, anggota := range anggotaList {
+		anggotaByNomor[anggota.NomorAnggota] = anggota
+	}
+
+	// Simpanan pokok hanya dibayar sekali (UU No. 25 Tahun 1992)
+	var idSudahPokok []uuid.UUID
+	if len(anggotaList) > 0 {
+		if err := tx.Model(&models.Simpanan{}).
+			Where("id_koperasi = ? AND tipe_simpanan = ? AND jenis_transaksi = ? AND dibatalkan = ?",
+				idKoperasi, models.SimpananPokok, models.TransaksiSetoran, false).
+			Pluck("id_anggota", &idSudahPokok).Error; err != nil {
+			return nil, errors.New("gagal memeriksa simpanan pokok anggota")
+		}
+	}
+	sudahPokok := make(map[uuid.UUID]bool, len(idSudahPokok))
+	for _, id := range idSudahPokok {
+		sudahPokok[id] = true
+	}
+
+	simpananList := []models.Simpanan{}
+	barisByKunci := make(map[string]int, len(barisList))
+	for i, baris := range barisList {
+		nomor := baris.Baris
+		if nomor == 0 {
+			nomor = i + 1
+		}
+		nomorAnggota := strings.TrimSpace(baris.NomorAnggota)
+
+		if nomorAnggota == "" {
+			hasil.tambahKesalahan(BagianSaldoAwalSimpanan, nomor, "", "nomor anggota wajib diisi")
+			continue
+		}
+		if _, valid := peristiwaSimpanan[baris.TipeSimpanan]; !valid {
+			hasil.tambahKesalahan(BagianSaldoAwalSimpanan, nomor, nomorAnggota,
+				fmt.Sprintf("tipe simpanan %q tidak valid (POKOK, WAJIB atau SUKARELA)", baris.TipeSimpanan))
+			continue
+		}
+
+		kunci := nomorAnggota + "|" + string(baris.TipeSimpanan)
+		if sebelumnya, ganda := barisByKunci[kunci]; ganda {
+			hasil.tambahKesalahan(BagianSaldoAwalSimpanan, nomor, nomorAnggota,
+				fmt.Sprintf("simpanan %s anggota %s sudah ada di baris %d", baris.TipeSimpanan, nomorAnggota, sebelumnya))
+			continue
+		}
+		barisByKunci[kunci] = nomor
+
+		anggota, ada := anggotaByNomor[nomorAnggota]
+		switch {
+		case !ada:
+			hasil.tambahKesalahan(BagianSaldoAwalSimpanan, nomor, nomorAnggota, fmt.Sprintf("anggota %s tidak ditemukan", nomorAnggota))
+			continue
+		case baris.Jumlah <= 0:
+			hasil.tambahKesalahan(BagianSaldoAwalSimpanan, nomor, nomorAnggota, "jumlah simpanan harus lebih dari 0")
+			continue
+		case baris.TipeSimpanan == models.SimpananPokok && sudahPokok[anggota.ID]:
+			hasil.tambahKesalahan(BagianSaldoAwalSimpanan, nomor, nomorAnggota, "anggota sudah membayar simpanan pokok")
+			continue
+		}
+
+		hasil.JumlahSimpanan++
+		simpananList = append(simpananList, models.Simpanan{
+			IDKoperasi:       idKoperasi,
+			IDAnggota:        anggota.ID,
+			TipeSimpanan:     baris.TipeSimpanan,
+			JenisTransaksi:   models.TransaksiSetoran,
+			TanggalTransaksi: hasil.TanggalSaldo,
+			JumlahSetoran:    baris.Jumlah,
+			Keterangan:       keteranganSaldoAwal,
+		})
+	}
+
+	return simpananList, nil
+}
+
+// rekonsiliasiSimpananWithTx mencocokkan saldo kredit akun simpanan di neraca dengan total simpanan
+// anggota. Akun simpanan diambil dari aturan posting, sehingga beberapa tipe simpanan yang
+// dipetakan ke akun yang sama direkonsiliasi bersama.
+func (s *SaldoAwalService) rekonsiliasiSimpananWithTx(tx *gorm.DB, idKoperasi uuid.UUID, barisJurnal []BuatBarisTransaksiRequest, simpananList []models.Simpanan, hasil *HasilImporSaldoAwal) {
+	saldoByAkun := make(map[uuid.UUID]models.Uang, len(barisJurnal))
+	for _, baris := range barisJurnal {
+		saldoByAkun[baris.IDAkun] += baris.JumlahKredit - baris.JumlahDebit
+	}
+
+	totalByTipe := make(map[models.TipeSimpanan]models.Uang)
+	for _, simpanan := range simpananList {
+		totalByTipe[simpanan.TipeSimpanan] += simpanan.JumlahSetoran
+	}
+
+	indeksByAkun := make(map[uuid.UUID]int)
+	for _, tipe := range []models.TipeSimpanan{models.SimpananPokok, models.SimpananWajib, models.SimpananSukarela} {
+		akunPosting, err := akunPostingWithTx(tx, idKoperasi, peristiwaSimpanan[tipe])
+		if err != nil {
+			// Akun simpanan yang belum tersedia hanya bermasalah jika ada saldo anggota untuknya
+			if totalByTipe[tipe] != 0 {
+				hasil.tambahKesalahan(BagianSaldoAwalRekonsiliasi, 0, "", fmt.Sprintf("simpanan %s: %s", tipe, err.Error()))
+			}
+			continue
+		}
+
+		akun := akunPosting[models.PeranAkunSimpanan]
+		indeks, ada := indeksByAkun[akun.ID]
+		if !ada {
+			indeks = len(hasil.Rekonsiliasi)
+			indeksByAkun[akun.ID] = indeks
+			hasil.Rekonsiliasi = append(hasil.Rekonsiliasi, RekonsiliasiSaldoAwalSimpanan{
+				KodeAkun:  akun.KodeAkun,
+				NamaAkun:  akun.NamaAkun,
+				SaldoAkun: saldoByAkun[akun.ID],
+			})
+		}
+		rekonsiliasi := &hasil.Rekonsiliasi[indeks]
+		rekonsiliasi.TipeSimpanan = append(rekonsiliasi.TipeSimpanan, tipe)
+		rekonsiliasi.TotalSimpananAnggota += totalByTipe[tipe]
+	}
+
+	for i := range hasil.Rekonsiliasi {
+		rekonsiliasi := &hasil.Rekonsiliasi[i]
+		rekonsiliasi.Selisih = rekonsiliasi.SaldoAkun - rekonsiliasi.TotalSimpananAnggota
+		if rekonsiliasi.Selisih != 0 {
+			hasil.tambahKesalahan(BagianSaldoAwalRekonsiliasi, 0, rekonsiliasi.KodeAkun,
+				fmt.Sprintf("saldo akun %s %s tidak sama dengan total simpanan anggota %s (selisih %s)",
+					rekonsiliasi.KodeAkun, rekonsiliasi.SaldoAkun, rekonsiliasi.TotalSimpananAnggota, rekonsiliasi.Selisih))
+		}
+	}
+}
+
+// BatalkanSaldoAwalRequest adalah struktur request untuk membatalkan saldo awal
+type BatalkanSaldoAwalRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// BatalkanSaldoAwal membatalkan saldo awal yang salah agar dapat diimpor ulang.
+//
+// Jurnal pembukaan dibalik dengan jurnal pembalik bertanggal sama sehingga saldo pada tanggal
+// berapa pun kembali nol, dan seluruh simpanan saldo awal ditandai dibatalkan. Pembatalan ditolak
+// jika saldo simpanan anggota sudah terpakai (misalnya ditarik) sehingga akan menjadi negatif.
+func (s *SaldoAwalService) BatalkanSaldoAwal(idKoperasi, idPengguna uuid.UUID, req *BatalkanSaldoAwalRequest) (*models.TransaksiResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	var pembalik *models.Transaksi
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var koperasi models.Koperasi
+		if lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", idKoperasi).First(&koperasi).Error; lockErr != nil {
+			return errors.New("koperasi tidak ditemukan")
+		}
+
+		jurnal, err := s.jurnalSaldoAwalAktifWithTx(tx, idKoperasi)
+		if err != nil {
+			return err
+		}
+		if jurnal == nil {
+			return errors.New("saldo awal belum diimpor")
+		}
+
+		var simpananList []models.Simpanan
+		if findErr := tx.Where("id_koperasi = ? AND id_transaksi = ? AND dibatalkan = ?", idKoperasi, jurnal.ID, false).
+			Find(&simpananList).Error; findErr != nil {
+			return errors.New("gagal mengambil simpanan saldo awal")
+		}
+
+		// Saldo simpanan setelah pembatalan tidak boleh negatif
+		for _, simpanan := range simpananList {
+			var saldo models.Uang
+			if saldoErr := tx.Model(&models.Simpanan{}).
+				Select("COALESCE(SUM("+ekspresiJumlahBersihSimpanan+"), 0)").
+				Where("id_anggota = ? AND tipe_simpanan = ?", simpanan.IDAnggota, simpanan.TipeSimpanan).
+				Scan(&saldo).Error; saldoErr != nil {
+				return errors.New("gagal menghitung saldo simpanan")
+			}
+			if simpanan.JumlahSetoran > saldo {
+				var anggota models.Anggota
+				tx.Select("nomor_anggota").Where("id = ?", simpanan.IDAnggota).First(&anggota)
+				return fmt.Errorf("saldo awal tidak dapat dibatalkan karena saldo simpanan %s anggota %s tinggal %s",
+					simpanan.TipeSimpanan, anggota.NomorAnggota, saldo)
+			}
+		}
+
+		pembalik, err = s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, jurnal.ID,
+			jurnal.TanggalTransaksi, req.Alasan, models.TipeTransaksiSaldoAwal)
+		if err != nil {
+			return fmt.Errorf("gagal membalik jurnal saldo awal: %w", err)
+		}
+
+		sekarang := time.Now()
+		if updateErr := tx.Model(&models.Simpanan{}).
+			Where("id_koperasi = ? AND id_transaksi = ? AND dibatalkan = ?", idKoperasi, jurnal.ID, false).
+			Updates(map[string]interface{}{
+				"dibatalkan":         true,
+				"tanggal_dibatalkan": sekarang,
+				"dibatalkan_oleh":    idPengguna,
+				"alasan_pembatalan":  req.Alasan,
+			}).Error; updateErr != nil {
+			return errors.New("gagal membatalkan simpanan saldo awal")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := pembalik.ToResponse()
+	return &response, nil
+}
+
+// Kolom berkas saldo awal
+const (
+	kolomSaldoAwalKodeAkun     = "kodeAkun"
+	kolomSaldoAwalSaldoDebit   = "saldoDebit"
+	kolomSaldoAwalSaldoKredit  = "saldoKredit"
+	kolomSaldoAwalNomorAnggota = "nomorAnggota"
+	kolomSaldoAwalTipeSimpanan = "tipeSimpanan"
+	kolomSaldoAwalJumlah       = "jumlah"
+)
+
+// BacaBerkasNeracaSaldoAwal membaca neraca saldo awal dari berkas CSV atau XLSX dengan kolom
+// kodeAkun, saldoDebit dan saldoKredit. Sel nominal kosong dianggap nol.
+func BacaBerkasNeracaSaldoAwal(format FormatBerkas, data []byte) ([]BarisSaldoAwalAkun, error) {
+	tabel, err := bacaTabelImpor(format, data, kolomSaldoAwalKodeAkun, kolomSaldoAwalSaldoDebit, kolomSaldoAwalSaldoKredit)
+	if err != nil {
+		return nil, err
+	}
+
+	barisList := []BarisSaldoAwalAkun{}
+	for i, sel := range tabel.baris {
+		if barisKosong(sel) {
+			continue
+		}
+		nomor := i + 2
+
+		debit, err := parseNominalImpor(tabel.ambil(sel, kolomSaldoAwalSaldoDebit))
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: saldo debit tidak valid", nomor)
+		}
+		kredit, err := parseNominalImpor(tabel.ambil(sel, kolomSaldoAwalSaldoKredit))
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: saldo kredit tidak valid", nomor)
+		}
+
+		barisList = append(barisList, BarisSaldoAwalAkun{
+			Baris:       nomor,
+			KodeAkun:    tabel.ambil(sel, kolomSaldoAwalKodeAkun),
+			SaldoDebit:  debit,
+			SaldoKredit: kredit,
+		})
+	}
+
+	if len(barisList) == 0 {
+		return nil, errors.New("berkas neraca saldo awal tidak memuat data akun")
+	}
+	return barisList, nil
+}
+
+// BacaBerkasSimpananSaldoAwal membaca saldo simpanan anggota dari berkas CSV atau XLSX dengan
+// kolom nomorAnggota, tipeSimpanan dan jumlah
+func BacaBerkasSimpananSaldoAwal(format FormatBerkas, data []byte) ([]BarisSaldoAwalSimpanan, error) {
+	tabel, err := bacaTabelImpor(format, data, kolomSaldoAwalNomorAnggota, kolomSaldoAwalTipeSimpanan, kolomSaldoAwalJumlah)
+	if err != nil {
+		return nil, err
+	}
+
+	barisList := []BarisSaldoAwalSimpanan{}
+	for i, sel := range tabel.baris {
+		if barisKosong(sel) {
+			continue
+		}
+		nomor := i + 2
+
+		jumlah, err := parseNominalImpor(tabel.ambil(sel, kolomSaldoAwalJumlah))
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: jumlah simpanan tidak valid", nomor)
+		}
+
+		barisList = append(barisList, BarisSaldoAwalSimpanan{
+			Baris:        nomor,
+			NomorAnggota: tabel.ambil(sel, kolomSaldoAwalNomorAnggota),
+			TipeSimpanan: models.TipeSimpanan(strings.ToUpper(tabel.ambil(sel, kolomSaldoAwalTipeSimpanan))),
+			Jumlah:       jumlah,
+		})
+	}
+
+	return barisList, nil
+}
+
+// parseNominalImpor membaca nominal dari sel berkas impor; sel kosong bernilai nol.
+// Pemisah ribuan ("1.000.000" atau "1,000,000") ditolak agar tidak terbaca sebagai desimal.
+func parseNominalImpor(nilai string) (models.Uang, error) {
+	if nilai == "" {
+		return 0, nil
+	}
+	if _, pecahan, ada := strings.Cut(nilai, "."); strings.Contains(nilai, ",") || (ada && len(pecahan) > 2) {
+		return 0, fmt.Errorf("nominal %q tidak valid, tulis tanpa pemisah ribuan", nilai)
+	}
+	return models.ParseUang(nilai)
+}
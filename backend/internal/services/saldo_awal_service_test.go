@@ -0,0 +1,194 @@
+package services
+
+import (
+	"bytes"
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/xlsx"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacaBerkasNeracaSaldoAwal(t *testing.T) {
+	data := []byte("Kode Akun,Saldo Debit,Saldo Kredit\n" +
+		"1101,1500000.50,\n" +
+		",,\n" +
+		"3101,,1500000.50\n")
+	barisList, err := BacaBerkasNeracaSaldoAwal(FormatCSV, data)
+	require.NoError(t, err)
+	require.Len(t, barisList, 2)
+
+	assert.Equal(t, BarisSaldoAwalAkun{Baris: 2, KodeAkun: "1101", SaldoDebit: mustUang("1500000.50")}, barisList[0])
+	assert.Equal(t, BarisSaldoAwalAkun{Baris: 4, KodeAkun: "3101", SaldoKredit: mustUang("1500000.50")}, barisList[1])
+
+	_, err = BacaBerkasNeracaSaldoAwal(FormatCSV, []byte("kodeAkun,saldoDebit,saldoKredit\n1101,1.000,\n"))
+	assert.Error(t, err, "pemisah ribuan tidak boleh terbaca sebagai desimal")
+
+	_, err = BacaBerkasNeracaSaldoAwal(FormatCSV, []byte("kodeAkun,saldoDebit,saldoKredit\n1101,\"1,000\",\n"))
+	assert.Error(t, err)
+
+	_, err = BacaBerkasNeracaSaldoAwal(FormatCSV, []byte("kodeAkun,saldo\n1101,1000\n"))
+	assert.Error(t, err, "header tanpa kolom saldo debit/kredit harus ditolak")
+}
+
+func TestBacaBerkasSimpananSaldoAwal(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, xlsx.Tulis(&buf, "Simpanan", [][]string{
+		{"nomor_anggota", "tipe_simpanan", "jumlah"},
+		{"A0001", "pokok", "100000"},
+		{"A0002", "SUKARELA", "25000.75"},
+	}))
+
+	barisList, err := BacaBerkasSimpananSaldoAwal(FormatXLSX, buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, barisList, 2)
+	assert.Equal(t, models.SimpananPokok, barisList[0].TipeSimpanan)
+	assert.Equal(t, models.Rupiah(100000), barisList[0].Jumlah)
+	assert.Equal(t, 3, barisList[1].Baris)
+	assert.Equal(t, mustUang("25000.75"), barisList[1].Jumlah)
+}
+
+func TestImporSaldoAwal(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+	)
+	require.NoError(t, err)
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Saldo Awal Koperasi", Alamat: "Test Address"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	require.NoError(t, NewAkunService(db).InisialisasiCOADefault(koperasi.ID))
+
+	anggota1 := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "A0001", NamaLengkap: "Anggota Satu", Status: models.StatusAktif}
+	anggota2 := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "A0002", NamaLengkap: "Anggota Dua", Status: models.StatusAktif}
+	db.Create(anggota1)
+	db.Create(anggota2)
+
+	idPengguna := anggota1.ID
+	transaksiService := NewTransaksiService(db)
+	simpananService := NewSimpananService(db, transaksiService)
+	service := NewSaldoAwalService(db, transaksiService)
+
+	tanggalSaldo := time.Now().AddDate(0, 0, -30).Truncate(24 * time.Hour)
+	req := &ImporSaldoAwalRequest{
+		TanggalSaldo: tanggalSaldo,
+		Akun: []BarisSaldoAwalAkun{
+			{KodeAkun: "1101", SaldoDebit: models.Rupiah(2000000)},
+			{KodeAkun: "3101", SaldoKredit: models.Rupiah(200000)},
+			{KodeAkun: "3102", SaldoKredit: models.Rupiah(300000)},
+			{KodeAkun: "3103", SaldoKredit: models.Rupiah(500000)},
+			{KodeAkun: "3202", SaldoKredit: models.Rupiah(1000000)},
+			{KodeAkun: "2101"},
+		},
+		Simpanan: []BarisSaldoAwalSimpanan{
+			{NomorAnggota: "A0001", TipeSimpanan: models.SimpananPokok, Jumlah: models.Rupiah(100000)},
+			{NomorAnggota: "A0002", TipeSimpanan: models.SimpananPokok, Jumlah: models.Rupiah(100000)},
+			{NomorAnggota: "A0001", TipeSimpanan: models.SimpananWajib, Jumlah: models.Rupiah(300000)},
+			{NomorAnggota: "A0002", TipeSimpanan: models.SimpananSukarela, Jumlah: models.Rupiah(400000)},
+		},
+		DryRun: true,
+	}
+
+	// Simpanan sukarela anggota kurang Rp100.000 dari saldo akun 3103
+	hasil, err := service.ImporSaldoAwal(koperasi.ID, idPengguna, req)
+	require.NoError(t, err)
+	require.Len(t, hasil.Kesalahan, 1)
+	assert.Equal(t, BagianSaldoAwalRekonsiliasi, hasil.Kesalahan[0].Bagian)
+	assert.Equal(t, "3103", hasil.Kesalahan[0].Kode)
+
+	// Dry run yang valid tidak menyimpan apa pun
+	req.Simpanan[3].Jumlah = models.Rupiah(500000)
+	hasil, err = service.ImporSaldoAwal(koperasi.ID, idPengguna, req)
+	require.NoError(t, err)
+	assert.Empty(t, hasil.Kesalahan)
+	assert.False(t, hasil.Disimpan)
+	assert.Equal(t, 5, hasil.JumlahAkun, "akun bersaldo nol dilewati")
+	assert.Equal(t, models.Rupiah(2000000), hasil.TotalDebit)
+	assert.Len(t, hasil.Rekonsiliasi, 3)
+
+	var jumlahJurnal int64
+	db.Model(&models.Transaksi{}).Where("id_koperasi = ?", koperasi.ID).Count(&jumlahJurnal)
+	assert.Zero(t, jumlahJurnal)
+
+	// Impor sebenarnya: satu jurnal SALDO_AWAL dan simpanan pembuka per anggota
+	req.DryRun = false
+	hasil, err = service.ImporSaldoAwal(koperasi.ID, idPengguna, req)
+	require.NoError(t, err)
+	require.True(t, hasil.Disimpan)
+	require.NotNil(t, hasil.IDTransaksi)
+
+	var jurnal models.Transaksi
+	require.NoError(t, db.Preload("BarisTransaksi").First(&jurnal, "id = ?", *hasil.IDTransaksi).Error)
+	assert.Equal(t, models.TipeTransaksiSaldoAwal, jurnal.TipeTransaksi)
+	assert.Len(t, jurnal.BarisTransaksi, 5)
+
+	saldo1, err := simpananService.DapatkanSaldoAnggota(anggota1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(100000), saldo1.SimpananPokok)
+	assert.Equal(t, models.Rupiah(300000), saldo1.SimpananWajib)
+
+	saldo2, err := simpananService.DapatkanSaldoAnggota(anggota2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(500000), saldo2.SimpananSukarela)
+
+	laporanService := NewLaporanService(db, NewAkunService(db), simpananService, nil)
+	neraca, err := laporanService.GenerateLaporanPosisiKeuangan(koperasi.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(2000000), neraca.TotalAset)
+	assert.Equal(t, neraca.TotalAset, neraca.TotalKewajiban+neraca.TotalModal)
+
+	// Saldo awal tidak menjadi arus kas periode
+	arusKas, err := laporanService.GenerateLaporanArusKas(koperasi.ID,
+		tanggalSaldo.Format("2006-01-02"), time.Now().Format("2006-01-02"), MetodeArusKasLangsung)
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(2000000), arusKas.SaldoKasAwal)
+	assert.Zero(t, arusKas.KenaikanKasBersih)
+
+	// Simpanan pokok dari saldo awal berlaku untuk aturan bayar sekali
+	_, err = simpananService.CatatSetoran(koperasi.ID, idPengguna, &CatatSetoranRequest{
+		IDAnggota:        anggota1.ID,
+		TipeSimpanan:     models.SimpananPokok,
+		TanggalTransaksi: time.Now(),
+		JumlahSetoran:    models.Rupiah(100000),
+	})
+	assert.Error(t, err)
+
+	// Jurnal dan simpanan saldo awal tidak dapat diubah satu per satu
+	_, err = transaksiService.BalikTransaksi(jurnal.ID, koperasi.ID, idPengguna, &BalikTransaksiRequest{Alasan: "Koreksi saldo awal"})
+	assert.Error(t, err)
+
+	var simpananPembuka models.Simpanan
+	require.NoError(t, db.Where("id_transaksi = ?", jurnal.ID).First(&simpananPembuka).Error)
+	_, err = simpananService.BatalkanSimpanan(koperasi.ID, idPengguna, simpananPembuka.ID, &BatalkanSimpananRequest{Alasan: "Salah input"})
+	assert.Error(t, err)
+
+	// Impor kedua ditolak selama saldo awal masih berlaku
+	_, err = service.ImporSaldoAwal(koperasi.ID, idPengguna, req)
+	assert.Error(t, err)
+
+	// Pembatalan membalik jurnal dan mengosongkan saldo simpanan, lalu impor ulang diperbolehkan
+	_, err = service.BatalkanSaldoAwal(koperasi.ID, idPengguna, &BatalkanSaldoAwalRequest{Alasan: "Neraca migrasi salah"})
+	require.NoError(t, err)
+
+	saldo1, err = simpananService.DapatkanSaldoAnggota(anggota1.ID)
+	require.NoError(t, err)
+	assert.Zero(t, saldo1.TotalSimpanan)
+
+	hasil, err = service.ImporSaldoAwal(koperasi.ID, idPengguna, req)
+	require.NoError(t, err)
+	assert.True(t, hasil.Disimpan)
+}
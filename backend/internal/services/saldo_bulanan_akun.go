@@ -0,0 +1,213 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ekspresiBulanJurnal adalah awal bulan kalender tanggal jurnal, kunci snapshot saldo bulanan
+const ekspresiBulanJurnal = "DATE_TRUNC('month', transaksi.tanggal_transaksi)::date"
+
+// mutasiSaldoBulanan adalah total mutasi satu akun dalam satu bulan
+type mutasiSaldoBulanan struct {
+	IDKoperasi   uuid.UUID
+	IDAkun       uuid.UUID
+	Bulan        time.Time
+	MutasiDebit  models.Uang
+	MutasiKredit models.Uang
+}
+
+// kueriMutasiBulanan menjumlahkan baris jurnal yang sudah di-post per akun per bulan
+func kueriMutasiBulanan(tx *gorm.DB) *gorm.DB {
+	return tx.Table("baris_transaksi").
+		Select(`transaksi.id_koperasi, baris_transaksi.id_akun, `+ekspresiBulanJurnal+` as bulan,
+			COALESCE(SUM(baris_transaksi.jumlah_debit), 0) as mutasi_debit,
+			COALESCE(SUM(baris_transaksi.jumlah_kredit), 0) as mutasi_kredit`).
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where(kondisiJurnalPosted).
+		Group("transaksi.id_koperasi, baris_transaksi.id_akun, bulan")
+}
+
+// catatMutasiSaldoWithTx menambahkan (arah 1) atau mengurangkan (arah -1) baris jurnal idTransaksi
+// ke snapshot saldo bulanan dalam transaction yang sama dengan perubahan jurnalnya.
+// Jurnal yang belum di-post atau sudah dihapus tidak mengubah snapshot.
+func catatMutasiSaldoWithTx(tx *gorm.DB, idTransaksi uuid.UUID, arah int64) error {
+	var mutasiList []mutasiSaldoBulanan
+	err := kueriMutasiBulanan(tx).
+		Where("transaksi.id = ?", idTransaksi).
+		// Urutan tetap agar posting bersamaan mengunci baris snapshot dengan urutan yang sama
+		Order("baris_transaksi.id_akun").
+		Scan(&mutasiList).Error
+	if err != nil {
+		return errors.New("gagal menghitung mutasi saldo bulanan")
+	}
+
+	for _, mutasi := range mutasiList {
+		saldo := models.SaldoBulananAkun{
+			IDKoperasi:   mutasi.IDKoperasi,
+			IDAkun:       mutasi.IDAkun,
+			Bulan:        mutasi.Bulan,
+			MutasiDebit:  models.Uang(arah) * mutasi.MutasiDebit,
+			MutasiKredit: models.Uang(arah) * mutasi.MutasiKredit,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id_akun"}, {Name: "bulan"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"mutasi_debit":       gorm.Expr("saldo_bulanan_akun.mutasi_debit + excluded.mutasi_debit"),
+				"mutasi_kredit":      gorm.Expr("saldo_bulanan_akun.mutasi_kredit + excluded.mutasi_kredit"),
+				"tanggal_diperbarui": time.Now(),
+			}),
+		}).Create(&saldo).Error; err != nil {
+			return errors.New("gagal memperbarui saldo bulanan akun")
+		}
+	}
+
+	return nil
+}
+
+// totalMutasiAkun adalah total debit dan kredit satu akun sampai suatu tanggal
+type totalMutasiAkun struct {
+	IDAkun      uuid.UUID
+	TotalDebit  models.Uang
+	TotalKredit models.Uang
+}
+
+// hitungTotalMutasiAkun menjumlahkan mutasi jurnal yang sudah di-post per akun sampai tanggalPer
+// (format YYYY-MM-DD, kosong berarti semua jurnal). Bulan-bulan sebelum bulan tanggalPer dibaca
+// dari snapshot saldo bulanan; hanya jurnal sejak awal bulan tanggalPer yang dijumlahkan langsung.
+// idAkun opsional membatasi perhitungan ke satu akun. idUnitUsaha opsional membatasi perhitungan
+// ke satu unit usaha (lihat kondisiUnitUsaha); karena snapshot tidak memiliki dimensi unit usaha,
+// seluruh jurnal unit tersebut dijumlahkan langsung.
+func hitungTotalMutasiAkun(db *gorm.DB, idKoperasi uuid.UUID, idAkun *uuid.UUID, tanggalPer string, idUnitUsaha *uuid.UUID) (map[uuid.UUID]totalMutasiAkun, error) {
+	acuan := time.Now()
+	if tanggalPer != "" {
+		var err error
+		acuan, err = time.Parse("2006-01-02", tanggalPer)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid")
+		}
+	}
+	awalBulan := time.Date(acuan.Year(), acuan.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+
+	var snapshot []totalMutasiAkun
+	if idUnitUsaha == nil {
+		snapshotQuery := db.Model(&models.SaldoBulananAkun{}).
+			Select("id_akun, COALESCE(SUM(mutasi_debit), 0) as total_debit, COALESCE(SUM(mutasi_kredit), 0) as total_kredit").
+			Where("id_koperasi = ? AND bulan < ?", idKoperasi, awalBulan)
+		if idAkun != nil {
+			snapshotQuery = snapshotQuery.Where("id_akun = ?", *idAkun)
+		}
+		if err := snapshotQuery.Group("id_akun").Scan(&snapshot).Error; err != nil {
+			return nil, errors.New("gagal mengambil saldo bulanan akun")
+		}
+	}
+
+	var delta []totalMutasiAkun
+	deltaQuery := db.Table("baris_transaksi").
+		Select("baris_transaksi.id_akun, COALESCE(SUM(baris_transaksi.jumlah_debit), 0) as total_debit, COALESCE(SUM(baris_transaksi.jumlah_kredit), 0) as total_kredit").
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where("transaksi.id_koperasi = ? AND "+kondisiJurnalPosted, idKoperasi)
+	if idUnitUsaha == nil {
+		deltaQuery = deltaQuery.Where("transaksi.tanggal_transaksi >= ?", awalBulan)
+	} else {
+		kondisi, args := kondisiUnitUsaha(idUnitUsaha)
+		deltaQuery = deltaQuery.Where(kondisi, args...)
+	}
+	if tanggalPer != "" {
+		deltaQuery = deltaQuery.Where("transaksi.tanggal_transaksi <= ?", tanggalPer)
+	}
+	if idAkun != nil {
+		deltaQuery = deltaQuery.Where("baris_transaksi.id_akun = ?", *idAkun)
+	}
+	if err := deltaQuery.Group("baris_transaksi.id_akun").Scan(&delta).Error; err != nil {
+		return nil, errors.New("gagal menghitung mutasi akun")
+	}
+
+	hasil := make(map[uuid.UUID]totalMutasiAkun, len(snapshot))
+	for _, mutasi := range append(snapshot, delta...) {
+		total := hasil[mutasi.IDAkun]
+		total.IDAkun = mutasi.IDAkun
+		total.TotalDebit += mutasi.TotalDebit
+		total.TotalKredit += mutasi.TotalKredit
+		hasil[mutasi.IDAkun] = total
+	}
+
+	return hasil, nil
+}
+
+// BangunUlangSaldoBulanan menghitung ulang seluruh snapshot saldo bulanan koperasi dari jurnal
+// yang sudah di-post, misalnya setelah upgrade atau perbaikan data langsung di database.
+// Mengembalikan jumlah baris snapshot yang ditulis.
+func (s *AkunService) BangunUlangSaldoBulanan(idKoperasi uuid.UUID) (int, error) {
+	var jumlah int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Tahan posting jurnal selama snapshot dibangun agar tidak ada mutasi yang terlewat atau terhitung dua kali
+		if err := tx.Exec("LOCK TABLE saldo_bulanan_akun IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return errors.New("gagal mengunci saldo bulanan akun")
+		}
+
+		if err := tx.Where("id_koperasi = ?", idKoperasi).Delete(&models.SaldoBulananAkun{}).Error; err != nil {
+			return errors.New("gagal menghapus saldo bulanan lama")
+		}
+
+		var mutasiList []mutasiSaldoBulanan
+		err := kueriMutasiBulanan(tx).
+			Where("transaksi.id_koperasi = ?", idKoperasi).
+			Scan(&mutasiList).Error
+		if err != nil {
+			return errors.New("gagal menghitung mutasi saldo bulanan")
+		}
+
+		saldoList := make([]models.SaldoBulananAkun, 0, len(mutasiList))
+		for _, mutasi := range mutasiList {
+			saldoList = append(saldoList, models.SaldoBulananAkun{
+				IDKoperasi:   mutasi.IDKoperasi,
+				IDAkun:       mutasi.IDAkun,
+				Bulan:        mutasi.Bulan,
+				MutasiDebit:  mutasi.MutasiDebit,
+				MutasiKredit: mutasi.MutasiKredit,
+			})
+		}
+		if len(saldoList) > 0 {
+			if err := tx.CreateInBatches(saldoList, 500).Error; err != nil {
+				return errors.New("gagal menyimpan saldo bulanan akun")
+			}
+		}
+
+		jumlah = len(saldoList)
+		return nil
+	})
+
+	return jumlah, err
+}
+
+// BangunUlangSaldoBulananKosong membangun snapshot saldo bulanan untuk koperasi yang sudah memiliki
+// jurnal di-post tetapi belum memiliki snapshot sama sekali (data sebelum snapshot diperkenalkan).
+// Dipanggil saat aplikasi dimulai.
+func (s *AkunService) BangunUlangSaldoBulananKosong() error {
+	var idKoperasiList []uuid.UUID
+	err := s.db.Model(&models.Transaksi{}).
+		Distinct().
+		Where(kondisiJurnalPosted).
+		Where("NOT EXISTS (SELECT 1 FROM saldo_bulanan_akun WHERE saldo_bulanan_akun.id_koperasi = transaksi.id_koperasi)").
+		Pluck("transaksi.id_koperasi", &idKoperasiList).Error
+	if err != nil {
+		return errors.New("gagal mencari koperasi tanpa saldo bulanan")
+	}
+
+	for _, idKoperasi := range idKoperasiList {
+		jumlah, err := s.BangunUlangSaldoBulanan(idKoperasi)
+		if err != nil {
+			return err
+		}
+		log.Printf("Saldo bulanan koperasi %s dibangun: %d baris", idKoperasi, jumlah)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaldoBulananAkun(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+	)
+	require.NoError(t, err)
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Saldo Bulanan Koperasi", Alamat: "Test Address"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	modal, _ := akunService.DapatkanAkunByKode(koperasi.ID, "3103")
+
+	transaksiService := NewTransaksiService(db)
+	laporanService := NewLaporanService(db, akunService, nil, nil)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+	kasir := buatPenggunaTest(t, db, koperasi.ID, models.PeranKasir)
+
+	sekarang := time.Now()
+	bulanIni := time.Date(sekarang.Year(), sekarang.Month(), 1, 0, 0, 0, 0, time.UTC)
+	duaBulanLalu := bulanIni.AddDate(0, -2, 0)
+	bulanLalu := bulanIni.AddDate(0, -1, 0)
+
+	jurnal := func(tanggal time.Time, jumlah int64) *BuatTransaksiRequest {
+		return &BuatTransaksiRequest{
+			TanggalTransaksi: tanggal,
+			Deskripsi:        "Setoran modal test",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: kas.ID, JumlahDebit: models.Rupiah(jumlah)},
+				{IDAkun: modal.ID, JumlahKredit: models.Rupiah(jumlah)},
+			},
+		}
+	}
+
+	snapshotKas := func() map[string]models.Uang {
+		var list []models.SaldoBulananAkun
+		require.NoError(t, db.Where("id_akun = ?", kas.ID).Find(&list).Error)
+		hasil := map[string]models.Uang{}
+		for _, saldo := range list {
+			hasil[saldo.Bulan.Format("2006-01")] = saldo.MutasiDebit - saldo.MutasiKredit
+		}
+		return hasil
+	}
+
+	// Jurnal admin langsung di-post dan masuk snapshot bulannya
+	diubah, err := transaksiService.BuatTransaksi(koperasi.ID, admin, jurnal(duaBulanLalu.AddDate(0, 0, 4), 100000))
+	require.NoError(t, err)
+	_, err = transaksiService.BuatTransaksi(koperasi.ID, admin, jurnal(bulanLalu.AddDate(0, 0, 9), 50000))
+	require.NoError(t, err)
+
+	// Jurnal DRAFT baru dihitung setelah di-post
+	draft, err := transaksiService.BuatTransaksi(koperasi.ID, kasir, jurnal(bulanLalu.AddDate(0, 0, 9), 30000))
+	require.NoError(t, err)
+	_, err = transaksiService.PerbaruiTransaksi(draft.ID, koperasi.ID, kasir, jurnal(bulanLalu.AddDate(0, 0, 14), 25000))
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(50000), snapshotKas()[bulanLalu.Format("2006-01")])
+
+	_, err = transaksiService.AjukanTransaksi(draft.ID, koperasi.ID, kasir)
+	require.NoError(t, err)
+	_, err = transaksiService.SetujuiTransaksi(draft.ID, koperasi.ID, admin)
+	require.NoError(t, err)
+	_, err = transaksiService.PostingTransaksi(draft.ID, koperasi.ID, admin)
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(75000), snapshotKas()[bulanLalu.Format("2006-01")])
+
+	// Jurnal yang sudah di-post tidak dapat diubah sehingga snapshotnya tetap
+	_, err = transaksiService.PerbaruiTransaksi(diubah.ID, koperasi.ID, admin, jurnal(bulanLalu.AddDate(0, 0, 1), 120000))
+	assert.ErrorIs(t, err, ErrStatusJurnalTidakSesuai)
+	snapshot := snapshotKas()
+	assert.Equal(t, models.Rupiah(100000), snapshot[duaBulanLalu.Format("2006-01")])
+	assert.Equal(t, models.Rupiah(75000), snapshot[bulanLalu.Format("2006-01")])
+
+	// Pembalikan di bulan berjalan dibaca dari jurnal, bukan dari snapshot
+	_, err = transaksiService.BalikTransaksi(diubah.ID, koperasi.ID, admin, &BalikTransaksiRequest{Alasan: "Salah nominal"})
+	require.NoError(t, err)
+
+	saldoKas, err := akunService.HitungSaldoAkun(kas.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(75000), saldoKas)
+
+	saldoKasBulanLalu, err := akunService.HitungSaldoAkun(kas.ID, bulanIni.AddDate(0, 0, -1).Format("2006-01-02"))
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(175000), saldoKasBulanLalu)
+
+	// Saldo di tengah bulan lalu menjumlahkan snapshot dua bulan lalu dan jurnal bulan lalu sampai tanggal itu
+	saldoTengahBulan, err := akunService.HitungSaldoAkun(kas.ID, bulanLalu.AddDate(0, 0, 9).Format("2006-01-02"))
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(150000), saldoTengahBulan)
+
+	neraca, err := laporanService.GenerateLaporanPosisiKeuangan(koperasi.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(75000), neraca.TotalAset)
+	assert.Equal(t, neraca.TotalAset, neraca.TotalKewajiban+neraca.TotalModal)
+
+	neracaSaldo, err := laporanService.GenerateNeracaSaldo(koperasi.ID, bulanIni.AddDate(0, 0, -1).Format("2006-01-02"))
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(175000), neracaSaldo["totalDebit"])
+	assert.True(t, neracaSaldo["isBalanced"].(bool))
+
+	// Bangun ulang menghasilkan snapshot yang sama dengan yang dipelihara saat posting
+	sebelum := snapshotKas()
+	jumlah, err := akunService.BangunUlangSaldoBulanan(koperasi.ID)
+	require.NoError(t, err)
+	assert.Positive(t, jumlah)
+	assertSnapshotSama(t, sebelum, snapshotKas())
+
+	// Koperasi yang snapshotnya hilang dibangun ulang saat aplikasi dimulai
+	db.Where("id_koperasi = ?", koperasi.ID).Delete(&models.SaldoBulananAkun{})
+	require.NoError(t, akunService.BangunUlangSaldoBulananKosong())
+	assertSnapshotSama(t, sebelum, snapshotKas())
+
+	var jumlahBaris int64
+	db.Model(&models.SaldoBulananAkun{}).Where("id_koperasi = ?", koperasi.ID).Count(&jumlahBaris)
+	assert.Equal(t, int64(jumlah), jumlahBaris)
+}
+
+// assertSnapshotSama membandingkan mutasi bersih per bulan, mengabaikan bulan yang bersih nol
+func assertSnapshotSama(t *testing.T, harapan, aktual map[string]models.Uang) {
+	t.Helper()
+	for bulan, mutasi := range harapan {
+		assert.Equal(t, mutasi, aktual[bulan], "bulan %s", bulan)
+	}
+	for bulan, mutasi := range aktual {
+		assert.Equal(t, harapan[bulan], mutasi, "bulan %s", bulan)
+	}
+}
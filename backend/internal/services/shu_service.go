@@ -0,0 +1,537 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Persentase alokasi SHU default yang umum dipakai dalam AD/ART koperasi
+const (
+	DefaultPersenDanaCadangan   = 40.0
+	DefaultPersenJasaModal      = 20.0
+	DefaultPersenJasaUsaha      = 25.0
+	DefaultPersenDanaPengurus   = 5.0
+	DefaultPersenDanaPendidikan = 5.0
+	DefaultPersenDanaSosial     = 5.0
+)
+
+// SHUService menangani perhitungan dan pembagian Sisa Hasil Usaha
+type SHUService struct {
+	db               *gorm.DB
+	laporanService   *LaporanService
+	transaksiService *TransaksiService
+}
+
+// NewSHUService membuat instance baru SHUService
+func NewSHUService(db *gorm.DB, laporanService *LaporanService, transaksiService *TransaksiService) *SHUService {
+	return &SHUService{
+		db:               db,
+		laporanService:   laporanService,
+		transaksiService: transaksiService,
+	}
+}
+
+// PengaturanSHURequest adalah struktur request untuk mengubah persentase alokasi SHU
+type PengaturanSHURequest struct {
+	PersenDanaCadangan   float64 `json:"persenDanaCadangan"`
+	PersenJasaModal      float64 `json:"persenJasaModal"`
+	PersenJasaUsaha      float64 `json:"persenJasaUsaha"`
+	PersenDanaPengurus   float64 `json:"persenDanaPengurus"`
+	PersenDanaPendidikan float64 `json:"persenDanaPendidikan"`
+	PersenDanaSosial     float64 `json:"persenDanaSosial"`
+}
+
+// TetapkanSHURequest adalah struktur request untuk menetapkan dan memposting SHU
+type TetapkanSHURequest struct {
+	TahunBuku        int       `json:"tahunBuku" binding:"required"`
+	TanggalPenetapan time.Time `json:"tanggalPenetapan" binding:"required"` // Tanggal RAT
+}
+
+// RentangTahunBuku menghitung tanggal awal dan akhir tahun buku.
+// Tahun buku diberi label sesuai tahun kalender saat tahun buku dimulai,
+// misalnya bulanMulai=7 dan tahunBuku=2024 berarti 1 Juli 2024 - 30 Juni 2025.
+func RentangTahunBuku(bulanMulai, tahunBuku int) (time.Time, time.Time) {
+	if bulanMulai < 1 || bulanMulai > 12 {
+		bulanMulai = 1
+	}
+
+	mulai := time.Date(tahunBuku, time.Month(bulanMulai), 1, 0, 0, 0, 0, time.UTC)
+	akhir := mulai.AddDate(1, 0, -1)
+	return mulai, akhir
+}
+
+// DapatkanPengaturanSHU mengambil persentase alokasi SHU koperasi.
+// Jika belum pernah diatur, persentase default dikembalikan tanpa disimpan.
+func (s *SHUService) DapatkanPengaturanSHU(idKoperasi uuid.UUID) (*models.PengaturanSHU, error) {
+	var pengaturan models.PengaturanSHU
+	err := s.db.Where("id_koperasi = ?", idKoperasi).First(&pengaturan).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.PengaturanSHU{
+				IDKoperasi:           idKoperasi,
+				PersenDanaCadangan:   DefaultPersenDanaCadangan,
+				PersenJasaModal:      DefaultPersenJasaModal,
+				PersenJasaUsaha:      DefaultPersenJasaUsaha,
+				PersenDanaPengurus:   DefaultPersenDanaPengurus,
+				PersenDanaPendidikan: DefaultPersenDanaPendidikan,
+				PersenDanaSosial:     DefaultPersenDanaSosial,
+			}, nil
+		}
+		return nil, errors.New("gagal mengambil pengaturan SHU")
+	}
+
+	return &pengaturan, nil
+}
+
+// SimpanPengaturanSHU menyimpan persentase alokasi SHU koperasi
+func (s *SHUService) SimpanPengaturanSHU(idKoperasi uuid.UUID, req *PengaturanSHURequest) (*models.PengaturanSHU, error) {
+	validator := validasi.Baru()
+
+	persentase := map[string]float64{
+		"persen dana cadangan":   req.PersenDanaCadangan,
+		"persen jasa modal":      req.PersenJasaModal,
+		"persen jasa usaha":      req.PersenJasaUsaha,
+		"persen dana pengurus":   req.PersenDanaPengurus,
+		"persen dana pendidikan": req.PersenDanaPendidikan,
+		"persen dana sosial":     req.PersenDanaSosial,
+	}
+	for namaField, nilai := range persentase {
+		if err := validator.Persentase(nilai, namaField); err != nil {
+			return nil, err
+		}
+	}
+
+	pengaturan, err := s.DapatkanPengaturanSHU(idKoperasi)
+	if err != nil {
+		return nil, err
+	}
+
+	pengaturan.PersenDanaCadangan = req.PersenDanaCadangan
+	pengaturan.PersenJasaModal = req.PersenJasaModal
+	pengaturan.PersenJasaUsaha = req.PersenJasaUsaha
+	pengaturan.PersenDanaPengurus = req.PersenDanaPengurus
+	pengaturan.PersenDanaPendidikan = req.PersenDanaPendidikan
+	pengaturan.PersenDanaSosial = req.PersenDanaSosial
+
+	if math.Round(pengaturan.TotalPersen()*100) != 100*100 {
+		return nil, fmt.Errorf("total persentase alokasi SHU harus 100 (saat ini %.2f)", pengaturan.TotalPersen())
+	}
+
+	if err := s.db.Save(pengaturan).Error; err != nil {
+		return nil, errors.New("gagal menyimpan pengaturan SHU")
+	}
+
+	return pengaturan, nil
+}
+
+// HitungSHU menghitung simulasi pembagian SHU untuk satu tahun buku tanpa menyimpan apapun
+func (s *SHUService) HitungSHU(idKoperasi uuid.UUID, tahunBuku int) (*models.SHUResponse, error) {
+	shu, err := s.hitungSHU(idKoperasi, tahunBuku)
+	if err != nil {
+		return nil, err
+	}
+
+	response := shu.ToResponse()
+	response.ID = nil
+	response.TanggalPenetapan = nil
+	return &response, nil
+}
+
+// TetapkanSHU menyimpan hasil perhitungan SHU dan memposting jurnal pembagiannya.
+//
+// Jurnal yang dibuat:
+//   - Debit SHU Tahun Berjalan (3201) sebesar total SHU
+//   - Kredit Cadangan (3202), SHU Bagian Anggota (2102), Dana Pengurus (2103),
+//     Dana Pendidikan (2104) dan Dana Sosial (2105) sesuai alokasi
+func (s *SHUService) TetapkanSHU(idKoperasi, idPengguna uuid.UUID, req *TetapkanSHURequest) (*models.SHUResponse, error) {
+	// Cegah penetapan ganda untuk tahun buku yang sama
+	var jumlah int64
+	s.db.Model(&models.SHU{}).
+		Where("id_koperasi = ? AND tahun_buku = ?", idKoperasi, req.TahunBuku).
+		Count(&jumlah)
+	if jumlah > 0 {
+		return nil, fmt.Errorf("SHU tahun buku %d sudah ditetapkan", req.TahunBuku)
+	}
+
+	shu, err := s.hitungSHU(idKoperasi, req.TahunBuku)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.TanggalPenetapan.After(shu.PeriodeAkhir) {
+		return nil, errors.New("SHU hanya dapat ditetapkan setelah tahun buku berakhir")
+	}
+
+	if shu.TotalSHU <= 0 {
+		return nil, errors.New("tidak ada SHU yang dapat dibagikan (laba bersih tahun buku tidak positif)")
+	}
+
+	shu.TanggalPenetapan = req.TanggalPenetapan
+	shu.DitetapkanOleh = idPengguna
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Step 1: Posting jurnal pembagian SHU
+		jurnalReq, jurnalErr := s.susunJurnalSHU(tx, idKoperasi, shu)
+		if jurnalErr != nil {
+			return jurnalErr
+		}
+
+		transaksi, postErr := s.transaksiService.BuatTransaksiWithTx(tx, idKoperasi, idPengguna, jurnalReq)
+		if postErr != nil {
+			return fmt.Errorf("gagal posting jurnal SHU: %w", postErr)
+		}
+		shu.IDTransaksi = &transaksi.ID
+
+		// Step 2: Simpan header SHU beserta bagian per anggota
+		shuAnggota := shu.SHUAnggota
+		shu.SHUAnggota = nil
+		if createErr := tx.Create(shu).Error; createErr != nil {
+			return errors.New("gagal menyimpan SHU")
+		}
+
+		for i := range shuAnggota {
+			shuAnggota[i].IDSHU = shu.ID
+			anggota := shuAnggota[i].Anggota
+			shuAnggota[i].Anggota = models.Anggota{}
+			if createErr := tx.Create(&shuAnggota[i]).Error; createErr != nil {
+				return errors.New("gagal menyimpan SHU anggota")
+			}
+			shuAnggota[i].Anggota = anggota
+		}
+		shu.SHUAnggota = shuAnggota
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := shu.ToResponse()
+	return &response, nil
+}
+
+// BatalkanSHURequest adalah struktur request untuk membatalkan penetapan SHU
+type BatalkanSHURequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// BatalkanSHU membatalkan penetapan SHU satu tahun buku.
+//
+// Jurnal pembagian SHU dibalik dengan jurnal pembalik tertanggal hari ini yang mencatat alasan
+// pembatalan, lalu header SHU beserta bagian anggotanya dihapus permanen agar tahun buku
+// tersebut dapat ditetapkan ulang.
+func (s *SHUService) BatalkanSHU(idKoperasi, idPengguna uuid.UUID, tahunBuku int, req *BatalkanSHURequest) error {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var shu models.SHU
+		findErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id_koperasi = ? AND tahun_buku = ?", idKoperasi, tahunBuku).
+			First(&shu).Error
+		if findErr != nil {
+			if errors.Is(findErr, gorm.ErrRecordNotFound) {
+				return errors.New("SHU tidak ditemukan")
+			}
+			return errors.New("gagal mengambil SHU")
+		}
+
+		// Step 1: Balik jurnal pembagian SHU dalam transaction yang sama
+		if shu.IDTransaksi != nil {
+			if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *shu.IDTransaksi,
+				time.Now(), req.Alasan, models.TipeTransaksiSHU); balikErr != nil {
+				return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+			}
+		}
+
+		// Step 2: Hapus bagian anggota dan header SHU
+		if deleteErr := tx.Where("id_shu = ?", shu.ID).Delete(&models.SHUAnggota{}).Error; deleteErr != nil {
+			return errors.New("gagal menghapus SHU anggota")
+		}
+		if deleteErr := tx.Unscoped().Delete(&shu).Error; deleteErr != nil {
+			return errors.New("gagal menghapus SHU")
+		}
+
+		return nil
+	})
+}
+
+// DapatkanSHU mengambil SHU yang sudah ditetapkan untuk tahun buku tertentu
+func (s *SHUService) DapatkanSHU(idKoperasi uuid.UUID, tahunBuku int) (*models.SHUResponse, error) {
+	var shu models.SHU
+	err := s.db.Preload("SHUAnggota", func(db *gorm.DB) *gorm.DB {
+		return db.Order("total_shu DESC")
+	}).Preload("SHUAnggota.Anggota").
+		Where("id_koperasi = ? AND tahun_buku = ?", idKoperasi, tahunBuku).
+		First(&shu).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("SHU tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil SHU")
+	}
+
+	response := shu.ToResponse()
+	return &response, nil
+}
+
+// DapatkanSemuaSHU mengambil riwayat penetapan SHU koperasi (tanpa rincian anggota)
+func (s *SHUService) DapatkanSemuaSHU(idKoperasi uuid.UUID) ([]models.SHUResponse, error) {
+	var shuList []models.SHU
+	err := s.db.Where("id_koperasi = ?", idKoperasi).
+		Order("tahun_buku DESC").
+		Find(&shuList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar SHU")
+	}
+
+	responses := make([]models.SHUResponse, len(shuList))
+	for i := range shuList {
+		responses[i] = shuList[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// hitungSHU menghitung alokasi SHU dan bagian setiap anggota untuk satu tahun buku
+func (s *SHUService) hitungSHU(idKoperasi uuid.UUID, tahunBuku int) (*models.SHU, error) {
+	var koperasi models.Koperasi
+	if err := s.db.Where("id = ?", idKoperasi).First(&koperasi).Error; err != nil {
+		return nil, errors.New("koperasi tidak ditemukan")
+	}
+
+	periodeMulai, periodeAkhir := RentangTahunBuku(koperasi.TahunBukuMulai, tahunBuku)
+
+	// Laba bersih tahun buku diambil dari laporan laba rugi
+	labaRugi, err := s.laporanService.GenerateLaporanLabaRugi(idKoperasi,
+		periodeMulai.Format("2006-01-02"), periodeAkhir.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	pengaturan, err := s.DapatkanPengaturanSHU(idKoperasi)
+	if err != nil {
+		return nil, err
+	}
+
+	shu := &models.SHU{
+		IDKoperasi:   idKoperasi,
+		TahunBuku:    tahunBuku,
+		PeriodeMulai: periodeMulai,
+		PeriodeAkhir: periodeAkhir,
+		TotalSHU:     labaRugi.LabaRugiBersih,
+		SHUAnggota:   []models.SHUAnggota{},
+	}
+
+	if shu.TotalSHU <= 0 {
+		return shu, nil
+	}
+
+	// Alokasi per pos dengan pembulatan ke sen agar jumlahnya tepat sama dengan total SHU
+	alokasi := models.BagiProporsional(shu.TotalSHU, []int64{
+		basisPoin(pengaturan.PersenDanaCadangan),
+		basisPoin(pengaturan.PersenJasaModal),
+		basisPoin(pengaturan.PersenJasaUsaha),
+		basisPoin(pengaturan.PersenDanaPengurus),
+		basisPoin(pengaturan.PersenDanaPendidikan),
+		basisPoin(pengaturan.PersenDanaSosial),
+	})
+	shu.DanaCadangan = alokasi[0]
+	shu.JasaModal = alokasi[1]
+	shu.JasaUsaha = alokasi[2]
+	shu.DanaPengurus = alokasi[3]
+	shu.DanaPendidikan = alokasi[4]
+	shu.DanaSosial = alokasi[5]
+
+	shuAnggota, err := s.hitungSHUAnggota(idKoperasi, periodeMulai, periodeAkhir, shu.JasaModal, shu.JasaUsaha)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range shuAnggota {
+		shu.TotalSimpananRataRata += item.RataRataSimpanan
+		shu.TotalBelanjaAnggota += item.TotalBelanja
+	}
+	shu.SHUAnggota = shuAnggota
+
+	return shu, nil
+}
+
+// mutasiSimpanan adalah satu mutasi saldo simpanan anggota
+type mutasiSimpanan struct {
+	IDAnggota        uuid.UUID
+	TanggalTransaksi time.Time
+	Jumlah           models.Uang
+}
+
+// hitungSHUAnggota membagi jasa modal berdasarkan rata-rata saldo simpanan dan
+// jasa usaha berdasarkan total belanja anggota selama tahun buku
+func (s *SHUService) hitungSHUAnggota(idKoperasi uuid.UUID, periodeMulai, periodeAkhir time.Time, jasaModal, jasaUsaha models.Uang) ([]models.SHUAnggota, error) {
+	// Anggota yang sudah keluar tidak lagi berhak atas SHU
+	var anggotaList []models.Anggota
+	err := s.db.Where("id_koperasi = ? AND status <> ?", idKoperasi, models.StatusKeluar).
+		Order("nomor_anggota ASC").
+		Find(&anggotaList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar anggota")
+	}
+
+	// Mutasi simpanan sampai akhir tahun buku
+	var mutasiList []mutasiSimpanan
+	err = s.db.Model(&models.Simpanan{}).
+		Select("id_anggota, tanggal_transaksi, " + ekspresiJumlahBersihSimpanan + " as jumlah").
+		Where("id_koperasi = ? AND tanggal_transaksi <= ?", idKoperasi, periodeAkhir.Format("2006-01-02")).
+		Scan(&mutasiList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil data simpanan")
+	}
+
+	mutasiPerAnggota := make(map[uuid.UUID][]mutasiSimpanan)
+	for _, mutasi := range mutasiList {
+		mutasiPerAnggota[mutasi.IDAnggota] = append(mutasiPerAnggota[mutasi.IDAnggota], mutasi)
+	}
+
+	// Volume belanja anggota selama tahun buku
+	type belanjaAnggota struct {
+		IDAnggota uuid.UUID
+		Total     models.Uang
+	}
+	var belanjaList []belanjaAnggota
+	err = s.db.Model(&models.Penjualan{}).
+		Select("id_anggota, COALESCE(SUM(total_belanja), 0) as total").
+		Where("id_koperasi = ? AND id_anggota IS NOT NULL AND dibatalkan = ?", idKoperasi, false).
+		Where("tanggal_penjualan >= ? AND tanggal_penjualan < ?",
+			periodeMulai.Format("2006-01-02"), periodeAkhir.AddDate(0, 0, 1).Format("2006-01-02")).
+		Group("id_anggota").
+		Scan(&belanjaList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil data belanja anggota")
+	}
+
+	belanjaPerAnggota := make(map[uuid.UUID]models.Uang)
+	for _, belanja := range belanjaList {
+		belanjaPerAnggota[belanja.IDAnggota] = belanja.Total
+	}
+
+	// Hitung basis pembagian per anggota
+	hasil := make([]models.SHUAnggota, 0, len(anggotaList))
+	bobotModal := make([]int64, 0, len(anggotaList))
+	bobotUsaha := make([]int64, 0, len(anggotaList))
+	for _, anggota := range anggotaList {
+		rataRata := hitungRataRataSaldoBulanan(mutasiPerAnggota[anggota.ID], periodeMulai, periodeAkhir)
+		belanja := belanjaPerAnggota[anggota.ID]
+		if rataRata <= 0 && belanja <= 0 {
+			continue
+		}
+
+		hasil = append(hasil, models.SHUAnggota{
+			IDKoperasi:       idKoperasi,
+			IDAnggota:        anggota.ID,
+			RataRataSimpanan: rataRata,
+			TotalBelanja:     belanja,
+			Anggota:          anggota,
+		})
+		bobotModal = append(bobotModal, int64(max(rataRata, 0)))
+		bobotUsaha = append(bobotUsaha, int64(belanja))
+	}
+
+	bagianModal := models.BagiProporsional(jasaModal, bobotModal)
+	bagianUsaha := models.BagiProporsional(jasaUsaha, bobotUsaha)
+	for i := range hasil {
+		hasil[i].JasaModal = bagianModal[i]
+		hasil[i].JasaUsaha = bagianUsaha[i]
+		hasil[i].TotalSHU = bagianModal[i] + bagianUsaha[i]
+	}
+
+	return hasil, nil
+}
+
+// susunJurnalSHU menyusun request jurnal pembagian SHU
+func (s *SHUService) susunJurnalSHU(tx *gorm.DB, idKoperasi uuid.UUID, shu *models.SHU) (*BuatTransaksiRequest, error) {
+	akunPosting, err := akunPostingWithTx(tx, idKoperasi, models.PeristiwaPembagianSHU)
+	if err != nil {
+		return nil, err
+	}
+
+	keterangan := fmt.Sprintf("Pembagian SHU tahun buku %d", shu.TahunBuku)
+	baris := []BuatBarisTransaksiRequest{
+		{IDAkun: akunPosting[models.PeranAkunSHUTahunBerjalan].ID, JumlahDebit: shu.TotalSHU, Keterangan: keterangan},
+	}
+
+	kredit := []struct {
+		peran      models.PeranAkun
+		jumlah     models.Uang
+		keterangan string
+	}{
+		{models.PeranAkunDanaCadangan, shu.DanaCadangan, "Dana cadangan"},
+		{models.PeranAkunSHUBagianAnggota, shu.JasaModal + shu.JasaUsaha, "Jasa modal dan jasa usaha anggota"},
+		{models.PeranAkunDanaPengurus, shu.DanaPengurus, "Dana pengurus dan karyawan"},
+		{models.PeranAkunDanaPendidikan, shu.DanaPendidikan, "Dana pendidikan"},
+		{models.PeranAkunDanaSosial, shu.DanaSosial, "Dana sosial"},
+	}
+	for _, item := range kredit {
+		if item.jumlah <= 0 {
+			continue
+		}
+		baris = append(baris, BuatBarisTransaksiRequest{
+			IDAkun:       akunPosting[item.peran].ID,
+			JumlahKredit: item.jumlah,
+			Keterangan:   item.keterangan,
+		})
+	}
+
+	return &BuatTransaksiRequest{
+		TanggalTransaksi: shu.TanggalPenetapan,
+		Deskripsi:        keterangan,
+		NomorReferensi:   fmt.Sprintf("SHU-%d", shu.TahunBuku),
+		TipeTransaksi:    models.TipeTransaksiSHU,
+		BarisTransaksi:   baris,
+	}, nil
+}
+
+// hitungRataRataSaldoBulanan menghitung rata-rata saldo akhir bulan selama periode.
+// Mutasi sebelum periode dihitung sebagai saldo awal.
+func hitungRataRataSaldoBulanan(mutasiList []mutasiSimpanan, periodeMulai, periodeAkhir time.Time) models.Uang {
+	var totalSaldo models.Uang
+	jumlahBulan := 0
+
+	for awalBulan := periodeMulai; !awalBulan.After(periodeAkhir); awalBulan = awalBulan.AddDate(0, 1, 0) {
+		akhirBulan := awalBulan.AddDate(0, 1, -1)
+		if akhirBulan.After(periodeAkhir) {
+			akhirBulan = periodeAkhir
+		}
+
+		var saldo models.Uang
+		for _, mutasi := range mutasiList {
+			if !mutasi.TanggalTransaksi.After(akhirBulan) {
+				saldo += mutasi.Jumlah
+			}
+		}
+
+		totalSaldo += saldo
+		jumlahBulan++
+	}
+
+	if jumlahBulan == 0 {
+		return 0
+	}
+
+	return totalSaldo.KaliPecahan(1, int64(jumlahBulan))
+}
+
+// basisPoin mengubah persentase dengan 2 angka desimal menjadi basis poin (1% = 100)
+func basisPoin(persen float64) int64 {
+	return int64(math.Round(persen * 100))
+}
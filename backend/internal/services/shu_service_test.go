@@ -0,0 +1,209 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupSHUTestDB creates a test database for SHU service
+func setupSHUTestDB(t *testing.T) *gorm.DB {
+	dsn := "host=localhost user=postgres password=postgres dbname=koperasi_erp_test port=5432 sslmode=disable TimeZone=Asia/Jakarta"
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Skipf("Skipping test: cannot connect to test database: %v", err)
+		return nil
+	}
+
+	err = db.AutoMigrate(
+		&models.Koperasi{},
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+		&models.Anggota{},
+		&models.Simpanan{},
+		&models.Produk{},
+		&models.Penjualan{},
+		&models.ItemPenjualan{},
+		&models.PengaturanSHU{},
+		&models.SHU{},
+		&models.SHUAnggota{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	db.Exec("TRUNCATE TABLE shu_anggota CASCADE")
+	db.Exec("TRUNCATE TABLE shu CASCADE")
+	db.Exec("TRUNCATE TABLE pengaturan_shu CASCADE")
+
+	return db
+}
+
+func TestRentangTahunBuku(t *testing.T) {
+	t.Run("tahun buku kalender", func(t *testing.T) {
+		mulai, akhir := RentangTahunBuku(1, 2024)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), mulai)
+		assert.Equal(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), akhir)
+	})
+
+	t.Run("tahun buku mulai Juli", func(t *testing.T) {
+		mulai, akhir := RentangTahunBuku(7, 2024)
+		assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), mulai)
+		assert.Equal(t, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), akhir)
+	})
+
+	t.Run("bulan tidak valid dianggap Januari", func(t *testing.T) {
+		mulai, _ := RentangTahunBuku(0, 2024)
+		assert.Equal(t, time.January, mulai.Month())
+	})
+}
+
+func TestHitungRataRataSaldoBulanan(t *testing.T) {
+	mulai, akhir := RentangTahunBuku(1, 2024)
+
+	mutasi := []mutasiSimpanan{
+		// Saldo awal sebelum tahun buku dihitung penuh setiap bulan
+		{TanggalTransaksi: time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC), Jumlah: models.Rupiah(1200000)},
+		// Setoran di bulan Juli menambah saldo untuk 6 bulan terakhir
+		{TanggalTransaksi: time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), Jumlah: models.Rupiah(600000)},
+		// Setoran setelah tahun buku tidak dihitung
+		{TanggalTransaksi: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Jumlah: models.Rupiah(999999)},
+	}
+
+	rataRata := hitungRataRataSaldoBulanan(mutasi, mulai, akhir)
+	assert.Equal(t, models.Rupiah(1500000), rataRata)
+
+	assert.Equal(t, models.Uang(0), hitungRataRataSaldoBulanan(nil, mulai, akhir))
+}
+
+func TestSimpanPengaturanSHU_TotalHarus100(t *testing.T) {
+	db := setupSHUTestDB(t)
+	if db == nil {
+		return
+	}
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test SHU", Email: "shu@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	service := NewSHUService(db, nil, nil)
+
+	// Default dikembalikan bila belum diatur
+	pengaturan, err := service.DapatkanPengaturanSHU(koperasi.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(100), pengaturan.TotalPersen())
+
+	_, err = service.SimpanPengaturanSHU(koperasi.ID, &PengaturanSHURequest{
+		PersenDanaCadangan: 50, PersenJasaModal: 30, PersenJasaUsaha: 30,
+	})
+	assert.Error(t, err)
+
+	pengaturan, err = service.SimpanPengaturanSHU(koperasi.ID, &PengaturanSHURequest{
+		PersenDanaCadangan: 30, PersenJasaModal: 25, PersenJasaUsaha: 30,
+		PersenDanaPengurus: 5, PersenDanaPendidikan: 5, PersenDanaSosial: 5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(30), pengaturan.PersenDanaCadangan)
+}
+
+func TestTetapkanSHU_PostingJurnalBalanced(t *testing.T) {
+	db := setupSHUTestDB(t)
+	if db == nil {
+		return
+	}
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test SHU", Email: "shu@test.com", NoTelepon: "081234567890", TahunBukuMulai: 1}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	transaksiService := NewTransaksiService(db)
+	simpananService := NewSimpananService(db, transaksiService)
+	laporanService := NewLaporanService(db, akunService, simpananService, nil)
+	service := NewSHUService(db, laporanService, transaksiService)
+
+	pengguna := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	pendapatan, _ := akunService.DapatkanAkunByKode(koperasi.ID, "4200")
+
+	// Dua anggota dengan simpanan berbeda
+	anggotaA := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "SHU-001", NamaLengkap: "Anggota A", TanggalBergabung: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	anggotaB := &models.Anggota{IDKoperasi: koperasi.ID, NomorAnggota: "SHU-002", NamaLengkap: "Anggota B", TanggalBergabung: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	db.Create(anggotaA)
+	db.Create(anggotaB)
+	db.Create(&models.Simpanan{IDKoperasi: koperasi.ID, IDAnggota: anggotaA.ID, TipeSimpanan: models.SimpananPokok, TanggalTransaksi: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), JumlahSetoran: models.Rupiah(300000), NomorReferensi: "SMP-SHU-1"})
+	db.Create(&models.Simpanan{IDKoperasi: koperasi.ID, IDAnggota: anggotaB.ID, TipeSimpanan: models.SimpananPokok, TanggalTransaksi: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), JumlahSetoran: models.Rupiah(100000), NomorReferensi: "SMP-SHU-2"})
+
+	// Pendapatan tahun buku lalu sebagai laba bersih
+	tahunLalu := time.Now().Year() - 1
+	_, err := transaksiService.BuatTransaksi(koperasi.ID, pengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: time.Date(tahunLalu, 12, 31, 0, 0, 0, 0, time.UTC),
+		Deskripsi:        "Pendapatan lain-lain",
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: kas.ID, JumlahDebit: models.Rupiah(1000000)},
+			{IDAkun: pendapatan.ID, JumlahKredit: models.Rupiah(1000000)},
+		},
+	})
+	if err != nil {
+		t.Skipf("Skipping test: tanggal transaksi di luar rentang validasi: %v", err)
+	}
+
+	shu, err := service.TetapkanSHU(koperasi.ID, pengguna, &TetapkanSHURequest{
+		TahunBuku:        tahunLalu,
+		TanggalPenetapan: time.Now(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(1000000), shu.TotalSHU)
+	assert.Equal(t, models.Rupiah(400000), shu.DanaCadangan)
+	require.Len(t, shu.SHUAnggota, 2)
+
+	// Jasa modal dibagi 3:1 sesuai rata-rata simpanan
+	assert.Equal(t, models.Rupiah(150000), shu.SHUAnggota[0].JasaModal)
+	assert.Equal(t, models.Rupiah(50000), shu.SHUAnggota[1].JasaModal)
+
+	// Jurnal pembagian harus balance dan mendebit SHU Tahun Berjalan
+	require.NotNil(t, shu.IDTransaksi)
+	var transaksi models.Transaksi
+	require.NoError(t, db.Preload("BarisTransaksi").First(&transaksi, "id = ?", *shu.IDTransaksi).Error)
+	assert.Equal(t, models.TipeTransaksiSHU, transaksi.TipeTransaksi)
+	assert.Equal(t, transaksi.TotalDebit, transaksi.TotalKredit)
+	assert.Equal(t, models.Rupiah(1000000), transaksi.TotalDebit)
+
+	// Penetapan ganda ditolak
+	_, err = service.TetapkanSHU(koperasi.ID, pengguna, &TetapkanSHURequest{
+		TahunBuku:        tahunLalu,
+		TanggalPenetapan: time.Now(),
+	})
+	assert.Error(t, err)
+
+	// Pembatalan membalik jurnal dan membuka tahun buku untuk ditetapkan ulang
+	require.NoError(t, service.BatalkanSHU(koperasi.ID, pengguna, tahunLalu, &BatalkanSHURequest{Alasan: "Salah hitung alokasi"}))
+	require.NoError(t, db.First(&transaksi, "id = ?", *shu.IDTransaksi).Error)
+	assert.True(t, transaksi.Dibalik)
+	_, err = service.DapatkanSHU(koperasi.ID, tahunLalu)
+	assert.Error(t, err)
+
+	shu, err = service.TetapkanSHU(koperasi.ID, pengguna, &TetapkanSHURequest{
+		TahunBuku:        tahunLalu,
+		TanggalPenetapan: time.Now(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.Rupiah(1000000), shu.TotalSHU)
+}
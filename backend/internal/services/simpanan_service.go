@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/pkg/validasi"
 	"errors"
@@ -12,6 +13,10 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// ekspresiJumlahBersihSimpanan adalah ekspresi SQL nominal simpanan bertanda:
+// setoran bernilai positif, penarikan bernilai negatif, dan simpanan yang dibatalkan bernilai nol
+const ekspresiJumlahBersihSimpanan = "CASE WHEN dibatalkan THEN 0 WHEN jenis_transaksi = 'PENARIKAN' THEN -jumlah_setoran ELSE jumlah_setoran END"
+
 // SimpananService menangani logika bisnis simpanan anggota
 type SimpananService struct {
 	db               *gorm.DB
@@ -26,13 +31,23 @@ func NewSimpananService(db *gorm.DB, transaksiService *TransaksiService) *Simpan
 	}
 }
 
+// DenganKonteks mengembalikan salinan service yang menjalankan query dengan ctx,
+// sehingga log audit mencatat pengguna, alamat IP dan request ID dari request tersebut
+func (s *SimpananService) DenganKonteks(ctx context.Context) *SimpananService {
+	salinan := *s
+	salinan.db = s.db.WithContext(ctx)
+	salinan.transaksiService = s.transaksiService.DenganKonteks(ctx)
+	return &salinan
+}
+
 // CatatSetoranRequest adalah struktur request untuk catat setoran
 type CatatSetoranRequest struct {
 	IDAnggota        uuid.UUID           `json:"idAnggota" binding:"required"`
 	TipeSimpanan     models.TipeSimpanan `json:"tipeSimpanan" binding:"required"`
 	TanggalTransaksi time.Time           `json:"tanggalTransaksi" binding:"required"`
-	JumlahSetoran    float64             `json:"jumlahSetoran" binding:"required,gt=0"`
+	JumlahSetoran    models.Uang         `json:"jumlahSetoran" binding:"required,gt=0"`
 	Keterangan       string              `json:"keterangan"`
+	IDUnitUsaha      *uuid.UUID          `json:"idUnitUsaha"` // Opsional, unit usaha pencatat simpanan
 }
 
 // CatatSetoran mencatat setoran simpanan anggota
@@ -41,7 +56,7 @@ func (s *SimpananService) CatatSetoran(idKoperasi, idPengguna uuid.UUID, req *Ca
 	validator := validasi.Baru()
 
 	// Validasi business logic
-	if err := validator.Jumlah(req.JumlahSetoran, "jumlah setoran"); err != nil {
+	if err := validator.Jumlah(req.JumlahSetoran.Float64(), "jumlah setoran"); err != nil {
 		return nil, err
 	}
 
@@ -61,11 +76,15 @@ func (s *SimpananService) CatatSetoran(idKoperasi, idPengguna uuid.UUID, req *Ca
 		return nil, errors.New("anggota tidak ditemukan atau tidak aktif")
 	}
 
+	if err := validasiUnitUsahaAktifWithTx(s.db, idKoperasi, req.IDUnitUsaha); err != nil {
+		return nil, err
+	}
+
 	// Validasi Simpanan Pokok hanya boleh dibayar sekali (UU No. 25 Tahun 1992)
 	if req.TipeSimpanan == models.SimpananPokok {
 		var jumlahSimpananPokok int64
 		s.db.Model(&models.Simpanan{}).
-			Where("id_anggota = ? AND tipe_simpanan = ?", req.IDAnggota, models.SimpananPokok).
+			Where("id_anggota = ? AND tipe_simpanan = ? AND jenis_transaksi = ? AND dibatalkan = ?", req.IDAnggota, models.SimpananPokok, models.TransaksiSetoran, false).
 			Count(&jumlahSimpananPokok)
 
 		if jumlahSimpananPokok > 0 {
@@ -84,10 +103,12 @@ func (s *SimpananService) CatatSetoran(idKoperasi, idPengguna uuid.UUID, req *Ca
 		IDKoperasi:       idKoperasi,
 		IDAnggota:        req.IDAnggota,
 		TipeSimpanan:     req.TipeSimpanan,
+		JenisTransaksi:   models.TransaksiSetoran,
 		TanggalTransaksi: req.TanggalTransaksi,
 		JumlahSetoran:    req.JumlahSetoran,
 		Keterangan:       req.Keterangan,
 		NomorReferensi:   nomorReferensi,
+		IDUnitUsaha:      req.IDUnitUsaha,
 		DibuatOleh:       idPengguna,
 	}
 
@@ -118,6 +139,261 @@ func (s *SimpananService) CatatSetoran(idKoperasi, idPengguna uuid.UUID, req *Ca
 	return &response, nil
 }
 
+// CatatPenarikanRequest adalah struktur request untuk catat penarikan
+type CatatPenarikanRequest struct {
+	IDAnggota        uuid.UUID           `json:"idAnggota" binding:"required"`
+	TipeSimpanan     models.TipeSimpanan `json:"tipeSimpanan" binding:"required"`
+	TanggalTransaksi time.Time           `json:"tanggalTransaksi" binding:"required"`
+	JumlahPenarikan  models.Uang         `json:"jumlahPenarikan" binding:"required,gt=0"`
+	Keterangan       string              `json:"keterangan"`
+	IDUnitUsaha      *uuid.UUID          `json:"idUnitUsaha"` // Opsional, unit usaha pencatat simpanan
+}
+
+// CatatPenarikan mencatat penarikan simpanan anggota.
+//
+// Simpanan sukarela dapat ditarik kapan saja selama saldonya mencukupi. Simpanan pokok
+// dan wajib hanya dapat ditarik jika anggota sudah keluar (UU No. 25 Tahun 1992).
+// Baris anggota dikunci selama pengecekan saldo sehingga penarikan bersamaan tidak
+// dapat membuat saldo menjadi negatif.
+func (s *SimpananService) CatatPenarikan(idKoperasi, idPengguna uuid.UUID, req *CatatPenarikanRequest) (*models.SimpananResponse, error) {
+	// Initialize validator
+	validator := validasi.Baru()
+
+	// Validasi business logic
+	if err := validator.Jumlah(req.JumlahPenarikan.Float64(), "jumlah penarikan"); err != nil {
+		return nil, err
+	}
+
+	if err := validator.TanggalTransaksi(req.TanggalTransaksi); err != nil {
+		return nil, err
+	}
+
+	if err := validator.TeksOpsional(req.Keterangan, "keterangan", 500); err != nil {
+		return nil, err
+	}
+
+	if err := validator.Enum(string(req.TipeSimpanan), "tipe simpanan",
+		[]string{string(models.SimpananPokok), string(models.SimpananWajib), string(models.SimpananSukarela)}); err != nil {
+		return nil, err
+	}
+
+	if err := validasiUnitUsahaAktifWithTx(s.db, idKoperasi, req.IDUnitUsaha); err != nil {
+		return nil, err
+	}
+
+	// Generate nomor referensi
+	nomorReferensi, err := s.GenerateNomorReferensi(idKoperasi, req.TanggalTransaksi)
+	if err != nil {
+		return nil, err
+	}
+
+	simpanan := &models.Simpanan{
+		IDKoperasi:       idKoperasi,
+		IDAnggota:        req.IDAnggota,
+		TipeSimpanan:     req.TipeSimpanan,
+		JenisTransaksi:   models.TransaksiPenarikan,
+		TanggalTransaksi: req.TanggalTransaksi,
+		JumlahSetoran:    req.JumlahPenarikan,
+		Keterangan:       req.Keterangan,
+		NomorReferensi:   nomorReferensi,
+		IDUnitUsaha:      req.IDUnitUsaha,
+		DibuatOleh:       idPengguna,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Step 1: Kunci baris anggota agar pengecekan saldo dan penarikan bersifat serial
+		var anggota models.Anggota
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", req.IDAnggota, idKoperasi).
+			First(&anggota).Error
+		if lockErr != nil {
+			return errors.New("anggota tidak ditemukan")
+		}
+
+		// Step 2: Simpanan pokok dan wajib hanya dapat ditarik saat anggota keluar
+		if req.TipeSimpanan != models.SimpananSukarela && anggota.Status != models.StatusKeluar {
+			return errors.New("simpanan pokok dan wajib hanya dapat ditarik jika anggota sudah keluar")
+		}
+
+		// Step 3: Pastikan saldo per tanggal penarikan dan saldo setelahnya mencukupi
+		saldo, tanggalNegatif, saldoErr := cekSaldoSimpananWithTx(tx, req.IDAnggota, req.TipeSimpanan,
+			req.TanggalTransaksi, -req.JumlahPenarikan)
+		if saldoErr != nil {
+			return errors.New("gagal menghitung saldo simpanan")
+		}
+
+		if req.JumlahPenarikan > saldo {
+			return fmt.Errorf("saldo simpanan %s per %s tidak mencukupi (saldo: %s)",
+				req.TipeSimpanan, req.TanggalTransaksi.Format("2006-01-02"), saldo)
+		}
+		if tanggalNegatif != nil {
+			return fmt.Errorf("penarikan membuat saldo simpanan %s negatif pada %s",
+				req.TipeSimpanan, tanggalNegatif.Format("2006-01-02"))
+		}
+
+		// Step 4: Simpan record penarikan
+		if createErr := tx.Create(simpanan).Error; createErr != nil {
+			return errors.New("gagal mencatat penarikan simpanan")
+		}
+
+		// Step 5: Posting otomatis jurnal pembalik dalam transaction yang sama
+		if postErr := s.transaksiService.PostingOtomatisSimpananWithTx(tx, idKoperasi, idPengguna, simpanan.ID); postErr != nil {
+			return fmt.Errorf("gagal posting ke jurnal: %w", postErr)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload dengan relasi
+	s.db.Preload("Anggota").First(simpanan, simpanan.ID)
+
+	response := simpanan.ToResponse()
+	return &response, nil
+}
+
+// cekSaldoSimpananWithTx menghitung saldo simpanan anggota per tanggal (termasuk transaksi pada
+// tanggal itu) lalu memeriksa apakah perubahan saldo pada tanggal tersebut membuat saldo berjalan
+// setelah transaksi simpanan berikutnya menjadi negatif. Tanggal pertama saldo negatif dikembalikan,
+// atau nil jika saldo berjalan tetap cukup. Pemanggil memeriksa saldo per tanggal sendiri.
+func cekSaldoSimpananWithTx(tx *gorm.DB, idAnggota uuid.UUID, tipe models.TipeSimpanan, tanggal time.Time, perubahan models.Uang) (models.Uang, *time.Time, error) {
+	var saldo models.Uang
+	if err := tx.Model(&models.Simpanan{}).
+		Select("COALESCE(SUM("+ekspresiJumlahBersihSimpanan+"), 0)").
+		Where("id_anggota = ? AND tipe_simpanan = ? AND tanggal_transaksi <= ?", idAnggota, tipe, tanggal).
+		Scan(&saldo).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var berikutnya []models.Simpanan
+	if err := tx.Select("tanggal_transaksi, jenis_transaksi, jumlah_setoran, dibatalkan").
+		Where("id_anggota = ? AND tipe_simpanan = ? AND tanggal_transaksi > ? AND dibatalkan = ?", idAnggota, tipe, tanggal, false).
+		Order("tanggal_transaksi ASC, tanggal_dibuat ASC").
+		Find(&berikutnya).Error; err != nil {
+		return 0, nil, err
+	}
+
+	berjalan := saldo + perubahan
+	for _, simpanan := range berikutnya {
+		berjalan += simpanan.JumlahBersih()
+		if berjalan < 0 {
+			tanggalNegatif := simpanan.TanggalTransaksi
+			return saldo, &tanggalNegatif, nil
+		}
+	}
+
+	return saldo, nil, nil
+}
+
+// BatalkanSimpananRequest adalah struktur request untuk membatalkan transaksi simpanan
+type BatalkanSimpananRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// BatalkanSimpanan membatalkan (void) setoran atau penarikan simpanan.
+//
+// Simpanan tidak dihapus: jurnalnya dibalik dengan jurnal pembalik tertanggal hari ini dan
+// simpanan ditandai dibatalkan sehingga tidak lagi dihitung dalam saldo. Setoran hanya dapat
+// dibatalkan jika saldo simpanan sejenis tidak menjadi negatif. Baris anggota dikunci seperti
+// pada CatatPenarikan.
+func (s *SimpananService) BatalkanSimpanan(idKoperasi, idPengguna, id uuid.UUID, req *BatalkanSimpananRequest) (*models.SimpananResponse, error) {
+	validator := validasi.Baru()
+
+	if err := validator.TeksWajib(req.Alasan, "alasan pembatalan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	var simpanan models.Simpanan
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if findErr := tx.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&simpanan).Error; findErr != nil {
+			if errors.Is(findErr, gorm.ErrRecordNotFound) {
+				return errors.New("simpanan tidak ditemukan")
+			}
+			return errors.New("gagal mengambil simpanan")
+		}
+
+		// Step 1: Kunci baris anggota agar pengecekan saldo dan pembatalan bersifat serial
+		var anggota models.Anggota
+		lockErr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", simpanan.IDAnggota, idKoperasi).
+			First(&anggota).Error
+		if lockErr != nil {
+			return errors.New("anggota tidak ditemukan")
+		}
+
+		// Baca ulang setelah kunci diperoleh untuk mencegah pembatalan ganda
+		if findErr := tx.Where("id = ?", simpanan.ID).First(&simpanan).Error; findErr != nil {
+			return errors.New("gagal mengambil simpanan")
+		}
+		if simpanan.Dibatalkan {
+			return errors.New("simpanan sudah dibatalkan")
+		}
+
+		// Saldo awal migrasi dibatalkan sekaligus bersama jurnal pembukaannya
+		if simpanan.IDTransaksi != nil {
+			var jumlahSaldoAwal int64
+			if cekErr := tx.Model(&models.Transaksi{}).
+				Where("id = ? AND tipe_transaksi = ?", *simpanan.IDTransaksi, models.TipeTransaksiSaldoAwal).
+				Count(&jumlahSaldoAwal).Error; cekErr != nil {
+				return errors.New("gagal memeriksa jurnal simpanan")
+			}
+			if jumlahSaldoAwal > 0 {
+				return errors.New("simpanan saldo awal hanya dapat dibatalkan melalui pembatalan saldo awal")
+			}
+		}
+
+		// Step 2: Pembatalan setoran tidak boleh membuat saldo negatif, pada tanggal setoran maupun setelahnya
+		if simpanan.JenisTransaksi == models.TransaksiSetoran {
+			saldo, tanggalNegatif, saldoErr := cekSaldoSimpananWithTx(tx, simpanan.IDAnggota, simpanan.TipeSimpanan,
+				simpanan.TanggalTransaksi, -simpanan.JumlahSetoran)
+			if saldoErr != nil {
+				return errors.New("gagal menghitung saldo simpanan")
+			}
+
+			if simpanan.JumlahSetoran > saldo {
+				return fmt.Errorf("setoran tidak dapat dibatalkan karena saldo simpanan %s tinggal %s", simpanan.TipeSimpanan, saldo)
+			}
+			if tanggalNegatif != nil {
+				return fmt.Errorf("setoran tidak dapat dibatalkan karena saldo simpanan %s menjadi negatif pada %s",
+					simpanan.TipeSimpanan, tanggalNegatif.Format("2006-01-02"))
+			}
+		}
+
+		// Step 3: Balik jurnal simpanan dalam transaction yang sama
+		sekarang := time.Now()
+		if simpanan.IDTransaksi != nil {
+			if _, balikErr := s.transaksiService.balikJurnalWithTx(tx, idKoperasi, idPengguna, *simpanan.IDTransaksi,
+				sekarang, req.Alasan, models.TipeTransaksiSimpanan); balikErr != nil {
+				return fmt.Errorf("gagal membalik jurnal: %w", balikErr)
+			}
+		}
+
+		// Step 4: Tandai simpanan dibatalkan
+		simpanan.Dibatalkan = true
+		simpanan.TanggalDibatalkan = &sekarang
+		simpanan.DibatalkanOleh = &idPengguna
+		simpanan.AlasanPembatalan = req.Alasan
+		if saveErr := tx.Save(&simpanan).Error; saveErr != nil {
+			return errors.New("gagal membatalkan simpanan")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload dengan relasi
+	s.db.Preload("Anggota").First(&simpanan, simpanan.ID)
+
+	response := simpanan.ToResponse()
+	return &response, nil
+}
+
 // GenerateNomorReferensi menghasilkan nomor referensi setoran
 // Format: SMP-YYYYMMDD-NNNN
 // Uses row-level locking to prevent race conditions in concurrent requests
@@ -206,7 +482,7 @@ func (s *SimpananService) DapatkanSemuaTransaksiSimpanan(idKoperasi uuid.UUID, t
 	return responses, total, nil
 }
 
-// DapatkanSaldoAnggota mengambil saldo simpanan per anggota
+// DapatkanSaldoAnggota mengambil saldo bersih simpanan per anggota (setoran dikurangi penarikan)
 func (s *SimpananService) DapatkanSaldoAnggota(idAnggota uuid.UUID) (*models.SaldoSimpananAnggota, error) {
 	// Validasi anggota exists
 	var anggota models.Anggota
@@ -218,12 +494,12 @@ func (s *SimpananService) DapatkanSaldoAnggota(idAnggota uuid.UUID) (*models.Sal
 	// Hitung saldo per tipe simpanan
 	type SaldoByTipe struct {
 		TipeSimpanan models.TipeSimpanan
-		Total        float64
+		Total        models.Uang
 	}
 
 	var saldoList []SaldoByTipe
 	err = s.db.Model(&models.Simpanan{}).
-		Select("tipe_simpanan, COALESCE(SUM(jumlah_setoran), 0) as total").
+		Select("tipe_simpanan, COALESCE(SUM(" + ekspresiJumlahBersihSimpanan + "), 0) as total").
 		Where("id_anggota = ?", idAnggota).
 		Group("tipe_simpanan").
 		Scan(&saldoList).Error
@@ -259,12 +535,12 @@ func (s *SimpananService) DapatkanSaldoAnggota(idAnggota uuid.UUID) (*models.Sal
 func (s *SimpananService) DapatkanRingkasanSimpanan(idKoperasi uuid.UUID) (*models.RingkasanSimpanan, error) {
 	type SaldoByTipe struct {
 		TipeSimpanan models.TipeSimpanan
-		Total        float64
+		Total        models.Uang
 	}
 
 	var saldoList []SaldoByTipe
 	err := s.db.Model(&models.Simpanan{}).
-		Select("tipe_simpanan, COALESCE(SUM(jumlah_setoran), 0) as total").
+		Select("tipe_simpanan, COALESCE(SUM(" + ekspresiJumlahBersihSimpanan + "), 0) as total").
 		Where("id_koperasi = ?", idKoperasi).
 		Group("tipe_simpanan").
 		Scan(&saldoList).Error
@@ -328,10 +604,10 @@ func (s *SimpananService) DapatkanLaporanSaldoAnggota(idKoperasi uuid.UUID) ([]m
 }
 
 // HitungTotalSimpananByTipe menghitung total simpanan berdasarkan tipe
-func (s *SimpananService) HitungTotalSimpananByTipe(idKoperasi uuid.UUID, tipeSimpanan models.TipeSimpanan) (float64, error) {
-	var total float64
+func (s *SimpananService) HitungTotalSimpananByTipe(idKoperasi uuid.UUID, tipeSimpanan models.TipeSimpanan) (models.Uang, error) {
+	var total models.Uang
 	err := s.db.Model(&models.Simpanan{}).
-		Select("COALESCE(SUM(jumlah_setoran), 0)").
+		Select("COALESCE(SUM(" + ekspresiJumlahBersihSimpanan + "), 0)").
 		Where("id_koperasi = ? AND tipe_simpanan = ?", idKoperasi, tipeSimpanan).
 		Scan(&total).Error
 
@@ -81,7 +81,7 @@ func seedMembers(db *gorm.DB, idKoperasi uuid.UUID, count int) {
 			IDAnggota:        member.ID,
 			TipeSimpanan:     models.SimpananPokok,
 			TanggalTransaksi: time.Now().AddDate(0, -1, 0),
-			JumlahSetoran:    100000,
+			JumlahSetoran:    models.Rupiah(100000),
 			NomorReferensi:   fmt.Sprintf("SMP-%s-0001", time.Now().Format("20060102")),
 		})
 
@@ -91,7 +91,7 @@ func seedMembers(db *gorm.DB, idKoperasi uuid.UUID, count int) {
 			IDAnggota:        member.ID,
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: time.Now().AddDate(0, 0, -15),
-			JumlahSetoran:    50000,
+			JumlahSetoran:    models.Rupiah(50000),
 			NomorReferensi:   fmt.Sprintf("SMP-%s-0002", time.Now().Format("20060102")),
 		})
 
@@ -102,7 +102,7 @@ func seedMembers(db *gorm.DB, idKoperasi uuid.UUID, count int) {
 				IDAnggota:        member.ID,
 				TipeSimpanan:     models.SimpananSukarela,
 				TanggalTransaksi: time.Now().AddDate(0, 0, -7),
-				JumlahSetoran:    25000,
+				JumlahSetoran:    models.Rupiah(25000),
 				NomorReferensi:   fmt.Sprintf("SMP-%s-0003", time.Now().Format("20060102")),
 			})
 		}
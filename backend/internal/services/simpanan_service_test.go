@@ -98,7 +98,7 @@ func TestN1QueryCount(t *testing.T) {
 				IDAnggota:        member.ID,
 				TipeSimpanan:     models.SimpananPokok,
 				TanggalTransaksi: time.Now(),
-				JumlahSetoran:    100000,
+				JumlahSetoran:    models.Rupiah(100000),
 				NomorReferensi:   fmt.Sprintf("SMP-%s-0001", time.Now().Format("20060102")),
 			},
 			models.Simpanan{
@@ -106,7 +106,7 @@ func TestN1QueryCount(t *testing.T) {
 				IDAnggota:        member.ID,
 				TipeSimpanan:     models.SimpananWajib,
 				TanggalTransaksi: time.Now(),
-				JumlahSetoran:    50000,
+				JumlahSetoran:    models.Rupiah(50000),
 				NomorReferensi:   fmt.Sprintf("SMP-%s-0002", time.Now().Format("20060102")),
 			},
 			models.Simpanan{
@@ -114,7 +114,7 @@ func TestN1QueryCount(t *testing.T) {
 				IDAnggota:        member.ID,
 				TipeSimpanan:     models.SimpananSukarela,
 				TanggalTransaksi: time.Now(),
-				JumlahSetoran:    25000,
+				JumlahSetoran:    models.Rupiah(25000),
 				NomorReferensi:   fmt.Sprintf("SMP-%s-0003", time.Now().Format("20060102")),
 			},
 		)
@@ -189,7 +189,7 @@ func TestDapatkanLaporanSaldoAnggota_Correctness(t *testing.T) {
 		IDAnggota:        member1.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    100000,
+		JumlahSetoran:    models.Rupiah(100000),
 		NomorReferensi:   "SMP-TEST-001",
 	})
 	db.Create(&models.Simpanan{
@@ -197,7 +197,7 @@ func TestDapatkanLaporanSaldoAnggota_Correctness(t *testing.T) {
 		IDAnggota:        member1.ID,
 		TipeSimpanan:     models.SimpananWajib,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    50000,
+		JumlahSetoran:    models.Rupiah(50000),
 		NomorReferensi:   "SMP-TEST-002",
 	})
 
@@ -207,7 +207,7 @@ func TestDapatkanLaporanSaldoAnggota_Correctness(t *testing.T) {
 		IDAnggota:        member2.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    200000,
+		JumlahSetoran:    models.Rupiah(200000),
 		NomorReferensi:   "SMP-TEST-003",
 	})
 	db.Create(&models.Simpanan{
@@ -215,7 +215,7 @@ func TestDapatkanLaporanSaldoAnggota_Correctness(t *testing.T) {
 		IDAnggota:        member2.ID,
 		TipeSimpanan:     models.SimpananSukarela,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    75000,
+		JumlahSetoran:    models.Rupiah(75000),
 		NomorReferensi:   "SMP-TEST-004",
 	})
 
@@ -243,24 +243,24 @@ func TestDapatkanLaporanSaldoAnggota_Correctness(t *testing.T) {
 
 	// Verify member 1
 	assert.NotNil(t, report1)
-	assert.Equal(t, float64(100000), report1.SimpananPokok)
-	assert.Equal(t, float64(50000), report1.SimpananWajib)
-	assert.Equal(t, float64(0), report1.SimpananSukarela)
-	assert.Equal(t, float64(150000), report1.TotalSimpanan)
+	assert.Equal(t, models.Rupiah(100000), report1.SimpananPokok)
+	assert.Equal(t, models.Rupiah(50000), report1.SimpananWajib)
+	assert.Equal(t, models.Uang(0), report1.SimpananSukarela)
+	assert.Equal(t, models.Rupiah(150000), report1.TotalSimpanan)
 
 	// Verify member 2
 	assert.NotNil(t, report2)
-	assert.Equal(t, float64(200000), report2.SimpananPokok)
-	assert.Equal(t, float64(0), report2.SimpananWajib)
-	assert.Equal(t, float64(75000), report2.SimpananSukarela)
-	assert.Equal(t, float64(275000), report2.TotalSimpanan)
+	assert.Equal(t, models.Rupiah(200000), report2.SimpananPokok)
+	assert.Equal(t, models.Uang(0), report2.SimpananWajib)
+	assert.Equal(t, models.Rupiah(75000), report2.SimpananSukarela)
+	assert.Equal(t, models.Rupiah(275000), report2.TotalSimpanan)
 
 	// Verify member 3 (no deposits)
 	assert.NotNil(t, report3)
-	assert.Equal(t, float64(0), report3.SimpananPokok)
-	assert.Equal(t, float64(0), report3.SimpananWajib)
-	assert.Equal(t, float64(0), report3.SimpananSukarela)
-	assert.Equal(t, float64(0), report3.TotalSimpanan)
+	assert.Equal(t, models.Uang(0), report3.SimpananPokok)
+	assert.Equal(t, models.Uang(0), report3.SimpananWajib)
+	assert.Equal(t, models.Uang(0), report3.SimpananSukarela)
+	assert.Equal(t, models.Uang(0), report3.TotalSimpanan)
 
 	// Cleanup
 	db.Exec("DELETE FROM simpanan WHERE id_koperasi = ?", koperasi.ID)
@@ -309,7 +309,7 @@ func TestDapatkanLaporanSaldoAnggota_MultiTenant(t *testing.T) {
 		IDAnggota:        member1.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    100000,
+		JumlahSetoran:    models.Rupiah(100000),
 		NomorReferensi:   "SMP-K1-001",
 	})
 	db.Create(&models.Simpanan{
@@ -317,7 +317,7 @@ func TestDapatkanLaporanSaldoAnggota_MultiTenant(t *testing.T) {
 		IDAnggota:        member2.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    200000,
+		JumlahSetoran:    models.Rupiah(200000),
 		NomorReferensi:   "SMP-K2-001",
 	})
 
@@ -327,14 +327,14 @@ func TestDapatkanLaporanSaldoAnggota_MultiTenant(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(laporan1))
 	assert.Equal(t, "A0001", laporan1[0].NomorAnggota)
-	assert.Equal(t, float64(100000), laporan1[0].SimpananPokok)
+	assert.Equal(t, models.Rupiah(100000), laporan1[0].SimpananPokok)
 
 	// Get report for koperasi 2
 	laporan2, err := service.DapatkanLaporanSaldoAnggota(koperasi2.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(laporan2))
 	assert.Equal(t, "A0001", laporan2[0].NomorAnggota)
-	assert.Equal(t, float64(200000), laporan2[0].SimpananPokok)
+	assert.Equal(t, models.Rupiah(200000), laporan2[0].SimpananPokok)
 
 	// Verify isolation: each cooperative only sees their own data
 	assert.NotEqual(t, laporan1[0].IDAnggota, laporan2[0].IDAnggota)
@@ -359,6 +359,9 @@ func TestCatatSetoran_SimpananPokokUniqueness(t *testing.T) {
 		&models.Akun{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate additional tables: %v", err)
@@ -400,7 +403,7 @@ func TestCatatSetoran_SimpananPokokUniqueness(t *testing.T) {
 		IDAnggota:        member.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    100000,
+		JumlahSetoran:    models.Rupiah(100000),
 		Keterangan:       "Simpanan pokok pertama",
 	}
 
@@ -408,14 +411,14 @@ func TestCatatSetoran_SimpananPokokUniqueness(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result1)
 	assert.Equal(t, models.SimpananPokok, result1.TipeSimpanan)
-	assert.Equal(t, float64(100000), result1.JumlahSetoran)
+	assert.Equal(t, models.Rupiah(100000), result1.JumlahSetoran)
 
 	// Second deposit of Simpanan Pokok - should fail with specific error
 	req2 := &CatatSetoranRequest{
 		IDAnggota:        member.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    50000,
+		JumlahSetoran:    models.Rupiah(50000),
 		Keterangan:       "Simpanan pokok kedua (tidak boleh)",
 	}
 
@@ -453,6 +456,9 @@ func TestCatatSetoran_OtherSimpananTypes(t *testing.T) {
 		&models.Akun{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate additional tables: %v", err)
@@ -495,7 +501,7 @@ func TestCatatSetoran_OtherSimpananTypes(t *testing.T) {
 			IDAnggota:        member.ID,
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: time.Now().AddDate(0, i-1, 0), // Different months
-			JumlahSetoran:    50000,
+			JumlahSetoran:    models.Rupiah(50000),
 			Keterangan:       fmt.Sprintf("Simpanan wajib bulan %d", i),
 		}
 
@@ -511,7 +517,7 @@ func TestCatatSetoran_OtherSimpananTypes(t *testing.T) {
 			IDAnggota:        member.ID,
 			TipeSimpanan:     models.SimpananSukarela,
 			TanggalTransaksi: time.Now(),
-			JumlahSetoran:    25000,
+			JumlahSetoran:    models.Rupiah(25000),
 			Keterangan:       fmt.Sprintf("Simpanan sukarela %d", i),
 		}
 
@@ -541,3 +547,132 @@ func TestCatatSetoran_OtherSimpananTypes(t *testing.T) {
 	db.Exec("DELETE FROM pengguna WHERE id_koperasi = ?", koperasi.ID)
 	db.Delete(koperasi)
 }
+
+// TestCatatPenarikan verifies withdrawal balance checks and the pokok/wajib restriction
+func TestCatatPenarikan(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate additional tables: %v", err)
+	}
+
+	koperasi := &models.Koperasi{
+		NamaKoperasi: "Test Penarikan Koperasi",
+		Alamat:       "Test Address",
+	}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	if err := NewAkunService(db).InisialisasiCOADefault(koperasi.ID); err != nil {
+		t.Fatalf("Failed to initialize COA: %v", err)
+	}
+
+	member := &models.Anggota{
+		IDKoperasi:   koperasi.ID,
+		NomorAnggota: "A0001",
+		NamaLengkap:  "Test Member",
+		Status:       models.StatusAktif,
+	}
+	db.Create(member)
+
+	idPengguna := member.ID
+	transaksiService := NewTransaksiService(db)
+	service := NewSimpananService(db, transaksiService)
+
+	for _, tipe := range []models.TipeSimpanan{models.SimpananPokok, models.SimpananSukarela} {
+		_, err := service.CatatSetoran(koperasi.ID, idPengguna, &CatatSetoranRequest{
+			IDAnggota:        member.ID,
+			TipeSimpanan:     tipe,
+			TanggalTransaksi: time.Now(),
+			JumlahSetoran:    models.Rupiah(100000),
+		})
+		assert.NoError(t, err)
+	}
+
+	// Penarikan sukarela sebagian berhasil dan mengurangi saldo bersih
+	hasil, err := service.CatatPenarikan(koperasi.ID, idPengguna, &CatatPenarikanRequest{
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananSukarela,
+		TanggalTransaksi: time.Now(),
+		JumlahPenarikan:  models.Rupiah(40000),
+	})
+	assert.NoError(t, err)
+	if assert.NotNil(t, hasil) {
+		assert.Equal(t, models.TransaksiPenarikan, hasil.JenisTransaksi)
+	}
+
+	saldo, err := service.DapatkanSaldoAnggota(member.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Rupiah(60000), saldo.SimpananSukarela)
+	assert.Equal(t, models.Rupiah(100000), saldo.SimpananPokok)
+
+	// Penarikan melebihi saldo ditolak
+	_, err = service.CatatPenarikan(koperasi.ID, idPengguna, &CatatPenarikanRequest{
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananSukarela,
+		TanggalTransaksi: time.Now(),
+		JumlahPenarikan:  mustUang("60000.01"),
+	})
+	assert.Error(t, err)
+
+	// Simpanan pokok tidak dapat ditarik selama anggota masih aktif
+	_, err = service.CatatPenarikan(koperasi.ID, idPengguna, &CatatPenarikanRequest{
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananPokok,
+		TanggalTransaksi: time.Now(),
+		JumlahPenarikan:  models.Rupiah(100000),
+	})
+	assert.Error(t, err)
+
+	// Penarikan bertanggal mundur tidak boleh memakai setoran yang dicatat sesudahnya
+	_, err = service.CatatPenarikan(koperasi.ID, idPengguna, &CatatPenarikanRequest{
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananSukarela,
+		TanggalTransaksi: time.Now().AddDate(0, 0, -5),
+		JumlahPenarikan:  models.Rupiah(10000),
+	})
+	assert.Error(t, err)
+
+	// Setoran lama 50.000 lalu saldo hari ini ditarik habis: saldo per tanggal mundur cukup,
+	// tetapi penarikan mundur akan membuat saldo hari ini negatif
+	setoranLama, err := service.CatatSetoran(koperasi.ID, idPengguna, &CatatSetoranRequest{
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananSukarela,
+		TanggalTransaksi: time.Now().AddDate(0, 0, -10),
+		JumlahSetoran:    models.Rupiah(50000),
+	})
+	assert.NoError(t, err)
+	_, err = service.CatatPenarikan(koperasi.ID, idPengguna, &CatatPenarikanRequest{
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananSukarela,
+		TanggalTransaksi: time.Now(),
+		JumlahPenarikan:  models.Rupiah(110000),
+	})
+	assert.NoError(t, err)
+
+	_, err = service.CatatPenarikan(koperasi.ID, idPengguna, &CatatPenarikanRequest{
+		IDAnggota:        member.ID,
+		TipeSimpanan:     models.SimpananSukarela,
+		TanggalTransaksi: time.Now().AddDate(0, 0, -5),
+		JumlahPenarikan:  models.Rupiah(30000),
+	})
+	assert.Error(t, err)
+
+	// Pembatalan setoran lama juga ditolak karena saldo setelahnya menjadi negatif
+	if assert.NotNil(t, setoranLama) {
+		_, err = service.BatalkanSimpanan(koperasi.ID, idPengguna, setoranLama.ID, &BatalkanSimpananRequest{Alasan: "Salah input setoran"})
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"sort"
+)
+
+// BarisCOA adalah satu akun dalam template, berkas impor, atau berkas ekspor COA.
+// Hierarki akun dinyatakan dengan kode akun induk, bukan ID, agar dapat dipindahkan antar koperasi.
+type BarisCOA struct {
+	KodeAkun        string                 `json:"kodeAkun"`
+	NamaAkun        string                 `json:"namaAkun"`
+	TipeAkun        models.TipeAkun        `json:"tipeAkun"`
+	KodeInduk       string                 `json:"kodeInduk,omitempty"`
+	NormalSaldo     string                 `json:"normalSaldo,omitempty"` // Kosong: ditentukan dari tipe akun
+	KategoriArusKas models.KategoriArusKas `json:"kategoriArusKas,omitempty"`
+	Deskripsi       string                 `json:"deskripsi,omitempty"`
+}
+
+// coaDasar adalah akun yang dimiliki semua jenis koperasi. Kode akun yang dipakai
+// aturan posting default (kas, simpanan, SHU) sengaja sama di semua template.
+var coaDasar = []BarisCOA{
+	// ASET
+	{KodeAkun: "1000", NamaAkun: "ASET", TipeAkun: models.AkunAktiva},
+	{KodeAkun: "1100", NamaAkun: "Aset Lancar", TipeAkun: models.AkunAktiva, KodeInduk: "1000"},
+	{KodeAkun: "1101", NamaAkun: "Kas", TipeAkun: models.AkunAktiva, KodeInduk: "1100", KategoriArusKas: models.ArusKasKas},
+	{KodeAkun: "1102", NamaAkun: "Bank", TipeAkun: models.AkunAktiva, KodeInduk: "1100", KategoriArusKas: models.ArusKasKas},
+	{KodeAkun: "1200", NamaAkun: "Piutang", TipeAkun: models.AkunAktiva, KodeInduk: "1000"},
+	{KodeAkun: "1201", NamaAkun: "Piutang Anggota", TipeAkun: models.AkunAktiva, KodeInduk: "1200"},
+	{KodeAkun: "1400", NamaAkun: "Aset Tetap", TipeAkun: models.AkunAktiva, KodeInduk: "1000", KategoriArusKas: models.ArusKasInvestasi},
+	{KodeAkun: "1401", NamaAkun: "Peralatan dan Inventaris", TipeAkun: models.AkunAktiva, KodeInduk: "1400", KategoriArusKas: models.ArusKasInvestasi},
+	{KodeAkun: "1402", NamaAkun: "Akumulasi Penyusutan Peralatan", TipeAkun: models.AkunAktiva, KodeInduk: "1400", NormalSaldo: "KREDIT"},
+	{KodeAkun: "1403", NamaAkun: "Tanah", TipeAkun: models.AkunAktiva, KodeInduk: "1400", KategoriArusKas: models.ArusKasInvestasi},
+	{KodeAkun: "1404", NamaAkun: "Gedung dan Bangunan", TipeAkun: models.AkunAktiva, KodeInduk: "1400", KategoriArusKas: models.ArusKasInvestasi},
+	{KodeAkun: "1405", NamaAkun: "Akumulasi Penyusutan Gedung dan Bangunan", TipeAkun: models.AkunAktiva, KodeInduk: "1400", NormalSaldo: "KREDIT"},
+	{KodeAkun: "1406", NamaAkun: "Kendaraan", TipeAkun: models.AkunAktiva, KodeInduk: "1400", KategoriArusKas: models.ArusKasInvestasi},
+	{KodeAkun: "1407", NamaAkun: "Akumulasi Penyusutan Kendaraan", TipeAkun: models.AkunAktiva, KodeInduk: "1400", NormalSaldo: "KREDIT"},
+
+	// KEWAJIBAN
+	{KodeAkun: "2000", NamaAkun: "KEWAJIBAN", TipeAkun: models.AkunKewajiban},
+	{KodeAkun: "2100", NamaAkun: "Kewajiban Jangka Pendek", TipeAkun: models.AkunKewajiban, KodeInduk: "2000"},
+	{KodeAkun: "2101", NamaAkun: "Hutang Usaha", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+	{KodeAkun: "2102", NamaAkun: "SHU Bagian Anggota", TipeAkun: models.AkunKewajiban, KodeInduk: "2100", KategoriArusKas: models.ArusKasPendanaan},
+	{KodeAkun: "2103", NamaAkun: "Dana Pengurus & Karyawan", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+	{KodeAkun: "2104", NamaAkun: "Dana Pendidikan", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+	{KodeAkun: "2105", NamaAkun: "Dana Sosial", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+	{KodeAkun: "2111", NamaAkun: "PPN Keluaran", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+	{KodeAkun: "2112", NamaAkun: "Utang PPh Pasal 21", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+	{KodeAkun: "2113", NamaAkun: "Utang PPh Pasal 23", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+
+	// MODAL
+	{KodeAkun: "3000", NamaAkun: "MODAL", TipeAkun: models.AkunModal},
+	{KodeAkun: "3100", NamaAkun: "Modal Koperasi", TipeAkun: models.AkunModal, KodeInduk: "3000"},
+	{KodeAkun: "3101", NamaAkun: "Simpanan Pokok", TipeAkun: models.AkunModal, KodeInduk: "3100"},
+	{KodeAkun: "3102", NamaAkun: "Simpanan Wajib", TipeAkun: models.AkunModal, KodeInduk: "3100"},
+	{KodeAkun: "3103", NamaAkun: "Simpanan Sukarela", TipeAkun: models.AkunModal, KodeInduk: "3100"},
+	{KodeAkun: "3200", NamaAkun: "Sisa Hasil Usaha (SHU)", TipeAkun: models.AkunModal, KodeInduk: "3000"},
+	{KodeAkun: "3201", NamaAkun: "SHU Tahun Berjalan", TipeAkun: models.AkunModal, KodeInduk: "3200"},
+	{KodeAkun: "3202", NamaAkun: "Cadangan Koperasi", TipeAkun: models.AkunModal, KodeInduk: "3200"},
+
+	// PENDAPATAN
+	{KodeAkun: "4000", NamaAkun: "PENDAPATAN", TipeAkun: models.AkunPendapatan},
+	{KodeAkun: "4100", NamaAkun: "Pendapatan Usaha", TipeAkun: models.AkunPendapatan, KodeInduk: "4000"},
+	{KodeAkun: "4200", NamaAkun: "Pendapatan Lain-lain", TipeAkun: models.AkunPendapatan, KodeInduk: "4000"},
+	{KodeAkun: "4201", NamaAkun: "Laba Pelepasan Aset Tetap", TipeAkun: models.AkunPendapatan, KodeInduk: "4200"},
+	{KodeAkun: "4202", NamaAkun: "Pendapatan Jasa Giro", TipeAkun: models.AkunPendapatan, KodeInduk: "4200"},
+
+	// BEBAN
+	{KodeAkun: "5000", NamaAkun: "BEBAN", TipeAkun: models.AkunBeban},
+	{KodeAkun: "5100", NamaAkun: "Beban Operasional", TipeAkun: models.AkunBeban, KodeInduk: "5000"},
+	{KodeAkun: "5101", NamaAkun: "Beban Gaji", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+	{KodeAkun: "5102", NamaAkun: "Beban Listrik", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+	{KodeAkun: "5103", NamaAkun: "Beban Air", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+	{KodeAkun: "5104", NamaAkun: "Beban Telepon & Internet", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+	{KodeAkun: "5105", NamaAkun: "Beban Penyusutan", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+	{KodeAkun: "5300", NamaAkun: "Beban Lain-lain", TipeAkun: models.AkunBeban, KodeInduk: "5000"},
+	{KodeAkun: "5301", NamaAkun: "Rugi Pelepasan Aset Tetap", TipeAkun: models.AkunBeban, KodeInduk: "5300"},
+	{KodeAkun: "5302", NamaAkun: "Beban Administrasi Bank", TipeAkun: models.AkunBeban, KodeInduk: "5300"},
+}
+
+// coaPerdagangan adalah akun untuk unit usaha penjualan barang (POS)
+var coaPerdagangan = []BarisCOA{
+	{KodeAkun: "1300", NamaAkun: "Persediaan", TipeAkun: models.AkunAktiva, KodeInduk: "1000"},
+	{KodeAkun: "1301", NamaAkun: "Persediaan Barang Dagangan", TipeAkun: models.AkunAktiva, KodeInduk: "1300"},
+	{KodeAkun: "4101", NamaAkun: "Penjualan", TipeAkun: models.AkunPendapatan, KodeInduk: "4100"},
+	{KodeAkun: "5200", NamaAkun: "Harga Pokok Penjualan", TipeAkun: models.AkunBeban, KodeInduk: "5000"},
+	{KodeAkun: "5201", NamaAkun: "HPP", TipeAkun: models.AkunBeban, KodeInduk: "5200"},
+}
+
+// coaSimpanPinjam adalah akun untuk unit usaha pinjaman anggota konvensional
+var coaSimpanPinjam = []BarisCOA{
+	{KodeAkun: "1202", NamaAkun: "Piutang Pinjaman Anggota", TipeAkun: models.AkunAktiva, KodeInduk: "1200"},
+	{KodeAkun: "4102", NamaAkun: "Pendapatan Jasa Pinjaman", TipeAkun: models.AkunPendapatan, KodeInduk: "4100"},
+}
+
+// coaKSP adalah akun tambahan khusus koperasi simpan pinjam
+var coaKSP = []BarisCOA{
+	{KodeAkun: "1203", NamaAkun: "Penyisihan Piutang Tak Tertagih", TipeAkun: models.AkunAktiva, KodeInduk: "1200", NormalSaldo: "KREDIT"},
+	{KodeAkun: "2106", NamaAkun: "Simpanan Berjangka Anggota", TipeAkun: models.AkunKewajiban, KodeInduk: "2100", KategoriArusKas: models.ArusKasPendanaan},
+	{KodeAkun: "4103", NamaAkun: "Pendapatan Provisi dan Administrasi", TipeAkun: models.AkunPendapatan, KodeInduk: "4100"},
+	{KodeAkun: "5106", NamaAkun: "Beban Jasa Simpanan Berjangka", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+	{KodeAkun: "5107", NamaAkun: "Beban Penyisihan Piutang", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+}
+
+// coaKonsumen adalah akun tambahan khusus koperasi konsumen
+var coaKonsumen = []BarisCOA{
+	{KodeAkun: "4103", NamaAkun: "Retur dan Potongan Penjualan", TipeAkun: models.AkunPendapatan, KodeInduk: "4100", NormalSaldo: "DEBIT"},
+	{KodeAkun: "5202", NamaAkun: "Beban Angkut Pembelian", TipeAkun: models.AkunBeban, KodeInduk: "5200"},
+}
+
+// coaSyariah adalah akun pembiayaan dan simpanan berbasis akad syariah.
+// Kode 1202 dan 4102 tetap dipakai agar aturan posting pinjaman default langsung berlaku.
+var coaSyariah = []BarisCOA{
+	{KodeAkun: "1202", NamaAkun: "Piutang Murabahah", TipeAkun: models.AkunAktiva, KodeInduk: "1200"},
+	{KodeAkun: "1203", NamaAkun: "Pembiayaan Mudharabah", TipeAkun: models.AkunAktiva, KodeInduk: "1200"},
+	{KodeAkun: "1204", NamaAkun: "Pembiayaan Musyarakah", TipeAkun: models.AkunAktiva, KodeInduk: "1200"},
+	{KodeAkun: "1205", NamaAkun: "Piutang Qardh", TipeAkun: models.AkunAktiva, KodeInduk: "1200"},
+	{KodeAkun: "2106", NamaAkun: "Simpanan Wadiah", TipeAkun: models.AkunKewajiban, KodeInduk: "2100", KategoriArusKas: models.ArusKasPendanaan},
+	{KodeAkun: "2107", NamaAkun: "Simpanan Mudharabah Berjangka", TipeAkun: models.AkunKewajiban, KodeInduk: "2100", KategoriArusKas: models.ArusKasPendanaan},
+	{KodeAkun: "2108", NamaAkun: "Dana Zakat, Infak dan Sedekah", TipeAkun: models.AkunKewajiban, KodeInduk: "2100"},
+	{KodeAkun: "4102", NamaAkun: "Pendapatan Margin Murabahah", TipeAkun: models.AkunPendapatan, KodeInduk: "4100"},
+	{KodeAkun: "4103", NamaAkun: "Pendapatan Bagi Hasil Mudharabah", TipeAkun: models.AkunPendapatan, KodeInduk: "4100"},
+	{KodeAkun: "4104", NamaAkun: "Pendapatan Bagi Hasil Musyarakah", TipeAkun: models.AkunPendapatan, KodeInduk: "4100"},
+	{KodeAkun: "4105", NamaAkun: "Pendapatan Ujrah", TipeAkun: models.AkunPendapatan, KodeInduk: "4100"},
+	{KodeAkun: "5106", NamaAkun: "Beban Bagi Hasil Simpanan Mudharabah", TipeAkun: models.AkunBeban, KodeInduk: "5100"},
+}
+
+// TemplateCOA adalah template Chart of Accounts untuk satu jenis koperasi
+type TemplateCOA struct {
+	JenisKoperasi models.JenisKoperasi `json:"jenisKoperasi"`
+	Nama          string               `json:"nama"`
+	Deskripsi     string               `json:"deskripsi"`
+	Akun          []BarisCOA           `json:"akun"`
+}
+
+// daftarTemplateCOA menentukan template yang tersedia dan urutan tampilannya
+var daftarTemplateCOA = []TemplateCOA{
+	{
+		JenisKoperasi: models.JenisKoperasiSimpanPinjam,
+		Nama:          "Koperasi Simpan Pinjam (KSP)",
+		Deskripsi:     "Simpanan anggota, pinjaman, simpanan berjangka dan penyisihan piutang",
+		Akun:          gabungCOA(coaDasar, coaSimpanPinjam, coaKSP),
+	},
+	{
+		JenisKoperasi: models.JenisKoperasiSerbaUsaha,
+		Nama:          "Koperasi Serba Usaha (KSU)",
+		Deskripsi:     "Unit simpan pinjam dan unit penjualan barang",
+		Akun:          gabungCOA(coaDasar, coaPerdagangan, coaSimpanPinjam),
+	},
+	{
+		JenisKoperasi: models.JenisKoperasiKonsumen,
+		Nama:          "Koperasi Konsumen",
+		Deskripsi:     "Penjualan barang kebutuhan anggota tanpa unit simpan pinjam",
+		Akun:          gabungCOA(coaDasar, coaPerdagangan, coaKonsumen),
+	},
+	{
+		JenisKoperasi: models.JenisKoperasiSyariah,
+		Nama:          "Koperasi Simpan Pinjam dan Pembiayaan Syariah (KSPPS)",
+		Deskripsi:     "Pembiayaan murabahah, mudharabah, musyarakah dan simpanan wadiah",
+		Akun:          gabungCOA(coaDasar, coaSyariah),
+	},
+}
+
+// gabungCOA menggabungkan beberapa kelompok akun dan mengurutkannya berdasarkan kode,
+// sehingga akun induk selalu muncul sebelum sub-akunnya
+func gabungCOA(kelompok ...[]BarisCOA) []BarisCOA {
+	var hasil []BarisCOA
+	for _, daftar := range kelompok {
+		hasil = append(hasil, daftar...)
+	}
+	sort.SliceStable(hasil, func(i, j int) bool {
+		return hasil[i].KodeAkun < hasil[j].KodeAkun
+	})
+	return hasil
+}
+
+// cariTemplateCOA mencari template COA berdasarkan jenis koperasi
+func cariTemplateCOA(jenis models.JenisKoperasi) (TemplateCOA, bool) {
+	for _, template := range daftarTemplateCOA {
+		if template.JenisKoperasi == jenis {
+			return template, true
+		}
+	}
+	return TemplateCOA{}, false
+}
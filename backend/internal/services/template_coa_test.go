@@ -0,0 +1,99 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateCOA_HierarkiValid(t *testing.T) {
+	for _, template := range daftarTemplateCOA {
+		t.Run(string(template.JenisKoperasi), func(t *testing.T) {
+			tipeByKode := map[string]models.TipeAkun{}
+			for _, baris := range template.Akun {
+				_, ganda := tipeByKode[baris.KodeAkun]
+				assert.False(t, ganda, "kode akun %s ganda", baris.KodeAkun)
+
+				// Induk harus sudah muncul sebelumnya dengan tipe yang sama
+				if baris.KodeInduk != "" {
+					tipeInduk, ada := tipeByKode[baris.KodeInduk]
+					if assert.True(t, ada, "induk %s dari %s belum didefinisikan", baris.KodeInduk, baris.KodeAkun) {
+						assert.Equal(t, baris.TipeAkun, tipeInduk, "tipe induk %s berbeda", baris.KodeAkun)
+					}
+				}
+				tipeByKode[baris.KodeAkun] = baris.TipeAkun
+			}
+		})
+	}
+}
+
+func TestTemplateCOA_MemuatAkunAturanPostingDefault(t *testing.T) {
+	// Akun kas, simpanan, SHU, pelepasan aset dan mutasi bank dipakai semua jenis koperasi
+	peristiwaUmum := []models.JenisPeristiwa{
+		models.PeristiwaSimpananPokok,
+		models.PeristiwaSimpananWajib,
+		models.PeristiwaSimpananSukarela,
+		models.PeristiwaPembagianSHU,
+		models.PeristiwaPenutupanTahun,
+		models.PeristiwaPelepasanAset,
+		models.PeristiwaMutasiBank,
+		models.PeristiwaPPNKeluaran,
+		models.PeristiwaPemotonganPPh21,
+		models.PeristiwaPemotonganPPh23,
+	}
+
+	for _, template := range daftarTemplateCOA {
+		kode := map[string]bool{}
+		for _, baris := range template.Akun {
+			kode[baris.KodeAkun] = true
+		}
+
+		for _, peristiwa := range peristiwaUmum {
+			for _, definisi := range definisiAturanPosting[peristiwa] {
+				assert.True(t, kode[definisi.KodeDefault], "template %s tidak memuat akun %s untuk %s",
+					template.JenisKoperasi, definisi.KodeDefault, peristiwa)
+			}
+		}
+	}
+
+	ksu, ok := cariTemplateCOA(models.JenisKoperasiSerbaUsaha)
+	require.True(t, ok)
+	kodeKSU := map[string]bool{}
+	for _, baris := range ksu.Akun {
+		kodeKSU[baris.KodeAkun] = true
+	}
+	for _, peristiwa := range daftarPeristiwaPosting {
+		for _, definisi := range definisiAturanPosting[peristiwa] {
+			assert.True(t, kodeKSU[definisi.KodeDefault], "template KSU tidak memuat akun %s", definisi.KodeDefault)
+		}
+	}
+}
+
+func TestInisialisasiCOA_TemplateSyariah(t *testing.T) {
+	db := setupAkunTestDB(t)
+	if db == nil {
+		return
+	}
+
+	service := NewAkunService(db)
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test KSPPS", Email: "kspps@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	require.NoError(t, service.InisialisasiCOA(koperasi.ID, models.JenisKoperasiSyariah))
+
+	murabahah, err := service.DapatkanAkunByKode(koperasi.ID, "1202")
+	require.NoError(t, err)
+	assert.Equal(t, "Piutang Murabahah", murabahah.NamaAkun)
+	assert.Equal(t, "Piutang", murabahah.NamaInduk)
+
+	_, err = service.DapatkanAkunByKode(koperasi.ID, "4101")
+	assert.Error(t, err, "template syariah tidak memuat akun penjualan")
+
+	// Jenis koperasi yang tidak dikenal ditolak
+	err = service.InisialisasiCOA(uuid.New(), models.JenisKoperasi("KOPERASI_LAIN"))
+	assert.Error(t, err)
+}
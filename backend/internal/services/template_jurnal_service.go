@@ -0,0 +1,542 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TemplateJurnalService menangani template jurnal dan jadwal jurnal berulang
+type TemplateJurnalService struct {
+	db               *gorm.DB
+	transaksiService *TransaksiService
+}
+
+// NewTemplateJurnalService membuat instance baru TemplateJurnalService
+func NewTemplateJurnalService(db *gorm.DB, transaksiService *TransaksiService) *TemplateJurnalService {
+	return &TemplateJurnalService{
+		db:               db,
+		transaksiService: transaksiService,
+	}
+}
+
+// SimpanTemplateJurnalRequest adalah struktur request untuk membuat atau memperbarui template jurnal
+type SimpanTemplateJurnalRequest struct {
+	KodeTemplate    string                      `json:"kodeTemplate" binding:"required"`
+	NamaTemplate    string                      `json:"namaTemplate" binding:"required"`
+	Deskripsi       string                      `json:"deskripsi" binding:"required"`
+	StatusAktif     *bool                       `json:"statusAktif"`     // Default true
+	Frekuensi       models.FrekuensiJurnal      `json:"frekuensi"`       // Kosong: template tanpa jadwal
+	TanggalMulai    *time.Time                  `json:"tanggalMulai"`    // Wajib jika frekuensi diisi
+	TanggalSelesai  *time.Time                  `json:"tanggalSelesai"`  // Opsional
+	PostingLangsung bool                        `json:"postingLangsung"` // Hanya admin; selain itu jurnal dibuat DRAFT
+	BarisTransaksi  []BuatBarisTransaksiRequest `json:"barisTransaksi" binding:"required,min=2"`
+}
+
+// GunakanTemplateJurnalRequest adalah struktur request untuk membuat jurnal dari template secara manual
+type GunakanTemplateJurnalRequest struct {
+	TanggalTransaksi time.Time `json:"tanggalTransaksi" binding:"required"`
+	NomorReferensi   string    `json:"nomorReferensi"`
+}
+
+// PratinjauJurnalBerulang adalah jurnal yang akan dibuat oleh scheduler pada satu tanggal jatuh tempo
+type PratinjauJurnalBerulang struct {
+	IDTemplateJurnal uuid.UUID                            `json:"idTemplateJurnal"`
+	KodeTemplate     string                               `json:"kodeTemplate"`
+	NamaTemplate     string                               `json:"namaTemplate"`
+	TanggalTransaksi time.Time                            `json:"tanggalTransaksi"`
+	Deskripsi        string                               `json:"deskripsi"`
+	NomorReferensi   string                               `json:"nomorReferensi"`
+	Status           models.StatusJurnal                  `json:"status"` // Status jurnal saat dibuat
+	TotalDebit       models.Uang                          `json:"totalDebit"`
+	BarisTransaksi   []models.BarisTemplateJurnalResponse `json:"barisTransaksi"`
+}
+
+// BuatTemplateJurnal membuat template jurnal baru beserta jadwal berulangnya (jika ada)
+func (s *TemplateJurnalService) BuatTemplateJurnal(idKoperasi, idPengguna uuid.UUID, req *SimpanTemplateJurnalRequest) (*models.TemplateJurnalResponse, error) {
+	if err := s.validasiTemplateJurnal(req); err != nil {
+		return nil, err
+	}
+
+	template := &models.TemplateJurnal{
+		IDKoperasi:   idKoperasi,
+		KodeTemplate: req.KodeTemplate,
+		DibuatOleh:   idPengguna,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Validasi kode template unique (termasuk yang sudah dihapus karena unique index)
+		var count int64
+		tx.Unscoped().Model(&models.TemplateJurnal{}).
+			Where("id_koperasi = ? AND kode_template = ?", idKoperasi, req.KodeTemplate).
+			Count(&count)
+		if count > 0 {
+			return errors.New("kode template sudah digunakan")
+		}
+
+		if err := s.terapkanTemplateWithTx(tx, template, idPengguna, req); err != nil {
+			return err
+		}
+		if err := tx.Omit("Baris").Create(template).Error; err != nil {
+			return errors.New("gagal membuat template jurnal")
+		}
+		return s.gantiBarisTemplateWithTx(tx, template.ID, req.BarisTransaksi)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanTemplateJurnal(template.ID, idKoperasi)
+}
+
+// PerbaruiTemplateJurnal mengganti isi template jurnal dan menghitung ulang jatuh tempo berikutnya.
+// Kode template tidak dapat diubah karena dipakai sebagai nomor referensi jurnal berulang.
+// Jatuh tempo yang sudah diproses tidak dibuat ulang.
+func (s *TemplateJurnalService) PerbaruiTemplateJurnal(id, idKoperasi, idPengguna uuid.UUID, req *SimpanTemplateJurnalRequest) (*models.TemplateJurnalResponse, error) {
+	if err := s.validasiTemplateJurnal(req); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var template models.TemplateJurnal
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&template).Error
+		if err != nil {
+			return errors.New("template jurnal tidak ditemukan")
+		}
+		if template.KodeTemplate != req.KodeTemplate {
+			return errors.New("kode template tidak dapat diubah")
+		}
+
+		if err := s.terapkanTemplateWithTx(tx, &template, idPengguna, req); err != nil {
+			return err
+		}
+		if err := tx.Omit("Baris").Save(&template).Error; err != nil {
+			return errors.New("gagal memperbarui template jurnal")
+		}
+		return s.gantiBarisTemplateWithTx(tx, template.ID, req.BarisTransaksi)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DapatkanTemplateJurnal(id, idKoperasi)
+}
+
+// validasiTemplateJurnal memvalidasi request template jurnal, termasuk keseimbangan debit dan kredit
+func (s *TemplateJurnalService) validasiTemplateJurnal(req *SimpanTemplateJurnalRequest) error {
+	validator := validasi.Baru()
+
+	if err := validator.TeksWajib(req.KodeTemplate, "kode template", 1, 20); err != nil {
+		return err
+	}
+	if err := validator.TeksWajib(req.NamaTemplate, "nama template", 3, 255); err != nil {
+		return err
+	}
+	if err := validator.TeksWajib(req.Deskripsi, "deskripsi", 5, 500); err != nil {
+		return err
+	}
+
+	if req.Frekuensi == "" {
+		if req.TanggalMulai != nil || req.TanggalSelesai != nil || req.PostingLangsung {
+			return errors.New("tanggal jadwal dan posting langsung hanya berlaku untuk template berjadwal")
+		}
+	} else {
+		if req.Frekuensi.JarakBulan() == 0 {
+			return fmt.Errorf("frekuensi %s tidak dikenal", req.Frekuensi)
+		}
+		if req.TanggalMulai == nil {
+			return errors.New("tanggal mulai wajib diisi untuk template berjadwal")
+		}
+		if req.TanggalSelesai != nil && req.TanggalSelesai.Before(*req.TanggalMulai) {
+			return errors.New("tanggal selesai tidak boleh sebelum tanggal mulai")
+		}
+	}
+
+	return s.transaksiService.ValidasiTransaksi(req.BarisTransaksi)
+}
+
+// terapkanTemplateWithTx menyalin request ke template setelah memvalidasi akun, unit usaha,
+// dan hak posting langsung, lalu menghitung jatuh tempo berikutnya.
+func (s *TemplateJurnalService) terapkanTemplateWithTx(tx *gorm.DB, template *models.TemplateJurnal, idPengguna uuid.UUID, req *SimpanTemplateJurnalRequest) error {
+	var totalDebit, totalKredit models.Uang
+	for i, baris := range req.BarisTransaksi {
+		var akun models.Akun
+		if err := tx.Where("id = ? AND id_koperasi = ?", baris.IDAkun, template.IDKoperasi).First(&akun).Error; err != nil {
+			return fmt.Errorf("baris %d: akun tidak ditemukan", i+1)
+		}
+		if !akun.StatusAktif {
+			return fmt.Errorf("baris %d: akun %s tidak aktif", i+1, akun.KodeAkun)
+		}
+		if err := validasiUnitUsahaAktifWithTx(tx, template.IDKoperasi, baris.IDUnitUsaha); err != nil {
+			return fmt.Errorf("baris %d: %v", i+1, err)
+		}
+
+		totalDebit += baris.JumlahDebit
+		totalKredit += baris.JumlahKredit
+	}
+
+	// Jurnal berulang yang langsung di-post dibuat atas nama pengguna ini, sehingga
+	// hanya admin (yang jurnal umumnya langsung POSTED) yang boleh mengaktifkannya
+	if req.PostingLangsung {
+		status, err := statusAwalJurnalWithTx(tx, idPengguna)
+		if err != nil {
+			return err
+		}
+		if status != models.StatusJurnalPosted {
+			return errors.New("hanya admin yang dapat menjadwalkan jurnal yang langsung di-post")
+		}
+	}
+
+	template.NamaTemplate = req.NamaTemplate
+	template.Deskripsi = req.Deskripsi
+	template.StatusAktif = req.StatusAktif == nil || *req.StatusAktif
+	template.TotalDebit = totalDebit
+	template.TotalKredit = totalKredit
+	template.Frekuensi = req.Frekuensi
+	template.TanggalMulai = req.TanggalMulai
+	template.TanggalSelesai = req.TanggalSelesai
+	template.PostingLangsung = req.PostingLangsung
+	template.DiperbaruiOleh = idPengguna
+	template.TanggalBerikutnya = jatuhTempoBerikutnya(template)
+
+	return nil
+}
+
+// gantiBarisTemplateWithTx mengganti seluruh baris template sesuai urutan request
+func (s *TemplateJurnalService) gantiBarisTemplateWithTx(tx *gorm.DB, idTemplate uuid.UUID, barisReq []BuatBarisTransaksiRequest) error {
+	if err := tx.Where("id_template_jurnal = ?", idTemplate).Delete(&models.BarisTemplateJurnal{}).Error; err != nil {
+		return errors.New("gagal menghapus baris template lama")
+	}
+
+	barisList := make([]models.BarisTemplateJurnal, len(barisReq))
+	for i, baris := range barisReq {
+		barisList[i] = models.BarisTemplateJurnal{
+			IDTemplateJurnal: idTemplate,
+			Urutan:           i + 1,
+			IDAkun:           baris.IDAkun,
+			JumlahDebit:      baris.JumlahDebit,
+			JumlahKredit:     baris.JumlahKredit,
+			Keterangan:       baris.Keterangan,
+			IDUnitUsaha:      baris.IDUnitUsaha,
+		}
+	}
+
+	if err := tx.Create(&barisList).Error; err != nil {
+		return errors.New("gagal menyimpan baris template")
+	}
+	return nil
+}
+
+// DapatkanSemuaTemplateJurnal mengambil seluruh template jurnal koperasi, diurutkan berdasarkan kode
+func (s *TemplateJurnalService) DapatkanSemuaTemplateJurnal(idKoperasi uuid.UUID) ([]models.TemplateJurnalResponse, error) {
+	var templateList []models.TemplateJurnal
+	err := s.preloadBaris(s.db).
+		Where("id_koperasi = ?", idKoperasi).
+		Order("kode_template ASC").
+		Find(&templateList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil daftar template jurnal")
+	}
+
+	responses := make([]models.TemplateJurnalResponse, len(templateList))
+	for i := range templateList {
+		responses[i] = templateList[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// DapatkanTemplateJurnal mengambil detail template jurnal beserta barisnya
+func (s *TemplateJurnalService) DapatkanTemplateJurnal(id, idKoperasi uuid.UUID) (*models.TemplateJurnalResponse, error) {
+	template, err := s.ambilTemplate(id, idKoperasi)
+	if err != nil {
+		return nil, err
+	}
+
+	response := template.ToResponse()
+	return &response, nil
+}
+
+// HapusTemplateJurnal menghapus template jurnal; jurnal yang sudah dibuat dari template tetap ada
+func (s *TemplateJurnalService) HapusTemplateJurnal(id, idKoperasi uuid.UUID) error {
+	result := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).Delete(&models.TemplateJurnal{})
+	if result.Error != nil {
+		return errors.New("gagal menghapus template jurnal")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("template jurnal tidak ditemukan")
+	}
+
+	return nil
+}
+
+// BuatJurnalDariTemplate membuat jurnal umum dari template atas nama pengguna yang meminta.
+// Status jurnal mengikuti aturan BuatTransaksi (DRAFT untuk non-admin).
+func (s *TemplateJurnalService) BuatJurnalDariTemplate(id, idKoperasi, idPengguna uuid.UUID, req *GunakanTemplateJurnalRequest) (*models.TransaksiResponse, error) {
+	template, err := s.ambilTemplate(id, idKoperasi)
+	if err != nil {
+		return nil, err
+	}
+	if !template.StatusAktif {
+		return nil, errors.New("template jurnal tidak aktif")
+	}
+
+	return s.transaksiService.BuatTransaksi(idKoperasi, idPengguna, requestJurnalTemplate(template, req.TanggalTransaksi, req.NomorReferensi))
+}
+
+// ambilTemplate mengambil template jurnal milik koperasi beserta baris dan akunnya
+func (s *TemplateJurnalService) ambilTemplate(id, idKoperasi uuid.UUID) (*models.TemplateJurnal, error) {
+	var template models.TemplateJurnal
+	err := s.preloadBaris(s.db).
+		Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+		First(&template).Error
+	if err != nil {
+		return nil, errors.New("template jurnal tidak ditemukan")
+	}
+
+	return &template, nil
+}
+
+// preloadBaris memuat baris template sesuai urutan beserta akunnya
+func (s *TemplateJurnalService) preloadBaris(db *gorm.DB) *gorm.DB {
+	return db.Preload("Baris", func(db *gorm.DB) *gorm.DB {
+		return db.Order("urutan ASC")
+	}).Preload("Baris.Akun")
+}
+
+// requestJurnalTemplate menyusun request BuatTransaksi dari baris template
+func requestJurnalTemplate(template *models.TemplateJurnal, tanggal time.Time, nomorReferensi string) *BuatTransaksiRequest {
+	barisTransaksi := make([]BuatBarisTransaksiRequest, len(template.Baris))
+	for i, baris := range template.Baris {
+		barisTransaksi[i] = BuatBarisTransaksiRequest{
+			IDAkun:       baris.IDAkun,
+			JumlahDebit:  baris.JumlahDebit,
+			JumlahKredit: baris.JumlahKredit,
+			Keterangan:   baris.Keterangan,
+			IDUnitUsaha:  baris.IDUnitUsaha,
+		}
+	}
+
+	return &BuatTransaksiRequest{
+		TanggalTransaksi: tanggal,
+		Deskripsi:        template.Deskripsi,
+		NomorReferensi:   nomorReferensi,
+		TipeTransaksi:    models.TipeTransaksiJurnalUmum,
+		BarisTransaksi:   barisTransaksi,
+	}
+}
+
+// nomorReferensiJadwal adalah nomor referensi jurnal berulang, misalnya SEWA-20260131.
+// Nomor ini juga dipakai untuk mendeteksi jatuh tempo yang jurnalnya sudah terlanjur dibuat.
+func nomorReferensiJadwal(template *models.TemplateJurnal, jatuhTempo time.Time) string {
+	return fmt.Sprintf("%s-%s", template.KodeTemplate, jatuhTempo.Format("20060102"))
+}
+
+// jatuhTempoKe menghitung jatuh tempo ke-n (dimulai dari 0) sejak tanggal mulai.
+// Tanggal mengikuti hari tanggal mulai; untuk bulan yang lebih pendek dipakai akhir bulan,
+// sehingga jadwal tanggal 31 tetap jatuh pada 31 Januari, 28/29 Februari, 31 Maret, dan seterusnya.
+func jatuhTempoKe(mulai time.Time, jarakBulan, n int) time.Time {
+	awalBulan := time.Date(mulai.Year(), mulai.Month()+time.Month(jarakBulan*n), 1, 0, 0, 0, 0, time.UTC)
+	hari := mulai.Day()
+	if akhirBulan := awalBulan.AddDate(0, 1, -1).Day(); hari > akhirBulan {
+		hari = akhirBulan
+	}
+	return time.Date(awalBulan.Year(), awalBulan.Month(), hari, 0, 0, 0, 0, time.UTC)
+}
+
+// jatuhTempoBerikutnya mencari jatuh tempo pertama setelah TanggalTerakhir (atau sejak tanggal
+// mulai jika belum pernah diproses). Mengembalikan nil jika template tidak berjadwal atau
+// jadwal sudah melewati tanggal selesai.
+func jatuhTempoBerikutnya(template *models.TemplateJurnal) *time.Time {
+	jarak := template.Frekuensi.JarakBulan()
+	if jarak == 0 || template.TanggalMulai == nil {
+		return nil
+	}
+
+	for n := 0; ; n++ {
+		tanggal := jatuhTempoKe(*template.TanggalMulai, jarak, n)
+		if template.TanggalTerakhir != nil && !tanggal.After(*template.TanggalTerakhir) {
+			continue
+		}
+		if template.TanggalSelesai != nil && tanggal.After(*template.TanggalSelesai) {
+			return nil
+		}
+		return &tanggal
+	}
+}
+
+// PratinjauJurnalBerulang menampilkan jurnal yang akan dibuat scheduler sampai tanggal tertentu,
+// termasuk jatuh tempo yang sudah lewat tetapi belum diproses. Diurutkan berdasarkan tanggal.
+func (s *TemplateJurnalService) PratinjauJurnalBerulang(idKoperasi uuid.UUID, sampai time.Time) ([]PratinjauJurnalBerulang, error) {
+	if sampai.After(hariIniUTC().AddDate(1, 0, 0)) {
+		return nil, errors.New("pratinjau paling jauh satu tahun ke depan")
+	}
+
+	var templateList []models.TemplateJurnal
+	err := s.preloadBaris(s.db).
+		Where("id_koperasi = ? AND status_aktif = ? AND tanggal_berikutnya IS NOT NULL AND tanggal_berikutnya <= ?",
+			idKoperasi, true, sampai.Format("2006-01-02")).
+		Find(&templateList).Error
+	if err != nil {
+		return nil, errors.New("gagal mengambil jadwal jurnal berulang")
+	}
+
+	var hasil []PratinjauJurnalBerulang
+	for i := range templateList {
+		template := templateList[i]
+		response := template.ToResponse()
+
+		status := models.StatusJurnalDraft
+		if template.PostingLangsung {
+			if status, err = statusAwalJurnalWithTx(s.db, template.DiperbaruiOleh); err != nil {
+				return nil, err
+			}
+		}
+
+		for jatuhTempo := template.TanggalBerikutnya; jatuhTempo != nil && !jatuhTempo.After(sampai); jatuhTempo = jatuhTempoBerikutnya(&template) {
+			hasil = append(hasil, PratinjauJurnalBerulang{
+				IDTemplateJurnal: template.ID,
+				KodeTemplate:     template.KodeTemplate,
+				NamaTemplate:     template.NamaTemplate,
+				TanggalTransaksi: *jatuhTempo,
+				Deskripsi:        template.Deskripsi,
+				NomorReferensi:   nomorReferensiJadwal(&template, *jatuhTempo),
+				Status:           status,
+				TotalDebit:       template.TotalDebit,
+				BarisTransaksi:   response.Baris,
+			})
+			template.TanggalTerakhir = jatuhTempo
+		}
+	}
+
+	sort.SliceStable(hasil, func(i, j int) bool {
+		if !hasil[i].TanggalTransaksi.Equal(hasil[j].TanggalTransaksi) {
+			return hasil[i].TanggalTransaksi.Before(hasil[j].TanggalTransaksi)
+		}
+		return hasil[i].KodeTemplate < hasil[j].KodeTemplate
+	})
+
+	return hasil, nil
+}
+
+// BuatJurnalBerulangOtomatis membuat jurnal untuk setiap jadwal yang sudah jatuh tempo di semua
+// koperasi, termasuk jatuh tempo yang tertinggal. Jatuh tempo di periode yang sudah ditutup
+// dilewati; kegagalan lain dicatat ke log dan dicoba lagi pada putaran berikutnya.
+func (s *TemplateJurnalService) BuatJurnalBerulangOtomatis() {
+	var templateList []models.TemplateJurnal
+	err := s.db.Where("status_aktif = ? AND tanggal_berikutnya IS NOT NULL AND tanggal_berikutnya <= ?",
+		true, hariIniUTC().Format("2006-01-02")).
+		Order("tanggal_berikutnya ASC").
+		Find(&templateList).Error
+	if err != nil {
+		log.Printf("Jurnal berulang otomatis: gagal mengambil jadwal: %v", err)
+		return
+	}
+
+	for _, template := range templateList {
+		if err := s.prosesJadwalTemplate(template.ID); err != nil {
+			log.Printf("Jurnal berulang otomatis: gagal memproses template %s (%s): %v", template.KodeTemplate, template.ID, err)
+		}
+	}
+}
+
+// prosesJadwalTemplate membuat jurnal untuk seluruh jatuh tempo template yang sudah lewat.
+// Template dikunci selama proses agar scheduler yang berjalan bersamaan tidak membuat jurnal ganda.
+func (s *TemplateJurnalService) prosesJadwalTemplate(id uuid.UUID) error {
+	hariIni := hariIniUTC()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var template models.TemplateJurnal
+		err := s.preloadBaris(tx.Clauses(clause.Locking{Strength: "UPDATE"})).
+			Where("id = ?", id).
+			First(&template).Error
+		if err != nil {
+			return errors.New("template jurnal tidak ditemukan")
+		}
+
+		for template.StatusAktif && template.TanggalBerikutnya != nil && !template.TanggalBerikutnya.After(hariIni) {
+			jatuhTempo := *template.TanggalBerikutnya
+
+			idTransaksi, err := s.buatJurnalJatuhTempoWithTx(tx, &template, jatuhTempo)
+			if err != nil && !errors.Is(err, ErrPeriodeDitutup) {
+				return err
+			}
+			if err != nil {
+				log.Printf("Jurnal berulang otomatis: template %s jatuh tempo %s dilewati: %v",
+					template.KodeTemplate, jatuhTempo.Format("2006-01-02"), err)
+			}
+
+			template.TanggalTerakhir = &jatuhTempo
+			template.TanggalBerikutnya = jatuhTempoBerikutnya(&template)
+			updates := map[string]interface{}{
+				"tanggal_terakhir":   template.TanggalTerakhir,
+				"tanggal_berikutnya": template.TanggalBerikutnya,
+			}
+			if idTransaksi != nil {
+				updates["id_transaksi_terakhir"] = *idTransaksi
+			}
+			if updateErr := tx.Model(&template).Updates(updates).Error; updateErr != nil {
+				return errors.New("gagal memperbarui jadwal template jurnal")
+			}
+		}
+		return nil
+	})
+}
+
+// buatJurnalJatuhTempoWithTx membuat jurnal satu jatuh tempo dalam transaction template, sehingga
+// jurnal dan jadwal berikutnya tersimpan bersama. Jurnal dibuat atas nama pengguna yang terakhir
+// menyimpan template: langsung di-post jika template mengaktifkan posting langsung, selain itu
+// sebagai DRAFT yang mengikuti alur persetujuan biasa dengan pengguna tersebut sebagai pembuat.
+func (s *TemplateJurnalService) buatJurnalJatuhTempoWithTx(tx *gorm.DB, template *models.TemplateJurnal, jatuhTempo time.Time) (*uuid.UUID, error) {
+	nomorReferensi := nomorReferensiJadwal(template, jatuhTempo)
+
+	// Jurnal yang sudah terlanjur dibuat (misalnya proses sebelumnya terhenti) tidak dibuat ulang
+	var sudahAda models.Transaksi
+	err := tx.Where("id_koperasi = ? AND nomor_referensi = ? AND tipe_transaksi = ?",
+		template.IDKoperasi, nomorReferensi, models.TipeTransaksiJurnalUmum).
+		First(&sudahAda).Error
+	if err == nil {
+		return &sudahAda.ID, nil
+	}
+
+	transaksi, err := s.transaksiService.buatTransaksiManualWithTx(tx, template.IDKoperasi, template.DiperbaruiOleh,
+		requestJurnalTemplate(template, jatuhTempo, nomorReferensi), !template.PostingLangsung)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaksi.ID, nil
+}
+
+// MulaiJurnalBerulangOtomatis menjalankan BuatJurnalBerulangOtomatis secara berkala di background.
+// Panggil fungsi stop yang dikembalikan untuk menghentikan goroutine.
+func (s *TemplateJurnalService) MulaiJurnalBerulangOtomatis(interval time.Duration) (stop func()) {
+	stopChan := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.BuatJurnalBerulangOtomatis()
+		for {
+			select {
+			case <-ticker.C:
+				s.BuatJurnalBerulangOtomatis()
+			case <-stopChan:
+				return // Graceful shutdown
+			}
+		}
+	}()
+
+	return func() { close(stopChan) }
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJatuhTempoJurnalBerulang(t *testing.T) {
+	mulai := mustParseTime("2026-01-31")
+
+	// Tanggal 31 dibatasi ke akhir bulan tanpa menggeser jatuh tempo berikutnya
+	assert.Equal(t, mustParseTime("2026-02-28"), jatuhTempoKe(mulai, 1, 1))
+	assert.Equal(t, mustParseTime("2026-03-31"), jatuhTempoKe(mulai, 1, 2))
+	assert.Equal(t, mustParseTime("2026-04-30"), jatuhTempoKe(mulai, 3, 1))
+	assert.Equal(t, mustParseTime("2027-01-31"), jatuhTempoKe(mulai, 3, 4))
+
+	selesai := mustParseTime("2026-07-31")
+	template := &models.TemplateJurnal{
+		Frekuensi:      models.FrekuensiTriwulanan,
+		TanggalMulai:   &mulai,
+		TanggalSelesai: &selesai,
+	}
+	require.NotNil(t, jatuhTempoBerikutnya(template))
+	assert.Equal(t, mulai, *jatuhTempoBerikutnya(template))
+
+	terakhir := mustParseTime("2026-04-30")
+	template.TanggalTerakhir = &terakhir
+	require.NotNil(t, jatuhTempoBerikutnya(template))
+	assert.Equal(t, mustParseTime("2026-07-31"), *jatuhTempoBerikutnya(template))
+
+	// Jadwal selesai setelah jatuh tempo terakhir sebelum tanggal selesai
+	terakhir = mustParseTime("2026-07-31")
+	assert.Nil(t, jatuhTempoBerikutnya(template))
+
+	// Template tanpa frekuensi tidak pernah jatuh tempo
+	assert.Nil(t, jatuhTempoBerikutnya(&models.TemplateJurnal{TanggalMulai: &mulai}))
+}
+
+func TestTemplateJurnalService(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.SaldoBulananAkun{},
+		&models.UnitUsaha{},
+		&models.TemplateJurnal{},
+		&models.BarisTemplateJurnal{},
+	)
+	require.NoError(t, err)
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Template Jurnal Koperasi", Alamat: "Test Address", TahunBukuMulai: 1}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	bebanGaji, _ := akunService.DapatkanAkunByKode(koperasi.ID, "5101")
+	bebanListrik, _ := akunService.DapatkanAkunByKode(koperasi.ID, "5102")
+
+	transaksiService := NewTransaksiService(db)
+	templateJurnalService := NewTemplateJurnalService(db, transaksiService)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+	bendahara := buatPenggunaTest(t, db, koperasi.ID, models.PeranBendahara)
+
+	hariIni := hariIniUTC()
+	duaBulanLalu := hariIni.AddDate(0, -2, 0)
+	barisGaji := []BuatBarisTransaksiRequest{
+		{IDAkun: bebanGaji.ID, JumlahDebit: models.Rupiah(5000000)},
+		{IDAkun: kas.ID, JumlahKredit: models.Rupiah(5000000)},
+	}
+
+	t.Run("validasi template", func(t *testing.T) {
+		_, err := templateJurnalService.BuatTemplateJurnal(koperasi.ID, admin, &SimpanTemplateJurnalRequest{
+			KodeTemplate: "TIDAK-SEIMBANG", NamaTemplate: "Tidak Seimbang", Deskripsi: "Jurnal tidak seimbang",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: bebanGaji.ID, JumlahDebit: models.Rupiah(100)},
+				{IDAkun: kas.ID, JumlahKredit: models.Rupiah(90)},
+			},
+		})
+		assert.Error(t, err, "debit dan kredit template harus seimbang")
+
+		_, err = templateJurnalService.BuatTemplateJurnal(koperasi.ID, bendahara, &SimpanTemplateJurnalRequest{
+			KodeTemplate: "GAJI-B", NamaTemplate: "Gaji Bendahara", Deskripsi: "Pembayaran gaji karyawan",
+			Frekuensi: models.FrekuensiBulanan, TanggalMulai: &hariIni, PostingLangsung: true,
+			BarisTransaksi: barisGaji,
+		})
+		assert.Error(t, err, "hanya admin yang dapat menjadwalkan jurnal yang langsung di-post")
+	})
+
+	gaji, err := templateJurnalService.BuatTemplateJurnal(koperasi.ID, admin, &SimpanTemplateJurnalRequest{
+		KodeTemplate:    "GAJI",
+		NamaTemplate:    "Gaji Karyawan",
+		Deskripsi:       "Pembayaran gaji karyawan",
+		Frekuensi:       models.FrekuensiBulanan,
+		TanggalMulai:    &duaBulanLalu,
+		PostingLangsung: true,
+		BarisTransaksi:  barisGaji,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, gaji.TanggalBerikutnya)
+	assert.True(t, gaji.TanggalBerikutnya.Equal(duaBulanLalu))
+
+	listrik, err := templateJurnalService.BuatTemplateJurnal(koperasi.ID, bendahara, &SimpanTemplateJurnalRequest{
+		KodeTemplate: "LISTRIK",
+		NamaTemplate: "Listrik Kantor",
+		Deskripsi:    "Pembayaran listrik kantor",
+		Frekuensi:    models.FrekuensiTriwulanan,
+		TanggalMulai: &hariIni,
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: bebanListrik.ID, JumlahDebit: models.Rupiah(750000)},
+			{IDAkun: kas.ID, JumlahKredit: models.Rupiah(750000)},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("pratinjau jurnal berulang", func(t *testing.T) {
+		pratinjau, err := templateJurnalService.PratinjauJurnalBerulang(koperasi.ID, hariIni.AddDate(0, 3, 0))
+		require.NoError(t, err)
+
+		jumlah := map[string]int{}
+		for _, item := range pratinjau {
+			jumlah[item.KodeTemplate]++
+			if item.KodeTemplate == "LISTRIK" {
+				assert.Equal(t, models.StatusJurnalDraft, item.Status)
+			} else {
+				assert.Equal(t, models.StatusJurnalPosted, item.Status)
+			}
+		}
+		assert.Equal(t, 6, jumlah["GAJI"], "dua bulan tertunda, bulan ini, dan tiga bulan ke depan")
+		assert.Equal(t, 2, jumlah["LISTRIK"])
+		assert.True(t, pratinjau[0].TanggalTransaksi.Equal(duaBulanLalu))
+	})
+
+	t.Run("scheduler membuat jurnal jatuh tempo tanpa duplikasi", func(t *testing.T) {
+		templateJurnalService.BuatJurnalBerulangOtomatis()
+		templateJurnalService.BuatJurnalBerulangOtomatis()
+
+		var jurnalGaji []models.Transaksi
+		db.Where("id_koperasi = ? AND nomor_referensi LIKE ?", koperasi.ID, "GAJI-%").Find(&jurnalGaji)
+		require.Len(t, jurnalGaji, 3)
+		for _, jurnal := range jurnalGaji {
+			assert.Equal(t, models.StatusJurnalPosted, jurnal.Status)
+			assert.Equal(t, admin, jurnal.DibuatOleh)
+		}
+
+		var jurnalListrik models.Transaksi
+		require.NoError(t, db.Where("id_koperasi = ? AND nomor_referensi = ?", koperasi.ID, nomorReferensiJadwal(&models.TemplateJurnal{KodeTemplate: "LISTRIK"}, hariIni)).
+			First(&jurnalListrik).Error)
+		assert.Equal(t, models.StatusJurnalDraft, jurnalListrik.Status)
+		assert.Equal(t, bendahara, jurnalListrik.DibuatOleh, "pembuat draft dicatat untuk maker-checker")
+
+		detail, err := templateJurnalService.DapatkanTemplateJurnal(listrik.ID, koperasi.ID)
+		require.NoError(t, err)
+		require.NotNil(t, detail.TanggalBerikutnya)
+		assert.True(t, detail.TanggalBerikutnya.Equal(jatuhTempoKe(hariIni, 3, 1)))
+		assert.Equal(t, &jurnalListrik.ID, detail.IDTransaksiTerakhir)
+	})
+
+	t.Run("jurnal manual dari template mengikuti peran pengguna", func(t *testing.T) {
+		jurnal, err := templateJurnalService.BuatJurnalDariTemplate(gaji.ID, koperasi.ID, bendahara, &GunakanTemplateJurnalRequest{
+			TanggalTransaksi: hariIni,
+			NomorReferensi:   "BONUS-01",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusJurnalDraft, jurnal.Status)
+		assert.Equal(t, models.Rupiah(5000000), jurnal.TotalDebit)
+	})
+}
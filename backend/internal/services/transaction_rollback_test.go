@@ -25,6 +25,9 @@ func setupTestDBForRollback(t *testing.T) *gorm.DB {
 		&models.Simpanan{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 		&models.Akun{},
 		&models.Produk{},
 		&models.Penjualan{},
@@ -89,7 +92,7 @@ func TestSimpananService_CatatSetoran_RollbackOnPostingFailure(t *testing.T) {
 		IDAnggota:        anggota.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    100000,
+		JumlahSetoran:    models.Rupiah(100000),
 		Keterangan:       "Test setoran",
 	}
 
@@ -190,7 +193,7 @@ func TestSimpananService_CatatSetoran_CommitOnSuccess(t *testing.T) {
 		IDAnggota:        anggota.ID,
 		TipeSimpanan:     models.SimpananPokok,
 		TanggalTransaksi: time.Now(),
-		JumlahSetoran:    100000,
+		JumlahSetoran:    models.Rupiah(100000),
 		Keterangan:       "Test setoran",
 	}
 
@@ -270,8 +273,8 @@ func TestPenjualanService_ProsesPenjualan_RollbackOnPostingFailure(t *testing.T)
 		IDKoperasi:  koperasi.ID,
 		KodeProduk:  "P001",
 		NamaProduk:  "Test Product",
-		Harga:       10000,
-		HargaBeli:   8000,
+		Harga:       models.Rupiah(10000),
+		HargaBeli:   models.Rupiah(8000),
 		Stok:        100,
 		StokMinimum: 10,
 	}
@@ -290,10 +293,10 @@ func TestPenjualanService_ProsesPenjualan_RollbackOnPostingFailure(t *testing.T)
 			{
 				IDProduk:    produk.ID,
 				Kuantitas:   5,
-				HargaSatuan: 10000,
+				HargaSatuan: models.Rupiah(10000),
 			},
 		},
-		JumlahBayar: 50000,
+		JumlahBayar: models.Rupiah(50000),
 	}
 
 	// This should fail because accounts don't exist
@@ -371,8 +374,8 @@ func TestPenjualanService_ProsesPenjualan_CommitOnSuccess(t *testing.T) {
 		IDKoperasi:  koperasi.ID,
 		KodeProduk:  "P001",
 		NamaProduk:  "Test Product",
-		Harga:       10000,
-		HargaBeli:   8000,
+		Harga:       models.Rupiah(10000),
+		HargaBeli:   models.Rupiah(8000),
 		Stok:        100,
 		StokMinimum: 10,
 	}
@@ -400,10 +403,10 @@ func TestPenjualanService_ProsesPenjualan_CommitOnSuccess(t *testing.T) {
 			{
 				IDProduk:    produk.ID,
 				Kuantitas:   5,
-				HargaSatuan: 10000,
+				HargaSatuan: models.Rupiah(10000),
 			},
 		},
-		JumlahBayar: 50000,
+		JumlahBayar: models.Rupiah(50000),
 	}
 
 	result, err := penjualanService.ProsesPenjualan(koperasi.ID, pengguna.ID, req)
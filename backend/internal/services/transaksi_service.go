@@ -1,22 +1,25 @@
 package services
 
 import (
+	"context"
 	"cooperative-erp-lite/internal/models"
 	"cooperative-erp-lite/pkg/validasi"
 	"errors"
 	"fmt"
-	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-const (
-	// EpsilonTolerance adalah toleransi untuk perbandingan floating-point dalam rupiah
-	// 0.01 = 1 sen toleransi untuk menangani masalah floating-point precision
-	EpsilonTolerance = 0.01
-)
+// kondisiJurnalPosted adalah kondisi SQL untuk jurnal yang dihitung dalam saldo dan laporan:
+// sudah di-post dan tidak dihapus. Dipakai pada query yang melakukan JOIN ke tabel transaksi.
+const kondisiJurnalPosted = "transaksi.status = 'POSTED' AND transaksi.tanggal_dihapus IS NULL"
+
+// ErrStatusJurnalTidakSesuai dikembalikan jika jurnal tidak berada pada status yang dibutuhkan
+// oleh operasi (misalnya menyetujui jurnal yang belum diajukan).
+var ErrStatusJurnalTidakSesuai = errors.New("status jurnal tidak sesuai")
 
 // TransaksiService menangani logika bisnis transaksi akuntansi
 type TransaksiService struct {
@@ -28,6 +31,14 @@ func NewTransaksiService(db *gorm.DB) *TransaksiService {
 	return &TransaksiService{db: db}
 }
 
+// DenganKonteks mengembalikan salinan service yang menjalankan query dengan ctx,
+// sehingga log audit mencatat pengguna, alamat IP dan request ID dari request tersebut
+func (s *TransaksiService) DenganKonteks(ctx context.Context) *TransaksiService {
+	salinan := *s
+	salinan.db = s.db.WithContext(ctx)
+	return &salinan
+}
+
 // BuatTransaksiRequest adalah struktur request untuk membuat transaksi
 type BuatTransaksiRequest struct {
 	TanggalTransaksi time.Time                   `json:"tanggalTransaksi" binding:"required"`
@@ -39,112 +50,227 @@ type BuatTransaksiRequest struct {
 
 // BuatBarisTransaksiRequest adalah struktur untuk baris transaksi
 type BuatBarisTransaksiRequest struct {
-	IDAkun       uuid.UUID `json:"idAkun" binding:"required"`
-	JumlahDebit  float64   `json:"jumlahDebit"`
-	JumlahKredit float64   `json:"jumlahKredit"`
-	Keterangan   string    `json:"keterangan"`
+	IDAkun       uuid.UUID   `json:"idAkun" binding:"required"`
+	JumlahDebit  models.Uang `json:"jumlahDebit"`
+	JumlahKredit models.Uang `json:"jumlahKredit"`
+	Keterangan   string      `json:"keterangan"`
+	IDUnitUsaha  *uuid.UUID  `json:"idUnitUsaha"` // Opsional, dimensi unit usaha
 }
 
 // BuatTransaksi membuat jurnal entry baru dengan validasi double-entry
 func (s *TransaksiService) BuatTransaksi(idKoperasi, idPengguna uuid.UUID, req *BuatTransaksiRequest) (*models.TransaksiResponse, error) {
+	// Buat transaksi dengan baris-barisnya dalam satu transaction
+	// IMPORTANT: GenerateNomorJurnal is now called INSIDE the transaction to prevent race conditions
+	var transaksi *models.Transaksi
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var simpanErr error
+		transaksi, simpanErr = s.buatTransaksiManualWithTx(tx, idKoperasi, idPengguna, req, false)
+		return simpanErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload dengan baris transaksi
+	s.db.Preload("BarisTransaksi.Akun").First(transaksi, transaksi.ID)
+
+	response := transaksi.ToResponse()
+	return &response, nil
+}
+
+// BuatTransaksiWithTx membuat jurnal entry baru menggunakan transaction yang diberikan.
+//
+// Method ini dipakai oleh service lain (misalnya SHU) yang perlu menyimpan dokumen sumber
+// dan jurnalnya secara atomik. Validasi yang dijalankan sama dengan BuatTransaksi.
+func (s *TransaksiService) BuatTransaksiWithTx(tx *gorm.DB, idKoperasi, idPengguna uuid.UUID, req *BuatTransaksiRequest) (*models.Transaksi, error) {
+	if err := s.validasiRequestTransaksi(req); err != nil {
+		return nil, err
+	}
+
+	return s.simpanJurnalWithTx(tx, idKoperasi, idPengguna, req)
+}
+
+// buatTransaksiManualWithTx membuat jurnal manual dalam transaction yang sudah ada dengan aturan
+// yang sama seperti BuatTransaksi: tipe jurnal manual, status awal menurut peran pembuat, dan
+// tanggal di periode yang masih terbuka. Jika draft true, jurnal selalu dimulai sebagai DRAFT
+// meskipun pembuatnya admin.
+func (s *TransaksiService) buatTransaksiManualWithTx(tx *gorm.DB, idKoperasi, idPengguna uuid.UUID, req *BuatTransaksiRequest, draft bool) (*models.Transaksi, error) {
+	if err := s.validasiRequestTransaksi(req); err != nil {
+		return nil, err
+	}
+
+	// Jurnal bertipe lain hanya dibuat sistem bersama dokumen sumbernya
+	if err := validasiTipeJurnalManual(req.TipeTransaksi); err != nil {
+		return nil, err
+	}
+
+	// Jurnal umum buatan non-admin harus melalui persetujuan sebelum di-post
+	status := models.StatusJurnalDraft
+	if !draft {
+		var err error
+		if status, err = statusAwalJurnalWithTx(tx, idPengguna); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tolak jurnal yang jatuh di periode yang sudah ditutup
+	if err := cekPeriodeTerbukaWithTx(tx, idKoperasi, req.TanggalTransaksi); err != nil {
+		return nil, err
+	}
+
+	return s.tulisJurnalWithTx(tx, idKoperasi, idPengguna, req, status)
+}
+
+// validasiRequestTransaksi menjalankan validasi business logic untuk request jurnal
+func (s *TransaksiService) validasiRequestTransaksi(req *BuatTransaksiRequest) error {
 	// Initialize validator
 	validator := validasi.Baru()
 
 	// Validasi business logic
 	if err := validator.TanggalTransaksi(req.TanggalTransaksi); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := validator.TeksWajib(req.Deskripsi, "deskripsi", 5, 500); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := validator.TeksOpsional(req.NomorReferensi, "nomor referensi", 50); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := validator.TeksOpsional(req.TipeTransaksi, "tipe transaksi", 50); err != nil {
-		return nil, err
+		return err
+	}
+
+	// Jurnal penutupan hanya dibuat oleh sistem saat tahun buku ditutup
+	if req.TipeTransaksi == models.TipeTransaksiPenutupan {
+		return errors.New("jurnal penutupan hanya dapat dibuat melalui penutupan tahun buku")
 	}
 
 	// Validasi baris transaksi (debit = kredit)
-	if err := s.ValidasiTransaksi(req.BarisTransaksi); err != nil {
+	return s.ValidasiTransaksi(req.BarisTransaksi)
+}
+
+// simpanJurnalWithTx menyimpan header dan baris jurnal dalam transaction yang sudah ada.
+// Request diasumsikan sudah divalidasi oleh pemanggil.
+func (s *TransaksiService) simpanJurnalWithTx(tx *gorm.DB, idKoperasi, idPengguna uuid.UUID, req *BuatTransaksiRequest) (*models.Transaksi, error) {
+	// Tolak jurnal yang jatuh di periode yang sudah ditutup
+	if err := cekPeriodeTerbukaWithTx(tx, idKoperasi, req.TanggalTransaksi); err != nil {
 		return nil, err
 	}
 
+	return s.tulisJurnalWithTx(tx, idKoperasi, idPengguna, req, models.StatusJurnalPosted)
+}
+
+// validasiTipeJurnalManual menolak tipe transaksi selain JURNAL_UMUM pada jurnal yang dibuat
+// atau diubah pengguna. Jurnal bertipe lain hanya dibuat sistem bersama dokumen sumbernya.
+func validasiTipeJurnalManual(tipeTransaksi string) error {
+	switch tipeTransaksi {
+	case "", models.TipeTransaksiJurnalUmum:
+		return nil
+	case models.TipeTransaksiPenutupan:
+		return errors.New("jurnal penutupan hanya dapat dibuat melalui penutupan tahun buku")
+	case models.TipeTransaksiSaldoAwal:
+		return errors.New("jurnal saldo awal hanya dapat dibuat melalui impor saldo awal")
+	default:
+		return fmt.Errorf("jurnal manual harus bertipe %s, jurnal %s dibuat otomatis dari dokumen sumbernya",
+			models.TipeTransaksiJurnalUmum, tipeTransaksi)
+	}
+}
+
+// statusAwalJurnalWithTx menentukan status awal jurnal manual berdasarkan peran pengguna.
+// Jurnal hanya langsung di-post jika dibuat oleh ADMIN; selain itu dimulai sebagai DRAFT
+// dan harus diajukan, disetujui, lalu di-post.
+func statusAwalJurnalWithTx(tx *gorm.DB, idPengguna uuid.UUID) (models.StatusJurnal, error) {
+	var pengguna models.Pengguna
+	err := tx.Where("id = ?", idPengguna).First(&pengguna).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", errors.New("gagal mengambil data pengguna")
+	}
+
+	if err == nil && pengguna.Peran == models.PeranAdmin {
+		return models.StatusJurnalPosted, nil
+	}
+	return models.StatusJurnalDraft, nil
+}
+
+// tulisJurnalWithTx menulis header dan baris jurnal dengan status tertentu tanpa memeriksa
+// periode akuntansi. Hanya dipakai langsung oleh jurnal sistem seperti jurnal penutupan tahun buku.
+func (s *TransaksiService) tulisJurnalWithTx(tx *gorm.DB, idKoperasi, idPengguna uuid.UUID, req *BuatTransaksiRequest, status models.StatusJurnal) (*models.Transaksi, error) {
 	// Hitung total debit dan kredit
-	var totalDebit, totalKredit float64
+	var totalDebit, totalKredit models.Uang
 	for _, baris := range req.BarisTransaksi {
 		totalDebit += baris.JumlahDebit
 		totalKredit += baris.JumlahKredit
 	}
 
-	// Buat transaksi dengan baris-barisnya dalam satu transaction
-	// IMPORTANT: GenerateNomorJurnal is now called INSIDE the transaction to prevent race conditions
-	var transaksi models.Transaksi
+	// Generate nomor jurnal INSIDE transaction with row-level locking
+	// This prevents race conditions when multiple requests come simultaneously
+	nomorJurnal, err := s.generateNomorJurnalInTx(tx, idKoperasi, req.TanggalTransaksi)
+	if err != nil {
+		return nil, err
+	}
 
-	err := s.db.Transaction(func(tx *gorm.DB) error {
-		// Generate nomor jurnal INSIDE transaction with row-level locking
-		// This prevents race conditions when multiple requests come simultaneously
-		nomorJurnal, genErr := s.generateNomorJurnalInTx(tx, idKoperasi, req.TanggalTransaksi)
-		if genErr != nil {
-			return genErr
-		}
+	// Buat header transaksi
+	transaksi := &models.Transaksi{
+		IDKoperasi:       idKoperasi,
+		NomorJurnal:      nomorJurnal,
+		TanggalTransaksi: req.TanggalTransaksi,
+		Deskripsi:        req.Deskripsi,
+		NomorReferensi:   req.NomorReferensi,
+		TipeTransaksi:    req.TipeTransaksi,
+		TotalDebit:       totalDebit,
+		TotalKredit:      totalKredit,
+		StatusBalanced:   true,
+		DibuatOleh:       idPengguna,
+		Status:           status,
+	}
 
-		// Buat header transaksi
-		transaksi = models.Transaksi{
-			IDKoperasi:       idKoperasi,
-			NomorJurnal:      nomorJurnal,
-			TanggalTransaksi: req.TanggalTransaksi,
-			Deskripsi:        req.Deskripsi,
-			NomorReferensi:   req.NomorReferensi,
-			TipeTransaksi:    req.TipeTransaksi,
-			TotalDebit:       totalDebit,
-			TotalKredit:      totalKredit,
-			StatusBalanced:   true,
-			DibuatOleh:       idPengguna,
-		}
+	if createErr := tx.Create(transaksi).Error; createErr != nil {
+		return nil, errors.New("gagal membuat transaksi")
+	}
 
-		if createErr := tx.Create(&transaksi).Error; createErr != nil {
-			return errors.New("gagal membuat transaksi")
+	// Buat baris transaksi
+	for _, barisReq := range req.BarisTransaksi {
+		// Validasi akun exists
+		var akun models.Akun
+		if findErr := tx.Where("id = ? AND id_koperasi = ?", barisReq.IDAkun, idKoperasi).First(&akun).Error; findErr != nil {
+			return nil, fmt.Errorf("akun %s tidak ditemukan", barisReq.IDAkun)
 		}
 
-		// Buat baris transaksi
-		for _, barisReq := range req.BarisTransaksi {
-			// Validasi akun exists
-			var akun models.Akun
-			if findErr := tx.Where("id = ? AND id_koperasi = ?", barisReq.IDAkun, idKoperasi).First(&akun).Error; findErr != nil {
-				return fmt.Errorf("akun %s tidak ditemukan", barisReq.IDAkun)
-			}
-
-			baris := models.BarisTransaksi{
-				IDTransaksi:  transaksi.ID,
-				IDAkun:       barisReq.IDAkun,
-				JumlahDebit:  barisReq.JumlahDebit,
-				JumlahKredit: barisReq.JumlahKredit,
-				Keterangan:   barisReq.Keterangan,
-			}
-
-			if barisErr := tx.Create(&baris).Error; barisErr != nil {
-				return errors.New("gagal membuat baris transaksi")
-			}
+		if unitErr := validasiUnitUsahaWithTx(tx, idKoperasi, barisReq.IDUnitUsaha); unitErr != nil {
+			return nil, unitErr
 		}
 
-		return nil
-	})
+		baris := models.BarisTransaksi{
+			IDTransaksi:  transaksi.ID,
+			IDAkun:       barisReq.IDAkun,
+			JumlahDebit:  barisReq.JumlahDebit,
+			JumlahKredit: barisReq.JumlahKredit,
+			Keterangan:   barisReq.Keterangan,
+			IDUnitUsaha:  barisReq.IDUnitUsaha,
+		}
 
-	if err != nil {
-		return nil, err
+		if barisErr := tx.Create(&baris).Error; barisErr != nil {
+			return nil, errors.New("gagal membuat baris transaksi")
+		}
 	}
 
-	// Reload dengan baris transaksi
-	s.db.Preload("BarisTransaksi.Akun").First(&transaksi, transaksi.ID)
+	// Jurnal yang langsung di-post masuk ke snapshot saldo bulanan
+	if saldoErr := catatMutasiSaldoWithTx(tx, transaksi.ID, 1); saldoErr != nil {
+		return nil, saldoErr
+	}
 
-	response := transaksi.ToResponse()
-	return &response, nil
+	return transaksi, nil
 }
 
-// PerbaruiTransaksi memperbarui transaksi yang sudah ada
+// PerbaruiTransaksi memperbarui jurnal manual yang masih draft.
+//
+// Jurnal yang sudah di-post tidak pernah diubah di tempat; koreksinya dilakukan dengan
+// membalik jurnal tersebut lalu membuat jurnal baru. Tipe transaksi tidak dapat diubah.
 func (s *TransaksiService) PerbaruiTransaksi(id, idKoperasi, idPengguna uuid.UUID, req *BuatTransaksiRequest) (*models.TransaksiResponse, error) {
 	// Initialize validator
 	validator := validasi.Baru()
@@ -166,13 +292,17 @@ func (s *TransaksiService) PerbaruiTransaksi(id, idKoperasi, idPengguna uuid.UUI
 		return nil, err
 	}
 
+	if err := validasiTipeJurnalManual(req.TipeTransaksi); err != nil {
+		return nil, err
+	}
+
 	// Validasi baris transaksi (debit = kredit)
 	if err := s.ValidasiTransaksi(req.BarisTransaksi); err != nil {
 		return nil, err
 	}
 
 	// Hitung total debit dan kredit
-	var totalDebit, totalKredit float64
+	var totalDebit, totalKredit models.Uang
 	for _, baris := range req.BarisTransaksi {
 		totalDebit += baris.JumlahDebit
 		totalKredit += baris.JumlahKredit
@@ -190,6 +320,40 @@ func (s *TransaksiService) PerbaruiTransaksi(id, idKoperasi, idPengguna uuid.UUI
 			return errors.New("gagal mengambil transaksi")
 		}
 
+		if transaksi.TipeTransaksi == models.TipeTransaksiPenutupan {
+			return errors.New("jurnal penutupan tidak dapat diubah")
+		}
+
+		if transaksi.TipeTransaksi == models.TipeTransaksiSaldoAwal {
+			return errors.New("jurnal saldo awal tidak dapat diubah, batalkan saldo awal lalu impor ulang")
+		}
+
+		if tipeJurnalDokumenSumber[transaksi.TipeTransaksi] {
+			return fmt.Errorf("jurnal %s hanya dapat diubah melalui dokumen sumbernya", transaksi.TipeTransaksi)
+		}
+
+		// Jurnal yang sudah dibalik maupun jurnal pembalik tidak boleh diubah
+		if transaksi.Dibalik || transaksi.IDJurnalAsal != nil {
+			return errors.New("jurnal yang terkait pembalikan tidak dapat diubah")
+		}
+
+		// Hanya draft yang dapat diubah; jurnal yang sudah di-post dikoreksi dengan pembalikan
+		switch transaksi.Status {
+		case models.StatusJurnalDraft:
+		case models.StatusJurnalPosted:
+			return fmt.Errorf("%w: jurnal yang sudah di-post tidak dapat diubah, balik jurnal lalu buat jurnal baru", ErrStatusJurnalTidakSesuai)
+		default:
+			return fmt.Errorf("%w: jurnal sedang dalam proses persetujuan", ErrStatusJurnalTidakSesuai)
+		}
+
+		// Tanggal lama maupun tanggal baru tidak boleh berada di periode yang sudah ditutup
+		if periodeErr := cekPeriodeTerbukaWithTx(tx, idKoperasi, transaksi.TanggalTransaksi); periodeErr != nil {
+			return periodeErr
+		}
+		if periodeErr := cekPeriodeTerbukaWithTx(tx, idKoperasi, req.TanggalTransaksi); periodeErr != nil {
+			return periodeErr
+		}
+
 		// Hapus baris transaksi yang lama
 		if deleteErr := tx.Where("id_transaksi = ?", id).Delete(&models.BarisTransaksi{}).Error; deleteErr != nil {
 			return errors.New("gagal menghapus baris transaksi lama")
@@ -199,7 +363,6 @@ func (s *TransaksiService) PerbaruiTransaksi(id, idKoperasi, idPengguna uuid.UUI
 		transaksi.TanggalTransaksi = req.TanggalTransaksi
 		transaksi.Deskripsi = req.Deskripsi
 		transaksi.NomorReferensi = req.NomorReferensi
-		transaksi.TipeTransaksi = req.TipeTransaksi
 		transaksi.TotalDebit = totalDebit
 		transaksi.TotalKredit = totalKredit
 		transaksi.StatusBalanced = true
@@ -217,12 +380,17 @@ func (s *TransaksiService) PerbaruiTransaksi(id, idKoperasi, idPengguna uuid.UUI
 				return fmt.Errorf("akun %s tidak ditemukan", barisReq.IDAkun)
 			}
 
+			if unitErr := validasiUnitUsahaWithTx(tx, idKoperasi, barisReq.IDUnitUsaha); unitErr != nil {
+				return unitErr
+			}
+
 			baris := models.BarisTransaksi{
 				IDTransaksi:  transaksi.ID,
 				IDAkun:       barisReq.IDAkun,
 				JumlahDebit:  barisReq.JumlahDebit,
 				JumlahKredit: barisReq.JumlahKredit,
 				Keterangan:   barisReq.Keterangan,
+				IDUnitUsaha:  barisReq.IDUnitUsaha,
 			}
 
 			if barisErr := tx.Create(&baris).Error; barisErr != nil {
@@ -252,7 +420,7 @@ func (s *TransaksiService) ValidasiTransaksi(barisTransaksi []BuatBarisTransaksi
 		return errors.New("transaksi harus memiliki minimal 2 baris (debit dan kredit)")
 	}
 
-	var totalDebit, totalKredit float64
+	var totalDebit, totalKredit models.Uang
 	hasDebit := false
 	hasKredit := false
 
@@ -269,7 +437,7 @@ func (s *TransaksiService) ValidasiTransaksi(barisTransaksi []BuatBarisTransaksi
 
 		// Validasi jumlah debit jika ada
 		if baris.JumlahDebit > 0 {
-			if err := validator.Jumlah(baris.JumlahDebit, fmt.Sprintf("jumlah debit baris ke-%d", i+1)); err != nil {
+			if err := validator.Jumlah(baris.JumlahDebit.Float64(), fmt.Sprintf("jumlah debit baris ke-%d", i+1)); err != nil {
 				return err
 			}
 			hasDebit = true
@@ -277,7 +445,7 @@ func (s *TransaksiService) ValidasiTransaksi(barisTransaksi []BuatBarisTransaksi
 
 		// Validasi jumlah kredit jika ada
 		if baris.JumlahKredit > 0 {
-			if err := validator.Jumlah(baris.JumlahKredit, fmt.Sprintf("jumlah kredit baris ke-%d", i+1)); err != nil {
+			if err := validator.Jumlah(baris.JumlahKredit.Float64(), fmt.Sprintf("jumlah kredit baris ke-%d", i+1)); err != nil {
 				return err
 			}
 			hasKredit = true
@@ -297,16 +465,14 @@ func (s *TransaksiService) ValidasiTransaksi(barisTransaksi []BuatBarisTransaksi
 		return errors.New("transaksi harus memiliki minimal satu baris debit dan satu baris kredit")
 	}
 
-	// Validasi total tidak boleh nol (menggunakan epsilon untuk floating-point)
-	if totalDebit < EpsilonTolerance {
+	// Validasi total tidak boleh nol
+	if totalDebit <= 0 {
 		return errors.New("total debit dan kredit tidak boleh 0")
 	}
 
-	// Validasi balanced (debit = kredit) dengan epsilon tolerance untuk floating-point precision
-	// Menggunakan math.Abs untuk menghitung selisih absolut dan membandingkan dengan epsilon
-	// Ini mengatasi masalah floating-point arithmetic seperti: 0.1 + 0.1 + 0.1 != 0.3
-	if math.Abs(totalDebit-totalKredit) > EpsilonTolerance {
-		return fmt.Errorf("total debit (%.2f) tidak sama dengan total kredit (%.2f)", totalDebit, totalKredit)
+	// Validasi balanced (debit = kredit); nominal dalam sen sehingga perbandingan eksak
+	if totalDebit != totalKredit {
+		return fmt.Errorf("total debit (%s) tidak sama dengan total kredit (%s)", totalDebit, totalKredit)
 	}
 
 	return nil
@@ -403,7 +569,7 @@ func (s *TransaksiService) GenerateNomorJurnal(idKoperasi uuid.UUID, tanggal tim
 }
 
 // DapatkanSemuaTransaksi mengambil daftar transaksi dengan filter
-func (s *TransaksiService) DapatkanSemuaTransaksi(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir, tipeTransaksi string, page, pageSize int) ([]models.TransaksiResponse, int64, error) {
+func (s *TransaksiService) DapatkanSemuaTransaksi(idKoperasi uuid.UUID, tanggalMulai, tanggalAkhir, tipeTransaksi, status string, page, pageSize int) ([]models.TransaksiResponse, int64, error) {
 	var transaksiList []models.Transaksi
 	var total int64
 
@@ -419,6 +585,9 @@ func (s *TransaksiService) DapatkanSemuaTransaksi(idKoperasi uuid.UUID, tanggalM
 	if tipeTransaksi != "" {
 		query = query.Where("tipe_transaksi = ?", tipeTransaksi)
 	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
 
 	// Count total
 	query.Count(&total)
@@ -444,9 +613,9 @@ func (s *TransaksiService) DapatkanSemuaTransaksi(idKoperasi uuid.UUID, tanggalM
 }
 
 // DapatkanTransaksi mengambil transaksi berdasarkan ID
-func (s *TransaksiService) DapatkanTransaksi(id uuid.UUID) (*models.TransaksiResponse, error) {
+func (s *TransaksiService) DapatkanTransaksi(idKoperasi, id uuid.UUID) (*models.TransaksiResponse, error) {
 	var transaksi models.Transaksi
-	err := s.db.Preload("BarisTransaksi.Akun").Where("id = ?", id).First(&transaksi).Error
+	err := s.db.Preload("BarisTransaksi.Akun").Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&transaksi).Error
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -459,15 +628,295 @@ func (s *TransaksiService) DapatkanTransaksi(id uuid.UUID) (*models.TransaksiRes
 	return &response, nil
 }
 
-// HapusTransaksi menghapus transaksi (soft delete)
-func (s *TransaksiService) HapusTransaksi(id uuid.UUID) error {
-	// Soft delete transaksi (baris transaksi akan cascade delete)
-	err := s.db.Delete(&models.Transaksi{}, id).Error
+// HapusTransaksi menghapus jurnal DRAFT (soft delete).
+//
+// Jurnal yang sudah di-post tidak boleh dihapus agar jejak audit tetap utuh; koreksi dilakukan
+// dengan BalikTransaksi. Jurnal sistem dibatalkan melalui dokumen sumbernya (misalnya
+// pembatalan penjualan atau simpanan).
+func (s *TransaksiService) HapusTransaksi(idKoperasi, id uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var transaksi models.Transaksi
+		if err := tx.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&transaksi).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("transaksi tidak ditemukan")
+			}
+			return errors.New("gagal mengambil transaksi")
+		}
+
+		switch transaksi.Status {
+		case models.StatusJurnalPosted:
+			return errors.New("transaksi sudah di-post, tidak dapat dihapus")
+		case models.StatusJurnalDraft:
+		default:
+			return fmt.Errorf("%w: jurnal sedang dalam proses persetujuan", ErrStatusJurnalTidakSesuai)
+		}
+
+		if err := tx.Where("id_transaksi = ?", transaksi.ID).Delete(&models.BarisTransaksi{}).Error; err != nil {
+			return errors.New("gagal menghapus baris transaksi")
+		}
+		if err := tx.Delete(&transaksi).Error; err != nil {
+			return errors.New("gagal menghapus transaksi")
+		}
+
+		return nil
+	})
+}
+
+// AjukanTransaksi mengajukan jurnal DRAFT untuk disetujui admin
+func (s *TransaksiService) AjukanTransaksi(id, idKoperasi, idPengguna uuid.UUID) (*models.TransaksiResponse, error) {
+	return s.ubahStatusJurnal(id, idKoperasi, models.StatusJurnalDraft, func(tx *gorm.DB, transaksi *models.Transaksi, sekarang time.Time) error {
+		transaksi.Status = models.StatusJurnalDiajukan
+		transaksi.DiajukanOleh = &idPengguna
+		transaksi.TanggalDiajukan = &sekarang
+		return nil
+	})
+}
+
+// SetujuiTransaksi menyetujui jurnal yang sudah diajukan.
+// Penyetuju tidak boleh sama dengan pembuat jurnal (maker-checker).
+func (s *TransaksiService) SetujuiTransaksi(id, idKoperasi, idPengguna uuid.UUID) (*models.TransaksiResponse, error) {
+	return s.ubahStatusJurnal(id, idKoperasi, models.StatusJurnalDiajukan, func(tx *gorm.DB, transaksi *models.Transaksi, sekarang time.Time) error {
+		if transaksi.DibuatOleh == idPengguna {
+			return errors.New("jurnal tidak dapat disetujui oleh pembuatnya sendiri")
+		}
+
+		transaksi.Status = models.StatusJurnalDisetujui
+		transaksi.DisetujuiOleh = &idPengguna
+		transaksi.TanggalDisetujui = &sekarang
+		return nil
+	})
+}
+
+// TolakTransaksiRequest adalah struktur request untuk menolak jurnal yang diajukan
+type TolakTransaksiRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
+// TolakTransaksi mengembalikan jurnal yang diajukan ke DRAFT beserta alasan penolakannya
+func (s *TransaksiService) TolakTransaksi(id, idKoperasi, idPengguna uuid.UUID, req *TolakTransaksiRequest) (*models.TransaksiResponse, error) {
+	validator := validasi.Baru()
+	if err := validator.TeksWajib(req.Alasan, "alasan penolakan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	return s.ubahStatusJurnal(id, idKoperasi, models.StatusJurnalDiajukan, func(tx *gorm.DB, transaksi *models.Transaksi, sekarang time.Time) error {
+		transaksi.Status = models.StatusJurnalDraft
+		transaksi.DitolakOleh = &idPengguna
+		transaksi.TanggalDitolak = &sekarang
+		transaksi.AlasanPenolakan = req.Alasan
+		return nil
+	})
+}
+
+// PostingTransaksi mem-posting jurnal yang sudah disetujui sehingga dihitung dalam saldo dan laporan.
+// Tanggal jurnal harus masih berada di periode yang terbuka.
+func (s *TransaksiService) PostingTransaksi(id, idKoperasi, idPengguna uuid.UUID) (*models.TransaksiResponse, error) {
+	return s.ubahStatusJurnal(id, idKoperasi, models.StatusJurnalDisetujui, func(tx *gorm.DB, transaksi *models.Transaksi, sekarang time.Time) error {
+		if err := cekPeriodeTerbukaWithTx(tx, idKoperasi, transaksi.TanggalTransaksi); err != nil {
+			return err
+		}
+
+		transaksi.Status = models.StatusJurnalPosted
+		transaksi.DipostingOleh = &idPengguna
+		transaksi.TanggalDiposting = &sekarang
+		return nil
+	})
+}
+
+// ubahStatusJurnal menjalankan satu langkah alur persetujuan jurnal.
+// Jurnal dikunci selama perubahan dan harus berada pada statusAsal.
+func (s *TransaksiService) ubahStatusJurnal(id, idKoperasi uuid.UUID, statusAsal models.StatusJurnal, ubah func(tx *gorm.DB, transaksi *models.Transaksi, sekarang time.Time) error) (*models.TransaksiResponse, error) {
+	var transaksi models.Transaksi
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND id_koperasi = ?", id, idKoperasi).
+			First(&transaksi).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("transaksi tidak ditemukan")
+			}
+			return errors.New("gagal mengambil transaksi")
+		}
+
+		if transaksi.Status != statusAsal {
+			return fmt.Errorf("%w: jurnal berstatus %s, seharusnya %s", ErrStatusJurnalTidakSesuai, transaksi.Status, statusAsal)
+		}
+
+		if err := ubah(tx, &transaksi, time.Now()); err != nil {
+			return err
+		}
+
+		if err := tx.Save(&transaksi).Error; err != nil {
+			return errors.New("gagal memperbarui status jurnal")
+		}
+
+		// Jurnal yang baru di-post mulai dihitung dalam snapshot saldo bulanan
+		if statusAsal != models.StatusJurnalPosted && transaksi.Status == models.StatusJurnalPosted {
+			return catatMutasiSaldoWithTx(tx, transaksi.ID, 1)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload dengan baris transaksi
+	s.db.Preload("BarisTransaksi.Akun").First(&transaksi, transaksi.ID)
+
+	response := transaksi.ToResponse()
+	return &response, nil
+}
+
+// BalikTransaksiRequest adalah struktur request untuk membalik jurnal
+type BalikTransaksiRequest struct {
+	TanggalPembalikan *time.Time `json:"tanggalPembalikan"` // Opsional, default hari ini
+	Alasan            string     `json:"alasan" binding:"required"`
+}
+
+// tipeJurnalDokumenSumber adalah tipe jurnal yang dibuat otomatis dari dokumen sumber.
+// Jurnal dengan tipe ini hanya dapat dibalik dengan membatalkan dokumen sumbernya.
+var tipeJurnalDokumenSumber = map[string]bool{
+	models.TipeTransaksiSimpanan:  true,
+	models.TipeTransaksiPenjualan: true,
+	models.TipeTransaksiPinjaman:  true,
+	models.TipeTransaksiSHU:       true,
+	models.TipeTransaksiSaldoAwal: true,
+	models.TipeTransaksiAsetTetap: true,
+	models.TipeTransaksiBank:      true,
+	models.TipeTransaksiPajak:     true,
+}
+
+// BalikTransaksi membalik jurnal manual dengan membuat jurnal cermin (debit dan kredit
+// ditukar) yang terhubung ke jurnal asal, lalu menandai jurnal asal sebagai sudah dibalik.
+//
+// Jurnal pembalik dicatat pada tanggal pembalikan (default hari ini) sehingga periode yang
+// sudah ditutup tidak berubah.
+func (s *TransaksiService) BalikTransaksi(id, idKoperasi, idPengguna uuid.UUID, req *BalikTransaksiRequest) (*models.TransaksiResponse, error) {
+	validator := validasi.Baru()
+
+	if err := validator.TeksWajib(req.Alasan, "alasan pembalikan", 5, 500); err != nil {
+		return nil, err
+	}
+
+	tanggal := time.Now()
+	if req.TanggalPembalikan != nil {
+		tanggal = *req.TanggalPembalikan
+	}
+	if err := validator.TanggalTransaksi(tanggal); err != nil {
+		return nil, err
+	}
+
+	var pembalik *models.Transaksi
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var balikErr error
+		pembalik, balikErr = s.balikJurnalWithTx(tx, idKoperasi, idPengguna, id, tanggal, req.Alasan, "")
+		return balikErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload dengan baris transaksi
+	s.db.Preload("BarisTransaksi.Akun").First(pembalik, pembalik.ID)
+
+	response := pembalik.ToResponse()
+	return &response, nil
+}
+
+// balikJurnalWithTx membuat jurnal pembalik untuk jurnal idTransaksi dalam transaction yang sudah ada.
+//
+// tipeSumber kosong berarti pembalikan manual: jurnal dokumen sumber ditolak. Service dokumen
+// sumber (penjualan, simpanan) mengisi tipeSumber dengan tipe jurnal yang dibatalkannya.
+// Jurnal penutupan hanya dibalik oleh penutupan tahun buku (tipeSumber PENUTUPAN) dan, seperti
+// jurnal penutupan itu sendiri, pembaliknya ditulis tanpa pemeriksaan periode.
+func (s *TransaksiService) balikJurnalWithTx(tx *gorm.DB, idKoperasi, idPengguna, idTransaksi uuid.UUID, tanggal time.Time, alasan, tipeSumber string) (*models.Transaksi, error) {
+	// Kunci jurnal asal agar tidak dibalik dua kali secara bersamaan
+	var asal models.Transaksi
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND id_koperasi = ?", idTransaksi, idKoperasi).
+		First(&asal).Error
 	if err != nil {
-		return errors.New("gagal menghapus transaksi")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaksi tidak ditemukan")
+		}
+		return nil, errors.New("gagal mengambil transaksi")
+	}
+
+	switch {
+	case asal.Status != models.StatusJurnalPosted:
+		return nil, fmt.Errorf("%w: hanya jurnal yang sudah di-post yang dapat dibalik", ErrStatusJurnalTidakSesuai)
+	case asal.IDJurnalAsal != nil:
+		return nil, errors.New("jurnal pembalik tidak dapat dibalik")
+	case asal.Dibalik:
+		return nil, errors.New("transaksi sudah dibalik")
+	case asal.TipeTransaksi == models.TipeTransaksiPenutupan && tipeSumber != models.TipeTransaksiPenutupan:
+		return nil, errors.New("jurnal penutupan tidak dapat dibalik")
+	case tipeSumber == "" && tipeJurnalDokumenSumber[asal.TipeTransaksi]:
+		return nil, fmt.Errorf("jurnal %s hanya dapat dibatalkan melalui dokumen sumbernya", asal.TipeTransaksi)
+	case tipeSumber != "" && asal.TipeTransaksi != tipeSumber:
+		return nil, fmt.Errorf("jurnal %s bukan jurnal %s", asal.NomorJurnal, tipeSumber)
+	}
+
+	if tanggal.Format("2006-01-02") < asal.TanggalTransaksi.Format("2006-01-02") {
+		return nil, errors.New("tanggal pembalikan tidak boleh sebelum tanggal jurnal asal")
+	}
+
+	var barisAsal []models.BarisTransaksi
+	if findErr := tx.Where("id_transaksi = ?", asal.ID).Find(&barisAsal).Error; findErr != nil {
+		return nil, errors.New("gagal mengambil baris transaksi")
+	}
+
+	// Jurnal cermin: debit dan kredit setiap baris ditukar
+	barisPembalik := make([]BuatBarisTransaksiRequest, len(barisAsal))
+	for i, baris := range barisAsal {
+		barisPembalik[i] = BuatBarisTransaksiRequest{
+			IDAkun:       baris.IDAkun,
+			JumlahDebit:  baris.JumlahKredit,
+			JumlahKredit: baris.JumlahDebit,
+			Keterangan:   baris.Keterangan,
+			IDUnitUsaha:  baris.IDUnitUsaha,
+		}
 	}
 
-	return nil
+	reqPembalik := &BuatTransaksiRequest{
+		TanggalTransaksi: tanggal,
+		Deskripsi:        fmt.Sprintf("Pembalikan %s: %s", asal.NomorJurnal, alasan),
+		NomorReferensi:   asal.NomorJurnal,
+		TipeTransaksi:    asal.TipeTransaksi,
+		BarisTransaksi:   barisPembalik,
+	}
+	var pembalik *models.Transaksi
+	if asal.TipeTransaksi == models.TipeTransaksiPenutupan {
+		pembalik, err = s.tulisJurnalWithTx(tx, idKoperasi, idPengguna, reqPembalik, models.StatusJurnalPosted)
+	} else {
+		pembalik, err = s.simpanJurnalWithTx(tx, idKoperasi, idPengguna, reqPembalik)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Hubungkan jurnal pembalik dan jurnal asal
+	pembalik.IDJurnalAsal = &asal.ID
+	pembalik.AlasanPembalikan = alasan
+	if linkErr := tx.Model(&models.Transaksi{}).Where("id = ?", pembalik.ID).
+		UpdateColumns(map[string]interface{}{"id_jurnal_asal": asal.ID, "alasan_pembalikan": alasan}).Error; linkErr != nil {
+		return nil, errors.New("gagal menghubungkan jurnal pembalik")
+	}
+
+	if markErr := tx.Model(&models.Transaksi{}).Where("id = ?", asal.ID).
+		UpdateColumns(map[string]interface{}{
+			"dibalik":            true,
+			"id_jurnal_pembalik": pembalik.ID,
+			"alasan_pembalikan":  alasan,
+			"diperbarui_oleh":    idPengguna,
+		}).Error; markErr != nil {
+		return nil, errors.New("gagal menandai jurnal asal sebagai dibalik")
+	}
+
+	return pembalik, nil
 }
 
 // DapatkanBukuBesar mengambil buku besar (ledger) untuk akun tertentu
@@ -482,7 +931,8 @@ func (s *TransaksiService) DapatkanBukuBesar(idAkun uuid.UUID, tanggalMulai, tan
 	// Query baris transaksi untuk akun ini
 	var barisTransaksiList []models.BarisTransaksi
 	query := s.db.Preload("Transaksi").Where("id_akun = ?", idAkun).
-		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi")
+		Joins("JOIN transaksi ON transaksi.id = baris_transaksi.id_transaksi").
+		Where(kondisiJurnalPosted)
 
 	if tanggalMulai != "" {
 		query = query.Where("transaksi.tanggal_transaksi >= ?", tanggalMulai)
@@ -497,7 +947,7 @@ func (s *TransaksiService) DapatkanBukuBesar(idAkun uuid.UUID, tanggalMulai, tan
 	}
 
 	// Format response dengan running balance
-	var saldo float64
+	var saldo models.Uang
 	ledger := make([]map[string]interface{}, 0)
 
 	for _, baris := range barisTransaksiList {
@@ -523,148 +973,125 @@ func (s *TransaksiService) DapatkanBukuBesar(idAkun uuid.UUID, tanggalMulai, tan
 	return ledger, nil
 }
 
-// PostingOtomatisSimpanan membuat jurnal otomatis untuk setoran simpanan
+// PostingOtomatisSimpanan membuat jurnal otomatis untuk setoran atau penarikan simpanan
+// dalam transaction tersendiri. Lihat PostingOtomatisSimpananWithTx.
 func (s *TransaksiService) PostingOtomatisSimpanan(idKoperasi, idPengguna, idSimpanan uuid.UUID) error {
-	// Ambil data simpanan
-	var simpanan models.Simpanan
-	err := s.db.Where("id = ?", idSimpanan).First(&simpanan).Error
-	if err != nil {
-		return errors.New("simpanan tidak ditemukan")
-	}
-
-	// Tentukan akun modal berdasarkan tipe simpanan
-	var kodeAkunModal string
-	switch simpanan.TipeSimpanan {
-	case models.SimpananPokok:
-		kodeAkunModal = "3101" // Simpanan Pokok
-	case models.SimpananWajib:
-		kodeAkunModal = "3102" // Simpanan Wajib
-	case models.SimpananSukarela:
-		kodeAkunModal = "3103" // Simpanan Sukarela
-	}
-
-	// Dapatkan akun kas dan akun modal
-	var akunKas, akunModal models.Akun
-	if kasErr := s.db.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "1101").First(&akunKas).Error; kasErr != nil {
-		return errors.New("akun kas tidak ditemukan")
-	}
-	if modalErr := s.db.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, kodeAkunModal).First(&akunModal).Error; modalErr != nil {
-		return errors.New("akun modal tidak ditemukan")
-	}
-
-	// Buat jurnal entry
-	req := &BuatTransaksiRequest{
-		TanggalTransaksi: simpanan.TanggalTransaksi,
-		Deskripsi:        fmt.Sprintf("Setoran %s", simpanan.TipeSimpanan),
-		NomorReferensi:   simpanan.NomorReferensi,
-		TipeTransaksi:    models.TipeTransaksiSimpanan,
-		BarisTransaksi: []BuatBarisTransaksiRequest{
-			{
-				IDAkun:      akunKas.ID,
-				JumlahDebit: simpanan.JumlahSetoran,
-				Keterangan:  fmt.Sprintf("Setoran %s", simpanan.TipeSimpanan),
-			},
-			{
-				IDAkun:       akunModal.ID,
-				JumlahKredit: simpanan.JumlahSetoran,
-				Keterangan:   fmt.Sprintf("Setoran %s", simpanan.TipeSimpanan),
-			},
-		},
-	}
-
-	transaksi, err := s.BuatTransaksi(idKoperasi, idPengguna, req)
-	if err != nil {
-		return fmt.Errorf("gagal posting simpanan: %w", err)
-	}
-
-	// Update simpanan dengan ID transaksi
-	simpanan.IDTransaksi = &transaksi.ID
-	s.db.Save(&simpanan)
-
-	return nil
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.PostingOtomatisSimpananWithTx(tx, idKoperasi, idPengguna, idSimpanan)
+	})
 }
 
-// PostingOtomatisPenjualan membuat jurnal otomatis untuk penjualan
+// PostingOtomatisPenjualan membuat jurnal otomatis untuk penjualan dalam transaction
+// tersendiri. Lihat PostingOtomatisPenjualanWithTx.
 func (s *TransaksiService) PostingOtomatisPenjualan(idKoperasi, idPengguna, idPenjualan uuid.UUID) error {
-	// Ambil data penjualan dengan items
-	var penjualan models.Penjualan
-	err := s.db.Preload("ItemPenjualan.Produk").Where("id = ?", idPenjualan).First(&penjualan).Error
-	if err != nil {
-		return errors.New("penjualan tidak ditemukan")
-	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.PostingOtomatisPenjualanWithTx(tx, idKoperasi, idPengguna, idPenjualan)
+	})
+}
 
-	// Dapatkan akun-akun yang diperlukan
-	var akunKas, akunPenjualan, akunHPP, akunPersediaan models.Akun
-	if kasErr := s.db.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "1101").First(&akunKas).Error; kasErr != nil {
-		return errors.New("akun kas tidak ditemukan")
-	}
-	if penjualanErr := s.db.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "4101").First(&akunPenjualan).Error; penjualanErr != nil {
-		return errors.New("akun penjualan tidak ditemukan")
-	}
-	if hppErr := s.db.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "5201").First(&akunHPP).Error; hppErr != nil {
-		return errors.New("akun HPP tidak ditemukan")
+// akunPPNPenjualanWithTx meresolusi akun PPN keluaran hanya jika penjualan memungut PPN,
+// sehingga koperasi non-PKP tidak wajib memiliki akun tersebut
+func akunPPNPenjualanWithTx(tx *gorm.DB, idKoperasi uuid.UUID, penjualan *models.Penjualan) (uuid.UUID, error) {
+	var totalPPN models.Uang
+	for _, item := range penjualan.ItemPenjualan {
+		totalPPN += item.PPN
 	}
-	if persediaanErr := s.db.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "1301").First(&akunPersediaan).Error; persediaanErr != nil {
-		return errors.New("akun persediaan tidak ditemukan")
+	if totalPPN == 0 {
+		return uuid.Nil, nil
 	}
 
-	// Hitung total HPP
-	var totalHPP float64
-	for _, item := range penjualan.ItemPenjualan {
-		totalHPP += item.Produk.HargaBeli * float64(item.Kuantitas)
+	akunPosting, err := akunPostingWithTx(tx, idKoperasi, models.PeristiwaPPNKeluaran)
+	if err != nil {
+		return uuid.Nil, err
 	}
+	return akunPosting[models.PeranAkunPPNKeluaran].ID, nil
+}
 
-	// Buat baris transaksi
-	barisTransaksi := []BuatBarisTransaksiRequest{
-		// Kas bertambah (debit)
-		{
-			IDAkun:      akunKas.ID,
-			JumlahDebit: penjualan.TotalBelanja,
-			Keterangan:  "Penerimaan kas dari penjualan",
-		},
-		// Penjualan bertambah (kredit)
-		{
-			IDAkun:       akunPenjualan.ID,
-			JumlahKredit: penjualan.TotalBelanja,
-			Keterangan:   "Pendapatan penjualan",
-		},
+// barisJurnalPenjualan menyusun baris jurnal penjualan: Kas (debit) / Penjualan dan PPN Keluaran
+// (kredit) serta HPP (debit) / Persediaan (kredit). Baris dipisah per unit usaha produk agar
+// pendapatan dan HPP setiap unit usaha dapat dilaporkan sendiri; urutan unit mengikuti urutan item.
+// Pendapatan item kena PPN dicatat sebesar DPP; idAkunPPN hanya dipakai jika ada PPN.
+func barisJurnalPenjualan(items []models.ItemPenjualan, idAkunKas, idAkunPenjualan, idAkunHPP, idAkunPersediaan, idAkunPPN uuid.UUID) []BuatBarisTransaksiRequest {
+	type nilaiUnit struct {
+		idUnitUsaha *uuid.UUID
+		penjualan   models.Uang
+		ppn         models.Uang
+		hpp         models.Uang
+	}
+
+	var daftarUnit []*nilaiUnit
+	perUnit := make(map[uuid.UUID]*nilaiUnit)
+	for _, item := range items {
+		kunci := uuid.Nil
+		if item.Produk.IDUnitUsaha != nil {
+			kunci = *item.Produk.IDUnitUsaha
+		}
+		nilai, ok := perUnit[kunci]
+		if !ok {
+			nilai = &nilaiUnit{idUnitUsaha: item.Produk.IDUnitUsaha}
+			perUnit[kunci] = nilai
+			daftarUnit = append(daftarUnit, nilai)
+		}
+		if item.PPN > 0 {
+			nilai.penjualan += item.DPP
+			nilai.ppn += item.PPN
+		} else {
+			nilai.penjualan += item.HargaSatuan.Kali(item.Kuantitas)
+		}
+		nilai.hpp += item.Produk.HargaBeli.Kali(item.Kuantitas)
 	}
 
-	// Jika ada HPP, tambahkan jurnal HPP
-	if totalHPP > 0 {
+	var barisTransaksi []BuatBarisTransaksiRequest
+	for _, nilai := range daftarUnit {
 		barisTransaksi = append(barisTransaksi,
+			// Kas bertambah (debit)
 			BuatBarisTransaksiRequest{
-				IDAkun:      akunHPP.ID,
-				JumlahDebit: totalHPP,
-				Keterangan:  "Harga Pokok Penjualan",
+				IDAkun:      idAkunKas,
+				JumlahDebit: nilai.penjualan + nilai.ppn,
+				Keterangan:  "Penerimaan kas dari penjualan",
+				IDUnitUsaha: nilai.idUnitUsaha,
 			},
+			// Penjualan bertambah (kredit)
 			BuatBarisTransaksiRequest{
-				IDAkun:       akunPersediaan.ID,
-				JumlahKredit: totalHPP,
-				Keterangan:   "Pengurangan persediaan",
+				IDAkun:       idAkunPenjualan,
+				JumlahKredit: nilai.penjualan,
+				Keterangan:   "Pendapatan penjualan",
+				IDUnitUsaha:  nilai.idUnitUsaha,
 			},
 		)
-	}
 
-	// Buat jurnal entry
-	req := &BuatTransaksiRequest{
-		TanggalTransaksi: penjualan.TanggalPenjualan,
-		Deskripsi:        fmt.Sprintf("Penjualan %s", penjualan.NomorPenjualan),
-		NomorReferensi:   penjualan.NomorPenjualan,
-		TipeTransaksi:    models.TipeTransaksiPenjualan,
-		BarisTransaksi:   barisTransaksi,
-	}
+		// PPN yang dipungut menjadi utang kepada negara (kredit)
+		if nilai.ppn > 0 {
+			barisTransaksi = append(barisTransaksi,
+				BuatBarisTransaksiRequest{
+					IDAkun:       idAkunPPN,
+					JumlahKredit: nilai.ppn,
+					Keterangan:   "PPN keluaran",
+					IDUnitUsaha:  nilai.idUnitUsaha,
+				},
+			)
+		}
 
-	transaksi, err := s.BuatTransaksi(idKoperasi, idPengguna, req)
-	if err != nil {
-		return fmt.Errorf("gagal posting penjualan: %w", err)
+		// Jika ada HPP, tambahkan jurnal HPP
+		if nilai.hpp > 0 {
+			barisTransaksi = append(barisTransaksi,
+				BuatBarisTransaksiRequest{
+					IDAkun:      idAkunHPP,
+					JumlahDebit: nilai.hpp,
+					Keterangan:  "Harga Pokok Penjualan",
+					IDUnitUsaha: nilai.idUnitUsaha,
+				},
+				BuatBarisTransaksiRequest{
+					IDAkun:       idAkunPersediaan,
+					JumlahKredit: nilai.hpp,
+					Keterangan:   "Pengurangan persediaan",
+					IDUnitUsaha:  nilai.idUnitUsaha,
+				},
+			)
+		}
 	}
 
-	// Update penjualan dengan ID transaksi
-	penjualan.IDTransaksi = &transaksi.ID
-	s.db.Save(&penjualan)
-
-	return nil
+	return barisTransaksi
 }
 
 // PostingOtomatisPenjualanWithTx membuat jurnal entry otomatis untuk penjualan menggunakan transaction yang diberikan.
@@ -682,7 +1109,9 @@ func (s *TransaksiService) PostingOtomatisPenjualan(idKoperasi, idPengguna, idPe
 //
 // Returns error jika:
 //   - Penjualan tidak ditemukan
-//   - Akun-akun yang diperlukan (Kas, Penjualan, HPP, Persediaan) tidak ditemukan
+//   - Akun-akun aturan posting PENJUALAN (Kas, Penjualan, HPP, Persediaan) tidak ditemukan atau tidak aktif
+//   - Penjualan memungut PPN tetapi akun aturan posting PPN_KELUARAN tidak ditemukan atau tidak aktif
+//   - Tanggal penjualan berada di periode yang sudah ditutup (ErrPeriodeDitutup)
 //   - Gagal generate nomor jurnal
 //   - Gagal membuat transaksi atau baris transaksi
 func (s *TransaksiService) PostingOtomatisPenjualanWithTx(tx *gorm.DB, idKoperasi, idPengguna, idPenjualan uuid.UUID) error {
@@ -693,25 +1122,24 @@ func (s *TransaksiService) PostingOtomatisPenjualanWithTx(tx *gorm.DB, idKoperas
 		return errors.New("penjualan tidak ditemukan")
 	}
 
-	// Dapatkan akun-akun yang diperlukan untuk jurnal penjualan
-	var akunKas, akunPenjualan, akunHPP, akunPersediaan models.Akun
-	if kasErr := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "1101").First(&akunKas).Error; kasErr != nil {
-		return errors.New("akun kas tidak ditemukan")
-	}
-	if penjualanErr := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "4101").First(&akunPenjualan).Error; penjualanErr != nil {
-		return errors.New("akun penjualan tidak ditemukan")
-	}
-	if hppErr := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "5201").First(&akunHPP).Error; hppErr != nil {
-		return errors.New("akun HPP tidak ditemukan")
+	// Dapatkan akun-akun yang diperlukan dari aturan posting untuk jurnal penjualan
+	akunPosting, err := akunPostingWithTx(tx, idKoperasi, models.PeristiwaPenjualan)
+	if err != nil {
+		return err
 	}
-	if persediaanErr := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "1301").First(&akunPersediaan).Error; persediaanErr != nil {
-		return errors.New("akun persediaan tidak ditemukan")
+	akunKas, akunPenjualan := akunPosting[models.PeranAkunKas], akunPosting[models.PeranAkunPendapatan]
+	akunHPP, akunPersediaan := akunPosting[models.PeranAkunHPP], akunPosting[models.PeranAkunPersediaan]
+	idAkunPPN, err := akunPPNPenjualanWithTx(tx, idKoperasi, &penjualan)
+	if err != nil {
+		return err
 	}
 
-	// Hitung total HPP
-	var totalHPP float64
-	for _, item := range penjualan.ItemPenjualan {
-		totalHPP += item.Produk.HargaBeli * float64(item.Kuantitas)
+	// Susun baris jurnal per unit usaha produk
+	barisTransaksi := barisJurnalPenjualan(penjualan.ItemPenjualan, akunKas.ID, akunPenjualan.ID, akunHPP.ID, akunPersediaan.ID, idAkunPPN)
+
+	// Tolak posting ke periode yang sudah ditutup
+	if periodeErr := cekPeriodeTerbukaWithTx(tx, idKoperasi, penjualan.TanggalPenjualan); periodeErr != nil {
+		return periodeErr
 	}
 
 	// Generate nomor jurnal dalam transaction yang sama
@@ -721,8 +1149,11 @@ func (s *TransaksiService) PostingOtomatisPenjualanWithTx(tx *gorm.DB, idKoperas
 	}
 
 	// Hitung total debit dan kredit
-	totalDebit := penjualan.TotalBelanja + totalHPP
-	totalKredit := penjualan.TotalBelanja + totalHPP
+	var totalDebit, totalKredit models.Uang
+	for _, baris := range barisTransaksi {
+		totalDebit += baris.JumlahDebit
+		totalKredit += baris.JumlahKredit
+	}
 
 	// Buat header transaksi langsung menggunakan tx
 	transaksi := models.Transaksi{
@@ -743,53 +1174,22 @@ func (s *TransaksiService) PostingOtomatisPenjualanWithTx(tx *gorm.DB, idKoperas
 	}
 
 	// Buat baris transaksi menggunakan tx
-	// 1. Kas bertambah (debit)
-	barisKas := models.BarisTransaksi{
-		IDTransaksi:  transaksi.ID,
-		IDAkun:       akunKas.ID,
-		JumlahDebit:  penjualan.TotalBelanja,
-		JumlahKredit: 0,
-		Keterangan:   "Penerimaan kas dari penjualan",
-	}
-	if kasBarisErr := tx.Create(&barisKas).Error; kasBarisErr != nil {
-		return errors.New("gagal membuat baris kas")
-	}
-
-	// 2. Penjualan bertambah (kredit)
-	barisPenjualan := models.BarisTransaksi{
-		IDTransaksi:  transaksi.ID,
-		IDAkun:       akunPenjualan.ID,
-		JumlahDebit:  0,
-		JumlahKredit: penjualan.TotalBelanja,
-		Keterangan:   "Pendapatan penjualan",
-	}
-	if penjualanBarisErr := tx.Create(&barisPenjualan).Error; penjualanBarisErr != nil {
-		return errors.New("gagal membuat baris penjualan")
-	}
-
-	// 3. Jika ada HPP, tambahkan jurnal HPP
-	if totalHPP > 0 {
-		barisHPP := models.BarisTransaksi{
+	for _, barisReq := range barisTransaksi {
+		baris := models.BarisTransaksi{
 			IDTransaksi:  transaksi.ID,
-			IDAkun:       akunHPP.ID,
-			JumlahDebit:  totalHPP,
-			JumlahKredit: 0,
-			Keterangan:   "Harga Pokok Penjualan",
+			IDAkun:       barisReq.IDAkun,
+			JumlahDebit:  barisReq.JumlahDebit,
+			JumlahKredit: barisReq.JumlahKredit,
+			Keterangan:   barisReq.Keterangan,
+			IDUnitUsaha:  barisReq.IDUnitUsaha,
 		}
-		if hppBarisErr := tx.Create(&barisHPP).Error; hppBarisErr != nil {
-			return errors.New("gagal membuat baris HPP")
+		if barisErr := tx.Create(&baris).Error; barisErr != nil {
+			return errors.New("gagal membuat baris jurnal penjualan")
 		}
+	}
 
-		barisPersediaan := models.BarisTransaksi{
-			IDTransaksi:  transaksi.ID,
-			IDAkun:       akunPersediaan.ID,
-			JumlahDebit:  0,
-			JumlahKredit: totalHPP,
-			Keterangan:   "Pengurangan persediaan",
-		}
-		if persediaanBarisErr := tx.Create(&barisPersediaan).Error; persediaanBarisErr != nil {
-			return errors.New("gagal membuat baris persediaan")
-		}
+	if saldoErr := catatMutasiSaldoWithTx(tx, transaksi.ID, 1); saldoErr != nil {
+		return saldoErr
 	}
 
 	// Update penjualan dengan ID transaksi menggunakan tx
@@ -802,6 +1202,7 @@ func (s *TransaksiService) PostingOtomatisPenjualanWithTx(tx *gorm.DB, idKoperas
 }
 
 // PostingOtomatisSimpananWithTx membuat jurnal entry otomatis untuk simpanan menggunakan transaction yang diberikan.
+// Setoran dijurnal Kas (debit) / Modal Simpanan (kredit), penarikan dijurnal sebaliknya.
 //
 // Method ini dirancang untuk dipanggil dalam transaction yang sama dengan pembuatan simpanan,
 // sehingga memastikan atomicity antara data simpanan dan jurnal akuntansi. Jika terjadi error
@@ -816,7 +1217,8 @@ func (s *TransaksiService) PostingOtomatisPenjualanWithTx(tx *gorm.DB, idKoperas
 // Returns error jika:
 //   - Simpanan tidak ditemukan
 //   - Tipe simpanan tidak valid
-//   - Akun-akun yang diperlukan (Kas, Modal Simpanan) tidak ditemukan
+//   - Akun-akun aturan posting simpanan (Kas, Modal Simpanan) tidak ditemukan atau tidak aktif
+//   - Tanggal simpanan berada di periode yang sudah ditutup (ErrPeriodeDitutup)
 //   - Gagal generate nomor jurnal
 //   - Gagal membuat transaksi atau baris transaksi
 func (s *TransaksiService) PostingOtomatisSimpananWithTx(tx *gorm.DB, idKoperasi, idPengguna, idSimpanan uuid.UUID) error {
@@ -827,26 +1229,28 @@ func (s *TransaksiService) PostingOtomatisSimpananWithTx(tx *gorm.DB, idKoperasi
 		return errors.New("simpanan tidak ditemukan")
 	}
 
-	// Tentukan akun modal berdasarkan tipe simpanan
-	var kodeAkunModal string
-	switch simpanan.TipeSimpanan {
-	case models.SimpananPokok:
-		kodeAkunModal = "3101" // Simpanan Pokok
-	case models.SimpananWajib:
-		kodeAkunModal = "3102" // Simpanan Wajib
-	case models.SimpananSukarela:
-		kodeAkunModal = "3103" // Simpanan Sukarela
-	default:
+	// Tentukan akun kas dan akun modal dari aturan posting tipe simpanan menggunakan tx
+	peristiwa, ok := peristiwaSimpanan[simpanan.TipeSimpanan]
+	if !ok {
 		return errors.New("tipe simpanan tidak valid")
 	}
+	akunPosting, err := akunPostingWithTx(tx, idKoperasi, peristiwa)
+	if err != nil {
+		return err
+	}
+	akunKas, akunModal := akunPosting[models.PeranAkunKas], akunPosting[models.PeranAkunSimpanan]
 
-	// Dapatkan akun kas dan akun modal menggunakan tx
-	var akunKas, akunModal models.Akun
-	if kasErr := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, "1101").First(&akunKas).Error; kasErr != nil {
-		return errors.New("akun kas tidak ditemukan")
+	// Setoran: Kas (debit) / Modal (kredit). Penarikan adalah kebalikannya.
+	akunDebit, akunKredit := akunKas, akunModal
+	deskripsi := fmt.Sprintf("Setoran %s", simpanan.TipeSimpanan)
+	if simpanan.JenisTransaksi == models.TransaksiPenarikan {
+		akunDebit, akunKredit = akunModal, akunKas
+		deskripsi = fmt.Sprintf("Penarikan %s", simpanan.TipeSimpanan)
 	}
-	if modalErr := tx.Where("id_koperasi = ? AND kode_akun = ?", idKoperasi, kodeAkunModal).First(&akunModal).Error; modalErr != nil {
-		return errors.New("akun modal tidak ditemukan")
+
+	// Tolak posting ke periode yang sudah ditutup
+	if periodeErr := cekPeriodeTerbukaWithTx(tx, idKoperasi, simpanan.TanggalTransaksi); periodeErr != nil {
+		return periodeErr
 	}
 
 	// Generate nomor jurnal dalam transaction yang sama
@@ -860,7 +1264,7 @@ func (s *TransaksiService) PostingOtomatisSimpananWithTx(tx *gorm.DB, idKoperasi
 		IDKoperasi:       idKoperasi,
 		NomorJurnal:      nomorJurnal,
 		TanggalTransaksi: simpanan.TanggalTransaksi,
-		Deskripsi:        fmt.Sprintf("Setoran %s", simpanan.TipeSimpanan),
+		Deskripsi:        deskripsi,
 		NomorReferensi:   simpanan.NomorReferensi,
 		TipeTransaksi:    models.TipeTransaksiSimpanan,
 		TotalDebit:       simpanan.JumlahSetoran,
@@ -873,28 +1277,34 @@ func (s *TransaksiService) PostingOtomatisSimpananWithTx(tx *gorm.DB, idKoperasi
 		return errors.New("gagal membuat jurnal simpanan")
 	}
 
-	// Buat baris debit (Kas) menggunakan tx
-	barisKas := models.BarisTransaksi{
+	// Buat baris debit menggunakan tx
+	barisDebit := models.BarisTransaksi{
 		IDTransaksi:  transaksi.ID,
-		IDAkun:       akunKas.ID,
+		IDAkun:       akunDebit.ID,
 		JumlahDebit:  simpanan.JumlahSetoran,
 		JumlahKredit: 0,
-		Keterangan:   fmt.Sprintf("Setoran %s", simpanan.TipeSimpanan),
+		Keterangan:   deskripsi,
+		IDUnitUsaha:  simpanan.IDUnitUsaha,
 	}
-	if kasBarisErr := tx.Create(&barisKas).Error; kasBarisErr != nil {
-		return errors.New("gagal membuat baris kas")
+	if debitBarisErr := tx.Create(&barisDebit).Error; debitBarisErr != nil {
+		return errors.New("gagal membuat baris debit")
 	}
 
-	// Buat baris kredit (Modal) menggunakan tx
-	barisModal := models.BarisTransaksi{
+	// Buat baris kredit menggunakan tx
+	barisKredit := models.BarisTransaksi{
 		IDTransaksi:  transaksi.ID,
-		IDAkun:       akunModal.ID,
+		IDAkun:       akunKredit.ID,
 		JumlahDebit:  0,
 		JumlahKredit: simpanan.JumlahSetoran,
-		Keterangan:   fmt.Sprintf("Setoran %s", simpanan.TipeSimpanan),
+		Keterangan:   deskripsi,
+		IDUnitUsaha:  simpanan.IDUnitUsaha,
+	}
+	if kreditBarisErr := tx.Create(&barisKredit).Error; kreditBarisErr != nil {
+		return errors.New("gagal membuat baris kredit")
 	}
-	if modalBarisErr := tx.Create(&barisModal).Error; modalBarisErr != nil {
-		return errors.New("gagal membuat baris modal")
+
+	if saldoErr := catatMutasiSaldoWithTx(tx, transaksi.ID, 1); saldoErr != nil {
+		return saldoErr
 	}
 
 	// Update simpanan dengan ID transaksi menggunakan tx
@@ -905,3 +1315,112 @@ func (s *TransaksiService) PostingOtomatisSimpananWithTx(tx *gorm.DB, idKoperasi
 
 	return nil
 }
+
+// PostingOtomatisPencairanPinjamanWithTx membuat jurnal otomatis untuk pencairan pinjaman
+// menggunakan transaction yang diberikan.
+//
+// Jurnal yang dibuat:
+//   - Debit Piutang Pinjaman Anggota sebesar pokok pinjaman
+//   - Kredit Kas sebesar pokok pinjaman
+//
+// Akun ditentukan oleh aturan posting PENCAIRAN_PINJAMAN.
+//
+// Returns error jika pinjaman atau akun yang diperlukan tidak ditemukan,
+// atau gagal membuat jurnal.
+func (s *TransaksiService) PostingOtomatisPencairanPinjamanWithTx(tx *gorm.DB, idKoperasi, idPengguna, idPinjaman uuid.UUID) error {
+	// Ambil data pinjaman menggunakan tx
+	var pinjaman models.Pinjaman
+	if err := tx.Where("id = ? AND id_koperasi = ?", idPinjaman, idKoperasi).First(&pinjaman).Error; err != nil {
+		return errors.New("pinjaman tidak ditemukan")
+	}
+
+	// Dapatkan akun kas dan akun piutang pinjaman dari aturan posting menggunakan tx
+	akunPosting, err := akunPostingWithTx(tx, idKoperasi, models.PeristiwaPencairanPinjaman)
+	if err != nil {
+		return err
+	}
+	akunKas, akunPiutang := akunPosting[models.PeranAkunKas], akunPosting[models.PeranAkunPiutangPinjaman]
+
+	keterangan := fmt.Sprintf("Pencairan pinjaman %s", pinjaman.NomorPinjaman)
+	transaksi, err := s.simpanJurnalWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: pinjaman.TanggalPencairan,
+		Deskripsi:        keterangan,
+		NomorReferensi:   pinjaman.NomorPinjaman,
+		TipeTransaksi:    models.TipeTransaksiPinjaman,
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: akunPiutang.ID, JumlahDebit: pinjaman.JumlahPokok, Keterangan: keterangan},
+			{IDAkun: akunKas.ID, JumlahKredit: pinjaman.JumlahPokok, Keterangan: keterangan},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal membuat jurnal pencairan: %w", err)
+	}
+
+	// Update pinjaman dengan ID transaksi menggunakan tx
+	if updateErr := tx.Model(&pinjaman).Update("id_transaksi", transaksi.ID).Error; updateErr != nil {
+		return errors.New("gagal update ID transaksi di pinjaman")
+	}
+
+	return nil
+}
+
+// PostingOtomatisAngsuranWithTx membuat jurnal otomatis untuk pembayaran satu angsuran pinjaman
+// menggunakan transaction yang diberikan.
+//
+// Jurnal yang dibuat:
+//   - Debit Kas sebesar total angsuran
+//   - Kredit Piutang Pinjaman Anggota sebesar porsi pokok
+//   - Kredit Pendapatan Jasa Pinjaman sebesar porsi bunga
+//
+// Akun ditentukan oleh aturan posting ANGSURAN_PINJAMAN.
+//
+// Returns error jika jadwal angsuran atau akun yang diperlukan tidak ditemukan,
+// atau gagal membuat jurnal.
+func (s *TransaksiService) PostingOtomatisAngsuranWithTx(tx *gorm.DB, idKoperasi, idPengguna, idJadwalAngsuran uuid.UUID, tanggalBayar time.Time) error {
+	// Ambil data jadwal angsuran beserta pinjamannya menggunakan tx
+	var jadwal models.JadwalAngsuran
+	if err := tx.Preload("Pinjaman").Where("id = ? AND id_koperasi = ?", idJadwalAngsuran, idKoperasi).First(&jadwal).Error; err != nil {
+		return errors.New("jadwal angsuran tidak ditemukan")
+	}
+
+	// Dapatkan akun-akun yang diperlukan dari aturan posting menggunakan tx
+	akunPosting, err := akunPostingWithTx(tx, idKoperasi, models.PeristiwaAngsuranPinjaman)
+	if err != nil {
+		return err
+	}
+	akunKas, akunPiutang := akunPosting[models.PeranAkunKas], akunPosting[models.PeranAkunPiutangPinjaman]
+	akunPendapatanJasa := akunPosting[models.PeranAkunPendapatanJasa]
+
+	keterangan := fmt.Sprintf("Angsuran ke-%d pinjaman %s", jadwal.AngsuranKe, jadwal.Pinjaman.NomorPinjaman)
+	barisTransaksi := []BuatBarisTransaksiRequest{
+		{IDAkun: akunKas.ID, JumlahDebit: jadwal.TotalAngsuran, Keterangan: keterangan},
+	}
+	if jadwal.Pokok > 0 {
+		barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{
+			IDAkun: akunPiutang.ID, JumlahKredit: jadwal.Pokok, Keterangan: "Pokok pinjaman",
+		})
+	}
+	if jadwal.Bunga > 0 {
+		barisTransaksi = append(barisTransaksi, BuatBarisTransaksiRequest{
+			IDAkun: akunPendapatanJasa.ID, JumlahKredit: jadwal.Bunga, Keterangan: "Jasa pinjaman",
+		})
+	}
+
+	transaksi, err := s.simpanJurnalWithTx(tx, idKoperasi, idPengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: tanggalBayar,
+		Deskripsi:        keterangan,
+		NomorReferensi:   jadwal.Pinjaman.NomorPinjaman,
+		TipeTransaksi:    models.TipeTransaksiPinjaman,
+		BarisTransaksi:   barisTransaksi,
+	})
+	if err != nil {
+		return fmt.Errorf("gagal membuat jurnal angsuran: %w", err)
+	}
+
+	// Update jadwal angsuran dengan ID transaksi menggunakan tx
+	if updateErr := tx.Model(&jadwal).Update("id_transaksi", transaksi.ID).Error; updateErr != nil {
+		return errors.New("gagal update ID transaksi di jadwal angsuran")
+	}
+
+	return nil
+}
@@ -3,10 +3,13 @@ package services
 import (
 	"cooperative-erp-lite/internal/models"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -23,8 +26,12 @@ func setupTestDBForTransaksi(t *testing.T) *gorm.DB {
 	// Auto migrate tables for testing
 	err = db.AutoMigrate(
 		&models.Koperasi{},
+		&models.Pengguna{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 		&models.Akun{},
 	)
 	if err != nil {
@@ -34,8 +41,8 @@ func setupTestDBForTransaksi(t *testing.T) *gorm.DB {
 	return db
 }
 
-// TestValidasiTransaksi_FloatingPointPrecision tests that floating-point precision issues don't cause false rejections
-func TestValidasiTransaksi_FloatingPointPrecision(t *testing.T) {
+// TestValidasiTransaksi_PresisiSen tests that amounts are compared exactly in sen without any epsilon tolerance
+func TestValidasiTransaksi_PresisiSen(t *testing.T) {
 	db := setupTestDBForTransaksi(t)
 	if db == nil {
 		return
@@ -52,71 +59,71 @@ func TestValidasiTransaksi_FloatingPointPrecision(t *testing.T) {
 		{
 			name: "Classic floating-point issue: 0.1 + 0.1 + 0.1 should equal 0.3",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 0.1, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0.1, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0.1, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 0.3},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("0.10"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("0.10"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("0.10"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: mustUang("0.30")},
 			},
 			shouldPass:  true,
-			description: "Should pass with epsilon tolerance (0.1+0.1+0.1 = 0.30000000000000004 ≈ 0.3)",
+			description: "10 + 10 + 10 sen is exactly 30 sen",
 		},
 		{
-			name: "Multiple small amounts that could accumulate floating-point errors",
+			name: "Multiple small decimal amounts",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 10.5, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 15.3, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 8.7, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 34.5},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("10.50"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("15.30"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("8.70"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: mustUang("34.50")},
 			},
 			shouldPass:  true,
-			description: "Multiple decimal additions should balance within epsilon",
+			description: "Decimal additions should balance exactly",
 		},
 		{
 			name: "Real-world scenario: Rp 33,800 split across multiple items",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 10000.0, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 15500.0, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 8300.0, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 33800.0},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(10000), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(15500), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(8300), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: models.Rupiah(33800)},
 			},
 			shouldPass:  true,
 			description: "Real-world transaction amounts should balance exactly",
 		},
 		{
-			name: "Edge case: Very small amounts within epsilon tolerance",
+			name: "Edge case: one sen",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 0.01, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 0.01},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("0.01"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: mustUang("0.01")},
 			},
 			shouldPass:  true,
 			description: "Minimum transaction amount (1 sen) should be valid",
 		},
 		{
-			name: "Actually unbalanced: Off by more than epsilon (0.50)",
+			name: "Unbalanced: off by 0.50",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 100.00, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 99.50},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(100), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: mustUang("99.50")},
 			},
 			shouldPass:  false,
-			description: "Truly unbalanced transaction should fail (off by 0.50 > epsilon 0.01)",
+			description: "Truly unbalanced transaction should fail",
 		},
 		{
-			name: "Unbalanced: Off by 0.02 (greater than epsilon)",
+			name: "Unbalanced: off by 0.02",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 100.00, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 99.98},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(100), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: mustUang("99.98")},
 			},
 			shouldPass:  false,
-			description: "Unbalanced by 0.02 should fail (> epsilon 0.01)",
+			description: "Unbalanced by 0.02 should fail",
 		},
 		{
-			name: "Edge case: Balanced within epsilon (off by 0.005)",
+			name: "Unbalanced: off by one sen",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 100.005, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 100.00},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("100.01"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: models.Rupiah(100)},
 			},
-			shouldPass:  true,
-			description: "Off by 0.005 should pass (< epsilon 0.01)",
+			shouldPass:  false,
+			description: "There is no tolerance: a difference of 1 sen must be rejected",
 		},
 		{
 			name: "Zero transaction should fail",
@@ -128,21 +135,21 @@ func TestValidasiTransaksi_FloatingPointPrecision(t *testing.T) {
 			description: "Transaction with zero amounts should be rejected",
 		},
 		{
-			name: "Complex multi-line transaction with floating-point arithmetic",
+			name: "Complex multi-line transaction splitting 100 into thirds",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 33.33, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 33.33, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 33.34, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 100.00},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("33.33"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("33.33"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("33.34"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: models.Rupiah(100)},
 			},
 			shouldPass:  true,
-			description: "Splitting amounts like 100/3 should balance within epsilon",
+			description: "Splitting amounts like 100/3 should balance exactly",
 		},
 		{
 			name: "Large amounts with decimal precision",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 9999999.99, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 9999999.99},
+				{IDAkun: uuid.New(), JumlahDebit: mustUang("9999999.99"), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: mustUang("9999999.99")},
 			},
 			shouldPass:  true,
 			description: "Large amounts should not cause precision issues",
@@ -155,8 +162,7 @@ func TestValidasiTransaksi_FloatingPointPrecision(t *testing.T) {
 
 			if tt.shouldPass && err != nil {
 				t.Errorf("Expected validation to PASS but got error: %v\n"+
-					"Description: %s\n"+
-					"This indicates floating-point precision issue not properly handled",
+					"Description: %s",
 					err, tt.description)
 			}
 
@@ -188,7 +194,7 @@ func TestValidasiTransaksi_EdgeCases(t *testing.T) {
 		{
 			name: "Less than 2 lines should fail",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 100, JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(100), JumlahKredit: 0},
 			},
 			shouldPass:    false,
 			expectedError: "minimal 2 baris",
@@ -196,8 +202,8 @@ func TestValidasiTransaksi_EdgeCases(t *testing.T) {
 		{
 			name: "Both debit and credit on same line should fail",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 100, JumlahKredit: 50},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 100},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(100), JumlahKredit: models.Rupiah(50)},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: models.Rupiah(100)},
 			},
 			shouldPass:    false,
 			expectedError: "tidak boleh memiliki debit dan kredit sekaligus",
@@ -205,7 +211,7 @@ func TestValidasiTransaksi_EdgeCases(t *testing.T) {
 		{
 			name: "Line with zero debit and zero credit should fail",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 100, JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(100), JumlahKredit: 0},
 				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 0},
 			},
 			shouldPass:    false,
@@ -214,8 +220,8 @@ func TestValidasiTransaksi_EdgeCases(t *testing.T) {
 		{
 			name: "Only debit lines (no credit) should fail",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 100, JumlahKredit: 0},
-				{IDAkun: uuid.New(), JumlahDebit: 50, JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(100), JumlahKredit: 0},
+				{IDAkun: uuid.New(), JumlahDebit: models.Rupiah(50), JumlahKredit: 0},
 			},
 			shouldPass:    false,
 			expectedError: "minimal satu baris debit dan satu baris kredit",
@@ -223,8 +229,8 @@ func TestValidasiTransaksi_EdgeCases(t *testing.T) {
 		{
 			name: "Only credit lines (no debit) should fail",
 			barisTransaksi: []BuatBarisTransaksiRequest{
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 100},
-				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 50},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: models.Rupiah(100)},
+				{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: models.Rupiah(50)},
 			},
 			shouldPass:    false,
 			expectedError: "minimal satu baris debit dan satu baris kredit",
@@ -246,8 +252,8 @@ func TestValidasiTransaksi_EdgeCases(t *testing.T) {
 	}
 }
 
-// TestBeforeSaveHook_FloatingPointPrecision tests the BeforeSave hook handles floating-point correctly
-func TestBeforeSaveHook_FloatingPointPrecision(t *testing.T) {
+// TestBeforeSaveHook_PresisiSen tests the BeforeSave hook compares totals exactly
+func TestBeforeSaveHook_PresisiSen(t *testing.T) {
 	db := setupTestDBForTransaksi(t)
 	if db == nil {
 		return
@@ -255,45 +261,45 @@ func TestBeforeSaveHook_FloatingPointPrecision(t *testing.T) {
 
 	tests := []struct {
 		name            string
-		totalDebit      float64
-		totalKredit     float64
+		totalDebit      models.Uang
+		totalKredit     models.Uang
 		expectedBalance bool
 		description     string
 	}{
 		{
 			name:            "Exact match should be balanced",
-			totalDebit:      100.0,
-			totalKredit:     100.0,
+			totalDebit:      models.Rupiah(100),
+			totalKredit:     models.Rupiah(100),
 			expectedBalance: true,
 			description:     "Perfect equality",
 		},
 		{
-			name:            "Floating-point precision issue: 0.3 vs 0.30000000000000004",
-			totalDebit:      0.1 + 0.1 + 0.1, // = 0.30000000000000004
-			totalKredit:     0.3,
+			name:            "0.1 + 0.1 + 0.1 equals 0.3",
+			totalDebit:      mustUang("0.10") + mustUang("0.10") + mustUang("0.10"),
+			totalKredit:     mustUang("0.30"),
 			expectedBalance: true,
-			description:     "Should be balanced with epsilon tolerance",
+			description:     "Sums of sen are exact",
 		},
 		{
-			name:            "Off by more than epsilon should not be balanced",
-			totalDebit:      100.0,
-			totalKredit:     99.5,
+			name:            "Off by 0.50 should not be balanced",
+			totalDebit:      models.Rupiah(100),
+			totalKredit:     mustUang("99.50"),
 			expectedBalance: false,
-			description:     "Off by 0.5 > epsilon 0.01",
+			description:     "Off by 0.50",
 		},
 		{
 			name:            "Zero amounts should not be balanced",
-			totalDebit:      0.0,
-			totalKredit:     0.0,
+			totalDebit:      0,
+			totalKredit:     0,
 			expectedBalance: false,
 			description:     "Empty transaction",
 		},
 		{
-			name:            "Within epsilon tolerance (0.005 difference)",
-			totalDebit:      100.005,
-			totalKredit:     100.0,
-			expectedBalance: true,
-			description:     "0.005 difference < epsilon 0.01",
+			name:            "Off by one sen should not be balanced",
+			totalDebit:      mustUang("100.01"),
+			totalKredit:     models.Rupiah(100),
+			expectedBalance: false,
+			description:     "No epsilon tolerance",
 		},
 	}
 
@@ -316,7 +322,7 @@ func TestBeforeSaveHook_FloatingPointPrecision(t *testing.T) {
 			if transaksi.StatusBalanced != tt.expectedBalance {
 				t.Errorf("Expected StatusBalanced=%v but got %v\n"+
 					"Description: %s\n"+
-					"TotalDebit: %.17f, TotalKredit: %.17f",
+					"TotalDebit: %s, TotalKredit: %s",
 					tt.expectedBalance, transaksi.StatusBalanced,
 					tt.description, tt.totalDebit, tt.totalKredit)
 			}
@@ -397,10 +403,10 @@ func TestConcurrentTransactionCreation_FirstOfDay(t *testing.T) {
 			req := &BuatTransaksiRequest{
 				TanggalTransaksi: mustParseTime("2025-01-20"),
 				Deskripsi:        fmt.Sprintf("First transaction #%d", index),
-				TipeTransaksi:    "test",
+				TipeTransaksi:    models.TipeTransaksiJurnalUmum,
 				BarisTransaksi: []BuatBarisTransaksiRequest{
-					{IDAkun: akunKas.ID, JumlahDebit: 100000.0},
-					{IDAkun: akunModal.ID, JumlahKredit: 100000.0},
+					{IDAkun: akunKas.ID, JumlahDebit: models.Rupiah(100000)},
+					{IDAkun: akunModal.ID, JumlahKredit: models.Rupiah(100000)},
 				},
 			}
 
@@ -525,16 +531,16 @@ func TestConcurrentTransactionCreation_RaceConditionFix(t *testing.T) {
 				TanggalTransaksi: mustParseTime("2025-01-16"),
 				Deskripsi:        "Concurrent test transaction",
 				NomorReferensi:   "",
-				TipeTransaksi:    "test",
+				TipeTransaksi:    models.TipeTransaksiJurnalUmum,
 				BarisTransaksi: []BuatBarisTransaksiRequest{
 					{
 						IDAkun:      akunKas.ID,
-						JumlahDebit: 100000.0,
+						JumlahDebit: models.Rupiah(100000),
 						Keterangan:  "Test debit",
 					},
 					{
 						IDAkun:       akunModal.ID,
-						JumlahKredit: 100000.0,
+						JumlahKredit: models.Rupiah(100000),
 						Keterangan:   "Test kredit",
 					},
 				},
@@ -613,6 +619,278 @@ func TestConcurrentTransactionCreation_RaceConditionFix(t *testing.T) {
 	db.Exec("DELETE FROM koperasi WHERE id = ?", koperasi.ID)
 }
 
+// TestBalikTransaksi memastikan jurnal yang sudah di-post dikoreksi dengan jurnal pembalik, bukan dihapus
+func TestBalikTransaksi(t *testing.T) {
+	db := setupTestDBForTransaksi(t)
+	if db == nil {
+		return
+	}
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test Pembalikan", Email: "pembalikan@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	var akunKas, akunBeban models.Akun
+	require.NoError(t, db.Where("id_koperasi = ? AND kode_akun = ?", koperasi.ID, "1101").First(&akunKas).Error)
+	require.NoError(t, db.Where("id_koperasi = ? AND kode_akun = ?", koperasi.ID, "5101").First(&akunBeban).Error)
+
+	service := NewTransaksiService(db)
+	pengguna := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	jurnal, err := service.BuatTransaksi(koperasi.ID, pengguna, &BuatTransaksiRequest{
+		TanggalTransaksi: time.Now(),
+		Deskripsi:        "Pembayaran gaji karyawan",
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: akunBeban.ID, JumlahDebit: models.Rupiah(250000)},
+			{IDAkun: akunKas.ID, JumlahKredit: models.Rupiah(250000)},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("jurnal yang sudah di-post tidak dapat dihapus", func(t *testing.T) {
+		err := service.HapusTransaksi(koperasi.ID, jurnal.ID)
+		assert.EqualError(t, err, "transaksi sudah di-post, tidak dapat dihapus")
+	})
+
+	t.Run("jurnal koperasi lain tidak dapat dibaca atau dihapus", func(t *testing.T) {
+		koperasiLain := uuid.New()
+		_, err := service.DapatkanTransaksi(koperasiLain, jurnal.ID)
+		assert.EqualError(t, err, "transaksi tidak ditemukan")
+		err = service.HapusTransaksi(koperasiLain, jurnal.ID)
+		assert.EqualError(t, err, "transaksi tidak ditemukan")
+	})
+
+	t.Run("jurnal yang sudah di-post tidak dapat diubah", func(t *testing.T) {
+		_, err := service.PerbaruiTransaksi(jurnal.ID, koperasi.ID, pengguna, &BuatTransaksiRequest{
+			TanggalTransaksi: time.Now(),
+			Deskripsi:        "Pembayaran gaji karyawan",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: akunBeban.ID, JumlahDebit: models.Rupiah(200000)},
+				{IDAkun: akunKas.ID, JumlahKredit: models.Rupiah(200000)},
+			},
+		})
+		assert.ErrorIs(t, err, ErrStatusJurnalTidakSesuai)
+	})
+
+	pembalik, err := service.BalikTransaksi(jurnal.ID, koperasi.ID, pengguna, &BalikTransaksiRequest{Alasan: "Salah input nominal"})
+	require.NoError(t, err)
+
+	t.Run("jurnal pembalik mencerminkan jurnal asal", func(t *testing.T) {
+		require.NotNil(t, pembalik.IDJurnalAsal)
+		assert.Equal(t, jurnal.ID, *pembalik.IDJurnalAsal)
+		assert.Equal(t, models.Rupiah(250000), pembalik.TotalDebit)
+
+		for _, baris := range pembalik.BarisTransaksi {
+			if baris.IDAkun == akunKas.ID {
+				assert.Equal(t, models.Rupiah(250000), baris.JumlahDebit)
+			} else {
+				assert.Equal(t, models.Rupiah(250000), baris.JumlahKredit)
+			}
+		}
+
+		asal, err := service.DapatkanTransaksi(koperasi.ID, jurnal.ID)
+		require.NoError(t, err)
+		assert.True(t, asal.Dibalik)
+		require.NotNil(t, asal.IDJurnalPembalik)
+		assert.Equal(t, pembalik.ID, *asal.IDJurnalPembalik)
+
+		saldoKas, err := akunService.HitungSaldoAkun(akunKas.ID, "")
+		require.NoError(t, err)
+		assert.Equal(t, models.Uang(0), saldoKas)
+	})
+
+	t.Run("jurnal tidak dapat dibalik dua kali", func(t *testing.T) {
+		_, err := service.BalikTransaksi(jurnal.ID, koperasi.ID, pengguna, &BalikTransaksiRequest{Alasan: "Salah input nominal"})
+		assert.Error(t, err)
+		_, err = service.BalikTransaksi(pembalik.ID, koperasi.ID, pengguna, &BalikTransaksiRequest{Alasan: "Salah input nominal"})
+		assert.Error(t, err)
+	})
+
+	t.Run("jurnal dokumen sumber hanya dibalik melalui dokumennya", func(t *testing.T) {
+		// Jurnal dokumen sumber hanya dibuat sistem, bukan lewat BuatTransaksi
+		var jurnalSimpanan *models.Transaksi
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var simpanErr error
+			jurnalSimpanan, simpanErr = service.simpanJurnalWithTx(tx, koperasi.ID, pengguna, &BuatTransaksiRequest{
+				TanggalTransaksi: time.Now(),
+				Deskripsi:        "Setoran simpanan",
+				TipeTransaksi:    models.TipeTransaksiSimpanan,
+				BarisTransaksi: []BuatBarisTransaksiRequest{
+					{IDAkun: akunKas.ID, JumlahDebit: models.Rupiah(100000)},
+					{IDAkun: akunBeban.ID, JumlahKredit: models.Rupiah(100000)},
+				},
+			})
+			return simpanErr
+		})
+		require.NoError(t, err)
+
+		_, err = service.BalikTransaksi(jurnalSimpanan.ID, koperasi.ID, pengguna, &BalikTransaksiRequest{Alasan: "Salah input nominal"})
+		assert.ErrorContains(t, err, "dokumen sumber")
+
+		_, err = service.PerbaruiTransaksi(jurnalSimpanan.ID, koperasi.ID, pengguna, &BuatTransaksiRequest{
+			TanggalTransaksi: time.Now(),
+			Deskripsi:        "Setoran simpanan",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: akunKas.ID, JumlahDebit: models.Rupiah(10000)},
+				{IDAkun: akunBeban.ID, JumlahKredit: models.Rupiah(10000)},
+			},
+		})
+		assert.ErrorContains(t, err, "dokumen sumber")
+	})
+}
+
+// TestAlurPersetujuanJurnal memastikan jurnal umum dari kasir baru dihitung setelah disetujui admin dan di-post
+func TestAlurPersetujuanJurnal(t *testing.T) {
+	db := setupTestDBForTransaksi(t)
+	if db == nil {
+		return
+	}
+
+	koperasi := &models.Koperasi{ID: uuid.New(), NamaKoperasi: "Test Persetujuan", Email: "persetujuan@test.com", NoTelepon: "081234567890"}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+
+	var akunKas, akunBeban models.Akun
+	require.NoError(t, db.Where("id_koperasi = ? AND kode_akun = ?", koperasi.ID, "1101").First(&akunKas).Error)
+	require.NoError(t, db.Where("id_koperasi = ? AND kode_akun = ?", koperasi.ID, "5101").First(&akunBeban).Error)
+
+	service := NewTransaksiService(db)
+	kasir := buatPenggunaTest(t, db, koperasi.ID, models.PeranKasir)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	jurnal, err := service.BuatTransaksi(koperasi.ID, kasir, &BuatTransaksiRequest{
+		TanggalTransaksi: time.Now(),
+		Deskripsi:        "Pembelian alat tulis",
+		BarisTransaksi: []BuatBarisTransaksiRequest{
+			{IDAkun: akunBeban.ID, JumlahDebit: models.Rupiah(75000)},
+			{IDAkun: akunKas.ID, JumlahKredit: models.Rupiah(75000)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusJurnalDraft, jurnal.Status)
+
+	saldoKas := func() models.Uang {
+		saldo, err := akunService.HitungSaldoAkun(akunKas.ID, "")
+		require.NoError(t, err)
+		return saldo
+	}
+	assert.Equal(t, models.Uang(0), saldoKas(), "jurnal draft tidak boleh dihitung")
+
+	t.Run("tipe transaksi draft tidak dapat diubah", func(t *testing.T) {
+		_, err := service.PerbaruiTransaksi(jurnal.ID, koperasi.ID, kasir, &BuatTransaksiRequest{
+			TanggalTransaksi: time.Now(),
+			Deskripsi:        "Pembelian alat tulis",
+			TipeTransaksi:    models.TipeTransaksiPenjualan,
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: akunBeban.ID, JumlahDebit: models.Rupiah(75000)},
+				{IDAkun: akunKas.ID, JumlahKredit: models.Rupiah(75000)},
+			},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("jurnal draft tidak dapat disetujui sebelum diajukan", func(t *testing.T) {
+		_, err := service.SetujuiTransaksi(jurnal.ID, koperasi.ID, admin)
+		assert.ErrorIs(t, err, ErrStatusJurnalTidakSesuai)
+	})
+
+	diajukan, err := service.AjukanTransaksi(jurnal.ID, koperasi.ID, kasir)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusJurnalDiajukan, diajukan.Status)
+
+	t.Run("pembuat tidak dapat menyetujui jurnalnya sendiri", func(t *testing.T) {
+		_, err := service.SetujuiTransaksi(jurnal.ID, koperasi.ID, kasir)
+		assert.Error(t, err)
+	})
+
+	t.Run("penolakan mengembalikan jurnal ke draft", func(t *testing.T) {
+		ditolak, err := service.TolakTransaksi(jurnal.ID, koperasi.ID, admin, &TolakTransaksiRequest{Alasan: "Lampirkan nota pembelian"})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusJurnalDraft, ditolak.Status)
+		assert.Equal(t, "Lampirkan nota pembelian", ditolak.AlasanPenolakan)
+
+		_, err = service.AjukanTransaksi(jurnal.ID, koperasi.ID, kasir)
+		require.NoError(t, err)
+	})
+
+	disetujui, err := service.SetujuiTransaksi(jurnal.ID, koperasi.ID, admin)
+	require.NoError(t, err)
+	require.NotNil(t, disetujui.DisetujuiOleh)
+	assert.Equal(t, admin, *disetujui.DisetujuiOleh)
+	assert.NotNil(t, disetujui.TanggalDisetujui)
+	assert.Equal(t, models.Uang(0), saldoKas(), "jurnal yang belum di-post tidak boleh dihitung")
+
+	diposting, err := service.PostingTransaksi(jurnal.ID, koperasi.ID, admin)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusJurnalPosted, diposting.Status)
+	assert.Equal(t, models.Rupiah(-75000), saldoKas())
+
+	t.Run("kasir tidak dapat mengubah jurnal yang sudah di-post", func(t *testing.T) {
+		req := &BuatTransaksiRequest{
+			TanggalTransaksi: time.Now(),
+			Deskripsi:        "Pembelian alat tulis",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: akunBeban.ID, JumlahDebit: models.Rupiah(5000)},
+				{IDAkun: akunKas.ID, JumlahKredit: models.Rupiah(5000)},
+			},
+		}
+		_, err := service.PerbaruiTransaksi(jurnal.ID, koperasi.ID, kasir, req)
+		assert.ErrorIs(t, err, ErrStatusJurnalTidakSesuai)
+
+		req.TipeTransaksi = models.TipeTransaksiSimpanan
+		_, err = service.PerbaruiTransaksi(jurnal.ID, koperasi.ID, kasir, req)
+		assert.Error(t, err)
+		assert.Equal(t, models.Rupiah(-75000), saldoKas())
+	})
+
+	t.Run("kasir tidak dapat membuat jurnal bertipe sistem", func(t *testing.T) {
+		_, err := service.BuatTransaksi(koperasi.ID, kasir, &BuatTransaksiRequest{
+			TanggalTransaksi: time.Now(),
+			Deskripsi:        "Penjualan tunai",
+			TipeTransaksi:    models.TipeTransaksiPenjualan,
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: akunKas.ID, JumlahDebit: models.Rupiah(20000)},
+				{IDAkun: akunBeban.ID, JumlahKredit: models.Rupiah(20000)},
+			},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, models.Rupiah(-75000), saldoKas(), "jurnal bertipe sistem dari kasir tidak boleh di-post")
+	})
+
+	t.Run("jurnal buatan admin langsung di-post", func(t *testing.T) {
+		jurnalAdmin, err := service.BuatTransaksi(koperasi.ID, admin, &BuatTransaksiRequest{
+			TanggalTransaksi: time.Now(),
+			Deskripsi:        "Pembelian materai",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: akunBeban.ID, JumlahDebit: models.Rupiah(10000)},
+				{IDAkun: akunKas.ID, JumlahKredit: models.Rupiah(10000)},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusJurnalPosted, jurnalAdmin.Status)
+	})
+}
+
+// buatPenggunaTest membuat pengguna dengan peran tertentu dan mengembalikan ID-nya
+func buatPenggunaTest(t *testing.T, db *gorm.DB, idKoperasi uuid.UUID, peran models.PeranPengguna) uuid.UUID {
+	pengguna := &models.Pengguna{
+		IDKoperasi:   idKoperasi,
+		NamaLengkap:  "Pengguna " + string(peran),
+		NamaPengguna: fmt.Sprintf("%s-%s", strings.ToLower(string(peran)), uuid.NewString()[:8]),
+		Email:        strings.ToLower(string(peran)) + "@test.com",
+		Peran:        peran,
+	}
+	pengguna.SetKataSandi("password123")
+	require.NoError(t, db.Create(pengguna).Error)
+	return pengguna.ID
+}
+
 // Helper function to parse time for tests
 func mustParseTime(dateStr string) time.Time {
 	t, err := time.Parse("2006-01-02", dateStr)
@@ -622,6 +900,15 @@ func mustParseTime(dateStr string) time.Time {
 	return t
 }
 
+// mustUang mengurai nominal rupiah desimal untuk data test
+func mustUang(s string) models.Uang {
+	uang, err := models.ParseUang(s)
+	if err != nil {
+		panic(err)
+	}
+	return uang
+}
+
 // Helper function to format expected journal number
 func mustFormatJournalNumber(seq int) string {
 	return fmt.Sprintf("JRN-20250116-%04d", seq)
@@ -633,10 +920,10 @@ func BenchmarkValidasiTransaksi(b *testing.B) {
 	service := NewTransaksiService(db)
 
 	barisTransaksi := []BuatBarisTransaksiRequest{
-		{IDAkun: uuid.New(), JumlahDebit: 0.1, JumlahKredit: 0},
-		{IDAkun: uuid.New(), JumlahDebit: 0.1, JumlahKredit: 0},
-		{IDAkun: uuid.New(), JumlahDebit: 0.1, JumlahKredit: 0},
-		{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: 0.3},
+		{IDAkun: uuid.New(), JumlahDebit: mustUang("0.10"), JumlahKredit: 0},
+		{IDAkun: uuid.New(), JumlahDebit: mustUang("0.10"), JumlahKredit: 0},
+		{IDAkun: uuid.New(), JumlahDebit: mustUang("0.10"), JumlahKredit: 0},
+		{IDAkun: uuid.New(), JumlahDebit: 0, JumlahKredit: mustUang("0.30")},
 	}
 
 	b.ResetTimer()
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"cooperative-erp-lite/pkg/validasi"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UnitUsahaService menangani master unit usaha koperasi serba usaha
+type UnitUsahaService struct {
+	db *gorm.DB
+}
+
+// NewUnitUsahaService membuat instance baru UnitUsahaService
+func NewUnitUsahaService(db *gorm.DB) *UnitUsahaService {
+	return &UnitUsahaService{db: db}
+}
+
+// BuatUnitUsahaRequest adalah struktur request untuk membuat unit usaha
+type BuatUnitUsahaRequest struct {
+	KodeUnit  string `json:"kodeUnit" binding:"required"`
+	NamaUnit  string `json:"namaUnit" binding:"required"`
+	Deskripsi string `json:"deskripsi"`
+}
+
+// PerbaruiUnitUsahaRequest adalah struktur request untuk update unit usaha
+type PerbaruiUnitUsahaRequest struct {
+	NamaUnit    string `json:"namaUnit"`
+	Deskripsi   string `json:"deskripsi"`
+	StatusAktif *bool  `json:"statusAktif"`
+}
+
+// BuatUnitUsaha membuat unit usaha baru
+func (s *UnitUsahaService) BuatUnitUsaha(idKoperasi uuid.UUID, req *BuatUnitUsahaRequest) (*models.UnitUsahaResponse, error) {
+	// Initialize validator
+	validator := validasi.Baru()
+
+	if err := validator.TeksWajib(req.KodeUnit, "kode unit usaha", 1, 20); err != nil {
+		return nil, err
+	}
+
+	if err := validator.TeksWajib(req.NamaUnit, "nama unit usaha", 3, 255); err != nil {
+		return nil, err
+	}
+
+	if err := validator.TeksOpsional(req.Deskripsi, "deskripsi", 1000); err != nil {
+		return nil, err
+	}
+
+	// Validasi kode unit usaha unique (termasuk yang sudah dihapus karena unique index)
+	var count int64
+	s.db.Unscoped().Model(&models.UnitUsaha{}).
+		Where("id_koperasi = ? AND kode_unit = ?", idKoperasi, req.KodeUnit).
+		Count(&count)
+
+	if count > 0 {
+		return nil, errors.New("kode unit usaha sudah digunakan")
+	}
+
+	unit := &models.UnitUsaha{
+		IDKoperasi:  idKoperasi,
+		KodeUnit:    req.KodeUnit,
+		NamaUnit:    req.NamaUnit,
+		Deskripsi:   req.Deskripsi,
+		StatusAktif: true,
+	}
+
+	if err := s.db.Create(unit).Error; err != nil {
+		return nil, errors.New("gagal membuat unit usaha")
+	}
+
+	response := unit.ToResponse()
+	return &response, nil
+}
+
+// DapatkanSemuaUnitUsaha mengambil daftar unit usaha koperasi, diurutkan berdasarkan kode
+func (s *UnitUsahaService) DapatkanSemuaUnitUsaha(idKoperasi uuid.UUID, statusAktif *bool) ([]models.UnitUsahaResponse, error) {
+	query := s.db.Where("id_koperasi = ?", idKoperasi)
+	if statusAktif != nil {
+		query = query.Where("status_aktif = ?", *statusAktif)
+	}
+
+	var unitList []models.UnitUsaha
+	if err := query.Order("kode_unit ASC").Find(&unitList).Error; err != nil {
+		return nil, errors.New("gagal mengambil daftar unit usaha")
+	}
+
+	responses := make([]models.UnitUsahaResponse, len(unitList))
+	for i, unit := range unitList {
+		responses[i] = unit.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// DapatkanUnitUsaha mengambil unit usaha berdasarkan ID
+func (s *UnitUsahaService) DapatkanUnitUsaha(idKoperasi, id uuid.UUID) (*models.UnitUsahaResponse, error) {
+	var unit models.UnitUsaha
+	err := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&unit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("unit usaha tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	response := unit.ToResponse()
+	return &response, nil
+}
+
+// PerbaruiUnitUsaha mengupdate nama, deskripsi, atau status unit usaha.
+// Kode unit tidak dapat diubah karena dipakai sebagai acuan di laporan.
+func (s *UnitUsahaService) PerbaruiUnitUsaha(idKoperasi, id uuid.UUID, req *PerbaruiUnitUsahaRequest) (*models.UnitUsahaResponse, error) {
+	validator := validasi.Baru()
+
+	if req.NamaUnit != "" {
+		if err := validator.TeksWajib(req.NamaUnit, "nama unit usaha", 3, 255); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validator.TeksOpsional(req.Deskripsi, "deskripsi", 1000); err != nil {
+		return nil, err
+	}
+
+	var unit models.UnitUsaha
+	err := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&unit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("unit usaha tidak ditemukan atau tidak memiliki akses")
+		}
+		return nil, err
+	}
+
+	if req.NamaUnit != "" {
+		unit.NamaUnit = req.NamaUnit
+	}
+	if req.Deskripsi != "" {
+		unit.Deskripsi = req.Deskripsi
+	}
+	if req.StatusAktif != nil {
+		unit.StatusAktif = *req.StatusAktif
+	}
+
+	if err := s.db.Save(&unit).Error; err != nil {
+		return nil, errors.New("gagal memperbarui unit usaha")
+	}
+
+	response := unit.ToResponse()
+	return &response, nil
+}
+
+// HapusUnitUsaha menghapus unit usaha yang belum pernah dipakai.
+// Unit usaha yang sudah memiliki jurnal, produk, atau simpanan cukup dinonaktifkan.
+func (s *UnitUsahaService) HapusUnitUsaha(idKoperasi, id uuid.UUID) error {
+	var unit models.UnitUsaha
+	err := s.db.Where("id = ? AND id_koperasi = ?", id, idKoperasi).First(&unit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("unit usaha tidak ditemukan atau tidak memiliki akses")
+		}
+		return err
+	}
+
+	var countBaris, countProduk, countSimpanan int64
+	s.db.Model(&models.BarisTransaksi{}).Where("id_unit_usaha = ?", id).Count(&countBaris)
+	s.db.Model(&models.Produk{}).Where("id_unit_usaha = ?", id).Count(&countProduk)
+	s.db.Model(&models.Simpanan{}).Where("id_unit_usaha = ?", id).Count(&countSimpanan)
+
+	if countBaris+countProduk+countSimpanan > 0 {
+		return errors.New("unit usaha sudah digunakan, nonaktifkan unit usaha sebagai gantinya")
+	}
+
+	if err := s.db.Delete(&unit).Error; err != nil {
+		return errors.New("gagal menghapus unit usaha")
+	}
+
+	return nil
+}
+
+// kondisiUnitUsaha menghasilkan kondisi SQL atas baris_transaksi untuk filter unit usaha laporan:
+// nil berarti seluruh baris, uuid.Nil berarti baris tanpa unit usaha.
+func kondisiUnitUsaha(idUnitUsaha *uuid.UUID) (string, []interface{}) {
+	switch {
+	case idUnitUsaha == nil:
+		return "TRUE", nil
+	case *idUnitUsaha == uuid.Nil:
+		return "baris_transaksi.id_unit_usaha IS NULL", nil
+	default:
+		return "baris_transaksi.id_unit_usaha = ?", []interface{}{*idUnitUsaha}
+	}
+}
+
+// validasiUnitUsahaWithTx memastikan unit usaha pada baris jurnal milik koperasi yang sama.
+// Unit usaha kosong (nil) selalu valid. Unit nonaktif tetap diterima agar jurnal lama
+// (misalnya pembalikan) yang memakai unit tersebut tetap dapat diproses.
+func validasiUnitUsahaWithTx(tx *gorm.DB, idKoperasi uuid.UUID, idUnitUsaha *uuid.UUID) error {
+	if idUnitUsaha == nil {
+		return nil
+	}
+
+	var count int64
+	err := tx.Model(&models.UnitUsaha{}).
+		Where("id = ? AND id_koperasi = ?", *idUnitUsaha, idKoperasi).
+		Count(&count).Error
+	if err != nil {
+		return errors.New("gagal memvalidasi unit usaha")
+	}
+	if count == 0 {
+		return fmt.Errorf("unit usaha %s tidak ditemukan", *idUnitUsaha)
+	}
+
+	return nil
+}
+
+// validasiUnitUsahaAktifWithTx memastikan unit usaha yang dipilih untuk data baru
+// (produk, simpanan) milik koperasi yang sama dan masih aktif.
+func validasiUnitUsahaAktifWithTx(tx *gorm.DB, idKoperasi uuid.UUID, idUnitUsaha *uuid.UUID) error {
+	if idUnitUsaha == nil {
+		return nil
+	}
+
+	var unit models.UnitUsaha
+	err := tx.Where("id = ? AND id_koperasi = ?", *idUnitUsaha, idKoperasi).First(&unit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("unit usaha tidak ditemukan")
+		}
+		return errors.New("gagal memvalidasi unit usaha")
+	}
+	if !unit.StatusAktif {
+		return fmt.Errorf("unit usaha %s tidak aktif", unit.KodeUnit)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"cooperative-erp-lite/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarisJurnalPenjualan(t *testing.T) {
+	idKas, idPenjualan, idHPP, idPersediaan := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	idToko := uuid.New()
+
+	items := []models.ItemPenjualan{
+		{Kuantitas: 2, HargaSatuan: models.Rupiah(10000), Produk: models.Produk{HargaBeli: models.Rupiah(7000), IDUnitUsaha: &idToko}},
+		{Kuantitas: 5, HargaSatuan: models.Rupiah(500), Produk: models.Produk{}},
+		{Kuantitas: 1, HargaSatuan: models.Rupiah(15000), Produk: models.Produk{HargaBeli: models.Rupiah(12000), IDUnitUsaha: &idToko}},
+	}
+
+	baris := barisJurnalPenjualan(items, idKas, idPenjualan, idHPP, idPersediaan, uuid.Nil)
+
+	// Unit toko: kas, penjualan, HPP, persediaan. Tanpa unit (tanpa harga beli): kas dan penjualan.
+	require.Len(t, baris, 6)
+	assert.Equal(t, idKas, baris[0].IDAkun)
+	assert.Equal(t, models.Rupiah(35000), baris[0].JumlahDebit)
+	assert.Equal(t, &idToko, baris[0].IDUnitUsaha)
+	assert.Equal(t, models.Rupiah(26000), baris[2].JumlahDebit)
+	assert.Equal(t, idPersediaan, baris[3].IDAkun)
+	assert.Nil(t, baris[4].IDUnitUsaha)
+	assert.Equal(t, models.Rupiah(2500), baris[5].JumlahKredit)
+
+	var totalDebit, totalKredit models.Uang
+	for _, b := range baris {
+		totalDebit += b.JumlahDebit
+		totalKredit += b.JumlahKredit
+	}
+	assert.Equal(t, totalDebit, totalKredit)
+}
+
+func TestUnitUsahaService(t *testing.T) {
+	db, _ := setupTestDBWithQueryCounter(t)
+	if db == nil {
+		return
+	}
+
+	err := db.AutoMigrate(
+		&models.Pengguna{},
+		&models.Akun{},
+		&models.Transaksi{},
+		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
+		&models.UnitUsaha{},
+	)
+	require.NoError(t, err)
+
+	// Tahun buku berjalan dimulai dua bulan lalu sehingga tahun buku sebelumnya sudah berakhir
+	sekarang := time.Now()
+	awalTahunBuku := time.Date(sekarang.Year(), sekarang.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -2, 0)
+	tahunLalu := awalTahunBuku.AddDate(-1, 0, 0).Year()
+
+	koperasi := &models.Koperasi{NamaKoperasi: "Test Unit Usaha Koperasi", Alamat: "Test Address", TahunBukuMulai: int(awalTahunBuku.Month())}
+	db.Create(koperasi)
+	defer cleanupTestData(db, koperasi.ID)
+
+	akunService := NewAkunService(db)
+	require.NoError(t, akunService.InisialisasiCOADefault(koperasi.ID))
+	kas, _ := akunService.DapatkanAkunByKode(koperasi.ID, "1101")
+	penjualan, _ := akunService.DapatkanAkunByKode(koperasi.ID, "4101")
+	gaji, _ := akunService.DapatkanAkunByKode(koperasi.ID, "5101")
+
+	transaksiService := NewTransaksiService(db)
+	laporanService := NewLaporanService(db, akunService, nil, nil)
+	unitUsahaService := NewUnitUsahaService(db)
+	admin := buatPenggunaTest(t, db, koperasi.ID, models.PeranAdmin)
+
+	toko, err := unitUsahaService.BuatUnitUsaha(koperasi.ID, &BuatUnitUsahaRequest{KodeUnit: "TOKO", NamaUnit: "Toko Koperasi"})
+	require.NoError(t, err)
+	fotokopi, err := unitUsahaService.BuatUnitUsaha(koperasi.ID, &BuatUnitUsahaRequest{KodeUnit: "FOTO", NamaUnit: "Fotokopi"})
+	require.NoError(t, err)
+
+	_, err = unitUsahaService.BuatUnitUsaha(koperasi.ID, &BuatUnitUsahaRequest{KodeUnit: "TOKO", NamaUnit: "Toko Kedua"})
+	assert.Error(t, err, "kode unit usaha harus unik per koperasi")
+
+	jurnal := func(tanggal time.Time, debit, kredit *models.AkunResponse, jumlah int64, idUnitUsaha *uuid.UUID) error {
+		_, err := transaksiService.BuatTransaksi(koperasi.ID, admin, &BuatTransaksiRequest{
+			TanggalTransaksi: tanggal,
+			Deskripsi:        "Transaksi test unit usaha",
+			BarisTransaksi: []BuatBarisTransaksiRequest{
+				{IDAkun: debit.ID, JumlahDebit: models.Rupiah(jumlah), IDUnitUsaha: idUnitUsaha},
+				{IDAkun: kredit.ID, JumlahKredit: models.Rupiah(jumlah), IDUnitUsaha: idUnitUsaha},
+			},
+		})
+		return err
+	}
+
+	t.Run("unit usaha koperasi lain ditolak", func(t *testing.T) {
+		idAsing := uuid.New()
+		assert.Error(t, jurnal(awalTahunBuku, kas, penjualan, 1000, &idAsing))
+	})
+
+	tanggal := awalTahunBuku.AddDate(0, 0, 9)
+	require.NoError(t, jurnal(tanggal, kas, penjualan, 500000, &toko.ID))
+	require.NoError(t, jurnal(tanggal, kas, penjualan, 200000, &fotokopi.ID))
+	require.NoError(t, jurnal(tanggal, gaji, kas, 100000, &fotokopi.ID))
+	require.NoError(t, jurnal(tanggal, kas, penjualan, 50000, nil))
+
+	mulai := awalTahunBuku.Format("2006-01-02")
+	akhir := tanggal.Format("2006-01-02")
+
+	t.Run("laporan dapat difilter per unit usaha", func(t *testing.T) {
+		labaRugiToko, err := laporanService.UntukUnitUsaha(&toko.ID).GenerateLaporanLabaRugi(koperasi.ID, mulai, akhir)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(500000), labaRugiToko.TotalPendapatan)
+		assert.Equal(t, models.Uang(0), labaRugiToko.TotalBeban)
+
+		tanpaUnit := uuid.Nil
+		labaRugiTanpaUnit, err := laporanService.UntukUnitUsaha(&tanpaUnit).GenerateLaporanLabaRugi(koperasi.ID, mulai, akhir)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(50000), labaRugiTanpaUnit.LabaRugiBersih)
+
+		neracaSaldo, err := laporanService.UntukUnitUsaha(&fotokopi.ID).GenerateNeracaSaldo(koperasi.ID, akhir)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(200000), neracaSaldo["totalDebit"])
+		assert.True(t, neracaSaldo["isBalanced"].(bool))
+
+		arusKas, err := laporanService.UntukUnitUsaha(&toko.ID).GenerateLaporanArusKas(koperasi.ID, mulai, akhir, MetodeArusKasLangsung)
+		require.NoError(t, err)
+		assert.Equal(t, models.Rupiah(500000), arusKas.KenaikanKasBersih)
+	})
+
+	t.Run("laporan dikelompokkan per unit usaha", func(t *testing.T) {
+		perUnit, err := laporanService.KelompokkanPerUnitUsaha(koperasi.ID, func(ls *LaporanService) (interface{}, error) {
+			return ls.GenerateLaporanLabaRugi(koperasi.ID, mulai, akhir)
+		})
+		require.NoError(t, err)
+		require.Len(t, perUnit, 3)
+		assert.Equal(t, "FOTO", perUnit[0].KodeUnit)
+		assert.Equal(t, models.Rupiah(100000), perUnit[0].Laporan.(*LaporanLabaRugi).LabaRugiBersih)
+		assert.Nil(t, perUnit[2].IDUnitUsaha)
+	})
+
+	t.Run("kontribusi unit usaha sama dengan laba rugi koperasi", func(t *testing.T) {
+		kontribusi, err := laporanService.GenerateKontribusiUnitUsaha(koperasi.ID, mulai, akhir)
+		require.NoError(t, err)
+
+		labaRugi, err := laporanService.GenerateLaporanLabaRugi(koperasi.ID, mulai, akhir)
+		require.NoError(t, err)
+		assert.Equal(t, labaRugi.LabaRugiBersih, kontribusi.TotalSHU)
+		assert.Equal(t, labaRugi.TotalPendapatan, kontribusi.TotalPendapatan)
+
+		require.Len(t, kontribusi.UnitUsaha, 3)
+		assert.Equal(t, models.Rupiah(100000), kontribusi.UnitUsaha[0].SHU)
+		assert.Equal(t, 15.38, *kontribusi.UnitUsaha[0].PersentaseKontribusi)
+		assert.Equal(t, models.Rupiah(500000), kontribusi.UnitUsaha[1].SHU)
+		assert.Equal(t, namaTanpaUnitUsaha, kontribusi.UnitUsaha[2].NamaUnit)
+	})
+
+	t.Run("jurnal penutupan memindahkan SHU per unit usaha", func(t *testing.T) {
+		tanggalLalu := awalTahunBuku.AddDate(0, 0, -15)
+		require.NoError(t, jurnal(tanggalLalu, kas, penjualan, 300000, &toko.ID))
+		require.NoError(t, jurnal(tanggalLalu, gaji, kas, 80000, &fotokopi.ID))
+
+		periodeService := NewPeriodeService(db, transaksiService)
+		penutupan, err := periodeService.BuatJurnalPenutupan(koperasi.ID, admin, tahunLalu)
+		require.NoError(t, err)
+		require.NotNil(t, penutupan)
+
+		shuPerUnit := make(map[uuid.UUID]models.Uang)
+		for _, baris := range penutupan.BarisTransaksi {
+			if baris.KodeAkun == "3201" && baris.IDUnitUsaha != nil {
+				shuPerUnit[*baris.IDUnitUsaha] += baris.JumlahKredit - baris.JumlahDebit
+			}
+		}
+		assert.Equal(t, models.Rupiah(300000), shuPerUnit[toko.ID])
+		assert.Equal(t, models.Rupiah(-80000), shuPerUnit[fotokopi.ID])
+	})
+
+	t.Run("penutupan ulang membalik jurnal penutupan lama", func(t *testing.T) {
+		periodeService := NewPeriodeService(db, transaksiService)
+		var lama models.Transaksi
+		require.NoError(t, db.Where("id_koperasi = ? AND tipe_transaksi = ? AND dibalik = ?",
+			koperasi.ID, models.TipeTransaksiPenutupan, false).First(&lama).Error)
+
+		// Jurnal penyesuaian setelah penutupan ikut tertutup oleh jurnal penutupan baru
+		require.NoError(t, jurnal(awalTahunBuku.AddDate(0, 0, -10), kas, penjualan, 40000, &toko.ID))
+		baru, err := periodeService.BuatJurnalPenutupan(koperasi.ID, admin, tahunLalu)
+		require.NoError(t, err)
+		require.NotNil(t, baru)
+		assert.NotEqual(t, lama.ID, baru.ID)
+
+		require.NoError(t, db.First(&lama, lama.ID).Error)
+		assert.True(t, lama.Dibalik, "jurnal penutupan lama dibalik, tidak dihapus")
+		require.NotNil(t, lama.IDJurnalPembalik)
+
+		saldo, err := akunService.HitungSaldoAkun(penjualan.ID, awalTahunBuku.AddDate(0, 0, -1).Format("2006-01-02"))
+		require.NoError(t, err)
+		assert.Equal(t, models.Uang(0), saldo)
+	})
+
+	t.Run("unit usaha yang sudah dipakai tidak dapat dihapus", func(t *testing.T) {
+		assert.Error(t, unitUsahaService.HapusUnitUsaha(koperasi.ID, toko.ID))
+
+		nonaktif := false
+		unit, err := unitUsahaService.PerbaruiUnitUsaha(koperasi.ID, toko.ID, &PerbaruiUnitUsahaRequest{StatusAktif: &nonaktif})
+		require.NoError(t, err)
+		assert.False(t, unit.StatusAktif)
+	})
+}
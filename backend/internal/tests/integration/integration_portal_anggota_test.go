@@ -267,7 +267,7 @@ func TestPortalAnggotaSaldo(t *testing.T) {
 			IDAnggota:        anggotaID,
 			TipeSimpanan:     models.SimpananPokok,
 			TanggalTransaksi: tanggalTransaksi,
-			JumlahSetoran:    1000000,
+			JumlahSetoran:    models.Rupiah(1000000),
 			Keterangan:       "Simpanan Pokok",
 			NomorReferensi:   "SPK-001",
 		},
@@ -277,7 +277,7 @@ func TestPortalAnggotaSaldo(t *testing.T) {
 			IDAnggota:        anggotaID,
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: tanggalTransaksi,
-			JumlahSetoran:    500000,
+			JumlahSetoran:    models.Rupiah(500000),
 			Keterangan:       "Simpanan Wajib Januari",
 			NomorReferensi:   "SWJ-001",
 		},
@@ -287,7 +287,7 @@ func TestPortalAnggotaSaldo(t *testing.T) {
 			IDAnggota:        anggotaID,
 			TipeSimpanan:     models.SimpananSukarela,
 			TanggalTransaksi: tanggalTransaksi,
-			JumlahSetoran:    200000,
+			JumlahSetoran:    models.Rupiah(200000),
 			Keterangan:       "Simpanan Sukarela",
 			NomorReferensi:   "SSK-001",
 		},
@@ -344,7 +344,7 @@ func TestPortalAnggotaRiwayat(t *testing.T) {
 			IDAnggota:        anggotaID,
 			TipeSimpanan:     models.SimpananWajib,
 			TanggalTransaksi: tanggalTransaksi,
-			JumlahSetoran:    float64(i * 100000),
+			JumlahSetoran:    models.Rupiah(int64(i * 100000)),
 			Keterangan:       "Test Transaction",
 			NomorReferensi:   "TEST-" + string(rune(i)),
 		}
@@ -408,7 +408,7 @@ func TestPortalAnggotaRiwayat(t *testing.T) {
 			// Check pagination metadata
 			pagination := response["pagination"].(map[string]interface{})
 			assert.NotNil(t, pagination)
-			assert.Equal(t, float64(15), pagination["totalItems"])
+			assert.Equal(t, models.Rupiah(15), pagination["totalItems"])
 		})
 	}
 }
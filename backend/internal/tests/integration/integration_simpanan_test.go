@@ -66,7 +66,7 @@ func TestSimpananIntegration_TransactionCycle(t *testing.T) {
 	pokokReq := &services.CatatSetoranRequest{
 		IDAnggota:         anggota.ID,
 		TipeSimpanan:     models.SimpananPokok,
-		JumlahSetoran:    100000,
+		JumlahSetoran:    models.Rupiah(100000),
 		TanggalTransaksi:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 		Keterangan:        "Simpanan Pokok Awal",
 	}
@@ -76,17 +76,17 @@ func TestSimpananIntegration_TransactionCycle(t *testing.T) {
 		t.Fatalf("Failed to record simpanan pokok: %v", err)
 	}
 
-	if pokokSimpanan.JumlahSetoran != 100000 {
-		t.Errorf("Expected amount 100000, got %.0f", pokokSimpanan.JumlahSetoran)
+	if pokokSimpanan.JumlahSetoran != models.Rupiah(100000) {
+		t.Errorf("Expected amount 100000, got %s", pokokSimpanan.JumlahSetoran)
 	}
 
-	t.Logf("✓ Simpanan Pokok recorded: Rp %.0f", pokokSimpanan.JumlahSetoran)
+	t.Logf("✓ Simpanan Pokok recorded: Rp %s", pokokSimpanan.JumlahSetoran)
 
 	// Step 2: Record Simpanan Wajib (Mandatory Share) - Month 1
 	wajibReq1 := &services.CatatSetoranRequest{
 		IDAnggota:         anggota.ID,
 		TipeSimpanan:     models.SimpananWajib,
-		JumlahSetoran:    50000,
+		JumlahSetoran:    models.Rupiah(50000),
 		TanggalTransaksi:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 		Keterangan:        "Simpanan Wajib Januari",
 	}
@@ -96,13 +96,13 @@ func TestSimpananIntegration_TransactionCycle(t *testing.T) {
 		t.Fatalf("Failed to record simpanan wajib: %v", err)
 	}
 
-	t.Logf("✓ Simpanan Wajib Januari recorded: Rp %.0f", wajibSimpanan1.JumlahSetoran)
+	t.Logf("✓ Simpanan Wajib Januari recorded: Rp %s", wajibSimpanan1.JumlahSetoran)
 
 	// Step 3: Record Simpanan Wajib - Month 2
 	wajibReq2 := &services.CatatSetoranRequest{
 		IDAnggota:         anggota.ID,
 		TipeSimpanan:     models.SimpananWajib,
-		JumlahSetoran:    50000,
+		JumlahSetoran:    models.Rupiah(50000),
 		TanggalTransaksi:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 		Keterangan:        "Simpanan Wajib Februari",
 	}
@@ -118,7 +118,7 @@ func TestSimpananIntegration_TransactionCycle(t *testing.T) {
 	sukarelaReq := &services.CatatSetoranRequest{
 		IDAnggota:         anggota.ID,
 		TipeSimpanan:     models.SimpananSukarela,
-		JumlahSetoran:    75000,
+		JumlahSetoran:    models.Rupiah(75000),
 		TanggalTransaksi:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 		Keterangan:        "Simpanan Sukarela",
 	}
@@ -136,25 +136,25 @@ func TestSimpananIntegration_TransactionCycle(t *testing.T) {
 		t.Fatalf("Failed to get member balance: %v", err)
 	}
 
-	expectedTotal := float64(100000 + 50000 + 50000 + 75000) // 275000
+	expectedTotal := models.Rupiah(100000 + 50000 + 50000 + 75000) // 275000
 	if saldo.TotalSimpanan != expectedTotal {
-		t.Errorf("Expected total %.0f, got %.0f", expectedTotal, saldo.TotalSimpanan)
+		t.Errorf("Expected total %s, got %s", expectedTotal, saldo.TotalSimpanan)
 	}
-	if saldo.SimpananPokok != 100000 {
-		t.Errorf("Expected pokok 100000, got %.0f", saldo.SimpananPokok)
+	if saldo.SimpananPokok != models.Rupiah(100000) {
+		t.Errorf("Expected pokok 100000, got %s", saldo.SimpananPokok)
 	}
-	if saldo.SimpananWajib != 100000 {
-		t.Errorf("Expected wajib 100000, got %.0f", saldo.SimpananWajib)
+	if saldo.SimpananWajib != models.Rupiah(100000) {
+		t.Errorf("Expected wajib 100000, got %s", saldo.SimpananWajib)
 	}
-	if saldo.SimpananSukarela != 75000 {
-		t.Errorf("Expected sukarela 75000, got %.0f", saldo.SimpananSukarela)
+	if saldo.SimpananSukarela != models.Rupiah(75000) {
+		t.Errorf("Expected sukarela 75000, got %s", saldo.SimpananSukarela)
 	}
 
 	t.Logf("✓ Member balance calculated correctly:")
-	t.Logf("  - Pokok: Rp %.0f", saldo.SimpananPokok)
-	t.Logf("  - Wajib: Rp %.0f", saldo.SimpananWajib)
-	t.Logf("  - Sukarela: Rp %.0f", saldo.SimpananSukarela)
-	t.Logf("  - Total: Rp %.0f", saldo.TotalSimpanan)
+	t.Logf("  - Pokok: Rp %s", saldo.SimpananPokok)
+	t.Logf("  - Wajib: Rp %s", saldo.SimpananWajib)
+	t.Logf("  - Sukarela: Rp %s", saldo.SimpananSukarela)
+	t.Logf("  - Total: Rp %s", saldo.TotalSimpanan)
 
 	// Step 6: Get transaction history
 	riwayat, _, err := simpananService.DapatkanSemuaTransaksiSimpanan(koperasiID, "", &anggota.ID, "", "", 1, 10)
@@ -233,7 +233,7 @@ func TestSimpananIntegration_BalanceReporting(t *testing.T) {
 		pokokReq := &services.CatatSetoranRequest{
 			IDAnggota:         anggota.ID,
 			TipeSimpanan:     models.SimpananPokok,
-			JumlahSetoran:    float64(m.pokok),
+			JumlahSetoran:    models.Rupiah(int64(m.pokok)),
 			TanggalTransaksi:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 			Keterangan:        "Simpanan Pokok",
 		}
@@ -243,7 +243,7 @@ func TestSimpananIntegration_BalanceReporting(t *testing.T) {
 		wajibReq := &services.CatatSetoranRequest{
 			IDAnggota:         anggota.ID,
 			TipeSimpanan:     models.SimpananWajib,
-			JumlahSetoran:    float64(m.wajib),
+			JumlahSetoran:    models.Rupiah(int64(m.wajib)),
 			TanggalTransaksi:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 			Keterangan:        "Simpanan Wajib",
 		}
@@ -260,25 +260,25 @@ func TestSimpananIntegration_BalanceReporting(t *testing.T) {
 		t.Errorf("Expected 3 members in report, got %d", len(laporanSaldo))
 	}
 
-	var totalPokok, totalWajib, grandTotal float64
+	var totalPokok, totalWajib, grandTotal models.Uang
 	for _, saldo := range laporanSaldo {
 		totalPokok += saldo.SimpananPokok
 		totalWajib += saldo.SimpananWajib
 		grandTotal += saldo.TotalSimpanan
 
-		t.Logf("  %s: Pokok=Rp%.0f, Wajib=Rp%.0f, Total=Rp%.0f",
+		t.Logf("  %s: Pokok=Rp%s, Wajib=Rp%s, Total=Rp%s",
 			saldo.NamaAnggota, saldo.SimpananPokok, saldo.SimpananWajib, saldo.TotalSimpanan)
 	}
 
-	expectedTotal := float64((100000+100000+150000) + (50000+75000+60000))
+	expectedTotal := models.Rupiah((100000 + 100000 + 150000) + (50000 + 75000 + 60000))
 	if grandTotal != expectedTotal {
-		t.Errorf("Expected grand total %.0f, got %.0f", expectedTotal, grandTotal)
+		t.Errorf("Expected grand total %s, got %s", expectedTotal, grandTotal)
 	}
 
 	t.Logf("✓ Balance report totals:")
-	t.Logf("  - Total Pokok: Rp %.0f", totalPokok)
-	t.Logf("  - Total Wajib: Rp %.0f", totalWajib)
-	t.Logf("  - Grand Total: Rp %.0f", grandTotal)
+	t.Logf("  - Total Pokok: Rp %s", totalPokok)
+	t.Logf("  - Total Wajib: Rp %s", totalWajib)
+	t.Logf("  - Grand Total: Rp %s", grandTotal)
 
 	t.Log("✅ Balance reporting integration test passed")
 }
@@ -341,7 +341,7 @@ func TestSimpananIntegration_FilterByType(t *testing.T) {
 		req := &services.CatatSetoranRequest{
 			IDAnggota:         anggota.ID,
 			TipeSimpanan:     jenis,
-			JumlahSetoran:    float64(10000 * (i + 1)),
+			JumlahSetoran:    models.Rupiah(int64(10000 * (i + 1))),
 			TanggalTransaksi:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 			Keterangan:        "Test " + string(jenis),
 		}
@@ -444,7 +444,7 @@ func TestSimpananIntegration_DateRangeFilter(t *testing.T) {
 		req := &services.CatatSetoranRequest{
 			IDAnggota:         anggota.ID,
 			TipeSimpanan:     models.SimpananWajib,
-			JumlahSetoran:    50000,
+			JumlahSetoran:    models.Rupiah(50000),
 			TanggalTransaksi:  date,
 			Keterangan:        "Transaction on " + date.Format("2006-01-02"),
 		}
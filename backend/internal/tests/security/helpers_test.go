@@ -27,6 +27,9 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&models.Simpanan{},
 		&models.Transaksi{},
 		&models.BarisTransaksi{},
+		&models.PeriodeAkuntansi{},
+		&models.AturanPosting{},
+		&models.SaldoBulananAkun{},
 		&models.Produk{},
 		&models.Penjualan{},
 		&models.ItemPenjualan{},
@@ -309,7 +309,7 @@ func TestMultiTenant_ShareCapitalIsolation(t *testing.T) {
 		IDKoperasi:        koperasi1.ID,
 		IDAnggota:         memberA.ID,
 		TipeSimpanan:     "pokok",
-		JumlahSetoran:            100000,
+		JumlahSetoran:            models.Rupiah(100000),
 		TanggalTransaksi:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		NomorReferensi:    "REF-A001",
 		Keterangan:        "Simpanan Pokok",
@@ -321,7 +321,7 @@ func TestMultiTenant_ShareCapitalIsolation(t *testing.T) {
 		IDKoperasi:        koperasi2.ID,
 		IDAnggota:         memberB.ID,
 		TipeSimpanan:     "pokok",
-		JumlahSetoran:            200000,
+		JumlahSetoran:            models.Rupiah(200000),
 		TanggalTransaksi:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 		NomorReferensi:    "REF-B001",
 		Keterangan:        "Simpanan Pokok",
@@ -0,0 +1,311 @@
+// Package pdf menyediakan penulis dokumen PDF sederhana tanpa dependensi eksternal.
+//
+// Package ini hanya mendukung teks dengan font standar Helvetica (biasa dan tebal), garis,
+// dan gambar raster (PNG, JPEG, GIF). Cukup untuk mencetak laporan tabular dengan kop surat.
+// Koordinat diukur dalam point (1/72 inci) dari pojok kiri atas halaman.
+//
+// Penggunaan:
+//
+//	dok := pdf.Baru(pdf.LebarA4, pdf.TinggiA4)
+//	dok.TambahHalaman()
+//	dok.Teks(40, 60, 14, true, "Laporan Posisi Keuangan")
+//	dok.Garis(40, 66, 555, 66, 0.5)
+//	if err := dok.Tulis(w); err != nil {
+//	    return err
+//	}
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // Registrasi decoder GIF untuk Gambar
+	_ "image/jpeg" // Registrasi decoder JPEG untuk Gambar
+	_ "image/png"  // Registrasi decoder PNG untuk Gambar
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Ukuran kertas A4 dalam point
+const (
+	LebarA4  = 595.28
+	TinggiA4 = 841.89
+)
+
+// maksPikselGambar membatasi ukuran gambar agar decode tidak menghabiskan memori
+const maksPikselGambar = 4_000_000
+
+// ErrGambarTidakValid dikembalikan jika data gambar tidak dapat dibaca atau terlalu besar
+var ErrGambarTidakValid = errors.New("gambar tidak valid atau terlalu besar")
+
+// Dokumen adalah dokumen PDF yang sedang disusun
+type Dokumen struct {
+	lebar, tinggi float64
+	halaman       []*bytes.Buffer
+	aktif         int
+	gambar        []gambarPDF
+}
+
+// gambarPDF adalah gambar yang sudah diubah menjadi piksel RGB terkompresi
+type gambarPDF struct {
+	lebar, tinggi int
+	data          []byte
+}
+
+// Baru membuat dokumen kosong dengan ukuran halaman dalam point
+func Baru(lebar, tinggi float64) *Dokumen {
+	return &Dokumen{lebar: lebar, tinggi: tinggi}
+}
+
+// Lebar mengembalikan lebar halaman dalam point
+func (d *Dokumen) Lebar() float64 {
+	return d.lebar
+}
+
+// Tinggi mengembalikan tinggi halaman dalam point
+func (d *Dokumen) Tinggi() float64 {
+	return d.tinggi
+}
+
+// TambahHalaman menambahkan halaman baru dan menjadikannya halaman aktif
+func (d *Dokumen) TambahHalaman() {
+	d.halaman = append(d.halaman, &bytes.Buffer{})
+	d.aktif = len(d.halaman) - 1
+}
+
+// JumlahHalaman mengembalikan banyaknya halaman dokumen
+func (d *Dokumen) JumlahHalaman() int {
+	return len(d.halaman)
+}
+
+// KeHalaman menjadikan halaman ke-nomor (mulai dari 1) sebagai halaman aktif,
+// misalnya untuk menulis nomor halaman setelah seluruh isi tersusun
+func (d *Dokumen) KeHalaman(nomor int) {
+	if nomor >= 1 && nomor <= len(d.halaman) {
+		d.aktif = nomor - 1
+	}
+}
+
+// isi mengembalikan content stream halaman aktif, membuat halaman pertama jika belum ada
+func (d *Dokumen) isi() *bytes.Buffer {
+	if len(d.halaman) == 0 {
+		d.TambahHalaman()
+	}
+	return d.halaman[d.aktif]
+}
+
+// Teks menulis satu baris teks dengan garis dasar (baseline) pada y
+func (d *Dokumen) Teks(x, y, ukuran float64, tebal bool, teks string) {
+	font := "F1"
+	if tebal {
+		font = "F2"
+	}
+	fmt.Fprintf(d.isi(), "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, angka(ukuran), angka(x), angka(d.tinggi-y), escapeTeks(teks))
+}
+
+// Garis menggambar garis lurus dari (x1, y1) ke (x2, y2)
+func (d *Dokumen) Garis(x1, y1, x2, y2, ketebalan float64) {
+	fmt.Fprintf(d.isi(), "%s w %s %s m %s %s l S\n",
+		angka(ketebalan), angka(x1), angka(d.tinggi-y1), angka(x2), angka(d.tinggi-y2))
+}
+
+// Gambar menempatkan gambar PNG, JPEG atau GIF di dalam kotak lebarMaks x tinggiMaks dengan
+// pojok kiri atas pada (x, y), tanpa mengubah rasio gambar. Bagian transparan digambar di atas
+// latar putih. Mengembalikan lebar gambar yang tercetak.
+func (d *Dokumen) Gambar(data []byte, x, y, lebarMaks, tinggiMaks float64) (float64, error) {
+	konfigurasi, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || konfigurasi.Width <= 0 || konfigurasi.Height <= 0 ||
+		konfigurasi.Width*konfigurasi.Height > maksPikselGambar {
+		return 0, ErrGambarTidakValid
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, ErrGambarTidakValid
+	}
+
+	skala := math.Min(lebarMaks/float64(konfigurasi.Width), tinggiMaks/float64(konfigurasi.Height))
+	lebar, tinggi := float64(konfigurasi.Width)*skala, float64(konfigurasi.Height)*skala
+
+	batas := img.Bounds()
+	piksel := make([]byte, 0, batas.Dx()*batas.Dy()*3)
+	for py := batas.Min.Y; py < batas.Max.Y; py++ {
+		for px := batas.Min.X; px < batas.Max.X; px++ {
+			c := color.NRGBAModel.Convert(img.At(px, py)).(color.NRGBA)
+			alpha := uint32(c.A)
+			piksel = append(piksel,
+				byte((uint32(c.R)*alpha+255*(255-alpha))/255),
+				byte((uint32(c.G)*alpha+255*(255-alpha))/255),
+				byte((uint32(c.B)*alpha+255*(255-alpha))/255),
+			)
+		}
+	}
+
+	var terkompresi bytes.Buffer
+	zw := zlib.NewWriter(&terkompresi)
+	if _, err := zw.Write(piksel); err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	d.gambar = append(d.gambar, gambarPDF{lebar: batas.Dx(), tinggi: batas.Dy(), data: terkompresi.Bytes()})
+	fmt.Fprintf(d.isi(), "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+		angka(lebar), angka(tinggi), angka(x), angka(d.tinggi-y-tinggi), len(d.gambar))
+	return lebar, nil
+}
+
+// Tulis menulis dokumen sebagai berkas PDF 1.4
+func (d *Dokumen) Tulis(w io.Writer) error {
+	if len(d.halaman) == 0 {
+		d.TambahHalaman()
+	}
+
+	// Nomor objek: 1 katalog, 2 daftar halaman, 3-4 font, lalu gambar, lalu pasangan halaman dan isinya
+	const objekGambarPertama = 5
+	objekHalamanPertama := objekGambarPertama + len(d.gambar)
+
+	var buf bytes.Buffer
+	offset := []int{0}
+	mulaiObjek := func() {
+		offset = append(offset, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n", len(offset)-1)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	mulaiObjek()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	kids := make([]string, len(d.halaman))
+	for i := range d.halaman {
+		kids[i] = strconv.Itoa(objekHalamanPertama+i*2) + " 0 R"
+	}
+	mulaiObjek()
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(d.halaman))
+
+	for _, font := range []string{"Helvetica", "Helvetica-Bold"} {
+		mulaiObjek()
+		fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", font)
+	}
+
+	for _, g := range d.gambar {
+		mulaiObjek()
+		fmt.Fprintf(&buf, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB "+
+			"/BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n", g.lebar, g.tinggi, len(g.data))
+		buf.Write(g.data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	var xobject strings.Builder
+	for i := range d.gambar {
+		fmt.Fprintf(&xobject, " /Im%d %d 0 R", i+1, objekGambarPertama+i)
+	}
+	for i, isi := range d.halaman {
+		mulaiObjek()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> /XObject <<%s >> >> /Contents %d 0 R >>\nendobj\n",
+			angka(d.lebar), angka(d.tinggi), xobject.String(), objekHalamanPertama+i*2+1)
+
+		mulaiObjek()
+		fmt.Fprintf(&buf, "<< /Length %d >>\nstream\n", isi.Len())
+		buf.Write(isi.Bytes())
+		buf.WriteString("endstream\nendobj\n")
+	}
+
+	awalXref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offset))
+	for _, o := range offset[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offset), awalXref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// LebarTeks menghitung lebar teks dalam point untuk font Helvetica, dipakai untuk meratakan
+// teks ke kanan dan memotong teks yang terlalu panjang
+func LebarTeks(teks string, ukuran float64, tebal bool) float64 {
+	tabel := &lebarHelvetica
+	if tebal {
+		tabel = &lebarHelveticaTebal
+	}
+
+	total := 0
+	for _, b := range []byte(encodeWinAnsi(teks)) {
+		if b >= 32 && b <= 126 {
+			total += int(tabel[b-32])
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * ukuran / 1000
+}
+
+// encodeWinAnsi mengubah teks UTF-8 ke WinAnsiEncoding; karakter yang tidak terwakili menjadi "?"
+func encodeWinAnsi(teks string) string {
+	var sb strings.Builder
+	for _, r := range teks {
+		switch {
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			sb.WriteByte(byte(r))
+		case r == '€':
+			sb.WriteByte(0x80)
+		case r == '‘':
+			sb.WriteByte(0x91)
+		case r == '’':
+			sb.WriteByte(0x92)
+		case r == '“':
+			sb.WriteByte(0x93)
+		case r == '”':
+			sb.WriteByte(0x94)
+		case r == '•':
+			sb.WriteByte(0x95)
+		case r == '–':
+			sb.WriteByte(0x96)
+		case r == '—':
+			sb.WriteByte(0x97)
+		case r == '\t':
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
+
+// escapeTeks menyiapkan teks sebagai string literal PDF
+func escapeTeks(teks string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(encodeWinAnsi(teks))
+}
+
+// angka memformat koordinat dengan paling banyak dua desimal
+func angka(nilai float64) string {
+	return strconv.FormatFloat(math.Round(nilai*100)/100, 'f', -1, 64)
+}
+
+// Lebar karakter ASCII 32-126 (per 1000 unit ukuran font) dari metrik standar Adobe
+var lebarHelvetica = [95]uint16{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var lebarHelveticaTebal = [95]uint16{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
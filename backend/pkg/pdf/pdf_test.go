@@ -0,0 +1,103 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestTulis menguji struktur berkas: header, objek halaman, dan tabel xref yang menunjuk ke setiap objek
+func TestTulis(t *testing.T) {
+	dok := Baru(LebarA4, TinggiA4)
+	dok.TambahHalaman()
+	dok.Teks(40, 60, 14, true, "Neraca (Per 31 Desember)")
+	dok.Garis(40, 66, 555, 66, 0.5)
+	dok.TambahHalaman()
+	dok.Teks(40, 60, 10, false, `Kas \ Bank`)
+
+	var gambar bytes.Buffer
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+	img.Set(1, 1, color.NRGBA{R: 200, A: 255})
+	if err := png.Encode(&gambar, img); err != nil {
+		t.Fatal(err)
+	}
+	lebar, err := dok.Gambar(gambar.Bytes(), 40, 20, 100, 30)
+	if err != nil {
+		t.Fatalf("Gambar() error = %v", err)
+	}
+	if lebar != 40 {
+		t.Errorf("Gambar() lebar = %v, want 40 (rasio 4:3 dalam tinggi 30)", lebar)
+	}
+
+	var buf bytes.Buffer
+	if err := dok.Tulis(&buf); err != nil {
+		t.Fatalf("Tulis() error = %v", err)
+	}
+	isi := buf.String()
+
+	if !strings.HasPrefix(isi, "%PDF-1.4\n") || !strings.HasSuffix(isi, "%%EOF\n") {
+		t.Fatalf("header atau trailer PDF tidak valid")
+	}
+	for _, diharapkan := range []string{
+		"/Count 2",
+		`(Neraca \(Per 31 Desember\)) Tj`,
+		`(Kas \\ Bank) Tj`,
+		"/Width 4 /Height 3",
+		"q 40 0 0 30 40 791.89 cm /Im1 Do Q",
+	} {
+		if !strings.Contains(isi, diharapkan) {
+			t.Errorf("PDF tidak berisi %q", diharapkan)
+		}
+	}
+
+	awalXref, err := strconv.Atoi(strings.TrimSpace(isi[strings.LastIndex(isi, "startxref")+len("startxref") : strings.LastIndex(isi, "%%EOF")]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	baris := strings.Split(isi[awalXref:], "\n")
+	var jumlah int
+	if _, err := fmt.Sscanf(baris[1], "0 %d", &jumlah); err != nil {
+		t.Fatal(err)
+	}
+	for nomor := 1; nomor < jumlah; nomor++ {
+		offset, err := strconv.Atoi(baris[2+nomor][:10])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(isi[offset:], fmt.Sprintf("%d 0 obj\n", nomor)) {
+			t.Errorf("xref objek %d menunjuk ke offset yang salah", nomor)
+		}
+	}
+}
+
+// TestGambar_TidakValid menguji data yang bukan gambar ditolak
+func TestGambar_TidakValid(t *testing.T) {
+	dok := Baru(LebarA4, TinggiA4)
+	if _, err := dok.Gambar([]byte("<svg></svg>"), 0, 0, 10, 10); err != ErrGambarTidakValid {
+		t.Errorf("Gambar() error = %v, want %v", err, ErrGambarTidakValid)
+	}
+}
+
+// TestLebarTeks menguji metrik font dan pengkodean karakter di luar ASCII
+func TestLebarTeks(t *testing.T) {
+	for i := range lebarHelvetica {
+		if lebarHelvetica[i] == 0 || lebarHelveticaTebal[i] == 0 {
+			t.Fatalf("lebar karakter %q kosong", rune(i+32))
+		}
+	}
+
+	if got := LebarTeks("1.000,00", 10, false); got != 38.92 {
+		t.Errorf("LebarTeks() = %v, want 38.92", got)
+	}
+	if LebarTeks("Kas", 10, true) <= LebarTeks("Kas", 10, false) {
+		t.Errorf("teks tebal seharusnya lebih lebar")
+	}
+	if got := encodeWinAnsi("Rp 1.000 – é 漢"); got != "Rp 1.000 \x96 \xe9 ?" {
+		t.Errorf("encodeWinAnsi() = %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package penyimpanan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Lokal menyimpan berkas di direktori filesystem lokal
+type Lokal struct {
+	direktori string
+}
+
+// BaruLokal membuat penyimpanan lokal di direktori; direktori dibuat jika belum ada
+func BaruLokal(direktori string) (*Lokal, error) {
+	abs, err := filepath.Abs(direktori)
+	if err != nil {
+		return nil, fmt.Errorf("direktori penyimpanan tidak valid: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0o750); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori penyimpanan: %w", err)
+	}
+	return &Lokal{direktori: abs}, nil
+}
+
+// path mengubah kunci menjadi path di dalam direktori penyimpanan
+func (l *Lokal) path(kunci string) (string, error) {
+	if err := ValidasiKunci(kunci); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.direktori, filepath.FromSlash(kunci)), nil
+}
+
+// Simpan menulis berkas ke file sementara lalu me-rename-nya, sehingga pembaca tidak
+// pernah melihat berkas yang baru setengah tertulis
+func (l *Lokal) Simpan(ctx context.Context, kunci string, isi io.Reader, ukuran int64, tipeKonten string) error {
+	tujuan, err := l.path(kunci)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(tujuan), 0o750); err != nil {
+		return fmt.Errorf("gagal membuat direktori berkas: %w", err)
+	}
+
+	sementara, err := os.CreateTemp(filepath.Dir(tujuan), ".unggah-*")
+	if err != nil {
+		return fmt.Errorf("gagal membuat berkas sementara: %w", err)
+	}
+	defer os.Remove(sementara.Name()) // No-op setelah rename berhasil
+
+	if _, err := io.Copy(sementara, isi); err != nil {
+		sementara.Close()
+		return fmt.Errorf("gagal menulis berkas: %w", err)
+	}
+	if err := sementara.Close(); err != nil {
+		return fmt.Errorf("gagal menulis berkas: %w", err)
+	}
+	if err := os.Rename(sementara.Name(), tujuan); err != nil {
+		return fmt.Errorf("gagal menyimpan berkas: %w", err)
+	}
+	return nil
+}
+
+// Buka membuka berkas untuk dibaca
+func (l *Lokal) Buka(ctx context.Context, kunci string) (io.ReadCloser, error) {
+	sumber, err := l.path(kunci)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(sumber)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrTidakDitemukan
+		}
+		return nil, fmt.Errorf("gagal membuka berkas: %w", err)
+	}
+	return file, nil
+}
+
+// Hapus menghapus berkas; berkas yang sudah tidak ada diabaikan
+func (l *Lokal) Hapus(ctx context.Context, kunci string) error {
+	sumber, err := l.path(kunci)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(sumber); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("gagal menghapus berkas: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package penyimpanan menyediakan abstraksi penyimpanan berkas (bukti transaksi, scan KTP)
+// dengan backend filesystem lokal dan backend S3-compatible yang dapat dipasang.
+//
+// Berkas dialamatkan dengan kunci relatif berbentuk path yang dipisahkan "/", misalnya
+// "<idKoperasi>/transaksi/<idTransaksi>/<idLampiran>.pdf". Kunci yang keluar dari akar
+// penyimpanan ("..", path absolut) ditolak oleh semua backend.
+//
+// Penggunaan:
+//
+//	store, err := penyimpanan.BaruLokal("./data/lampiran")
+//	if err != nil {
+//	    return err
+//	}
+//	err = store.Simpan(ctx, kunci, bytes.NewReader(data), int64(len(data)), "application/pdf")
+//	isi, err := store.Buka(ctx, kunci)
+package penyimpanan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrTidakDitemukan dikembalikan jika berkas dengan kunci tersebut tidak ada
+var ErrTidakDitemukan = errors.New("berkas tidak ditemukan di penyimpanan")
+
+// ErrKunciTidakValid dikembalikan untuk kunci kosong atau yang keluar dari akar penyimpanan
+var ErrKunciTidakValid = errors.New("kunci berkas tidak valid")
+
+// Penyimpanan adalah backend penyimpanan berkas
+type Penyimpanan interface {
+	// Simpan menulis isi berkas ke kunci; berkas lama dengan kunci yang sama ditimpa
+	Simpan(ctx context.Context, kunci string, isi io.Reader, ukuran int64, tipeKonten string) error
+	// Buka membuka berkas untuk dibaca; pemanggil wajib menutup reader
+	Buka(ctx context.Context, kunci string) (io.ReadCloser, error)
+	// Hapus menghapus berkas; menghapus berkas yang tidak ada bukan error
+	Hapus(ctx context.Context, kunci string) error
+}
+
+// ValidasiKunci memastikan kunci relatif, tidak kosong, dan tidak mengandung segmen ".."
+func ValidasiKunci(kunci string) error {
+	if kunci == "" || strings.HasPrefix(kunci, "/") || strings.Contains(kunci, "\\") {
+		return ErrKunciTidakValid
+	}
+	for _, segmen := range strings.Split(kunci, "/") {
+		if segmen == "" || segmen == "." || segmen == ".." {
+			return ErrKunciTidakValid
+		}
+	}
+	return nil
+}
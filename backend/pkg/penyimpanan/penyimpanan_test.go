@@ -0,0 +1,139 @@
+package penyimpanan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestValidasiKunci menguji kunci yang keluar dari akar penyimpanan ditolak
+func TestValidasiKunci(t *testing.T) {
+	tests := []struct {
+		kunci string
+		valid bool
+	}{
+		{"koperasi/transaksi/a.pdf", true},
+		{"a.pdf", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../rahasia.pdf", false},
+		{"koperasi/../../rahasia.pdf", false},
+		{"koperasi//a.pdf", false},
+		{"koperasi/./a.pdf", false},
+		{"koperasi\\a.pdf", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidasiKunci(tt.kunci)
+		if tt.valid && err != nil {
+			t.Errorf("ValidasiKunci(%q) error = %v, want nil", tt.kunci, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrKunciTidakValid) {
+			t.Errorf("ValidasiKunci(%q) error = %v, want ErrKunciTidakValid", tt.kunci, err)
+		}
+	}
+}
+
+// ujiPenyimpanan menjalankan siklus simpan-buka-hapus yang sama untuk setiap backend
+func ujiPenyimpanan(t *testing.T, store Penyimpanan) {
+	t.Helper()
+	ctx := context.Background()
+	kunci := "koperasi-1/transaksi/jurnal-1/bukti.pdf"
+
+	if err := store.Simpan(ctx, kunci, bytes.NewReader([]byte("versi 1")), 7, "application/pdf"); err != nil {
+		t.Fatalf("Simpan() error = %v", err)
+	}
+	if err := store.Simpan(ctx, kunci, bytes.NewReader([]byte("versi 2")), 7, "application/pdf"); err != nil {
+		t.Fatalf("Simpan() timpa error = %v", err)
+	}
+
+	isi, err := store.Buka(ctx, kunci)
+	if err != nil {
+		t.Fatalf("Buka() error = %v", err)
+	}
+	data, _ := io.ReadAll(isi)
+	isi.Close()
+	if string(data) != "versi 2" {
+		t.Errorf("Buka() = %q, want %q", data, "versi 2")
+	}
+
+	if err := store.Hapus(ctx, kunci); err != nil {
+		t.Fatalf("Hapus() error = %v", err)
+	}
+	if err := store.Hapus(ctx, kunci); err != nil {
+		t.Errorf("Hapus() berkas yang sudah tidak ada error = %v", err)
+	}
+	if _, err := store.Buka(ctx, kunci); !errors.Is(err, ErrTidakDitemukan) {
+		t.Errorf("Buka() setelah hapus error = %v, want ErrTidakDitemukan", err)
+	}
+
+	if err := store.Simpan(ctx, "../luar.pdf", bytes.NewReader(nil), 0, "application/pdf"); !errors.Is(err, ErrKunciTidakValid) {
+		t.Errorf("Simpan() di luar akar error = %v, want ErrKunciTidakValid", err)
+	}
+}
+
+// TestLokal menguji penyimpanan filesystem lokal
+func TestLokal(t *testing.T) {
+	direktori := filepath.Join(t.TempDir(), "lampiran")
+	store, err := BaruLokal(direktori)
+	if err != nil {
+		t.Fatalf("BaruLokal() error = %v", err)
+	}
+
+	ujiPenyimpanan(t, store)
+
+	// Tidak ada berkas sementara yang tertinggal
+	sisa, _ := filepath.Glob(filepath.Join(direktori, "koperasi-1", "transaksi", "jurnal-1", "*"))
+	if len(sisa) != 0 {
+		t.Errorf("berkas tertinggal: %v", sisa)
+	}
+	if _, err := os.Stat(direktori); err != nil {
+		t.Errorf("direktori penyimpanan tidak dibuat: %v", err)
+	}
+}
+
+// klienObjekMemori adalah KlienObjek in-memory untuk pengujian
+type klienObjekMemori struct {
+	objek map[string][]byte
+}
+
+func (k *klienObjekMemori) PutObject(ctx context.Context, bucket, kunci string, isi io.Reader, ukuran int64, tipeKonten string) error {
+	data, err := io.ReadAll(isi)
+	if err != nil {
+		return err
+	}
+	k.objek[bucket+"/"+kunci] = data
+	return nil
+}
+
+func (k *klienObjekMemori) GetObject(ctx context.Context, bucket, kunci string) (io.ReadCloser, error) {
+	data, ada := k.objek[bucket+"/"+kunci]
+	if !ada {
+		return nil, ErrTidakDitemukan
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (k *klienObjekMemori) RemoveObject(ctx context.Context, bucket, kunci string) error {
+	delete(k.objek, bucket+"/"+kunci)
+	return nil
+}
+
+// TestS3 menguji penyimpanan S3 menambahkan prefix ke kunci objek
+func TestS3(t *testing.T) {
+	klien := &klienObjekMemori{objek: map[string][]byte{}}
+	store := BaruS3(klien, "bukti", "/produksi/")
+
+	ujiPenyimpanan(t, store)
+
+	if err := store.Simpan(context.Background(), "a/b.png", bytes.NewReader([]byte("png")), 3, "image/png"); err != nil {
+		t.Fatalf("Simpan() error = %v", err)
+	}
+	if _, ada := klien.objek["bukti/produksi/a/b.png"]; !ada {
+		t.Errorf("objek tidak disimpan dengan prefix, isi bucket: %v", klien.objek)
+	}
+}
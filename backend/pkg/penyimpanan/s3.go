@@ -0,0 +1,74 @@
+package penyimpanan
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// KlienObjek adalah operasi minimum object storage S3-compatible (AWS S3, MinIO, Cloudflare R2,
+// dan sejenisnya). Implementasinya membungkus SDK pilihan, misalnya minio-go atau aws-sdk-go-v2,
+// sehingga package ini tidak bergantung pada SDK tertentu.
+//
+// GetObject wajib mengembalikan ErrTidakDitemukan jika objek tidak ada (NoSuchKey), dan
+// RemoveObject tidak boleh mengembalikan error untuk objek yang tidak ada.
+type KlienObjek interface {
+	PutObject(ctx context.Context, bucket, kunci string, isi io.Reader, ukuran int64, tipeKonten string) error
+	GetObject(ctx context.Context, bucket, kunci string) (io.ReadCloser, error)
+	RemoveObject(ctx context.Context, bucket, kunci string) error
+}
+
+// S3 menyimpan berkas di bucket S3-compatible melalui KlienObjek
+type S3 struct {
+	klien  KlienObjek
+	bucket string
+	prefix string
+}
+
+// BaruS3 membuat penyimpanan S3. Prefix (opsional) ditambahkan di depan setiap kunci agar
+// beberapa lingkungan dapat berbagi satu bucket.
+func BaruS3(klien KlienObjek, bucket, prefix string) *S3 {
+	return &S3{
+		klien:  klien,
+		bucket: bucket,
+		prefix: strings.Trim(prefix, "/"),
+	}
+}
+
+// kunciObjek menggabungkan prefix dengan kunci berkas
+func (s *S3) kunciObjek(kunci string) (string, error) {
+	if err := ValidasiKunci(kunci); err != nil {
+		return "", err
+	}
+	if s.prefix == "" {
+		return kunci, nil
+	}
+	return s.prefix + "/" + kunci, nil
+}
+
+// Simpan mengunggah berkas ke bucket
+func (s *S3) Simpan(ctx context.Context, kunci string, isi io.Reader, ukuran int64, tipeKonten string) error {
+	objek, err := s.kunciObjek(kunci)
+	if err != nil {
+		return err
+	}
+	return s.klien.PutObject(ctx, s.bucket, objek, isi, ukuran, tipeKonten)
+}
+
+// Buka mengunduh berkas dari bucket
+func (s *S3) Buka(ctx context.Context, kunci string) (io.ReadCloser, error) {
+	objek, err := s.kunciObjek(kunci)
+	if err != nil {
+		return nil, err
+	}
+	return s.klien.GetObject(ctx, s.bucket, objek)
+}
+
+// Hapus menghapus berkas dari bucket
+func (s *S3) Hapus(ctx context.Context, kunci string) error {
+	objek, err := s.kunciObjek(kunci)
+	if err != nil {
+		return err
+	}
+	return s.klien.RemoveObject(ctx, s.bucket, objek)
+}
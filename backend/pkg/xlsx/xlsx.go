@@ -0,0 +1,406 @@
+// Package xlsx menyediakan baca-tulis workbook Excel (.xlsx) sederhana tanpa dependensi eksternal.
+//
+// Package ini hanya menangani satu lembar kerja berisi data tabular. Tulis menulis setiap
+// baris sebagai slice string, cukup untuk impor/ekspor data master seperti Chart of Accounts.
+// TulisSel menulis sel bertipe (teks, angka, rumus) dengan huruf tebal dan format ribuan,
+// dipakai untuk ekspor laporan keuangan.
+//
+// Penggunaan:
+//
+//	var buf bytes.Buffer
+//	if err := xlsx.Tulis(&buf, "COA", baris); err != nil {
+//	    return err
+//	}
+//
+//	baris, err := xlsx.Baca(bytes.NewReader(data), int64(len(data)))
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrBukanXLSX dikembalikan jika data bukan workbook xlsx yang valid
+var ErrBukanXLSX = errors.New("file bukan workbook xlsx yang valid")
+
+const (
+	tipeKontenWorkbook  = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"
+	tipeKontenWorksheet = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
+	relasiDokumen       = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	relasiWorksheet     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"
+	tipeKontenStyles    = "application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"
+	relasiStyles        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+)
+
+// isiStyles mendefinisikan style sel sesuai urutan konstanta style*: teks, teks tebal,
+// angka #,##0.00 dan angka tebal
+const isiStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="#,##0.00"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="164" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
+
+// Sel adalah satu sel lembar kerja untuk TulisSel
+type Sel struct {
+	Nilai string // Teks, atau angka dengan titik desimal jika Angka (misalnya "-1500.05")
+	Angka bool   // Nilai ditulis sebagai angka dengan format ribuan dua desimal
+	Rumus string // Rumus tanpa tanda "=", misalnya "SUM(C2:C10)"; Nilai menjadi hasil yang tersimpan
+	Tebal bool   // Huruf tebal untuk judul dan total
+}
+
+// Indeks style pada styles.xml yang ditulis TulisSel
+const (
+	styleTeks = iota
+	styleTeksTebal
+	styleAngka
+	styleAngkaTebal
+)
+
+// Tulis menulis baris data sebagai workbook xlsx dengan satu lembar kerja.
+// Semua sel ditulis sebagai teks (inline string) agar kode seperti "1101-01" tidak berubah.
+func Tulis(w io.Writer, namaSheet string, baris [][]string) error {
+	sel := make([][]Sel, len(baris))
+	for i, nilaiBaris := range baris {
+		sel[i] = make([]Sel, len(nilaiBaris))
+		for j, nilai := range nilaiBaris {
+			sel[i][j] = Sel{Nilai: nilai}
+		}
+	}
+	return TulisSel(w, namaSheet, sel)
+}
+
+// TulisSel menulis sel bertipe sebagai workbook xlsx dengan satu lembar kerja.
+// Workbook ditandai untuk dihitung ulang saat dibuka sehingga rumus selalu mutakhir.
+func TulisSel(w io.Writer, namaSheet string, baris [][]Sel) error {
+	if namaSheet == "" {
+		namaSheet = "Sheet1"
+	}
+
+	zw := zip.NewWriter(w)
+	berkas := []struct {
+		nama string
+		isi  string
+	}{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="` + tipeKontenWorkbook + `"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="` + tipeKontenWorksheet + `"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="` + tipeKontenStyles + `"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relasiDokumen + `" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + escapeXML(namaSheet) + `" sheetId="1" r:id="rId1"/></sheets>` +
+			`<calcPr fullCalcOnLoad="1"/>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + relasiWorksheet + `" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="` + relasiStyles + `" Target="styles.xml"/>` +
+			`</Relationships>`},
+		{"xl/styles.xml", isiStyles},
+		{"xl/worksheets/sheet1.xml", isiWorksheet(baris)},
+	}
+
+	for _, b := range berkas {
+		fw, err := zw.Create(b.nama)
+		if err != nil {
+			return fmt.Errorf("gagal menulis %s: %w", b.nama, err)
+		}
+		if _, err := io.WriteString(fw, b.isi); err != nil {
+			return fmt.Errorf("gagal menulis %s: %w", b.nama, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// isiWorksheet menyusun XML lembar kerja dari baris data
+func isiWorksheet(baris [][]Sel) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, selBaris := range baris {
+		nomorBaris := strconv.Itoa(i + 1)
+		sb.WriteString(`<row r="` + nomorBaris + `">`)
+		for j, sel := range selBaris {
+			if sel.Nilai == "" && sel.Rumus == "" {
+				continue
+			}
+
+			style := styleTeks
+			if sel.Angka {
+				style = styleAngka
+			}
+			if sel.Tebal {
+				style++
+			}
+			atributStyle := ""
+			if style != styleTeks {
+				atributStyle = ` s="` + strconv.Itoa(style) + `"`
+			}
+
+			referensi := namaKolom(j) + nomorBaris
+			switch {
+			case sel.Angka:
+				sb.WriteString(`<c r="` + referensi + `"` + atributStyle + `>`)
+				if sel.Rumus != "" {
+					sb.WriteString(`<f>` + escapeXML(sel.Rumus) + `</f>`)
+				}
+				if sel.Nilai != "" {
+					sb.WriteString(`<v>` + escapeXML(sel.Nilai) + `</v>`)
+				}
+				sb.WriteString(`</c>`)
+			case sel.Rumus != "":
+				sb.WriteString(`<c r="` + referensi + `"` + atributStyle + ` t="str"><f>` + escapeXML(sel.Rumus) + `</f>`)
+				sb.WriteString(`<v>` + escapeXML(sel.Nilai) + `</v></c>`)
+			default:
+				sb.WriteString(`<c r="` + referensi + `"` + atributStyle + ` t="inlineStr"><is><t xml:space="preserve">`)
+				sb.WriteString(escapeXML(sel.Nilai))
+				sb.WriteString(`</t></is></c>`)
+			}
+		}
+		sb.WriteString(`</row>`)
+	}
+	sb.WriteString(`</sheetData></worksheet>`)
+	return sb.String()
+}
+
+// Referensi mengembalikan referensi sel Excel dari indeks baris dan kolom (0-based): (0, 2) → C1
+func Referensi(baris, kolom int) string {
+	return namaKolom(kolom) + strconv.Itoa(baris+1)
+}
+
+// Baca membaca seluruh baris dari lembar kerja pertama workbook xlsx.
+// Sel kosong di tengah baris diisi string kosong; baris kosong di antara data dipertahankan.
+func Baca(r io.ReaderAt, ukuran int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, ukuran)
+	if err != nil {
+		return nil, ErrBukanXLSX
+	}
+
+	berkas := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		berkas[f.Name] = f
+	}
+
+	lokasiSheet, err := cariSheetPertama(berkas)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings []string
+	if f, ada := berkas["xl/sharedStrings.xml"]; ada {
+		sharedStrings, err = bacaSharedStrings(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, ada := berkas[lokasiSheet]
+	if !ada {
+		return nil, ErrBukanXLSX
+	}
+
+	var ws struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R  string `xml:"r,attr"`
+				T  string `xml:"t,attr"`
+				V  string `xml:"v"`
+				Is struct {
+					T string `xml:"t"`
+					R []struct {
+						T string `xml:"t"`
+					} `xml:"r"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := bacaXML(f, &ws); err != nil {
+		return nil, err
+	}
+
+	var hasil [][]string
+	for _, row := range ws.Rows {
+		indeksBaris := len(hasil)
+		if row.R > 0 {
+			indeksBaris = row.R - 1
+		}
+		for len(hasil) <= indeksBaris {
+			hasil = append(hasil, nil)
+		}
+
+		var sel []string
+		for k, c := range row.Cells {
+			kolom := k
+			if c.R != "" {
+				kolom = indeksKolom(c.R)
+			}
+			if kolom < 0 {
+				return nil, ErrBukanXLSX
+			}
+
+			var nilai string
+			switch c.T {
+			case "s":
+				idx, err := strconv.Atoi(strings.TrimSpace(c.V))
+				if err != nil || idx < 0 || idx >= len(sharedStrings) {
+					return nil, ErrBukanXLSX
+				}
+				nilai = sharedStrings[idx]
+			case "inlineStr":
+				nilai = c.Is.T
+				for _, run := range c.Is.R {
+					nilai += run.T
+				}
+			default:
+				nilai = c.V
+			}
+
+			for len(sel) <= kolom {
+				sel = append(sel, "")
+			}
+			sel[kolom] = nilai
+		}
+		hasil[indeksBaris] = sel
+	}
+
+	return hasil, nil
+}
+
+// cariSheetPertama menentukan lokasi berkas lembar kerja pertama melalui workbook.xml dan relasinya
+func cariSheetPertama(berkas map[string]*zip.File) (string, error) {
+	const lokasiDefault = "xl/worksheets/sheet1.xml"
+
+	fWorkbook, ada := berkas["xl/workbook.xml"]
+	if !ada {
+		return "", ErrBukanXLSX
+	}
+
+	var wb struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := bacaXML(fWorkbook, &wb); err != nil {
+		return "", err
+	}
+
+	fRels, ada := berkas["xl/_rels/workbook.xml.rels"]
+	if len(wb.Sheets) == 0 || !ada {
+		return lokasiDefault, nil
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := bacaXML(fRels, &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return lokasiDefault, nil
+}
+
+// bacaSharedStrings membaca tabel string bersama workbook
+func bacaSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []struct {
+			T string `xml:"t"`
+			R []struct {
+				T string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := bacaXML(f, &sst); err != nil {
+		return nil, err
+	}
+
+	hasil := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		nilai := item.T
+		for _, run := range item.R {
+			nilai += run.T
+		}
+		hasil[i] = nilai
+	}
+	return hasil, nil
+}
+
+// bacaXML membuka berkas di dalam arsip dan men-decode isinya
+func bacaXML(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return ErrBukanXLSX
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return ErrBukanXLSX
+	}
+	return nil
+}
+
+// namaKolom mengubah indeks kolom (0-based) menjadi huruf kolom Excel: 0 → A, 26 → AA
+func namaKolom(indeks int) string {
+	nama := ""
+	for indeks >= 0 {
+		nama = string(rune('A'+indeks%26)) + nama
+		indeks = indeks/26 - 1
+	}
+	return nama
+}
+
+// indeksKolom mengambil indeks kolom (0-based) dari referensi sel seperti "AB12"
+func indeksKolom(referensi string) int {
+	indeks := 0
+	jumlahHuruf := 0
+	for _, ch := range referensi {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		indeks = indeks*26 + int(ch-'A'+1)
+		jumlahHuruf++
+	}
+	if jumlahHuruf == 0 {
+		return -1
+	}
+	return indeks - 1
+}
+
+// escapeXML meng-escape teks untuk dipakai di dalam elemen atau atribut XML
+func escapeXML(teks string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(teks))
+	return sb.String()
+}
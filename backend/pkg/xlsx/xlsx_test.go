@@ -0,0 +1,177 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestTulisBaca menguji workbook yang ditulis dapat dibaca kembali tanpa perubahan
+func TestTulisBaca(t *testing.T) {
+	baris := [][]string{
+		{"kodeAkun", "namaAkun", "tipeAkun", "kodeInduk"},
+		{"1000", "ASET", "AKTIVA", ""},
+		{"1101-01", "Kas <Toko> & Gudang", "AKTIVA", "1000"},
+		{"", "", "", ""},
+		{"5101", "Beban Gaji", "BEBAN"},
+	}
+
+	var buf bytes.Buffer
+	if err := Tulis(&buf, "COA", baris); err != nil {
+		t.Fatalf("Tulis() error = %v", err)
+	}
+
+	hasil, err := Baca(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Baca() error = %v", err)
+	}
+
+	diharapkan := [][]string{
+		{"kodeAkun", "namaAkun", "tipeAkun", "kodeInduk"},
+		{"1000", "ASET", "AKTIVA"},
+		{"1101-01", "Kas <Toko> & Gudang", "AKTIVA", "1000"},
+		nil,
+		{"5101", "Beban Gaji", "BEBAN"},
+	}
+	if !reflect.DeepEqual(hasil, diharapkan) {
+		t.Errorf("Baca() = %q, want %q", hasil, diharapkan)
+	}
+}
+
+// TestTulisSel menguji sel angka dan rumus ditulis dengan nilai tersimpan dan rumusnya
+func TestTulisSel(t *testing.T) {
+	baris := [][]Sel{
+		{{Nilai: "Akun", Tebal: true}, {Nilai: "Saldo", Tebal: true}},
+		{{Nilai: "Kas"}, {Nilai: "1500.50", Angka: true}},
+		{{Nilai: "Bank"}, {Nilai: "-500.25", Angka: true}},
+		{{Nilai: "Total", Tebal: true}, {Nilai: "1000.25", Angka: true, Rumus: "SUM(B2:B3)", Tebal: true}},
+	}
+
+	var buf bytes.Buffer
+	if err := TulisSel(&buf, "Neraca", baris); err != nil {
+		t.Fatalf("TulisSel() error = %v", err)
+	}
+
+	hasil, err := Baca(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Baca() error = %v", err)
+	}
+	diharapkan := [][]string{
+		{"Akun", "Saldo"},
+		{"Kas", "1500.50"},
+		{"Bank", "-500.25"},
+		{"Total", "1000.25"},
+	}
+	if !reflect.DeepEqual(hasil, diharapkan) {
+		t.Errorf("Baca() = %q, want %q", hasil, diharapkan)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		isi, _ := io.ReadAll(rc)
+		rc.Close()
+		sheet = string(isi)
+	}
+	if !strings.Contains(sheet, `<c r="B4" s="3"><f>SUM(B2:B3)</f><v>1000.25</v></c>`) {
+		t.Errorf("sel total tidak berisi rumus: %s", sheet)
+	}
+}
+
+// TestReferensi menguji referensi sel dari indeks baris dan kolom
+func TestReferensi(t *testing.T) {
+	if got := Referensi(0, 2); got != "C1" {
+		t.Errorf("Referensi(0, 2) = %s, want C1", got)
+	}
+	if got := Referensi(9, 26); got != "AA10" {
+		t.Errorf("Referensi(9, 26) = %s, want AA10", got)
+	}
+}
+
+// TestBaca_SharedStrings menguji pembacaan workbook buatan Excel yang memakai shared strings
+func TestBaca_SharedStrings(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	berkas := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Akun" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Type="worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>Kas</t></si><si><r><t>Bank </t></r><r><t>BRI</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1"><v>1101</v></c><c r="C1" t="s"><v>0</v></c></row>` +
+			`<row r="3"><c r="B3" t="s"><v>1</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	for nama, isi := range berkas {
+		fw, err := zw.Create(nama)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(isi)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	hasil, err := Baca(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Baca() error = %v", err)
+	}
+
+	diharapkan := [][]string{
+		{"1101", "", "Kas"},
+		nil,
+		{"", "Bank BRI"},
+	}
+	if !reflect.DeepEqual(hasil, diharapkan) {
+		t.Errorf("Baca() = %q, want %q", hasil, diharapkan)
+	}
+}
+
+// TestBaca_BukanXLSX menguji data yang bukan arsip xlsx ditolak
+func TestBaca_BukanXLSX(t *testing.T) {
+	data := []byte("kodeAkun,namaAkun\n1101,Kas\n")
+	if _, err := Baca(bytes.NewReader(data), int64(len(data))); err != ErrBukanXLSX {
+		t.Errorf("Baca() error = %v, want %v", err, ErrBukanXLSX)
+	}
+}
+
+// TestNamaKolom menguji konversi indeks kolom ke huruf dan sebaliknya
+func TestNamaKolom(t *testing.T) {
+	tests := []struct {
+		indeks int
+		nama   string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{51, "AZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := namaKolom(tt.indeks); got != tt.nama {
+			t.Errorf("namaKolom(%d) = %s, want %s", tt.indeks, got, tt.nama)
+		}
+		if got := indeksKolom(tt.nama + "12"); got != tt.indeks {
+			t.Errorf("indeksKolom(%s12) = %d, want %d", tt.nama, got, tt.indeks)
+		}
+	}
+}
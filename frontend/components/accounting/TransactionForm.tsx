@@ -270,7 +270,7 @@ export default function TransactionForm({
         tanggalTransaksi: tanggalTransaksi,
         deskripsi: deskripsi.trim(),
         nomorReferensi: nomorReferensi.trim() || undefined,
-        tipeTransaksi: "manual",
+        tipeTransaksi: "JURNAL_UMUM",
         barisTransaksi: filledItems.map((item) => ({
           idAkun: item.idAkun,
           jumlahDebit: parseFloat(item.jumlahDebit) || 0,